@@ -77,23 +77,70 @@ func main() {
 	validate := validation.Init()
 
 	api := api.Api{
-		Router:               chi.NewMux(),
-		Logger:               logger,
-		UserService:          services.NewUserService(pool),
-		ClienteService:       services.NewClienteService(pool),
-		DashboardService:     services.NewDashboardService(pool),
-		OutboxService:        services.NewOutboxService(pool),
-		OperadorCaixaService: services.NewOperadorCaixaService(pool),
-		CaixaService:         services.NewCaixaService(pool),
-		Sessions:             s,
-		JWTSecret:            []byte(jwtSecret),
-		Validate:             validate,
-		DBPool:               pool,
-		SQLBoilerDB:          sqlBoilerDB, // Use o adaptador SQLBoiler
+		Router:                 chi.NewMux(),
+		Logger:                 logger,
+		UserService:            services.NewUserService(pool),
+		ClienteService:         services.NewClienteService(pool),
+		DashboardService:       services.NewDashboardService(pool),
+		OutboxService:          services.NewOutboxService(pool),
+		OperadorCaixaService:   services.NewOperadorCaixaService(pool),
+		CaixaService:           services.NewCaixaService(pool),
+		WebhookService:         services.NewWebhookService(pool),
+		PedidoFeedHub:          services.NewPedidoFeedHub(pool, logger),
+		KdsService:             services.NewKdsService(pool),
+		PrecificacaoService:    services.NewPrecificacaoService(pool),
+		DisponibilidadeService: services.NewDisponibilidadeService(pool),
+		PedidoStatusService:    services.NewPedidoStatusService(pool),
+		CancelamentoService:    services.NewCancelamentoService(pool),
+		IdempotencyService:     services.NewIdempotencyService(pool),
+		RelatorioService:       services.NewRelatorioService(pool),
+		ComboService:           services.NewComboService(pool),
+		CupomService:           services.NewCupomService(pool),
+		ZonaEntregaService:     services.NewZonaEntregaService(pool),
+		EntregadorService:      services.NewEntregadorService(pool),
+		RotaEntregaService:     services.NewRotaEntregaService(pool),
+		AgendamentoService:     services.NewAgendamentoService(pool),
+		PixService:             services.NewPixService(pool),
+		CreditoService:         services.NewCreditoService(pool),
+		ParcelamentoService:    services.NewParcelamentoService(pool),
+		TaxaAdquirenteService:  services.NewTaxaAdquirenteService(pool),
+		Sessions:               s,
+		JWTSecret:              []byte(jwtSecret),
+		Validate:               validate,
+		DBPool:                 pool,
+		SQLBoilerDB:            sqlBoilerDB, // Use o adaptador SQLBoiler
 	}
 
 	api.BindRoutes()
 
+	// Dispatcher do outbox roda junto com a API, a menos que seja executado
+	// separadamente via cmd/outbox-worker. Os eventos são entregues aos webhooks.
+	if os.Getenv("GOBID_OUTBOX_DISPATCHER") != "false" {
+		dispatcher := services.NewOutboxDispatcher(pool,
+			&api.WebhookService,
+			logger,
+			services.DefaultOutboxDispatcherConfig())
+
+		dispatcherCtx, cancelDispatcher := context.WithCancel(ctx)
+		defer cancelDispatcher()
+		go dispatcher.Run(dispatcherCtx)
+	}
+
+	// Remove as Idempotency-Key vencidas
+	limpezaCtx, cancelLimpeza := context.WithCancel(ctx)
+	defer cancelLimpeza()
+	go api.IdempotencyService.RunLimpeza(limpezaCtx, time.Hour, logger)
+
+	// Libera para a cozinha os pedidos agendados cuja hora chegou
+	liberacaoCtx, cancelLiberacao := context.WithCancel(ctx)
+	defer cancelLiberacao()
+	go api.AgendamentoService.RunLiberacao(liberacaoCtx, time.Minute, logger)
+
+	// LISTEN do feed de pedidos (SSE/WebSocket)
+	feedCtx, cancelFeed := context.WithCancel(ctx)
+	defer cancelFeed()
+	go api.PedidoFeedHub.Run(feedCtx)
+
 	fmt.Println("Staring Server on port :3081")
 	if err := http.ListenAndServe("0.0.0.0:3081", api.Router); err != nil {
 		panic(err)
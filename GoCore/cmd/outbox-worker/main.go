@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gobid/internal/database"
+	"gobid/internal/logging"
+	"gobid/internal/services"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+)
+
+// Executa apenas o dispatcher do outbox. Use com GOBID_OUTBOX_DISPATCHER=false
+// na API quando quiser escalar a entrega de eventos separadamente.
+func main() {
+	logger := logging.New()
+	defer logger.Sync()
+
+	if err := godotenv.Load(); err != nil {
+		logger.Fatal("erro carregando .env", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := database.NewPGXPool(ctx, fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME"),
+	), logger)
+	if err != nil {
+		panic(err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		panic(err)
+	}
+
+	webhookService := services.NewWebhookService(pool)
+	dispatcher := services.NewOutboxDispatcher(pool,
+		&webhookService,
+		logger,
+		services.DefaultOutboxDispatcherConfig())
+
+	logger.Info("iniciando outbox worker")
+	dispatcher.Run(ctx)
+}
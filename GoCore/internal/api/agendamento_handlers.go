@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/agendamentos/config
+func (api *Api) handleAgendamentos_GetConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cfg, err := api.AgendamentoService.GetConfig(r.Context(), tenantID)
+	if err != nil {
+		api.agendamentoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// PUT /api/v1/agendamentos/config
+// Grava a configuração; as faixas enviadas substituem as atuais.
+func (api *Api) handleAgendamentos_PutConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.AgendamentoConfigDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	cfg, err := api.AgendamentoService.SalvarConfig(r.Context(), data)
+	if err != nil {
+		api.agendamentoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// GET /api/v1/agendamentos?data=YYYY-MM-DD
+// Agenda do dia: faixas de horário com ocupação e os pedidos agendados.
+func (api *Api) handleAgendamentos_Agenda(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dia *time.Time
+	if v := r.URL.Query().Get("data"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "data inválida, use YYYY-MM-DD")
+			return
+		}
+		dia = &t
+	}
+
+	agenda, err := api.AgendamentoService.Agenda(r.Context(), tenantID, dia)
+	if err != nil {
+		api.agendamentoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, agenda)
+}
+
+// POST /api/v1/pedidos/{id}/liberar
+// Manda o pedido agendado para a cozinha antes da hora de liberação.
+func (api *Api) handlePedidos_Liberar(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	historico, err := api.AgendamentoService.Liberar(r.Context(), tenantID, id, api.getUserIDFromContext(r))
+	if err != nil {
+		api.agendamentoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, historico)
+}
+
+func (api *Api) agendamentoError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		invalido *services.AgendamentoInvalidoError
+		lotado   *services.HorarioLotadoError
+	)
+	switch {
+	case errors.Is(err, services.ErrAgendamentoDesativado),
+		errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.As(err, &lotado):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error":      err.Error(),
+			"inicio":     lotado.Inicio,
+			"fim":        lotado.Fim,
+			"capacidade": lotado.Capacidade,
+			"ocupados":   lotado.Ocupados,
+		})
+	case errors.Is(err, services.ErrPedidoNaoAgendado):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrTenantNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrPedidoNaoEncontrado),
+		errors.Is(err, services.ErrLiberacaoSemCaixa):
+		api.pedidoStatusError(w, r, err)
+	default:
+		var transicao *services.TransicaoStatusInvalidaError
+		if errors.As(err, &transicao) {
+			api.pedidoStatusError(w, r, err)
+			return
+		}
+		api.Logger.Error("erro no agendamento de pedidos", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -20,19 +20,38 @@ import (
 )
 
 type Api struct {
-	Router               *chi.Mux
-	Logger               *zap.Logger
-	UserService          services.UserService
-	ClienteService       services.ClienteService
-	DashboardService     services.DashboardService
-	OutboxService        services.OutboxService
-	OperadorCaixaService services.OperadorCaixaService
-	CaixaService         services.CaixaService
-	Sessions             *scs.SessionManager
-	JWTSecret            []byte
-	tenantCache          sync.Map
-	userCache            sync.Map // <-- novo
-	cacheExpiration      time.Duration
+	Router                 *chi.Mux
+	Logger                 *zap.Logger
+	UserService            services.UserService
+	ClienteService         services.ClienteService
+	DashboardService       services.DashboardService
+	OutboxService          services.OutboxService
+	OperadorCaixaService   services.OperadorCaixaService
+	CaixaService           services.CaixaService
+	WebhookService         services.WebhookService
+	PedidoFeedHub          *services.PedidoFeedHub
+	KdsService             services.KdsService
+	PrecificacaoService    services.PrecificacaoService
+	DisponibilidadeService services.DisponibilidadeService
+	PedidoStatusService    services.PedidoStatusService
+	CancelamentoService    services.CancelamentoService
+	IdempotencyService     services.IdempotencyService
+	RelatorioService       services.RelatorioService
+	ComboService           services.ComboService
+	CupomService           services.CupomService
+	ZonaEntregaService     services.ZonaEntregaService
+	EntregadorService      services.EntregadorService
+	RotaEntregaService     services.RotaEntregaService
+	AgendamentoService     services.AgendamentoService
+	PixService             services.PixService
+	CreditoService         services.CreditoService
+	ParcelamentoService    services.ParcelamentoService
+	TaxaAdquirenteService  services.TaxaAdquirenteService
+	Sessions               *scs.SessionManager
+	JWTSecret              []byte
+	tenantCache            sync.Map
+	userCache              sync.Map // <-- novo
+	cacheExpiration        time.Duration
 	// S3Service               services.S3Service
 	// ProdutoImagemService    services.ProdutoImagemService
 	Validate    *validator.Validate
@@ -58,25 +77,63 @@ func NewApi(router *chi.Mux,
 	outboxService services.OutboxService,
 	operadorCaixaService services.OperadorCaixaService,
 	caixaService services.CaixaService,
+	webhookService services.WebhookService,
+	pedidoFeedHub *services.PedidoFeedHub,
+	kdsService services.KdsService,
+	precificacaoService services.PrecificacaoService,
+	disponibilidadeService services.DisponibilidadeService,
+	pedidoStatusService services.PedidoStatusService,
+	cancelamentoService services.CancelamentoService,
+	idempotencyService services.IdempotencyService,
+	relatorioService services.RelatorioService,
+	comboService services.ComboService,
+	cupomService services.CupomService,
+	zonaEntregaService services.ZonaEntregaService,
+	entregadorService services.EntregadorService,
+	rotaEntregaService services.RotaEntregaService,
+	agendamentoService services.AgendamentoService,
+	pixService services.PixService,
+	creditoService services.CreditoService,
+	parcelamentoService services.ParcelamentoService,
+	taxaAdquirenteService services.TaxaAdquirenteService,
 	sessions *scs.SessionManager, jwtSecret []byte,
 	validate *validator.Validate,
 	pool *pgxpool.Pool,
 	sqlBoilerDB *database.SQLBoilerDB) *Api {
 	return &Api{
-		Router:               router,
-		Logger:               logger,
-		UserService:          userService,
-		ClienteService:       clienteService,
-		DashboardService:     dashboardService,
-		OutboxService:        outboxService,
-		OperadorCaixaService: operadorCaixaService,
-		CaixaService:         caixaService,
-		Sessions:             sessions,
-		JWTSecret:            jwtSecret,
-		cacheExpiration:      15 * time.Minute, // Cache expira em 15 minutos
-		Validate:             validate,
-		DBPool:               pool,
-		SQLBoilerDB:          sqlBoilerDB,
+		Router:                 router,
+		Logger:                 logger,
+		UserService:            userService,
+		ClienteService:         clienteService,
+		DashboardService:       dashboardService,
+		OutboxService:          outboxService,
+		OperadorCaixaService:   operadorCaixaService,
+		CaixaService:           caixaService,
+		WebhookService:         webhookService,
+		PedidoFeedHub:          pedidoFeedHub,
+		KdsService:             kdsService,
+		PrecificacaoService:    precificacaoService,
+		DisponibilidadeService: disponibilidadeService,
+		PedidoStatusService:    pedidoStatusService,
+		CancelamentoService:    cancelamentoService,
+		IdempotencyService:     idempotencyService,
+		RelatorioService:       relatorioService,
+		ComboService:           comboService,
+		CupomService:           cupomService,
+		ZonaEntregaService:     zonaEntregaService,
+		EntregadorService:      entregadorService,
+		RotaEntregaService:     rotaEntregaService,
+		AgendamentoService:     agendamentoService,
+		PixService:             pixService,
+		CreditoService:         creditoService,
+		ParcelamentoService:    parcelamentoService,
+		TaxaAdquirenteService:  taxaAdquirenteService,
+		Sessions:               sessions,
+		JWTSecret:              jwtSecret,
+		cacheExpiration:        15 * time.Minute, // Cache expira em 15 minutos
+		Validate:               validate,
+		DBPool:                 pool,
+		SQLBoilerDB:            sqlBoilerDB,
 	}
 }
 
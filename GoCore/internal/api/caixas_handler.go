@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"gobid/internal/dto"
 	"gobid/internal/jsonutils"
+	"gobid/internal/services"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -197,8 +199,15 @@ func (api *Api) handleCaixas_FecharCaixa(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	data.TenantID = tenantID
+	data.UserID = api.getUserIDFromContext(r)
+
 	err = api.CaixaService.FecharCaixa(r.Context(), data)
 	if err != nil {
+		if errors.Is(err, services.ErrCaixaNaoEncontrado) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
 		api.Logger.Error("erro ao fechar caixa", zap.Error(err))
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
 		return
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// POST /api/v1/pedidos/{id}/cancelar
+// Cancela o pedido com motivo. Se houver pagamento, exige gerente: o próprio
+// usuário (admin) ou as credenciais de um gerente em "autorizacao".
+// Estorna pagamentos e caixa, cancela parcelas e grava a auditoria.
+func (api *Api) handlePedidos_Cancelar(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	req, problems, err := jsonutils.DecodeValidJsonV10[dto.CancelarPedidoRequest](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid request body")
+		}
+		return
+	}
+
+	in := dto.CancelarPedidoDTO{
+		TenantID: tenantID,
+		UserID:   api.getUserIDFromContext(r),
+		IDPedido: id,
+		Motivo:   req.Motivo,
+	}
+
+	autorizadoPor, ok := api.autorizacaoGerente(w, r, tenantID, req.Autorizacao)
+	if !ok {
+		return
+	}
+	in.AutorizadoPor = autorizadoPor
+
+	cancelamento, err := api.CancelamentoService.CancelarPedido(r.Context(), in)
+	if err != nil {
+		api.cancelamentoError(w, r, err)
+		return
+	}
+
+	api.Logger.Info("pedido cancelado",
+		zap.String("pedido_id", id.String()),
+		zap.String("user_id", in.UserID.String()),
+		zap.Int("pagamentos_estornados", len(cancelamento.Pagamentos)))
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cancelamento)
+}
+
+// autorizacaoGerente devolve o gerente que autoriza a operação: o próprio
+// usuário, se for admin, ou o dono das credenciais em "autorizacao". Nil
+// quando não há autorização. Devolve false se a resposta de erro já foi
+// escrita.
+func (api *Api) autorizacaoGerente(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID, autorizacao *dto.AutorizacaoGerenteDTO) (*uuid.UUID, bool) {
+	if user := api.getUserFromContext(r); user.Admin == 1 {
+		return &user.ID, true
+	}
+	if autorizacao == nil {
+		return nil, true
+	}
+
+	gerente, err := api.UserService.AuthenticateUser(r.Context(), autorizacao.Email, autorizacao.Senha)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			api.jsonError(w, r, http.StatusForbidden, "credenciais do gerente inválidas")
+			return nil, false
+		}
+		api.Logger.Error("erro ao autenticar gerente", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+	if gerente.TenantID != tenantID || gerente.Admin != 1 {
+		api.jsonError(w, r, http.StatusForbidden, "usuário informado não é gerente deste estabelecimento")
+		return nil, false
+	}
+	return &gerente.ID, true
+}
+
+func (api *Api) cancelamentoError(w http.ResponseWriter, r *http.Request, err error) {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, services.ErrAutorizacaoGerente):
+		api.jsonError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrPedidoJaCancelado),
+		errors.Is(err, services.ErrSemCaixaAberto):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		// regra de negócio do banco (pedido travado, caixa fechado...)
+		api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+	default:
+		api.pedidoStatusError(w, r, err)
+	}
+}
@@ -192,6 +192,10 @@ func (api *Api) handleCategorias_Post(w http.ResponseWriter, r *http.Request) {
 		categoria.OpcaoMeia.SetValid(*createDTO.OpcaoMeia)
 	}
 
+	if createDTO.MaxSabores != nil {
+		categoria.MaxSabores = *createDTO.MaxSabores
+	}
+
 	if createDTO.Ordem != nil {
 		categoria.Ordem.SetValid(int(*createDTO.Ordem))
 	}
@@ -409,6 +413,10 @@ func (api *Api) handleCategorias_Put(w http.ResponseWriter, r *http.Request) {
 		categoriaExistente.OpcaoMeia.Valid = false
 	}
 
+	if updateDTO.MaxSabores != nil {
+		categoriaExistente.MaxSabores = *updateDTO.MaxSabores
+	}
+
 	if updateDTO.Ordem != nil {
 		categoriaExistente.Ordem.SetValid(int(*updateDTO.Ordem))
 	} else {
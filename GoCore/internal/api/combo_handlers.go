@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/produtos/{id}/combo
+// Slots do combo com os acréscimos por produto.
+func (api *Api) handleProdutos_GetCombo(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.comboIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	combo, err := api.ComboService.Get(r.Context(), tenantID, id)
+	if err != nil {
+		api.comboError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, combo)
+}
+
+// PUT /api/v1/produtos/{id}/combo
+// Transforma o produto em combo (ou troca os slots de um combo existente).
+// O preço do pacote continua sendo o de produto_precos.
+func (api *Api) handleProdutos_PutCombo(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.comboIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.ComboUpdateDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.IDProduto = id
+	data.TenantID = tenantID
+
+	combo, err := api.ComboService.Salvar(r.Context(), data)
+	if err != nil {
+		api.comboError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, combo)
+}
+
+// DELETE /api/v1/produtos/{id}/combo
+func (api *Api) handleProdutos_DeleteCombo(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.comboIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.ComboService.Remover(r.Context(), tenantID, id); err != nil {
+		api.comboError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *Api) comboIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) comboError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalido *services.ComboInvalidoError
+	switch {
+	case errors.Is(err, services.ErrProdutoNaoEncontrado),
+		errors.Is(err, services.ErrComboNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		api.Logger.Error("erro no combo", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/contas-receber/politica-credito
+func (api *Api) handleCredito_GetConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cfg, err := api.CreditoService.GetConfig(r.Context(), tenantID)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// PUT /api/v1/contas-receber/politica-credito
+// Limite padrão, bloqueio por atraso e encargos (multa, juros ao dia e
+// carência); só gerente (admin) altera.
+func (api *Api) handleCredito_PutConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if api.getUserFromContext(r).Admin != 1 {
+		api.jsonError(w, r, http.StatusForbidden, "alterar a política de crédito exige um gerente")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.CreditoConfigDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	cfg, err := api.CreditoService.SalvarConfig(r.Context(), data)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// GET /api/v1/clientes/{id}/credito?data=YYYY-MM-DD
+// Extrato do fiado: limite, saldo e parcelas em aberto de todos os pedidos
+// do cliente, com os pagamentos já abatidos.
+func (api *Api) handleClientes_GetCredito(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+	dia, ok := api.dataDaQuery(w, r)
+	if !ok {
+		return
+	}
+
+	extrato, err := api.CreditoService.Extrato(r.Context(), tenantID, id, dia)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, extrato)
+}
+
+// PUT /api/v1/clientes/{id}/credito
+// Limite próprio e bloqueio do cliente; só gerente (admin) altera.
+func (api *Api) handleClientes_PutCredito(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	user := api.getUserFromContext(r)
+	if user.Admin != 1 {
+		api.jsonError(w, r, http.StatusForbidden, "alterar o crédito do cliente exige um gerente")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.ClienteCreditoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	extrato, err := api.CreditoService.SalvarCliente(r.Context(), tenantID, id, user.ID, data)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	api.Logger.Info("crédito do cliente alterado",
+		zap.String("cliente_id", id.String()),
+		zap.String("user_id", user.ID.String()),
+		zap.Bool("bloqueado", data.Bloqueado))
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, extrato)
+}
+
+// GET /api/v1/contas-receber/aging?data=YYYY-MM-DD
+// Saldo em aberto por cliente nas faixas de dias após o vencimento.
+func (api *Api) handleContasReceber_Aging(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	dia, ok := api.dataDaQuery(w, r)
+	if !ok {
+		return
+	}
+
+	aging, err := api.CreditoService.Aging(r.Context(), tenantID, dia)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, aging)
+}
+
+// GET /api/v1/contas-receber/{id}/saldo?data=YYYY-MM-DD
+// Valor atualizado da parcela na data: principal em aberto, multa e juros
+// pendentes e o total que a quita.
+func (api *Api) handleContasReceber_Saldo(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+	dia, ok := api.dataDaQuery(w, r)
+	if !ok {
+		return
+	}
+
+	saldo, err := api.CreditoService.Saldo(r.Context(), tenantID, id, dia)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, saldo)
+}
+
+// verificarCredito confere as parcelas novas contra a política de crédito
+// dos clientes. Fora da política, só passam com gerente: devolve, por
+// id_pedido, quem autorizou, para gravar em autorizado_por. Devolve false
+// se a resposta de erro já foi escrita.
+func (api *Api) verificarCredito(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID,
+	parcelas []dto.ParcelaCreditoDTO, autorizacao *dto.AutorizacaoGerenteDTO) (map[string]string, bool) {
+
+	negados, err := api.CreditoService.VerificarParcelas(r.Context(), tenantID, parcelas)
+	if err != nil {
+		api.creditoError(w, r, err)
+		return nil, false
+	}
+	if len(negados) == 0 {
+		return nil, true
+	}
+
+	gerente, ok := api.autorizacaoGerente(w, r, tenantID, autorizacao)
+	if !ok {
+		return nil, false
+	}
+	if gerente == nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":    negados[0].Mensagem,
+			"clientes": negados,
+		})
+		return nil, false
+	}
+
+	autorizadas := make(map[string]string)
+	for _, n := range negados {
+		for _, idPedido := range n.IDPedidos {
+			autorizadas[idPedido.String()] = gerente.String()
+		}
+		api.Logger.Info("crédito liberado pelo gerente",
+			zap.String("cliente_id", n.IDCliente.String()),
+			zap.String("motivo", n.Motivo),
+			zap.String("gerente_id", gerente.String()))
+	}
+	return autorizadas, true
+}
+
+// dataDaQuery lê ?data=YYYY-MM-DD; nil quando ausente. Devolve false se a
+// resposta de erro já foi escrita.
+func (api *Api) dataDaQuery(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	return api.dataDoParametro(w, r, "data")
+}
+
+// dataDoParametro lê ?<nome>=YYYY-MM-DD; ausente devolve nil
+func (api *Api) dataDoParametro(w http.ResponseWriter, r *http.Request, nome string) (*time.Time, bool) {
+	v := r.URL.Query().Get(nome)
+	if v == "" {
+		return nil, true
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "parâmetro "+nome+" inválido, use YYYY-MM-DD")
+		return nil, false
+	}
+	return &t, true
+}
+
+func (api *Api) creditoError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		invalido *services.CreditoInvalidoError
+		pgErr    *pgconn.PgError
+	)
+	switch {
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrClienteNaoEncontrado),
+		errors.Is(err, services.ErrContaReceberNaoEncontrada):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrPedidoNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, "pedido not found or not authorized")
+	case errors.Is(err, services.ErrTenantNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		// política conferida de novo pelo gatilho, sob lock do cliente
+		api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+	default:
+		api.Logger.Error("erro no crédito do cliente", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/cupons
+func (api *Api) handleCupons_List(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cupons, err := api.CupomService.List(r.Context(), tenantID)
+	if err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cupons)
+}
+
+// GET /api/v1/cupons/{id}
+func (api *Api) handleCupons_Get(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.cupomIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	cupom, err := api.CupomService.Get(r.Context(), tenantID, id)
+	if err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cupom)
+}
+
+// POST /api/v1/cupons
+func (api *Api) handleCupons_Post(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.CupomDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	cupom, err := api.CupomService.Create(r.Context(), data)
+	if err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, cupom)
+}
+
+// PUT /api/v1/cupons/{id}
+func (api *Api) handleCupons_Put(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.cupomIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.CupomDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.ID = id
+	data.TenantID = tenantID
+
+	cupom, err := api.CupomService.Update(r.Context(), data)
+	if err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cupom)
+}
+
+// DELETE /api/v1/cupons/{id}
+// Pedidos já feitos mantêm o desconto; o código fica livre para outro cupom.
+func (api *Api) handleCupons_Delete(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.cupomIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.CupomService.Delete(r.Context(), tenantID, id); err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/cupons/{id}/resgates?limit=&offset=
+func (api *Api) handleCupons_ListResgates(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.cupomIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := int32(50)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = int32(parsedLimit)
+		}
+	}
+
+	offset := int32(0)
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = int32(parsedOffset)
+		}
+	}
+
+	resgates, err := api.CupomService.ListResgates(r.Context(), tenantID, id, limit, offset)
+	if err != nil {
+		api.cupomError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, resgates)
+}
+
+func (api *Api) cupomIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) cupomError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalido *services.CupomInvalidoError
+	switch {
+	case errors.Is(err, services.ErrCupomNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrCupomCodigoDuplicado):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		api.Logger.Error("erro no cupom", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
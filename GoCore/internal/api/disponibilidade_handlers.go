@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/cardapio/disponivel
+// Cardápio que pode ser pedido agora (ou em ?data=, RFC3339) no horário do
+// tenant. Com ?incluir_indisponiveis=true devolve tudo, com disponivel e
+// motivo em cada categoria, produto e opção.
+func (api *Api) handleCardapio_Disponivel(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var ref *time.Time
+	if v := r.URL.Query().Get("data"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "data inválida, use RFC3339")
+			return
+		}
+		ref = &t
+	}
+
+	cardapio, err := api.DisponibilidadeService.Cardapio(r.Context(), tenantID, ref,
+		r.URL.Query().Get("incluir_indisponiveis") == "true")
+	if err != nil {
+		api.disponibilidadeError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cardapio)
+}
+
+// verificarDisponibilidade rejeita com 422 pedidos com categorias fora do
+// horário ou do dia de venda. ignorar_disponibilidade só é aceito de
+// gerentes (admin); a liberação fica registrada no pedido.
+// Devolve false se a resposta de erro já foi escrita.
+func (api *Api) verificarDisponibilidade(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID, pedido *dto.PedidoCreateDTO) bool {
+	if pedido.IgnorarDisponibilidade {
+		user := api.getUserFromContext(r)
+		if user.Admin != 1 {
+			api.jsonError(w, r, http.StatusForbidden, "ignorar_disponibilidade exige um gerente")
+			return false
+		}
+		liberadoPor := user.ID.String()
+		pedido.DisponibilidadeLiberadaPor = &liberadoPor
+		api.Logger.Info("disponibilidade do cardápio liberada pelo gerente",
+			zap.String("tenant_id", tenantID.String()),
+			zap.String("user_id", liberadoPor))
+		return true
+	}
+
+	// Componentes de combo também respeitam a disponibilidade da sua
+	// categoria; o problema é apontado no item do combo
+	categorias := make([]string, 0, len(pedido.Itens))
+	itemDaCategoria := make([]int, 0, len(pedido.Itens))
+	for i, item := range pedido.Itens {
+		categorias = append(categorias, item.IDCategoria)
+		itemDaCategoria = append(itemDaCategoria, i)
+		for _, comp := range item.Componentes {
+			categorias = append(categorias, comp.IDCategoria)
+			itemDaCategoria = append(itemDaCategoria, i)
+		}
+	}
+
+	indisponiveis, err := api.DisponibilidadeService.VerificarPedido(r.Context(), tenantID, pedido.AgendadoPara, categorias)
+	if err != nil {
+		api.disponibilidadeError(w, r, err)
+		return false
+	}
+	for k := range indisponiveis {
+		indisponiveis[k].Item = itemDaCategoria[indisponiveis[k].Item]
+	}
+	if len(indisponiveis) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":         "itens fora da disponibilidade do cardápio",
+			"indisponiveis": indisponiveis,
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) disponibilidadeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrTenantNaoEncontrado) {
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	api.Logger.Error("erro ao avaliar disponibilidade do cardápio", zap.Error(err))
+	api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+}
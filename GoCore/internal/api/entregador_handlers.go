@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/entregadores
+func (api *Api) handleEntregadores_List(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	entregadores, err := api.EntregadorService.List(r.Context(), tenantID)
+	if err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, entregadores)
+}
+
+// GET /api/v1/entregadores/{id}
+func (api *Api) handleEntregadores_Get(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.entregadorIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	entregador, err := api.EntregadorService.Get(r.Context(), tenantID, id)
+	if err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, entregador)
+}
+
+// POST /api/v1/entregadores
+func (api *Api) handleEntregadores_Post(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.EntregadorDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	entregador, err := api.EntregadorService.Create(r.Context(), data)
+	if err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, entregador)
+}
+
+// PUT /api/v1/entregadores/{id}
+func (api *Api) handleEntregadores_Put(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.entregadorIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.EntregadorDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.ID = id
+	data.TenantID = tenantID
+
+	entregador, err := api.EntregadorService.Update(r.Context(), data)
+	if err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, entregador)
+}
+
+// DELETE /api/v1/entregadores/{id}
+// Rotas já feitas continuam no histórico e no relatório.
+func (api *Api) handleEntregadores_Delete(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.entregadorIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.EntregadorService.Delete(r.Context(), tenantID, id); err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/entregadores/relatorio?inicio=&fim=&id_entregador=
+// Entregas por entregador e dia; sem período, o dia de hoje.
+func (api *Api) handleEntregadores_Relatorio(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	var inicio, fim *time.Time
+	if s := query.Get("inicio"); s != "" {
+		data, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid inicio")
+			return
+		}
+		inicio = &data
+	}
+	if s := query.Get("fim"); s != "" {
+		data, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid fim")
+			return
+		}
+		fim = &data
+	}
+	var idEntregador *uuid.UUID
+	if s := query.Get("id_entregador"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid id_entregador")
+			return
+		}
+		idEntregador = &id
+	}
+
+	relatorio, err := api.EntregadorService.Relatorio(r.Context(), tenantID, inicio, fim, idEntregador)
+	if err != nil {
+		api.entregadorError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, relatorio)
+}
+
+func (api *Api) entregadorIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) entregadorError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalido *services.EntregadorInvalidoError
+	switch {
+	case errors.Is(err, services.ErrEntregadorNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUsuarioEntregadorEmUso):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		api.Logger.Error("erro no entregador", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+/* -------------------- Estações -------------------- */
+
+func (api *Api) handleKds_ListEstacoes(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	estacoes, err := api.KdsService.ListEstacoes(r.Context(), tenantID)
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, estacoes)
+}
+
+func (api *Api) handleKds_GetEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	estacao, err := api.KdsService.GetEstacao(r.Context(), id, tenantID)
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, estacao)
+}
+
+func (api *Api) handleKds_PostEstacao(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.CreateEstacaoProducaoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	estacao, err := api.KdsService.CreateEstacao(r.Context(), data)
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, estacao)
+}
+
+func (api *Api) handleKds_PutEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.UpdateEstacaoProducaoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.ID = id
+	data.TenantID = tenantID
+
+	estacao, err := api.KdsService.UpdateEstacao(r.Context(), data)
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, estacao)
+}
+
+func (api *Api) handleKds_DeleteEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.KdsService.DeleteEstacao(r.Context(), id, tenantID); err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* -------------------- Filas -------------------- */
+
+func (api *Api) handleKds_FilaEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	itens, err := api.KdsService.ListFila(r.Context(), tenantID, &id, r.URL.Query().Get("incluir_prontos") == "true")
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, itens)
+}
+
+func (api *Api) handleKds_Fila(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	itens, err := api.KdsService.ListFila(r.Context(), tenantID, nil, r.URL.Query().Get("incluir_prontos") == "true")
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, itens)
+}
+
+/* -------------------- Roteamento -------------------- */
+
+func (api *Api) handleKds_PutCategoriaEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.SetEstacaoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	if err := api.KdsService.SetCategoriaEstacao(r.Context(), id, tenantID, data); err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *Api) handleKds_PutProdutoEstacao(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.SetEstacaoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	if err := api.KdsService.SetProdutoEstacao(r.Context(), id, tenantID, data); err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* -------------------- Itens -------------------- */
+
+func (api *Api) handleKds_PutItemStatus(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.kdsIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.UpdateStatusPreparoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	item, err := api.KdsService.UpdateItemStatus(r.Context(), tenantID, api.getUserIDFromContext(r), id, data.Status)
+	if err != nil {
+		api.kdsError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, item)
+}
+
+func (api *Api) kdsIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) kdsError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrEstacaoNaoEncontrada),
+		errors.Is(err, services.ErrCategoriaNaoEncontrada),
+		errors.Is(err, services.ErrProdutoNaoEncontrado),
+		errors.Is(err, services.ErrItemPreparoNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrTransicaoPreparoInvalida):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	default:
+		api.Logger.Error("erro no KDS", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+	"gobid/internal/dto"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// emitOutboxEvent grava um evento de domínio no outbox usando a mesma
+// transação da alteração: se o COMMIT falhar, o evento também não existe.
+func (api *Api) emitOutboxEvent(ctx context.Context, exec boil.ContextExecutor,
+	tenantID, userID uuid.UUID, aggregateType, aggregateID, eventType string, data any) error {
+
+	ev, err := dto.NewOutboxEventDTO(tenantID, userID, aggregateType, aggregateID, eventType, data)
+	if err != nil {
+		return err
+	}
+	return ev.ToModel().Insert(ctx, exec, boil.Infer())
+}
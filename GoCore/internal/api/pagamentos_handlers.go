@@ -1,18 +1,24 @@
 package api
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
+	"gobid/internal/dto"
 	"gobid/internal/jsonutils"
 	m "gobid/internal/models_sql_boiler"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +29,7 @@ type PedidoPagamentoCreateDTO struct {
 	Forma          string  `json:"forma_pagamento"  validate:"required"`
 	ValorPago      string  `json:"valor_pago"       validate:"required"`
 	Troco          string  `json:"troco"            `
+	Parcelas       *int16  `json:"parcelas,omitempty" validate:"omitempty,min=1,max=24"` // cartão de crédito; define a taxa da adquirente
 	Observacao     *string `json:"observacao,omitempty"`
 }
 
@@ -39,6 +46,28 @@ type ContasReceberCreateDTO struct {
 type PedidoPagamentoBulkDTO []PedidoPagamentoCreateDTO
 type ContasReceberBulkDTO []ContasReceberCreateDTO
 
+// ContasReceberPostDTO: parcela avulsa. Autorizacao (credenciais do gerente)
+// libera parcela fora da política de crédito do cliente.
+type ContasReceberPostDTO struct {
+	ContasReceberCreateDTO
+	Autorizacao *dto.AutorizacaoGerenteDTO `json:"autorizacao,omitempty"`
+}
+
+// ContasReceberBulkRequest aceita o array de parcelas ou
+// {"parcelas": [...], "autorizacao": {...}}
+type ContasReceberBulkRequest struct {
+	Parcelas    ContasReceberBulkDTO       `json:"parcelas"              validate:"required,min=1,max=120,dive"`
+	Autorizacao *dto.AutorizacaoGerenteDTO `json:"autorizacao,omitempty"`
+}
+
+func (b *ContasReceberBulkRequest) UnmarshalJSON(data []byte) error {
+	if t := bytes.TrimSpace(data); len(t) > 0 && t[0] == '[' {
+		return json.Unmarshal(t, &b.Parcelas)
+	}
+	type semMetodos ContasReceberBulkRequest
+	return json.Unmarshal(data, (*semMetodos)(b))
+}
+
 func (api *Api) jsonError(w http.ResponseWriter, r *http.Request, code int, msg string) {
 	jsonutils.EncodeJson(w, r, code, map[string]any{"error": msg})
 }
@@ -123,12 +152,17 @@ func (api *Api) handlePedidoPagamentos_Post(w http.ResponseWriter, r *http.Reque
 		}
 		return
 	}
+	if pedido.IDStatus == dto.PedidoStatusCancelado {
+		api.jsonError(w, r, http.StatusConflict, "pedido cancelado")
+		return
+	}
 
 	// valida conta‑receber se enviada
 	if dtoIn.IDContaReceber != nil {
 		if _, err := m.ContasRecebers(
 			qm.Where("id = ?", *dtoIn.IDContaReceber),
-			qm.Where("id_pedido = ?", pedido.ID)).One(r.Context(), api.SQLBoilerDB.GetDB()); err != nil {
+			qm.Where("id_pedido = ?", pedido.ID),
+			qm.Where("cancelado_em IS NULL")).One(r.Context(), api.SQLBoilerDB.GetDB()); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				api.jsonError(w, r, http.StatusBadRequest, "conta_receber not found for pedido")
 			} else {
@@ -169,13 +203,43 @@ func (api *Api) handlePedidoPagamentos_Post(w http.ResponseWriter, r *http.Reque
 	if dtoIn.Observacao != nil {
 		pagamento.Observacao.SetValid(*dtoIn.Observacao)
 	}
+	if dtoIn.Parcelas != nil {
+		pagamento.ParcelasCartao = *dtoIn.Parcelas
+	}
+
+	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
+	if err != nil {
+		api.Logger.Error("pagamento create tx", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer tx.Rollback()
 
-	if err := pagamento.Insert(r.Context(), api.SQLBoilerDB.GetDB(), boil.Infer()); err != nil {
+	if err := pagamento.Insert(r.Context(), tx, boil.Infer()); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+			api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+			return
+		}
 		api.Logger.Error("pagamento insert", zap.Error(err))
 		api.jsonError(w, r, http.StatusInternalServerError, "error inserting pagamento")
 		return
 	}
 
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, api.getUserIDFromContext(r),
+		dto.OutboxAggregatePagamento, pagamento.ID, dto.EventPagamentoRegistered,
+		dto.PagamentoEventPayloadFromModel(pagamento)); err != nil {
+		api.Logger.Error("pagamento outbox", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		api.Logger.Error("pagamento commit", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+
 	jsonutils.EncodeJson(w, r, http.StatusCreated, pagamento)
 }
 
@@ -207,11 +271,33 @@ func (api *Api) handlePedidoPagamentos_Delete(w http.ResponseWriter, r *http.Req
 	pagamento.DeletedAt.Valid = true
 	pagamento.UpdatedAt = time.Now()
 
-	if _, err := pagamento.Update(r.Context(), api.SQLBoilerDB.GetDB(), boil.Infer()); err != nil {
+	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
+	if err != nil {
+		api.Logger.Error("pagamento delete tx", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := pagamento.Update(r.Context(), tx, boil.Infer()); err != nil {
 		api.Logger.Error("pagamento soft delete", zap.Error(err))
 		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
 		return
 	}
+
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, api.getUserIDFromContext(r),
+		dto.OutboxAggregatePagamento, pagamento.ID, dto.EventPagamentoDeleted,
+		dto.PagamentoEventPayloadFromModel(pagamento)); err != nil {
+		api.Logger.Error("pagamento outbox", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		api.Logger.Error("pagamento delete commit", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -226,7 +312,7 @@ func (api *Api) handleContasReceber_Post(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	dtoIn, problems, err := jsonutils.DecodeValidJsonV10[ContasReceberCreateDTO](r)
+	dtoIn, problems, err := jsonutils.DecodeValidJsonV10[ContasReceberPostDTO](r)
 	if err != nil {
 		if problems != nil {
 			jsonutils.EncodeJson(w, r, http.StatusBadRequest, problems)
@@ -253,6 +339,14 @@ func (api *Api) handleContasReceber_Post(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Limite e atraso do cliente; fora da política só com gerente
+	autorizadas, ok := api.verificarCredito(w, r, tenantID, []dto.ParcelaCreditoDTO{
+		{IDPedido: uuid.MustParse(dtoIn.IDPedido), Valor: valor},
+	}, dtoIn.Autorizacao)
+	if !ok {
+		return
+	}
+
 	conta := &m.ContasReceber{
 		ID:          uuid.New().String(),
 		IDPedido:    dtoIn.IDPedido,
@@ -262,8 +356,16 @@ func (api *Api) handleContasReceber_Post(w http.ResponseWriter, r *http.Request)
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if gerente, ok := autorizadas[dtoIn.IDPedido]; ok {
+		conta.AutorizadoPor = null.StringFrom(gerente)
+	}
 
 	if err := conta.Insert(r.Context(), api.SQLBoilerDB.GetDB(), boil.Infer()); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+			api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+			return
+		}
 		api.Logger.Error("conta insert", zap.Error(err))
 		api.jsonError(w, r, http.StatusInternalServerError, "error inserting conta")
 		return
@@ -324,8 +426,8 @@ func (api *Api) handleContasReceber_BulkPost(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// ⇣ decodifica array de DTOs
-	dtos, problems, err := jsonutils.DecodeValidJsonV10[ContasReceberBulkDTO](r)
+	// ⇣ decodifica array de DTOs (ou objeto com parcelas e autorizacao)
+	req, problems, err := jsonutils.DecodeValidJsonV10[ContasReceberBulkRequest](r)
 	if err != nil {
 		if problems != nil {
 			jsonutils.EncodeJson(w, r, http.StatusBadRequest, problems)
@@ -334,6 +436,7 @@ func (api *Api) handleContasReceber_BulkPost(w http.ResponseWriter, r *http.Requ
 		}
 		return
 	}
+	dtos := req.Parcelas
 	if len(dtos) == 0 {
 		api.jsonError(w, r, http.StatusBadRequest, "lista vazia")
 		return
@@ -367,25 +470,45 @@ func (api *Api) handleContasReceber_BulkPost(w http.ResponseWriter, r *http.Requ
 	}
 
 	created := make([]*m.ContasReceber, 0, len(dtos))
-
-	for _, dto := range dtos {
-		venc, _ := time.Parse("2006-01-02", dto.Vencimento)
-		valor, err := api.decimalFromString(dto.ValorDevido)
+	valores := make([]types.Decimal, len(dtos))
+	parcelas := make([]dto.ParcelaCreditoDTO, len(dtos))
+	for i, d := range dtos {
+		valor, err := api.decimalFromString(d.ValorDevido)
 		if err != nil {
 			api.jsonError(w, r, http.StatusBadRequest, "valor_devido inválido")
 			return
 		}
+		valores[i] = valor
+		parcelas[i] = dto.ParcelaCreditoDTO{IDPedido: uuid.MustParse(d.IDPedido), Valor: valor}
+	}
+
+	// Limite e atraso somados por cliente; fora da política só com gerente
+	autorizadas, ok := api.verificarCredito(w, r, tenantID, parcelas, req.Autorizacao)
+	if !ok {
+		return
+	}
+
+	for i, d := range dtos {
+		venc, _ := time.Parse("2006-01-02", d.Vencimento)
 
 		cr := &m.ContasReceber{
 			ID:          uuid.New().String(),
-			IDPedido:    dto.IDPedido,
-			Parcela:     dto.Parcela,
+			IDPedido:    d.IDPedido,
+			Parcela:     d.Parcela,
 			Vencimento:  venc,
-			ValorDevido: valor,
+			ValorDevido: valores[i],
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
+		if gerente, ok := autorizadas[d.IDPedido]; ok {
+			cr.AutorizadoPor = null.StringFrom(gerente)
+		}
 		if err := cr.Insert(ctx, tx, boil.Infer()); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+				api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+				return
+			}
 			api.Logger.Error("conta bulk insert", zap.Error(err))
 			api.jsonError(w, r, http.StatusInternalServerError, "erro ao inserir conta")
 			return
@@ -439,6 +562,7 @@ func (api *Api) handlePedidoPagamentos_BulkPost(w http.ResponseWriter, r *http.R
 	if count, err = m.Pedidos(
 		qm.WhereIn("id IN ?", api.convertStringsToInterfaces(idsPedido)...),
 		qm.Where("tenant_id = ?", tenantID.String()),
+		qm.Where("id_status <> ?", dto.PedidoStatusCancelado),
 		qm.Where("deleted_at IS NULL")).
 		Count(ctx, tx); err != nil || count != int64(len(idsPedido)) {
 
@@ -478,8 +602,16 @@ func (api *Api) handlePedidoPagamentos_BulkPost(w http.ResponseWriter, r *http.R
 		if dto.Observacao != nil {
 			pp.Observacao.SetValid(*dto.Observacao)
 		}
+		if dto.Parcelas != nil {
+			pp.ParcelasCartao = *dto.Parcelas
+		}
 
 		if err := pp.Insert(ctx, tx, boil.Infer()); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+				api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+				return
+			}
 			api.Logger.Error("pagamento bulk insert", zap.Error(err))
 			api.jsonError(w, r, http.StatusInternalServerError, "erro ao inserir pagamento")
 			return
@@ -487,6 +619,17 @@ func (api *Api) handlePedidoPagamentos_BulkPost(w http.ResponseWriter, r *http.R
 		created = append(created, pp)
 	}
 
+	userID := api.getUserIDFromContext(r)
+	for _, pp := range created {
+		if err := api.emitOutboxEvent(ctx, tx, tenantID, userID,
+			dto.OutboxAggregatePagamento, pp.ID, dto.EventPagamentoRegistered,
+			dto.PagamentoEventPayloadFromModel(pp)); err != nil {
+			api.Logger.Error("pagamento bulk outbox", zap.Error(err))
+			api.jsonError(w, r, http.StatusInternalServerError, "erro ao registrar evento")
+			return
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		api.Logger.Error("pagamento bulk commit", zap.Error(err))
 		api.jsonError(w, r, http.StatusInternalServerError, "erro ao finalizar transação")
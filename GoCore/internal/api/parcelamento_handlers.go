@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// POST /api/v1/pedidos/{id}/parcelamento/preview
+// Calcula as parcelas (valores e vencimentos) sem gravar. Aponta se o
+// cliente está fora da política de crédito e vai exigir gerente.
+func (api *Api) handlePedidos_ParcelamentoPreview(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	in, _, ok := api.decodeParcelamento(w, r, tenantID)
+	if !ok {
+		return
+	}
+
+	plano, err := api.ParcelamentoService.Simular(r.Context(), in)
+	if err != nil {
+		api.parcelamentoError(w, r, err)
+		return
+	}
+
+	negados, err := api.CreditoService.VerificarParcelas(r.Context(), tenantID, []dto.ParcelaCreditoDTO{
+		{IDPedido: in.IDPedido, Valor: plano.ValorParcelado},
+	})
+	if err != nil {
+		api.parcelamentoError(w, r, err)
+		return
+	}
+	if len(negados) > 0 {
+		plano.CreditoNegado = &negados[0]
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, plano)
+}
+
+// POST /api/v1/pedidos/{id}/parcelamento
+// Gera o crediário do pedido: entrada (se houver) como pagamento e as
+// parcelas em contas a receber, com o resto dos centavos na última.
+func (api *Api) handlePedidos_Parcelamento(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	in, autorizacao, ok := api.decodeParcelamento(w, r, tenantID)
+	if !ok {
+		return
+	}
+
+	plano, err := api.ParcelamentoService.Simular(r.Context(), in)
+	if err != nil {
+		api.parcelamentoError(w, r, err)
+		return
+	}
+
+	// Limite e atraso do cliente; fora da política só com gerente
+	autorizadas, ok := api.verificarCredito(w, r, tenantID, []dto.ParcelaCreditoDTO{
+		{IDPedido: in.IDPedido, Valor: plano.ValorParcelado},
+	}, autorizacao)
+	if !ok {
+		return
+	}
+	if gerente, ok := autorizadas[in.IDPedido.String()]; ok {
+		id := uuid.MustParse(gerente)
+		in.AutorizadoPor = &id
+	}
+
+	parcelamento, err := api.ParcelamentoService.Gerar(r.Context(), in)
+	if err != nil {
+		api.parcelamentoError(w, r, err)
+		return
+	}
+
+	api.Logger.Info("parcelamento gerado",
+		zap.String("pedido_id", in.IDPedido.String()),
+		zap.Int("parcelas", len(parcelamento.Parcelas)),
+		zap.String("valor_parcelado", parcelamento.ValorParcelado.String()))
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, parcelamento)
+}
+
+// decodeParcelamento lê o pedido da URL e o plano do corpo. Devolve false
+// se a resposta de erro já foi escrita.
+func (api *Api) decodeParcelamento(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) (dto.ParcelamentoDTO, *dto.AutorizacaoGerenteDTO, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return dto.ParcelamentoDTO{}, nil, false
+	}
+
+	req, problems, err := jsonutils.DecodeValidJsonV10[dto.ParcelamentoRequest](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return dto.ParcelamentoDTO{}, nil, false
+	}
+
+	return req.ToDTO(tenantID, api.getUserIDFromContext(r), id), req.Autorizacao, true
+}
+
+func (api *Api) parcelamentoError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		invalido *services.ParcelamentoInvalidoError
+		pgErr    *pgconn.PgError
+	)
+	switch {
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrPedidoQuitado),
+		errors.Is(err, services.ErrPedidoJaCancelado):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrPedidoNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, "pedido not found or not authorized")
+	case errors.Is(err, services.ErrTenantNaoEncontrado),
+		errors.Is(err, services.ErrClienteNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		// regra de negócio do banco (restante do pedido, crédito, caixa fechado...)
+		api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+	default:
+		api.Logger.Error("erro no parcelamento", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
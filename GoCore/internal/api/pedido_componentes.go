@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+
+	"gobid/internal/dto"
+	"gobid/internal/models_sql_boiler"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// inserirComponentes grava as escolhas do combo como itens filhos do item
+// do combo, cada um com seus sabores. O valor de cada componente é o
+// acréscimo do slot, que a cotação já aplicou e o trigger trg_pi_preco_combo
+// confere (migration 064). Na edição, preparos devolve o estado de KDS dos
+// componentes que não mudaram.
+func inserirComponentes(ctx context.Context, exec boil.ContextExecutor, combo *models_sql_boiler.PedidoItem, componentes []dto.PedidoItemComponenteDTO, preparos preparosItens) error {
+	for i := range componentes {
+		it := componentes[i].ToModel(combo)
+		it.ID = uuid.New().String()
+		preparos.aplicar(it, componentes[i].Sabores, nil)
+
+		if err := it.Insert(ctx, exec, boil.Infer()); err != nil {
+			return err
+		}
+		if err := inserirSabores(ctx, exec, it.ID, componentes[i].Sabores); err != nil {
+			return err
+		}
+	}
+	return nil
+}
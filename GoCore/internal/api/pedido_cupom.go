@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gobid/internal/jsonutils"
+	"gobid/internal/models_sql_boiler"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"go.uber.org/zap"
+)
+
+// O cupom é conferido na cotação, mas dois pedidos simultâneos podem passar
+// pela cotação com o último uso disponível. O resgate abaixo é o que vale:
+// roda na transação do pedido e o UPDATE em cupons trava a linha do cupom
+// até o commit, então os limites são conferidos de novo já serializados.
+// A devolução no cancelamento/exclusão é feita pelo trigger
+// trg_pedidos_liberar_cupom (migration 065).
+
+var (
+	errCupomEsgotado      = errors.New("cupom esgotado")
+	errCupomLimiteCliente = errors.New("cliente atingiu o limite de uso do cupom")
+)
+
+// resgatarCupom registra o uso do cupom validado pela cotação
+// (PedidoCreateDTO.IDCupom). Sem cupom não faz nada.
+func resgatarCupom(ctx context.Context, exec boil.ContextExecutor, pedido *models_sql_boiler.Pedido, idCupom *string) error {
+	if idCupom == nil {
+		return nil
+	}
+
+	res, err := queries.Raw(`
+		UPDATE cupons
+		   SET usos = usos + 1
+		 WHERE id = $1
+		   AND ativo
+		   AND deleted_at IS NULL
+		   AND (limite_total IS NULL OR usos < limite_total)`,
+		*idCupom,
+	).ExecContext(ctx, exec)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errCupomEsgotado
+	}
+
+	var uso struct {
+		Limite null.Int `boil:"limite_por_cliente"`
+		Usos   int      `boil:"usos"`
+	}
+	err = queries.Raw(`
+		SELECT c.limite_por_cliente,
+		       (SELECT COUNT(*)
+		          FROM cupom_resgates r
+		         WHERE r.id_cupom = c.id
+		           AND r.id_cliente = $2
+		           AND r.cancelado_em IS NULL) AS usos
+		  FROM cupons c
+		 WHERE c.id = $1`,
+		*idCupom, pedido.IDCliente,
+	).Bind(ctx, exec, &uso)
+	if err != nil {
+		return err
+	}
+	if uso.Limite.Valid && uso.Usos >= uso.Limite.Int {
+		return errCupomLimiteCliente
+	}
+
+	_, err = queries.Raw(`
+		INSERT INTO cupom_resgates (id_cupom, id_pedido, id_cliente, valor_desconto)
+		VALUES ($1, $2, $3, $4)`,
+		*idCupom, pedido.ID, pedido.IDCliente, pedido.Desconto,
+	).ExecContext(ctx, exec)
+	return err
+}
+
+// liberarCupom devolve o uso do cupom do pedido, antes de regravar o
+// resgate na edição
+func liberarCupom(ctx context.Context, exec boil.ContextExecutor, idPedido string) error {
+	_, err := queries.Raw(`SELECT liberar_cupom_pedido($1)`, idPedido).ExecContext(ctx, exec)
+	return err
+}
+
+func (api *Api) resgateCupomError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errCupomEsgotado) || errors.Is(err, errCupomLimiteCliente) {
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		return
+	}
+	api.Logger.Error("erro ao resgatar cupom", zap.Error(err))
+	jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+}
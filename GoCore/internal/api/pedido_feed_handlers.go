@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gobid/internal/dto"
+	"gobid/internal/services"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const pedidoFeedHeartbeat = 25 * time.Second
+
+// GET /api/v1/pedidos/stream
+// Server-Sent Events com os eventos de pedidos do tenant. Ao reconectar, o
+// EventSource do navegador envia o cabeçalho Last-Event-ID automaticamente;
+// clientes que não usam EventSource podem informar ?last_event_id=.
+func (api *Api) handlePedidos_Stream(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	lastEventID, err := api.pedidoFeedLastEventID(r)
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid last event id")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: não bufferizar
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, args ...any) error {
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := write("retry: 3000\n\n"); err != nil {
+		return
+	}
+
+	send := func(ev dto.PedidoFeedEvent) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return write("id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.EventType, data)
+	}
+	heartbeat := func() error {
+		return write(": ping\n\n")
+	}
+
+	err = api.PedidoFeedHub.Stream(r.Context(), tenantID, lastEventID, pedidoFeedHeartbeat, send, heartbeat)
+	if errors.Is(err, services.ErrPedidoFeedLagged) {
+		// o EventSource reconecta sozinho com o último id recebido
+		_ = write("event: reconnect\ndata: {}\n\n")
+		return
+	}
+	if err != nil && r.Context().Err() == nil {
+		api.Logger.Warn("feed de pedidos (SSE) encerrado", zap.Error(err))
+	}
+}
+
+// GET /api/v1/pedidos/ws
+// Mesmo feed via WebSocket: cada mensagem é um dto.PedidoFeedEvent em JSON.
+// Para retomar, reconecte com ?last_event_id= do último evento recebido.
+func (api *Api) handlePedidos_WebSocket(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	lastEventID, err := api.pedidoFeedLastEventID(r)
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid last event id")
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: api.checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			// o cliente não envia nada; a leitura serve para detectar o fechamento
+			go func() {
+				var discard []byte
+				for {
+					if err := websocket.Message.Receive(ws, &discard); err != nil {
+						cancel()
+						return
+					}
+				}
+			}()
+
+			send := func(ev dto.PedidoFeedEvent) error {
+				return websocket.JSON.Send(ws, ev)
+			}
+			heartbeat := func() error {
+				return websocket.JSON.Send(ws, map[string]string{"event_type": "heartbeat"})
+			}
+
+			err := api.PedidoFeedHub.Stream(ctx, tenantID, lastEventID, pedidoFeedHeartbeat, send, heartbeat)
+			if errors.Is(err, services.ErrPedidoFeedLagged) {
+				_ = websocket.JSON.Send(ws, map[string]string{"event_type": "reconnect"})
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				api.Logger.Warn("feed de pedidos (WebSocket) encerrado", zap.Error(err))
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+func (api *Api) pedidoFeedLastEventID(r *http.Request) (int64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid last event id")
+	}
+	return id, nil
+}
+
+// O WebSocket não passa pelo CORS: aceita apenas a própria origem ou as
+// origens liberadas no CORS da API.
+func (api *Api) checkWebSocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil || origin == nil {
+		return errors.New("origin ausente")
+	}
+	if origin.Host == r.Host || slices.Contains(corsAllowedOrigins, (&url.URL{Scheme: origin.Scheme, Host: origin.Host}).String()) {
+		config.Origin = origin
+		return nil
+	}
+	return fmt.Errorf("origin não permitida: %s", origin.String())
+}
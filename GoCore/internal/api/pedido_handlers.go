@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"gobid/internal/decimalutils"
 	"gobid/internal/dto"
 	"gobid/internal/jsonutils"
 	"gobid/internal/models_sql_boiler"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"go.uber.org/zap"
@@ -152,6 +154,11 @@ func (api *Api) handlePedidos_List(w http.ResponseWriter, r *http.Request) {
 	for i, pedido := range pedidos {
 		pedidosDTO[i] = dto.PedidoModelToResponse(pedido)
 	}
+	if err := anexarSabores(r.Context(), api.SQLBoilerDB.GetDB(), pedidosDTO...); err != nil {
+		api.Logger.Error("erro ao buscar sabores dos pedidos", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
 
 	response := map[string]any{
 		"pedidos": pedidosDTO,
@@ -216,6 +223,11 @@ func (api *Api) handlePedidos_Get(w http.ResponseWriter, r *http.Request) {
 
 	// Converter para DTO
 	resp := dto.PedidoModelToResponse(pedido)
+	if err := anexarSabores(r.Context(), api.SQLBoilerDB.GetDB(), resp); err != nil {
+		api.Logger.Error("erro ao buscar sabores do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
 	jsonutils.EncodeJson(w, r, http.StatusOK, resp)
 }
 
@@ -244,6 +256,7 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 
 	// Definir o tenant ID no DTO
 	createDTO.TenantID = tenantID.String()
+	createDTO.NormalizarSabores()
 
 	// Verificar se o cliente existe e pertence ao tenant
 	_, err = models_sql_boiler.Clientes(
@@ -261,19 +274,33 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verificar se o status existe
-	_, err = models_sql_boiler.PedidoStatuses(
-		qm.Where("id = ?", createDTO.IDStatus),
-	).One(r.Context(), api.SQLBoilerDB.GetDB())
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "status not found"})
+	// Pedido nasce Confirmado, ou Agendado quando tem horário marcado; os
+	// demais status só por PUT /pedidos/{id}/status, que valida a transição
+	// e grava o histórico
+	if createDTO.AgendadoPara != nil {
+		if createDTO.IDStatus != 0 && createDTO.IDStatus != dto.PedidoStatusAgendado {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "pedido com agendado_para nasce com status agendado"})
+			return
+		}
+		if err := api.AgendamentoService.ValidarHorario(r.Context(), tenantID, nil,
+			createDTO.TipoEntrega, *createDTO.AgendadoPara); err != nil {
+			api.agendamentoError(w, r, err)
+			return
+		}
+		createDTO.IDStatus = dto.PedidoStatusAgendado
+		agendadoPor := api.getUserIDFromContext(r).String()
+		createDTO.AgendadoPor = &agendadoPor
+	} else {
+		switch createDTO.IDStatus {
+		case 0, dto.PedidoStatusConfirmado:
+			createDTO.IDStatus = dto.PedidoStatusConfirmado
+		case dto.PedidoStatusAgendado:
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "pedido agendado exige agendado_para"})
+			return
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "pedido novo nasce com status confirmado"})
 			return
 		}
-		api.Logger.Error("erro ao buscar status", zap.Error(err))
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
-		return
 	}
 
 	// Verificar se o código do pedido já existe para este tenant
@@ -294,6 +321,16 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Categorias só podem ser pedidas no horário e dia de venda
+	if !api.verificarDisponibilidade(w, r, tenantID, &createDTO) {
+		return
+	}
+
+	// Preços, adicionais e total vêm do cardápio, não do cliente
+	if !api.precificarPedido(w, r, tenantID, uuid.Nil, &createDTO) {
+		return
+	}
+
 	// Iniciar transação
 	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
 	if err != nil {
@@ -316,19 +353,42 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 
 	// Inserir o pedido
 	if err := pedido.Insert(r.Context(), tx, boil.Infer()); err != nil {
+		// Faixa de agendamento lotada entre a validação e o insert
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": pgErr.Message})
+			return
+		}
 		api.Logger.Error("erro ao inserir pedido", zap.Error(err))
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error creating pedido"})
 		return
 	}
 
+	// Resgate do cupom; trava os limites de uso até o commit
+	if err := resgatarCupom(r.Context(), tx, pedido, createDTO.IDCupom); err != nil {
+		api.resgateCupomError(w, r, err)
+		return
+	}
+
 	// Inserir itens do pedido
 	for i, item := range itens {
 		item.ID = uuid.New().String()
 		item.IDPedido = pedido.ID
 
 		if err := item.Insert(r.Context(), tx, boil.Infer()); err != nil {
-			api.Logger.Error("erro ao inserir item do pedido", zap.Error(err))
-			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error creating pedido item"})
+			api.itemPedidoError(w, r, err, "erro ao inserir item do pedido", "error creating pedido item")
+			return
+		}
+
+		// Sabores do item (pizza com mais de um sabor)
+		if err := inserirSabores(r.Context(), tx, item.ID, createDTO.Itens[i].Sabores); err != nil {
+			api.itemPedidoError(w, r, err, "erro ao inserir sabores do item", "error creating pedido item")
+			return
+		}
+
+		// Componentes do combo viram itens filhos
+		if err := inserirComponentes(r.Context(), tx, item, createDTO.Itens[i].Componentes, nil); err != nil {
+			api.itemPedidoError(w, r, err, "erro ao inserir componentes do combo", "error creating pedido item")
 			return
 		}
 
@@ -353,6 +413,22 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Recarregar para obter codigo_pedido e valores calculados pelos triggers
+	if err := pedido.Reload(r.Context(), tx); err != nil {
+		api.Logger.Error("erro ao recarregar pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
+	// Registrar evento no outbox (mesma transação)
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, api.getUserIDFromContext(r),
+		dto.OutboxAggregatePedido, pedido.ID, dto.EventPedidoCreated,
+		dto.PedidoEventPayloadFromModel(pedido, len(itens))); err != nil {
+		api.Logger.Error("erro ao registrar evento do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
 	// Confirmar transação
 	if err := tx.Commit(); err != nil {
 		api.Logger.Error("erro ao confirmar transação", zap.Error(err))
@@ -386,6 +462,9 @@ func (api *Api) handlePedidos_Post(w http.ResponseWriter, r *http.Request) {
 
 	// Converter e retornar
 	resp := dto.PedidoModelToResponse(pedidoCompleto)
+	if err := anexarSabores(r.Context(), api.SQLBoilerDB.GetDB(), resp); err != nil {
+		api.Logger.Error("erro ao buscar sabores do pedido criado", zap.Error(err))
+	}
 	jsonutils.EncodeJson(w, r, http.StatusCreated, resp)
 }
 
@@ -429,6 +508,7 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 	// Definir o ID e tenant no DTO
 	updateDTO.ID = id
 	updateDTO.TenantID = tenantID.String()
+	updateDTO.NormalizarSabores()
 
 	// Verificar se o pedido exists e pertence ao tenant
 	pedidoExistente, err := models_sql_boiler.Pedidos(
@@ -458,6 +538,33 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Status só muda pelo PUT /pedidos/{id}/status, que valida a transição
+	if updateDTO.IDStatus != pedidoExistente.IDStatus {
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "use PUT /pedidos/{id}/status para alterar o status"})
+		return
+	}
+
+	// Reagendar só enquanto o pedido não foi liberado para a cozinha
+	reagendar := updateDTO.AgendadoPara != nil &&
+		!(pedidoExistente.AgendadoPara.Valid && pedidoExistente.AgendadoPara.Time.Equal(*updateDTO.AgendadoPara))
+	if reagendar {
+		if pedidoExistente.IDStatus != dto.PedidoStatusAgendado {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "só é possível reagendar pedido no status agendado"})
+			return
+		}
+		idPedido := uuid.MustParse(id)
+		if err := api.AgendamentoService.ValidarHorario(r.Context(), tenantID, &idPedido,
+			updateDTO.TipoEntrega, *updateDTO.AgendadoPara); err != nil {
+			api.agendamentoError(w, r, err)
+			return
+		}
+	}
+
+	// O código de pedido agendado é do banco (provisório e depois definitivo)
+	if pedidoExistente.AgendadoPara.Valid {
+		updateDTO.CodigoPedido = pedidoExistente.CodigoPedido
+	}
+
 	// Verificar se o código do pedido já existe para outro pedido do mesmo tenant
 	if updateDTO.CodigoPedido != pedidoExistente.CodigoPedido {
 		exists, err := models_sql_boiler.Pedidos(
@@ -479,6 +586,11 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Preços, adicionais e total vêm do cardápio, não do cliente
+	if !api.precificarPedido(w, r, tenantID, uuid.MustParse(id), &updateDTO.PedidoCreateDTO) {
+		return
+	}
+
 	// Iniciar transação
 	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
 	if err != nil {
@@ -495,15 +607,48 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid data"})
 		return
 	}
+	// A liberação de disponibilidade é da criação; a edição não a altera
+	pedido.DisponibilidadeLiberadaPor = pedidoExistente.DisponibilidadeLiberadaPor
+	// Agendamento: horário só muda ao reagendar; liberação e autor não mudam
+	if !reagendar {
+		pedido.AgendadoPara = pedidoExistente.AgendadoPara
+	}
+	pedido.LiberadoEm = pedidoExistente.LiberadoEm
+	pedido.AgendadoPor = pedidoExistente.AgendadoPor
 
 	// Atualizar dados do pedido
 	_, err = pedido.Update(r.Context(), tx, boil.Infer())
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "P0001" {
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": pgErr.Message})
+			return
+		}
 		api.Logger.Error("erro ao atualizar pedido", zap.Error(err))
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error updating pedido"})
 		return
 	}
 
+	// O cupom é resgatado de novo com o desconto recalculado
+	if err := liberarCupom(r.Context(), tx, id); err != nil {
+		api.Logger.Error("erro ao liberar cupom do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error updating pedido"})
+		return
+	}
+	if err := resgatarCupom(r.Context(), tx, pedido, updateDTO.IDCupom); err != nil {
+		api.resgateCupomError(w, r, err)
+		return
+	}
+
+	// Estado de preparo dos itens que a cozinha já pegou; volta para os
+	// itens que não mudaram
+	preparos, err := carregarPreparos(r.Context(), tx, pedidoExistente)
+	if err != nil {
+		api.Logger.Error("erro ao carregar preparo dos itens", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error updating pedido"})
+		return
+	}
+
 	// Remover itens e adicionais existentes (soft delete)
 	if pedidoExistente.R != nil && pedidoExistente.R.IDPedidoPedidoItens != nil {
 		for _, itemExistente := range pedidoExistente.R.IDPedidoPedidoItens {
@@ -535,10 +680,22 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 	for i, item := range novosItens {
 		item.ID = uuid.New().String()
 		item.IDPedido = id
+		preparos.aplicar(item, updateDTO.Itens[i].Sabores, updateDTO.Itens[i].Adicionais)
 
 		if err := item.Insert(r.Context(), tx, boil.Infer()); err != nil {
-			api.Logger.Error("erro ao inserir novo item do pedido", zap.Error(err))
-			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error updating pedido"})
+			api.itemPedidoError(w, r, err, "erro ao inserir novo item do pedido", "error updating pedido")
+			return
+		}
+
+		// Sabores do item (pizza com mais de um sabor)
+		if err := inserirSabores(r.Context(), tx, item.ID, updateDTO.Itens[i].Sabores); err != nil {
+			api.itemPedidoError(w, r, err, "erro ao inserir sabores do item", "error updating pedido")
+			return
+		}
+
+		// Componentes do combo viram itens filhos
+		if err := inserirComponentes(r.Context(), tx, item, updateDTO.Itens[i].Componentes, preparos); err != nil {
+			api.itemPedidoError(w, r, err, "erro ao inserir componentes do combo", "error updating pedido")
 			return
 		}
 
@@ -563,6 +720,38 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Recarregar para obter os valores recalculados pelos triggers
+	if err := pedido.Reload(r.Context(), tx); err != nil {
+		api.Logger.Error("erro ao recarregar pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
+	// Registrar eventos no outbox (mesma transação)
+	userID := api.getUserIDFromContext(r)
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, userID,
+		dto.OutboxAggregatePedido, pedido.ID, dto.EventPedidoUpdated,
+		dto.PedidoEventPayloadFromModel(pedido, len(novosItens))); err != nil {
+		api.Logger.Error("erro ao registrar evento do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
+	if pedido.IDStatus != pedidoExistente.IDStatus {
+		if err := api.emitOutboxEvent(r.Context(), tx, tenantID, userID,
+			dto.OutboxAggregatePedido, pedido.ID, dto.EventPedidoStatusChanged,
+			dto.PedidoStatusChangedPayload{
+				ID:             pedido.ID,
+				CodigoPedido:   pedido.CodigoPedido,
+				StatusAnterior: pedidoExistente.IDStatus,
+				StatusNovo:     pedido.IDStatus,
+			}); err != nil {
+			api.Logger.Error("erro ao registrar evento de status", zap.Error(err))
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+			return
+		}
+	}
+
 	// Confirmar transação
 	if err := tx.Commit(); err != nil {
 		api.Logger.Error("erro ao confirmar transação", zap.Error(err))
@@ -596,6 +785,9 @@ func (api *Api) handlePedidos_Put(w http.ResponseWriter, r *http.Request) {
 
 	// Converter e retornar
 	resp := dto.PedidoModelToResponse(pedidoAtualizado)
+	if err := anexarSabores(r.Context(), api.SQLBoilerDB.GetDB(), resp); err != nil {
+		api.Logger.Error("erro ao buscar sabores do pedido atualizado", zap.Error(err))
+	}
 	jsonutils.EncodeJson(w, r, http.StatusOK, resp)
 }
 
@@ -645,6 +837,12 @@ func (api *Api) handlePedidos_Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pedido com pagamento precisa ser cancelado (estorno + auditoria)
+	if decimalutils.ToCentavos(pedido.ValorPago) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "pedido com pagamento: use POST /pedidos/{id}/cancelar"})
+		return
+	}
+
 	// Iniciar transação
 	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
 	if err != nil {
@@ -689,6 +887,15 @@ func (api *Api) handlePedidos_Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Registrar evento no outbox (mesma transação)
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, api.getUserIDFromContext(r),
+		dto.OutboxAggregatePedido, pedido.ID, dto.EventPedidoDeleted,
+		dto.PedidoEventPayloadFromModel(pedido, len(pedido.R.GetIDPedidoPedidoItens()))); err != nil {
+		api.Logger.Error("erro ao registrar evento do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "error deleting pedido"})
+		return
+	}
+
 	// Confirmar transação
 	if err := tx.Commit(); err != nil {
 		api.Logger.Error("erro ao confirmar transação", zap.Error(err))
@@ -725,11 +932,7 @@ func (api *Api) handlePedidos_PutStatus(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Decodificar e validar o payload JSON
-	type UpdateStatusRequest struct {
-		IDStatus int16 `json:"id_status" validate:"required"`
-	}
-
-	updateDTO, problems, err := jsonutils.DecodeValidJsonV10[UpdateStatusRequest](r)
+	updateDTO, problems, err := jsonutils.DecodeValidJsonV10[dto.UpdatePedidoStatusDTO](r)
 	if err != nil {
 		api.Logger.Error("erro ao decodificar/validar JSON", zap.Error(err))
 		if problems != nil {
@@ -740,50 +943,35 @@ func (api *Api) handlePedidos_PutStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Verificar se o status existe
-	_, err = models_sql_boiler.PedidoStatuses(
-		qm.Where("id = ?", updateDTO.IDStatus),
-	).One(r.Context(), api.SQLBoilerDB.GetDB())
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "status not found"})
-			return
-		}
-		api.Logger.Error("erro ao buscar status", zap.Error(err))
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+	// Cancelamento estorna pagamentos e parcelas; tem endpoint próprio
+	if updateDTO.IDStatus == dto.PedidoStatusCancelado {
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "use POST /pedidos/{id}/cancelar para cancelar o pedido"})
 		return
 	}
 
-	// Buscar pedido existente
-	pedido, err := models_sql_boiler.Pedidos(
-		qm.Where("pedidos.id = ?", id),
-		qm.Where("pedidos.tenant_id = ?", tenantID.String()),
-		qm.Where("pedidos.deleted_at IS NULL"),
-	).One(r.Context(), api.SQLBoilerDB.GetDB())
-
+	// Transição validada pelo grafo de status; grava histórico e outbox
+	transicao, err := api.PedidoStatusService.AlterarStatus(r.Context(), dto.AlterarStatusPedidoDTO{
+		TenantID: tenantID,
+		UserID:   api.getUserIDFromContext(r),
+		IDPedido: uuid.MustParse(id),
+		IDStatus: updateDTO.IDStatus,
+		Motivo:   updateDTO.Motivo,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "pedido not found or not authorized"})
-			return
-		}
-		api.Logger.Error("erro ao buscar pedido", zap.Error(err))
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		api.pedidoStatusError(w, r, err)
 		return
 	}
 
-	// Atualizar status
-	pedido.IDStatus = updateDTO.IDStatus
-
-	// Salvar alterações
-	_, err = pedido.Update(r.Context(), api.SQLBoilerDB.GetDB(), boil.Infer())
-	if err != nil {
-		api.Logger.Error("erro ao atualizar status do pedido", zap.Error(err))
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+	// Nada a fazer se o status não mudou
+	if transicao == nil {
+		jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "status updated successfully"})
 		return
 	}
 
-	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "status updated successfully"})
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":   "status updated successfully",
+		"transicao": transicao,
+	})
 }
 
 // handlePedidos_PutPedidoPronto marca pedido como pronto ou não pronto
@@ -855,14 +1043,44 @@ func (api *Api) handlePedidos_PutPedidoPronto(w http.ResponseWriter, r *http.Req
 		pedido.DataPedidoPronto.Valid = false
 	}
 
+	// Iniciar transação
+	tx, err := api.SQLBoilerDB.GetDB().BeginTx(r.Context(), nil)
+	if err != nil {
+		api.Logger.Error("erro ao iniciar transação", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+	defer tx.Rollback()
+
 	// Salvar alterações
-	_, err = pedido.Update(r.Context(), api.SQLBoilerDB.GetDB(), boil.Infer())
+	_, err = pedido.Update(r.Context(), tx, boil.Infer())
 	if err != nil {
 		api.Logger.Error("erro ao atualizar pedido_pronto", zap.Error(err))
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
 		return
 	}
 
+	// Registrar evento no outbox (mesma transação)
+	if err := api.emitOutboxEvent(r.Context(), tx, tenantID, api.getUserIDFromContext(r),
+		dto.OutboxAggregatePedido, pedido.ID, dto.EventPedidoPronto,
+		dto.PedidoProntoPayload{
+			ID:               pedido.ID,
+			CodigoPedido:     pedido.CodigoPedido,
+			PedidoPronto:     pedido.PedidoPronto,
+			DataPedidoPronto: api.nullTimeToPtr(pedido.DataPedidoPronto),
+		}); err != nil {
+		api.Logger.Error("erro ao registrar evento de pedido pronto", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		api.Logger.Error("erro ao confirmar transação", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "pedido_pronto updated successfully"})
 }
 
@@ -912,6 +1130,11 @@ func (api *Api) handlePedidos_GetByCodigoPedido(w http.ResponseWriter, r *http.R
 
 	// Converter para DTO
 	resp := dto.PedidoModelToResponse(pedido)
+	if err := anexarSabores(r.Context(), api.SQLBoilerDB.GetDB(), resp); err != nil {
+		api.Logger.Error("erro ao buscar sabores do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
 	jsonutils.EncodeJson(w, r, http.StatusOK, resp)
 }
 
@@ -1046,6 +1269,14 @@ func (api *Api) handlePedidos_GetDadosEdicao(w http.ResponseWriter, r *http.Requ
 		pedido.R.IDPedidoPedidoItens = itensAtivos
 	}
 
+	// Sabores dos itens com mais de um sabor
+	sabores, err := carregarSabores(r.Context(), api.SQLBoilerDB.GetDB(), pedido.ID)
+	if err != nil {
+		api.Logger.Error("erro ao buscar sabores do pedido", zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
 	// 3. Extrair IDs únicos dos itens ativos do pedido
 	categoriaIDs := make([]string, 0)
 	produtoIDs := make([]string, 0)
@@ -1062,6 +1293,11 @@ func (api *Api) handlePedidos_GetDadosEdicao(w http.ResponseWriter, r *http.Requ
 			if item.IDProduto2.Valid {
 				produtoIDs = append(produtoIDs, item.IDProduto2.String)
 			}
+			for _, sabor := range sabores {
+				if sabor.IDPedidoItem == item.ID {
+					produtoIDs = append(produtoIDs, sabor.IDProduto)
+				}
+			}
 
 			// Coletar categoria opcao IDs (importante para buscar preços corretos)
 			if item.IDCategoriaOpcao.Valid {
@@ -1165,6 +1401,6 @@ func (api *Api) handlePedidos_GetDadosEdicao(w http.ResponseWriter, r *http.Requ
 	}
 
 	// 7. Converter para DTO e retornar
-	resp := dto.ConvertPedidoToEdicaoResponse(pedido, categorias, produtos, adicionais)
+	resp := dto.ConvertPedidoToEdicaoResponse(pedido, categorias, produtos, adicionais, sabores)
 	jsonutils.EncodeJson(w, r, http.StatusOK, resp)
 }
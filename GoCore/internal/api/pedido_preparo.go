@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gobid/internal/dto"
+	"gobid/internal/models_sql_boiler"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// preparoItem é o estado de KDS de um item (ver 058_kds.sql)
+type preparoItem struct {
+	idEstacao     null.String
+	statusPreparo string
+	inicio        null.Time
+	fim           null.Time
+	despacho      null.Time
+}
+
+// preparosItens guarda, por assinatura do item, o estado de preparo dos itens
+// de um pedido antes da edição. A edição regrava todos os itens; os que não
+// mudaram recebem de volta o estado, para não voltarem à fila da cozinha.
+type preparosItens map[string][]preparoItem
+
+// carregarPreparos lê o estado de preparo dos itens atuais (com adicionais
+// já carregados) que saíram da fila; deve ser chamado antes de removê-los.
+func carregarPreparos(ctx context.Context, exec boil.ContextExecutor, pedido *models_sql_boiler.Pedido) (preparosItens, error) {
+	if pedido.R == nil || len(pedido.R.IDPedidoPedidoItens) == 0 {
+		return nil, nil
+	}
+
+	sabores, err := carregarSabores(ctx, exec, pedido.ID)
+	if err != nil {
+		return nil, err
+	}
+	saboresPorItem := make(map[string][]string)
+	for _, s := range sabores {
+		saboresPorItem[s.IDPedidoItem] = append(saboresPorItem[s.IDPedidoItem], saborChave(s.IDProduto, s.Partes))
+	}
+
+	preparos := make(preparosItens)
+	for _, it := range pedido.R.IDPedidoPedidoItens {
+		if it.StatusPreparo == dto.StatusPreparoQueued {
+			continue
+		}
+		var adicionais []string
+		if it.R != nil {
+			for _, a := range it.R.IDPedidoItemPedidoItemAdicionais {
+				adicionais = append(adicionais, adicionalChave(a.IDAdicionalOpcao, a.Quantidade))
+			}
+		}
+		k := assinaturaItem(it, saboresPorItem[it.ID], adicionais)
+		preparos[k] = append(preparos[k], preparoItem{
+			idEstacao:     it.IDEstacao,
+			statusPreparo: it.StatusPreparo,
+			inicio:        it.DataInicioPreparo,
+			fim:           it.DataFimPreparo,
+			despacho:      it.DataDespacho,
+		})
+	}
+	return preparos, nil
+}
+
+// aplicar devolve ao item novo o estado de um item igual de antes da edição
+func (p preparosItens) aplicar(it *models_sql_boiler.PedidoItem, sabores []dto.PedidoItemSaborDTO, adicionais []dto.PedidoItemAdicionalDTO) {
+	if len(p) == 0 {
+		return
+	}
+	sab := make([]string, len(sabores))
+	for i, s := range sabores {
+		sab[i] = saborChave(s.IDProduto, s.Partes)
+	}
+	ads := make([]string, len(adicionais))
+	for i, a := range adicionais {
+		ads[i] = adicionalChave(a.IDAdicionalOpcao, a.Quantidade)
+	}
+
+	k := assinaturaItem(it, sab, ads)
+	fila := p[k]
+	if len(fila) == 0 {
+		return
+	}
+	prep := fila[0]
+	p[k] = fila[1:]
+
+	it.IDEstacao = prep.idEstacao
+	it.StatusPreparo = prep.statusPreparo
+	it.DataInicioPreparo = prep.inicio
+	it.DataFimPreparo = prep.fim
+	it.DataDespacho = prep.despacho
+}
+
+// assinaturaItem identifica o que a cozinha prepara: produto, sabores,
+// adicionais, observação, quantidade e slot do combo. Preço não entra.
+func assinaturaItem(it *models_sql_boiler.PedidoItem, sabores, adicionais []string) string {
+	sort.Strings(adicionais)
+	return strings.Join([]string{
+		it.IDProduto,
+		it.IDProduto2.String,
+		it.IDCategoriaOpcao.String,
+		it.IDComboSlot.String,
+		it.Observacao.String,
+		fmt.Sprint(it.Quantidade),
+		strings.Join(sabores, ","),
+		strings.Join(adicionais, ","),
+	}, "|")
+}
+
+func saborChave(idProduto string, partes int) string {
+	if partes == 0 {
+		partes = 1
+	}
+	return fmt.Sprintf("%s:%d", idProduto, partes)
+}
+
+func adicionalChave(idOpcao string, quantidade int) string {
+	return fmt.Sprintf("%s:%d", idOpcao, quantidade)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"go.uber.org/zap"
+)
+
+// pedido_item_sabores não tem modelo do sqlboiler; os handlers de pedido
+// gravam e leem os sabores com SQL direto, na mesma transação dos itens.
+// Preço do sabor, validação da categoria e o valor_unitario do item ficam
+// por conta dos triggers da migration 063.
+
+// inserirSabores grava os sabores já normalizados do item (ver
+// dto.PedidoItemDTO.NormalizarSabores). Item de um sabor só não grava nada.
+func inserirSabores(ctx context.Context, exec boil.ContextExecutor, idPedidoItem string, sabores []dto.PedidoItemSaborDTO) error {
+	for i, s := range sabores {
+		_, err := queries.Raw(`
+			INSERT INTO pedido_item_sabores (id_pedido_item, id_produto, partes, ordem)
+			VALUES ($1, $2, $3, $4)`,
+			idPedidoItem, s.IDProduto, s.Partes, i,
+		).ExecContext(ctx, exec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemPedidoError responde a falha ao gravar item, sabores ou componentes:
+// regra dos triggers de sabores e meia pizza (P0001) é 422, sabor repetido
+// no item é 409 e o resto é 500 com msg.
+func (api *Api) itemPedidoError(w http.ResponseWriter, r *http.Request, err error, log, msg string) {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": pgErr.Message})
+	case errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uq_pis_item_produto":
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "sabor repetido no item"})
+	default:
+		api.Logger.Error(log, zap.Error(err))
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": msg})
+	}
+}
+
+type saborRow struct {
+	IDPedidoItem string            `boil:"id_pedido_item"`
+	IDProduto    string            `boil:"id_produto"`
+	ProdutoNome  string            `boil:"produto_nome"`
+	Partes       int               `boil:"partes"`
+	Valor        types.NullDecimal `boil:"valor"`
+}
+
+// carregarSabores lê os sabores dos itens dos pedidos informados, na ordem
+// em que foram enviados.
+func carregarSabores(ctx context.Context, exec boil.ContextExecutor, idsPedido ...string) ([]dto.PedidoItemSaborResponseDTO, error) {
+	if len(idsPedido) == 0 {
+		return nil, nil
+	}
+
+	var rows []saborRow
+	err := queries.Raw(`
+		SELECT s.id_pedido_item, s.id_produto, p.nome AS produto_nome, s.partes, s.valor
+		  FROM pedido_item_sabores s
+		  JOIN pedido_itens pi ON pi.id = s.id_pedido_item
+		  JOIN produtos p      ON p.id  = s.id_produto
+		 WHERE pi.id_pedido = ANY(string_to_array($1, ',')::uuid[])
+		   AND pi.deleted_at IS NULL
+		 ORDER BY s.id_pedido_item, s.ordem`,
+		strings.Join(idsPedido, ","),
+	).Bind(ctx, exec, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sabores := make([]dto.PedidoItemSaborResponseDTO, len(rows))
+	for i, row := range rows {
+		sabores[i] = dto.PedidoItemSaborResponseDTO{
+			IDPedidoItem: row.IDPedidoItem,
+			IDProduto:    row.IDProduto,
+			ProdutoNome:  row.ProdutoNome,
+			Partes:       row.Partes,
+			Valor:        row.Valor,
+		}
+	}
+	return sabores, nil
+}
+
+// anexarSabores carrega e distribui os sabores nas respostas de pedido
+func anexarSabores(ctx context.Context, exec boil.ContextExecutor, pedidos ...*dto.PedidoResponseDTO) error {
+	ids := make([]string, len(pedidos))
+	for i, p := range pedidos {
+		ids[i] = p.ID
+	}
+	sabores, err := carregarSabores(ctx, exec, ids...)
+	if err != nil {
+		return err
+	}
+	for _, p := range pedidos {
+		p.AnexarSabores(sabores)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/pedidos/{id}/historico
+// Linha do tempo de status do pedido, com a duração de cada etapa e os
+// tempos de preparo e de entrega.
+func (api *Api) handlePedidos_GetHistorico(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	historico, err := api.PedidoStatusService.ListHistorico(r.Context(), tenantID, id)
+	if err != nil {
+		api.pedidoStatusError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, historico)
+}
+
+func (api *Api) pedidoStatusError(w http.ResponseWriter, r *http.Request, err error) {
+	var transicao *services.TransicaoStatusInvalidaError
+	switch {
+	case errors.As(err, &transicao):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error":             err.Error(),
+			"status_atual":      transicao.De,
+			"status_solicitado": transicao.Para,
+			"status_permitidos": transicao.Permitidos,
+		})
+	case errors.Is(err, services.ErrPedidoNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, "pedido not found or not authorized")
+	case errors.Is(err, services.ErrStatusNaoEncontrado):
+		api.jsonError(w, r, http.StatusBadRequest, "status not found")
+	case errors.Is(err, services.ErrMotivoObrigatorio):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrLiberacaoSemCaixa):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	default:
+		api.Logger.Error("erro ao alterar status do pedido", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
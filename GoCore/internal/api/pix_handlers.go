@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/pixutils"
+	"gobid/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"go.uber.org/zap"
+)
+
+const (
+	tamanhoQRCodePadrao = 320
+	tamanhoQRCodeMin    = 128
+	tamanhoQRCodeMax    = 1024
+)
+
+// GET /api/v1/pix/config
+func (api *Api) handlePix_GetConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cfg, err := api.PixService.GetConfig(r.Context(), tenantID)
+	if err != nil {
+		api.pixError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// PUT /api/v1/pix/config
+func (api *Api) handlePix_PutConfig(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.PixConfigDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	cfg, err := api.PixService.SalvarConfig(r.Context(), tenantID, data)
+	if err != nil {
+		api.pixError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cfg)
+}
+
+// GET /api/v1/pix/qrcode?formato=png|json&tamanho=
+// QR fixo do balcão: sem valor, o cliente digita no app do banco.
+func (api *Api) handlePix_QRCodeEstatico(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cobranca, err := api.PixService.CobrancaEstatica(r.Context(), tenantID)
+	if err != nil {
+		api.pixError(w, r, err)
+		return
+	}
+
+	api.escreverCobrancaPix(w, r, cobranca, "pix.png")
+}
+
+// GET /api/v1/pedidos/{id}/pix?valor=&formato=png|json&tamanho=
+// BR Code do pedido com txid do codigo_pedido. Sem valor, cobra o saldo.
+func (api *Api) handlePedidos_Pix(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	var valor *types.Decimal
+	if v := r.URL.Query().Get("valor"); v != "" {
+		d, err := decimalutils.FromString(v)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "valor inválido")
+			return
+		}
+		valor = &d
+	}
+
+	cobranca, err := api.PixService.CobrancaPedido(r.Context(), tenantID, id, valor)
+	if err != nil {
+		api.pixError(w, r, err)
+		return
+	}
+
+	api.escreverCobrancaPix(w, r, cobranca, "pix-"+cobranca.TxID+".png")
+}
+
+// POST /api/v1/pedidos/{id}/pix/confirmar
+// Registra o PIX recebido como pagamento do pedido (forma PIX). A mesma
+// confirmação repetida responde 200 com o pagamento já gravado.
+func (api *Api) handlePedidos_PixConfirmar(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	req, problems, err := jsonutils.DecodeValidJsonV10[dto.PixConfirmacaoRequest](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	pagamento, err := api.PixService.Confirmar(r.Context(), dto.PixConfirmacaoDTO{
+		TenantID: tenantID,
+		UserID:   api.getUserIDFromContext(r),
+		IDPedido: id,
+		TxID:     req.TxID,
+		Valor:    req.Valor,
+		E2EID:    req.E2EID,
+	})
+	if err != nil {
+		api.pixError(w, r, err)
+		return
+	}
+
+	if pagamento.JaConfirmado {
+		jsonutils.EncodeJson(w, r, http.StatusOK, pagamento)
+		return
+	}
+
+	api.Logger.Info("pagamento PIX confirmado",
+		zap.String("pedido_id", id.String()),
+		zap.String("txid", pagamento.TxID))
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, pagamento)
+}
+
+// escreverCobrancaPix responde o PNG do QR Code ou, com formato=json, o
+// payload "copia e cola"
+func (api *Api) escreverCobrancaPix(w http.ResponseWriter, r *http.Request, cobranca dto.PixCobrancaResponse, arquivo string) {
+	switch r.URL.Query().Get("formato") {
+	case "json":
+		jsonutils.EncodeJson(w, r, http.StatusOK, cobranca)
+		return
+	case "", "png":
+	default:
+		api.jsonError(w, r, http.StatusBadRequest, "formato inválido: use png ou json")
+		return
+	}
+
+	tamanho := tamanhoQRCodePadrao
+	if v := r.URL.Query().Get("tamanho"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < tamanhoQRCodeMin || n > tamanhoQRCodeMax {
+			api.jsonError(w, r, http.StatusBadRequest, "tamanho deve estar entre 128 e 1024")
+			return
+		}
+		tamanho = n
+	}
+
+	png, err := pixutils.QRCodePNG(cobranca.Payload, tamanho)
+	if err != nil {
+		api.Logger.Error("erro ao gerar QR Code PIX", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", `inline; filename="`+arquivo+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(png)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Pix-Txid", cobranca.TxID)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(png)
+}
+
+func (api *Api) pixError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		invalido *services.PixInvalidoError
+		pgErr    *pgconn.PgError
+	)
+	switch {
+	case errors.As(err, &invalido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrPixNaoConfigurado):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrPedidoQuitado),
+		errors.Is(err, services.ErrPedidoJaCancelado),
+		errors.Is(err, services.ErrPixJaConfirmado):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrPedidoNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, "pedido not found or not authorized")
+	case errors.Is(err, services.ErrTenantNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		// regra de negócio do banco (valor acima do saldo, caixa fechado...)
+		api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+	default:
+		api.Logger.Error("erro na cobrança PIX", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// POST /api/v1/pedidos/quote
+// Calcula os preços do pedido pelo cardápio sem gravar nada, para a tela
+// mostrar o total antes do envio. Valores informados são opcionais e, se
+// presentes, as diferenças voltam em divergencias.
+func (api *Api) handlePedidos_Quote(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.PedidoCotacaoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	cotacao, err := api.PrecificacaoService.Cotar(r.Context(), data)
+	if err != nil {
+		api.cotacaoError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cotacao)
+}
+
+// precificarPedido recalcula os valores do pedido pelo cardápio antes de
+// gravar. Por padrão os valores enviados são corrigidos; com ?precos=estrito
+// qualquer divergência rejeita o pedido com 422 e o detalhamento.
+// idPedido é o pedido em edição (uuid.Nil na criação), para o cupom.
+// Devolve false se a resposta de erro já foi escrita.
+func (api *Api) precificarPedido(w http.ResponseWriter, r *http.Request, tenantID, idPedido uuid.UUID, pedido *dto.PedidoCreateDTO) bool {
+	cotacao, err := api.PrecificacaoService.Cotar(r.Context(), pedido.ToCotacaoDTO(tenantID, idPedido))
+	if err != nil {
+		api.cotacaoError(w, r, err)
+		return false
+	}
+
+	if len(cotacao.Divergencias) > 0 {
+		if r.URL.Query().Get("precos") == "estrito" {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error":   "valores divergentes do cardápio",
+				"cotacao": cotacao,
+			})
+			return false
+		}
+		api.Logger.Info("valores do pedido corrigidos pelo cardápio",
+			zap.String("tenant_id", tenantID.String()),
+			zap.Int("divergencias", len(cotacao.Divergencias)))
+	}
+
+	pedido.AplicarCotacao(cotacao)
+	return true
+}
+
+func (api *Api) cotacaoError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalida *services.CotacaoInvalidaError
+	if errors.As(err, &invalida) {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":     "pedido inválido para o cardápio",
+			"problemas": invalida.Problemas,
+		})
+		return
+	}
+	api.Logger.Error("erro ao precificar pedido", zap.Error(err))
+	api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+}
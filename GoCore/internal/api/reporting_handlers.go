@@ -1,108 +1,201 @@
 package api
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
-	"strings"
+	"strconv"
+
+	"gobid/internal/report"
+	"gobid/internal/services"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
-func (api *Api) handleGetWeatherForecast(w http.ResponseWriter, r *http.Request) {
-	// 1. Lê a base URL da .env
-	baseURL := os.Getenv("GOBID_FAST_API")
-	if baseURL == "" {
-		http.Error(w, "GOBID_FAST_API não configurado", http.StatusInternalServerError)
+// GET /api/v1/pedidos/relatorio/{id}?layout=80mm|a4
+// Comprovante do pedido em PDF. Sem layout usa a bobina de 80mm, como o
+// relatório antigo do FastReport.
+func (api *Api) handleGetReportPedido(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	// 2. Monta a URL completa (assegurando-se de não duplicar '/')
-	url := fmt.Sprintf("%s/WeatherForecast/GetReport", strings.TrimRight(baseURL, "/"))
-
-	// 3. Cria a requisição GET usando o mesmo contexto (para timeouts, cancelamentos, etc)
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
-	if err != nil {
-		http.Error(w, "erro ao criar requisição externa: "+err.Error(), http.StatusInternalServerError)
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		http.Error(w, "ID do pedido não fornecido", http.StatusBadRequest)
 		return
 	}
-
-	// 4. Executa a chamada para a API .NET
-	resp, err := http.DefaultClient.Do(req)
+	id, err := uuid.Parse(idParam)
 	if err != nil {
-		http.Error(w, "erro ao chamar API de relatórios: "+err.Error(), http.StatusBadGateway)
+		http.Error(w, "ID do pedido inválido", http.StatusBadRequest)
 		return
 	}
-	defer resp.Body.Close()
 
-	// 5. Se a API .NET retornar erro (ex: 404), repassa o status e o body
-	if resp.StatusCode != http.StatusOK {
-		w.WriteHeader(resp.StatusCode)
-		io.Copy(w, resp.Body)
+	layout, ok := report.LayoutPorNome(r.URL.Query().Get("layout"))
+	if !ok {
+		http.Error(w, "layout inválido: use 80mm ou a4", http.StatusBadRequest)
 		return
 	}
 
-	// 6. Copia os headers de conteúdo (Content-Type, Content-Disposition, etc)
-	for key, vals := range resp.Header {
-		for _, v := range vals {
-			w.Header().Add(key, v)
+	pdf, err := api.RelatorioService.PedidoPDF(r.Context(), tenantID, id, layout)
+	if err != nil {
+		if errors.Is(err, services.ErrPedidoNaoEncontrado) {
+			http.Error(w, fmt.Sprintf("Pedido com ID %s não encontrado.", id), http.StatusNotFound)
+			return
 		}
+		api.Logger.Error("erro ao gerar relatório do pedido", zap.String("pedido_id", id.String()), zap.Error(err))
+		http.Error(w, "erro ao gerar relatório do pedido", http.StatusInternalServerError)
+		return
 	}
 
-	// 7. Escreve o status 200 e copia o PDF para o ResponseWriter
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="relatorio-produto-nao-faturados.pdf"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
 	w.WriteHeader(http.StatusOK)
-	_, _ = io.Copy(w, resp.Body)
+	_, _ = w.Write(pdf)
 }
-func (api *Api) handleGetReportPedido(w http.ResponseWriter, r *http.Request) {
-	// 1. Lê a base URL da .env
-	baseURL := os.Getenv("GOBID_FAST_API")
-	if baseURL == "" {
-		http.Error(w, "GOBID_FAST_API não configurado", http.StatusInternalServerError)
+
+// GET /api/v1/pedidos/{id}/escpos?layout=cliente|cozinha
+// Bytes ESC/POS para o agente de impressão local enviar direto à
+// impressora térmica. Ver escPosConfig para os demais parâmetros.
+func (api *Api) handlePedidos_EscPos(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		http.Error(w, "ID do pedido não fornecido", http.StatusBadRequest)
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	gerar := report.EscPosPedido
+	switch layout := r.URL.Query().Get("layout"); layout {
+	case "", "cliente":
+	case "cozinha":
+		gerar = report.EscPosCozinha
+	default:
+		api.jsonError(w, r, http.StatusBadRequest, "layout inválido: use cliente ou cozinha")
+		return
+	}
+
+	cfg, err := escPosConfig(r)
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pedido, err := api.RelatorioService.CarregarPedido(r.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, services.ErrPedidoNaoEncontrado) {
+			api.jsonError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		api.Logger.Error("erro ao carregar pedido para impressão", zap.String("pedido_id", id.String()), zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	// 2. Monta a URL completa (assegurando-se de não duplicar '/')
-	url := fmt.Sprintf("%s/Pedido/GetReportPedidoById/%s", strings.TrimRight(baseURL, "/"), id)
+	dados, err := gerar(pedido, cfg)
+	if err != nil {
+		api.Logger.Error("erro ao gerar ESC/POS do pedido", zap.String("pedido_id", id.String()), zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	escreverEscPos(w, fmt.Sprintf("pedido-%s.bin", pedido.Codigo), dados)
+}
 
-	api.Logger.Info("URL: " + url)
+// GET /api/v1/caixas/{id}/escpos
+// Fechamento do caixa (ou resumo, se ainda aberto) em ESC/POS.
+func (api *Api) handleCaixas_EscPos(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
 
-	// 3. Cria a requisição GET usando o mesmo contexto (para timeouts, cancelamentos, etc)
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+	cfg, err := escPosConfig(r)
 	if err != nil {
-		http.Error(w, "erro ao criar requisição externa: "+err.Error(), http.StatusInternalServerError)
+		api.jsonError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// 4. Executa a chamada para a API .NET
-	resp, err := http.DefaultClient.Do(req)
+	caixa, err := api.RelatorioService.CarregarCaixa(r.Context(), tenantID, id)
 	if err != nil {
-		http.Error(w, "erro ao chamar API de relatórios: "+err.Error(), http.StatusBadGateway)
+		if errors.Is(err, services.ErrRelatorioCaixaNaoEncontrado) {
+			api.jsonError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		api.Logger.Error("erro ao carregar caixa para impressão", zap.String("caixa_id", id.String()), zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	defer resp.Body.Close()
 
-	// 5. Se a API .NET retornar erro (ex: 404), repassa o status e o body
-	if resp.StatusCode != http.StatusOK {
-		w.WriteHeader(resp.StatusCode)
-		io.Copy(w, resp.Body)
+	dados, err := report.EscPosCaixa(caixa, cfg)
+	if err != nil {
+		api.Logger.Error("erro ao gerar ESC/POS do caixa", zap.String("caixa_id", id.String()), zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
 		return
 	}
+	escreverEscPos(w, fmt.Sprintf("caixa-%d.bin", caixa.Numero), dados)
+}
+
+// escPosConfig lê a configuração da impressora da query string:
+//
+//	codepage=cp850|cp860   (padrão cp850)
+//	colunas=N              (padrão 48, bobina de 80mm; 32 para 58mm)
+//	corte=true|false       (padrão true)
+//	gaveta=true|false      (padrão false)
+func escPosConfig(r *http.Request) (report.EscPos, error) {
+	q := r.URL.Query()
+	cfg := report.EscPosPadrao()
+
+	cp, ok := report.CodePagePorNome(q.Get("codepage"))
+	if !ok {
+		return cfg, errors.New("codepage inválido: use cp850 ou cp860")
+	}
+	cfg.CodePage = cp
 
-	// 6. Copia os headers de conteúdo (Content-Type, Content-Disposition, etc)
-	for key, vals := range resp.Header {
-		for _, v := range vals {
-			w.Header().Add(key, v)
+	if v := q.Get("colunas"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 24 || n > 80 {
+			return cfg, errors.New("colunas deve estar entre 24 e 80")
 		}
+		cfg.Colunas = n
 	}
+	if v := q.Get("corte"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("corte deve ser true ou false")
+		}
+		cfg.Cortar = b
+	}
+	if v := q.Get("gaveta"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, errors.New("gaveta deve ser true ou false")
+		}
+		cfg.AbrirGaveta = b
+	}
+	return cfg, nil
+}
 
-	// 7. Escreve o status 200 e copia o PDF para o ResponseWriter
+func escreverEscPos(w http.ResponseWriter, arquivo string, dados []byte) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", arquivo))
+	w.Header().Set("Content-Length", strconv.Itoa(len(dados)))
 	w.WriteHeader(http.StatusOK)
-	_, _ = io.Copy(w, resp.Body)
+	_, _ = w.Write(dados)
 }
@@ -0,0 +1,589 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/rotas-entrega?status=&id_entregador=&limit=&offset=
+func (api *Api) handleRotasEntrega_List(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	var status *string
+	if s := query.Get("status"); s != "" {
+		switch s {
+		case dto.RotaAberta, dto.RotaEmRota, dto.RotaRetornou, dto.RotaCancelada:
+			status = &s
+		default:
+			api.jsonError(w, r, http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+	var idEntregador *uuid.UUID
+	if s := query.Get("id_entregador"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid id_entregador")
+			return
+		}
+		idEntregador = &id
+	}
+
+	limit := int32(50)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = int32(parsedLimit)
+		}
+	}
+
+	offset := int32(0)
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = int32(parsedOffset)
+		}
+	}
+
+	rotas, err := api.RotaEntregaService.List(r.Context(), tenantID, status, idEntregador, limit, offset)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rotas)
+}
+
+// GET /api/v1/rotas-entrega/{id}
+func (api *Api) handleRotasEntrega_Get(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+
+	rota, err := api.RotaEntregaService.Get(r.Context(), ref)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// POST /api/v1/rotas-entrega
+// Atribui um ou mais pedidos Delivery a um entregador.
+func (api *Api) handleRotasEntrega_Post(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.RotaEntregaCreateDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+	data.UserID = api.getUserIDFromContext(r)
+
+	rota, err := api.RotaEntregaService.Create(r.Context(), data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, rota)
+}
+
+// POST /api/v1/rotas-entrega/{id}/pedidos
+func (api *Api) handleRotasEntrega_AdicionarPedidos(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.RotaEntregaPedidosDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	rota, err := api.RotaEntregaService.AdicionarPedidos(r.Context(), ref, data.Pedidos)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// DELETE /api/v1/rotas-entrega/{id}/pedidos/{idPedido}
+func (api *Api) handleRotasEntrega_RemoverPedido(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	idPedido, err := uuid.Parse(chi.URLParam(r, "idPedido"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid idPedido")
+		return
+	}
+
+	rota, err := api.RotaEntregaService.RemoverPedido(r.Context(), ref, idPedido)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// POST /api/v1/rotas-entrega/{id}/saida
+func (api *Api) handleRotasEntrega_Saida(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaSaida(w, r, ref)
+}
+
+// POST /api/v1/rotas-entrega/{id}/retorno
+func (api *Api) handleRotasEntrega_Retorno(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaRetorno(w, r, ref)
+}
+
+// POST /api/v1/rotas-entrega/{id}/pedidos/{idPedido}/entregue
+func (api *Api) handleRotasEntrega_Entregue(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaEntregue(w, r, ref)
+}
+
+// POST /api/v1/rotas-entrega/{id}/pedidos/{idPedido}/nao-entregue
+func (api *Api) handleRotasEntrega_NaoEntregue(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaNaoEntregue(w, r, ref)
+}
+
+// POST /api/v1/rotas-entrega/{id}/cancelar
+func (api *Api) handleRotasEntrega_Cancelar(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+
+	rota, err := api.RotaEntregaService.Cancelar(r.Context(), ref)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// POST /api/v1/rotas-entrega/{id}/acerto
+// Lança no caixa aberto o dinheiro recebido e a sangria das taxas.
+func (api *Api) handleRotasEntrega_Acerto(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.RotaEntregaAcertoDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	rota, err := api.RotaEntregaService.Acerto(r.Context(), ref, data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	api.Logger.Info("rota de entrega acertada",
+		zap.String("rota_id", ref.ID.String()),
+		zap.String("user_id", ref.UserID.String()))
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// POST /api/v1/rotas-entrega/planejar
+// Sugere rotas e a ordem das paradas; nada é gravado.
+func (api *Api) handleRotasEntrega_Planejar(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var data dto.PlanejamentoEntregaDTO
+	if r.ContentLength != 0 {
+		var (
+			problems map[string]string
+			err      error
+		)
+		data, problems, err = jsonutils.DecodeValidJsonV10[dto.PlanejamentoEntregaDTO](r)
+		if err != nil {
+			if problems != nil {
+				jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			} else {
+				api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+			}
+			return
+		}
+	}
+
+	plano, err := api.RotaEntregaService.Planejar(r.Context(), tenantID, data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, plano)
+}
+
+// POST /api/v1/rotas-entrega/{id}/otimizar
+// Reordena as paradas de uma rota que ainda não saiu.
+func (api *Api) handleRotasEntrega_Otimizar(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+
+	var data dto.ParametrosRotaDTO
+	if r.ContentLength != 0 {
+		var (
+			problems map[string]string
+			err      error
+		)
+		data, problems, err = jsonutils.DecodeValidJsonV10[dto.ParametrosRotaDTO](r)
+		if err != nil {
+			if problems != nil {
+				jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			} else {
+				api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+			}
+			return
+		}
+	}
+
+	otimizada, err := api.RotaEntregaService.Otimizar(r.Context(), ref, data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, otimizada)
+}
+
+// PUT /api/v1/rotas-entrega/{id}/ordem
+func (api *Api) handleRotasEntrega_Reordenar(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRef(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaReordenar(w, r, ref)
+}
+
+/* ---------- app do entregador (JWT) ---------- */
+
+// GET /api/v1/mobile/entregas
+// Rotas abertas e em andamento do entregador logado.
+func (api *Api) handleMobileEntregas_List(w http.ResponseWriter, r *http.Request) {
+	entregador, ok := api.entregadorMobile(w, r)
+	if !ok {
+		return
+	}
+
+	rotas, err := api.RotaEntregaService.ListEntregador(r.Context(), entregador.ID)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rotas)
+}
+
+// GET /api/v1/mobile/entregas/{id}
+func (api *Api) handleMobileEntregas_Get(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+
+	rota, err := api.RotaEntregaService.Get(r.Context(), ref)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+// POST /api/v1/mobile/entregas/{id}/saida
+func (api *Api) handleMobileEntregas_Saida(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaSaida(w, r, ref)
+}
+
+// POST /api/v1/mobile/entregas/{id}/retorno
+func (api *Api) handleMobileEntregas_Retorno(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaRetorno(w, r, ref)
+}
+
+// POST /api/v1/mobile/entregas/{id}/pedidos/{idPedido}/entregue
+func (api *Api) handleMobileEntregas_Entregue(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaEntregue(w, r, ref)
+}
+
+// POST /api/v1/mobile/entregas/{id}/pedidos/{idPedido}/nao-entregue
+func (api *Api) handleMobileEntregas_NaoEntregue(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaNaoEntregue(w, r, ref)
+}
+
+// PUT /api/v1/mobile/entregas/{id}/ordem
+func (api *Api) handleMobileEntregas_Reordenar(w http.ResponseWriter, r *http.Request) {
+	ref, ok := api.rotaEntregaRefMobile(w, r)
+	if !ok {
+		return
+	}
+	api.rotaEntregaReordenar(w, r, ref)
+}
+
+/* ---------- ações comuns ao painel e ao app ---------- */
+
+func (api *Api) rotaEntregaReordenar(w http.ResponseWriter, r *http.Request, ref dto.RotaEntregaRef) {
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.RotaEntregaPedidosDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	rota, err := api.RotaEntregaService.Reordenar(r.Context(), ref, data.Pedidos)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+func (api *Api) rotaEntregaSaida(w http.ResponseWriter, r *http.Request, ref dto.RotaEntregaRef) {
+	rota, err := api.RotaEntregaService.Saida(r.Context(), ref)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+func (api *Api) rotaEntregaRetorno(w http.ResponseWriter, r *http.Request, ref dto.RotaEntregaRef) {
+	rota, err := api.RotaEntregaService.Retorno(r.Context(), ref)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+func (api *Api) rotaEntregaEntregue(w http.ResponseWriter, r *http.Request, ref dto.RotaEntregaRef) {
+	idPedido, err := uuid.Parse(chi.URLParam(r, "idPedido"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid idPedido")
+		return
+	}
+
+	var data dto.EntregaRealizadaDTO
+	if r.ContentLength != 0 {
+		var problems map[string]string
+		data, problems, err = jsonutils.DecodeValidJsonV10[dto.EntregaRealizadaDTO](r)
+		if err != nil {
+			if problems != nil {
+				jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			} else {
+				api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+			}
+			return
+		}
+	}
+
+	rota, err := api.RotaEntregaService.Entregue(r.Context(), ref, idPedido, data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+func (api *Api) rotaEntregaNaoEntregue(w http.ResponseWriter, r *http.Request, ref dto.RotaEntregaRef) {
+	idPedido, err := uuid.Parse(chi.URLParam(r, "idPedido"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid idPedido")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.EntregaNaoRealizadaDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	rota, err := api.RotaEntregaService.NaoEntregue(r.Context(), ref, idPedido, data)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, rota)
+}
+
+func (api *Api) rotaEntregaRef(w http.ResponseWriter, r *http.Request) (dto.RotaEntregaRef, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return dto.RotaEntregaRef{}, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return dto.RotaEntregaRef{}, false
+	}
+
+	return dto.RotaEntregaRef{TenantID: tenantID, ID: id, UserID: api.getUserIDFromContext(r)}, true
+}
+
+// rotaEntregaRefMobile limita a rota ao entregador vinculado ao usuário do token
+func (api *Api) rotaEntregaRefMobile(w http.ResponseWriter, r *http.Request) (dto.RotaEntregaRef, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return dto.RotaEntregaRef{}, false
+	}
+
+	entregador, ok := api.entregadorMobile(w, r)
+	if !ok {
+		return dto.RotaEntregaRef{}, false
+	}
+
+	return dto.RotaEntregaRef{
+		TenantID:     entregador.TenantID,
+		ID:           id,
+		UserID:       entregador.IDUsuario.Bytes,
+		IDEntregador: entregador.ID,
+	}, true
+}
+
+func (api *Api) entregadorMobile(w http.ResponseWriter, r *http.Request) (pgstore.Entregadore, bool) {
+	userIDStr, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userIDStr == "" {
+		api.jsonError(w, r, http.StatusUnauthorized, "não autenticado")
+		return pgstore.Entregadore{}, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "não autenticado")
+		return pgstore.Entregadore{}, false
+	}
+
+	entregador, err := api.RotaEntregaService.EntregadorDoUsuario(r.Context(), userID)
+	if err != nil {
+		api.rotaEntregaError(w, r, err)
+		return pgstore.Entregadore{}, false
+	}
+	if !entregador.Ativo {
+		api.jsonError(w, r, http.StatusForbidden, "entregador inativo")
+		return pgstore.Entregadore{}, false
+	}
+	return entregador, true
+}
+
+func (api *Api) rotaEntregaError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		invalida *services.RotaEntregaInvalidaError
+		status   *services.RotaEntregaStatusError
+		pgErr    *pgconn.PgError
+	)
+	switch {
+	case errors.Is(err, services.ErrRotaEntregaNaoEncontrada),
+		errors.Is(err, services.ErrPedidoForaDaRota),
+		errors.Is(err, services.ErrEntregadorNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUsuarioNaoEntregador):
+		api.jsonError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrRotaSemPedidos), errors.Is(err, services.ErrOrigemNaoConfigurada),
+		errors.As(err, &invalida):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrRotaSemCaixaAberto), errors.As(err, &status):
+		api.jsonError(w, r, http.StatusConflict, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "P0001":
+		// regra de negócio do banco (pagamento excede o pedido, caixa fechado...)
+		api.jsonError(w, r, http.StatusConflict, pgErr.Message)
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		api.jsonError(w, r, http.StatusConflict, "pedido já está em outra rota")
+	default:
+		api.pedidoStatusError(w, r, err)
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/go-chi/cors"
 )
 
+var corsAllowedOrigins = []string{"https://psielt.com.br", "http://localhost:3006"}
+
 func (api *Api) BindRoutes() {
 	corsMiddleware := cors.New(cors.Options{
-		AllowedOrigins:   []string{"https://psielt.com.br", "http://localhost:3006"},
+		AllowedOrigins:   corsAllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Last-Event-ID", httpmiddleware.IdempotencyKeyHeader},
+		ExposedHeaders:   []string{"Link", httpmiddleware.IdempotentReplayedHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
@@ -24,6 +26,9 @@ func (api *Api) BindRoutes() {
 		middleware.Recoverer,
 		api.Sessions.LoadAndSave)
 
+	// Reenvios do PDV com o mesmo Idempotency-Key repetem a primeira resposta
+	idempotency := httpmiddleware.Idempotency(&api.IdempotencyService, api.getTenantIDFromContext, api.Logger)
+
 	api.Router.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Route("/users", func(r chi.Router) {
@@ -66,6 +71,7 @@ func (api *Api) BindRoutes() {
 					r.Delete("/sangria/{id}", api.handleCaixas_RemoveSangria)
 					r.Delete("/suprimento/{id}", api.handleCaixas_RemoveSuprimento)
 					r.Get("/resumo/{id}", api.handleCaixas_Resumo)
+					r.Get("/{id}/escpos", api.handleCaixas_EscPos)
 					r.Post("/inserir-valores-informados", api.handleCaixas_InserirValoresInformados)
 					r.Post("/fechar-caixa", api.handleCaixas_FecharCaixa)
 				})
@@ -106,13 +112,18 @@ func (api *Api) BindRoutes() {
 					r.Put("/{id}/precos/{precoId}", api.handleProdutoPrecos_Put)
 					r.Delete("/{id}/precos/{precoId}", api.handleProdutoPrecos_Delete)
 					r.Put("/{id}/precos/{precoId}/disponibilidade", api.handleProdutoPrecos_PutDisponibilidade)
+
+					// Combos: slots de escolha e acréscimos
+					r.Get("/{id}/combo", api.handleProdutos_GetCombo)
+					r.Put("/{id}/combo", api.handleProdutos_PutCombo)
+					r.Delete("/{id}/combo", api.handleProdutos_DeleteCombo)
 				})
 			})
 
 			r.Route("/pedido-pagamentos", func(r chi.Router) {
 				r.Get("/", api.handlePedidoPagamentos_List)
 				r.Post("/", api.handlePedidoPagamentos_Post)
-				r.Post("/bulk", api.handlePedidoPagamentos_BulkPost) // novo
+				r.With(idempotency).Post("/bulk", api.handlePedidoPagamentos_BulkPost) // novo
 				r.Delete("/{id}", api.handlePedidoPagamentos_Delete)
 			})
 
@@ -121,6 +132,14 @@ func (api *Api) BindRoutes() {
 				r.Post("/", api.handleContasReceber_Post)
 				r.Post("/bulk", api.handleContasReceber_BulkPost) // novo
 				r.Delete("/{id}", api.handleContasReceber_Delete)
+
+				// Crédito (fiado): política do tenant e aging
+				r.Get("/aging", api.handleContasReceber_Aging)
+				r.Get("/politica-credito", api.handleCredito_GetConfig)
+				r.Put("/politica-credito", api.handleCredito_PutConfig)
+
+				// Saldo atualizado com multa e juros na data
+				r.Get("/{id}/saldo", api.handleContasReceber_Saldo)
 			})
 
 			r.Route("/pedidos", func(r chi.Router) {
@@ -128,25 +147,38 @@ func (api *Api) BindRoutes() {
 					r.Use(api.AuthMiddleware)
 
 					// CRUD básico
-					r.Get("/", api.handlePedidos_List)          // GET /api/v1/pedidos - lista paginada com filtros
-					r.Get("/count", api.handlePedidos_Count)    // GET /api/v1/pedidos/count - contagem total com filtros
-					r.Get("/{id}", api.handlePedidos_Get)       // GET /api/v1/pedidos/{id}
-					r.Post("/", api.handlePedidos_Post)         // POST /api/v1/pedidos
-					r.Put("/{id}", api.handlePedidos_Put)       // PUT /api/v1/pedidos/{id}
-					r.Delete("/{id}", api.handlePedidos_Delete) // DELETE /api/v1/pedidos/{id}
+					r.Get("/", api.handlePedidos_List)                    // GET /api/v1/pedidos - lista paginada com filtros
+					r.Get("/count", api.handlePedidos_Count)              // GET /api/v1/pedidos/count - contagem total com filtros
+					r.Get("/{id}", api.handlePedidos_Get)                 // GET /api/v1/pedidos/{id}
+					r.With(idempotency).Post("/", api.handlePedidos_Post) // POST /api/v1/pedidos - aceita Idempotency-Key
+					r.Post("/quote", api.handlePedidos_Quote)             // POST /api/v1/pedidos/quote - cotação sem gravar
+					r.Put("/{id}", api.handlePedidos_Put)                 // PUT /api/v1/pedidos/{id}
+					r.Delete("/{id}", api.handlePedidos_Delete)           // DELETE /api/v1/pedidos/{id}
 
 					// NOVA ROTA PARA DADOS DE EDIÇÃO
 					r.Get("/{id}/dados-edicao", api.handlePedidos_GetDadosEdicao) // GET /api/v1/pedidos/{id}/dados-edicao
 
 					// Operações específicas
-					r.Put("/{id}/status", api.handlePedidos_PutStatus)              // PUT /api/v1/pedidos/{id}/status
-					r.Put("/{id}/pedido-pronto", api.handlePedidos_PutPedidoPronto) // PUT /api/v1/pedidos/{id}/pedido-pronto
+					r.Put("/{id}/status", api.handlePedidos_PutStatus)                              // PUT /api/v1/pedidos/{id}/status
+					r.Put("/{id}/pedido-pronto", api.handlePedidos_PutPedidoPronto)                 // PUT /api/v1/pedidos/{id}/pedido-pronto
+					r.Get("/{id}/historico", api.handlePedidos_GetHistorico)                        // GET /api/v1/pedidos/{id}/historico - transições de status
+					r.Post("/{id}/cancelar", api.handlePedidos_Cancelar)                            // POST /api/v1/pedidos/{id}/cancelar - estorna pagamentos e parcelas
+					r.Post("/{id}/liberar", api.handlePedidos_Liberar)                              // POST /api/v1/pedidos/{id}/liberar - agendado para a cozinha
+					r.Get("/{id}/pix", api.handlePedidos_Pix)                                       // GET /api/v1/pedidos/{id}/pix?valor=&formato=png|json - BR Code do pedido
+					r.With(idempotency).Post("/{id}/pix/confirmar", api.handlePedidos_PixConfirmar) // POST /api/v1/pedidos/{id}/pix/confirmar - aceita Idempotency-Key
+					r.Post("/{id}/parcelamento/preview", api.handlePedidos_ParcelamentoPreview)     // POST /api/v1/pedidos/{id}/parcelamento/preview - simula sem gravar
+					r.With(idempotency).Post("/{id}/parcelamento", api.handlePedidos_Parcelamento)  // POST /api/v1/pedidos/{id}/parcelamento - entrada e parcelas
 
 					// Busca por código
 					r.Get("/codigo/{codigo}", api.handlePedidos_GetByCodigoPedido) // GET /api/v1/pedidos/codigo/{codigo}
 
 					// Rota para relatórios
 					r.Get("/relatorio/{id}", api.handleGetReportPedido) // GET /api/v1/pedidos/relatorio/{id}
+					r.Get("/{id}/escpos", api.handlePedidos_EscPos)     // GET /api/v1/pedidos/{id}/escpos?layout=cozinha|cliente - impressora térmica
+
+					// Feed em tempo real
+					r.Get("/stream", api.handlePedidos_Stream) // GET /api/v1/pedidos/stream - Server-Sent Events
+					r.Get("/ws", api.handlePedidos_WebSocket)  // GET /api/v1/pedidos/ws - WebSocket
 				})
 			})
 
@@ -170,6 +202,139 @@ func (api *Api) BindRoutes() {
 					r.Get("/cnpj/{cnpj}", api.handleGetClienteByCNPJ) // GET /api/v1/clientes/cnpj/{cnpj}
 
 					r.Post("/upsert", api.handleUpsertCliente) // POST /api/v1/clientes/upsert
+
+					// Crédito (fiado)
+					r.Get("/{id}/credito", api.handleClientes_GetCredito) // GET /api/v1/clientes/{id}/credito - extrato do fiado
+					r.Put("/{id}/credito", api.handleClientes_PutCredito) // PUT /api/v1/clientes/{id}/credito - limite e bloqueio
+				})
+			})
+
+			r.Route("/cupons", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleCupons_List)                      // GET /api/v1/cupons
+					r.Post("/", api.handleCupons_Post)                     // POST /api/v1/cupons
+					r.Get("/{id}", api.handleCupons_Get)                   // GET /api/v1/cupons/{id}
+					r.Put("/{id}", api.handleCupons_Put)                   // PUT /api/v1/cupons/{id}
+					r.Delete("/{id}", api.handleCupons_Delete)             // DELETE /api/v1/cupons/{id}
+					r.Get("/{id}/resgates", api.handleCupons_ListResgates) // GET /api/v1/cupons/{id}/resgates?limit=&offset=
+				})
+			})
+
+			r.Route("/zonas-entrega", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleZonasEntrega_List)              // GET /api/v1/zonas-entrega
+					r.Post("/", api.handleZonasEntrega_Post)             // POST /api/v1/zonas-entrega
+					r.Get("/origem", api.handleZonasEntrega_GetOrigem)   // GET /api/v1/zonas-entrega/origem
+					r.Put("/origem", api.handleZonasEntrega_PutOrigem)   // PUT /api/v1/zonas-entrega/origem
+					r.Post("/resolver", api.handleZonasEntrega_Resolver) // POST /api/v1/zonas-entrega/resolver
+					r.Get("/{id}", api.handleZonasEntrega_Get)           // GET /api/v1/zonas-entrega/{id}
+					r.Put("/{id}", api.handleZonasEntrega_Put)           // PUT /api/v1/zonas-entrega/{id}
+					r.Delete("/{id}", api.handleZonasEntrega_Delete)     // DELETE /api/v1/zonas-entrega/{id}
+				})
+			})
+
+			r.Route("/entregadores", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleEntregadores_List)               // GET /api/v1/entregadores
+					r.Post("/", api.handleEntregadores_Post)              // POST /api/v1/entregadores
+					r.Get("/relatorio", api.handleEntregadores_Relatorio) // GET /api/v1/entregadores/relatorio
+					r.Get("/{id}", api.handleEntregadores_Get)            // GET /api/v1/entregadores/{id}
+					r.Put("/{id}", api.handleEntregadores_Put)            // PUT /api/v1/entregadores/{id}
+					r.Delete("/{id}", api.handleEntregadores_Delete)      // DELETE /api/v1/entregadores/{id}
+				})
+			})
+
+			r.Route("/rotas-entrega", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleRotasEntrega_List)                                             // GET /api/v1/rotas-entrega
+					r.Post("/", api.handleRotasEntrega_Post)                                            // POST /api/v1/rotas-entrega
+					r.Post("/planejar", api.handleRotasEntrega_Planejar)                                // POST /api/v1/rotas-entrega/planejar
+					r.Get("/{id}", api.handleRotasEntrega_Get)                                          // GET /api/v1/rotas-entrega/{id}
+					r.Post("/{id}/pedidos", api.handleRotasEntrega_AdicionarPedidos)                    // POST /api/v1/rotas-entrega/{id}/pedidos
+					r.Delete("/{id}/pedidos/{idPedido}", api.handleRotasEntrega_RemoverPedido)          // DELETE /api/v1/rotas-entrega/{id}/pedidos/{idPedido}
+					r.Post("/{id}/otimizar", api.handleRotasEntrega_Otimizar)                           // POST /api/v1/rotas-entrega/{id}/otimizar
+					r.Put("/{id}/ordem", api.handleRotasEntrega_Reordenar)                              // PUT /api/v1/rotas-entrega/{id}/ordem
+					r.Post("/{id}/saida", api.handleRotasEntrega_Saida)                                 // POST /api/v1/rotas-entrega/{id}/saida
+					r.Post("/{id}/pedidos/{idPedido}/entregue", api.handleRotasEntrega_Entregue)        // POST /api/v1/rotas-entrega/{id}/pedidos/{idPedido}/entregue
+					r.Post("/{id}/pedidos/{idPedido}/nao-entregue", api.handleRotasEntrega_NaoEntregue) // POST /api/v1/rotas-entrega/{id}/pedidos/{idPedido}/nao-entregue
+					r.Post("/{id}/retorno", api.handleRotasEntrega_Retorno)                             // POST /api/v1/rotas-entrega/{id}/retorno
+					r.Post("/{id}/cancelar", api.handleRotasEntrega_Cancelar)                           // POST /api/v1/rotas-entrega/{id}/cancelar
+					r.Post("/{id}/acerto", api.handleRotasEntrega_Acerto)                               // POST /api/v1/rotas-entrega/{id}/acerto
+				})
+			})
+
+			r.Route("/agendamentos", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleAgendamentos_Agenda)          // GET /api/v1/agendamentos?data=YYYY-MM-DD
+					r.Get("/config", api.handleAgendamentos_GetConfig) // GET /api/v1/agendamentos/config
+					r.Put("/config", api.handleAgendamentos_PutConfig) // PUT /api/v1/agendamentos/config
+				})
+			})
+
+			r.Route("/pix", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/config", api.handlePix_GetConfig)      // GET /api/v1/pix/config
+					r.Put("/config", api.handlePix_PutConfig)      // PUT /api/v1/pix/config
+					r.Get("/qrcode", api.handlePix_QRCodeEstatico) // GET /api/v1/pix/qrcode?formato=png|json
+				})
+			})
+
+			r.Route("/taxas-adquirente", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleTaxasAdquirente_Get)           // GET /api/v1/taxas-adquirente
+					r.Put("/", api.handleTaxasAdquirente_Put)           // PUT /api/v1/taxas-adquirente
+					r.Delete("/{id}", api.handleTaxasAdquirente_Delete) // DELETE /api/v1/taxas-adquirente/{id}
+				})
+			})
+
+			r.Route("/recebiveis", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/calendario", api.handleRecebiveis_Calendario) // GET /api/v1/recebiveis/calendario?inicio=YYYY-MM-DD&fim=YYYY-MM-DD
+				})
+			})
+
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/", api.handleWebhooks_List)                                             // GET /api/v1/webhooks
+					r.Post("/", api.handleWebhooks_Post)                                            // POST /api/v1/webhooks
+					r.Get("/{id}", api.handleWebhooks_Get)                                          // GET /api/v1/webhooks/{id}
+					r.Put("/{id}", api.handleWebhooks_Put)                                          // PUT /api/v1/webhooks/{id}
+					r.Delete("/{id}", api.handleWebhooks_Delete)                                    // DELETE /api/v1/webhooks/{id}
+					r.Post("/{id}/rotate-secret", api.handleWebhooks_RotateSecret)                  // POST /api/v1/webhooks/{id}/rotate-secret
+					r.Get("/{id}/deliveries", api.handleWebhooks_ListDeliveries)                    // GET /api/v1/webhooks/{id}/deliveries
+					r.Post("/{id}/deliveries/{deliveryId}/redeliver", api.handleWebhooks_Redeliver) // POST /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver
+				})
+			})
+
+			r.Route("/kds", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/estacoes", api.handleKds_ListEstacoes)                       // GET /api/v1/kds/estacoes
+					r.Post("/estacoes", api.handleKds_PostEstacao)                       // POST /api/v1/kds/estacoes
+					r.Get("/estacoes/{id}", api.handleKds_GetEstacao)                    // GET /api/v1/kds/estacoes/{id}
+					r.Put("/estacoes/{id}", api.handleKds_PutEstacao)                    // PUT /api/v1/kds/estacoes/{id}
+					r.Delete("/estacoes/{id}", api.handleKds_DeleteEstacao)              // DELETE /api/v1/kds/estacoes/{id}
+					r.Get("/estacoes/{id}/fila", api.handleKds_FilaEstacao)              // GET /api/v1/kds/estacoes/{id}/fila?incluir_prontos=true
+					r.Get("/fila", api.handleKds_Fila)                                   // GET /api/v1/kds/fila?incluir_prontos=true - todas as estações
+					r.Put("/categorias/{id}/estacao", api.handleKds_PutCategoriaEstacao) // PUT /api/v1/kds/categorias/{id}/estacao
+					r.Put("/produtos/{id}/estacao", api.handleKds_PutProdutoEstacao)     // PUT /api/v1/kds/produtos/{id}/estacao
+					r.Put("/itens/{id}/status", api.handleKds_PutItemStatus)             // PUT /api/v1/kds/itens/{id}/status
+				})
+			})
+
+			r.Route("/cardapio", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Get("/disponivel", api.handleCardapio_Disponivel) // GET /api/v1/cardapio/disponivel?data=RFC3339&incluir_indisponiveis=true
 				})
 			})
 
@@ -200,6 +365,15 @@ func (api *Api) BindRoutes() {
 		r.Group(func(r chi.Router) {
 			r.Use(api.JWTAuthMiddleware)
 			r.Get("/me", api.handleGetCurrentUserToken)
+
+			// App do entregador
+			r.Get("/entregas", api.handleMobileEntregas_List)                                              // GET /api/v1/mobile/entregas
+			r.Get("/entregas/{id}", api.handleMobileEntregas_Get)                                          // GET /api/v1/mobile/entregas/{id}
+			r.Put("/entregas/{id}/ordem", api.handleMobileEntregas_Reordenar)                              // PUT /api/v1/mobile/entregas/{id}/ordem
+			r.Post("/entregas/{id}/saida", api.handleMobileEntregas_Saida)                                 // POST /api/v1/mobile/entregas/{id}/saida
+			r.Post("/entregas/{id}/pedidos/{idPedido}/entregue", api.handleMobileEntregas_Entregue)        // POST /api/v1/mobile/entregas/{id}/pedidos/{idPedido}/entregue
+			r.Post("/entregas/{id}/pedidos/{idPedido}/nao-entregue", api.handleMobileEntregas_NaoEntregue) // POST /api/v1/mobile/entregas/{id}/pedidos/{idPedido}/nao-entregue
+			r.Post("/entregas/{id}/retorno", api.handleMobileEntregas_Retorno)                             // POST /api/v1/mobile/entregas/{id}/retorno
 		})
 
 	})
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/taxas-adquirente
+func (api *Api) handleTaxasAdquirente_Get(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	taxas, err := api.TaxaAdquirenteService.Listar(r.Context(), tenantID)
+	if err != nil {
+		api.taxaAdquirenteError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, taxas)
+}
+
+// PUT /api/v1/taxas-adquirente
+// MDR, tarifa fixa e prazo de repasse da forma de pagamento (e parcelas,
+// no crédito); só gerente (admin) altera.
+func (api *Api) handleTaxasAdquirente_Put(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if api.getUserFromContext(r).Admin != 1 {
+		api.jsonError(w, r, http.StatusForbidden, "alterar taxas da adquirente exige um gerente")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.TaxaAdquirenteDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	taxa, err := api.TaxaAdquirenteService.Salvar(r.Context(), tenantID, data)
+	if err != nil {
+		api.taxaAdquirenteError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, taxa)
+}
+
+// DELETE /api/v1/taxas-adquirente/{id}
+func (api *Api) handleTaxasAdquirente_Delete(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if api.getUserFromContext(r).Admin != 1 {
+		api.jsonError(w, r, http.StatusForbidden, "alterar taxas da adquirente exige um gerente")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id format")
+		return
+	}
+
+	if err := api.TaxaAdquirenteService.Remover(r.Context(), tenantID, id); err != nil {
+		api.taxaAdquirenteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/recebiveis/calendario?inicio=YYYY-MM-DD&fim=YYYY-MM-DD
+// Depósitos previstos da adquirente por dia: bruto, taxa e líquido.
+func (api *Api) handleRecebiveis_Calendario(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	inicio, ok := api.dataDoParametro(w, r, "inicio")
+	if !ok {
+		return
+	}
+	fim, ok := api.dataDoParametro(w, r, "fim")
+	if !ok {
+		return
+	}
+
+	cal, err := api.TaxaAdquirenteService.Calendario(r.Context(), tenantID, inicio, fim)
+	if err != nil {
+		api.taxaAdquirenteError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, cal)
+}
+
+func (api *Api) taxaAdquirenteError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalida *services.TaxaAdquirenteInvalidaError
+	switch {
+	case errors.As(err, &invalida):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrTaxaAdquirenteNaoEncontrada),
+		errors.Is(err, services.ErrTenantNaoEncontrado):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	default:
+		api.Logger.Error("erro nas taxas da adquirente", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
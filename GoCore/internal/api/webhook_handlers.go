@@ -0,0 +1,214 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func (api *Api) handleWebhooks_List(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	subs, err := api.WebhookService.ListSubscriptions(r.Context(), tenantID)
+	if err != nil {
+		api.Logger.Error("erro ao listar webhooks", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, subs)
+}
+
+func (api *Api) handleWebhooks_Get(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := api.WebhookService.GetSubscription(r.Context(), id, tenantID)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, sub)
+}
+
+func (api *Api) handleWebhooks_Post(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.CreateWebhookSubscriptionDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	// O segredo só é devolvido aqui (e na rotação)
+	sub, err := api.WebhookService.CreateSubscription(r.Context(), data)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, sub)
+}
+
+func (api *Api) handleWebhooks_Put(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.UpdateWebhookSubscriptionDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.ID = id
+	data.TenantID = tenantID
+
+	sub, err := api.WebhookService.UpdateSubscription(r.Context(), data)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, sub)
+}
+
+func (api *Api) handleWebhooks_Delete(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.WebhookService.DeleteSubscription(r.Context(), id, tenantID); err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *Api) handleWebhooks_RotateSecret(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := api.WebhookService.RotateSecret(r.Context(), id, tenantID)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, sub)
+}
+
+func (api *Api) handleWebhooks_ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := int32(50)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = int32(parsedLimit)
+		}
+	}
+
+	offset := int32(0)
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = int32(parsedOffset)
+		}
+	}
+
+	deliveries, err := api.WebhookService.ListDeliveries(r.Context(), id, tenantID, limit, offset)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, deliveries)
+}
+
+func (api *Api) handleWebhooks_Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.webhookIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryId"), 10, 64)
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid delivery id")
+		return
+	}
+
+	// A entrega é síncrona: o resultado (sucesso ou falha HTTP) vem no corpo
+	delivery, err := api.WebhookService.Redeliver(r.Context(), id, deliveryID, tenantID)
+	if err != nil {
+		api.webhookError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, delivery)
+}
+
+func (api *Api) webhookIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid webhook id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) webhookError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNaoEncontrado),
+		errors.Is(err, services.ErrWebhookEntregaNaoEncontrada):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrWebhookURLInvalida),
+		errors.Is(err, services.ErrWebhookDestinoBloqueado),
+		errors.Is(err, services.ErrWebhookEventoDesconhecido):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		api.Logger.Error("erro no webhook", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
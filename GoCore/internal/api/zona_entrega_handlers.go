@@ -0,0 +1,221 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/zonas-entrega
+func (api *Api) handleZonasEntrega_List(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	zonas, err := api.ZonaEntregaService.List(r.Context(), tenantID)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, zonas)
+}
+
+// GET /api/v1/zonas-entrega/{id}
+func (api *Api) handleZonasEntrega_Get(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.zonaEntregaIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	zona, err := api.ZonaEntregaService.Get(r.Context(), tenantID, id)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, zona)
+}
+
+// POST /api/v1/zonas-entrega
+func (api *Api) handleZonasEntrega_Post(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.ZonaEntregaDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.TenantID = tenantID
+
+	zona, err := api.ZonaEntregaService.Create(r.Context(), data)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, zona)
+}
+
+// PUT /api/v1/zonas-entrega/{id}
+func (api *Api) handleZonasEntrega_Put(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.zonaEntregaIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.ZonaEntregaDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+	data.ID = id
+	data.TenantID = tenantID
+
+	zona, err := api.ZonaEntregaService.Update(r.Context(), data)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, zona)
+}
+
+// DELETE /api/v1/zonas-entrega/{id}
+// Pedidos já feitos mantêm a taxa e a referência à zona.
+func (api *Api) handleZonasEntrega_Delete(w http.ResponseWriter, r *http.Request) {
+	id, tenantID, ok := api.zonaEntregaIDAndTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.ZonaEntregaService.Delete(r.Context(), tenantID, id); err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/zonas-entrega/origem
+func (api *Api) handleZonasEntrega_GetOrigem(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	origem, err := api.ZonaEntregaService.GetOrigem(r.Context(), tenantID)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, origem)
+}
+
+// PUT /api/v1/zonas-entrega/origem
+// Coordenadas da loja, usadas pelas zonas por raio.
+func (api *Api) handleZonasEntrega_PutOrigem(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.TenantOrigemDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	origem, err := api.ZonaEntregaService.PutOrigem(r.Context(), tenantID, data)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, origem)
+}
+
+// POST /api/v1/zonas-entrega/resolver
+// Zona, taxa e prazo para o endereço, sem criar pedido.
+func (api *Api) handleZonasEntrega_Resolver(w http.ResponseWriter, r *http.Request) {
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJsonV10[dto.EnderecoEntregaDTO](r)
+	if err != nil {
+		if problems != nil {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		} else {
+			api.jsonError(w, r, http.StatusBadRequest, "invalid body")
+		}
+		return
+	}
+
+	entrega, err := api.ZonaEntregaService.Resolver(r.Context(), tenantID, data)
+	if err != nil {
+		api.zonaEntregaError(w, r, err)
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, entrega)
+}
+
+func (api *Api) zonaEntregaIDAndTenant(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		api.jsonError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID := api.getTenantIDFromContext(r)
+	if tenantID == uuid.Nil {
+		api.jsonError(w, r, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, tenantID, true
+}
+
+func (api *Api) zonaEntregaError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalida *services.ZonaEntregaInvalidaError
+	switch {
+	case errors.Is(err, services.ErrZonaEntregaNaoEncontrada):
+		api.jsonError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrSemZonasEntrega), errors.Is(err, services.ErrForaDaAreaEntrega),
+		errors.Is(err, services.ErrEnderecoIncompleto), errors.As(err, &invalida):
+		api.jsonError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		api.Logger.Error("erro na zona de entrega", zap.Error(err))
+		api.jsonError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -1,6 +1,10 @@
 package decimalutils
 
 import (
+	"math/big"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/volatiletech/sqlboiler/v4/types"
 )
 
@@ -21,3 +25,50 @@ func FromStringToNullDecimal(s string) (types.NullDecimal, error) {
 	}
 	return d, nil
 }
+
+// ToCentavos converte um valor monetário em centavos, arredondando para
+// duas casas (meio para cima, como o ROUND do Postgres). Zero se nulo.
+func ToCentavos(d types.Decimal) int64 {
+	if d.Big == nil {
+		return 0
+	}
+	c := new(decimal.Big).Copy(d.Big)
+	c.Context.RoundingMode = decimal.ToNearestAway
+	c.Mul(c, decimal.New(100, 0)).RoundToInt()
+	v, _ := c.Int64()
+	return v
+}
+
+// FromCentavos converte centavos num types.Decimal com duas casas.
+func FromCentavos(c int64) types.Decimal {
+	return types.NewDecimal(decimal.New(c, 2))
+}
+
+// NumericToCentavos converte um numeric do pgx (ex.: numeric(10,2)) em
+// centavos. Devolve false se o valor for nulo.
+func NumericToCentavos(n pgtype.Numeric) (int64, bool) {
+	if !n.Valid || n.Int == nil {
+		return 0, false
+	}
+	v := new(big.Int).Set(n.Int)
+	exp := n.Exp + 2
+	ten := big.NewInt(10)
+	for ; exp > 0; exp-- {
+		v.Mul(v, ten)
+	}
+	if exp < 0 {
+		// mais de duas casas: arredonda meio para longe do zero
+		div := new(big.Int).Exp(ten, big.NewInt(int64(-exp)), nil)
+		q, r := new(big.Int).QuoRem(v, div, new(big.Int))
+		if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(div) >= 0 {
+			q.Add(q, big.NewInt(int64(v.Sign())))
+		}
+		v = q
+	}
+	return v.Int64(), true
+}
+
+// CentavosToNumeric converte centavos num numeric do pgx com duas casas.
+func CentavosToNumeric(c int64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(c), Exp: -2, Valid: true}
+}
@@ -0,0 +1,190 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Valores usados quando o tenant ainda não configurou o agendamento
+const (
+	AgendamentoIntervaloPadrao    = 30
+	AgendamentoLiberacaoPadrao    = 40
+	AgendamentoAntecedenciaPadrao = 60
+	AgendamentoDiasMaximosPadrao  = 7
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Capacidade de um horário da semana; sobrepõe capacidade_por_faixa
+type AgendamentoFaixaDTO struct {
+	DiaSemana  int16  `json:"dia_semana"  validate:"min=0,max=6"`
+	HoraInicio string `json:"hora_inicio" validate:"required,datetime=15:04"`
+	HoraFim    string `json:"hora_fim"    validate:"required,datetime=15:04"`
+	Capacidade int32  `json:"capacidade"  validate:"min=0,max=1000"`
+}
+
+type AgendamentoConfigDTO struct {
+	TenantID         uuid.UUID `json:"-"`
+	Ativo            bool      `json:"ativo"`
+	IntervaloMinutos int32     `json:"intervalo_minutos"              validate:"min=5,max=240"`
+	// Sem valor, as faixas não têm limite de pedidos
+	CapacidadePorFaixa       *int32                `json:"capacidade_por_faixa,omitempty" validate:"omitempty,min=1,max=1000"`
+	AntecedenciaLiberacaoMin int32                 `json:"antecedencia_liberacao_min"     validate:"min=0,max=720"`
+	AntecedenciaMinimaMin    int32                 `json:"antecedencia_minima_min"        validate:"min=0,max=10080"`
+	DiasMaximos              int32                 `json:"dias_maximos"                   validate:"min=1,max=90"`
+	Faixas                   []AgendamentoFaixaDTO `json:"faixas"                         validate:"omitempty,max=200,dive"`
+}
+
+func (d AgendamentoConfigDTO) ToUpsertParams() pgstore.UpsertAgendamentoConfigParams {
+	p := pgstore.UpsertAgendamentoConfigParams{
+		TenantID:                 d.TenantID,
+		Ativo:                    d.Ativo,
+		IntervaloMinutos:         d.IntervaloMinutos,
+		AntecedenciaLiberacaoMin: d.AntecedenciaLiberacaoMin,
+		AntecedenciaMinimaMin:    d.AntecedenciaMinimaMin,
+		DiasMaximos:              d.DiasMaximos,
+	}
+	if d.CapacidadePorFaixa != nil {
+		p.CapacidadePorFaixa = pgtype.Int4{Int32: *d.CapacidadePorFaixa, Valid: true}
+	}
+	return p
+}
+
+func (f AgendamentoFaixaDTO) ToCreateParams(tenantID uuid.UUID) (pgstore.CreateAgendamentoFaixaParams, error) {
+	inicio, err := horaParaPgTime(f.HoraInicio)
+	if err != nil {
+		return pgstore.CreateAgendamentoFaixaParams{}, err
+	}
+	fim, err := horaParaPgTime(f.HoraFim)
+	if err != nil {
+		return pgstore.CreateAgendamentoFaixaParams{}, err
+	}
+	return pgstore.CreateAgendamentoFaixaParams{
+		TenantID:   tenantID,
+		DiaSemana:  f.DiaSemana,
+		HoraInicio: inicio,
+		HoraFim:    fim,
+		Capacidade: f.Capacidade,
+	}, nil
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type AgendamentoConfigResponse struct {
+	Ativo                    bool                  `json:"ativo"`
+	IntervaloMinutos         int32                 `json:"intervalo_minutos"`
+	CapacidadePorFaixa       *int32                `json:"capacidade_por_faixa"`
+	AntecedenciaLiberacaoMin int32                 `json:"antecedencia_liberacao_min"`
+	AntecedenciaMinimaMin    int32                 `json:"antecedencia_minima_min"`
+	DiasMaximos              int32                 `json:"dias_maximos"`
+	Faixas                   []AgendamentoFaixaDTO `json:"faixas"`
+	UpdatedAt                *time.Time            `json:"updated_at,omitempty"`
+}
+
+type HorarioAgendamentoResponse struct {
+	Inicio time.Time `json:"inicio"`
+	Fim    time.Time `json:"fim"`
+	// Nulo quando a faixa não tem limite
+	Capacidade *int32 `json:"capacidade"`
+	Ocupados   int32  `json:"ocupados"`
+	Disponivel bool   `json:"disponivel"`
+}
+
+type PedidoAgendadoResponse struct {
+	ID           uuid.UUID     `json:"id"`
+	CodigoPedido string        `json:"codigo_pedido"`
+	IDStatus     int16         `json:"id_status"`
+	TipoEntrega  string        `json:"tipo_entrega"`
+	AgendadoPara time.Time     `json:"agendado_para"`
+	LiberadoEm   *time.Time    `json:"liberado_em,omitempty"`
+	ValorPedido  types.Decimal `json:"valor_pedido"`
+	ClienteNome  string        `json:"cliente_nome"`
+}
+
+type AgendaResponse struct {
+	Data     string                       `json:"data"`
+	Horarios []HorarioAgendamentoResponse `json:"horarios"`
+	Pedidos  []PedidoAgendadoResponse     `json:"pedidos"`
+}
+
+// AgendamentoConfigToResponse: sem linha de configuração, devolve os
+// padrões com o agendamento desligado
+func AgendamentoConfigToResponse(c *pgstore.AgendamentoConfig, faixas []pgstore.AgendamentoFaixa) AgendamentoConfigResponse {
+	out := AgendamentoConfigResponse{
+		IntervaloMinutos:         AgendamentoIntervaloPadrao,
+		AntecedenciaLiberacaoMin: AgendamentoLiberacaoPadrao,
+		AntecedenciaMinimaMin:    AgendamentoAntecedenciaPadrao,
+		DiasMaximos:              AgendamentoDiasMaximosPadrao,
+		Faixas:                   make([]AgendamentoFaixaDTO, len(faixas)),
+	}
+	if c != nil {
+		out.Ativo = c.Ativo
+		out.IntervaloMinutos = c.IntervaloMinutos
+		if c.CapacidadePorFaixa.Valid {
+			out.CapacidadePorFaixa = &c.CapacidadePorFaixa.Int32
+		}
+		out.AntecedenciaLiberacaoMin = c.AntecedenciaLiberacaoMin
+		out.AntecedenciaMinimaMin = c.AntecedenciaMinimaMin
+		out.DiasMaximos = c.DiasMaximos
+		out.UpdatedAt = &c.UpdatedAt
+	}
+	for i, f := range faixas {
+		out.Faixas[i] = AgendamentoFaixaDTO{
+			DiaSemana:  f.DiaSemana,
+			HoraInicio: pgTimeParaHora(f.HoraInicio),
+			HoraFim:    pgTimeParaHora(f.HoraFim),
+			Capacidade: f.Capacidade,
+		}
+	}
+	return out
+}
+
+func HorarioAgendamentoToResponse(r pgstore.ListHorariosAgendamentoRow) HorarioAgendamentoResponse {
+	h := HorarioAgendamentoResponse{
+		Inicio:     r.Inicio,
+		Fim:        r.Fim,
+		Ocupados:   r.Ocupados,
+		Disponivel: true,
+	}
+	if r.Capacidade.Valid {
+		h.Capacidade = &r.Capacidade.Int32
+		h.Disponivel = r.Ocupados < r.Capacidade.Int32
+	}
+	return h
+}
+
+func PedidoAgendadoToResponse(r pgstore.ListPedidosAgendadosRow) PedidoAgendadoResponse {
+	return PedidoAgendadoResponse{
+		ID:           r.ID,
+		CodigoPedido: r.CodigoPedido,
+		IDStatus:     r.IDStatus,
+		TipoEntrega:  r.TipoEntrega,
+		AgendadoPara: r.AgendadoPara.Time,
+		LiberadoEm:   timestamptzToPtr(r.LiberadoEm),
+		ValorPedido:  numericToDecimal(r.ValorPedido),
+		ClienteNome:  r.ClienteNome,
+	}
+}
+
+// horaParaPgTime converte "HH:MM" para time do Postgres
+func horaParaPgTime(s string) (pgtype.Time, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return pgtype.Time{}, fmt.Errorf("hora inválida %q", s)
+	}
+	return pgtype.Time{
+		Microseconds: int64(t.Hour()*60+t.Minute()) * 60e6,
+		Valid:        true,
+	}, nil
+}
+
+func pgTimeParaHora(t pgtype.Time) string {
+	minutos := t.Microseconds / 60e6
+	return fmt.Sprintf("%02d:%02d", minutos/60, minutos%60)
+}
@@ -81,6 +81,8 @@ type InserirValoresInformadosParams struct {
 type FecharCaixaParams struct {
 	ID                   uuid.UUID `json:"id"`
 	ObservacaoFechamento string    `json:"observacao_fechamento"`
+	TenantID             uuid.UUID `json:"-"` // preenchido pelo handler
+	UserID               uuid.UUID `json:"-"` // autor do evento caixa.closed
 }
 
 func InserirValoresInformadosParamsToInserirValoresInformadosParams(dto InserirValoresInformadosParams) (pgstore.InserirValoresInformadosParams, error) {
@@ -102,6 +104,7 @@ func FecharCaixaParamsToFecharCaixaParams(dto FecharCaixaParams) (pgstore.Fechar
 	return pgstore.FecharCaixaParams{
 		ID:                   dto.ID,
 		ObservacaoFechamento: pgtype.Text{String: dto.ObservacaoFechamento, Valid: true},
+		TenantID:             dto.TenantID,
 	}, nil
 }
 
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Como o pagamento estornado saiu do caixa
+const (
+	// entrada (P) removida do caixa ainda aberto
+	EstornoCaixaRemovido = "removido"
+	// caixa original fechado: saída (E) lançada no caixa aberto
+	EstornoCaixaLancado = "estorno"
+	// pagamento não tinha entrada em caixa
+	EstornoCaixaSemMovimento = "sem_movimento"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type CancelarPedidoRequest struct {
+	Motivo string `json:"motivo" validate:"required,max=500"`
+	// Credenciais do gerente quando quem cancela não é gerente
+	Autorizacao *AutorizacaoGerenteDTO `json:"autorizacao,omitempty"`
+}
+
+type AutorizacaoGerenteDTO struct {
+	Email string `json:"email" validate:"required,email"`
+	Senha string `json:"senha" validate:"required"`
+}
+
+type CancelarPedidoDTO struct {
+	TenantID      uuid.UUID
+	UserID        uuid.UUID
+	IDPedido      uuid.UUID
+	Motivo        string
+	AutorizadoPor *uuid.UUID
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type PagamentoEstornadoResponse struct {
+	IDPagamento           string        `json:"id_pagamento"`
+	IDContaReceber        *string       `json:"id_conta_receber,omitempty"`
+	FormaPagamento        string        `json:"forma_pagamento"`
+	Valor                 types.Decimal `json:"valor"`
+	Caixa                 string        `json:"caixa"`
+	IDCaixa               *string       `json:"id_caixa,omitempty"`
+	IDMovimentacaoEstorno *string       `json:"id_movimentacao_estorno,omitempty"`
+}
+
+type ParcelaCanceladaResponse struct {
+	ID          string        `json:"id"`
+	Parcela     int16         `json:"parcela"`
+	Vencimento  string        `json:"vencimento"`
+	ValorDevido types.Decimal `json:"valor_devido"`
+	ValorPago   types.Decimal `json:"valor_pago"`
+}
+
+type PedidoCancelamentoResponse struct {
+	ID             string                        `json:"id"`
+	IDPedido       string                        `json:"id_pedido"`
+	CodigoPedido   string                        `json:"codigo_pedido"`
+	StatusAnterior int16                         `json:"status_anterior"`
+	Motivo         string                        `json:"motivo"`
+	UserID         *uuid.UUID                    `json:"user_id"`
+	AutorizadoPor  *uuid.UUID                    `json:"autorizado_por"`
+	ValorEstornado types.Decimal                 `json:"valor_estornado"`
+	Pagamentos     []PagamentoEstornadoResponse  `json:"pagamentos"`
+	Parcelas       []ParcelaCanceladaResponse    `json:"parcelas"`
+	Transicao      PedidoStatusHistoricoResponse `json:"transicao"`
+	CreatedAt      time.Time                     `json:"created_at"`
+}
+
+// pedido.cancelado
+type PedidoCanceladoPayload struct {
+	ID             string        `json:"id"`
+	CodigoPedido   string        `json:"codigo_pedido"`
+	StatusAnterior int16         `json:"status_anterior"`
+	Motivo         string        `json:"motivo"`
+	AutorizadoPor  *string       `json:"autorizado_por,omitempty"`
+	ValorEstornado types.Decimal `json:"valor_estornado"`
+	QtdPagamentos  int           `json:"qtd_pagamentos"`
+	QtdParcelas    int           `json:"qtd_parcelas"`
+}
@@ -16,7 +16,8 @@ type CoreCategoriaCreateDTO struct {
 	Inicio            *string   `json:"inicio" validate:"omitempty,datetime=15:04:05"`
 	Fim               *string   `json:"fim" validate:"omitempty,datetime=15:04:05"`
 	Ativo             *int16    `json:"ativo" validate:"omitempty,oneof=0 1"`
-	OpcaoMeia         *string   `json:"opcao_meia" validate:"omitempty,max=1,oneof=M V ''"`
+	OpcaoMeia         *string   `json:"opcao_meia" validate:"omitempty,max=1,oneof=M V P ''"`
+	MaxSabores        *int16    `json:"max_sabores" validate:"omitempty,min=1,max=8"`
 	Ordem             *int32    `json:"ordem"`
 	DisponivelDomingo *int16    `json:"disponivel_domingo" validate:"omitempty,oneof=0 1"`
 	DisponivelSegunda *int16    `json:"disponivel_segunda" validate:"omitempty,oneof=0 1"`
@@ -41,7 +42,8 @@ type CoreCategoriaUpdateDTO struct {
 	Inicio            *string `json:"inicio" validate:"omitempty,datetime=15:04:05"`
 	Fim               *string `json:"fim" validate:"omitempty,datetime=15:04:05"`
 	Ativo             *int16  `json:"ativo" validate:"omitempty,oneof=0 1"`
-	OpcaoMeia         *string `json:"opcao_meia" validate:"omitempty,max=1,oneof=M V ''"`
+	OpcaoMeia         *string `json:"opcao_meia" validate:"omitempty,max=1,oneof=M V P ''"`
+	MaxSabores        *int16  `json:"max_sabores" validate:"omitempty,min=1,max=8"`
 	Ordem             *int32  `json:"ordem"`
 	DisponivelDomingo *int16  `json:"disponivel_domingo" validate:"omitempty,oneof=0 1"`
 	DisponivelSegunda *int16  `json:"disponivel_segunda" validate:"omitempty,oneof=0 1"`
@@ -82,6 +84,7 @@ type CoreCategoriaResponseDTO struct {
 	Fim               string               `json:"fim"`
 	Ativo             int16                `json:"ativo"`
 	OpcaoMeia         string               `json:"opcao_meia"`
+	MaxSabores        int16                `json:"max_sabores"`
 	Ordem             *int32               `json:"ordem"`
 	DisponivelDomingo int16                `json:"disponivel_domingo"`
 	DisponivelSegunda int16                `json:"disponivel_segunda"`
@@ -113,6 +116,7 @@ func ConvertSQLBoilerCategoriaToCoreDTO(categoria *models_sql_boiler.Categoria)
 		Inicio:            formatTime(categoria.Inicio),
 		Fim:               formatTime(categoria.Fim),
 		Ativo:             categoria.Ativo,
+		MaxSabores:        categoria.MaxSabores,
 		DisponivelDomingo: categoria.DisponivelDomingo,
 		DisponivelSegunda: categoria.DisponivelSegunda,
 		DisponivelTerca:   categoria.DisponivelTerca,
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Acréscimo cobrado quando o produto é escolhido no slot (ex.: +R$ 5,00
+// pela pizza especial). Pode ser negativo.
+type ComboSlotAcrescimoDTO struct {
+	IDProduto string        `json:"id_produto" validate:"required,uuid"`
+	Valor     types.Decimal `json:"valor"      validate:"required"`
+}
+
+// Escolha do combo: quantidade produtos da categoria, opcionalmente numa
+// opção fixa (tamanho). Sem quantidade vale 1.
+type ComboSlotDTO struct {
+	Nome             string                  `json:"nome"                         validate:"required,min=1,max=100"`
+	IDCategoria      string                  `json:"id_categoria"                 validate:"required,uuid"`
+	IDCategoriaOpcao *string                 `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
+	Quantidade       int32                   `json:"quantidade,omitempty"         validate:"omitempty,min=1,max=20"`
+	Acrescimos       []ComboSlotAcrescimoDTO `json:"acrescimos"                   validate:"dive"`
+}
+
+// Substitui todos os slots do combo; a ordem da lista é a ordem de exibição.
+type ComboUpdateDTO struct {
+	IDProduto uuid.UUID      `json:"-"`
+	TenantID  uuid.UUID      `json:"-"`
+	Slots     []ComboSlotDTO `json:"slots" validate:"required,min=1,max=20,dive"`
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type ComboSlotAcrescimoResponse struct {
+	IDProduto   uuid.UUID     `json:"id_produto"`
+	ProdutoNome string        `json:"produto_nome"`
+	Valor       types.Decimal `json:"valor"`
+}
+
+type ComboSlotResponse struct {
+	ID               uuid.UUID                    `json:"id"`
+	Nome             string                       `json:"nome"`
+	IDCategoria      uuid.UUID                    `json:"id_categoria"`
+	CategoriaNome    string                       `json:"categoria_nome"`
+	IDCategoriaOpcao *uuid.UUID                   `json:"id_categoria_opcao"`
+	OpcaoNome        *string                      `json:"opcao_nome"`
+	Quantidade       int32                        `json:"quantidade"`
+	Ordem            int32                        `json:"ordem"`
+	Acrescimos       []ComboSlotAcrescimoResponse `json:"acrescimos"`
+}
+
+// O preço do pacote é o do próprio produto (produto_precos)
+type ComboResponse struct {
+	IDProduto   uuid.UUID           `json:"id_produto"`
+	ProdutoNome string              `json:"produto_nome"`
+	Slots       []ComboSlotResponse `json:"slots"`
+}
@@ -0,0 +1,306 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Por que a parcela nova foi recusada
+const (
+	CreditoMotivoBloqueado = "bloqueado"
+	CreditoMotivoAtraso    = "atraso"
+	CreditoMotivoLimite    = "limite"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type CreditoConfigDTO struct {
+	TenantID uuid.UUID `json:"-"`
+	// Sem valor, cliente sem limite próprio compra fiado sem limite
+	LimitePadrao         *types.Decimal `json:"limite_padrao,omitempty"`
+	BloquearAtraso       bool           `json:"bloquear_atraso"`
+	DiasToleranciaAtraso int32          `json:"dias_tolerancia_atraso" validate:"min=0,max=365"`
+	// Encargos da parcela vencida: multa (% fixo) e juros ao dia (% pró-rata),
+	// cobrados só depois da carência. Sem valor, zero.
+	MultaPercentual      *types.Decimal `json:"multa_percentual,omitempty"`
+	JurosDiaPercentual   *types.Decimal `json:"juros_dia_percentual,omitempty"`
+	DiasCarenciaEncargos int32          `json:"dias_carencia_encargos" validate:"min=0,max=365"`
+}
+
+type ClienteCreditoDTO struct {
+	// Sem valor, vale o limite padrão do tenant
+	Limite     *types.Decimal `json:"limite,omitempty"`
+	Bloqueado  bool           `json:"bloqueado"`
+	Observacao *string        `json:"observacao,omitempty" validate:"omitempty,max=500"`
+}
+
+// Parcela nova de crediário, conferida contra a política antes de gravar
+type ParcelaCreditoDTO struct {
+	IDPedido uuid.UUID
+	Valor    types.Decimal
+}
+
+func (d CreditoConfigDTO) ToUpsertParams() pgstore.UpsertCreditoConfigParams {
+	return pgstore.UpsertCreditoConfigParams{
+		TenantID:             d.TenantID,
+		LimitePadrao:         decimalPtrToNumeric(d.LimitePadrao),
+		BloquearAtraso:       d.BloquearAtraso,
+		DiasToleranciaAtraso: d.DiasToleranciaAtraso,
+		MultaPercentual:      percentualToNumeric(d.MultaPercentual),
+		JurosDiaPercentual:   percentualToNumeric(d.JurosDiaPercentual),
+		DiasCarenciaEncargos: d.DiasCarenciaEncargos,
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type CreditoConfigResponse struct {
+	LimitePadrao         *types.Decimal `json:"limite_padrao"`
+	BloquearAtraso       bool           `json:"bloquear_atraso"`
+	DiasToleranciaAtraso int32          `json:"dias_tolerancia_atraso"`
+	MultaPercentual      types.Decimal  `json:"multa_percentual"`
+	JurosDiaPercentual   types.Decimal  `json:"juros_dia_percentual"`
+	DiasCarenciaEncargos int32          `json:"dias_carencia_encargos"`
+	UpdatedAt            *time.Time     `json:"updated_at,omitempty"`
+}
+
+type PagamentoParcelaResponse struct {
+	ID             uuid.UUID     `json:"id"`
+	FormaPagamento string        `json:"forma_pagamento"`
+	Valor          types.Decimal `json:"valor"`
+	// Parte do valor que pagou multa e juros
+	Encargos  types.Decimal `json:"encargos"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type ParcelaCreditoResponse struct {
+	ID            uuid.UUID                  `json:"id"`
+	IDPedido      uuid.UUID                  `json:"id_pedido"`
+	CodigoPedido  string                     `json:"codigo_pedido"`
+	Parcela       int16                      `json:"parcela"`
+	Vencimento    string                     `json:"vencimento"`
+	ValorDevido   types.Decimal              `json:"valor_devido"`
+	ValorPago     types.Decimal              `json:"valor_pago"`
+	Saldo         types.Decimal              `json:"saldo"`
+	DiasAtraso    int32                      `json:"dias_atraso"`
+	AutorizadoPor *uuid.UUID                 `json:"autorizado_por,omitempty"`
+	Pagamentos    []PagamentoParcelaResponse `json:"pagamentos"`
+}
+
+// Extrato do fiado do cliente: parcelas em aberto de todos os pedidos
+type ClienteCreditoResponse struct {
+	IDCliente uuid.UUID `json:"id_cliente"`
+	Nome      string    `json:"nome"`
+	// Limite em vigor (próprio ou padrão); nulo = sem limite
+	Limite        *types.Decimal `json:"limite"`
+	LimiteProprio bool           `json:"limite_proprio"`
+	Bloqueado     bool           `json:"bloqueado"`
+	Observacao    *string        `json:"observacao"`
+	SaldoAberto   types.Decimal  `json:"saldo_aberto"`
+	SaldoVencido  types.Decimal  `json:"saldo_vencido"`
+	// Nulo quando não há limite
+	Disponivel      *types.Decimal `json:"disponivel"`
+	MaiorAtrasoDias int32          `json:"maior_atraso_dias"`
+	// Pode comprar fiado hoje sem gerente
+	Liberado bool                     `json:"liberado"`
+	Motivo   string                   `json:"motivo,omitempty"`
+	Data     string                   `json:"data"`
+	Parcelas []ParcelaCreditoResponse `json:"parcelas"`
+}
+
+// Saldo atualizado da parcela numa data. Principal e encargos separados:
+// os pagamentos quitam primeiro os encargos pendentes.
+type ContaReceberSaldoResponse struct {
+	ID           uuid.UUID `json:"id"`
+	IDPedido     uuid.UUID `json:"id_pedido"`
+	CodigoPedido string    `json:"codigo_pedido"`
+	IDCliente    uuid.UUID `json:"id_cliente"`
+	Parcela      int16     `json:"parcela"`
+	Vencimento   string    `json:"vencimento"`
+	Data         string    `json:"data"`
+	DiasAtraso   int32     `json:"dias_atraso"`
+	// Vencida, mas ainda sem multa nem juros
+	EmCarencia      bool          `json:"em_carencia"`
+	Cancelada       bool          `json:"cancelada"`
+	ValorDevido     types.Decimal `json:"valor_devido"`
+	PrincipalPago   types.Decimal `json:"principal_pago"`
+	PrincipalAberto types.Decimal `json:"principal_aberto"`
+	Multa           types.Decimal `json:"multa"`
+	Juros           types.Decimal `json:"juros"`
+	EncargosPagos   types.Decimal `json:"encargos_pagos"`
+	// Multa + juros - encargos já pagos
+	EncargosPendentes types.Decimal `json:"encargos_pendentes"`
+	// Principal em aberto + encargos pendentes: quanto quita a parcela na data
+	TotalDevido types.Decimal `json:"total_devido"`
+}
+
+// Cliente com parcela nova fora da política
+type CreditoNegadoResponse struct {
+	IDCliente       uuid.UUID      `json:"id_cliente"`
+	Nome            string         `json:"nome"`
+	Motivo          string         `json:"motivo"`
+	Mensagem        string         `json:"mensagem"`
+	Limite          *types.Decimal `json:"limite"`
+	SaldoAberto     types.Decimal  `json:"saldo_aberto"`
+	ValorNovo       types.Decimal  `json:"valor_novo"`
+	MaiorAtrasoDias int32          `json:"maior_atraso_dias"`
+	IDPedidos       []uuid.UUID    `json:"id_pedidos"`
+}
+
+// Saldo em aberto por dias após o vencimento
+type AgingFaixasResponse struct {
+	AVencer    types.Decimal `json:"a_vencer"`
+	Dias1a30   types.Decimal `json:"dias_1_30"`
+	Dias31a60  types.Decimal `json:"dias_31_60"`
+	Dias61a90  types.Decimal `json:"dias_61_90"`
+	Dias90Mais types.Decimal `json:"dias_90_mais"`
+	Total      types.Decimal `json:"total"`
+}
+
+type AgingClienteResponse struct {
+	IDCliente uuid.UUID `json:"id_cliente"`
+	Nome      string    `json:"nome"`
+	Parcelas  int32     `json:"parcelas"`
+	AgingFaixasResponse
+	MaiorAtrasoDias int32 `json:"maior_atraso_dias"`
+}
+
+type AgingResponse struct {
+	Data     string                 `json:"data"`
+	Totais   AgingFaixasResponse    `json:"totais"`
+	Clientes []AgingClienteResponse `json:"clientes"`
+}
+
+// CreditoConfigToResponse: sem linha de configuração, devolve a política
+// padrão (sem limite, atraso não bloqueia)
+func CreditoConfigToResponse(c *pgstore.CreditoConfig) CreditoConfigResponse {
+	if c == nil {
+		return CreditoConfigResponse{
+			MultaPercentual:    decimalutils.FromCentavos(0),
+			JurosDiaPercentual: decimalutils.FromCentavos(0),
+		}
+	}
+	return CreditoConfigResponse{
+		LimitePadrao:         numericToDecimalPtr(c.LimitePadrao),
+		BloquearAtraso:       c.BloquearAtraso,
+		DiasToleranciaAtraso: c.DiasToleranciaAtraso,
+		MultaPercentual:      numericToPercentual(c.MultaPercentual),
+		JurosDiaPercentual:   numericToPercentual(c.JurosDiaPercentual),
+		DiasCarenciaEncargos: c.DiasCarenciaEncargos,
+		UpdatedAt:            &c.UpdatedAt,
+	}
+}
+
+func PagamentoParcelaToResponse(r pgstore.ListPagamentosParcelasClienteRow) PagamentoParcelaResponse {
+	return PagamentoParcelaResponse{
+		ID:             r.ID,
+		FormaPagamento: r.FormaPagamento,
+		Valor:          numericToDecimal(r.Valor),
+		Encargos:       numericToDecimal(r.ValorEncargos),
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+// ParcelaCreditoToResponse calcula saldo e dias de atraso na data
+func ParcelaCreditoToResponse(r pgstore.ListParcelasAbertasClienteRow, data time.Time) ParcelaCreditoResponse {
+	devido, _ := decimalutils.NumericToCentavos(r.ValorDevido)
+	pago, _ := decimalutils.NumericToCentavos(r.ValorPago)
+	p := ParcelaCreditoResponse{
+		ID:            r.ID,
+		IDPedido:      r.IDPedido,
+		CodigoPedido:  r.CodigoPedido,
+		Parcela:       r.Parcela,
+		Vencimento:    r.Vencimento.Time.Format("2006-01-02"),
+		ValorDevido:   decimalutils.FromCentavos(devido),
+		ValorPago:     decimalutils.FromCentavos(pago),
+		Saldo:         decimalutils.FromCentavos(devido - pago),
+		AutorizadoPor: uuidToPtr(r.AutorizadoPor),
+		Pagamentos:    []PagamentoParcelaResponse{},
+	}
+	if dias := int32(data.Sub(r.Vencimento.Time).Hours() / 24); dias > 0 {
+		p.DiasAtraso = dias
+	}
+	return p
+}
+
+// ContaReceberSaldoToResponse fecha pendentes e total na data
+func ContaReceberSaldoToResponse(r pgstore.GetSaldoContaReceberRow, data time.Time) ContaReceberSaldoResponse {
+	devido, _ := decimalutils.NumericToCentavos(r.ValorDevido)
+	aberto, _ := decimalutils.NumericToCentavos(r.PrincipalAberto)
+	multa, _ := decimalutils.NumericToCentavos(r.Multa)
+	juros, _ := decimalutils.NumericToCentavos(r.Juros)
+	pagos, _ := decimalutils.NumericToCentavos(r.EncargosPagos)
+	pendentes := max(multa+juros-pagos, 0)
+	return ContaReceberSaldoResponse{
+		ID:                r.ID,
+		IDPedido:          r.IDPedido,
+		CodigoPedido:      r.CodigoPedido,
+		IDCliente:         r.IDCliente,
+		Parcela:           r.Parcela,
+		Vencimento:        r.Vencimento.Time.Format("2006-01-02"),
+		Data:              data.Format("2006-01-02"),
+		DiasAtraso:        r.DiasAtraso,
+		EmCarencia:        r.EmCarencia,
+		Cancelada:         r.CanceladoEm.Valid,
+		ValorDevido:       decimalutils.FromCentavos(devido),
+		PrincipalPago:     decimalutils.FromCentavos(devido - aberto),
+		PrincipalAberto:   decimalutils.FromCentavos(aberto),
+		Multa:             decimalutils.FromCentavos(multa),
+		Juros:             decimalutils.FromCentavos(juros),
+		EncargosPagos:     decimalutils.FromCentavos(pagos),
+		EncargosPendentes: decimalutils.FromCentavos(pendentes),
+		TotalDevido:       decimalutils.FromCentavos(aberto + pendentes),
+	}
+}
+
+func AgingClienteToResponse(r pgstore.ListAgingContasReceberRow) AgingClienteResponse {
+	return AgingClienteResponse{
+		IDCliente: r.IDCliente,
+		Nome:      r.NomeRazaoSocial,
+		Parcelas:  r.Parcelas,
+		AgingFaixasResponse: AgingFaixasResponse{
+			AVencer:    numericToDecimal(r.AVencer),
+			Dias1a30:   numericToDecimal(r.Dias130),
+			Dias31a60:  numericToDecimal(r.Dias3160),
+			Dias61a90:  numericToDecimal(r.Dias6190),
+			Dias90Mais: numericToDecimal(r.Dias90Mais),
+			Total:      numericToDecimal(r.Total),
+		},
+		MaiorAtrasoDias: r.MaiorAtrasoDias,
+	}
+}
+
+func numericToDecimalPtr(n pgtype.Numeric) *types.Decimal {
+	if !n.Valid {
+		return nil
+	}
+	d := numericToDecimal(n)
+	return &d
+}
+
+// Percentuais guardam até 4 casas (numeric(7,4)); não passam por centavos
+func percentualToNumeric(d *types.Decimal) pgtype.Numeric {
+	s := "0"
+	if d != nil && d.Big != nil {
+		s = d.String()
+	}
+	var n pgtype.Numeric
+	_ = n.Scan(s)
+	return n
+}
+
+func numericToPercentual(n pgtype.Numeric) types.Decimal {
+	v, _ := n.Value()
+	s, _ := v.(string)
+	d, err := decimalutils.FromString(s)
+	if err != nil {
+		return decimalutils.FromCentavos(0)
+	}
+	return d
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestContaReceberSaldoEncargos(t *testing.T) {
+	data := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		nome                          string
+		devido, aberto                int64
+		multa, juros, pagos           int64
+		wantPago, wantPend, wantTotal int64
+	}{
+		{"em dia, nada pago", 10000, 10000, 0, 0, 0, 0, 0, 10000},
+		{"vencida sem pagamento", 10000, 10000, 200, 150, 0, 0, 350, 10350},
+		{"encargos parcialmente pagos", 10000, 6000, 200, 150, 100, 4000, 250, 6250},
+		{"encargos quitados", 10000, 6000, 200, 150, 350, 4000, 0, 6000},
+		{"pago a mais não vira crédito", 10000, 0, 200, 150, 400, 10000, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			r := pgstore.GetSaldoContaReceberRow{
+				Vencimento:      pgtype.Date{Time: data.AddDate(0, 0, -10), Valid: true},
+				ValorDevido:     decimalutils.CentavosToNumeric(tt.devido),
+				PrincipalAberto: decimalutils.CentavosToNumeric(tt.aberto),
+				Multa:           decimalutils.CentavosToNumeric(tt.multa),
+				Juros:           decimalutils.CentavosToNumeric(tt.juros),
+				EncargosPagos:   decimalutils.CentavosToNumeric(tt.pagos),
+			}
+			got := ContaReceberSaldoToResponse(r, data)
+			checar := func(campo string, v interface{ String() string }, want int64) {
+				if w := decimalutils.FromCentavos(want).String(); v.String() != w {
+					t.Errorf("%s = %s, want %s", campo, v, w)
+				}
+			}
+			checar("principal_pago", got.PrincipalPago, tt.wantPago)
+			checar("encargos_pendentes", got.EncargosPendentes, tt.wantPend)
+			checar("total_devido", got.TotalDevido, tt.wantTotal)
+			if got.Data != "2026-03-15" || got.Vencimento != "2026-03-05" {
+				t.Errorf("data %s, vencimento %s", got.Data, got.Vencimento)
+			}
+		})
+	}
+}
+
+func TestParcelaCreditoDiasAtraso(t *testing.T) {
+	venc := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		data string
+		want int32
+	}{
+		{"2026-03-01", 0},
+		{"2026-03-10", 0},
+		{"2026-03-11", 1},
+		{"2026-04-10", 31},
+	}
+	for _, tt := range tests {
+		data, _ := time.Parse("2006-01-02", tt.data)
+		r := pgstore.ListParcelasAbertasClienteRow{
+			Vencimento:  pgtype.Date{Time: venc, Valid: true},
+			ValorDevido: decimalutils.CentavosToNumeric(5000),
+			ValorPago:   decimalutils.CentavosToNumeric(1000),
+		}
+		p := ParcelaCreditoToResponse(r, data)
+		if p.DiasAtraso != tt.want {
+			t.Errorf("em %s: dias_atraso = %d, want %d", tt.data, p.DiasAtraso, tt.want)
+		}
+		if p.Saldo.String() != decimalutils.FromCentavos(4000).String() {
+			t.Errorf("em %s: saldo = %s", tt.data, p.Saldo)
+		}
+	}
+}
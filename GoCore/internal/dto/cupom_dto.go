@@ -0,0 +1,227 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Tipos de cupom (cupons.tipo)
+const (
+	CupomPercentual  = "P"
+	CupomValorFixo   = "V"
+	CupomFreteGratis = "F"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Cadastro e alteração de cupom. Sem categorias nem produtos o cupom vale
+// para o pedido todo; com eles, o desconto incide só nos itens que casam.
+type CupomDTO struct {
+	ID        uuid.UUID `json:"-"`
+	TenantID  uuid.UUID `json:"-"`
+	Codigo    string    `json:"codigo"              validate:"required,min=3,max=40,alphanum"`
+	Descricao *string   `json:"descricao,omitempty" validate:"omitempty,max=200"`
+	Tipo      string    `json:"tipo"                validate:"required,oneof=P V F"`
+	// Percentual (P) ou valor em reais (V); ignorado no frete grátis (F)
+	Valor             types.Decimal  `json:"valor"`
+	DescontoMaximo    *types.Decimal `json:"desconto_maximo,omitempty"`
+	ValorMinimoPedido *types.Decimal `json:"valor_minimo_pedido,omitempty"`
+	// Sem início, vale a partir de agora; sem fim, não expira
+	Inicio           *time.Time `json:"inicio,omitempty"`
+	Fim              *time.Time `json:"fim,omitempty"`
+	LimiteTotal      *int32     `json:"limite_total,omitempty"       validate:"omitempty,min=1"`
+	LimitePorCliente *int32     `json:"limite_por_cliente,omitempty" validate:"omitempty,min=1"`
+	Ativo            *bool      `json:"ativo,omitempty"`
+	Categorias       []string   `json:"categorias,omitempty"         validate:"omitempty,max=50,dive,uuid"`
+	Produtos         []string   `json:"produtos,omitempty"           validate:"omitempty,max=200,dive,uuid"`
+}
+
+func (d *CupomDTO) ToCreateParams() pgstore.CreateCupomParams {
+	inicio := time.Now()
+	if d.Inicio != nil {
+		inicio = *d.Inicio
+	}
+	ativo := d.Ativo == nil || *d.Ativo
+
+	return pgstore.CreateCupomParams{
+		TenantID:          d.TenantID,
+		Codigo:            d.Codigo,
+		Descricao:         ptrToPgText(d.Descricao),
+		Tipo:              d.Tipo,
+		Valor:             decimalutils.CentavosToNumeric(decimalutils.ToCentavos(d.Valor)),
+		DescontoMaximo:    decimalPtrToNumeric(d.DescontoMaximo),
+		ValorMinimoPedido: decimalutils.CentavosToNumeric(decimalPtrToCentavos(d.ValorMinimoPedido)),
+		Inicio:            inicio,
+		Fim:               timePtrToTimestamptz(d.Fim),
+		LimiteTotal:       int32PtrToPgInt4(d.LimiteTotal),
+		LimitePorCliente:  int32PtrToPgInt4(d.LimitePorCliente),
+		Ativo:             ativo,
+	}
+}
+
+func (d *CupomDTO) ToUpdateParams() pgstore.UpdateCupomParams {
+	p := d.ToCreateParams()
+	return pgstore.UpdateCupomParams{
+		ID:                d.ID,
+		TenantID:          d.TenantID,
+		Codigo:            p.Codigo,
+		Descricao:         p.Descricao,
+		Tipo:              p.Tipo,
+		Valor:             p.Valor,
+		DescontoMaximo:    p.DescontoMaximo,
+		ValorMinimoPedido: p.ValorMinimoPedido,
+		Inicio:            p.Inicio,
+		Fim:               p.Fim,
+		LimiteTotal:       p.LimiteTotal,
+		LimitePorCliente:  p.LimitePorCliente,
+		Ativo:             p.Ativo,
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type CupomRestricaoResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Nome string    `json:"nome"`
+}
+
+type CupomResponse struct {
+	ID                uuid.UUID                `json:"id"`
+	Codigo            string                   `json:"codigo"`
+	Descricao         *string                  `json:"descricao"`
+	Tipo              string                   `json:"tipo"`
+	Valor             types.Decimal            `json:"valor"`
+	DescontoMaximo    *types.Decimal           `json:"desconto_maximo"`
+	ValorMinimoPedido types.Decimal            `json:"valor_minimo_pedido"`
+	Inicio            time.Time                `json:"inicio"`
+	Fim               *time.Time               `json:"fim"`
+	LimiteTotal       *int32                   `json:"limite_total"`
+	LimitePorCliente  *int32                   `json:"limite_por_cliente"`
+	Usos              int32                    `json:"usos"` // resgates ativos
+	Ativo             bool                     `json:"ativo"`
+	Categorias        []CupomRestricaoResponse `json:"categorias"`
+	Produtos          []CupomRestricaoResponse `json:"produtos"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
+
+type CupomResgateResponse struct {
+	ID            uuid.UUID     `json:"id"`
+	IDPedido      uuid.UUID     `json:"id_pedido"`
+	CodigoPedido  string        `json:"codigo_pedido"`
+	IDCliente     uuid.UUID     `json:"id_cliente"`
+	ClienteNome   string        `json:"cliente_nome"`
+	ValorDesconto types.Decimal `json:"valor_desconto"`
+	CreatedAt     time.Time     `json:"created_at"`
+	// Preenchido quando o pedido foi cancelado, excluído ou trocou de cupom
+	CanceladoEm *time.Time `json:"cancelado_em,omitempty"`
+}
+
+// CupomToResponse converte o cupom; restricoes são as linhas de
+// ListCupomRestricoes (de qualquer cupom, filtradas pelo id).
+func CupomToResponse(c pgstore.Cupon, restricoes []pgstore.ListCupomRestricoesRow) CupomResponse {
+	out := CupomResponse{
+		ID:                c.ID,
+		Codigo:            c.Codigo,
+		Tipo:              c.Tipo,
+		Valor:             numericToDecimal(c.Valor),
+		ValorMinimoPedido: numericToDecimal(c.ValorMinimoPedido),
+		Inicio:            c.Inicio,
+		Fim:               timestamptzToPtr(c.Fim),
+		Usos:              c.Usos,
+		Ativo:             c.Ativo,
+		Categorias:        []CupomRestricaoResponse{},
+		Produtos:          []CupomRestricaoResponse{},
+		CreatedAt:         c.CreatedAt,
+		UpdatedAt:         c.UpdatedAt,
+	}
+	if c.Descricao.Valid {
+		out.Descricao = &c.Descricao.String
+	}
+	if c.DescontoMaximo.Valid {
+		v := numericToDecimal(c.DescontoMaximo)
+		out.DescontoMaximo = &v
+	}
+	if c.LimiteTotal.Valid {
+		out.LimiteTotal = &c.LimiteTotal.Int32
+	}
+	if c.LimitePorCliente.Valid {
+		out.LimitePorCliente = &c.LimitePorCliente.Int32
+	}
+
+	for _, r := range restricoes {
+		if r.IDCupom != c.ID {
+			continue
+		}
+		item := CupomRestricaoResponse{ID: r.ID, Nome: r.Nome}
+		if r.Tipo == "C" {
+			out.Categorias = append(out.Categorias, item)
+		} else {
+			out.Produtos = append(out.Produtos, item)
+		}
+	}
+	return out
+}
+
+func CupomResgatesToResponses(rows []pgstore.ListCupomResgatesRow) []CupomResgateResponse {
+	out := make([]CupomResgateResponse, len(rows))
+	for i, r := range rows {
+		out[i] = CupomResgateResponse{
+			ID:            r.ID,
+			IDPedido:      r.IDPedido,
+			CodigoPedido:  r.CodigoPedido,
+			IDCliente:     r.IDCliente,
+			ClienteNome:   r.ClienteNome,
+			ValorDesconto: numericToDecimal(r.ValorDesconto),
+			CreatedAt:     r.CreatedAt,
+			CanceladoEm:   timestamptzToPtr(r.CanceladoEm),
+		}
+	}
+	return out
+}
+
+func numericToDecimal(n pgtype.Numeric) types.Decimal {
+	c, _ := decimalutils.NumericToCentavos(n)
+	return decimalutils.FromCentavos(c)
+}
+
+func decimalPtrToCentavos(d *types.Decimal) int64 {
+	if d == nil || d.Big == nil {
+		return 0
+	}
+	return decimalutils.ToCentavos(*d)
+}
+
+func decimalPtrToNumeric(d *types.Decimal) pgtype.Numeric {
+	if d == nil || d.Big == nil {
+		return pgtype.Numeric{}
+	}
+	return decimalutils.CentavosToNumeric(decimalutils.ToCentavos(*d))
+}
+
+func ptrToPgText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func timePtrToTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func int32PtrToPgInt4(v *int32) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *v, Valid: true}
+}
@@ -30,6 +30,10 @@ type DashboardTotalRow struct {
 	TotalAcrescimo   float64   `json:"total_acrescimo"`
 	TotalTaxaEntrega float64   `json:"total_taxa_entrega"`
 	TotalValorTotal  float64   `json:"total_valor_total"`
+	// Taxa da adquirente (MDR) dos pagamentos e o que eles depositam
+	// (encargos incluídos, taxa descontada)
+	TotalTaxaAdquirente float64 `json:"total_taxa_adquirente"`
+	TotalLiquido        float64 `json:"total_liquido"`
 }
 
 // DashboardDetailedRow representa uma linha detalhada do dashboard
@@ -76,13 +80,15 @@ type DashboardDetailedRow struct {
 // TotalRowToDTO converte uma linha de totais do sqlc para o DTO
 func TotalRowToDTO(row pgstore.GetTotalBrutoAndTotalPagoRow) DashboardTotalRow {
 	return DashboardTotalRow{
-		Dia:              row.Dia.Time,
-		TotalBruto:       numericToFloat64(row.TotalBruto),
-		TotalPago:        numericToFloat64(row.TotalPago),
-		TotalDesconto:    numericToFloat64(row.TotalDesconto),
-		TotalAcrescimo:   numericToFloat64(row.TotalAcrescimo),
-		TotalTaxaEntrega: numericToFloat64(row.TotalTaxaEntrega),
-		TotalValorTotal:  numericToFloat64(row.TotalValorTotal),
+		Dia:                 row.Dia.Time,
+		TotalBruto:          numericToFloat64(row.TotalBruto),
+		TotalPago:           numericToFloat64(row.TotalPago),
+		TotalDesconto:       numericToFloat64(row.TotalDesconto),
+		TotalAcrescimo:      numericToFloat64(row.TotalAcrescimo),
+		TotalTaxaEntrega:    numericToFloat64(row.TotalTaxaEntrega),
+		TotalValorTotal:     numericToFloat64(row.TotalValorTotal),
+		TotalTaxaAdquirente: numericToFloat64(row.TotalTaxaAdquirente),
+		TotalLiquido:        numericToFloat64(row.TotalLiquido),
 	}
 }
 
@@ -202,6 +208,9 @@ type DashboardPaymentResumoRow struct {
 	Dia                time.Time `json:"dia"`
 	CategoriaPagamento string    `json:"categoria_pagamento"`
 	ValorLiquido       float64   `json:"valor_liquido"`
+	// MDR descontado e o que a adquirente deposita
+	TaxaAdquirente float64 `json:"taxa_adquirente"`
+	ValorRepasse   float64 `json:"valor_repasse"`
 }
 
 // DashboardPaymentDetalhadoRow representa cada pagamento individual (tabela modal)
@@ -226,8 +235,10 @@ type DashboardPaymentDetalhadoRow struct {
 // PaymentResumoRowToDTO converte uma linha agregada do sqlc para o DTO
 func PaymentResumoRowToDTO(row pgstore.GetPagamentosPorDiaECategoriaRow) DashboardPaymentResumoRow {
 	dto := DashboardPaymentResumoRow{
-		Dia:          row.Dia.Time,
-		ValorLiquido: numericToFloat64(row.ValorLiquido),
+		Dia:            row.Dia.Time,
+		ValorLiquido:   numericToFloat64(row.ValorLiquido),
+		TaxaAdquirente: numericToFloat64(row.TaxaAdquirente),
+		ValorRepasse:   numericToFloat64(row.ValorRepasse),
 	}
 
 	// categoria_pagamento é pgtype.Text
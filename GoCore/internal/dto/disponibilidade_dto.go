@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Motivos de indisponibilidade, estáveis para a interface tratar cada caso
+const (
+	MotivoCategoriaInativa  = "categoria_inativa"
+	MotivoForaDoHorario     = "fora_do_horario"
+	MotivoDiaIndisponivel   = "dia_indisponivel"
+	MotivoProdutoInativo    = "produto_inativo"
+	MotivoOpcaoInativa      = "opcao_inativa"
+	MotivoPrecoIndisponivel = "preco_indisponivel"
+)
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type CardapioOpcaoResponse struct {
+	IDCategoriaOpcao string         `json:"id_categoria_opcao"`
+	Nome             string         `json:"nome"`
+	PrecoBase        types.Decimal  `json:"preco_base"`
+	PrecoPromocional *types.Decimal `json:"preco_promocional,omitempty"`
+	Disponivel       bool           `json:"disponivel"`
+	Motivo           string         `json:"motivo,omitempty"`
+}
+
+type CardapioProdutoResponse struct {
+	ID         string                  `json:"id"`
+	Nome       string                  `json:"nome"`
+	Disponivel bool                    `json:"disponivel"`
+	Motivo     string                  `json:"motivo,omitempty"`
+	Opcoes     []CardapioOpcaoResponse `json:"opcoes"`
+}
+
+type CardapioCategoriaResponse struct {
+	ID   string `json:"id"`
+	Nome string `json:"nome"`
+	// Janela diária no formato HH:MM; inicio = fim significa o dia todo
+	Inicio     string                    `json:"inicio"`
+	Fim        string                    `json:"fim"`
+	Disponivel bool                      `json:"disponivel"`
+	Motivo     string                    `json:"motivo,omitempty"`
+	Produtos   []CardapioProdutoResponse `json:"produtos"`
+}
+
+type CardapioDisponivelResponse struct {
+	// Horário local do tenant usado na avaliação
+	DataReferencia time.Time                   `json:"data_referencia"`
+	Timezone       string                      `json:"timezone"`
+	Categorias     []CardapioCategoriaResponse `json:"categorias"`
+}
+
+// Categoria do pedido fora da disponibilidade no momento da criação
+type IndisponibilidadeItem struct {
+	Item        int    `json:"item"`
+	IDCategoria string `json:"id_categoria"`
+	Categoria   string `json:"categoria"`
+	Motivo      string `json:"motivo"`
+	Mensagem    string `json:"mensagem"`
+}
@@ -0,0 +1,338 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Status da rota de entrega (rotas_entrega.status)
+const (
+	RotaAberta    = "A"
+	RotaEmRota    = "S"
+	RotaRetornou  = "R"
+	RotaCancelada = "C"
+)
+
+// Status do pedido na rota (rota_entrega_pedidos.status)
+const (
+	EntregaPendente     = "P"
+	EntregaRealizada    = "E"
+	EntregaNaoRealizada = "N"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type EntregadorDTO struct {
+	ID             uuid.UUID     `json:"-"`
+	TenantID       uuid.UUID     `json:"-"`
+	IDUsuario      *string       `json:"id_usuario,omitempty" validate:"omitempty,uuid"`
+	Nome           string        `json:"nome"                 validate:"required,min=1,max=100"`
+	Telefone       *string       `json:"telefone,omitempty"   validate:"omitempty,max=20"`
+	Veiculo        *string       `json:"veiculo,omitempty"    validate:"omitempty,max=50"`
+	Placa          *string       `json:"placa,omitempty"      validate:"omitempty,max=10"`
+	TaxaPorEntrega types.Decimal `json:"taxa_por_entrega"`
+	Ativo          *bool         `json:"ativo,omitempty"`
+}
+
+func (d EntregadorDTO) ToCreateParams() pgstore.CreateEntregadorParams {
+	return pgstore.CreateEntregadorParams{
+		TenantID:       d.TenantID,
+		IDUsuario:      stringPtrToPgUUID(d.IDUsuario),
+		Nome:           d.Nome,
+		Telefone:       textFromPtr(d.Telefone),
+		Veiculo:        textFromPtr(d.Veiculo),
+		Placa:          textFromPtr(d.Placa),
+		TaxaPorEntrega: decimalutils.CentavosToNumeric(decimalutils.ToCentavos(d.TaxaPorEntrega)),
+		Ativo:          d.Ativo == nil || *d.Ativo,
+	}
+}
+
+func (d EntregadorDTO) ToUpdateParams() pgstore.UpdateEntregadorParams {
+	c := d.ToCreateParams()
+	return pgstore.UpdateEntregadorParams{
+		ID:             d.ID,
+		TenantID:       d.TenantID,
+		IDUsuario:      c.IDUsuario,
+		Nome:           c.Nome,
+		Telefone:       c.Telefone,
+		Veiculo:        c.Veiculo,
+		Placa:          c.Placa,
+		TaxaPorEntrega: c.TaxaPorEntrega,
+		Ativo:          c.Ativo,
+	}
+}
+
+type RotaEntregaCreateDTO struct {
+	TenantID     uuid.UUID `json:"-"`
+	UserID       uuid.UUID `json:"-"`
+	IDEntregador string    `json:"id_entregador"        validate:"required,uuid"`
+	Pedidos      []string  `json:"pedidos"              validate:"required,min=1,max=50,dive,uuid"`
+	Observacao   *string   `json:"observacao,omitempty" validate:"omitempty,max=500"`
+}
+
+type RotaEntregaPedidosDTO struct {
+	Pedidos []string `json:"pedidos" validate:"required,min=1,max=50,dive,uuid"`
+}
+
+// RotaEntregaRef identifica a rota da ação. Com IDEntregador (app do
+// entregador) a rota precisa ser dele.
+type RotaEntregaRef struct {
+	TenantID     uuid.UUID
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	IDEntregador uuid.UUID
+}
+
+// Pedido entregue. Sem valor_recebido vale o saldo em dinheiro calculado
+// na saída.
+type EntregaRealizadaDTO struct {
+	ValorRecebido *types.Decimal `json:"valor_recebido,omitempty"`
+	Observacao    *string        `json:"observacao,omitempty" validate:"omitempty,max=500"`
+}
+
+type EntregaNaoRealizadaDTO struct {
+	Motivo string `json:"motivo" validate:"required,min=1,max=500"`
+}
+
+// Acerto da rota. valor_entregue é o dinheiro que o entregador devolveu,
+// para conferência com o recebido menos as taxas.
+type RotaEntregaAcertoDTO struct {
+	ValorEntregue *types.Decimal `json:"valor_entregue,omitempty"`
+	Observacao    *string        `json:"observacao,omitempty" validate:"omitempty,max=500"`
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type EntregadorResponse struct {
+	ID             uuid.UUID     `json:"id"`
+	IDUsuario      *uuid.UUID    `json:"id_usuario,omitempty"`
+	Nome           string        `json:"nome"`
+	Telefone       *string       `json:"telefone,omitempty"`
+	Veiculo        *string       `json:"veiculo,omitempty"`
+	Placa          *string       `json:"placa,omitempty"`
+	TaxaPorEntrega types.Decimal `json:"taxa_por_entrega"`
+	Ativo          bool          `json:"ativo"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+type RotaEntregaClienteResponse struct {
+	Nome        string   `json:"nome"`
+	Telefone    *string  `json:"telefone,omitempty"`
+	Logradouro  *string  `json:"logradouro,omitempty"`
+	Numero      *string  `json:"numero,omitempty"`
+	Complemento *string  `json:"complemento,omitempty"`
+	Bairro      *string  `json:"bairro,omitempty"`
+	Cidade      *string  `json:"cidade,omitempty"`
+	Cep         *string  `json:"cep,omitempty"`
+	Lat         *float64 `json:"lat,omitempty"`
+	Lng         *float64 `json:"lng,omitempty"`
+}
+
+type RotaEntregaPedidoResponse struct {
+	IDPedido       uuid.UUID      `json:"id_pedido"`
+	CodigoPedido   string         `json:"codigo_pedido"`
+	IDStatusPedido int16          `json:"id_status_pedido"`
+	Ordem          int32          `json:"ordem"`
+	Status         string         `json:"status"`
+	ValorPedido    types.Decimal  `json:"valor_pedido"`
+	ValorPago      types.Decimal  `json:"valor_pago"`
+	FormaPagamento *string        `json:"forma_pagamento,omitempty"`
+	TrocoPara      *types.Decimal `json:"troco_para,omitempty"`
+	// Taxa do entregador por este pedido
+	ValorTaxa types.Decimal `json:"valor_taxa"`
+	// Dinheiro a cobrar do cliente (calculado na saída) e o recebido
+	ValorReceber  types.Decimal `json:"valor_receber"`
+	ValorRecebido types.Decimal `json:"valor_recebido"`
+	// Parte do recebido que passou do saldo do pedido no acerto (o cliente
+	// pagou por outro meio depois da saída); não vira pagamento e é devolvida
+	ValorExcedente   types.Decimal              `json:"valor_excedente"`
+	EntregueEm       *time.Time                 `json:"entregue_em,omitempty"`
+	Observacao       *string                    `json:"observacao,omitempty"`
+	IDPagamento      *uuid.UUID                 `json:"id_pagamento,omitempty"`
+	DataPedido       time.Time                  `json:"data_pedido"`
+	PrazoMax         *int32                     `json:"prazo_max,omitempty"`
+	AgendadoPara     *time.Time                 `json:"agendado_para,omitempty"`
+	ObservacaoPedido *string                    `json:"observacao_pedido,omitempty"`
+	Cliente          RotaEntregaClienteResponse `json:"cliente"`
+}
+
+type RotaEntregaResponse struct {
+	ID             uuid.UUID      `json:"id"`
+	Numero         int64          `json:"numero"`
+	IDEntregador   uuid.UUID      `json:"id_entregador"`
+	EntregadorNome string         `json:"entregador_nome"`
+	Status         string         `json:"status"`
+	SaidaEm        *time.Time     `json:"saida_em,omitempty"`
+	RetornoEm      *time.Time     `json:"retorno_em,omitempty"`
+	Observacao     *string        `json:"observacao,omitempty"`
+	ValorTaxas     types.Decimal  `json:"valor_taxas"`
+	ValorRecebido  types.Decimal  `json:"valor_recebido"`
+	ValorEntregue  *types.Decimal `json:"valor_entregue,omitempty"`
+	// Soma dos excedentes dos pedidos, a devolver aos clientes
+	ValorExcedente types.Decimal `json:"valor_excedente"`
+	// valor_entregue - (valor_recebido - valor_taxas), após o acerto
+	Diferenca        *types.Decimal              `json:"diferenca,omitempty"`
+	IDCaixa          *uuid.UUID                  `json:"id_caixa,omitempty"`
+	AcertadoEm       *time.Time                  `json:"acertado_em,omitempty"`
+	AcertadoPor      *uuid.UUID                  `json:"acertado_por,omitempty"`
+	ObservacaoAcerto *string                     `json:"observacao_acerto,omitempty"`
+	CreatedAt        time.Time                   `json:"created_at"`
+	UpdatedAt        time.Time                   `json:"updated_at"`
+	Pedidos          []RotaEntregaPedidoResponse `json:"pedidos"`
+}
+
+// Linha do relatório: entregas de um entregador em um dia
+type RelatorioEntregadorDiaResponse struct {
+	IDEntregador  uuid.UUID     `json:"id_entregador"`
+	Nome          string        `json:"nome"`
+	Dia           string        `json:"dia"`
+	Rotas         int64         `json:"rotas"`
+	Entregues     int64         `json:"entregues"`
+	NaoEntregues  int64         `json:"nao_entregues"`
+	ValorTaxas    types.Decimal `json:"valor_taxas"`
+	ValorRecebido types.Decimal `json:"valor_recebido"`
+	// Média entre a saída da rota e a entrega
+	MinutosMedio float64 `json:"minutos_medio"`
+}
+
+func EntregadorToResponse(e pgstore.Entregadore) EntregadorResponse {
+	return EntregadorResponse{
+		ID:             e.ID,
+		IDUsuario:      uuidToPtr(e.IDUsuario),
+		Nome:           e.Nome,
+		Telefone:       textToPtr(e.Telefone),
+		Veiculo:        textToPtr(e.Veiculo),
+		Placa:          textToPtr(e.Placa),
+		TaxaPorEntrega: numericToDecimal(e.TaxaPorEntrega),
+		Ativo:          e.Ativo,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+// RotaEntregaToResponse monta a rota com os pedidos dela; pedidos pode
+// trazer os de outras rotas (consulta em lote)
+func RotaEntregaToResponse(r pgstore.GetRotaEntregaRow, pedidos []pgstore.ListRotaEntregaPedidosRow) RotaEntregaResponse {
+	out := RotaEntregaResponse{
+		ID:               r.ID,
+		Numero:           r.SeqID,
+		IDEntregador:     r.IDEntregador,
+		EntregadorNome:   r.EntregadorNome,
+		Status:           r.Status,
+		SaidaEm:          timestamptzToPtr(r.SaidaEm),
+		RetornoEm:        timestamptzToPtr(r.RetornoEm),
+		Observacao:       textToPtr(r.Observacao),
+		ValorTaxas:       numericToDecimal(r.ValorTaxas),
+		ValorRecebido:    numericToDecimal(r.ValorRecebido),
+		IDCaixa:          uuidToPtr(r.IDCaixa),
+		AcertadoEm:       timestamptzToPtr(r.AcertadoEm),
+		AcertadoPor:      uuidToPtr(r.AcertadoPor),
+		ObservacaoAcerto: textToPtr(r.ObservacaoAcerto),
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		Pedidos:          []RotaEntregaPedidoResponse{},
+	}
+	if r.ValorEntregue.Valid {
+		entregue, _ := decimalutils.NumericToCentavos(r.ValorEntregue)
+		recebido, _ := decimalutils.NumericToCentavos(r.ValorRecebido)
+		taxas, _ := decimalutils.NumericToCentavos(r.ValorTaxas)
+		v, d := decimalutils.FromCentavos(entregue), decimalutils.FromCentavos(entregue-(recebido-taxas))
+		out.ValorEntregue, out.Diferenca = &v, &d
+	}
+
+	var excedente int64
+	for _, p := range pedidos {
+		if p.IDRota != r.ID {
+			continue
+		}
+		c, _ := decimalutils.NumericToCentavos(p.ValorExcedente)
+		excedente += c
+
+		item := RotaEntregaPedidoResponse{
+			IDPedido:         p.IDPedido,
+			CodigoPedido:     p.CodigoPedido,
+			IDStatusPedido:   p.IDStatus,
+			Ordem:            p.Ordem,
+			Status:           p.Status,
+			ValorPedido:      numericToDecimal(p.ValorPedido),
+			ValorPago:        numericToDecimal(p.ValorPago),
+			FormaPagamento:   textToPtr(p.FormaPagamento),
+			ValorTaxa:        numericToDecimal(p.ValorTaxa),
+			ValorReceber:     numericToDecimal(p.ValorReceber),
+			ValorRecebido:    numericToDecimal(p.ValorRecebido),
+			ValorExcedente:   numericToDecimal(p.ValorExcedente),
+			EntregueEm:       timestamptzToPtr(p.EntregueEm),
+			Observacao:       textToPtr(p.Observacao),
+			IDPagamento:      uuidToPtr(p.IDPagamento),
+			DataPedido:       p.DataPedido,
+			AgendadoPara:     timestamptzToPtr(p.AgendadoPara),
+			ObservacaoPedido: textToPtr(p.ObservacaoPedido),
+			Cliente: RotaEntregaClienteResponse{
+				Nome:        p.ClienteNome,
+				Telefone:    textToPtr(p.ClienteTelefone),
+				Logradouro:  textToPtr(p.Logradouro),
+				Numero:      textToPtr(p.Numero),
+				Complemento: textToPtr(p.Complemento),
+				Bairro:      textToPtr(p.Bairro),
+				Cidade:      textToPtr(p.Cidade),
+				Cep:         textToPtr(p.Cep),
+				Lat:         numericToFloatPtr(p.Lat),
+				Lng:         numericToFloatPtr(p.Lng),
+			},
+		}
+		if p.TrocoPara.Valid {
+			troco := numericToDecimal(p.TrocoPara)
+			item.TrocoPara = &troco
+		}
+		if p.PrazoMax.Valid {
+			item.PrazoMax = &p.PrazoMax.Int32
+		}
+		out.Pedidos = append(out.Pedidos, item)
+	}
+	out.ValorExcedente = decimalutils.FromCentavos(excedente)
+	return out
+}
+
+func RelatorioEntregadoresToResponse(rows []pgstore.ListRelatorioEntregadoresRow) []RelatorioEntregadorDiaResponse {
+	out := make([]RelatorioEntregadorDiaResponse, len(rows))
+	for i, r := range rows {
+		out[i] = RelatorioEntregadorDiaResponse{
+			IDEntregador:  r.IDEntregador,
+			Nome:          r.Nome,
+			Dia:           r.Dia.Time.Format("2006-01-02"),
+			Rotas:         r.Rotas,
+			Entregues:     r.Entregues,
+			NaoEntregues:  r.NaoEntregues,
+			ValorTaxas:    numericToDecimal(r.ValorTaxas),
+			ValorRecebido: numericToDecimal(r.ValorRecebido),
+			MinutosMedio:  r.MinutosMedio,
+		}
+	}
+	return out
+}
+
+func stringPtrToPgUUID(s *string) pgtype.UUID {
+	if s == nil {
+		return pgtype.UUID{}
+	}
+	id, err := uuid.Parse(*s)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func numericToFloatPtr(n pgtype.Numeric) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f := numericToFloat64(n)
+	return &f
+}
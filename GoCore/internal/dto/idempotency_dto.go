@@ -0,0 +1,23 @@
+package dto
+
+import "gobid/internal/store/pgstore"
+
+// IdempotencyRegistro é o estado de uma Idempotency-Key já usada
+type IdempotencyRegistro struct {
+	HashRequisicao string
+	// false enquanto a primeira requisição ainda está em processamento
+	Concluida   bool
+	StatusCode  int
+	ContentType string
+	Resposta    []byte
+}
+
+func IdempotencyKeyToRegistro(k pgstore.IdempotencyKey) IdempotencyRegistro {
+	return IdempotencyRegistro{
+		HashRequisicao: k.HashRequisicao,
+		Concluida:      k.StatusCode.Valid,
+		StatusCode:     int(k.StatusCode.Int32),
+		ContentType:    k.ContentType.String,
+		Resposta:       k.Resposta,
+	}
+}
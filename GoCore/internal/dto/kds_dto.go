@@ -0,0 +1,185 @@
+package dto
+
+import (
+	"encoding/json"
+	"gobid/internal/store/pgstore"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Estados de preparo de um item no KDS
+const (
+	StatusPreparoQueued    = "queued"    // aguardando
+	StatusPreparoPreparing = "preparing" // em preparo
+	StatusPreparoDone      = "done"      // pronto, aguardando despacho
+	StatusPreparoBumped    = "bumped"    // despachado / retirado da tela
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type CreateEstacaoProducaoDTO struct {
+	TenantID uuid.UUID `json:"-"`
+	Nome     string    `json:"nome"  validate:"required,min=1,max=100"`
+	Ordem    int32     `json:"ordem"`
+	Ativo    *bool     `json:"ativo"`
+}
+
+type UpdateEstacaoProducaoDTO struct {
+	ID       uuid.UUID `json:"-"`
+	TenantID uuid.UUID `json:"-"`
+	Nome     string    `json:"nome"  validate:"required,min=1,max=100"`
+	Ordem    int32     `json:"ordem"`
+	Ativo    bool      `json:"ativo"`
+}
+
+// id_estacao nulo remove o roteamento da categoria/produto
+type SetEstacaoDTO struct {
+	IDEstacao *uuid.UUID `json:"id_estacao"`
+}
+
+type UpdateStatusPreparoDTO struct {
+	Status string `json:"status" validate:"required,oneof=queued preparing done bumped"`
+}
+
+func CreateEstacaoDTOToParams(in *CreateEstacaoProducaoDTO) pgstore.CreateEstacaoProducaoParams {
+	ativo := true
+	if in.Ativo != nil {
+		ativo = *in.Ativo
+	}
+	return pgstore.CreateEstacaoProducaoParams{
+		TenantID: in.TenantID,
+		Nome:     in.Nome,
+		Ordem:    in.Ordem,
+		Ativo:    ativo,
+	}
+}
+
+func UpdateEstacaoDTOToParams(in *UpdateEstacaoProducaoDTO) pgstore.UpdateEstacaoProducaoParams {
+	return pgstore.UpdateEstacaoProducaoParams{
+		ID:       in.ID,
+		TenantID: in.TenantID,
+		Nome:     in.Nome,
+		Ordem:    in.Ordem,
+		Ativo:    in.Ativo,
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type EstacaoProducaoResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Nome      string    `json:"nome"`
+	Ordem     int32     `json:"ordem"`
+	Ativo     bool      `json:"ativo"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func EstacaoProducaoToResponse(e pgstore.EstacoesProducao) EstacaoProducaoResponse {
+	return EstacaoProducaoResponse{
+		ID:        e.ID,
+		Nome:      e.Nome,
+		Ordem:     e.Ordem,
+		Ativo:     e.Ativo,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+func EstacoesProducaoToResponses(rows []pgstore.EstacoesProducao) []EstacaoProducaoResponse {
+	out := make([]EstacaoProducaoResponse, len(rows))
+	for i, r := range rows {
+		out[i] = EstacaoProducaoToResponse(r)
+	}
+	return out
+}
+
+// Item exibido na tela da estação
+type FilaPreparoItemResponse struct {
+	ID                uuid.UUID       `json:"id"`
+	IDPedido          uuid.UUID       `json:"id_pedido"`
+	CodigoPedido      string          `json:"codigo_pedido"`
+	TipoEntrega       string          `json:"tipo_entrega"`
+	DataPedido        time.Time       `json:"data_pedido"`
+	IDEstacao         *uuid.UUID      `json:"id_estacao"`
+	IDProduto         uuid.UUID       `json:"id_produto"`
+	ProdutoNome       string          `json:"produto_nome"`
+	Produto2Nome      *string         `json:"produto_2_nome"`
+	OpcaoNome         *string         `json:"opcao_nome"`
+	Quantidade        int32           `json:"quantidade"`
+	Observacao        *string         `json:"observacao"`
+	StatusPreparo     string          `json:"status_preparo"`
+	DataInicioPreparo *time.Time      `json:"data_inicio_preparo"`
+	DataFimPreparo    *time.Time      `json:"data_fim_preparo"`
+	Adicionais        json.RawMessage `json:"adicionais"` // [{nome, quantidade}]
+	Sabores           json.RawMessage `json:"sabores"`    // [{nome, partes}], vazio para um sabor só
+}
+
+func FilaPreparoRowsToResponses(rows []pgstore.ListFilaPreparoRow) []FilaPreparoItemResponse {
+	out := make([]FilaPreparoItemResponse, len(rows))
+	for i, r := range rows {
+		out[i] = FilaPreparoItemResponse{
+			ID:                r.ID,
+			IDPedido:          r.IDPedido,
+			CodigoPedido:      r.CodigoPedido,
+			TipoEntrega:       r.TipoEntrega,
+			DataPedido:        r.DataPedido,
+			IDEstacao:         uuidToPtr(r.IDEstacao),
+			IDProduto:         r.IDProduto,
+			ProdutoNome:       r.ProdutoNome,
+			Produto2Nome:      textToPtr(r.Produto2Nome),
+			OpcaoNome:         textToPtr(r.OpcaoNome),
+			Quantidade:        r.Quantidade,
+			Observacao:        textToPtr(r.Observacao),
+			StatusPreparo:     r.StatusPreparo,
+			DataInicioPreparo: timestamptzToPtr(r.DataInicioPreparo),
+			DataFimPreparo:    timestamptzToPtr(r.DataFimPreparo),
+			Adicionais:        r.Adicionais,
+			Sabores:           r.Sabores,
+		}
+	}
+	return out
+}
+
+type PedidoItemPreparoResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	IDPedido          uuid.UUID  `json:"id_pedido"`
+	IDEstacao         *uuid.UUID `json:"id_estacao"`
+	StatusPreparo     string     `json:"status_preparo"`
+	DataInicioPreparo *time.Time `json:"data_inicio_preparo"`
+	DataFimPreparo    *time.Time `json:"data_fim_preparo"`
+	DataDespacho      *time.Time `json:"data_despacho"`
+	// true quando esta mudança concluiu o último item e marcou o pedido como pronto
+	PedidoPronto bool `json:"pedido_pronto"`
+}
+
+func PedidoItemPreparoToResponse(r pgstore.UpdatePedidoItemStatusPreparoRow) PedidoItemPreparoResponse {
+	return PedidoItemPreparoResponse{
+		ID:                r.ID,
+		IDPedido:          r.IDPedido,
+		IDEstacao:         uuidToPtr(r.IDEstacao),
+		StatusPreparo:     r.StatusPreparo,
+		DataInicioPreparo: timestamptzToPtr(r.DataInicioPreparo),
+		DataFimPreparo:    timestamptzToPtr(r.DataFimPreparo),
+		DataDespacho:      timestamptzToPtr(r.DataDespacho),
+	}
+}
+
+/* ---------- Helpers ---------- */
+
+func uuidToPtr(u pgtype.UUID) *uuid.UUID {
+	if !u.Valid {
+		return nil
+	}
+	id := uuid.UUID(u.Bytes)
+	return &id
+}
+
+func timestamptzToPtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -2,12 +2,16 @@ package dto
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 
+	models "gobid/internal/models_sql_boiler"
 	"gobid/internal/store/pgstore"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/volatiletech/sqlboiler/v4/types"
 )
 
 /* ---------- DTOs de ENTRADA ---------- */
@@ -34,6 +38,172 @@ func CreateOutboxDTOToParams(in *CreateOutboxEventDTO) pgstore.CreateOutboxEvent
 	}
 }
 
+// DTO → modelo SQLBoiler (para gravar o evento dentro de uma *sql.Tx)
+func (in *CreateOutboxEventDTO) ToModel() *models.OutboxEvent {
+	return &models.OutboxEvent{
+		TenantID:      in.TenantID.String(),
+		UserID:        in.UserID.String(),
+		AggregateType: in.AggregateType,
+		AggregateID:   in.AggregateID,
+		EventType:     in.EventType,
+		Payload:       types.JSON(in.Payload),
+	}
+}
+
+/* ---------- Eventos de domínio ---------- */
+
+// Versão do schema do payload. Incremente ao mudar um campo existente
+// de forma incompatível; campos novos não exigem nova versão.
+const OutboxPayloadVersion = 1
+
+const (
+	OutboxAggregatePedido    = "pedido"
+	OutboxAggregatePagamento = "pagamento"
+	OutboxAggregateCaixa     = "caixa"
+)
+
+const (
+	EventPedidoCreated       = "pedido.created"
+	EventPedidoUpdated       = "pedido.updated"
+	EventPedidoDeleted       = "pedido.deleted"
+	EventPedidoStatusChanged = "pedido.status_changed"
+	EventPedidoPronto        = "pedido.pronto"
+	EventPedidoCancelado     = "pedido.cancelado"
+	EventPagamentoRegistered = "pagamento.registered"
+	EventPagamentoDeleted    = "pagamento.deleted"
+	EventCaixaClosed         = "caixa.closed"
+)
+
+// OutboxEventTypes lista os eventos publicados; usada para validar filtros
+// de assinaturas (webhooks).
+var OutboxEventTypes = []string{
+	EventPedidoCreated,
+	EventPedidoUpdated,
+	EventPedidoDeleted,
+	EventPedidoStatusChanged,
+	EventPedidoPronto,
+	EventPedidoCancelado,
+	EventPagamentoRegistered,
+	EventPagamentoDeleted,
+	EventCaixaClosed,
+}
+
+func IsKnownOutboxEventType(eventType string) bool {
+	return slices.Contains(OutboxEventTypes, eventType)
+}
+
+// OutboxEnvelope é o formato gravado em outbox_event.payload
+type OutboxEnvelope struct {
+	Version    int       `json:"version"`
+	EventType  string    `json:"event_type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// NewOutboxEventDTO monta o evento com o payload já envelopado
+func NewOutboxEventDTO(tenantID, userID uuid.UUID, aggregateType, aggregateID, eventType string, data any) (CreateOutboxEventDTO, error) {
+	payload, err := json.Marshal(OutboxEnvelope{
+		Version:    OutboxPayloadVersion,
+		EventType:  eventType,
+		OccurredAt: time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return CreateOutboxEventDTO{}, fmt.Errorf("falha ao serializar payload do evento %s: %w", eventType, err)
+	}
+
+	return CreateOutboxEventDTO{
+		TenantID:      tenantID,
+		UserID:        userID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       payload,
+	}, nil
+}
+
+// pedido.created | pedido.updated | pedido.deleted
+type PedidoEventPayload struct {
+	ID           string        `json:"id"`
+	CodigoPedido string        `json:"codigo_pedido"`
+	IDCliente    string        `json:"id_cliente"`
+	DataPedido   time.Time     `json:"data_pedido"`
+	TipoEntrega  string        `json:"tipo_entrega"`
+	IDStatus     int16         `json:"id_status"`
+	PedidoPronto int16         `json:"pedido_pronto"`
+	ValorTotal   types.Decimal `json:"valor_total"`
+	TaxaEntrega  types.Decimal `json:"taxa_entrega"`
+	Desconto     types.Decimal `json:"desconto"`
+	Acrescimo    types.Decimal `json:"acrescimo"`
+	QtdItens     int           `json:"qtd_itens"`
+}
+
+func PedidoEventPayloadFromModel(p *models.Pedido, qtdItens int) PedidoEventPayload {
+	return PedidoEventPayload{
+		ID:           p.ID,
+		CodigoPedido: p.CodigoPedido,
+		IDCliente:    p.IDCliente,
+		DataPedido:   p.DataPedido,
+		TipoEntrega:  p.TipoEntrega,
+		IDStatus:     p.IDStatus,
+		PedidoPronto: p.PedidoPronto,
+		ValorTotal:   p.ValorTotal,
+		TaxaEntrega:  p.TaxaEntrega,
+		Desconto:     p.Desconto,
+		Acrescimo:    p.Acrescimo,
+		QtdItens:     qtdItens,
+	}
+}
+
+// pedido.status_changed
+type PedidoStatusChangedPayload struct {
+	ID             string `json:"id"`
+	CodigoPedido   string `json:"codigo_pedido"`
+	StatusAnterior int16  `json:"status_anterior"`
+	StatusNovo     int16  `json:"status_novo"`
+}
+
+// pedido.pronto
+type PedidoProntoPayload struct {
+	ID               string     `json:"id"`
+	CodigoPedido     string     `json:"codigo_pedido"`
+	PedidoPronto     int16      `json:"pedido_pronto"`
+	DataPedidoPronto *time.Time `json:"data_pedido_pronto,omitempty"`
+}
+
+// pagamento.registered | pagamento.deleted
+type PagamentoEventPayload struct {
+	ID                 string            `json:"id"`
+	IDPedido           string            `json:"id_pedido"`
+	IDContaReceber     *string           `json:"id_conta_receber,omitempty"`
+	CategoriaPagamento *string           `json:"categoria_pagamento,omitempty"`
+	FormaPagamento     string            `json:"forma_pagamento"`
+	ValorPago          types.Decimal     `json:"valor_pago"`
+	Troco              types.NullDecimal `json:"troco"`
+}
+
+func PagamentoEventPayloadFromModel(pp *models.PedidoPagamento) PagamentoEventPayload {
+	return PagamentoEventPayload{
+		ID:                 pp.ID,
+		IDPedido:           pp.IDPedido,
+		IDContaReceber:     nullStringToPtr(pp.IDContaReceber),
+		CategoriaPagamento: nullStringToPtr(pp.CategoriaPagamento),
+		FormaPagamento:     pp.FormaPagamento,
+		ValorPago:          pp.ValorPago,
+		Troco:              pp.Troco,
+	}
+}
+
+// caixa.closed
+type CaixaClosedPayload struct {
+	ID                   uuid.UUID               `json:"id"`
+	IDOperador           uuid.UUID               `json:"id_operador"`
+	DataAbertura         time.Time               `json:"data_abertura"`
+	DataFechamento       time.Time               `json:"data_fechamento"`
+	ObservacaoFechamento string                  `json:"observacao_fechamento,omitempty"`
+	Formas               []ValorEsperadoFormaDto `json:"formas"`
+}
+
 /* ---------- DTOs de SAÍDA ---------- */
 
 type OutboxEventResponse struct {
@@ -89,6 +259,26 @@ func OutboxEventRowsToResponses(rows []pgstore.ListUnprocessedOutboxEventsForPro
 	return out
 }
 
+// lote reservado pelo dispatcher → slice de resposta
+func OutboxClaimedRowsToResponses(rows []pgstore.ClaimOutboxEventsForDispatchRow) []OutboxEventResponse {
+	out := make([]OutboxEventResponse, len(rows))
+	for i, r := range rows {
+		out[i] = OutboxEventResponse{
+			ID:            r.ID,
+			TenantID:      r.TenantID,
+			UserID:        r.UserID,
+			AggregateType: r.AggregateType,
+			AggregateID:   r.AggregateID,
+			EventType:     r.EventType,
+			Payload:       r.Payload,
+			CreatedAt:     r.CreatedAt,
+			Processed:     false,
+			Attempts:      r.Attempts,
+		}
+	}
+	return out
+}
+
 /* ---------- Helper genérico ---------- */
 
 func TextOrNull(s string) pgtype.Text {
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Intervalo entre os vencimentos das parcelas
+const (
+	IntervaloMensal  = "mensal"
+	IntervaloSemanal = "semanal"
+	IntervaloDias    = "dias"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type ParcelamentoRequest struct {
+	Parcelas           int16  `json:"parcelas"                 validate:"required,min=1,max=60"`
+	PrimeiroVencimento string `json:"primeiro_vencimento"      validate:"required,datetime=2006-01-02"`
+	Intervalo          string `json:"intervalo"                validate:"required,oneof=mensal semanal dias"`
+	// Obrigatório com intervalo "dias"
+	IntervaloDias *int `json:"intervalo_dias,omitempty" validate:"required_if=Intervalo dias,omitempty,min=1,max=365"`
+	// Valor a parcelar; sem valor, o restante do pedido menos a entrada
+	Valor *types.Decimal `json:"valor,omitempty"`
+	// Entrada paga no ato, registrada como pagamento do pedido
+	Entrada      *types.Decimal `json:"entrada,omitempty"`
+	FormaEntrada *string        `json:"forma_entrada,omitempty"  validate:"required_with=Entrada,omitempty,max=50"`
+	// Credenciais do gerente quando as parcelas ficam fora da política de crédito
+	Autorizacao *AutorizacaoGerenteDTO `json:"autorizacao,omitempty"`
+}
+
+type ParcelamentoDTO struct {
+	TenantID           uuid.UUID
+	UserID             uuid.UUID
+	IDPedido           uuid.UUID
+	Parcelas           int16
+	PrimeiroVencimento time.Time
+	Intervalo          string
+	IntervaloDias      int
+	Valor              *types.Decimal
+	Entrada            *types.Decimal
+	FormaEntrada       *string
+	AutorizadoPor      *uuid.UUID
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type ParcelaPlanoResponse struct {
+	ID         *uuid.UUID    `json:"id,omitempty"`
+	Parcela    int16         `json:"parcela"`
+	Vencimento string        `json:"vencimento"`
+	Valor      types.Decimal `json:"valor"`
+}
+
+// Plano de parcelamento: prévia ou parcelas gravadas
+type ParcelamentoResponse struct {
+	IDPedido     uuid.UUID `json:"id_pedido"`
+	CodigoPedido string    `json:"codigo_pedido"`
+	// Restante do pedido antes da entrada
+	Restante           types.Decimal          `json:"restante"`
+	Entrada            *types.Decimal         `json:"entrada,omitempty"`
+	FormaEntrada       *string                `json:"forma_entrada,omitempty"`
+	IDPagamentoEntrada *uuid.UUID             `json:"id_pagamento_entrada,omitempty"`
+	ValorParcelado     types.Decimal          `json:"valor_parcelado"`
+	Parcelas           []ParcelaPlanoResponse `json:"parcelas"`
+	Confirmado         bool                   `json:"confirmado"`
+	// Na prévia: cliente fora da política de crédito, exige gerente
+	CreditoNegado *CreditoNegadoResponse `json:"credito_negado,omitempty"`
+}
+
+func (r ParcelamentoRequest) ToDTO(tenantID, userID, idPedido uuid.UUID) ParcelamentoDTO {
+	venc, _ := time.Parse("2006-01-02", r.PrimeiroVencimento)
+	d := ParcelamentoDTO{
+		TenantID:           tenantID,
+		UserID:             userID,
+		IDPedido:           idPedido,
+		Parcelas:           r.Parcelas,
+		PrimeiroVencimento: venc,
+		Intervalo:          r.Intervalo,
+		Valor:              r.Valor,
+		Entrada:            r.Entrada,
+		FormaEntrada:       r.FormaEntrada,
+	}
+	if r.IntervaloDias != nil {
+		d.IntervaloDias = *r.IntervaloDias
+	}
+	return d
+}
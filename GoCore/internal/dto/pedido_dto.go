@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -22,15 +23,88 @@ type PedidoItemAdicionalDTO struct {
 	Quantidade       int           `json:"quantidade"         validate:"required,min=1"`
 }
 
+// Sabor de um item com mais de um sabor (pizza em frações). A fração do
+// sabor é partes / soma das partes do item; sem partes, todos valem 1.
+type PedidoItemSaborDTO struct {
+	IDProduto string `json:"id_produto"       validate:"required,uuid"`
+	Partes    int    `json:"partes,omitempty" validate:"omitempty,min=1,max=8"`
+}
+
+// Produto escolhido num slot do combo. Vira um item filho do item do combo
+// (mesma estação de produção e estatística de venda do produto), mas só
+// cobra o acréscimo do slot. Quantidade é por unidade do combo; sem ela vale 1.
+type PedidoItemComponenteDTO struct {
+	IDComboSlot      string               `json:"id_combo_slot"      validate:"required,uuid"`
+	IDCategoria      string               `json:"id_categoria"       validate:"required,uuid"`
+	IDCategoriaOpcao *string              `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
+	IDProduto        string               `json:"id_produto"         validate:"required_without=Sabores,omitempty,uuid"`
+	IDProduto2       *string              `json:"id_produto_2,omitempty" validate:"omitempty,uuid"`
+	Sabores          []PedidoItemSaborDTO `json:"sabores,omitempty"  validate:"omitempty,max=8,dive"`
+	Observacao       *string              `json:"observacao,omitempty"`
+	Quantidade       int                  `json:"quantidade,omitempty" validate:"omitempty,min=1,max=20"`
+	// Acréscimo do slot, preenchido pela cotação (AplicarCotacao)
+	ValorUnitario types.Decimal `json:"-"`
+}
+
+// Item com vários sabores: envie todos em sabores (id_produto passa a ser o
+// primeiro). O formato antigo, id_produto + id_produto_2, continua valendo
+// para meio-a-meio. Item de combo leva as escolhas em componentes.
 type PedidoItemDTO struct {
-	IDCategoria      string                   `json:"id_categoria"       validate:"required,uuid"`
-	IDCategoriaOpcao *string                  `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
-	IDProduto        string                   `json:"id_produto"         validate:"required,uuid"`
-	IDProduto2       *string                  `json:"id_produto_2,omitempty" validate:"omitempty,uuid"`
-	Observacao       *string                  `json:"observacao,omitempty"`
-	ValorUnitario    types.Decimal            `json:"valor_unitario"     validate:"required"`
-	Quantidade       int                      `json:"quantidade"         validate:"required,min=1"`
-	Adicionais       []PedidoItemAdicionalDTO `json:"adicionais"         validate:"dive"`
+	IDCategoria      string                    `json:"id_categoria"       validate:"required,uuid"`
+	IDCategoriaOpcao *string                   `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
+	IDProduto        string                    `json:"id_produto"         validate:"required_without=Sabores,omitempty,uuid"`
+	IDProduto2       *string                   `json:"id_produto_2,omitempty" validate:"omitempty,uuid"`
+	Sabores          []PedidoItemSaborDTO      `json:"sabores,omitempty"  validate:"omitempty,max=8,dive"`
+	Observacao       *string                   `json:"observacao,omitempty"`
+	ValorUnitario    types.Decimal             `json:"valor_unitario"     validate:"required"`
+	Quantidade       int                       `json:"quantidade"         validate:"required,min=1"`
+	Adicionais       []PedidoItemAdicionalDTO  `json:"adicionais"         validate:"dive"`
+	Componentes      []PedidoItemComponenteDTO `json:"componentes,omitempty" validate:"omitempty,max=20,dive"`
+}
+
+// NormalizarSabores deixa em Sabores a lista completa de sabores do item
+// (vazia para item de um sabor só) e acerta id_produto/id_produto_2. Faz o
+// mesmo com os componentes de combo, que também recebem a quantidade padrão.
+func (i *PedidoItemDTO) NormalizarSabores() {
+	i.IDProduto, i.IDProduto2, i.Sabores = normalizarSabores(i.IDProduto, i.IDProduto2, i.Sabores)
+	for k := range i.Componentes {
+		c := &i.Componentes[k]
+		c.IDProduto, c.IDProduto2, c.Sabores = normalizarSabores(c.IDProduto, c.IDProduto2, c.Sabores)
+		if c.Quantidade == 0 {
+			c.Quantidade = 1
+		}
+	}
+}
+
+// normalizarSabores converte meio-a-meio (id_produto_2) em dois sabores de
+// 1/2. Com sabores informados, id_produto é o primeiro e id_produto_2 só é
+// mantido para duas metades iguais, que é o que as telas antigas entendem.
+func normalizarSabores(idProduto string, idProduto2 *string, sabores []PedidoItemSaborDTO) (string, *string, []PedidoItemSaborDTO) {
+	if len(sabores) == 0 {
+		if idProduto2 == nil {
+			return idProduto, nil, nil
+		}
+		return idProduto, idProduto2, []PedidoItemSaborDTO{
+			{IDProduto: idProduto, Partes: 1},
+			{IDProduto: *idProduto2, Partes: 1},
+		}
+	}
+
+	for j := range sabores {
+		if sabores[j].Partes == 0 {
+			sabores[j].Partes = 1
+		}
+	}
+	idProduto = sabores[0].IDProduto
+	switch {
+	case len(sabores) == 1:
+		return idProduto, nil, nil
+	case len(sabores) == 2 && sabores[0].Partes == sabores[1].Partes:
+		segundo := sabores[1].IDProduto
+		return idProduto, &segundo, sabores
+	default:
+		return idProduto, nil, sabores
+	}
 }
 
 type PedidoStatusDTO struct {
@@ -80,6 +154,22 @@ type PedidoItemResponseDTO struct {
 	UpdatedAt        time.Time                    `json:"updated_at"`
 	DeletedAt        *time.Time                   `json:"deleted_at,omitempty"`
 	Adicionais       []PedidoItemAdicionalFullDTO `json:"adicionais"`
+	// Preenchidos nos componentes de combo: item do combo e slot atendido
+	IDItemCombo *string `json:"id_item_combo,omitempty"`
+	IDComboSlot *string `json:"id_combo_slot,omitempty"`
+	// Vazio para item de um sabor só
+	Sabores []PedidoItemSaborResponseDTO `json:"sabores"`
+}
+
+// Sabor do item (pedido_item_sabores). Fracao é partes / soma das partes,
+// ex.: "1/3"; Valor é o preço do sabor na opção do item.
+type PedidoItemSaborResponseDTO struct {
+	IDPedidoItem string            `json:"-"`
+	IDProduto    string            `json:"id_produto"`
+	ProdutoNome  string            `json:"produto_nome"`
+	Partes       int               `json:"partes"`
+	Fracao       string            `json:"fracao"`
+	Valor        types.NullDecimal `json:"valor"`
 }
 
 // DTO para os adicionais na resposta completa (com todos os campos da tabela)
@@ -114,13 +204,26 @@ type PedidoCreateDTO struct {
 	Observacao         *string            `json:"observacao,omitempty"`
 	TaxaEntrega        types.Decimal      `json:"taxa_entrega"       validate:"required"`
 	NomeTaxaEntrega    *string            `json:"nome_taxa_entrega,omitempty"`
-	IDStatus           int16              `json:"id_status"          validate:"required"`
+	IDStatus           int16              `json:"id_status,omitempty"`
 	Lat                *types.NullDecimal `json:"lat,omitempty"`
 	LNG                *types.NullDecimal `json:"lng,omitempty"`
 	TrocoPara          *types.NullDecimal `json:"troco_para,omitempty"`
 	Desconto           types.Decimal      `json:"desconto" validate:"required"`
 	Acrescimo          types.Decimal      `json:"acrescimo" validate:"required"`
 	Itens              []PedidoItemDTO    `json:"itens"              validate:"required,dive"`
+	// Pedido agendado: entra como Agendado e vai para a cozinha perto do
+	// horário. data_pedido continua sendo o momento do pedido.
+	AgendadoPara *time.Time `json:"agendado_para,omitempty"`
+	// Preenchido pelo handler com o usuário que agendou
+	AgendadoPor *string `json:"-"`
+	// Liberação do gerente para categorias fora do horário/dia de venda
+	IgnorarDisponibilidade bool `json:"ignorar_disponibilidade"`
+	// Preenchido pelo handler com o usuário que liberou a exceção
+	DisponibilidadeLiberadaPor *string `json:"-"`
+	// Cupom validado pela cotação, para registrar o resgate
+	IDCupom *string `json:"-"`
+	// Zona de entrega resolvida pela cotação
+	IDZonaEntrega *string `json:"-"`
 }
 
 type PedidoUpdateDTO struct {
@@ -128,6 +231,14 @@ type PedidoUpdateDTO struct {
 	PedidoCreateDTO
 }
 
+// NormalizarSabores normaliza os sabores de todos os itens; deve ser
+// chamado antes da precificação e de ToModels.
+func (d *PedidoCreateDTO) NormalizarSabores() {
+	for i := range d.Itens {
+		d.Itens[i].NormalizarSabores()
+	}
+}
+
 func (d *PedidoCreateDTO) Validate() error { return validate.Struct(d) }
 func (d *PedidoUpdateDTO) Validate() error { return validate.Struct(d) }
 
@@ -154,7 +265,10 @@ type PedidoResponseDTO struct {
 	Observacao         *string            `json:"observacao,omitempty"`
 	TaxaEntrega        types.Decimal      `json:"taxa_entrega"`
 	NomeTaxaEntrega    *string            `json:"nome_taxa_entrega,omitempty"`
+	IDZonaEntrega      *string            `json:"id_zona_entrega,omitempty"`
 	IDStatus           int16              `json:"id_status"`
+	AgendadoPara       *time.Time         `json:"agendado_para,omitempty"`
+	LiberadoEm         *time.Time         `json:"liberado_em,omitempty"`
 	Lat                *types.NullDecimal `json:"lat,omitempty"`
 	LNG                *types.NullDecimal `json:"lng,omitempty"`
 	CreatedAt          time.Time          `json:"created_at"`
@@ -203,6 +317,11 @@ func (d *PedidoCreateDTO) ToModels() (*models.Pedido, []*models.PedidoItem, []*m
 		TrocoPara:          derefNullDecimal(d.TrocoPara),
 		Desconto:           d.Desconto,
 		Acrescimo:          d.Acrescimo,
+
+		DisponibilidadeLiberadaPor: toNullString(d.DisponibilidadeLiberadaPor),
+		IDZonaEntrega:              toNullString(d.IDZonaEntrega),
+		AgendadoPara:               null.TimeFromPtr(d.AgendadoPara),
+		AgendadoPor:                toNullString(d.AgendadoPor),
 	}
 
 	var itens []*models.PedidoItem
@@ -218,6 +337,10 @@ func (d *PedidoCreateDTO) ToModels() (*models.Pedido, []*models.PedidoItem, []*m
 			ValorUnitario:    item.ValorUnitario,
 			Quantidade:       item.Quantidade,
 		}
+		// O combo em si não é preparado; quem vai para o KDS são os componentes
+		if len(item.Componentes) > 0 {
+			it.StatusPreparo = StatusPreparoBumped
+		}
 		itens = append(itens, it)
 
 		for _, ad := range item.Adicionais {
@@ -232,6 +355,23 @@ func (d *PedidoCreateDTO) ToModels() (*models.Pedido, []*models.PedidoItem, []*m
 	return pedido, itens, adds, nil
 }
 
+// ToModel monta o item filho do componente. A quantidade é a do componente
+// multiplicada pela do combo.
+func (c *PedidoItemComponenteDTO) ToModel(combo *models.PedidoItem) *models.PedidoItem {
+	return &models.PedidoItem{
+		IDPedido:         combo.IDPedido,
+		IDItemCombo:      null.StringFrom(combo.ID),
+		IDComboSlot:      null.StringFrom(c.IDComboSlot),
+		IDCategoria:      c.IDCategoria,
+		IDCategoriaOpcao: toNullString(c.IDCategoriaOpcao),
+		IDProduto:        c.IDProduto,
+		IDProduto2:       toNullString(c.IDProduto2),
+		Observacao:       toNullString(c.Observacao),
+		ValorUnitario:    c.ValorUnitario,
+		Quantidade:       c.Quantidade * combo.Quantidade,
+	}
+}
+
 func (d *PedidoUpdateDTO) ToModels() (*models.Pedido, []*models.PedidoItem, []*models.PedidoItemAdicional, error) {
 	p, its, ads, err := d.PedidoCreateDTO.ToModels()
 	if err != nil {
@@ -324,6 +464,9 @@ func PedidoModelToResponse(p *models.Pedido) *PedidoResponseDTO {
 				UpdatedAt:        it.UpdatedAt,
 				DeletedAt:        nullTimeToPtr(it.DeletedAt),
 				Adicionais:       adicionais,
+				IDItemCombo:      nullStringToPtr(it.IDItemCombo),
+				IDComboSlot:      nullStringToPtr(it.IDComboSlot),
+				Sabores:          []PedidoItemSaborResponseDTO{},
 			}
 			itens = append(itens, itemDTO)
 		}
@@ -351,7 +494,10 @@ func PedidoModelToResponse(p *models.Pedido) *PedidoResponseDTO {
 		Observacao:         nullStringToPtr(p.Observacao),
 		TaxaEntrega:        p.TaxaEntrega,
 		NomeTaxaEntrega:    nullStringToPtr(p.NomeTaxaEntrega),
+		IDZonaEntrega:      nullStringToPtr(p.IDZonaEntrega),
 		IDStatus:           p.IDStatus,
+		AgendadoPara:       nullTimeToPtr(p.AgendadoPara),
+		LiberadoEm:         nullTimeToPtr(p.LiberadoEm),
 		Lat:                &p.Lat,
 		LNG:                &p.LNG,
 		CreatedAt:          p.CreatedAt,
@@ -371,6 +517,40 @@ func PedidoModelToResponse(p *models.Pedido) *PedidoResponseDTO {
 	return resp
 }
 
+// AnexarSabores distribui os sabores (de um ou mais pedidos) pelos itens
+// da resposta. Os sabores ficam fora do modelo do sqlboiler.
+func (p *PedidoResponseDTO) AnexarSabores(sabores []PedidoItemSaborResponseDTO) {
+	porItem := make(map[string][]PedidoItemSaborResponseDTO)
+	totalPartes := make(map[string]int)
+	for _, s := range sabores {
+		porItem[s.IDPedidoItem] = append(porItem[s.IDPedidoItem], s)
+		totalPartes[s.IDPedidoItem] += s.Partes
+	}
+	for i := range p.Itens {
+		it := &p.Itens[i]
+		lista, ok := porItem[it.ID]
+		if !ok {
+			continue
+		}
+		for j := range lista {
+			lista[j].Fracao = fracao(lista[j].Partes, totalPartes[it.ID])
+		}
+		it.Sabores = lista
+	}
+}
+
+// fracao reduzida no formato "n/d"
+func fracao(n, d int) string {
+	a, b := n, d
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return fmt.Sprintf("%d/%d", n, d)
+	}
+	return fmt.Sprintf("%d/%d", n/a, d/a)
+}
+
 /* ---------------- utilidades ------------------------ */
 
 func toNullString(s *string) null.String {
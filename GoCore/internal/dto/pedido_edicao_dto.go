@@ -6,7 +6,8 @@ import (
 )
 
 // PedidoEdicaoResponse contém todos os dados necessários para edição de um pedido
-// Inclui o pedido completo + dados relacionados (mesmo se soft-deleted)
+// Inclui o pedido completo + dados relacionados (mesmo se soft-deleted).
+// Os produtos incluem os sabores dos itens com mais de um sabor.
 type PedidoEdicaoResponse struct {
 	Pedido     PedidoResponseDTO            `json:"pedido"`
 	Categorias []CoreCategoriaResponseDTO   `json:"categorias"` // Categorias dos produtos do pedido (incluindo soft-deleted)
@@ -20,10 +21,12 @@ func ConvertPedidoToEdicaoResponse(
 	categorias models_sql_boiler.CategoriaSlice,
 	produtos models_sql_boiler.ProdutoSlice,
 	adicionais models_sql_boiler.CategoriaAdicionalSlice,
+	sabores []PedidoItemSaborResponseDTO,
 ) PedidoEdicaoResponse {
 
 	// Converter pedido
 	pedidoDTO := PedidoModelToResponse(pedido)
+	pedidoDTO.AnexarSabores(sabores)
 
 	// Converter categorias (reutilizando DTO existente)
 	categoriasDTO := make([]CoreCategoriaResponseDTO, len(categorias))
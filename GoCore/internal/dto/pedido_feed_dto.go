@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"encoding/json"
+	"gobid/internal/store/pgstore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PedidoFeedEvent é o evento publicado pelo trigger trg_pedidos_notify_feed,
+// tanto no NOTIFY quanto na tabela pedido_feed.
+type PedidoFeedEvent struct {
+	ID        int64           `json:"id"`
+	TenantID  uuid.UUID       `json:"tenant_id"`
+	PedidoID  uuid.UUID       `json:"pedido_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func PedidoFeedToEvent(f pgstore.PedidoFeed) PedidoFeedEvent {
+	return PedidoFeedEvent{
+		ID:        f.ID,
+		TenantID:  f.TenantID,
+		PedidoID:  f.PedidoID,
+		EventType: f.EventType,
+		Payload:   f.Payload,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func PedidoFeedRowsToEvents(rows []pgstore.PedidoFeed) []PedidoFeedEvent {
+	out := make([]PedidoFeedEvent, len(rows))
+	for i, r := range rows {
+		out[i] = PedidoFeedToEvent(r)
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package dto
+
+import (
+	"gobid/internal/store/pgstore"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Valores de pedido_status
+const (
+	PedidoStatusConfirmado   int16 = 1
+	PedidoStatusEmPreparacao int16 = 2
+	PedidoStatusPronto       int16 = 3
+	PedidoStatusSaiuEntrega  int16 = 4
+	PedidoStatusConcluido    int16 = 5
+	PedidoStatusCancelado    int16 = 6
+	PedidoStatusAgendado     int16 = 7
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type UpdatePedidoStatusDTO struct {
+	IDStatus int16   `json:"id_status" validate:"required"`
+	Motivo   *string `json:"motivo,omitempty" validate:"omitempty,max=500"`
+}
+
+type AlterarStatusPedidoDTO struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	IDPedido uuid.UUID
+	IDStatus int16
+	Motivo   *string
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type PedidoStatusHistoricoResponse struct {
+	ID                      string     `json:"id"`
+	StatusAnterior          *int16     `json:"status_anterior"`
+	StatusAnteriorDescricao *string    `json:"status_anterior_descricao"`
+	StatusNovo              int16      `json:"status_novo"`
+	StatusNovoDescricao     string     `json:"status_novo_descricao"`
+	UserID                  *uuid.UUID `json:"user_id"`
+	UserName                *string    `json:"user_name"`
+	Motivo                  *string    `json:"motivo"`
+	CreatedAt               time.Time  `json:"created_at"`
+	// Tempo no status até a próxima transição; nulo no status atual
+	DuracaoSegundos *int64 `json:"duracao_segundos"`
+}
+
+type PedidoHistoricoResponse struct {
+	IDPedido     string                          `json:"id_pedido"`
+	CodigoPedido string                          `json:"codigo_pedido"`
+	StatusAtual  int16                           `json:"status_atual"`
+	Historico    []PedidoStatusHistoricoResponse `json:"historico"`
+	// Em preparação → Pronto
+	TempoPreparoSegundos *int64 `json:"tempo_preparo_segundos"`
+	// Saiu para entrega → Concluído
+	TempoEntregaSegundos *int64 `json:"tempo_entrega_segundos"`
+	// Criação → Concluído/Cancelado
+	TempoTotalSegundos *int64 `json:"tempo_total_segundos"`
+}
+
+func PedidoStatusHistoricoToResponse(h pgstore.PedidoStatusHistorico, anterior *string, novo string) PedidoStatusHistoricoResponse {
+	return PedidoStatusHistoricoResponse{
+		ID:                      h.ID.String(),
+		StatusAnterior:          int2ToPtr(h.StatusAnterior),
+		StatusAnteriorDescricao: anterior,
+		StatusNovo:              h.StatusNovo,
+		StatusNovoDescricao:     novo,
+		UserID:                  uuidToPtr(h.UserID),
+		Motivo:                  textToPtr(h.Motivo),
+		CreatedAt:               h.CreatedAt,
+	}
+}
+
+// PedidoHistoricoToResponse monta a linha do tempo e calcula as durações
+// a partir das primeiras entradas em cada status.
+func PedidoHistoricoToResponse(p pgstore.GetPedidoStatusAtualRow, rows []pgstore.ListPedidoStatusHistoricoRow) PedidoHistoricoResponse {
+	out := PedidoHistoricoResponse{
+		IDPedido:     p.ID.String(),
+		CodigoPedido: p.CodigoPedido,
+		StatusAtual:  p.IDStatus,
+		Historico:    make([]PedidoStatusHistoricoResponse, len(rows)),
+	}
+
+	entrada := make(map[int16]time.Time)
+	for i, r := range rows {
+		h := PedidoStatusHistoricoResponse{
+			ID:                      r.ID.String(),
+			StatusAnterior:          int2ToPtr(r.StatusAnterior),
+			StatusAnteriorDescricao: textToPtr(r.StatusAnteriorDescricao),
+			StatusNovo:              r.StatusNovo,
+			StatusNovoDescricao:     r.StatusNovoDescricao,
+			UserID:                  uuidToPtr(r.UserID),
+			UserName:                textToPtr(r.UserName),
+			Motivo:                  textToPtr(r.Motivo),
+			CreatedAt:               r.CreatedAt,
+		}
+		if i+1 < len(rows) {
+			d := int64(rows[i+1].CreatedAt.Sub(r.CreatedAt).Seconds())
+			h.DuracaoSegundos = &d
+		}
+		if _, ok := entrada[r.StatusNovo]; !ok {
+			entrada[r.StatusNovo] = r.CreatedAt
+		}
+		out.Historico[i] = h
+	}
+
+	out.TempoPreparoSegundos = intervalo(entrada, PedidoStatusEmPreparacao, PedidoStatusPronto)
+	out.TempoEntregaSegundos = intervalo(entrada, PedidoStatusSaiuEntrega, PedidoStatusConcluido)
+	if len(rows) > 0 {
+		inicio := rows[0].CreatedAt
+		for _, fim := range []int16{PedidoStatusConcluido, PedidoStatusCancelado} {
+			if t, ok := entrada[fim]; ok {
+				d := int64(t.Sub(inicio).Seconds())
+				out.TempoTotalSegundos = &d
+				break
+			}
+		}
+	}
+	return out
+}
+
+func intervalo(entrada map[int16]time.Time, de, ate int16) *int64 {
+	inicio, ok1 := entrada[de]
+	fim, ok2 := entrada[ate]
+	if !ok1 || !ok2 || fim.Before(inicio) {
+		return nil
+	}
+	d := int64(fim.Sub(inicio).Seconds())
+	return &d
+}
+
+func int2ToPtr(v pgtype.Int2) *int16 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int16
+}
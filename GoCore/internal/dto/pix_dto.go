@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Dados do recebedor gravados no tenant. Nome e cidade são gravados sem
+// acentos, como vão no BR Code.
+type PixConfigDTO struct {
+	Chave         string `json:"chave"          validate:"required,max=77"`
+	NomeRecebedor string `json:"nome_recebedor" validate:"required,max=60"`
+	Cidade        string `json:"cidade"         validate:"required,max=60"`
+}
+
+// Recebimento conferido no extrato do banco
+type PixConfirmacaoRequest struct {
+	TxID  string        `json:"txid"             validate:"required,alphanum,max=25"`
+	Valor types.Decimal `json:"valor"            validate:"required"`
+	// Identificador fim a fim (E2E) da transferência; confirmar de novo a
+	// mesma devolve o pagamento já gravado. Sem ele, a repetição é pelo
+	// txid e valor: duas transferências iguais no mesmo pedido exigem o E2E.
+	E2EID *string `json:"e2e_id,omitempty" validate:"omitempty,alphanum,len=32"`
+}
+
+type PixConfirmacaoDTO struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	IDPedido uuid.UUID
+	TxID     string
+	Valor    types.Decimal
+	E2EID    *string
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type PixConfigResponse struct {
+	Configurado   bool    `json:"configurado"`
+	Chave         *string `json:"chave"`
+	NomeRecebedor *string `json:"nome_recebedor"`
+	Cidade        *string `json:"cidade"`
+}
+
+// Cobrança PIX: o payload é o "copia e cola" do QR Code
+type PixCobrancaResponse struct {
+	IDPedido     *uuid.UUID     `json:"id_pedido,omitempty"`
+	CodigoPedido string         `json:"codigo_pedido,omitempty"`
+	TxID         string         `json:"txid"`
+	Valor        *types.Decimal `json:"valor"`
+	Payload      string         `json:"payload"`
+}
+
+type PixConfirmacaoResponse struct {
+	IDPagamento uuid.UUID     `json:"id_pagamento"`
+	IDPedido    uuid.UUID     `json:"id_pedido"`
+	TxID        string        `json:"txid"`
+	Valor       types.Decimal `json:"valor"`
+	Observacao  string        `json:"observacao"`
+	CreatedAt   time.Time     `json:"created_at"`
+	// Confirmação repetida: o pagamento já existia e nada foi gravado
+	JaConfirmado bool `json:"ja_confirmado"`
+}
+
+func PixConfigToResponse(r pgstore.GetTenantPixRow) PixConfigResponse {
+	return PixConfigResponse{
+		Configurado:   r.PixChave.Valid && r.PixNomeRecebedor.Valid && r.PixCidade.Valid,
+		Chave:         textToPtr(r.PixChave),
+		NomeRecebedor: textToPtr(r.PixNomeRecebedor),
+		Cidade:        textToPtr(r.PixCidade),
+	}
+}
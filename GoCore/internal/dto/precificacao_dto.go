@@ -0,0 +1,309 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Valores monetários informados pelo cliente são opcionais na cotação; quando
+// presentes, são comparados com os calculados e as diferenças são devolvidas
+// em divergencias.
+type CotacaoAdicionalDTO struct {
+	IDAdicionalOpcao string         `json:"id_adicional_opcao" validate:"required,uuid"`
+	Quantidade       int            `json:"quantidade"         validate:"required,min=1"`
+	Valor            *types.Decimal `json:"valor,omitempty"`
+}
+
+// Escolha num slot de combo; ver PedidoItemComponenteDTO
+type CotacaoComponenteDTO struct {
+	IDComboSlot      string               `json:"id_combo_slot"                validate:"required,uuid"`
+	IDCategoria      string               `json:"id_categoria"                 validate:"required,uuid"`
+	IDCategoriaOpcao *string              `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
+	IDProduto        string               `json:"id_produto"                   validate:"required_without=Sabores,omitempty,uuid"`
+	IDProduto2       *string              `json:"id_produto_2,omitempty"       validate:"omitempty,uuid"`
+	Sabores          []PedidoItemSaborDTO `json:"sabores,omitempty"            validate:"omitempty,max=8,dive"`
+	Quantidade       int                  `json:"quantidade,omitempty"         validate:"omitempty,min=1,max=20"`
+	ValorUnitario    *types.Decimal       `json:"valor_unitario,omitempty"`
+}
+
+type CotacaoItemDTO struct {
+	IDCategoria      string                 `json:"id_categoria"                 validate:"required,uuid"`
+	IDCategoriaOpcao *string                `json:"id_categoria_opcao,omitempty" validate:"omitempty,uuid"`
+	IDProduto        string                 `json:"id_produto"                   validate:"required_without=Sabores,omitempty,uuid"`
+	IDProduto2       *string                `json:"id_produto_2,omitempty"       validate:"omitempty,uuid"`
+	Sabores          []PedidoItemSaborDTO   `json:"sabores,omitempty"            validate:"omitempty,max=8,dive"`
+	Quantidade       int                    `json:"quantidade"                   validate:"required,min=1"`
+	ValorUnitario    *types.Decimal         `json:"valor_unitario,omitempty"`
+	Adicionais       []CotacaoAdicionalDTO  `json:"adicionais"                   validate:"dive"`
+	Componentes      []CotacaoComponenteDTO `json:"componentes,omitempty"        validate:"omitempty,max=20,dive"`
+}
+
+// NormalizarSabores: ver PedidoItemDTO.NormalizarSabores
+func (i *CotacaoItemDTO) NormalizarSabores() {
+	i.IDProduto, i.IDProduto2, i.Sabores = normalizarSabores(i.IDProduto, i.IDProduto2, i.Sabores)
+	for k := range i.Componentes {
+		c := &i.Componentes[k]
+		c.IDProduto, c.IDProduto2, c.Sabores = normalizarSabores(c.IDProduto, c.IDProduto2, c.Sabores)
+		if c.Quantidade == 0 {
+			c.Quantidade = 1
+		}
+	}
+}
+
+type PedidoCotacaoDTO struct {
+	TenantID    uuid.UUID        `json:"-"`
+	TaxaEntrega *types.Decimal   `json:"taxa_entrega,omitempty"`
+	Desconto    *types.Decimal   `json:"desconto,omitempty"`
+	Acrescimo   *types.Decimal   `json:"acrescimo,omitempty"`
+	ValorTotal  *types.Decimal   `json:"valor_total,omitempty"`
+	Itens       []CotacaoItemDTO `json:"itens" validate:"required,min=1,dive"`
+	// Com cupom o desconto é o do cupom. Sem id_cliente o limite por
+	// cliente não é conferido.
+	Cupom     *string `json:"cupom,omitempty"      validate:"omitempty,max=40"`
+	IDCliente *string `json:"id_cliente,omitempty" validate:"omitempty,uuid"`
+	// Pedido em edição: o resgate dele não conta nos limites do cupom
+	IDPedido uuid.UUID `json:"-"`
+	// Delivery com zonas cadastradas: a taxa é a da zona do endereço. Sem
+	// cep/bairro vale o cadastro do cliente; raio e polígono usam lat/lng.
+	TipoEntrega string   `json:"tipo_entrega,omitempty" validate:"omitempty,oneof=Delivery Retirada Balcão"`
+	Cep         *string  `json:"cep,omitempty"`
+	Bairro      *string  `json:"bairro,omitempty"`
+	Lat         *float64 `json:"lat,omitempty"          validate:"omitempty,latitude"`
+	Lng         *float64 `json:"lng,omitempty"          validate:"omitempty,longitude"`
+}
+
+// ToCotacaoDTO monta a cotação a partir do pedido enviado pelo cliente,
+// levando os valores informados para comparação. idPedido é o pedido em
+// edição (uuid.Nil na criação).
+func (d *PedidoCreateDTO) ToCotacaoDTO(tenantID, idPedido uuid.UUID) PedidoCotacaoDTO {
+	out := PedidoCotacaoDTO{
+		TenantID:    tenantID,
+		TaxaEntrega: &d.TaxaEntrega,
+		Desconto:    &d.Desconto,
+		Acrescimo:   &d.Acrescimo,
+		ValorTotal:  &d.ValorTotal,
+		Itens:       make([]CotacaoItemDTO, len(d.Itens)),
+		Cupom:       d.Cupom,
+		IDCliente:   &d.IDCliente,
+		IDPedido:    idPedido,
+		TipoEntrega: d.TipoEntrega,
+		Lat:         nullDecimalToFloat(d.Lat),
+		Lng:         nullDecimalToFloat(d.LNG),
+	}
+	for i := range d.Itens {
+		item := &d.Itens[i]
+		ci := CotacaoItemDTO{
+			IDCategoria:      item.IDCategoria,
+			IDCategoriaOpcao: item.IDCategoriaOpcao,
+			IDProduto:        item.IDProduto,
+			IDProduto2:       item.IDProduto2,
+			Sabores:          item.Sabores,
+			Quantidade:       item.Quantidade,
+			ValorUnitario:    &item.ValorUnitario,
+			Adicionais:       make([]CotacaoAdicionalDTO, len(item.Adicionais)),
+		}
+		for j := range item.Adicionais {
+			ci.Adicionais[j] = CotacaoAdicionalDTO{
+				IDAdicionalOpcao: item.Adicionais[j].IDAdicionalOpcao,
+				Quantidade:       item.Adicionais[j].Quantidade,
+				Valor:            &item.Adicionais[j].Valor,
+			}
+		}
+		for _, comp := range item.Componentes {
+			ci.Componentes = append(ci.Componentes, CotacaoComponenteDTO{
+				IDComboSlot:      comp.IDComboSlot,
+				IDCategoria:      comp.IDCategoria,
+				IDCategoriaOpcao: comp.IDCategoriaOpcao,
+				IDProduto:        comp.IDProduto,
+				IDProduto2:       comp.IDProduto2,
+				Sabores:          comp.Sabores,
+				Quantidade:       comp.Quantidade,
+			})
+		}
+		out.Itens[i] = ci
+	}
+	return out
+}
+
+// AplicarCotacao substitui os valores enviados pelo cliente pelos calculados.
+// A cotação deve ter sido gerada a partir deste mesmo DTO (mesma ordem).
+func (d *PedidoCreateDTO) AplicarCotacao(c PedidoCotacaoResponse) {
+	for i := range d.Itens {
+		d.Itens[i].ValorUnitario = c.Itens[i].ValorUnitario
+		for j := range d.Itens[i].Adicionais {
+			d.Itens[i].Adicionais[j].Valor = c.Itens[i].Adicionais[j].ValorUnitario
+		}
+		for k := range d.Itens[i].Componentes {
+			comp := &d.Itens[i].Componentes[k]
+			comp.ValorUnitario = c.Itens[i].Componentes[k].ValorUnitario
+			// a opção fixa do slot vale mesmo quando não foi enviada
+			if comp.IDCategoriaOpcao == nil {
+				opcao := c.Itens[i].Componentes[k].IDCategoriaOpcao
+				comp.IDCategoriaOpcao = &opcao
+			}
+		}
+	}
+	d.ValorTotal = c.ValorTotal
+	d.TaxaEntrega = c.TaxaEntrega
+	d.Desconto = c.Desconto
+	d.Acrescimo = c.Acrescimo
+
+	// Grava o código como cadastrado; cupom em branco vira nulo
+	d.Cupom, d.IDCupom = nil, nil
+	if c.Cupom != nil {
+		codigo, id := c.Cupom.Codigo, c.Cupom.ID.String()
+		d.Cupom, d.IDCupom = &codigo, &id
+	}
+
+	// Zona de entrega: nome e prazos sugeridos quando não informados
+	d.IDZonaEntrega = nil
+	if c.Entrega != nil {
+		id, nome := c.Entrega.IDZonaEntrega.String(), c.Entrega.Nome
+		d.IDZonaEntrega, d.NomeTaxaEntrega = &id, &nome
+		if d.PrazoMin == nil && c.Entrega.PrazoMin != nil {
+			v := int(*c.Entrega.PrazoMin)
+			d.PrazoMin = &v
+		}
+		if d.PrazoMax == nil && c.Entrega.PrazoMax != nil {
+			v := int(*c.Entrega.PrazoMax)
+			d.PrazoMax = &v
+		}
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type CotacaoAdicionalResponse struct {
+	IDAdicionalOpcao string        `json:"id_adicional_opcao"`
+	Nome             string        `json:"nome"`
+	Quantidade       int           `json:"quantidade"`
+	ValorUnitario    types.Decimal `json:"valor_unitario"`
+	ValorTotal       types.Decimal `json:"valor_total"` // valor_unitario × quantidade (por unidade do item)
+}
+
+// Sabor com o preço na opção do item
+type CotacaoSaborResponse struct {
+	IDProduto     string        `json:"id_produto"`
+	ProdutoNome   string        `json:"produto_nome"`
+	Partes        int           `json:"partes"`
+	ValorUnitario types.Decimal `json:"valor_unitario"`
+}
+
+// Componente do combo com o acréscimo do slot
+type CotacaoComponenteResponse struct {
+	IDComboSlot      string                 `json:"id_combo_slot"`
+	SlotNome         string                 `json:"slot_nome"`
+	IDProduto        string                 `json:"id_produto"`
+	ProdutoNome      string                 `json:"produto_nome"`
+	IDCategoriaOpcao string                 `json:"id_categoria_opcao"`
+	Sabores          []CotacaoSaborResponse `json:"sabores,omitempty"`
+	Quantidade       int                    `json:"quantidade"` // por unidade do combo
+	ValorUnitario    types.Decimal          `json:"valor_unitario"`
+	ValorTotal       types.Decimal          `json:"valor_total"` // valor_unitario × quantidade (por unidade do combo)
+}
+
+type CotacaoItemResponse struct {
+	Indice           int    `json:"indice"`
+	IDProduto        string `json:"id_produto"`
+	ProdutoNome      string `json:"produto_nome"`
+	IDProduto2       string `json:"id_produto_2,omitempty"`
+	Produto2Nome     string `json:"produto_2_nome,omitempty"`
+	IDCategoriaOpcao string `json:"id_categoria_opcao"`
+	// Regra aplicada com mais de um sabor: M = média, V = maior valor,
+	// P = proporcional à fração
+	RegraMeia  string                 `json:"regra_meia,omitempty"`
+	Sabores    []CotacaoSaborResponse `json:"sabores,omitempty"`
+	Quantidade int                    `json:"quantidade"`
+	// Preço do produto (ou dos sabores) sem adicionais; no combo, o do pacote
+	ValorUnitario    types.Decimal `json:"valor_unitario"`
+	PrecoPromocional bool          `json:"preco_promocional"`
+	ValorAdicionais  types.Decimal `json:"valor_adicionais"` // adicionais por unidade
+	// Acréscimos dos componentes por unidade; zero fora de combos
+	ValorComponentes types.Decimal `json:"valor_componentes"`
+	// (valor_unitario + valor_adicionais + valor_componentes) × quantidade
+	ValorTotal  types.Decimal               `json:"valor_total"`
+	Adicionais  []CotacaoAdicionalResponse  `json:"adicionais"`
+	Componentes []CotacaoComponenteResponse `json:"componentes,omitempty"`
+}
+
+// Cupom aplicado: base é a soma dos itens elegíveis (todos, sem restrição
+// de categoria/produto); no frete grátis, a taxa de entrega
+type CotacaoCupomResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Codigo    string        `json:"codigo"`
+	Descricao *string       `json:"descricao,omitempty"`
+	Tipo      string        `json:"tipo"`
+	Base      types.Decimal `json:"base"`
+	Desconto  types.Decimal `json:"desconto"`
+}
+
+// Diferença entre um valor informado pelo cliente e o calculado
+type PrecoDivergencia struct {
+	Campo     string        `json:"campo"`
+	Item      *int          `json:"item,omitempty"`
+	Adicional *int          `json:"adicional,omitempty"`
+	Informado types.Decimal `json:"informado"`
+	Calculado types.Decimal `json:"calculado"`
+}
+
+// Códigos de CotacaoProblema, estáveis para a interface tratar cada caso
+const (
+	ProblemaNaoEncontrado       = "nao_encontrado"
+	ProblemaCategoriaDivergente = "categoria_divergente"
+	ProblemaIndisponivel        = "indisponivel"
+	ProblemaSemPreco            = "sem_preco"
+	ProblemaOpcaoObrigatoria    = "opcao_obrigatoria"
+	ProblemaMeiaInvalida        = "meia_invalida"
+	ProblemaSaboresExcedidos    = "sabores_excedidos"
+	ProblemaValorNegativo       = "valor_negativo"
+	ProblemaDescontoExcedeTotal = "desconto_excede_total"
+	// Combos (combo_slots)
+	ProblemaNaoCombo        = "nao_combo"
+	ProblemaComboSlot       = "combo_slot_invalido"
+	ProblemaComboIncompleto = "combo_incompleto"
+	// Cupons (cupons)
+	ProblemaCupomInvalido      = "cupom_invalido"
+	ProblemaCupomExpirado      = "cupom_expirado"
+	ProblemaCupomEsgotado      = "cupom_esgotado"
+	ProblemaCupomLimiteCliente = "cupom_limite_cliente"
+	ProblemaCupomValorMinimo   = "cupom_valor_minimo"
+	ProblemaCupomSemDesconto   = "cupom_sem_desconto"
+	// Zonas de entrega (zonas_entrega)
+	ProblemaEnderecoIncompleto = "endereco_incompleto"
+	ProblemaForaDaArea         = "fora_da_area"
+	ProblemaEntregaValorMinimo = "entrega_valor_minimo"
+	// Regras dos grupos de adicionais (categoria_adicionais)
+	ProblemaGrupoObrigatorio = "grupo_obrigatorio"
+	ProblemaGrupoUnico       = "grupo_selecao_unica"
+	ProblemaGrupoQuantidade  = "grupo_quantidade_invalida"
+	ProblemaGrupoMinimo      = "grupo_minimo"
+	ProblemaGrupoLimite      = "grupo_limite"
+)
+
+// Motivo pelo qual um item não pôde ser precificado
+type CotacaoProblema struct {
+	Item      *int `json:"item,omitempty"`
+	Adicional *int `json:"adicional,omitempty"`
+	// Grupo de adicionais (categoria_adicionais) violado, quando for o caso
+	IDCategoriaAdicional string `json:"id_categoria_adicional,omitempty"`
+	Campo                string `json:"campo"`
+	Codigo               string `json:"codigo"`
+	Mensagem             string `json:"mensagem"`
+}
+
+type PedidoCotacaoResponse struct {
+	Itens []CotacaoItemResponse `json:"itens"`
+	// Soma dos itens; é o valor gravado em pedidos.valor_total
+	ValorTotal  types.Decimal `json:"valor_total"`
+	TaxaEntrega types.Decimal `json:"taxa_entrega"`
+	Desconto    types.Decimal `json:"desconto"`
+	Acrescimo   types.Decimal `json:"acrescimo"`
+	// valor_total + taxa_entrega + acrescimo - desconto
+	TotalAPagar types.Decimal         `json:"total_a_pagar"`
+	Cupom       *CotacaoCupomResponse `json:"cupom,omitempty"`
+	// Zona que definiu a taxa de entrega (Delivery com zonas cadastradas)
+	Entrega      *EntregaResolvidaResponse `json:"entrega,omitempty"`
+	Divergencias []PrecoDivergencia        `json:"divergencias"`
+}
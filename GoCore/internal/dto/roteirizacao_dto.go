@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/geoutils"
+
+	"github.com/google/uuid"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Parâmetros do tempo estimado de cada rota. Sem valores, vale 25 km/h,
+// 3 minutos por parada e saída agora.
+type ParametrosRotaDTO struct {
+	VelocidadeMediaKmh *float64   `json:"velocidade_media_kmh,omitempty" validate:"omitempty,gt=0,max=120"`
+	MinutosPorParada   *int32     `json:"minutos_por_parada,omitempty"   validate:"omitempty,min=0,max=60"`
+	SaidaEm            *time.Time `json:"saida_em,omitempty"`
+}
+
+// Planejamento de rotas. Sem pedidos, entram todos os Delivery prontos fora
+// de rota; com pedidos, também os ainda em preparo.
+type PlanejamentoEntregaDTO struct {
+	ParametrosRotaDTO
+	Pedidos           []string `json:"pedidos,omitempty"              validate:"omitempty,max=100,dive,uuid"`
+	MaxPedidosPorRota *int32   `json:"max_pedidos_por_rota,omitempty" validate:"omitempty,min=1,max=20"`
+	// Distância máxima entre um pedido e a parada mais próxima da rota para
+	// entrar nela
+	RaioAgrupamentoKm *float64 `json:"raio_agrupamento_km,omitempty" validate:"omitempty,gt=0,max=50"`
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type ParadaPlanejadaResponse struct {
+	Ordem        int32     `json:"ordem"`
+	IDPedido     uuid.UUID `json:"id_pedido"`
+	CodigoPedido string    `json:"codigo_pedido"`
+	ClienteNome  string    `json:"cliente_nome"`
+	Bairro       *string   `json:"bairro,omitempty"`
+	Lat          float64   `json:"lat"`
+	Lng          float64   `json:"lng"`
+	// Distância desde a parada anterior (ou da loja, na primeira)
+	DistanciaKm     float64    `json:"distancia_km"`
+	ChegadaPrevista time.Time  `json:"chegada_prevista"`
+	Prazo           *time.Time `json:"prazo,omitempty"`
+	// Minutos entre a chegada prevista e o prazo; negativo é atraso
+	FolgaMin *int32 `json:"folga_min,omitempty"`
+	Atrasado bool   `json:"atrasado"`
+}
+
+type RotaPlanejadaResponse struct {
+	// Pedidos na ordem sugerida, prontos para POST /rotas-entrega
+	Pedidos     []uuid.UUID               `json:"pedidos"`
+	Paradas     []ParadaPlanejadaResponse `json:"paradas"`
+	DistanciaKm float64                   `json:"distancia_km"`
+	RetornoKm   float64                   `json:"retorno_km"`
+	// Da saída até a última entrega
+	DuracaoMin int32 `json:"duracao_min"`
+	Atrasados  int   `json:"atrasados"`
+}
+
+type PedidoSemCoordenadasResponse struct {
+	IDPedido     uuid.UUID `json:"id_pedido"`
+	CodigoPedido string    `json:"codigo_pedido"`
+	ClienteNome  string    `json:"cliente_nome"`
+}
+
+type PlanejamentoEntregaResponse struct {
+	Origem  geoutils.Ponto          `json:"origem"`
+	SaidaEm time.Time               `json:"saida_em"`
+	Rotas   []RotaPlanejadaResponse `json:"rotas"`
+	// Pedidos que o planejador não consegue posicionar
+	SemCoordenadas []PedidoSemCoordenadasResponse `json:"sem_coordenadas"`
+}
+
+type RotaOtimizadaResponse struct {
+	Rota  RotaEntregaResponse   `json:"rota"`
+	Plano RotaPlanejadaResponse `json:"plano"`
+}
@@ -0,0 +1,163 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Taxa da adquirente para uma forma de pagamento. Parcelas só no crédito;
+// sem valor, 1 (à vista). Cada parcelamento aceito no crédito precisa da sua
+// taxa: o parcelado não usa a do à vista, e o repasse dele é previsto numa
+// data só (antecipação integral em D+dias_repasse).
+type TaxaAdquirenteDTO struct {
+	IDFormaPagamento int16          `json:"id_forma_pagamento" validate:"required,min=1"`
+	Parcelas         int16          `json:"parcelas"           validate:"omitempty,min=1,max=24"`
+	TaxaPercentual   *types.Decimal `json:"taxa_percentual"    validate:"required"`
+	TaxaFixa         *types.Decimal `json:"taxa_fixa,omitempty"`
+	DiasRepasse      int32          `json:"dias_repasse"       validate:"min=0,max=365"`
+}
+
+func (d TaxaAdquirenteDTO) ToUpsertParams(tenantID uuid.UUID) pgstore.UpsertTaxaAdquirenteParams {
+	parcelas := d.Parcelas
+	if parcelas == 0 {
+		parcelas = 1
+	}
+	return pgstore.UpsertTaxaAdquirenteParams{
+		TenantID:         tenantID,
+		IDFormaPagamento: d.IDFormaPagamento,
+		Parcelas:         parcelas,
+		TaxaPercentual:   decimalutils.CentavosToNumeric(decimalPtrToCentavos(d.TaxaPercentual)),
+		TaxaFixa:         decimalutils.CentavosToNumeric(decimalPtrToCentavos(d.TaxaFixa)),
+		DiasRepasse:      d.DiasRepasse,
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type TaxaAdquirenteResponse struct {
+	ID               uuid.UUID     `json:"id"`
+	IDFormaPagamento int16         `json:"id_forma_pagamento"`
+	Codigo           string        `json:"codigo"`
+	Nome             string        `json:"nome"`
+	Parcelas         int16         `json:"parcelas"`
+	TaxaPercentual   types.Decimal `json:"taxa_percentual"`
+	TaxaFixa         types.Decimal `json:"taxa_fixa"`
+	DiasRepasse      int32         `json:"dias_repasse"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// Valores de um grupo de pagamentos: bruto, taxa da adquirente e o
+// líquido que cai na conta
+type RecebivelValoresResponse struct {
+	Pagamentos   int32         `json:"pagamentos"`
+	ValorBruto   types.Decimal `json:"valor_bruto"`
+	Taxa         types.Decimal `json:"taxa"`
+	ValorLiquido types.Decimal `json:"valor_liquido"`
+}
+
+type RecebivelFormaResponse struct {
+	FormaPagamento string `json:"forma_pagamento"`
+	RecebivelValoresResponse
+}
+
+// Depósitos previstos num dia
+type RecebivelDiaResponse struct {
+	Data string `json:"data"`
+	RecebivelValoresResponse
+	Formas []RecebivelFormaResponse `json:"formas"`
+}
+
+// Calendário de recebíveis: o que a adquirente deposita, por dia
+type CalendarioRecebiveisResponse struct {
+	Inicio string                   `json:"inicio"`
+	Fim    string                   `json:"fim"`
+	Totais RecebivelValoresResponse `json:"totais"`
+	Dias   []RecebivelDiaResponse   `json:"dias"`
+}
+
+func TaxaAdquirenteToResponse(r pgstore.ListTaxasAdquirenteRow) TaxaAdquirenteResponse {
+	return TaxaAdquirenteResponse{
+		ID:               r.ID,
+		IDFormaPagamento: r.IDFormaPagamento,
+		Codigo:           r.Codigo,
+		Nome:             r.Nome,
+		Parcelas:         r.Parcelas,
+		TaxaPercentual:   numericToDecimal(r.TaxaPercentual),
+		TaxaFixa:         numericToDecimal(r.TaxaFixa),
+		DiasRepasse:      r.DiasRepasse,
+		UpdatedAt:        r.UpdatedAt,
+	}
+}
+
+// TaxaAdquirenteSalvaToResponse completa a taxa gravada com a forma
+func TaxaAdquirenteSalvaToResponse(t pgstore.TaxasAdquirente, forma pgstore.GetFormaPagamentoRow) TaxaAdquirenteResponse {
+	return TaxaAdquirenteResponse{
+		ID:               t.ID,
+		IDFormaPagamento: t.IDFormaPagamento,
+		Codigo:           forma.Codigo,
+		Nome:             forma.Nome,
+		Parcelas:         t.Parcelas,
+		TaxaPercentual:   numericToDecimal(t.TaxaPercentual),
+		TaxaFixa:         numericToDecimal(t.TaxaFixa),
+		DiasRepasse:      t.DiasRepasse,
+		UpdatedAt:        t.UpdatedAt,
+	}
+}
+
+// CalendarioRecebiveisToResponse agrupa as linhas (dia, forma) por dia e
+// soma os totais do período
+func CalendarioRecebiveisToResponse(rows []pgstore.ListCalendarioRecebiveisRow, inicio, fim time.Time) CalendarioRecebiveisResponse {
+	type soma struct {
+		pagamentos           int32
+		bruto, taxa, liquido int64
+	}
+	valores := func(s soma) RecebivelValoresResponse {
+		return RecebivelValoresResponse{
+			Pagamentos:   s.pagamentos,
+			ValorBruto:   decimalutils.FromCentavos(s.bruto),
+			Taxa:         decimalutils.FromCentavos(s.taxa),
+			ValorLiquido: decimalutils.FromCentavos(s.liquido),
+		}
+	}
+
+	out := CalendarioRecebiveisResponse{
+		Inicio: inicio.Format("2006-01-02"),
+		Fim:    fim.Format("2006-01-02"),
+		Dias:   []RecebivelDiaResponse{},
+	}
+	var total, dia soma
+	for i, r := range rows {
+		bruto, _ := decimalutils.NumericToCentavos(r.ValorBruto)
+		taxa, _ := decimalutils.NumericToCentavos(r.Taxa)
+		liquido, _ := decimalutils.NumericToCentavos(r.ValorLiquido)
+		linha := soma{pagamentos: r.Pagamentos, bruto: bruto, taxa: taxa, liquido: liquido}
+
+		data := r.DataRepasse.Time.Format("2006-01-02")
+		if i == 0 || out.Dias[len(out.Dias)-1].Data != data {
+			out.Dias = append(out.Dias, RecebivelDiaResponse{Data: data, Formas: []RecebivelFormaResponse{}})
+			dia = soma{}
+		}
+		d := &out.Dias[len(out.Dias)-1]
+		d.Formas = append(d.Formas, RecebivelFormaResponse{
+			FormaPagamento:           r.FormaPagamento,
+			RecebivelValoresResponse: valores(linha),
+		})
+
+		for _, s := range []*soma{&dia, &total} {
+			s.pagamentos += linha.pagamentos
+			s.bruto += linha.bruto
+			s.taxa += linha.taxa
+			s.liquido += linha.liquido
+		}
+		d.RecebivelValoresResponse = valores(dia)
+	}
+	out.Totais = valores(total)
+	return out
+}
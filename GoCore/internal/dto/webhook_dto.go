@@ -0,0 +1,184 @@
+package dto
+
+import (
+	"encoding/json"
+	"gobid/internal/store/pgstore"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+type CreateWebhookSubscriptionDTO struct {
+	TenantID   uuid.UUID `json:"-"`
+	URL        string    `json:"url"         validate:"required,url,max=2000"`
+	EventTypes []string  `json:"event_types"` // vazio = todos os eventos
+	Descricao  *string   `json:"descricao"   validate:"omitempty,max=255"`
+	Ativo      *bool     `json:"ativo"`
+}
+
+type UpdateWebhookSubscriptionDTO struct {
+	ID         uuid.UUID `json:"-"`
+	TenantID   uuid.UUID `json:"-"`
+	URL        string    `json:"url"         validate:"required,url,max=2000"`
+	EventTypes []string  `json:"event_types"`
+	Descricao  *string   `json:"descricao"   validate:"omitempty,max=255"`
+	Ativo      bool      `json:"ativo"`
+}
+
+func CreateWebhookDTOToParams(in *CreateWebhookSubscriptionDTO, secret string) pgstore.CreateWebhookSubscriptionParams {
+	ativo := true
+	if in.Ativo != nil {
+		ativo = *in.Ativo
+	}
+	return pgstore.CreateWebhookSubscriptionParams{
+		TenantID:   in.TenantID,
+		Url:        in.URL,
+		Secret:     secret,
+		EventTypes: nonNilStrings(in.EventTypes),
+		Descricao:  textFromPtr(in.Descricao),
+		Ativo:      ativo,
+	}
+}
+
+func UpdateWebhookDTOToParams(in *UpdateWebhookSubscriptionDTO) pgstore.UpdateWebhookSubscriptionParams {
+	return pgstore.UpdateWebhookSubscriptionParams{
+		ID:         in.ID,
+		TenantID:   in.TenantID,
+		Url:        in.URL,
+		EventTypes: nonNilStrings(in.EventTypes),
+		Descricao:  textFromPtr(in.Descricao),
+		Ativo:      in.Ativo,
+	}
+}
+
+/* ---------- Corpo enviado ao assinante ---------- */
+
+// WebhookEventBody é o JSON enviado no POST para a URL do assinante.
+// Payload é o envelope versionado gravado no outbox (OutboxEnvelope).
+type WebhookEventBody struct {
+	ID            int64           `json:"id"`
+	TenantID      uuid.UUID       `json:"tenant_id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func WebhookEventBodyFromOutbox(ev OutboxEventResponse) WebhookEventBody {
+	return WebhookEventBody{
+		ID:            ev.ID,
+		TenantID:      ev.TenantID,
+		EventType:     ev.EventType,
+		AggregateType: ev.AggregateType,
+		AggregateID:   ev.AggregateID,
+		CreatedAt:     ev.CreatedAt,
+		Payload:       ev.Payload,
+	}
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type WebhookSubscriptionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Descricao  *string   `json:"descricao"`
+	Ativo      bool      `json:"ativo"`
+	// Devolvido apenas na criação e na rotação do segredo
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func WebhookSubscriptionToResponse(s pgstore.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		ID:         s.ID,
+		URL:        s.Url,
+		EventTypes: nonNilStrings(s.EventTypes),
+		Descricao:  textToPtr(s.Descricao),
+		Ativo:      s.Ativo,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+func WebhookSubscriptionsToResponses(rows []pgstore.WebhookSubscription) []WebhookSubscriptionResponse {
+	out := make([]WebhookSubscriptionResponse, len(rows))
+	for i, r := range rows {
+		out[i] = WebhookSubscriptionToResponse(r)
+	}
+	return out
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	URL            string          `json:"url"`
+	StatusCode     *int32          `json:"status_code"`
+	ResponseBody   *string         `json:"response_body"`
+	Error          *string         `json:"error"`
+	Success        bool            `json:"success"`
+	DurationMs     int32           `json:"duration_ms"`
+	Manual         bool            `json:"manual"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func WebhookDeliveryToResponse(d pgstore.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		URL:            d.Url,
+		ResponseBody:   textToPtr(d.ResponseBody),
+		Error:          textToPtr(d.Error),
+		Success:        d.Success,
+		DurationMs:     d.DurationMs,
+		Manual:         d.Manual,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.StatusCode.Valid {
+		resp.StatusCode = &d.StatusCode.Int32
+	}
+	return resp
+}
+
+func WebhookDeliveriesToResponses(rows []pgstore.WebhookDelivery) []WebhookDeliveryResponse {
+	out := make([]WebhookDeliveryResponse, len(rows))
+	for i, r := range rows {
+		out[i] = WebhookDeliveryToResponse(r)
+	}
+	return out
+}
+
+/* ---------- Helpers ---------- */
+
+func textFromPtr(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func textToPtr(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+// text[] NOT NULL: nil viraria NULL no insert
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
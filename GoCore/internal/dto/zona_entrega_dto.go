@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"time"
+
+	"gobid/internal/geoutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Tipos de zona de entrega (zonas_entrega.tipo)
+const (
+	ZonaPorBairro   = "B"
+	ZonaPorCep      = "C"
+	ZonaPorRaio     = "R"
+	ZonaPorPoligono = "P"
+)
+
+/* ---------- DTOs de ENTRADA ---------- */
+
+// Zona de entrega. Conforme o tipo:
+//
+//	B → bairros
+//	C → cep_prefixo (ex.: "01310") ou cep_inicio/cep_fim, com 8 dígitos
+//	R → raio_km a partir das coordenadas da loja
+//	P → poligono com pelo menos 3 vértices
+type ZonaEntregaDTO struct {
+	ID                uuid.UUID        `json:"-"`
+	TenantID          uuid.UUID        `json:"-"`
+	Nome              string           `json:"nome"                          validate:"required,min=1,max=100"`
+	Tipo              string           `json:"tipo"                          validate:"required,oneof=B C R P"`
+	Bairros           []string         `json:"bairros,omitempty"             validate:"omitempty,max=200,dive,required,max=100"`
+	CepPrefixo        *string          `json:"cep_prefixo,omitempty"         validate:"omitempty,numeric,min=1,max=8"`
+	CepInicio         *string          `json:"cep_inicio,omitempty"          validate:"omitempty,numeric,len=8"`
+	CepFim            *string          `json:"cep_fim,omitempty"             validate:"omitempty,numeric,len=8"`
+	RaioKm            *types.Decimal   `json:"raio_km,omitempty"`
+	Poligono          []geoutils.Ponto `json:"poligono,omitempty"            validate:"omitempty,min=3,max=500"`
+	Taxa              types.Decimal    `json:"taxa"`
+	ValorMinimoPedido *types.Decimal   `json:"valor_minimo_pedido,omitempty"`
+	PrazoMin          *int32           `json:"prazo_min,omitempty"           validate:"omitempty,min=0"`
+	PrazoMax          *int32           `json:"prazo_max,omitempty"           validate:"omitempty,min=0"`
+	Ordem             int32            `json:"ordem"`
+	Ativo             *bool            `json:"ativo,omitempty"`
+}
+
+// Coordenadas da loja, origem das zonas por raio
+type TenantOrigemDTO struct {
+	Lat float64 `json:"lat" validate:"latitude"`
+	Lng float64 `json:"lng" validate:"longitude"`
+}
+
+// Endereço a resolver. Sem cep/bairro vale o cadastro do cliente; raio e
+// polígono dependem de lat/lng.
+type EnderecoEntregaDTO struct {
+	IDCliente *string  `json:"id_cliente,omitempty" validate:"omitempty,uuid"`
+	Cep       *string  `json:"cep,omitempty"`
+	Bairro    *string  `json:"bairro,omitempty"`
+	Lat       *float64 `json:"lat,omitempty"        validate:"omitempty,latitude"`
+	Lng       *float64 `json:"lng,omitempty"        validate:"omitempty,longitude"`
+}
+
+/* ---------- DTOs de SAÍDA ---------- */
+
+type ZonaEntregaResponse struct {
+	ID                uuid.UUID        `json:"id"`
+	Nome              string           `json:"nome"`
+	Tipo              string           `json:"tipo"`
+	Bairros           []string         `json:"bairros,omitempty"`
+	CepInicio         *string          `json:"cep_inicio,omitempty"`
+	CepFim            *string          `json:"cep_fim,omitempty"`
+	RaioKm            *types.Decimal   `json:"raio_km,omitempty"`
+	Poligono          []geoutils.Ponto `json:"poligono,omitempty"`
+	Taxa              types.Decimal    `json:"taxa"`
+	ValorMinimoPedido types.Decimal    `json:"valor_minimo_pedido"`
+	PrazoMin          *int32           `json:"prazo_min"`
+	PrazoMax          *int32           `json:"prazo_max"`
+	Ordem             int32            `json:"ordem"`
+	Ativo             bool             `json:"ativo"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+type TenantOrigemResponse struct {
+	Lat *float64 `json:"lat"`
+	Lng *float64 `json:"lng"`
+}
+
+// Zona que atende o endereço e o que ela define para o pedido
+type EntregaResolvidaResponse struct {
+	IDZonaEntrega     uuid.UUID     `json:"id_zona_entrega"`
+	Nome              string        `json:"nome"`
+	Tipo              string        `json:"tipo"`
+	Taxa              types.Decimal `json:"taxa"`
+	ValorMinimoPedido types.Decimal `json:"valor_minimo_pedido"`
+	PrazoMin          *int32        `json:"prazo_min"`
+	PrazoMax          *int32        `json:"prazo_max"`
+	// Distância em linha reta da loja, quando há coordenadas
+	DistanciaKm *float64 `json:"distancia_km,omitempty"`
+}
+
+// ZonaEntregaToResponse converte a zona; poligono já decodificado do jsonb
+func ZonaEntregaToResponse(z pgstore.ZonasEntrega, poligono []geoutils.Ponto) ZonaEntregaResponse {
+	out := ZonaEntregaResponse{
+		ID:                z.ID,
+		Nome:              z.Nome,
+		Tipo:              z.Tipo,
+		Bairros:           z.Bairros,
+		Poligono:          poligono,
+		Taxa:              numericToDecimal(z.Taxa),
+		ValorMinimoPedido: numericToDecimal(z.ValorMinimoPedido),
+		Ordem:             z.Ordem,
+		Ativo:             z.Ativo,
+		CreatedAt:         z.CreatedAt,
+		UpdatedAt:         z.UpdatedAt,
+	}
+	if z.CepInicio.Valid {
+		out.CepInicio = &z.CepInicio.String
+	}
+	if z.CepFim.Valid {
+		out.CepFim = &z.CepFim.String
+	}
+	if z.RaioKm.Valid {
+		v := numericToDecimal(z.RaioKm)
+		out.RaioKm = &v
+	}
+	if z.PrazoMin.Valid {
+		out.PrazoMin = &z.PrazoMin.Int32
+	}
+	if z.PrazoMax.Valid {
+		out.PrazoMax = &z.PrazoMax.Int32
+	}
+	return out
+}
+
+// ZonaEntregaToResolvida: o que a zona define para o pedido
+func ZonaEntregaToResolvida(z pgstore.ZonasEntrega, distanciaKm *float64) EntregaResolvidaResponse {
+	out := EntregaResolvidaResponse{
+		IDZonaEntrega:     z.ID,
+		Nome:              z.Nome,
+		Tipo:              z.Tipo,
+		Taxa:              numericToDecimal(z.Taxa),
+		ValorMinimoPedido: numericToDecimal(z.ValorMinimoPedido),
+		DistanciaKm:       distanciaKm,
+	}
+	if z.PrazoMin.Valid {
+		out.PrazoMin = &z.PrazoMin.Int32
+	}
+	if z.PrazoMax.Valid {
+		out.PrazoMax = &z.PrazoMax.Int32
+	}
+	return out
+}
+
+// nullDecimalToFloat: lat/lng do pedido para a cotação
+func nullDecimalToFloat(d *types.NullDecimal) *float64 {
+	if d == nil || d.Big == nil {
+		return nil
+	}
+	f, ok := d.Big.Float64()
+	if !ok {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,54 @@
+// Package geoutils reúne o cálculo geográfico usado nas entregas: distância
+// entre coordenadas e ponto dentro de polígono. As distâncias são em linha
+// reta sobre a esfera, suficientes para zonas e rotas dentro de uma cidade.
+package geoutils
+
+import "math"
+
+const raioTerraKm = 6371.0088
+
+// Ponto em graus decimais (WGS 84)
+type Ponto struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Valido descarta coordenadas fora da faixa e o (0, 0) de GPS sem sinal
+func (p Ponto) Valido() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180 && (p.Lat != 0 || p.Lng != 0)
+}
+
+// DistanciaKm: distância de haversine entre dois pontos
+func DistanciaKm(a, b Ponto) float64 {
+	lat1, lat2 := radianos(a.Lat), radianos(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radianos(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * raioTerraKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// DentroDoPoligono usa ray casting tratando lat/lng como plano, o que vale
+// para polígonos do tamanho de bairros. Vértices em ordem, sem repetir o
+// primeiro no fim; ponto sobre a borda pode cair de qualquer lado.
+func DentroDoPoligono(p Ponto, poligono []Ponto) bool {
+	if len(poligono) < 3 {
+		return false
+	}
+	dentro := false
+	j := len(poligono) - 1
+	for i := range poligono {
+		a, b := poligono[i], poligono[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			dentro = !dentro
+		}
+		j = i
+	}
+	return dentro
+}
+
+func radianos(graus float64) float64 {
+	return graus * math.Pi / 180
+}
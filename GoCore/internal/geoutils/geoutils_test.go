@@ -0,0 +1,82 @@
+package geoutils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDentroDoPoligono(t *testing.T) {
+	quadrado := []Ponto{
+		{Lat: -25.40, Lng: -49.30},
+		{Lat: -25.40, Lng: -49.20},
+		{Lat: -25.50, Lng: -49.20},
+		{Lat: -25.50, Lng: -49.30},
+	}
+	// "L": o canto nordeste fica de fora
+	concavo := []Ponto{
+		{Lat: 0, Lng: 0},
+		{Lat: 0, Lng: 2},
+		{Lat: 1, Lng: 2},
+		{Lat: 1, Lng: 1},
+		{Lat: 2, Lng: 1},
+		{Lat: 2, Lng: 0},
+	}
+	tests := []struct {
+		nome     string
+		p        Ponto
+		poligono []Ponto
+		want     bool
+	}{
+		{"centro do quadrado", Ponto{Lat: -25.45, Lng: -49.25}, quadrado, true},
+		{"ao norte do quadrado", Ponto{Lat: -25.35, Lng: -49.25}, quadrado, false},
+		{"a leste do quadrado", Ponto{Lat: -25.45, Lng: -49.10}, quadrado, false},
+		{"alinhado com um vértice, fora", Ponto{Lat: -25.40, Lng: -49.10}, quadrado, false},
+		{"perna do L", Ponto{Lat: 0.5, Lng: 1.5}, concavo, true},
+		{"outra perna do L", Ponto{Lat: 1.5, Lng: 0.5}, concavo, true},
+		{"recorte do L", Ponto{Lat: 1.5, Lng: 1.5}, concavo, false},
+		{"vértices ao contrário", Ponto{Lat: -25.45, Lng: -49.25}, []Ponto{quadrado[3], quadrado[2], quadrado[1], quadrado[0]}, true},
+		{"menos de três vértices", Ponto{Lat: 0, Lng: 0.5}, []Ponto{{0, 0}, {0, 1}}, false},
+		{"polígono vazio", Ponto{Lat: 0, Lng: 0}, nil, false},
+	}
+	for _, tt := range tests {
+		if got := DentroDoPoligono(tt.p, tt.poligono); got != tt.want {
+			t.Errorf("%s: DentroDoPoligono(%v) = %v, want %v", tt.nome, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestDistanciaKm(t *testing.T) {
+	tests := []struct {
+		nome string
+		a, b Ponto
+		want float64
+	}{
+		{"mesmo ponto", Ponto{-25.43, -49.27}, Ponto{-25.43, -49.27}, 0},
+		{"1 grau no equador", Ponto{0, 0}, Ponto{0, 1}, 111.195},
+		{"1 grau de latitude", Ponto{10, 30}, Ponto{11, 30}, 111.195},
+		{"polo a polo", Ponto{90, 0}, Ponto{-90, 0}, math.Pi * raioTerraKm},
+	}
+	for _, tt := range tests {
+		if got := DistanciaKm(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s: DistanciaKm = %.4f, want %.4f", tt.nome, got, tt.want)
+		}
+	}
+}
+
+func TestPontoValido(t *testing.T) {
+	tests := []struct {
+		p    Ponto
+		want bool
+	}{
+		{Ponto{-25.43, -49.27}, true},
+		{Ponto{0, 0}, false},
+		{Ponto{0, 10}, true},
+		{Ponto{91, 0}, false},
+		{Ponto{0, -181}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Valido(); got != tt.want {
+			t.Errorf("%v.Valido() = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
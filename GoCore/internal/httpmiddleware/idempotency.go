@@ -0,0 +1,153 @@
+package httpmiddleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyMaxLen      = 255
+	idempotencyMaxBodyRequest = 1 << 20
+)
+
+// IdempotencyStore persiste as respostas por (tenant, chave)
+type IdempotencyStore interface {
+	Reservar(ctx context.Context, tenantID uuid.UUID, chave, metodo, rota, hash string) (dto.IdempotencyRegistro, bool, error)
+	Concluir(ctx context.Context, tenantID uuid.UUID, chave string, status int, contentType string, resposta []byte) error
+	Liberar(ctx context.Context, tenantID uuid.UUID, chave string) error
+}
+
+// Idempotency devolve um middleware compatível com chi para o header
+// Idempotency-Key. Sem o header a requisição segue normalmente. Com ele:
+//
+//   - primeira vez: executa o handler e guarda status e corpo (respostas 5xx
+//     não são guardadas, a chave é liberada para nova tentativa)
+//   - mesma chave e mesmo payload: repete a resposta guardada
+//   - mesma chave e payload diferente: 409
+//   - mesma chave ainda em processamento: 409 com Retry-After
+//
+// tenantFn identifica o tenant; sem tenant o middleware não atua.
+func Idempotency(store IdempotencyStore, tenantFn func(*http.Request) uuid.UUID, l *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chave := r.Header.Get(IdempotencyKeyHeader)
+			if chave == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(chave) > idempotencyKeyMaxLen {
+				jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "Idempotency-Key muito longa"})
+				return
+			}
+
+			tenantID := tenantFn(r)
+			if tenantID == uuid.Nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, idempotencyMaxBodyRequest))
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": "corpo da requisição muito grande"})
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// a query entra no hash: ?precos=estrito muda o que o POST faz
+			rota := r.URL.Path
+			hash := hashRequisicao(r.Method, rota, r.URL.Query().Encode(), body)
+
+			registro, reservada, err := store.Reservar(r.Context(), tenantID, chave, r.Method, rota, hash)
+			if err != nil {
+				l.Error("erro ao reservar idempotency key", zap.Error(err))
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+				return
+			}
+
+			if !reservada {
+				switch {
+				case registro.HashRequisicao != hash:
+					jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+						"error": "Idempotency-Key já usada com outro payload",
+					})
+				case !registro.Concluida:
+					w.Header().Set("Retry-After", "1")
+					jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+						"error": "requisição com esta Idempotency-Key ainda em processamento",
+					})
+				default:
+					if registro.ContentType != "" {
+						w.Header().Set("Content-Type", registro.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(registro.StatusCode)
+					w.Write(registro.Resposta)
+				}
+				return
+			}
+
+			// a resposta precisa ser guardada mesmo se o cliente desconectar
+			ctx := context.WithoutCancel(r.Context())
+			concluida := false
+			defer func() {
+				if concluida {
+					return
+				}
+				if err := store.Liberar(ctx, tenantID, chave); err != nil {
+					l.Error("erro ao liberar idempotency key", zap.Error(err))
+				}
+			}()
+
+			var resposta bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&resposta)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			if err := store.Concluir(ctx, tenantID, chave, status, ww.Header().Get("Content-Type"), resposta.Bytes()); err != nil {
+				l.Error("erro ao guardar resposta da idempotency key", zap.Error(err))
+				return
+			}
+			concluida = true
+		})
+	}
+}
+
+// hashRequisicao usa o JSON canônico (chaves ordenadas, sem espaços) e a
+// query já ordenada (url.Values.Encode) para que a mesma requisição
+// reformatada pelo cliente não seja tratada como outra.
+func hashRequisicao(metodo, rota, query string, body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if canonico, err := json.Marshal(v); err == nil {
+			body = canonico
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(metodo + " " + rota + "?" + query + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
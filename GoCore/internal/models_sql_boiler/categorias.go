@@ -56,8 +56,10 @@ type Categoria struct {
 	CreatedAt         time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	UpdatedAt         time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 	// Data e hora de exclusão lógica (soft delete)
-	DeletedAt        null.Time `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
-	TipoVisualizacao null.Int  `boil:"tipo_visualizacao" json:"tipo_visualizacao,omitempty" toml:"tipo_visualizacao" yaml:"tipo_visualizacao,omitempty"`
+	DeletedAt        null.Time   `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	TipoVisualizacao null.Int    `boil:"tipo_visualizacao" json:"tipo_visualizacao,omitempty" toml:"tipo_visualizacao" yaml:"tipo_visualizacao,omitempty"`
+	IDEstacao        null.String `boil:"id_estacao" json:"id_estacao,omitempty" toml:"id_estacao" yaml:"id_estacao,omitempty"`
+	MaxSabores       int16       `boil:"max_sabores" json:"max_sabores" toml:"max_sabores" yaml:"max_sabores"`
 
 	R *categoriaR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L categoriaL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -86,6 +88,8 @@ var CategoriaColumns = struct {
 	UpdatedAt         string
 	DeletedAt         string
 	TipoVisualizacao  string
+	IDEstacao         string
+	MaxSabores        string
 }{
 	ID:                "id",
 	SeqID:             "seq_id",
@@ -109,6 +113,8 @@ var CategoriaColumns = struct {
 	UpdatedAt:         "updated_at",
 	DeletedAt:         "deleted_at",
 	TipoVisualizacao:  "tipo_visualizacao",
+	IDEstacao:         "id_estacao",
+	MaxSabores:        "max_sabores",
 }
 
 var CategoriaTableColumns = struct {
@@ -134,6 +140,8 @@ var CategoriaTableColumns = struct {
 	UpdatedAt         string
 	DeletedAt         string
 	TipoVisualizacao  string
+	IDEstacao         string
+	MaxSabores        string
 }{
 	ID:                "categorias.id",
 	SeqID:             "categorias.seq_id",
@@ -157,6 +165,8 @@ var CategoriaTableColumns = struct {
 	UpdatedAt:         "categorias.updated_at",
 	DeletedAt:         "categorias.deleted_at",
 	TipoVisualizacao:  "categorias.tipo_visualizacao",
+	IDEstacao:         "categorias.id_estacao",
+	MaxSabores:        "categorias.max_sabores",
 }
 
 // Generated where
@@ -207,6 +217,8 @@ var CategoriaWhere = struct {
 	UpdatedAt         whereHelpertime_Time
 	DeletedAt         whereHelpernull_Time
 	TipoVisualizacao  whereHelpernull_Int
+	IDEstacao         whereHelpernull_String
+	MaxSabores        whereHelperint16
 }{
 	ID:                whereHelperstring{field: "\"categorias\".\"id\""},
 	SeqID:             whereHelperint64{field: "\"categorias\".\"seq_id\""},
@@ -230,6 +242,8 @@ var CategoriaWhere = struct {
 	UpdatedAt:         whereHelpertime_Time{field: "\"categorias\".\"updated_at\""},
 	DeletedAt:         whereHelpernull_Time{field: "\"categorias\".\"deleted_at\""},
 	TipoVisualizacao:  whereHelpernull_Int{field: "\"categorias\".\"tipo_visualizacao\""},
+	IDEstacao:         whereHelpernull_String{field: "\"categorias\".\"id_estacao\""},
+	MaxSabores:        whereHelperint16{field: "\"categorias\".\"max_sabores\""},
 }
 
 // CategoriaRels is where relationship names are stored.
@@ -310,9 +324,9 @@ func (r *categoriaR) GetIDCategoriumProdutos() ProdutoSlice {
 type categoriaL struct{}
 
 var (
-	categoriaAllColumns            = []string{"id", "seq_id", "id_tenant", "id_culinaria", "nome", "descricao", "inicio", "fim", "ativo", "opcao_meia", "ordem", "disponivel_domingo", "disponivel_segunda", "disponivel_terca", "disponivel_quarta", "disponivel_quinta", "disponivel_sexta", "disponivel_sabado", "created_at", "updated_at", "deleted_at", "tipo_visualizacao"}
+	categoriaAllColumns            = []string{"id", "seq_id", "id_tenant", "id_culinaria", "nome", "descricao", "inicio", "fim", "ativo", "opcao_meia", "ordem", "disponivel_domingo", "disponivel_segunda", "disponivel_terca", "disponivel_quarta", "disponivel_quinta", "disponivel_sexta", "disponivel_sabado", "created_at", "updated_at", "deleted_at", "tipo_visualizacao", "id_estacao", "max_sabores"}
 	categoriaColumnsWithoutDefault = []string{"id_tenant", "id_culinaria", "nome", "inicio", "fim"}
-	categoriaColumnsWithDefault    = []string{"id", "seq_id", "descricao", "ativo", "opcao_meia", "ordem", "disponivel_domingo", "disponivel_segunda", "disponivel_terca", "disponivel_quarta", "disponivel_quinta", "disponivel_sexta", "disponivel_sabado", "created_at", "updated_at", "deleted_at", "tipo_visualizacao"}
+	categoriaColumnsWithDefault    = []string{"id", "seq_id", "descricao", "ativo", "opcao_meia", "ordem", "disponivel_domingo", "disponivel_segunda", "disponivel_terca", "disponivel_quarta", "disponivel_quinta", "disponivel_sexta", "disponivel_sabado", "created_at", "updated_at", "deleted_at", "tipo_visualizacao", "id_estacao", "max_sabores"}
 	categoriaPrimaryKeyColumns     = []string{"id"}
 	categoriaGeneratedColumns      = []string{}
 )
@@ -25,86 +25,114 @@ import (
 
 // ContasReceber is an object representing the database table.
 type ContasReceber struct {
-	ID          string            `boil:"id" json:"id" toml:"id" yaml:"id"`
-	IDPedido    string            `boil:"id_pedido" json:"id_pedido" toml:"id_pedido" yaml:"id_pedido"`
-	Parcela     int16             `boil:"parcela" json:"parcela" toml:"parcela" yaml:"parcela"`
-	Vencimento  time.Time         `boil:"vencimento" json:"vencimento" toml:"vencimento" yaml:"vencimento"`
-	ValorDevido types.Decimal     `boil:"valor_devido" json:"valor_devido" toml:"valor_devido" yaml:"valor_devido"`
-	ValorPago   types.NullDecimal `boil:"valor_pago" json:"valor_pago,omitempty" toml:"valor_pago" yaml:"valor_pago,omitempty"`
-	Quitado     null.Bool         `boil:"quitado" json:"quitado,omitempty" toml:"quitado" yaml:"quitado,omitempty"`
-	CreatedAt   time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt   time.Time         `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	ID                 string            `boil:"id" json:"id" toml:"id" yaml:"id"`
+	IDPedido           string            `boil:"id_pedido" json:"id_pedido" toml:"id_pedido" yaml:"id_pedido"`
+	Parcela            int16             `boil:"parcela" json:"parcela" toml:"parcela" yaml:"parcela"`
+	Vencimento         time.Time         `boil:"vencimento" json:"vencimento" toml:"vencimento" yaml:"vencimento"`
+	ValorDevido        types.Decimal     `boil:"valor_devido" json:"valor_devido" toml:"valor_devido" yaml:"valor_devido"`
+	ValorPago          types.NullDecimal `boil:"valor_pago" json:"valor_pago,omitempty" toml:"valor_pago" yaml:"valor_pago,omitempty"`
+	Quitado            null.Bool         `boil:"quitado" json:"quitado,omitempty" toml:"quitado" yaml:"quitado,omitempty"`
+	CreatedAt          time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt          time.Time         `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	CanceladoEm        null.Time         `boil:"cancelado_em" json:"cancelado_em,omitempty" toml:"cancelado_em" yaml:"cancelado_em,omitempty"`
+	MotivoCancelamento null.String       `boil:"motivo_cancelamento" json:"motivo_cancelamento,omitempty" toml:"motivo_cancelamento" yaml:"motivo_cancelamento,omitempty"`
+	AutorizadoPor      null.String       `boil:"autorizado_por" json:"autorizado_por,omitempty" toml:"autorizado_por" yaml:"autorizado_por,omitempty"`
+	EncargosPagos      types.Decimal     `boil:"encargos_pagos" json:"encargos_pagos" toml:"encargos_pagos" yaml:"encargos_pagos"`
 
 	R *contasReceberR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L contasReceberL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var ContasReceberColumns = struct {
-	ID          string
-	IDPedido    string
-	Parcela     string
-	Vencimento  string
-	ValorDevido string
-	ValorPago   string
-	Quitado     string
-	CreatedAt   string
-	UpdatedAt   string
+	ID                 string
+	IDPedido           string
+	Parcela            string
+	Vencimento         string
+	ValorDevido        string
+	ValorPago          string
+	Quitado            string
+	CreatedAt          string
+	UpdatedAt          string
+	CanceladoEm        string
+	MotivoCancelamento string
+	AutorizadoPor      string
+	EncargosPagos      string
 }{
-	ID:          "id",
-	IDPedido:    "id_pedido",
-	Parcela:     "parcela",
-	Vencimento:  "vencimento",
-	ValorDevido: "valor_devido",
-	ValorPago:   "valor_pago",
-	Quitado:     "quitado",
-	CreatedAt:   "created_at",
-	UpdatedAt:   "updated_at",
+	ID:                 "id",
+	IDPedido:           "id_pedido",
+	Parcela:            "parcela",
+	Vencimento:         "vencimento",
+	ValorDevido:        "valor_devido",
+	ValorPago:          "valor_pago",
+	Quitado:            "quitado",
+	CreatedAt:          "created_at",
+	UpdatedAt:          "updated_at",
+	CanceladoEm:        "cancelado_em",
+	MotivoCancelamento: "motivo_cancelamento",
+	AutorizadoPor:      "autorizado_por",
+	EncargosPagos:      "encargos_pagos",
 }
 
 var ContasReceberTableColumns = struct {
-	ID          string
-	IDPedido    string
-	Parcela     string
-	Vencimento  string
-	ValorDevido string
-	ValorPago   string
-	Quitado     string
-	CreatedAt   string
-	UpdatedAt   string
+	ID                 string
+	IDPedido           string
+	Parcela            string
+	Vencimento         string
+	ValorDevido        string
+	ValorPago          string
+	Quitado            string
+	CreatedAt          string
+	UpdatedAt          string
+	CanceladoEm        string
+	MotivoCancelamento string
+	AutorizadoPor      string
+	EncargosPagos      string
 }{
-	ID:          "contas_receber.id",
-	IDPedido:    "contas_receber.id_pedido",
-	Parcela:     "contas_receber.parcela",
-	Vencimento:  "contas_receber.vencimento",
-	ValorDevido: "contas_receber.valor_devido",
-	ValorPago:   "contas_receber.valor_pago",
-	Quitado:     "contas_receber.quitado",
-	CreatedAt:   "contas_receber.created_at",
-	UpdatedAt:   "contas_receber.updated_at",
+	ID:                 "contas_receber.id",
+	IDPedido:           "contas_receber.id_pedido",
+	Parcela:            "contas_receber.parcela",
+	Vencimento:         "contas_receber.vencimento",
+	ValorDevido:        "contas_receber.valor_devido",
+	ValorPago:          "contas_receber.valor_pago",
+	Quitado:            "contas_receber.quitado",
+	CreatedAt:          "contas_receber.created_at",
+	UpdatedAt:          "contas_receber.updated_at",
+	CanceladoEm:        "contas_receber.cancelado_em",
+	MotivoCancelamento: "contas_receber.motivo_cancelamento",
+	AutorizadoPor:      "contas_receber.autorizado_por",
+	EncargosPagos:      "contas_receber.encargos_pagos",
 }
 
 // Generated where
 
 var ContasReceberWhere = struct {
-	ID          whereHelperstring
-	IDPedido    whereHelperstring
-	Parcela     whereHelperint16
-	Vencimento  whereHelpertime_Time
-	ValorDevido whereHelpertypes_Decimal
-	ValorPago   whereHelpertypes_NullDecimal
-	Quitado     whereHelpernull_Bool
-	CreatedAt   whereHelpertime_Time
-	UpdatedAt   whereHelpertime_Time
+	ID                 whereHelperstring
+	IDPedido           whereHelperstring
+	Parcela            whereHelperint16
+	Vencimento         whereHelpertime_Time
+	ValorDevido        whereHelpertypes_Decimal
+	ValorPago          whereHelpertypes_NullDecimal
+	Quitado            whereHelpernull_Bool
+	CreatedAt          whereHelpertime_Time
+	UpdatedAt          whereHelpertime_Time
+	CanceladoEm        whereHelpernull_Time
+	MotivoCancelamento whereHelpernull_String
+	AutorizadoPor      whereHelpernull_String
+	EncargosPagos      whereHelpertypes_Decimal
 }{
-	ID:          whereHelperstring{field: "\"contas_receber\".\"id\""},
-	IDPedido:    whereHelperstring{field: "\"contas_receber\".\"id_pedido\""},
-	Parcela:     whereHelperint16{field: "\"contas_receber\".\"parcela\""},
-	Vencimento:  whereHelpertime_Time{field: "\"contas_receber\".\"vencimento\""},
-	ValorDevido: whereHelpertypes_Decimal{field: "\"contas_receber\".\"valor_devido\""},
-	ValorPago:   whereHelpertypes_NullDecimal{field: "\"contas_receber\".\"valor_pago\""},
-	Quitado:     whereHelpernull_Bool{field: "\"contas_receber\".\"quitado\""},
-	CreatedAt:   whereHelpertime_Time{field: "\"contas_receber\".\"created_at\""},
-	UpdatedAt:   whereHelpertime_Time{field: "\"contas_receber\".\"updated_at\""},
+	ID:                 whereHelperstring{field: "\"contas_receber\".\"id\""},
+	IDPedido:           whereHelperstring{field: "\"contas_receber\".\"id_pedido\""},
+	Parcela:            whereHelperint16{field: "\"contas_receber\".\"parcela\""},
+	Vencimento:         whereHelpertime_Time{field: "\"contas_receber\".\"vencimento\""},
+	ValorDevido:        whereHelpertypes_Decimal{field: "\"contas_receber\".\"valor_devido\""},
+	ValorPago:          whereHelpertypes_NullDecimal{field: "\"contas_receber\".\"valor_pago\""},
+	Quitado:            whereHelpernull_Bool{field: "\"contas_receber\".\"quitado\""},
+	CreatedAt:          whereHelpertime_Time{field: "\"contas_receber\".\"created_at\""},
+	UpdatedAt:          whereHelpertime_Time{field: "\"contas_receber\".\"updated_at\""},
+	CanceladoEm:        whereHelpernull_Time{field: "\"contas_receber\".\"cancelado_em\""},
+	MotivoCancelamento: whereHelpernull_String{field: "\"contas_receber\".\"motivo_cancelamento\""},
+	AutorizadoPor:      whereHelpernull_String{field: "\"contas_receber\".\"autorizado_por\""},
+	EncargosPagos:      whereHelpertypes_Decimal{field: "\"contas_receber\".\"encargos_pagos\""},
 }
 
 // ContasReceberRels is where relationship names are stored.
@@ -145,9 +173,9 @@ func (r *contasReceberR) GetIDContaReceberPedidoPagamentos() PedidoPagamentoSlic
 type contasReceberL struct{}
 
 var (
-	contasReceberAllColumns            = []string{"id", "id_pedido", "parcela", "vencimento", "valor_devido", "valor_pago", "quitado", "created_at", "updated_at"}
+	contasReceberAllColumns            = []string{"id", "id_pedido", "parcela", "vencimento", "valor_devido", "valor_pago", "quitado", "created_at", "updated_at", "cancelado_em", "motivo_cancelamento", "autorizado_por", "encargos_pagos"}
 	contasReceberColumnsWithoutDefault = []string{"id_pedido", "parcela", "vencimento", "valor_devido"}
-	contasReceberColumnsWithDefault    = []string{"id", "valor_pago", "quitado", "created_at", "updated_at"}
+	contasReceberColumnsWithDefault    = []string{"id", "valor_pago", "quitado", "created_at", "updated_at", "cancelado_em", "motivo_cancelamento", "autorizado_por", "encargos_pagos"}
 	contasReceberPrimaryKeyColumns     = []string{"id"}
 	contasReceberGeneratedColumns      = []string{"quitado"}
 )
@@ -36,6 +36,9 @@ type OutboxEvent struct {
 	LastError     null.String `boil:"last_error" json:"last_error,omitempty" toml:"last_error" yaml:"last_error,omitempty"`
 	TenantID      string      `boil:"tenant_id" json:"tenant_id" toml:"tenant_id" yaml:"tenant_id"`
 	UserID        string      `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	NextAttemptAt time.Time   `boil:"next_attempt_at" json:"next_attempt_at" toml:"next_attempt_at" yaml:"next_attempt_at"`
+	ProcessedAt   null.Time   `boil:"processed_at" json:"processed_at,omitempty" toml:"processed_at" yaml:"processed_at,omitempty"`
+	DeadLetter    bool        `boil:"dead_letter" json:"dead_letter" toml:"dead_letter" yaml:"dead_letter"`
 
 	R *outboxEventR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L outboxEventL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -53,6 +56,9 @@ var OutboxEventColumns = struct {
 	LastError     string
 	TenantID      string
 	UserID        string
+	NextAttemptAt string
+	ProcessedAt   string
+	DeadLetter    string
 }{
 	ID:            "id",
 	AggregateType: "aggregate_type",
@@ -65,6 +71,9 @@ var OutboxEventColumns = struct {
 	LastError:     "last_error",
 	TenantID:      "tenant_id",
 	UserID:        "user_id",
+	NextAttemptAt: "next_attempt_at",
+	ProcessedAt:   "processed_at",
+	DeadLetter:    "dead_letter",
 }
 
 var OutboxEventTableColumns = struct {
@@ -79,6 +88,9 @@ var OutboxEventTableColumns = struct {
 	LastError     string
 	TenantID      string
 	UserID        string
+	NextAttemptAt string
+	ProcessedAt   string
+	DeadLetter    string
 }{
 	ID:            "outbox_event.id",
 	AggregateType: "outbox_event.aggregate_type",
@@ -91,6 +103,9 @@ var OutboxEventTableColumns = struct {
 	LastError:     "outbox_event.last_error",
 	TenantID:      "outbox_event.tenant_id",
 	UserID:        "outbox_event.user_id",
+	NextAttemptAt: "outbox_event.next_attempt_at",
+	ProcessedAt:   "outbox_event.processed_at",
+	DeadLetter:    "outbox_event.dead_letter",
 }
 
 // Generated where
@@ -137,6 +152,9 @@ var OutboxEventWhere = struct {
 	LastError     whereHelpernull_String
 	TenantID      whereHelperstring
 	UserID        whereHelperstring
+	NextAttemptAt whereHelpertime_Time
+	ProcessedAt   whereHelpernull_Time
+	DeadLetter    whereHelperbool
 }{
 	ID:            whereHelperint64{field: "\"outbox_event\".\"id\""},
 	AggregateType: whereHelperstring{field: "\"outbox_event\".\"aggregate_type\""},
@@ -149,6 +167,9 @@ var OutboxEventWhere = struct {
 	LastError:     whereHelpernull_String{field: "\"outbox_event\".\"last_error\""},
 	TenantID:      whereHelperstring{field: "\"outbox_event\".\"tenant_id\""},
 	UserID:        whereHelperstring{field: "\"outbox_event\".\"user_id\""},
+	NextAttemptAt: whereHelpertime_Time{field: "\"outbox_event\".\"next_attempt_at\""},
+	ProcessedAt:   whereHelpernull_Time{field: "\"outbox_event\".\"processed_at\""},
+	DeadLetter:    whereHelperbool{field: "\"outbox_event\".\"dead_letter\""},
 }
 
 // OutboxEventRels is where relationship names are stored.
@@ -189,9 +210,9 @@ func (r *outboxEventR) GetUser() *User {
 type outboxEventL struct{}
 
 var (
-	outboxEventAllColumns            = []string{"id", "aggregate_type", "aggregate_id", "event_type", "payload", "created_at", "processed", "attempts", "last_error", "tenant_id", "user_id"}
+	outboxEventAllColumns            = []string{"id", "aggregate_type", "aggregate_id", "event_type", "payload", "created_at", "processed", "attempts", "last_error", "tenant_id", "user_id", "next_attempt_at", "processed_at", "dead_letter"}
 	outboxEventColumnsWithoutDefault = []string{"aggregate_type", "aggregate_id", "event_type", "payload", "tenant_id", "user_id"}
-	outboxEventColumnsWithDefault    = []string{"id", "created_at", "processed", "attempts", "last_error"}
+	outboxEventColumnsWithDefault    = []string{"id", "created_at", "processed", "attempts", "last_error", "next_attempt_at", "processed_at", "dead_letter"}
 	outboxEventPrimaryKeyColumns     = []string{"id"}
 	outboxEventGeneratedColumns      = []string{"id"}
 )
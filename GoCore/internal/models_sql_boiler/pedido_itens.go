@@ -25,114 +25,163 @@ import (
 
 // PedidoItem is an object representing the database table.
 type PedidoItem struct {
-	ID               string        `boil:"id" json:"id" toml:"id" yaml:"id"`
-	SeqID            int64         `boil:"seq_id" json:"seq_id" toml:"seq_id" yaml:"seq_id"`
-	IDPedido         string        `boil:"id_pedido" json:"id_pedido" toml:"id_pedido" yaml:"id_pedido"`
-	IDProduto        string        `boil:"id_produto" json:"id_produto" toml:"id_produto" yaml:"id_produto"`
-	IDProduto2       null.String   `boil:"id_produto_2" json:"id_produto_2,omitempty" toml:"id_produto_2" yaml:"id_produto_2,omitempty"`
-	IDCategoria      string        `boil:"id_categoria" json:"id_categoria" toml:"id_categoria" yaml:"id_categoria"`
-	IDCategoriaOpcao null.String   `boil:"id_categoria_opcao" json:"id_categoria_opcao,omitempty" toml:"id_categoria_opcao" yaml:"id_categoria_opcao,omitempty"`
-	Observacao       null.String   `boil:"observacao" json:"observacao,omitempty" toml:"observacao" yaml:"observacao,omitempty"`
-	ValorUnitario    types.Decimal `boil:"valor_unitario" json:"valor_unitario" toml:"valor_unitario" yaml:"valor_unitario"`
-	Quantidade       int           `boil:"quantidade" json:"quantidade" toml:"quantidade" yaml:"quantidade"`
-	CreatedAt        time.Time     `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt        time.Time     `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
-	DeletedAt        null.Time     `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	ID                string        `boil:"id" json:"id" toml:"id" yaml:"id"`
+	SeqID             int64         `boil:"seq_id" json:"seq_id" toml:"seq_id" yaml:"seq_id"`
+	IDPedido          string        `boil:"id_pedido" json:"id_pedido" toml:"id_pedido" yaml:"id_pedido"`
+	IDProduto         string        `boil:"id_produto" json:"id_produto" toml:"id_produto" yaml:"id_produto"`
+	IDProduto2        null.String   `boil:"id_produto_2" json:"id_produto_2,omitempty" toml:"id_produto_2" yaml:"id_produto_2,omitempty"`
+	IDCategoria       string        `boil:"id_categoria" json:"id_categoria" toml:"id_categoria" yaml:"id_categoria"`
+	IDCategoriaOpcao  null.String   `boil:"id_categoria_opcao" json:"id_categoria_opcao,omitempty" toml:"id_categoria_opcao" yaml:"id_categoria_opcao,omitempty"`
+	Observacao        null.String   `boil:"observacao" json:"observacao,omitempty" toml:"observacao" yaml:"observacao,omitempty"`
+	ValorUnitario     types.Decimal `boil:"valor_unitario" json:"valor_unitario" toml:"valor_unitario" yaml:"valor_unitario"`
+	Quantidade        int           `boil:"quantidade" json:"quantidade" toml:"quantidade" yaml:"quantidade"`
+	CreatedAt         time.Time     `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt         time.Time     `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	DeletedAt         null.Time     `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	IDEstacao         null.String   `boil:"id_estacao" json:"id_estacao,omitempty" toml:"id_estacao" yaml:"id_estacao,omitempty"`
+	StatusPreparo     string        `boil:"status_preparo" json:"status_preparo" toml:"status_preparo" yaml:"status_preparo"`
+	DataInicioPreparo null.Time     `boil:"data_inicio_preparo" json:"data_inicio_preparo,omitempty" toml:"data_inicio_preparo" yaml:"data_inicio_preparo,omitempty"`
+	DataFimPreparo    null.Time     `boil:"data_fim_preparo" json:"data_fim_preparo,omitempty" toml:"data_fim_preparo" yaml:"data_fim_preparo,omitempty"`
+	DataDespacho      null.Time     `boil:"data_despacho" json:"data_despacho,omitempty" toml:"data_despacho" yaml:"data_despacho,omitempty"`
+	IDItemCombo       null.String   `boil:"id_item_combo" json:"id_item_combo,omitempty" toml:"id_item_combo" yaml:"id_item_combo,omitempty"`
+	IDComboSlot       null.String   `boil:"id_combo_slot" json:"id_combo_slot,omitempty" toml:"id_combo_slot" yaml:"id_combo_slot,omitempty"`
 
 	R *pedidoItemR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L pedidoItemL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var PedidoItemColumns = struct {
-	ID               string
-	SeqID            string
-	IDPedido         string
-	IDProduto        string
-	IDProduto2       string
-	IDCategoria      string
-	IDCategoriaOpcao string
-	Observacao       string
-	ValorUnitario    string
-	Quantidade       string
-	CreatedAt        string
-	UpdatedAt        string
-	DeletedAt        string
+	ID                string
+	SeqID             string
+	IDPedido          string
+	IDProduto         string
+	IDProduto2        string
+	IDCategoria       string
+	IDCategoriaOpcao  string
+	Observacao        string
+	ValorUnitario     string
+	Quantidade        string
+	CreatedAt         string
+	UpdatedAt         string
+	DeletedAt         string
+	IDEstacao         string
+	StatusPreparo     string
+	DataInicioPreparo string
+	DataFimPreparo    string
+	DataDespacho      string
+	IDItemCombo       string
+	IDComboSlot       string
 }{
-	ID:               "id",
-	SeqID:            "seq_id",
-	IDPedido:         "id_pedido",
-	IDProduto:        "id_produto",
-	IDProduto2:       "id_produto_2",
-	IDCategoria:      "id_categoria",
-	IDCategoriaOpcao: "id_categoria_opcao",
-	Observacao:       "observacao",
-	ValorUnitario:    "valor_unitario",
-	Quantidade:       "quantidade",
-	CreatedAt:        "created_at",
-	UpdatedAt:        "updated_at",
-	DeletedAt:        "deleted_at",
+	ID:                "id",
+	SeqID:             "seq_id",
+	IDPedido:          "id_pedido",
+	IDProduto:         "id_produto",
+	IDProduto2:        "id_produto_2",
+	IDCategoria:       "id_categoria",
+	IDCategoriaOpcao:  "id_categoria_opcao",
+	Observacao:        "observacao",
+	ValorUnitario:     "valor_unitario",
+	Quantidade:        "quantidade",
+	CreatedAt:         "created_at",
+	UpdatedAt:         "updated_at",
+	DeletedAt:         "deleted_at",
+	IDEstacao:         "id_estacao",
+	StatusPreparo:     "status_preparo",
+	DataInicioPreparo: "data_inicio_preparo",
+	DataFimPreparo:    "data_fim_preparo",
+	DataDespacho:      "data_despacho",
+	IDItemCombo:       "id_item_combo",
+	IDComboSlot:       "id_combo_slot",
 }
 
 var PedidoItemTableColumns = struct {
-	ID               string
-	SeqID            string
-	IDPedido         string
-	IDProduto        string
-	IDProduto2       string
-	IDCategoria      string
-	IDCategoriaOpcao string
-	Observacao       string
-	ValorUnitario    string
-	Quantidade       string
-	CreatedAt        string
-	UpdatedAt        string
-	DeletedAt        string
+	ID                string
+	SeqID             string
+	IDPedido          string
+	IDProduto         string
+	IDProduto2        string
+	IDCategoria       string
+	IDCategoriaOpcao  string
+	Observacao        string
+	ValorUnitario     string
+	Quantidade        string
+	CreatedAt         string
+	UpdatedAt         string
+	DeletedAt         string
+	IDEstacao         string
+	StatusPreparo     string
+	DataInicioPreparo string
+	DataFimPreparo    string
+	DataDespacho      string
+	IDItemCombo       string
+	IDComboSlot       string
 }{
-	ID:               "pedido_itens.id",
-	SeqID:            "pedido_itens.seq_id",
-	IDPedido:         "pedido_itens.id_pedido",
-	IDProduto:        "pedido_itens.id_produto",
-	IDProduto2:       "pedido_itens.id_produto_2",
-	IDCategoria:      "pedido_itens.id_categoria",
-	IDCategoriaOpcao: "pedido_itens.id_categoria_opcao",
-	Observacao:       "pedido_itens.observacao",
-	ValorUnitario:    "pedido_itens.valor_unitario",
-	Quantidade:       "pedido_itens.quantidade",
-	CreatedAt:        "pedido_itens.created_at",
-	UpdatedAt:        "pedido_itens.updated_at",
-	DeletedAt:        "pedido_itens.deleted_at",
+	ID:                "pedido_itens.id",
+	SeqID:             "pedido_itens.seq_id",
+	IDPedido:          "pedido_itens.id_pedido",
+	IDProduto:         "pedido_itens.id_produto",
+	IDProduto2:        "pedido_itens.id_produto_2",
+	IDCategoria:       "pedido_itens.id_categoria",
+	IDCategoriaOpcao:  "pedido_itens.id_categoria_opcao",
+	Observacao:        "pedido_itens.observacao",
+	ValorUnitario:     "pedido_itens.valor_unitario",
+	Quantidade:        "pedido_itens.quantidade",
+	CreatedAt:         "pedido_itens.created_at",
+	UpdatedAt:         "pedido_itens.updated_at",
+	DeletedAt:         "pedido_itens.deleted_at",
+	IDEstacao:         "pedido_itens.id_estacao",
+	StatusPreparo:     "pedido_itens.status_preparo",
+	DataInicioPreparo: "pedido_itens.data_inicio_preparo",
+	DataFimPreparo:    "pedido_itens.data_fim_preparo",
+	DataDespacho:      "pedido_itens.data_despacho",
+	IDItemCombo:       "pedido_itens.id_item_combo",
+	IDComboSlot:       "pedido_itens.id_combo_slot",
 }
 
 // Generated where
 
 var PedidoItemWhere = struct {
-	ID               whereHelperstring
-	SeqID            whereHelperint64
-	IDPedido         whereHelperstring
-	IDProduto        whereHelperstring
-	IDProduto2       whereHelpernull_String
-	IDCategoria      whereHelperstring
-	IDCategoriaOpcao whereHelpernull_String
-	Observacao       whereHelpernull_String
-	ValorUnitario    whereHelpertypes_Decimal
-	Quantidade       whereHelperint
-	CreatedAt        whereHelpertime_Time
-	UpdatedAt        whereHelpertime_Time
-	DeletedAt        whereHelpernull_Time
+	ID                whereHelperstring
+	SeqID             whereHelperint64
+	IDPedido          whereHelperstring
+	IDProduto         whereHelperstring
+	IDProduto2        whereHelpernull_String
+	IDCategoria       whereHelperstring
+	IDCategoriaOpcao  whereHelpernull_String
+	Observacao        whereHelpernull_String
+	ValorUnitario     whereHelpertypes_Decimal
+	Quantidade        whereHelperint
+	CreatedAt         whereHelpertime_Time
+	UpdatedAt         whereHelpertime_Time
+	DeletedAt         whereHelpernull_Time
+	IDEstacao         whereHelpernull_String
+	StatusPreparo     whereHelperstring
+	DataInicioPreparo whereHelpernull_Time
+	DataFimPreparo    whereHelpernull_Time
+	DataDespacho      whereHelpernull_Time
+	IDItemCombo       whereHelpernull_String
+	IDComboSlot       whereHelpernull_String
 }{
-	ID:               whereHelperstring{field: "\"pedido_itens\".\"id\""},
-	SeqID:            whereHelperint64{field: "\"pedido_itens\".\"seq_id\""},
-	IDPedido:         whereHelperstring{field: "\"pedido_itens\".\"id_pedido\""},
-	IDProduto:        whereHelperstring{field: "\"pedido_itens\".\"id_produto\""},
-	IDProduto2:       whereHelpernull_String{field: "\"pedido_itens\".\"id_produto_2\""},
-	IDCategoria:      whereHelperstring{field: "\"pedido_itens\".\"id_categoria\""},
-	IDCategoriaOpcao: whereHelpernull_String{field: "\"pedido_itens\".\"id_categoria_opcao\""},
-	Observacao:       whereHelpernull_String{field: "\"pedido_itens\".\"observacao\""},
-	ValorUnitario:    whereHelpertypes_Decimal{field: "\"pedido_itens\".\"valor_unitario\""},
-	Quantidade:       whereHelperint{field: "\"pedido_itens\".\"quantidade\""},
-	CreatedAt:        whereHelpertime_Time{field: "\"pedido_itens\".\"created_at\""},
-	UpdatedAt:        whereHelpertime_Time{field: "\"pedido_itens\".\"updated_at\""},
-	DeletedAt:        whereHelpernull_Time{field: "\"pedido_itens\".\"deleted_at\""},
+	ID:                whereHelperstring{field: "\"pedido_itens\".\"id\""},
+	SeqID:             whereHelperint64{field: "\"pedido_itens\".\"seq_id\""},
+	IDPedido:          whereHelperstring{field: "\"pedido_itens\".\"id_pedido\""},
+	IDProduto:         whereHelperstring{field: "\"pedido_itens\".\"id_produto\""},
+	IDProduto2:        whereHelpernull_String{field: "\"pedido_itens\".\"id_produto_2\""},
+	IDCategoria:       whereHelperstring{field: "\"pedido_itens\".\"id_categoria\""},
+	IDCategoriaOpcao:  whereHelpernull_String{field: "\"pedido_itens\".\"id_categoria_opcao\""},
+	Observacao:        whereHelpernull_String{field: "\"pedido_itens\".\"observacao\""},
+	ValorUnitario:     whereHelpertypes_Decimal{field: "\"pedido_itens\".\"valor_unitario\""},
+	Quantidade:        whereHelperint{field: "\"pedido_itens\".\"quantidade\""},
+	CreatedAt:         whereHelpertime_Time{field: "\"pedido_itens\".\"created_at\""},
+	UpdatedAt:         whereHelpertime_Time{field: "\"pedido_itens\".\"updated_at\""},
+	DeletedAt:         whereHelpernull_Time{field: "\"pedido_itens\".\"deleted_at\""},
+	IDEstacao:         whereHelpernull_String{field: "\"pedido_itens\".\"id_estacao\""},
+	StatusPreparo:     whereHelperstring{field: "\"pedido_itens\".\"status_preparo\""},
+	DataInicioPreparo: whereHelpernull_Time{field: "\"pedido_itens\".\"data_inicio_preparo\""},
+	DataFimPreparo:    whereHelpernull_Time{field: "\"pedido_itens\".\"data_fim_preparo\""},
+	DataDespacho:      whereHelpernull_Time{field: "\"pedido_itens\".\"data_despacho\""},
+	IDItemCombo:       whereHelpernull_String{field: "\"pedido_itens\".\"id_item_combo\""},
+	IDComboSlot:       whereHelpernull_String{field: "\"pedido_itens\".\"id_combo_slot\""},
 }
 
 // PedidoItemRels is where relationship names are stored.
@@ -213,9 +262,9 @@ func (r *pedidoItemR) GetIDPedidoItemPedidoItemAdicionais() PedidoItemAdicionalS
 type pedidoItemL struct{}
 
 var (
-	pedidoItemAllColumns            = []string{"id", "seq_id", "id_pedido", "id_produto", "id_produto_2", "id_categoria", "id_categoria_opcao", "observacao", "valor_unitario", "quantidade", "created_at", "updated_at", "deleted_at"}
+	pedidoItemAllColumns            = []string{"id", "seq_id", "id_pedido", "id_produto", "id_produto_2", "id_categoria", "id_categoria_opcao", "observacao", "valor_unitario", "quantidade", "created_at", "updated_at", "deleted_at", "id_estacao", "status_preparo", "data_inicio_preparo", "data_fim_preparo", "data_despacho", "id_item_combo", "id_combo_slot"}
 	pedidoItemColumnsWithoutDefault = []string{"id_pedido", "id_produto", "id_categoria", "valor_unitario", "quantidade"}
-	pedidoItemColumnsWithDefault    = []string{"id", "seq_id", "id_produto_2", "id_categoria_opcao", "observacao", "created_at", "updated_at", "deleted_at"}
+	pedidoItemColumnsWithDefault    = []string{"id", "seq_id", "id_produto_2", "id_categoria_opcao", "observacao", "created_at", "updated_at", "deleted_at", "id_estacao", "status_preparo", "data_inicio_preparo", "data_fim_preparo", "data_despacho", "id_item_combo", "id_combo_slot"}
 	pedidoItemPrimaryKeyColumns     = []string{"id"}
 	pedidoItemGeneratedColumns      = []string{}
 )
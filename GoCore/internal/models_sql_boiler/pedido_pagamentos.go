@@ -38,6 +38,13 @@ type PedidoPagamento struct {
 	CreatedAt          time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	UpdatedAt          time.Time         `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 	DeletedAt          null.Time         `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	ValorEncargos      types.Decimal     `boil:"valor_encargos" json:"valor_encargos" toml:"valor_encargos" yaml:"valor_encargos"`
+	ParcelasCartao     int16             `boil:"parcelas_cartao" json:"parcelas_cartao" toml:"parcelas_cartao" yaml:"parcelas_cartao"`
+	TaxaAdquirente     types.NullDecimal `boil:"taxa_adquirente" json:"taxa_adquirente,omitempty" toml:"taxa_adquirente" yaml:"taxa_adquirente,omitempty"`
+	ValorRepasse       types.NullDecimal `boil:"valor_repasse" json:"valor_repasse,omitempty" toml:"valor_repasse" yaml:"valor_repasse,omitempty"`
+	DataRepasse        null.Time         `boil:"data_repasse" json:"data_repasse,omitempty" toml:"data_repasse" yaml:"data_repasse,omitempty"`
+	PixTxid            null.String       `boil:"pix_txid" json:"pix_txid,omitempty" toml:"pix_txid" yaml:"pix_txid,omitempty"`
+	PixE2eID           null.String       `boil:"pix_e2e_id" json:"pix_e2e_id,omitempty" toml:"pix_e2e_id" yaml:"pix_e2e_id,omitempty"`
 
 	R *pedidoPagamentoR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L pedidoPagamentoL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -57,6 +64,13 @@ var PedidoPagamentoColumns = struct {
 	CreatedAt          string
 	UpdatedAt          string
 	DeletedAt          string
+	ValorEncargos      string
+	ParcelasCartao     string
+	TaxaAdquirente     string
+	ValorRepasse       string
+	DataRepasse        string
+	PixTxid            string
+	PixE2eID           string
 }{
 	ID:                 "id",
 	SeqID:              "seq_id",
@@ -71,6 +85,13 @@ var PedidoPagamentoColumns = struct {
 	CreatedAt:          "created_at",
 	UpdatedAt:          "updated_at",
 	DeletedAt:          "deleted_at",
+	ValorEncargos:      "valor_encargos",
+	ParcelasCartao:     "parcelas_cartao",
+	TaxaAdquirente:     "taxa_adquirente",
+	ValorRepasse:       "valor_repasse",
+	DataRepasse:        "data_repasse",
+	PixTxid:            "pix_txid",
+	PixE2eID:           "pix_e2e_id",
 }
 
 var PedidoPagamentoTableColumns = struct {
@@ -87,6 +108,13 @@ var PedidoPagamentoTableColumns = struct {
 	CreatedAt          string
 	UpdatedAt          string
 	DeletedAt          string
+	ValorEncargos      string
+	ParcelasCartao     string
+	TaxaAdquirente     string
+	ValorRepasse       string
+	DataRepasse        string
+	PixTxid            string
+	PixE2eID           string
 }{
 	ID:                 "pedido_pagamentos.id",
 	SeqID:              "pedido_pagamentos.seq_id",
@@ -101,6 +129,13 @@ var PedidoPagamentoTableColumns = struct {
 	CreatedAt:          "pedido_pagamentos.created_at",
 	UpdatedAt:          "pedido_pagamentos.updated_at",
 	DeletedAt:          "pedido_pagamentos.deleted_at",
+	ValorEncargos:      "pedido_pagamentos.valor_encargos",
+	ParcelasCartao:     "pedido_pagamentos.parcelas_cartao",
+	TaxaAdquirente:     "pedido_pagamentos.taxa_adquirente",
+	ValorRepasse:       "pedido_pagamentos.valor_repasse",
+	DataRepasse:        "pedido_pagamentos.data_repasse",
+	PixTxid:            "pedido_pagamentos.pix_txid",
+	PixE2eID:           "pedido_pagamentos.pix_e2e_id",
 }
 
 // Generated where
@@ -119,6 +154,13 @@ var PedidoPagamentoWhere = struct {
 	CreatedAt          whereHelpertime_Time
 	UpdatedAt          whereHelpertime_Time
 	DeletedAt          whereHelpernull_Time
+	ValorEncargos      whereHelpertypes_Decimal
+	ParcelasCartao     whereHelperint16
+	TaxaAdquirente     whereHelpertypes_NullDecimal
+	ValorRepasse       whereHelpertypes_NullDecimal
+	DataRepasse        whereHelpernull_Time
+	PixTxid            whereHelpernull_String
+	PixE2eID           whereHelpernull_String
 }{
 	ID:                 whereHelperstring{field: "\"pedido_pagamentos\".\"id\""},
 	SeqID:              whereHelperint64{field: "\"pedido_pagamentos\".\"seq_id\""},
@@ -133,6 +175,13 @@ var PedidoPagamentoWhere = struct {
 	CreatedAt:          whereHelpertime_Time{field: "\"pedido_pagamentos\".\"created_at\""},
 	UpdatedAt:          whereHelpertime_Time{field: "\"pedido_pagamentos\".\"updated_at\""},
 	DeletedAt:          whereHelpernull_Time{field: "\"pedido_pagamentos\".\"deleted_at\""},
+	ValorEncargos:      whereHelpertypes_Decimal{field: "\"pedido_pagamentos\".\"valor_encargos\""},
+	ParcelasCartao:     whereHelperint16{field: "\"pedido_pagamentos\".\"parcelas_cartao\""},
+	TaxaAdquirente:     whereHelpertypes_NullDecimal{field: "\"pedido_pagamentos\".\"taxa_adquirente\""},
+	ValorRepasse:       whereHelpertypes_NullDecimal{field: "\"pedido_pagamentos\".\"valor_repasse\""},
+	DataRepasse:        whereHelpernull_Time{field: "\"pedido_pagamentos\".\"data_repasse\""},
+	PixTxid:            whereHelpernull_String{field: "\"pedido_pagamentos\".\"pix_txid\""},
+	PixE2eID:           whereHelpernull_String{field: "\"pedido_pagamentos\".\"pix_e2e_id\""},
 }
 
 // PedidoPagamentoRels is where relationship names are stored.
@@ -183,9 +232,9 @@ func (r *pedidoPagamentoR) GetIDPagamentoCaixaMovimentacoes() CaixaMovimentacaoS
 type pedidoPagamentoL struct{}
 
 var (
-	pedidoPagamentoAllColumns            = []string{"id", "seq_id", "id_pedido", "id_conta_receber", "categoria_pagamento", "forma_pagamento", "valor_pago", "troco", "autorizado_por", "observacao", "created_at", "updated_at", "deleted_at"}
+	pedidoPagamentoAllColumns            = []string{"id", "seq_id", "id_pedido", "id_conta_receber", "categoria_pagamento", "forma_pagamento", "valor_pago", "troco", "autorizado_por", "observacao", "created_at", "updated_at", "deleted_at", "valor_encargos", "parcelas_cartao", "taxa_adquirente", "valor_repasse", "data_repasse", "pix_txid", "pix_e2e_id"}
 	pedidoPagamentoColumnsWithoutDefault = []string{"id_pedido", "forma_pagamento", "valor_pago"}
-	pedidoPagamentoColumnsWithDefault    = []string{"id", "seq_id", "id_conta_receber", "categoria_pagamento", "troco", "autorizado_por", "observacao", "created_at", "updated_at", "deleted_at"}
+	pedidoPagamentoColumnsWithDefault    = []string{"id", "seq_id", "id_conta_receber", "categoria_pagamento", "troco", "autorizado_por", "observacao", "created_at", "updated_at", "deleted_at", "valor_encargos", "parcelas_cartao", "taxa_adquirente", "valor_repasse", "data_repasse", "pix_txid", "pix_e2e_id"}
 	pedidoPagamentoPrimaryKeyColumns     = []string{"id"}
 	pedidoPagamentoGeneratedColumns      = []string{}
 )
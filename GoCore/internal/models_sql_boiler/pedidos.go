@@ -25,247 +25,282 @@ import (
 
 // Pedido is an object representing the database table.
 type Pedido struct {
-	ID                 string            `boil:"id" json:"id" toml:"id" yaml:"id"`
-	SeqID              int64             `boil:"seq_id" json:"seq_id" toml:"seq_id" yaml:"seq_id"`
-	TenantID           string            `boil:"tenant_id" json:"tenant_id" toml:"tenant_id" yaml:"tenant_id"`
-	IDCliente          string            `boil:"id_cliente" json:"id_cliente" toml:"id_cliente" yaml:"id_cliente"`
-	CodigoPedido       string            `boil:"codigo_pedido" json:"codigo_pedido" toml:"codigo_pedido" yaml:"codigo_pedido"`
-	DataPedido         time.Time         `boil:"data_pedido" json:"data_pedido" toml:"data_pedido" yaml:"data_pedido"`
-	GMT                int16             `boil:"gmt" json:"gmt" toml:"gmt" yaml:"gmt"`
-	PedidoPronto       int16             `boil:"pedido_pronto" json:"pedido_pronto" toml:"pedido_pronto" yaml:"pedido_pronto"`
-	DataPedidoPronto   null.Time         `boil:"data_pedido_pronto" json:"data_pedido_pronto,omitempty" toml:"data_pedido_pronto" yaml:"data_pedido_pronto,omitempty"`
-	Cupom              null.String       `boil:"cupom" json:"cupom,omitempty" toml:"cupom" yaml:"cupom,omitempty"`
-	TipoEntrega        string            `boil:"tipo_entrega" json:"tipo_entrega" toml:"tipo_entrega" yaml:"tipo_entrega"`
-	Prazo              null.Int          `boil:"prazo" json:"prazo,omitempty" toml:"prazo" yaml:"prazo,omitempty"`
-	PrazoMin           null.Int          `boil:"prazo_min" json:"prazo_min,omitempty" toml:"prazo_min" yaml:"prazo_min,omitempty"`
-	PrazoMax           null.Int          `boil:"prazo_max" json:"prazo_max,omitempty" toml:"prazo_max" yaml:"prazo_max,omitempty"`
-	CategoriaPagamento null.String       `boil:"categoria_pagamento" json:"categoria_pagamento,omitempty" toml:"categoria_pagamento" yaml:"categoria_pagamento,omitempty"`
-	FormaPagamento     null.String       `boil:"forma_pagamento" json:"forma_pagamento,omitempty" toml:"forma_pagamento" yaml:"forma_pagamento,omitempty"`
-	ValorTotal         types.Decimal     `boil:"valor_total" json:"valor_total" toml:"valor_total" yaml:"valor_total"`
-	Observacao         null.String       `boil:"observacao" json:"observacao,omitempty" toml:"observacao" yaml:"observacao,omitempty"`
-	TaxaEntrega        types.Decimal     `boil:"taxa_entrega" json:"taxa_entrega" toml:"taxa_entrega" yaml:"taxa_entrega"`
-	NomeTaxaEntrega    null.String       `boil:"nome_taxa_entrega" json:"nome_taxa_entrega,omitempty" toml:"nome_taxa_entrega" yaml:"nome_taxa_entrega,omitempty"`
-	IDStatus           int16             `boil:"id_status" json:"id_status" toml:"id_status" yaml:"id_status"`
-	Lat                types.NullDecimal `boil:"lat" json:"lat,omitempty" toml:"lat" yaml:"lat,omitempty"`
-	LNG                types.NullDecimal `boil:"lng" json:"lng,omitempty" toml:"lng" yaml:"lng,omitempty"`
-	CreatedAt          time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt          time.Time         `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
-	DeletedAt          null.Time         `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
-	ValorPago          types.Decimal     `boil:"valor_pago" json:"valor_pago" toml:"valor_pago" yaml:"valor_pago"`
-	Quitado            null.Bool         `boil:"quitado" json:"quitado,omitempty" toml:"quitado" yaml:"quitado,omitempty"`
-	TrocoPara          types.NullDecimal `boil:"troco_para" json:"troco_para,omitempty" toml:"troco_para" yaml:"troco_para,omitempty"`
-	Desconto           types.Decimal     `boil:"desconto" json:"desconto" toml:"desconto" yaml:"desconto"`
-	Acrescimo          types.Decimal     `boil:"acrescimo" json:"acrescimo" toml:"acrescimo" yaml:"acrescimo"`
-	Finalizado         bool              `boil:"finalizado" json:"finalizado" toml:"finalizado" yaml:"finalizado"`
+	ID                         string            `boil:"id" json:"id" toml:"id" yaml:"id"`
+	SeqID                      int64             `boil:"seq_id" json:"seq_id" toml:"seq_id" yaml:"seq_id"`
+	TenantID                   string            `boil:"tenant_id" json:"tenant_id" toml:"tenant_id" yaml:"tenant_id"`
+	IDCliente                  string            `boil:"id_cliente" json:"id_cliente" toml:"id_cliente" yaml:"id_cliente"`
+	CodigoPedido               string            `boil:"codigo_pedido" json:"codigo_pedido" toml:"codigo_pedido" yaml:"codigo_pedido"`
+	DataPedido                 time.Time         `boil:"data_pedido" json:"data_pedido" toml:"data_pedido" yaml:"data_pedido"`
+	GMT                        int16             `boil:"gmt" json:"gmt" toml:"gmt" yaml:"gmt"`
+	PedidoPronto               int16             `boil:"pedido_pronto" json:"pedido_pronto" toml:"pedido_pronto" yaml:"pedido_pronto"`
+	DataPedidoPronto           null.Time         `boil:"data_pedido_pronto" json:"data_pedido_pronto,omitempty" toml:"data_pedido_pronto" yaml:"data_pedido_pronto,omitempty"`
+	Cupom                      null.String       `boil:"cupom" json:"cupom,omitempty" toml:"cupom" yaml:"cupom,omitempty"`
+	TipoEntrega                string            `boil:"tipo_entrega" json:"tipo_entrega" toml:"tipo_entrega" yaml:"tipo_entrega"`
+	Prazo                      null.Int          `boil:"prazo" json:"prazo,omitempty" toml:"prazo" yaml:"prazo,omitempty"`
+	PrazoMin                   null.Int          `boil:"prazo_min" json:"prazo_min,omitempty" toml:"prazo_min" yaml:"prazo_min,omitempty"`
+	PrazoMax                   null.Int          `boil:"prazo_max" json:"prazo_max,omitempty" toml:"prazo_max" yaml:"prazo_max,omitempty"`
+	CategoriaPagamento         null.String       `boil:"categoria_pagamento" json:"categoria_pagamento,omitempty" toml:"categoria_pagamento" yaml:"categoria_pagamento,omitempty"`
+	FormaPagamento             null.String       `boil:"forma_pagamento" json:"forma_pagamento,omitempty" toml:"forma_pagamento" yaml:"forma_pagamento,omitempty"`
+	ValorTotal                 types.Decimal     `boil:"valor_total" json:"valor_total" toml:"valor_total" yaml:"valor_total"`
+	Observacao                 null.String       `boil:"observacao" json:"observacao,omitempty" toml:"observacao" yaml:"observacao,omitempty"`
+	TaxaEntrega                types.Decimal     `boil:"taxa_entrega" json:"taxa_entrega" toml:"taxa_entrega" yaml:"taxa_entrega"`
+	NomeTaxaEntrega            null.String       `boil:"nome_taxa_entrega" json:"nome_taxa_entrega,omitempty" toml:"nome_taxa_entrega" yaml:"nome_taxa_entrega,omitempty"`
+	IDStatus                   int16             `boil:"id_status" json:"id_status" toml:"id_status" yaml:"id_status"`
+	Lat                        types.NullDecimal `boil:"lat" json:"lat,omitempty" toml:"lat" yaml:"lat,omitempty"`
+	LNG                        types.NullDecimal `boil:"lng" json:"lng,omitempty" toml:"lng" yaml:"lng,omitempty"`
+	CreatedAt                  time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt                  time.Time         `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	DeletedAt                  null.Time         `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	ValorPago                  types.Decimal     `boil:"valor_pago" json:"valor_pago" toml:"valor_pago" yaml:"valor_pago"`
+	Quitado                    null.Bool         `boil:"quitado" json:"quitado,omitempty" toml:"quitado" yaml:"quitado,omitempty"`
+	TrocoPara                  types.NullDecimal `boil:"troco_para" json:"troco_para,omitempty" toml:"troco_para" yaml:"troco_para,omitempty"`
+	Desconto                   types.Decimal     `boil:"desconto" json:"desconto" toml:"desconto" yaml:"desconto"`
+	Acrescimo                  types.Decimal     `boil:"acrescimo" json:"acrescimo" toml:"acrescimo" yaml:"acrescimo"`
+	Finalizado                 bool              `boil:"finalizado" json:"finalizado" toml:"finalizado" yaml:"finalizado"`
+	DisponibilidadeLiberadaPor null.String       `boil:"disponibilidade_liberada_por" json:"disponibilidade_liberada_por,omitempty" toml:"disponibilidade_liberada_por" yaml:"disponibilidade_liberada_por,omitempty"`
+	IDZonaEntrega              null.String       `boil:"id_zona_entrega" json:"id_zona_entrega,omitempty" toml:"id_zona_entrega" yaml:"id_zona_entrega,omitempty"`
+	AgendadoPara               null.Time         `boil:"agendado_para" json:"agendado_para,omitempty" toml:"agendado_para" yaml:"agendado_para,omitempty"`
+	LiberadoEm                 null.Time         `boil:"liberado_em" json:"liberado_em,omitempty" toml:"liberado_em" yaml:"liberado_em,omitempty"`
+	AgendadoPor                null.String       `boil:"agendado_por" json:"agendado_por,omitempty" toml:"agendado_por" yaml:"agendado_por,omitempty"`
 
 	R *pedidoR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L pedidoL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var PedidoColumns = struct {
-	ID                 string
-	SeqID              string
-	TenantID           string
-	IDCliente          string
-	CodigoPedido       string
-	DataPedido         string
-	GMT                string
-	PedidoPronto       string
-	DataPedidoPronto   string
-	Cupom              string
-	TipoEntrega        string
-	Prazo              string
-	PrazoMin           string
-	PrazoMax           string
-	CategoriaPagamento string
-	FormaPagamento     string
-	ValorTotal         string
-	Observacao         string
-	TaxaEntrega        string
-	NomeTaxaEntrega    string
-	IDStatus           string
-	Lat                string
-	LNG                string
-	CreatedAt          string
-	UpdatedAt          string
-	DeletedAt          string
-	ValorPago          string
-	Quitado            string
-	TrocoPara          string
-	Desconto           string
-	Acrescimo          string
-	Finalizado         string
+	ID                         string
+	SeqID                      string
+	TenantID                   string
+	IDCliente                  string
+	CodigoPedido               string
+	DataPedido                 string
+	GMT                        string
+	PedidoPronto               string
+	DataPedidoPronto           string
+	Cupom                      string
+	TipoEntrega                string
+	Prazo                      string
+	PrazoMin                   string
+	PrazoMax                   string
+	CategoriaPagamento         string
+	FormaPagamento             string
+	ValorTotal                 string
+	Observacao                 string
+	TaxaEntrega                string
+	NomeTaxaEntrega            string
+	IDStatus                   string
+	Lat                        string
+	LNG                        string
+	CreatedAt                  string
+	UpdatedAt                  string
+	DeletedAt                  string
+	ValorPago                  string
+	Quitado                    string
+	TrocoPara                  string
+	Desconto                   string
+	Acrescimo                  string
+	Finalizado                 string
+	DisponibilidadeLiberadaPor string
+	IDZonaEntrega              string
+	AgendadoPara               string
+	LiberadoEm                 string
+	AgendadoPor                string
 }{
-	ID:                 "id",
-	SeqID:              "seq_id",
-	TenantID:           "tenant_id",
-	IDCliente:          "id_cliente",
-	CodigoPedido:       "codigo_pedido",
-	DataPedido:         "data_pedido",
-	GMT:                "gmt",
-	PedidoPronto:       "pedido_pronto",
-	DataPedidoPronto:   "data_pedido_pronto",
-	Cupom:              "cupom",
-	TipoEntrega:        "tipo_entrega",
-	Prazo:              "prazo",
-	PrazoMin:           "prazo_min",
-	PrazoMax:           "prazo_max",
-	CategoriaPagamento: "categoria_pagamento",
-	FormaPagamento:     "forma_pagamento",
-	ValorTotal:         "valor_total",
-	Observacao:         "observacao",
-	TaxaEntrega:        "taxa_entrega",
-	NomeTaxaEntrega:    "nome_taxa_entrega",
-	IDStatus:           "id_status",
-	Lat:                "lat",
-	LNG:                "lng",
-	CreatedAt:          "created_at",
-	UpdatedAt:          "updated_at",
-	DeletedAt:          "deleted_at",
-	ValorPago:          "valor_pago",
-	Quitado:            "quitado",
-	TrocoPara:          "troco_para",
-	Desconto:           "desconto",
-	Acrescimo:          "acrescimo",
-	Finalizado:         "finalizado",
+	ID:                         "id",
+	SeqID:                      "seq_id",
+	TenantID:                   "tenant_id",
+	IDCliente:                  "id_cliente",
+	CodigoPedido:               "codigo_pedido",
+	DataPedido:                 "data_pedido",
+	GMT:                        "gmt",
+	PedidoPronto:               "pedido_pronto",
+	DataPedidoPronto:           "data_pedido_pronto",
+	Cupom:                      "cupom",
+	TipoEntrega:                "tipo_entrega",
+	Prazo:                      "prazo",
+	PrazoMin:                   "prazo_min",
+	PrazoMax:                   "prazo_max",
+	CategoriaPagamento:         "categoria_pagamento",
+	FormaPagamento:             "forma_pagamento",
+	ValorTotal:                 "valor_total",
+	Observacao:                 "observacao",
+	TaxaEntrega:                "taxa_entrega",
+	NomeTaxaEntrega:            "nome_taxa_entrega",
+	IDStatus:                   "id_status",
+	Lat:                        "lat",
+	LNG:                        "lng",
+	CreatedAt:                  "created_at",
+	UpdatedAt:                  "updated_at",
+	DeletedAt:                  "deleted_at",
+	ValorPago:                  "valor_pago",
+	Quitado:                    "quitado",
+	TrocoPara:                  "troco_para",
+	Desconto:                   "desconto",
+	Acrescimo:                  "acrescimo",
+	Finalizado:                 "finalizado",
+	DisponibilidadeLiberadaPor: "disponibilidade_liberada_por",
+	IDZonaEntrega:              "id_zona_entrega",
+	AgendadoPara:               "agendado_para",
+	LiberadoEm:                 "liberado_em",
+	AgendadoPor:                "agendado_por",
 }
 
 var PedidoTableColumns = struct {
-	ID                 string
-	SeqID              string
-	TenantID           string
-	IDCliente          string
-	CodigoPedido       string
-	DataPedido         string
-	GMT                string
-	PedidoPronto       string
-	DataPedidoPronto   string
-	Cupom              string
-	TipoEntrega        string
-	Prazo              string
-	PrazoMin           string
-	PrazoMax           string
-	CategoriaPagamento string
-	FormaPagamento     string
-	ValorTotal         string
-	Observacao         string
-	TaxaEntrega        string
-	NomeTaxaEntrega    string
-	IDStatus           string
-	Lat                string
-	LNG                string
-	CreatedAt          string
-	UpdatedAt          string
-	DeletedAt          string
-	ValorPago          string
-	Quitado            string
-	TrocoPara          string
-	Desconto           string
-	Acrescimo          string
-	Finalizado         string
+	ID                         string
+	SeqID                      string
+	TenantID                   string
+	IDCliente                  string
+	CodigoPedido               string
+	DataPedido                 string
+	GMT                        string
+	PedidoPronto               string
+	DataPedidoPronto           string
+	Cupom                      string
+	TipoEntrega                string
+	Prazo                      string
+	PrazoMin                   string
+	PrazoMax                   string
+	CategoriaPagamento         string
+	FormaPagamento             string
+	ValorTotal                 string
+	Observacao                 string
+	TaxaEntrega                string
+	NomeTaxaEntrega            string
+	IDStatus                   string
+	Lat                        string
+	LNG                        string
+	CreatedAt                  string
+	UpdatedAt                  string
+	DeletedAt                  string
+	ValorPago                  string
+	Quitado                    string
+	TrocoPara                  string
+	Desconto                   string
+	Acrescimo                  string
+	Finalizado                 string
+	DisponibilidadeLiberadaPor string
+	IDZonaEntrega              string
+	AgendadoPara               string
+	LiberadoEm                 string
+	AgendadoPor                string
 }{
-	ID:                 "pedidos.id",
-	SeqID:              "pedidos.seq_id",
-	TenantID:           "pedidos.tenant_id",
-	IDCliente:          "pedidos.id_cliente",
-	CodigoPedido:       "pedidos.codigo_pedido",
-	DataPedido:         "pedidos.data_pedido",
-	GMT:                "pedidos.gmt",
-	PedidoPronto:       "pedidos.pedido_pronto",
-	DataPedidoPronto:   "pedidos.data_pedido_pronto",
-	Cupom:              "pedidos.cupom",
-	TipoEntrega:        "pedidos.tipo_entrega",
-	Prazo:              "pedidos.prazo",
-	PrazoMin:           "pedidos.prazo_min",
-	PrazoMax:           "pedidos.prazo_max",
-	CategoriaPagamento: "pedidos.categoria_pagamento",
-	FormaPagamento:     "pedidos.forma_pagamento",
-	ValorTotal:         "pedidos.valor_total",
-	Observacao:         "pedidos.observacao",
-	TaxaEntrega:        "pedidos.taxa_entrega",
-	NomeTaxaEntrega:    "pedidos.nome_taxa_entrega",
-	IDStatus:           "pedidos.id_status",
-	Lat:                "pedidos.lat",
-	LNG:                "pedidos.lng",
-	CreatedAt:          "pedidos.created_at",
-	UpdatedAt:          "pedidos.updated_at",
-	DeletedAt:          "pedidos.deleted_at",
-	ValorPago:          "pedidos.valor_pago",
-	Quitado:            "pedidos.quitado",
-	TrocoPara:          "pedidos.troco_para",
-	Desconto:           "pedidos.desconto",
-	Acrescimo:          "pedidos.acrescimo",
-	Finalizado:         "pedidos.finalizado",
+	ID:                         "pedidos.id",
+	SeqID:                      "pedidos.seq_id",
+	TenantID:                   "pedidos.tenant_id",
+	IDCliente:                  "pedidos.id_cliente",
+	CodigoPedido:               "pedidos.codigo_pedido",
+	DataPedido:                 "pedidos.data_pedido",
+	GMT:                        "pedidos.gmt",
+	PedidoPronto:               "pedidos.pedido_pronto",
+	DataPedidoPronto:           "pedidos.data_pedido_pronto",
+	Cupom:                      "pedidos.cupom",
+	TipoEntrega:                "pedidos.tipo_entrega",
+	Prazo:                      "pedidos.prazo",
+	PrazoMin:                   "pedidos.prazo_min",
+	PrazoMax:                   "pedidos.prazo_max",
+	CategoriaPagamento:         "pedidos.categoria_pagamento",
+	FormaPagamento:             "pedidos.forma_pagamento",
+	ValorTotal:                 "pedidos.valor_total",
+	Observacao:                 "pedidos.observacao",
+	TaxaEntrega:                "pedidos.taxa_entrega",
+	NomeTaxaEntrega:            "pedidos.nome_taxa_entrega",
+	IDStatus:                   "pedidos.id_status",
+	Lat:                        "pedidos.lat",
+	LNG:                        "pedidos.lng",
+	CreatedAt:                  "pedidos.created_at",
+	UpdatedAt:                  "pedidos.updated_at",
+	DeletedAt:                  "pedidos.deleted_at",
+	ValorPago:                  "pedidos.valor_pago",
+	Quitado:                    "pedidos.quitado",
+	TrocoPara:                  "pedidos.troco_para",
+	Desconto:                   "pedidos.desconto",
+	Acrescimo:                  "pedidos.acrescimo",
+	Finalizado:                 "pedidos.finalizado",
+	DisponibilidadeLiberadaPor: "pedidos.disponibilidade_liberada_por",
+	IDZonaEntrega:              "pedidos.id_zona_entrega",
+	AgendadoPara:               "pedidos.agendado_para",
+	LiberadoEm:                 "pedidos.liberado_em",
+	AgendadoPor:                "pedidos.agendado_por",
 }
 
 // Generated where
 
 var PedidoWhere = struct {
-	ID                 whereHelperstring
-	SeqID              whereHelperint64
-	TenantID           whereHelperstring
-	IDCliente          whereHelperstring
-	CodigoPedido       whereHelperstring
-	DataPedido         whereHelpertime_Time
-	GMT                whereHelperint16
-	PedidoPronto       whereHelperint16
-	DataPedidoPronto   whereHelpernull_Time
-	Cupom              whereHelpernull_String
-	TipoEntrega        whereHelperstring
-	Prazo              whereHelpernull_Int
-	PrazoMin           whereHelpernull_Int
-	PrazoMax           whereHelpernull_Int
-	CategoriaPagamento whereHelpernull_String
-	FormaPagamento     whereHelpernull_String
-	ValorTotal         whereHelpertypes_Decimal
-	Observacao         whereHelpernull_String
-	TaxaEntrega        whereHelpertypes_Decimal
-	NomeTaxaEntrega    whereHelpernull_String
-	IDStatus           whereHelperint16
-	Lat                whereHelpertypes_NullDecimal
-	LNG                whereHelpertypes_NullDecimal
-	CreatedAt          whereHelpertime_Time
-	UpdatedAt          whereHelpertime_Time
-	DeletedAt          whereHelpernull_Time
-	ValorPago          whereHelpertypes_Decimal
-	Quitado            whereHelpernull_Bool
-	TrocoPara          whereHelpertypes_NullDecimal
-	Desconto           whereHelpertypes_Decimal
-	Acrescimo          whereHelpertypes_Decimal
-	Finalizado         whereHelperbool
+	ID                         whereHelperstring
+	SeqID                      whereHelperint64
+	TenantID                   whereHelperstring
+	IDCliente                  whereHelperstring
+	CodigoPedido               whereHelperstring
+	DataPedido                 whereHelpertime_Time
+	GMT                        whereHelperint16
+	PedidoPronto               whereHelperint16
+	DataPedidoPronto           whereHelpernull_Time
+	Cupom                      whereHelpernull_String
+	TipoEntrega                whereHelperstring
+	Prazo                      whereHelpernull_Int
+	PrazoMin                   whereHelpernull_Int
+	PrazoMax                   whereHelpernull_Int
+	CategoriaPagamento         whereHelpernull_String
+	FormaPagamento             whereHelpernull_String
+	ValorTotal                 whereHelpertypes_Decimal
+	Observacao                 whereHelpernull_String
+	TaxaEntrega                whereHelpertypes_Decimal
+	NomeTaxaEntrega            whereHelpernull_String
+	IDStatus                   whereHelperint16
+	Lat                        whereHelpertypes_NullDecimal
+	LNG                        whereHelpertypes_NullDecimal
+	CreatedAt                  whereHelpertime_Time
+	UpdatedAt                  whereHelpertime_Time
+	DeletedAt                  whereHelpernull_Time
+	ValorPago                  whereHelpertypes_Decimal
+	Quitado                    whereHelpernull_Bool
+	TrocoPara                  whereHelpertypes_NullDecimal
+	Desconto                   whereHelpertypes_Decimal
+	Acrescimo                  whereHelpertypes_Decimal
+	Finalizado                 whereHelperbool
+	DisponibilidadeLiberadaPor whereHelpernull_String
+	IDZonaEntrega              whereHelpernull_String
+	AgendadoPara               whereHelpernull_Time
+	LiberadoEm                 whereHelpernull_Time
+	AgendadoPor                whereHelpernull_String
 }{
-	ID:                 whereHelperstring{field: "\"pedidos\".\"id\""},
-	SeqID:              whereHelperint64{field: "\"pedidos\".\"seq_id\""},
-	TenantID:           whereHelperstring{field: "\"pedidos\".\"tenant_id\""},
-	IDCliente:          whereHelperstring{field: "\"pedidos\".\"id_cliente\""},
-	CodigoPedido:       whereHelperstring{field: "\"pedidos\".\"codigo_pedido\""},
-	DataPedido:         whereHelpertime_Time{field: "\"pedidos\".\"data_pedido\""},
-	GMT:                whereHelperint16{field: "\"pedidos\".\"gmt\""},
-	PedidoPronto:       whereHelperint16{field: "\"pedidos\".\"pedido_pronto\""},
-	DataPedidoPronto:   whereHelpernull_Time{field: "\"pedidos\".\"data_pedido_pronto\""},
-	Cupom:              whereHelpernull_String{field: "\"pedidos\".\"cupom\""},
-	TipoEntrega:        whereHelperstring{field: "\"pedidos\".\"tipo_entrega\""},
-	Prazo:              whereHelpernull_Int{field: "\"pedidos\".\"prazo\""},
-	PrazoMin:           whereHelpernull_Int{field: "\"pedidos\".\"prazo_min\""},
-	PrazoMax:           whereHelpernull_Int{field: "\"pedidos\".\"prazo_max\""},
-	CategoriaPagamento: whereHelpernull_String{field: "\"pedidos\".\"categoria_pagamento\""},
-	FormaPagamento:     whereHelpernull_String{field: "\"pedidos\".\"forma_pagamento\""},
-	ValorTotal:         whereHelpertypes_Decimal{field: "\"pedidos\".\"valor_total\""},
-	Observacao:         whereHelpernull_String{field: "\"pedidos\".\"observacao\""},
-	TaxaEntrega:        whereHelpertypes_Decimal{field: "\"pedidos\".\"taxa_entrega\""},
-	NomeTaxaEntrega:    whereHelpernull_String{field: "\"pedidos\".\"nome_taxa_entrega\""},
-	IDStatus:           whereHelperint16{field: "\"pedidos\".\"id_status\""},
-	Lat:                whereHelpertypes_NullDecimal{field: "\"pedidos\".\"lat\""},
-	LNG:                whereHelpertypes_NullDecimal{field: "\"pedidos\".\"lng\""},
-	CreatedAt:          whereHelpertime_Time{field: "\"pedidos\".\"created_at\""},
-	UpdatedAt:          whereHelpertime_Time{field: "\"pedidos\".\"updated_at\""},
-	DeletedAt:          whereHelpernull_Time{field: "\"pedidos\".\"deleted_at\""},
-	ValorPago:          whereHelpertypes_Decimal{field: "\"pedidos\".\"valor_pago\""},
-	Quitado:            whereHelpernull_Bool{field: "\"pedidos\".\"quitado\""},
-	TrocoPara:          whereHelpertypes_NullDecimal{field: "\"pedidos\".\"troco_para\""},
-	Desconto:           whereHelpertypes_Decimal{field: "\"pedidos\".\"desconto\""},
-	Acrescimo:          whereHelpertypes_Decimal{field: "\"pedidos\".\"acrescimo\""},
-	Finalizado:         whereHelperbool{field: "\"pedidos\".\"finalizado\""},
+	ID:                         whereHelperstring{field: "\"pedidos\".\"id\""},
+	SeqID:                      whereHelperint64{field: "\"pedidos\".\"seq_id\""},
+	TenantID:                   whereHelperstring{field: "\"pedidos\".\"tenant_id\""},
+	IDCliente:                  whereHelperstring{field: "\"pedidos\".\"id_cliente\""},
+	CodigoPedido:               whereHelperstring{field: "\"pedidos\".\"codigo_pedido\""},
+	DataPedido:                 whereHelpertime_Time{field: "\"pedidos\".\"data_pedido\""},
+	GMT:                        whereHelperint16{field: "\"pedidos\".\"gmt\""},
+	PedidoPronto:               whereHelperint16{field: "\"pedidos\".\"pedido_pronto\""},
+	DataPedidoPronto:           whereHelpernull_Time{field: "\"pedidos\".\"data_pedido_pronto\""},
+	Cupom:                      whereHelpernull_String{field: "\"pedidos\".\"cupom\""},
+	TipoEntrega:                whereHelperstring{field: "\"pedidos\".\"tipo_entrega\""},
+	Prazo:                      whereHelpernull_Int{field: "\"pedidos\".\"prazo\""},
+	PrazoMin:                   whereHelpernull_Int{field: "\"pedidos\".\"prazo_min\""},
+	PrazoMax:                   whereHelpernull_Int{field: "\"pedidos\".\"prazo_max\""},
+	CategoriaPagamento:         whereHelpernull_String{field: "\"pedidos\".\"categoria_pagamento\""},
+	FormaPagamento:             whereHelpernull_String{field: "\"pedidos\".\"forma_pagamento\""},
+	ValorTotal:                 whereHelpertypes_Decimal{field: "\"pedidos\".\"valor_total\""},
+	Observacao:                 whereHelpernull_String{field: "\"pedidos\".\"observacao\""},
+	TaxaEntrega:                whereHelpertypes_Decimal{field: "\"pedidos\".\"taxa_entrega\""},
+	NomeTaxaEntrega:            whereHelpernull_String{field: "\"pedidos\".\"nome_taxa_entrega\""},
+	IDStatus:                   whereHelperint16{field: "\"pedidos\".\"id_status\""},
+	Lat:                        whereHelpertypes_NullDecimal{field: "\"pedidos\".\"lat\""},
+	LNG:                        whereHelpertypes_NullDecimal{field: "\"pedidos\".\"lng\""},
+	CreatedAt:                  whereHelpertime_Time{field: "\"pedidos\".\"created_at\""},
+	UpdatedAt:                  whereHelpertime_Time{field: "\"pedidos\".\"updated_at\""},
+	DeletedAt:                  whereHelpernull_Time{field: "\"pedidos\".\"deleted_at\""},
+	ValorPago:                  whereHelpertypes_Decimal{field: "\"pedidos\".\"valor_pago\""},
+	Quitado:                    whereHelpernull_Bool{field: "\"pedidos\".\"quitado\""},
+	TrocoPara:                  whereHelpertypes_NullDecimal{field: "\"pedidos\".\"troco_para\""},
+	Desconto:                   whereHelpertypes_Decimal{field: "\"pedidos\".\"desconto\""},
+	Acrescimo:                  whereHelpertypes_Decimal{field: "\"pedidos\".\"acrescimo\""},
+	Finalizado:                 whereHelperbool{field: "\"pedidos\".\"finalizado\""},
+	DisponibilidadeLiberadaPor: whereHelpernull_String{field: "\"pedidos\".\"disponibilidade_liberada_por\""},
+	IDZonaEntrega:              whereHelpernull_String{field: "\"pedidos\".\"id_zona_entrega\""},
+	AgendadoPara:               whereHelpernull_Time{field: "\"pedidos\".\"agendado_para\""},
+	LiberadoEm:                 whereHelpernull_Time{field: "\"pedidos\".\"liberado_em\""},
+	AgendadoPor:                whereHelpernull_String{field: "\"pedidos\".\"agendado_por\""},
 }
 
 // PedidoRels is where relationship names are stored.
@@ -346,9 +381,9 @@ func (r *pedidoR) GetIDPedidoPedidoPagamentos() PedidoPagamentoSlice {
 type pedidoL struct{}
 
 var (
-	pedidoAllColumns            = []string{"id", "seq_id", "tenant_id", "id_cliente", "codigo_pedido", "data_pedido", "gmt", "pedido_pronto", "data_pedido_pronto", "cupom", "tipo_entrega", "prazo", "prazo_min", "prazo_max", "categoria_pagamento", "forma_pagamento", "valor_total", "observacao", "taxa_entrega", "nome_taxa_entrega", "id_status", "lat", "lng", "created_at", "updated_at", "deleted_at", "valor_pago", "quitado", "troco_para", "desconto", "acrescimo", "finalizado"}
+	pedidoAllColumns            = []string{"id", "seq_id", "tenant_id", "id_cliente", "codigo_pedido", "data_pedido", "gmt", "pedido_pronto", "data_pedido_pronto", "cupom", "tipo_entrega", "prazo", "prazo_min", "prazo_max", "categoria_pagamento", "forma_pagamento", "valor_total", "observacao", "taxa_entrega", "nome_taxa_entrega", "id_status", "lat", "lng", "created_at", "updated_at", "deleted_at", "valor_pago", "quitado", "troco_para", "desconto", "acrescimo", "finalizado", "disponibilidade_liberada_por", "id_zona_entrega", "agendado_para", "liberado_em", "agendado_por"}
 	pedidoColumnsWithoutDefault = []string{"tenant_id", "id_cliente", "codigo_pedido", "data_pedido", "gmt", "tipo_entrega", "valor_total", "id_status"}
-	pedidoColumnsWithDefault    = []string{"id", "seq_id", "pedido_pronto", "data_pedido_pronto", "cupom", "prazo", "prazo_min", "prazo_max", "categoria_pagamento", "forma_pagamento", "observacao", "taxa_entrega", "nome_taxa_entrega", "lat", "lng", "created_at", "updated_at", "deleted_at", "valor_pago", "quitado", "troco_para", "desconto", "acrescimo", "finalizado"}
+	pedidoColumnsWithDefault    = []string{"id", "seq_id", "pedido_pronto", "data_pedido_pronto", "cupom", "prazo", "prazo_min", "prazo_max", "categoria_pagamento", "forma_pagamento", "observacao", "taxa_entrega", "nome_taxa_entrega", "lat", "lng", "created_at", "updated_at", "deleted_at", "valor_pago", "quitado", "troco_para", "desconto", "acrescimo", "finalizado", "disponibilidade_liberada_por", "id_zona_entrega", "agendado_para", "liberado_em", "agendado_por"}
 	pedidoPrimaryKeyColumns     = []string{"id"}
 	pedidoGeneratedColumns      = []string{}
 )
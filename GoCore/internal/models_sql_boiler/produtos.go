@@ -51,7 +51,8 @@ type Produto struct {
 	// Timestamp da última atualização do registro do produto.
 	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 	// Timestamp da exclusão lógica do produto (soft delete).
-	DeletedAt null.Time `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	DeletedAt null.Time   `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	IDEstacao null.String `boil:"id_estacao" json:"id_estacao,omitempty" toml:"id_estacao" yaml:"id_estacao,omitempty"`
 
 	R *produtoR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L produtoL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -72,6 +73,7 @@ var ProdutoColumns = struct {
 	CreatedAt         string
 	UpdatedAt         string
 	DeletedAt         string
+	IDEstacao         string
 }{
 	ID:                "id",
 	SeqID:             "seq_id",
@@ -87,6 +89,7 @@ var ProdutoColumns = struct {
 	CreatedAt:         "created_at",
 	UpdatedAt:         "updated_at",
 	DeletedAt:         "deleted_at",
+	IDEstacao:         "id_estacao",
 }
 
 var ProdutoTableColumns = struct {
@@ -104,6 +107,7 @@ var ProdutoTableColumns = struct {
 	CreatedAt         string
 	UpdatedAt         string
 	DeletedAt         string
+	IDEstacao         string
 }{
 	ID:                "produtos.id",
 	SeqID:             "produtos.seq_id",
@@ -119,6 +123,7 @@ var ProdutoTableColumns = struct {
 	CreatedAt:         "produtos.created_at",
 	UpdatedAt:         "produtos.updated_at",
 	DeletedAt:         "produtos.deleted_at",
+	IDEstacao:         "produtos.id_estacao",
 }
 
 // Generated where
@@ -138,6 +143,7 @@ var ProdutoWhere = struct {
 	CreatedAt         whereHelpertime_Time
 	UpdatedAt         whereHelpertime_Time
 	DeletedAt         whereHelpernull_Time
+	IDEstacao         whereHelpernull_String
 }{
 	ID:                whereHelperstring{field: "\"produtos\".\"id\""},
 	SeqID:             whereHelperint64{field: "\"produtos\".\"seq_id\""},
@@ -153,6 +159,7 @@ var ProdutoWhere = struct {
 	CreatedAt:         whereHelpertime_Time{field: "\"produtos\".\"created_at\""},
 	UpdatedAt:         whereHelpertime_Time{field: "\"produtos\".\"updated_at\""},
 	DeletedAt:         whereHelpernull_Time{field: "\"produtos\".\"deleted_at\""},
+	IDEstacao:         whereHelpernull_String{field: "\"produtos\".\"id_estacao\""},
 }
 
 // ProdutoRels is where relationship names are stored.
@@ -213,9 +220,9 @@ func (r *produtoR) GetIDProdutoProdutoPrecos() ProdutoPrecoSlice {
 type produtoL struct{}
 
 var (
-	produtoAllColumns            = []string{"id", "seq_id", "id_categoria", "nome", "descricao", "codigo_externo", "sku", "permite_observacao", "ordem", "imagem_url", "status", "created_at", "updated_at", "deleted_at"}
+	produtoAllColumns            = []string{"id", "seq_id", "id_categoria", "nome", "descricao", "codigo_externo", "sku", "permite_observacao", "ordem", "imagem_url", "status", "created_at", "updated_at", "deleted_at", "id_estacao"}
 	produtoColumnsWithoutDefault = []string{"id_categoria", "nome"}
-	produtoColumnsWithDefault    = []string{"id", "seq_id", "descricao", "codigo_externo", "sku", "permite_observacao", "ordem", "imagem_url", "status", "created_at", "updated_at", "deleted_at"}
+	produtoColumnsWithDefault    = []string{"id", "seq_id", "descricao", "codigo_externo", "sku", "permite_observacao", "ordem", "imagem_url", "status", "created_at", "updated_at", "deleted_at", "id_estacao"}
 	produtoPrimaryKeyColumns     = []string{"id"}
 	produtoGeneratedColumns      = []string{}
 )
@@ -38,6 +38,12 @@ type Tenant struct {
 	Cidade            null.String       `boil:"cidade" json:"cidade,omitempty" toml:"cidade" yaml:"cidade,omitempty"`
 	SeqID             int64             `boil:"seq_id" json:"seq_id" toml:"seq_id" yaml:"seq_id"`
 	TaxaEntregaPadrao types.NullDecimal `boil:"taxa_entrega_padrao" json:"taxa_entrega_padrao,omitempty" toml:"taxa_entrega_padrao" yaml:"taxa_entrega_padrao,omitempty"`
+	Timezone          string            `boil:"timezone" json:"timezone" toml:"timezone" yaml:"timezone"`
+	Lat               types.NullDecimal `boil:"lat" json:"lat,omitempty" toml:"lat" yaml:"lat,omitempty"`
+	Lng               types.NullDecimal `boil:"lng" json:"lng,omitempty" toml:"lng" yaml:"lng,omitempty"`
+	PixChave          null.String       `boil:"pix_chave" json:"pix_chave,omitempty" toml:"pix_chave" yaml:"pix_chave,omitempty"`
+	PixNomeRecebedor  null.String       `boil:"pix_nome_recebedor" json:"pix_nome_recebedor,omitempty" toml:"pix_nome_recebedor" yaml:"pix_nome_recebedor,omitempty"`
+	PixCidade         null.String       `boil:"pix_cidade" json:"pix_cidade,omitempty" toml:"pix_cidade" yaml:"pix_cidade,omitempty"`
 
 	R *tenantR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L tenantL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -57,6 +63,12 @@ var TenantColumns = struct {
 	Cidade            string
 	SeqID             string
 	TaxaEntregaPadrao string
+	Timezone          string
+	Lat               string
+	Lng               string
+	PixChave          string
+	PixNomeRecebedor  string
+	PixCidade         string
 }{
 	ID:                "id",
 	Name:              "name",
@@ -71,6 +83,12 @@ var TenantColumns = struct {
 	Cidade:            "cidade",
 	SeqID:             "seq_id",
 	TaxaEntregaPadrao: "taxa_entrega_padrao",
+	Timezone:          "timezone",
+	Lat:               "lat",
+	Lng:               "lng",
+	PixChave:          "pix_chave",
+	PixNomeRecebedor:  "pix_nome_recebedor",
+	PixCidade:         "pix_cidade",
 }
 
 var TenantTableColumns = struct {
@@ -87,6 +105,12 @@ var TenantTableColumns = struct {
 	Cidade            string
 	SeqID             string
 	TaxaEntregaPadrao string
+	Timezone          string
+	Lat               string
+	Lng               string
+	PixChave          string
+	PixNomeRecebedor  string
+	PixCidade         string
 }{
 	ID:                "tenants.id",
 	Name:              "tenants.name",
@@ -101,6 +125,12 @@ var TenantTableColumns = struct {
 	Cidade:            "tenants.cidade",
 	SeqID:             "tenants.seq_id",
 	TaxaEntregaPadrao: "tenants.taxa_entrega_padrao",
+	Timezone:          "tenants.timezone",
+	Lat:               "tenants.lat",
+	Lng:               "tenants.lng",
+	PixChave:          "tenants.pix_chave",
+	PixNomeRecebedor:  "tenants.pix_nome_recebedor",
+	PixCidade:         "tenants.pix_cidade",
 }
 
 // Generated where
@@ -143,6 +173,12 @@ var TenantWhere = struct {
 	Cidade            whereHelpernull_String
 	SeqID             whereHelperint64
 	TaxaEntregaPadrao whereHelpertypes_NullDecimal
+	Timezone          whereHelperstring
+	Lat               whereHelpertypes_NullDecimal
+	Lng               whereHelpertypes_NullDecimal
+	PixChave          whereHelpernull_String
+	PixNomeRecebedor  whereHelpernull_String
+	PixCidade         whereHelpernull_String
 }{
 	ID:                whereHelperstring{field: "\"tenants\".\"id\""},
 	Name:              whereHelperstring{field: "\"tenants\".\"name\""},
@@ -157,6 +193,12 @@ var TenantWhere = struct {
 	Cidade:            whereHelpernull_String{field: "\"tenants\".\"cidade\""},
 	SeqID:             whereHelperint64{field: "\"tenants\".\"seq_id\""},
 	TaxaEntregaPadrao: whereHelpertypes_NullDecimal{field: "\"tenants\".\"taxa_entrega_padrao\""},
+	Timezone:          whereHelperstring{field: "\"tenants\".\"timezone\""},
+	Lat:               whereHelpertypes_NullDecimal{field: "\"tenants\".\"lat\""},
+	Lng:               whereHelpertypes_NullDecimal{field: "\"tenants\".\"lng\""},
+	PixChave:          whereHelpernull_String{field: "\"tenants\".\"pix_chave\""},
+	PixNomeRecebedor:  whereHelpernull_String{field: "\"tenants\".\"pix_nome_recebedor\""},
+	PixCidade:         whereHelpernull_String{field: "\"tenants\".\"pix_cidade\""},
 }
 
 // TenantRels is where relationship names are stored.
@@ -267,9 +309,9 @@ func (r *tenantR) GetUsers() UserSlice {
 type tenantL struct{}
 
 var (
-	tenantAllColumns            = []string{"id", "name", "plan", "status", "created_at", "id_cliente_padrao", "photo", "telefone", "endereco", "bairro", "cidade", "seq_id", "taxa_entrega_padrao"}
+	tenantAllColumns            = []string{"id", "name", "plan", "status", "created_at", "id_cliente_padrao", "photo", "telefone", "endereco", "bairro", "cidade", "seq_id", "taxa_entrega_padrao", "timezone", "lat", "lng", "pix_chave", "pix_nome_recebedor", "pix_cidade"}
 	tenantColumnsWithoutDefault = []string{"name", "plan", "status"}
-	tenantColumnsWithDefault    = []string{"id", "created_at", "id_cliente_padrao", "photo", "telefone", "endereco", "bairro", "cidade", "seq_id", "taxa_entrega_padrao"}
+	tenantColumnsWithDefault    = []string{"id", "created_at", "id_cliente_padrao", "photo", "telefone", "endereco", "bairro", "cidade", "seq_id", "taxa_entrega_padrao", "timezone", "lat", "lng", "pix_chave", "pix_nome_recebedor", "pix_cidade"}
 	tenantPrimaryKeyColumns     = []string{"id"}
 	tenantGeneratedColumns      = []string{}
 )
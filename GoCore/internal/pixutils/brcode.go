@@ -0,0 +1,231 @@
+// Package pixutils monta o BR Code do PIX (QR Code EMV do Banco Central):
+// campos TLV, CRC16-CCITT e a imagem PNG. Gera cobranças estáticas, com ou
+// sem valor; não fala com PSP nenhum, a confirmação do recebimento é manual.
+package pixutils
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Limites de tamanho do Manual do BR Code
+const (
+	MaxNomeRecebedor = 25
+	MaxCidade        = 15
+	MaxTxID          = 25
+	maxChave         = 77
+	maxDescricao     = 72
+	maxValorCampo    = 99 // o tamanho do TLV tem 2 dígitos
+
+	// txid de cobrança sem identificador (QR fixo do balcão)
+	TxIDSemIdentificador = "***"
+)
+
+// IDs dos campos EMV usados
+const (
+	campoPayloadFormat  = "00"
+	campoContaRecebedor = "26"
+	campoMCC            = "52"
+	campoMoeda          = "53"
+	campoValor          = "54"
+	campoPais           = "58"
+	campoNomeRecebedor  = "59"
+	campoCidade         = "60"
+	campoDadosAdicional = "62"
+	campoCRC            = "63"
+
+	subGUI       = "00"
+	subChave     = "01"
+	subDescricao = "02"
+	subTxID      = "05"
+
+	gui = "br.gov.bcb.pix"
+)
+
+var (
+	ErrChaveInvalida = errors.New("chave PIX inválida: use CPF, CNPJ, e-mail, celular (+55…) ou chave aleatória")
+	ErrTxIDInvalido  = errors.New("txid inválido: até 25 letras e números")
+
+	reTxID     = regexp.MustCompile(`^[A-Za-z0-9]{1,25}$`)
+	reTelefone = regexp.MustCompile(`^\+[1-9][0-9]{10,13}$`)
+	reDigitos  = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// BRCode é uma cobrança PIX estática. Valor em centavos; zero deixa o
+// valor para o pagador digitar.
+type BRCode struct {
+	Chave         string
+	NomeRecebedor string
+	Cidade        string
+	TxID          string
+	Descricao     string
+	Valor         int64
+}
+
+// Payload devolve o "copia e cola": os campos TLV seguidos do CRC
+func (b BRCode) Payload() (string, error) {
+	if err := ValidarChave(b.Chave); err != nil {
+		return "", err
+	}
+	nome := NormalizarTexto(b.NomeRecebedor, MaxNomeRecebedor)
+	cidade := NormalizarTexto(b.Cidade, MaxCidade)
+	if nome == "" || cidade == "" {
+		return "", errors.New("nome do recebedor e cidade são obrigatórios")
+	}
+	txid := b.TxID
+	if txid == "" {
+		txid = TxIDSemIdentificador
+	}
+	if txid != TxIDSemIdentificador && !reTxID.MatchString(txid) {
+		return "", ErrTxIDInvalido
+	}
+	if b.Valor < 0 {
+		return "", errors.New("valor da cobrança não pode ser negativo")
+	}
+
+	var t tlv
+	conta := t.campo(subGUI, gui) + t.campo(subChave, b.Chave)
+	// A descrição divide o campo 26 com a chave: usa o que sobra e, com
+	// chave longa, fica de fora
+	if sobra := maxValorCampo - len(conta) - 4; sobra > 0 {
+		if d := NormalizarTexto(b.Descricao, min(sobra, maxDescricao)); d != "" {
+			conta += t.campo(subDescricao, d)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(t.campo(campoPayloadFormat, "01"))
+	sb.WriteString(t.campo(campoContaRecebedor, conta))
+	sb.WriteString(t.campo(campoMCC, "0000"))
+	sb.WriteString(t.campo(campoMoeda, "986"))
+	if b.Valor > 0 {
+		sb.WriteString(t.campo(campoValor, fmt.Sprintf("%d.%02d", b.Valor/100, b.Valor%100)))
+	}
+	sb.WriteString(t.campo(campoPais, "BR"))
+	sb.WriteString(t.campo(campoNomeRecebedor, nome))
+	sb.WriteString(t.campo(campoCidade, cidade))
+	sb.WriteString(t.campo(campoDadosAdicional, t.campo(subTxID, txid)))
+	if t.err != nil {
+		return "", t.err
+	}
+
+	// O CRC cobre o próprio cabeçalho do campo 63
+	sb.WriteString(campoCRC + "04")
+	sb.WriteString(fmt.Sprintf("%04X", CRC16(sb.String())))
+	return sb.String(), nil
+}
+
+// QRCodePNG desenha o payload como QR Code; tamanho em pixels
+func QRCodePNG(payload string, tamanho int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, tamanho)
+}
+
+// CRC16 CCITT-FALSE (polinômio 0x1021, início 0xFFFF), exigido pelo BR Code
+func CRC16(s string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// TxIDDoPedido deriva o txid do código do pedido: só letras e números,
+// cortado em 25 caracteres
+func TxIDDoPedido(codigoPedido string) string {
+	txid := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, codigoPedido)
+	if len(txid) > MaxTxID {
+		txid = txid[:MaxTxID]
+	}
+	return txid
+}
+
+// ValidarTxID aceita só o formato do campo 62-05
+func ValidarTxID(txid string) error {
+	if !reTxID.MatchString(txid) {
+		return ErrTxIDInvalido
+	}
+	return nil
+}
+
+// ValidarChave confere o formato dos tipos de chave do DICT
+func ValidarChave(chave string) error {
+	switch {
+	case chave == "" || len(chave) > maxChave:
+		return ErrChaveInvalida
+	case reDigitos.MatchString(chave) && (len(chave) == 11 || len(chave) == 14):
+		return nil // CPF ou CNPJ
+	case reTelefone.MatchString(chave):
+		return nil
+	case strings.Contains(chave, "@"):
+		if a, err := mail.ParseAddress(chave); err == nil && a.Address == chave {
+			return nil
+		}
+	default:
+		if _, err := uuid.Parse(chave); err == nil && len(chave) == 36 {
+			return nil
+		}
+	}
+	return ErrChaveInvalida
+}
+
+// NormalizarTexto tira acentos e caracteres fora do ASCII imprimível, que
+// muitos apps de banco rejeitam, e corta no limite do campo
+func NormalizarTexto(s string, limite int) string {
+	semAcento, _, _ := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	limpo := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return -1
+		}
+		return r
+	}, semAcento)
+	limpo = strings.Join(strings.Fields(limpo), " ")
+	if len(limpo) > limite {
+		limpo = strings.TrimSpace(limpo[:limite])
+	}
+	return limpo
+}
+
+// campo monta um TLV: ID de 2 dígitos, tamanho de 2 dígitos e valor. Valor
+// acima de 99 bytes não cabe no tamanho e invalida o BR Code.
+func campo(id, valor string) (string, error) {
+	if len(valor) > maxValorCampo {
+		return "", fmt.Errorf("campo %s do BR Code com %d bytes, máximo %d", id, len(valor), maxValorCampo)
+	}
+	return fmt.Sprintf("%s%02d%s", id, len(valor), valor), nil
+}
+
+// tlv guarda o primeiro erro de campo, para montar o payload sem checar
+// cada chamada
+type tlv struct {
+	err error
+}
+
+func (t *tlv) campo(id, valor string) string {
+	c, err := campo(id, valor)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return c
+}
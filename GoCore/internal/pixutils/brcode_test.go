@@ -0,0 +1,181 @@
+package pixutils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		entrada string
+		want    uint16
+	}{
+		// valor de verificação do CRC-16/CCITT-FALSE
+		{"123456789", 0x29B1},
+		// exemplo do Manual do BR Code
+		{"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304", 0x1D3D},
+	}
+	for _, tt := range tests {
+		if got := CRC16(tt.entrada); got != tt.want {
+			t.Errorf("CRC16(%q) = %04X, want %04X", tt.entrada, got, tt.want)
+		}
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		nome string
+		br   BRCode
+		want string
+	}{
+		{
+			"exemplo do manual, sem valor nem txid",
+			BRCode{
+				Chave:         "123e4567-e12b-12d1-a456-426655440000",
+				NomeRecebedor: "Fulano de Tal",
+				Cidade:        "BRASILIA",
+			},
+			"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			"com valor, descrição e acentos",
+			BRCode{
+				Chave:         "fulano@example.com",
+				NomeRecebedor: "Pizzaria do Zé",
+				Cidade:        "São Paulo",
+				TxID:          "PED123",
+				Descricao:     "Pedido 123",
+				Valor:         1050,
+			},
+			"00020126540014br.gov.bcb.pix0118fulano@example.com0210Pedido 123520400005303986540510.505802BR5914Pizzaria do Ze6009Sao Paulo62100506PED1236304BDC8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			got, err := tt.br.Payload()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Payload() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadChaveLonga(t *testing.T) {
+	// e-mail de 77 caracteres, o máximo do DICT
+	chave := strings.Repeat("a", 60) + "@" + strings.Repeat("b", 12) + ".com"
+	tests := []struct {
+		nome      string
+		chave     string
+		descricao string
+	}{
+		{"chave no limite, descrição fica de fora", chave, "Pedido 20240101-000123"},
+		{"descrição cortada no que sobra", chave[len(chave)-40:], strings.Repeat("x", 72)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			br := BRCode{
+				Chave:         tt.chave,
+				NomeRecebedor: "Loja",
+				Cidade:        "Curitiba",
+				TxID:          "ABC",
+				Descricao:     tt.descricao,
+				Valor:         100,
+			}
+			payload, err := br.Payload()
+			if err != nil {
+				t.Fatal(err)
+			}
+			campos := lerTLV(t, payload)
+			if n := len(campos["26"]); n > 99 {
+				t.Fatalf("campo 26 com %d bytes", n)
+			}
+			if !strings.Contains(campos["26"], tt.chave) {
+				t.Fatalf("campo 26 sem a chave: %q", campos["26"])
+			}
+			corpo := payload[:len(payload)-4]
+			if crc := fmt.Sprintf("%04X", CRC16(corpo)); crc != campos["63"] {
+				t.Fatalf("CRC %s, calculado %s", campos["63"], crc)
+			}
+		})
+	}
+}
+
+func TestCampo(t *testing.T) {
+	if got, err := campo("05", "***"); err != nil || got != "0503***" {
+		t.Fatalf("campo() = %q, %v", got, err)
+	}
+	if _, err := campo("26", strings.Repeat("x", 99)); err != nil {
+		t.Fatalf("99 bytes: %v", err)
+	}
+	if _, err := campo("26", strings.Repeat("x", 100)); err == nil {
+		t.Fatal("100 bytes: want erro")
+	}
+}
+
+func TestTxIDDoPedido(t *testing.T) {
+	tests := []struct {
+		codigo string
+		want   string
+	}{
+		{"123", "123"},
+		{"PED-0001/A", "PED0001A"},
+		{"ção-9", "o9"},
+		{strings.Repeat("7", 30), strings.Repeat("7", 25)},
+	}
+	for _, tt := range tests {
+		if got := TxIDDoPedido(tt.codigo); got != tt.want {
+			t.Errorf("TxIDDoPedido(%q) = %q, want %q", tt.codigo, got, tt.want)
+		}
+	}
+}
+
+func TestValidarChave(t *testing.T) {
+	tests := []struct {
+		chave string
+		ok    bool
+	}{
+		{"12345678901", true},
+		{"12345678000199", true},
+		{"+5541999998888", true},
+		{"fulano@example.com", true},
+		{"123e4567-e12b-12d1-a456-426655440000", true},
+		{"", false},
+		{"1234", false},
+		{"41999998888", true}, // 11 dígitos: passa como CPF
+		{"fulano", false},
+		{"Fulano <fulano@example.com>", false},
+	}
+	for _, tt := range tests {
+		err := ValidarChave(tt.chave)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidarChave(%q) = %v, want ok=%v", tt.chave, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrChaveInvalida) {
+			t.Errorf("ValidarChave(%q) = %v, want ErrChaveInvalida", tt.chave, err)
+		}
+	}
+}
+
+// lerTLV separa os campos de primeiro nível do payload
+func lerTLV(t *testing.T, payload string) map[string]string {
+	t.Helper()
+	campos := make(map[string]string)
+	for i := 0; i < len(payload); {
+		if i+4 > len(payload) {
+			t.Fatalf("TLV truncado em %d: %q", i, payload[i:])
+		}
+		n, err := strconv.Atoi(payload[i+2 : i+4])
+		if err != nil || i+4+n > len(payload) {
+			t.Fatalf("tamanho inválido em %d: %q", i, payload[i:])
+		}
+		campos[payload[i:i+2]] = payload[i+4 : i+4+n]
+		i += 4 + n
+	}
+	return campos
+}
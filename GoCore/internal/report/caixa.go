@@ -0,0 +1,68 @@
+package report
+
+import "time"
+
+// Caixa é o resumo do caixa para o relatório de fechamento. Valores em
+// centavos.
+type Caixa struct {
+	Estabelecimento string
+	Numero          int64
+	Operador        string
+	Abertura        time.Time
+	Fechado         bool
+	Fechamento      time.Time
+	Observacao      string
+
+	ValorAbertura  int64
+	Suprimentos    int64
+	QtdSuprimentos int32
+	Sangrias       int64
+	QtdSangrias    int32
+	Estornos       int64
+	QtdEstornos    int32
+
+	Formas []CaixaForma
+}
+
+// CaixaForma é o esperado e o informado (fechamento às cegas) por forma de
+// pagamento
+type CaixaForma struct {
+	Nome      string
+	Esperado  int64
+	Informado int64
+	Informou  bool
+}
+
+func (f CaixaForma) Diferenca() int64 {
+	return f.Informado - f.Esperado
+}
+
+func (c Caixa) TotalEsperado() int64 {
+	var t int64
+	for _, f := range c.Formas {
+		t += f.Esperado
+	}
+	return t
+}
+
+func (c Caixa) TotalInformado() int64 {
+	var t int64
+	for _, f := range c.Formas {
+		t += f.Informado
+	}
+	return t
+}
+
+func (c Caixa) Diferenca() int64 {
+	return c.TotalInformado() - c.TotalEsperado()
+}
+
+// Informado diz se alguma forma teve valor informado no fechamento
+func (c Caixa) Informado() bool {
+	for _, f := range c.Formas {
+		if f.Informou {
+			return true
+		}
+	}
+	return false
+}
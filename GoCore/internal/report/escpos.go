@@ -0,0 +1,170 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Saída ESC/POS para impressoras térmicas: a mesma marcação dos templates
+// vira texto puro com comandos de alinhamento, negrito e tamanho duplo.
+
+// CodePage da impressora e o número que a seleciona (ESC t n, tabela Epson)
+type CodePage struct {
+	Nome    string
+	numero  byte
+	charmap *charmap.Charmap
+}
+
+var (
+	CP850 = CodePage{Nome: "cp850", numero: 2, charmap: charmap.CodePage850}
+	CP860 = CodePage{Nome: "cp860", numero: 3, charmap: charmap.CodePage860}
+)
+
+// CodePagePorNome aceita "cp850" e "cp860"; vazio é cp850.
+func CodePagePorNome(nome string) (CodePage, bool) {
+	switch strings.ToLower(nome) {
+	case "", "cp850", "850":
+		return CP850, true
+	case "cp860", "860":
+		return CP860, true
+	}
+	return CodePage{}, false
+}
+
+// Colunas da fonte A nas bobinas mais comuns
+const (
+	ColunasBobina80mm = 48
+	ColunasBobina58mm = 32
+)
+
+// EscPos configura a impressão
+type EscPos struct {
+	CodePage    CodePage
+	Colunas     int
+	Cortar      bool // corte parcial do papel no fim
+	AbrirGaveta bool // pulso na gaveta de dinheiro (pino 2)
+}
+
+// EscPosPadrao é a bobina de 80mm em CP850, com corte e sem gaveta
+func EscPosPadrao() EscPos {
+	return EscPos{CodePage: CP850, Colunas: ColunasBobina80mm, Cortar: true}
+}
+
+// títulos saem com largura dupla
+func (e EscPos) colunas(titulo bool) int {
+	if titulo {
+		return e.Colunas / 2
+	}
+	return e.Colunas
+}
+
+// EscPosPedido gera o comprovante do cliente, com preços
+func EscPosPedido(p Pedido, e EscPos) ([]byte, error) {
+	return e.gerar("pedido.tmpl", dadosTemplate{Pedido: p})
+}
+
+// EscPosCozinha gera a comanda da cozinha: itens, adicionais e observações,
+// sem preços
+func EscPosCozinha(p Pedido, e EscPos) ([]byte, error) {
+	return e.gerar("cozinha.tmpl", dadosTemplate{Pedido: p})
+}
+
+// EscPosCaixa gera o relatório de fechamento (ou resumo, se aberto) do caixa
+func EscPosCaixa(c Caixa, e EscPos) ([]byte, error) {
+	return e.gerar("caixa.tmpl", c)
+}
+
+func (e EscPos) gerar(template string, dados any) ([]byte, error) {
+	texto, err := executar(template, dados)
+	if err != nil {
+		return nil, err
+	}
+	return escreverEscPos(diagramar(texto, e.colunas), e), nil
+}
+
+var (
+	escInicializar  = []byte{0x1b, '@'}
+	escAlinharEsq   = []byte{0x1b, 'a', 0}
+	escAlinharCentr = []byte{0x1b, 'a', 1}
+	escNegritoOn    = []byte{0x1b, 'E', 1}
+	escNegritoOff   = []byte{0x1b, 'E', 0}
+	escTamanhoDuplo = []byte{0x1d, '!', 0x11}
+	escTamanhoNorm  = []byte{0x1d, '!', 0x00}
+	escAvancar      = []byte{0x1b, 'd', 4}
+	escGaveta       = []byte{0x1b, 'p', 0, 25, 250}
+	escCortar       = []byte{0x1d, 'V', 66, 0}
+)
+
+func escreverEscPos(linhas []linha, e EscPos) []byte {
+	enc := encoding.ReplaceUnsupported(e.CodePage.charmap.NewEncoder())
+
+	var buf bytes.Buffer
+	buf.Write(escInicializar)
+	buf.Write([]byte{0x1b, 't', e.CodePage.numero})
+
+	for _, ln := range linhas {
+		if ln.tipo == linhaCentro {
+			buf.Write(escAlinharCentr)
+		}
+		if ln.negrito {
+			buf.Write(escNegritoOn)
+		}
+		if ln.titulo {
+			buf.Write(escTamanhoDuplo)
+		}
+
+		var texto string
+		switch ln.tipo {
+		case linhaSeparador:
+			texto = strings.Repeat("-", ln.colunas)
+		case linhaBranca:
+		default:
+			texto = ln.texto
+			if ln.direita != "" {
+				espaco := ln.colunas - utf8.RuneCountInString(texto) - utf8.RuneCountInString(ln.direita)
+				texto += strings.Repeat(" ", max(espaco, 1)) + ln.direita
+			}
+		}
+		bs, err := enc.Bytes([]byte(semControle(texto)))
+		if err != nil {
+			bs = []byte(texto)
+		}
+		buf.Write(bs)
+		buf.WriteByte('\n')
+
+		if ln.titulo {
+			buf.Write(escTamanhoNorm)
+		}
+		if ln.negrito {
+			buf.Write(escNegritoOff)
+		}
+		if ln.tipo == linhaCentro {
+			buf.Write(escAlinharEsq)
+		}
+	}
+
+	buf.Write(escAvancar)
+	if e.AbrirGaveta {
+		buf.Write(escGaveta)
+	}
+	if e.Cortar {
+		buf.Write(escCortar)
+	}
+	return buf.Bytes()
+}
+
+// semControle impede que texto digitado (observações, nomes) carregue
+// comandos ESC/POS
+func semControle(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// PDF mínimo: só as fontes padrão Courier e Courier-Bold (não precisam ser
+// embutidas) com WinAnsiEncoding, que cobre os acentos do português. Como a
+// fonte é monoespaçada a largura de cada linha é exata.
+
+const entrelinha = 1.25
+
+var winAnsi = encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+
+func escreverPDF(linhas []linha, l Layout) []byte {
+	largura := mmParaPt(l.LarguraMM)
+	margem := mmParaPt(l.MargemMM)
+
+	altura := mmParaPt(l.AlturaMM)
+	if l.AlturaMM == 0 {
+		// bobina: a página tem a altura do conteúdo
+		altura = 2 * margem
+		for _, ln := range linhas {
+			altura += alturaLinha(ln, l)
+		}
+	}
+
+	var (
+		paginas []string
+		pagina  strings.Builder
+		y       = altura - margem
+	)
+	for _, ln := range linhas {
+		h := alturaLinha(ln, l)
+		if y-h < margem && pagina.Len() > 0 {
+			paginas = append(paginas, pagina.String())
+			pagina.Reset()
+			y = altura - margem
+		}
+		y -= h
+		desenharLinha(&pagina, ln, l.corpo(ln.titulo), margem, largura-margem, y)
+	}
+	paginas = append(paginas, pagina.String())
+
+	return montarPDF(paginas, largura, altura)
+}
+
+func alturaLinha(ln linha, l Layout) float64 {
+	if ln.tipo == linhaSeparador {
+		return l.corpo(ln.titulo) * 0.8
+	}
+	return l.corpo(ln.titulo) * entrelinha
+}
+
+func desenharLinha(b *strings.Builder, ln linha, corpo, x0, x1, y float64) {
+	// linha de base um pouco acima do fundo da linha
+	base := y + corpo*(entrelinha-1)
+
+	switch ln.tipo {
+	case linhaSeparador:
+		meio := y + corpo*0.4
+		fmt.Fprintf(b, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x0, meio, x1, meio)
+		return
+	case linhaBranca:
+		return
+	}
+
+	avanco := corpo * larguraGlifo
+	switch ln.tipo {
+	case linhaCentro:
+		w := float64(utf8.RuneCountInString(ln.texto)) * avanco
+		texto(b, ln.negrito, corpo, x0+(x1-x0-w)/2, base, ln.texto)
+	default:
+		texto(b, ln.negrito, corpo, x0, base, ln.texto)
+	}
+	if ln.direita != "" {
+		w := float64(utf8.RuneCountInString(ln.direita)) * avanco
+		texto(b, ln.negrito, corpo, x1-w, base, ln.direita)
+	}
+}
+
+func texto(b *strings.Builder, negrito bool, corpo, x, y float64, s string) {
+	if s == "" {
+		return
+	}
+	fonte := "F1"
+	if negrito {
+		fonte = "F2"
+	}
+	fmt.Fprintf(b, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fonte, corpo, x, y, escapar(s))
+}
+
+// escapar converte para WinAnsi (caracteres fora dele viram "?") e escapa a
+// string literal do PDF
+func escapar(s string) string {
+	bs, err := winAnsi.String(s)
+	if err != nil {
+		bs = s
+	}
+	var b strings.Builder
+	for i := 0; i < len(bs); i++ {
+		c := bs[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// montarPDF escreve os objetos e a tabela xref
+func montarPDF(paginas []string, largura, altura float64) []byte {
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+	obj := func(corpo string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), corpo)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catálogo, 2 páginas, 3 e 4 fontes, 5 info; depois página e conteúdo
+	const primeiraPagina = 6
+	kids := make([]string, len(paginas))
+	for i := range paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", primeiraPagina+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(paginas)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Producer (gobid) /CreationDate (D:%s) >>", time.Now().UTC().Format("20060102150405Z")))
+
+	for i, conteudo := range paginas {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			largura, altura, primeiraPagina+2*i+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(conteudo), conteudo))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,191 @@
+// Package report gera o comprovante do pedido, a comanda da cozinha e o
+// fechamento de caixa sem depender de serviço externo. O conteúdo vem de um
+// template de texto (templates/*.tmpl) com uma marcação simples por linha,
+// que é diagramada na largura da saída e escrita em PDF (A4 ou bobina de
+// 80mm) ou em ESC/POS para impressoras térmicas.
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Pedido é o que o comprovante precisa saber do pedido. Valores monetários
+// em centavos.
+type Pedido struct {
+	Estabelecimento Estabelecimento
+	Cliente         Cliente
+
+	Codigo      string
+	Data        time.Time
+	TipoEntrega string
+	Status      string
+	Observacao  string
+
+	Itens      []Item
+	Pagamentos []Pagamento
+
+	Subtotal        int64
+	TaxaEntrega     int64
+	NomeTaxaEntrega string
+	Desconto        int64
+	Acrescimo       int64
+	Total           int64
+	TrocoPara       int64
+	Troco           int64
+	ValorPago       int64
+}
+
+type Estabelecimento struct {
+	Nome     string
+	Endereco string
+	Bairro   string
+	Cidade   string
+	Telefone string
+}
+
+type Cliente struct {
+	Nome        string
+	Telefone    string
+	Celular     string
+	Cep         string
+	Logradouro  string
+	Numero      string
+	Complemento string
+	Bairro      string
+	Cidade      string
+	Uf          string
+}
+
+type Item struct {
+	Quantidade    int32
+	Categoria     string
+	Produto       string
+	Produto2      string // segunda metade da pizza meio-a-meio
+	Sabores       []Sabor
+	Opcao         string
+	ValorUnitario int64
+	Observacao    string
+	Adicionais    []Adicional
+	// Escolha de um combo; vem logo depois do item do combo e o valor é
+	// só o acréscimo do slot
+	Componente bool
+}
+
+// Sabor do item com mais de um sabor; a fração é Partes / soma das partes
+type Sabor struct {
+	Nome   string
+	Partes int
+}
+
+// Adicional de uma unidade do item: Quantidade e Valor são por unidade.
+type Adicional struct {
+	Nome       string
+	Quantidade int32
+	Valor      int64
+}
+
+type Pagamento struct {
+	Forma     string
+	Categoria string
+	Valor     int64
+	Troco     int64
+	Data      time.Time
+}
+
+// Linhas do endereço do estabelecimento no cabeçalho
+func (e Estabelecimento) Linhas() []string {
+	var linhas []string
+	if l := juntar(" - ", e.Endereco, e.Bairro); l != "" {
+		linhas = append(linhas, l)
+	}
+	if e.Cidade != "" {
+		linhas = append(linhas, e.Cidade)
+	}
+	if e.Telefone != "" {
+		linhas = append(linhas, "Tel.: "+e.Telefone)
+	}
+	return linhas
+}
+
+// Telefones do cliente, celular primeiro
+func (c Cliente) Telefones() string {
+	return juntar(" / ", c.Celular, c.Telefone)
+}
+
+// Endereco no formato do comprovante antigo:
+// logradouro, número - bairro / cidade, UF / complemento / CEP
+func (c Cliente) Endereco() []string {
+	var linhas []string
+	if l := juntar(" - ", juntar(", ", c.Logradouro, c.Numero), c.Bairro); l != "" {
+		linhas = append(linhas, l)
+	}
+	if l := juntar(", ", c.Cidade, c.Uf); l != "" {
+		linhas = append(linhas, l)
+	}
+	if c.Complemento != "" {
+		linhas = append(linhas, c.Complemento)
+	}
+	if c.Cep != "" {
+		linhas = append(linhas, "CEP "+c.Cep)
+	}
+	return linhas
+}
+
+// Descricao do item: "(Pizza meio-meio) A + B" quando há segunda metade,
+// "(Pizza 3 sabores) A + B + C" com mais sabores (com a fração de cada um
+// quando não são iguais), seguido da opção (tamanho) se houver. Componente
+// de combo começa com "+".
+func (i Item) Descricao() string {
+	d := i.Produto
+	switch {
+	case len(i.Sabores) > 2 || (len(i.Sabores) == 2 && i.Sabores[0].Partes != i.Sabores[1].Partes):
+		d = fmt.Sprintf("(Pizza %d sabores) %s", len(i.Sabores), i.descricaoSabores())
+	case i.Produto2 != "":
+		d = fmt.Sprintf("(Pizza meio-meio) %s + %s", i.Produto, i.Produto2)
+	}
+	if i.Opcao != "" {
+		d += " - " + i.Opcao
+	}
+	if i.Componente {
+		d = "+ " + d
+	}
+	return d
+}
+
+func (i Item) descricaoSabores() string {
+	total, iguais := 0, true
+	for _, s := range i.Sabores {
+		total += s.Partes
+		iguais = iguais && s.Partes == i.Sabores[0].Partes
+	}
+	nomes := make([]string, len(i.Sabores))
+	for j, s := range i.Sabores {
+		nomes[j] = s.Nome
+		if !iguais {
+			nomes[j] = fmt.Sprintf("%d/%d %s", s.Partes, total, s.Nome)
+		}
+	}
+	return strings.Join(nomes, " + ")
+}
+
+// Total do item sem os adicionais
+func (i Item) Total() int64 {
+	return i.ValorUnitario * int64(i.Quantidade)
+}
+
+// Total do adicional considerando a quantidade do item
+func (i Item) TotalAdicional(a Adicional) int64 {
+	return a.Valor * int64(a.Quantidade) * int64(i.Quantidade)
+}
+
+func juntar(sep string, partes ...string) string {
+	var s []string
+	for _, p := range partes {
+		if p = strings.TrimSpace(p); p != "" {
+			s = append(s, p)
+		}
+	}
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,284 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"moeda":  Moeda,
+	"data":   func(t time.Time) string { return t.Format("02/01/2006 15:04") },
+	"texto":  escaparTexto,
+	"linhas": escaparLinhas,
+}).ParseFS(templatesFS, "templates/*.tmpl"))
+
+// Marcas que o template põe em volta do que vem do usuário (nomes,
+// endereços, observações) para a marcação não ser interpretada ali. Somem
+// na diagramação.
+const (
+	marcaLiteral = "\uE000" // daqui até o fim da linha é texto
+	marcaBarra   = "\uE001" // "|" que não separa colunas
+)
+
+var escapeTexto = strings.NewReplacer(
+	marcaLiteral, "",
+	marcaBarra, "",
+	"|", marcaBarra,
+	"\r\n", " ",
+	"\r", " ",
+	"\n", " ",
+)
+
+// escaparTexto escapa um valor do usuário para uma linha só
+func escaparTexto(s string) string {
+	return marcaLiteral + escapeTexto.Replace(s)
+}
+
+// escaparLinhas escapa um texto do usuário mantendo as quebras de linha, que viram
+// linhas de texto simples
+func escaparLinhas(s string) string {
+	partes := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, p := range partes {
+		partes[i] = escaparTexto(p)
+	}
+	return strings.Join(partes, "\n")
+}
+
+var restauraTexto = strings.NewReplacer(marcaLiteral, "", marcaBarra, "|")
+
+// Layout define o papel. Altura zero é bobina: uma página só, do tamanho do
+// conteúdo.
+type Layout struct {
+	Nome      string
+	LarguraMM float64
+	AlturaMM  float64
+	MargemMM  float64
+	Fonte     float64 // corpo do texto, em pontos
+	Titulo    float64
+}
+
+var (
+	LayoutA4 = Layout{Nome: "a4", LarguraMM: 210, AlturaMM: 297, MargemMM: 15, Fonte: 10, Titulo: 14}
+	// ~42 colunas, como as impressoras térmicas
+	Layout80mm = Layout{Nome: "80mm", LarguraMM: 80, MargemMM: 4, Fonte: 8, Titulo: 10}
+)
+
+// LayoutPorNome aceita "a4" e "80mm"; vazio é 80mm.
+func LayoutPorNome(nome string) (Layout, bool) {
+	switch strings.ToLower(nome) {
+	case "", "80mm", "80":
+		return Layout80mm, true
+	case "a4":
+		return LayoutA4, true
+	}
+	return Layout{}, false
+}
+
+// largura da fonte monoespaçada (Courier) em fração do corpo
+const larguraGlifo = 0.6
+
+func mmParaPt(mm float64) float64 { return mm * 72 / 25.4 }
+
+// corpo da fonte da linha, em pontos
+func (l Layout) corpo(titulo bool) float64 {
+	if titulo {
+		return l.Titulo
+	}
+	return l.Fonte
+}
+
+// colunas de texto que cabem numa linha
+func (l Layout) colunas(titulo bool) int {
+	util := mmParaPt(l.LarguraMM - 2*l.MargemMM)
+	return int(util / (l.corpo(titulo) * larguraGlifo))
+}
+
+// RenderPedido gera o PDF do comprovante do pedido
+func RenderPedido(p Pedido, l Layout) ([]byte, error) {
+	texto, err := executar("pedido.tmpl", dadosTemplate{Pedido: p, Layout: l, A4: l.AlturaMM > 0})
+	if err != nil {
+		return nil, err
+	}
+	return escreverPDF(diagramar(texto, l.colunas), l), nil
+}
+
+type dadosTemplate struct {
+	Pedido
+	Layout Layout
+	A4     bool
+}
+
+func executar(nome string, dados any) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, nome, dados); err != nil {
+		return "", fmt.Errorf("template %s: %w", nome, err)
+	}
+	return buf.String(), nil
+}
+
+type tipoLinha int
+
+const (
+	linhaTexto tipoLinha = iota
+	linhaCentro
+	linhaColunas
+	linhaSeparador
+	linhaBranca
+)
+
+// linha já quebrada na largura da saída
+type linha struct {
+	tipo    tipoLinha
+	texto   string
+	direita string // linhaColunas
+	negrito bool
+	titulo  bool
+	colunas int
+}
+
+// diagramar interpreta a marcação do template e quebra as linhas. colunas
+// informa quantos caracteres cabem numa linha de texto ou de título. Linha
+// que começa com texto do usuário (ver escaparTexto) não tem marcação.
+func diagramar(saida string, colunas func(titulo bool) int) []linha {
+	var linhas []linha
+	for _, bruta := range strings.Split(saida, "\n") {
+		bruta = strings.TrimRight(bruta, " \t\r")
+		if strings.TrimSpace(restauraTexto.Replace(bruta)) == "" {
+			continue
+		}
+
+		var base linha
+		if strings.HasPrefix(bruta, "! ") {
+			base.negrito = true
+			bruta = bruta[2:]
+		}
+
+		base.colunas = colunas(false)
+		switch {
+		case strings.HasPrefix(bruta, marcaLiteral):
+		case bruta == "-":
+			base.tipo = linhaSeparador
+			linhas = append(linhas, base)
+			continue
+		case bruta == ".":
+			base.tipo = linhaBranca
+			linhas = append(linhas, base)
+			continue
+		case strings.HasPrefix(bruta, "# "):
+			base.tipo, base.negrito, base.titulo = linhaCentro, true, true
+			bruta = bruta[2:]
+		case strings.HasPrefix(bruta, "= "):
+			base.tipo = linhaCentro
+			bruta = bruta[2:]
+		case strings.HasPrefix(bruta, "> "):
+			base.tipo = linhaColunas
+			bruta = bruta[2:]
+		}
+		if base.titulo {
+			base.colunas = colunas(true)
+		}
+
+		if base.tipo == linhaColunas {
+			esq, dir, _ := strings.Cut(bruta, " | ")
+			linhas = append(linhas, duasColunas(base,
+				restauraTexto.Replace(esq), strings.TrimSpace(restauraTexto.Replace(dir)))...)
+			continue
+		}
+		for _, t := range quebrar(restauraTexto.Replace(bruta), base.colunas) {
+			ln := base
+			ln.texto = t
+			linhas = append(linhas, ln)
+		}
+	}
+	return linhas
+}
+
+// duasColunas quebra o texto da esquerda deixando espaço para o da direita
+// na última linha
+func duasColunas(base linha, esq, dir string) []linha {
+	partes := quebrar(esq, base.colunas)
+	ultima := partes[len(partes)-1]
+	if dir != "" && utf8.RuneCountInString(ultima)+1+utf8.RuneCountInString(dir) > base.colunas {
+		partes = append(partes, "")
+	}
+	linhas := make([]linha, len(partes))
+	for i, t := range partes {
+		linhas[i] = base
+		linhas[i].texto = t
+		if i < len(partes)-1 {
+			linhas[i].tipo = linhaTexto
+		} else {
+			linhas[i].direita = dir
+		}
+	}
+	return linhas
+}
+
+// quebrar divide o texto em linhas de até n colunas, por palavra. As
+// continuações mantêm a indentação da primeira linha.
+func quebrar(texto string, n int) []string {
+	corpo := strings.TrimLeft(texto, " ")
+	indent := texto[:len(texto)-len(corpo)]
+	if n <= len(indent)+1 {
+		indent = ""
+	}
+
+	var (
+		linhas []string
+		atual  = indent
+	)
+	for _, palavra := range strings.Fields(corpo) {
+		for utf8.RuneCountInString(palavra) > n-len(indent) {
+			// palavra maior que a linha: corta
+			if atual != indent {
+				linhas = append(linhas, atual)
+				atual = indent
+			}
+			r := []rune(palavra)
+			linhas = append(linhas, indent+string(r[:n-len(indent)]))
+			palavra = string(r[n-len(indent):])
+		}
+		if palavra == "" {
+			continue
+		}
+		switch {
+		case atual == indent:
+			atual += palavra
+		case utf8.RuneCountInString(atual)+1+utf8.RuneCountInString(palavra) <= n:
+			atual += " " + palavra
+		default:
+			linhas = append(linhas, atual)
+			atual = indent + palavra
+		}
+	}
+	if atual != indent || len(linhas) == 0 {
+		linhas = append(linhas, atual)
+	}
+	return linhas
+}
+
+// Moeda formata centavos como "R$ 1.234,56"
+func Moeda(centavos int64) string {
+	sinal := ""
+	if centavos < 0 {
+		sinal = "-"
+		centavos = -centavos
+	}
+	inteiro := fmt.Sprintf("%d", centavos/100)
+	var b strings.Builder
+	for i, c := range inteiro {
+		if i > 0 && (len(inteiro)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sinal, b.String(), centavos%100)
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiagramarTextoDoUsuario(t *testing.T) {
+	p := Pedido{
+		Codigo:     "10",
+		Data:       time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC),
+		Cliente:    Cliente{Nome: "# Zé | > x"},
+		Observacao: "sem troco\n# urgente\n-\n> a | b\n! c",
+		Itens: []Item{
+			{Quantidade: 1, Produto: "Pizza | grande", Observacao: "sem\n- cebola", ValorUnitario: 3000},
+		},
+	}
+	saida, err := executar("pedido.tmpl", dadosTemplate{Pedido: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linhas := diagramar(saida, Layout80mm.colunas)
+
+	tests := []struct {
+		texto   string
+		tipo    tipoLinha
+		direita string
+		negrito bool
+	}{
+		{"Cliente: # Zé | > x", linhaTexto, "", true},
+		{"(1x) Pizza | grande", linhaColunas, "R$ 30,00", false},
+		{"  Obs.: sem - cebola", linhaTexto, "", false},
+		{"sem troco", linhaTexto, "", false},
+		{"# urgente", linhaTexto, "", false},
+		{"-", linhaTexto, "", false},
+		{"> a | b", linhaTexto, "", false},
+		{"! c", linhaTexto, "", false},
+	}
+	for _, tt := range tests {
+		var achou *linha
+		for i := range linhas {
+			if linhas[i].texto == tt.texto {
+				achou = &linhas[i]
+				break
+			}
+		}
+		if achou == nil {
+			t.Errorf("linha %q não encontrada", tt.texto)
+			continue
+		}
+		if achou.tipo != tt.tipo || achou.direita != tt.direita || achou.negrito != tt.negrito {
+			t.Errorf("linha %q: tipo %d, direita %q, negrito %v; want %d, %q, %v",
+				tt.texto, achou.tipo, achou.direita, achou.negrito, tt.tipo, tt.direita, tt.negrito)
+		}
+	}
+}
+
+func TestQuebrar(t *testing.T) {
+	tests := []struct {
+		texto string
+		n     int
+		want  []string
+	}{
+		{"abc def", 10, []string{"abc def"}},
+		{"abc def ghi", 7, []string{"abc def", "ghi"}},
+		{"  abc def ghi", 9, []string{"  abc def", "  ghi"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"", 10, []string{""}},
+	}
+	for _, tt := range tests {
+		got := quebrar(tt.texto, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("quebrar(%q, %d) = %q, want %q", tt.texto, tt.n, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("quebrar(%q, %d) = %q, want %q", tt.texto, tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+)
+
+// Regras de seleção dos grupos de adicionais (categoria_adicionais.selecao):
+//
+//	U → obrigatório escolher exatamente uma opção, com quantidade 1
+//	M → várias opções distintas, cada uma com quantidade 1, até limite
+//	Q → quantidades livres; a soma fica entre minimo e limite
+//
+// Grupos marcados como is_main são obrigatórios mesmo quando M ou Q.
+// limite nulo ou zero significa sem limite.
+const (
+	selecaoUnica      = "U"
+	selecaoMultipla   = "M"
+	selecaoQuantidade = "Q"
+)
+
+type selecaoGrupo struct {
+	distintos map[uuid.UUID]bool
+	total     int
+	primeiro  int  // índice do primeiro adicional do grupo no item
+	repetido  bool // opção com quantidade > 1 ou informada mais de uma vez
+}
+
+// validarGruposAdicionais confere os adicionais de um item contra os grupos
+// ativos da categoria. Adicionais inexistentes ou de outra categoria já são
+// apontados na precificação e aqui são ignorados.
+func validarGruposAdicionais(c *cotacao, idx *int, item *dto.CotacaoItemDTO,
+	opcoes map[uuid.UUID]pgstore.ListAdicionalOpcoesCotacaoRow, grupos []pgstore.ListGruposAdicionaisCotacaoRow) {
+
+	idCategoria := uuid.MustParse(item.IDCategoria)
+
+	selecoes := make(map[uuid.UUID]*selecaoGrupo)
+	for j, a := range item.Adicionais {
+		opcao, ok := opcoes[uuid.MustParse(a.IDAdicionalOpcao)]
+		if !ok || opcao.IDCategoria != idCategoria {
+			continue
+		}
+		sel, ok := selecoes[opcao.IDCategoriaAdicional]
+		if !ok {
+			sel = &selecaoGrupo{distintos: make(map[uuid.UUID]bool), primeiro: j}
+			selecoes[opcao.IDCategoriaAdicional] = sel
+		}
+		if a.Quantidade > 1 || sel.distintos[opcao.ID] {
+			sel.repetido = true
+		}
+		sel.distintos[opcao.ID] = true
+		sel.total += a.Quantidade
+	}
+
+	for _, g := range grupos {
+		if g.IDCategoria != idCategoria {
+			continue
+		}
+
+		sel := selecoes[g.ID]
+		if sel == nil {
+			sel = &selecaoGrupo{distintos: map[uuid.UUID]bool{}, primeiro: -1}
+		}
+		var adicional *int
+		if sel.primeiro >= 0 {
+			j := sel.primeiro
+			adicional = &j
+		}
+		problema := func(codigo, format string, args ...any) {
+			c.problema(idx, adicional, "adicionais", codigo, format, args...)
+			c.problemas[len(c.problemas)-1].IDCategoriaAdicional = g.ID.String()
+		}
+
+		limite := 0
+		if g.Limite.Valid {
+			limite = int(g.Limite.Int32)
+		}
+
+		switch g.Selecao {
+		case selecaoUnica:
+			switch {
+			case sel.total == 0:
+				problema(dto.ProblemaGrupoObrigatorio, "item %d: escolha uma opção de %s", *idx+1, g.Nome)
+			case len(sel.distintos) > 1 || sel.repetido:
+				problema(dto.ProblemaGrupoUnico, "item %d: %s permite apenas uma opção", *idx+1, g.Nome)
+			}
+
+		case selecaoMultipla:
+			switch {
+			case sel.total == 0 && g.IsMain.Bool:
+				problema(dto.ProblemaGrupoObrigatorio, "item %d: escolha ao menos uma opção de %s", *idx+1, g.Nome)
+			case sel.repetido:
+				problema(dto.ProblemaGrupoQuantidade, "item %d: em %s cada opção pode ser escolhida uma vez", *idx+1, g.Nome)
+			case limite > 0 && len(sel.distintos) > limite:
+				problema(dto.ProblemaGrupoLimite, "item %d: %s permite no máximo %d opções", *idx+1, g.Nome, limite)
+			}
+
+		case selecaoQuantidade:
+			minimo := 0
+			if g.Minimo.Valid {
+				minimo = int(g.Minimo.Int32)
+			}
+			switch {
+			case sel.total == 0 && g.IsMain.Bool && minimo == 0:
+				problema(dto.ProblemaGrupoObrigatorio, "item %d: escolha ao menos uma opção de %s", *idx+1, g.Nome)
+			case sel.total < minimo:
+				problema(dto.ProblemaGrupoMinimo, "item %d: %s exige ao menos %d unidades (informado %d)", *idx+1, g.Nome, minimo, sel.total)
+			case limite > 0 && sel.total > limite:
+				problema(dto.ProblemaGrupoLimite, "item %d: %s permite no máximo %d unidades (informado %d)", *idx+1, g.Nome, limite, sel.total)
+			}
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Pedidos liberados por transação em cada passada do job
+const loteLiberacao = 100
+
+var (
+	ErrAgendamentoDesativado = errors.New("agendamento de pedidos não está ativo")
+	ErrPedidoNaoAgendado     = errors.New("pedido não está agendado")
+)
+
+// AgendamentoInvalidoError aponta horário ou configuração fora das regras
+type AgendamentoInvalidoError struct {
+	Mensagem string
+}
+
+func (e *AgendamentoInvalidoError) Error() string { return e.Mensagem }
+
+// HorarioLotadoError: a faixa do horário pedido já atingiu a capacidade
+type HorarioLotadoError struct {
+	Inicio     time.Time
+	Fim        time.Time
+	Capacidade int32
+	Ocupados   int32
+}
+
+func (e *HorarioLotadoError) Error() string {
+	if e.Capacidade == 0 {
+		return fmt.Sprintf("a faixa das %s às %s não aceita agendamento",
+			e.Inicio.Format("15:04"), e.Fim.Format("15:04"))
+	}
+	return fmt.Sprintf("a faixa das %s às %s está lotada (%d de %d pedidos)",
+		e.Inicio.Format("15:04"), e.Fim.Format("15:04"), e.Ocupados, e.Capacidade)
+}
+
+// AgendamentoService cuida dos pedidos agendados: configuração por tenant,
+// capacidade das faixas de horário e liberação para a cozinha. O pedido
+// agendado fica no status Agendado, fora do KDS, e recebe o código
+// definitivo na liberação (trigger liberar_pedido_agendado).
+type AgendamentoService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewAgendamentoService(pool *pgxpool.Pool) AgendamentoService {
+	return AgendamentoService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (as *AgendamentoService) GetConfig(ctx context.Context, tenantID uuid.UUID) (dto.AgendamentoConfigResponse, error) {
+	cfg, err := as.config(ctx, as.queries, tenantID)
+	if err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	faixas, err := as.queries.ListAgendamentoFaixas(ctx, tenantID)
+	if err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	return dto.AgendamentoConfigToResponse(cfg, faixas), nil
+}
+
+// SalvarConfig grava a configuração e substitui as faixas de capacidade
+func (as *AgendamentoService) SalvarConfig(ctx context.Context, in dto.AgendamentoConfigDTO) (dto.AgendamentoConfigResponse, error) {
+	if 1440%in.IntervaloMinutos != 0 {
+		return dto.AgendamentoConfigResponse{}, &AgendamentoInvalidoError{Mensagem: "intervalo_minutos deve dividir o dia (ex.: 15, 20, 30, 60)"}
+	}
+	faixas := make([]pgstore.CreateAgendamentoFaixaParams, len(in.Faixas))
+	for i, f := range in.Faixas {
+		params, err := f.ToCreateParams(in.TenantID)
+		if err != nil {
+			return dto.AgendamentoConfigResponse{}, &AgendamentoInvalidoError{Mensagem: fmt.Sprintf("faixa %d: %v", i, err)}
+		}
+		if params.HoraInicio.Microseconds >= params.HoraFim.Microseconds {
+			return dto.AgendamentoConfigResponse{}, &AgendamentoInvalidoError{Mensagem: fmt.Sprintf("faixa %d: hora_inicio deve ser anterior a hora_fim", i)}
+		}
+		faixas[i] = params
+	}
+
+	tx, err := as.pool.Begin(ctx)
+	if err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := as.queries.WithTx(tx)
+
+	cfg, err := q.UpsertAgendamentoConfig(ctx, in.ToUpsertParams())
+	if err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	if err := q.DeleteAgendamentoFaixas(ctx, in.TenantID); err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	for _, f := range faixas {
+		if err := q.CreateAgendamentoFaixa(ctx, f); err != nil {
+			return dto.AgendamentoConfigResponse{}, err
+		}
+	}
+	salvas, err := q.ListAgendamentoFaixas(ctx, in.TenantID)
+	if err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.AgendamentoConfigResponse{}, err
+	}
+	return dto.AgendamentoConfigToResponse(&cfg, salvas), nil
+}
+
+// ValidarHorario confere se o pedido pode ser agendado para o horário:
+// agendamento ativo, antecedência mínima, limite de dias e vaga na faixa.
+// idPedido (reagendamento) não conta na ocupação. A capacidade é
+// conferida de novo, sob lock, pelo trigger do pedido.
+func (as *AgendamentoService) ValidarHorario(ctx context.Context, tenantID uuid.UUID, idPedido *uuid.UUID, tipoEntrega string, horario time.Time) error {
+	cfg, err := as.config(ctx, as.queries, tenantID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || !cfg.Ativo {
+		return ErrAgendamentoDesativado
+	}
+	if tipoEntrega == "Balcão" {
+		return &AgendamentoInvalidoError{Mensagem: "agendamento vale para Delivery e Retirada"}
+	}
+
+	agora := time.Now()
+	if horario.Before(agora.Add(time.Duration(cfg.AntecedenciaMinimaMin) * time.Minute)) {
+		return &AgendamentoInvalidoError{Mensagem: fmt.Sprintf("agendamento exige antecedência mínima de %d minutos", cfg.AntecedenciaMinimaMin)}
+	}
+	if horario.After(agora.AddDate(0, 0, int(cfg.DiasMaximos))) {
+		return &AgendamentoInvalidoError{Mensagem: fmt.Sprintf("agendamento permitido até %d dias à frente", cfg.DiasMaximos)}
+	}
+
+	params := pgstore.GetFaixaAgendamentoParams{TenantID: tenantID, Horario: horario}
+	if idPedido != nil {
+		params.IDPedido = pgtype.UUID{Bytes: *idPedido, Valid: true}
+	}
+	faixa, err := as.queries.GetFaixaAgendamento(ctx, params)
+	if err != nil {
+		return err
+	}
+	if faixa.Capacidade.Valid && faixa.Ocupados >= faixa.Capacidade.Int32 {
+		loc, err := fusoDoTenant(ctx, as.queries, tenantID)
+		if err != nil {
+			return err
+		}
+		return &HorarioLotadoError{
+			Inicio:     faixa.Inicio.In(loc),
+			Fim:        faixa.Fim.In(loc),
+			Capacidade: faixa.Capacidade.Int32,
+			Ocupados:   faixa.Ocupados,
+		}
+	}
+	return nil
+}
+
+// Agenda do dia (no fuso do tenant; sem dia, hoje): faixas com ocupação e
+// os pedidos agendados não cancelados
+func (as *AgendamentoService) Agenda(ctx context.Context, tenantID uuid.UUID, dia *time.Time) (dto.AgendaResponse, error) {
+	loc, err := fusoDoTenant(ctx, as.queries, tenantID)
+	if err != nil {
+		return dto.AgendaResponse{}, err
+	}
+	cfg, err := as.config(ctx, as.queries, tenantID)
+	if err != nil {
+		return dto.AgendaResponse{}, err
+	}
+	intervalo := int32(dto.AgendamentoIntervaloPadrao)
+	if cfg != nil {
+		intervalo = cfg.IntervaloMinutos
+	}
+
+	ref := time.Now().In(loc)
+	if dia != nil {
+		ref = *dia
+	}
+	inicio := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, loc)
+	fim := inicio.AddDate(0, 0, 1)
+
+	horarios, err := as.queries.ListHorariosAgendamento(ctx, pgstore.ListHorariosAgendamentoParams{
+		TenantID:         tenantID,
+		Inicio:           inicio,
+		Fim:              fim,
+		IntervaloMinutos: intervalo,
+	})
+	if err != nil {
+		return dto.AgendaResponse{}, err
+	}
+	pedidos, err := as.queries.ListPedidosAgendados(ctx, pgstore.ListPedidosAgendadosParams{
+		TenantID: tenantID,
+		Inicio:   inicio,
+		Fim:      fim,
+	})
+	if err != nil {
+		return dto.AgendaResponse{}, err
+	}
+
+	out := dto.AgendaResponse{
+		Data:     inicio.Format("2006-01-02"),
+		Horarios: make([]dto.HorarioAgendamentoResponse, len(horarios)),
+		Pedidos:  make([]dto.PedidoAgendadoResponse, len(pedidos)),
+	}
+	for i, h := range horarios {
+		out.Horarios[i] = dto.HorarioAgendamentoToResponse(h)
+		out.Horarios[i].Inicio = h.Inicio.In(loc)
+		out.Horarios[i].Fim = h.Fim.In(loc)
+	}
+	for i, p := range pedidos {
+		out.Pedidos[i] = dto.PedidoAgendadoToResponse(p)
+	}
+	return out, nil
+}
+
+// Liberar manda o pedido agendado para a cozinha antes da hora
+func (as *AgendamentoService) Liberar(ctx context.Context, tenantID, idPedido, userID uuid.UUID) (dto.PedidoStatusHistoricoResponse, error) {
+	tx, err := as.pool.Begin(ctx)
+	if err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := as.queries.WithTx(tx)
+
+	pedido, err := q.GetPedidoStatusForUpdate(ctx, pgstore.GetPedidoStatusForUpdateParams{
+		ID:       idPedido,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PedidoStatusHistoricoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+	if pedido.IDStatus != dto.PedidoStatusAgendado {
+		return dto.PedidoStatusHistoricoResponse{}, ErrPedidoNaoAgendado
+	}
+
+	resp, err := registrarTransicaoStatus(ctx, q, dto.AlterarStatusPedidoDTO{
+		TenantID: tenantID,
+		UserID:   userID,
+		IDPedido: idPedido,
+		IDStatus: dto.PedidoStatusConfirmado,
+	}, pedido.IDStatus)
+	if err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+	return resp, nil
+}
+
+// LiberarVencidos libera um lote de agendados cuja hora de liberação
+// passou. Cada pedido roda em um savepoint: o que falhar fica para a
+// próxima passada sem derrubar os demais.
+func (as *AgendamentoService) LiberarVencidos(ctx context.Context) (int, error) {
+	tx, err := as.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	pedidos, err := as.queries.WithTx(tx).ListPedidosAgendadosParaLiberar(ctx, loteLiberacao)
+	if err != nil {
+		return 0, err
+	}
+
+	motivo := "liberado automaticamente para a cozinha"
+	liberados := 0
+	var falhas []error
+	for _, p := range pedidos {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return 0, err
+		}
+		// O evento de saída exige um usuário: a liberação fica em nome de quem
+		// agendou (a consulta deixa de fora os agendados sem autor)
+		_, err = registrarTransicaoStatus(ctx, as.queries.WithTx(sp), dto.AlterarStatusPedidoDTO{
+			TenantID: p.TenantID,
+			UserID:   uuid.UUID(p.AgendadoPor.Bytes),
+			IDPedido: p.ID,
+			IDStatus: dto.PedidoStatusConfirmado,
+			Motivo:   &motivo,
+		}, dto.PedidoStatusAgendado)
+		if err != nil {
+			sp.Rollback(ctx)
+			falhas = append(falhas, fmt.Errorf("pedido %s: %w", p.CodigoPedido, err))
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return 0, err
+		}
+		liberados++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return liberados, errors.Join(falhas...)
+}
+
+// RunLiberacao roda LiberarVencidos periodicamente até ctx ser cancelado
+func (as *AgendamentoService) RunLiberacao(ctx context.Context, intervalo time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := as.LiberarVencidos(ctx)
+			if err != nil {
+				logger.Error("erro ao liberar pedidos agendados", zap.Error(err))
+			}
+			if n > 0 {
+				logger.Info("pedidos agendados liberados para a cozinha", zap.Int("total", n))
+			}
+		}
+	}
+}
+
+// config devolve nil quando o tenant nunca configurou o agendamento
+func (as *AgendamentoService) config(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID) (*pgstore.AgendamentoConfig, error) {
+	cfg, err := q.GetAgendamentoConfig(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cfg, nil
+}
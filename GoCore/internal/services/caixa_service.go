@@ -2,13 +2,17 @@ package services
 
 import (
 	"context"
+	"errors"
 	"gobid/internal/dto"
 	"gobid/internal/store/pgstore"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var ErrCaixaNaoEncontrado = errors.New("caixa não encontrado ou já fechado")
+
 type CaixaService struct {
 	pool    *pgxpool.Pool
 	queries *pgstore.Queries
@@ -95,10 +99,55 @@ func (cs *CaixaService) InserirValoresInformados(ctx context.Context, valoresInf
 	return cs.queries.InserirValoresInformados(ctx, valoresInformadosParams)
 }
 
+// FecharCaixa fecha o caixa e grava o evento caixa.closed na mesma transação
 func (cs *CaixaService) FecharCaixa(ctx context.Context, fecharCaixa dto.FecharCaixaParams) error {
 	fecharCaixaParams, err := dto.FecharCaixaParamsToFecharCaixaParams(fecharCaixa)
 	if err != nil {
 		return err
 	}
-	return cs.queries.FecharCaixa(ctx, fecharCaixaParams)
+
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := cs.queries.WithTx(tx)
+
+	// valores esperados precisam ser lidos antes do fechamento
+	resumo, err := q.ResumoCaixaAberto(ctx, fecharCaixa.ID)
+	if err != nil {
+		return err
+	}
+	formas, err := dto.InterfaceToValorEsperadoFormaDto(resumo)
+	if err != nil {
+		return err
+	}
+
+	caixa, err := q.FecharCaixa(ctx, fecharCaixaParams)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCaixaNaoEncontrado
+		}
+		return err
+	}
+
+	ev, err := dto.NewOutboxEventDTO(caixa.TenantID, fecharCaixa.UserID,
+		dto.OutboxAggregateCaixa, caixa.ID.String(), dto.EventCaixaClosed,
+		dto.CaixaClosedPayload{
+			ID:                   caixa.ID,
+			IDOperador:           caixa.IDOperador,
+			DataAbertura:         caixa.DataAbertura,
+			DataFechamento:       caixa.DataFechamento.Time,
+			ObservacaoFechamento: caixa.ObservacaoFechamento.String,
+			Formas:               formas,
+		})
+	if err != nil {
+		return err
+	}
+	if _, err := q.CreateOutboxEvent(ctx, dto.CreateOutboxDTOToParams(&ev)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
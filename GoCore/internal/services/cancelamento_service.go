@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+var (
+	ErrPedidoJaCancelado  = errors.New("pedido já está cancelado")
+	ErrAutorizacaoGerente = errors.New("pedido com pagamento só pode ser cancelado com autorização de um gerente")
+	ErrSemCaixaAberto     = errors.New("pagamento de caixa fechado exige um caixa aberto para lançar o estorno")
+)
+
+// CancelamentoService cancela o pedido desfazendo o que foi recebido, tudo
+// em uma transação:
+//
+//   - cada pedido_pagamentos ativo é estornado (soft delete); a entrada (P)
+//     do caixa some pelo trigger se o caixa ainda estiver aberto, senão uma
+//     saída (E) é lançada no caixa aberto
+//   - as parcelas de contas_receber em aberto são canceladas
+//   - o pedido vai para Cancelado e o registro fica em pedido_cancelamentos
+//
+// Pedido com pagamento exige um gerente autorizando.
+type CancelamentoService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewCancelamentoService(pool *pgxpool.Pool) CancelamentoService {
+	return CancelamentoService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (cs *CancelamentoService) CancelarPedido(ctx context.Context, in dto.CancelarPedidoDTO) (dto.PedidoCancelamentoResponse, error) {
+	if in.Motivo == "" {
+		return dto.PedidoCancelamentoResponse{}, ErrMotivoObrigatorio
+	}
+
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := cs.queries.WithTx(tx)
+
+	pedido, err := q.GetPedidoCancelamentoForUpdate(ctx, pgstore.GetPedidoCancelamentoForUpdateParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PedidoCancelamentoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+	if pedido.IDStatus == dto.PedidoStatusCancelado {
+		return dto.PedidoCancelamentoResponse{}, ErrPedidoJaCancelado
+	}
+	if err := ValidarTransicaoStatus(pedido.IDStatus, dto.PedidoStatusCancelado); err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	pagamentos, err := q.ListPagamentosCancelamento(ctx, in.IDPedido)
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+	pago, _ := decimalutils.NumericToCentavos(pedido.ValorPago)
+	if (pago > 0 || len(pagamentos) > 0) && in.AutorizadoPor == nil {
+		return dto.PedidoCancelamentoResponse{}, ErrAutorizacaoGerente
+	}
+
+	autorizadoPor := pgtype.UUID{}
+	if in.AutorizadoPor != nil {
+		autorizadoPor = pgtype.UUID{Bytes: *in.AutorizadoPor, Valid: true}
+	}
+
+	var (
+		estornados   = make([]dto.PagamentoEstornadoResponse, 0, len(pagamentos))
+		totalEstorno int64
+		caixaAtivo   *uuid.UUID
+	)
+	for _, p := range pagamentos {
+		if err := q.SoftDeletePagamento(ctx, p.ID); err != nil {
+			return dto.PedidoCancelamentoResponse{}, err
+		}
+
+		valor, _ := decimalutils.NumericToCentavos(p.ValorPago)
+		troco, temTroco := decimalutils.NumericToCentavos(p.Troco)
+		liquido := valor - troco
+		totalEstorno += liquido
+
+		estorno := dto.PagamentoEstornadoResponse{
+			IDPagamento:    p.ID.String(),
+			IDContaReceber: pgUUIDToStringPtr(p.IDContaReceber),
+			FormaPagamento: p.FormaPagamento,
+			Valor:          decimalutils.FromCentavos(liquido),
+			Caixa:          dto.EstornoCaixaSemMovimento,
+			IDCaixa:        pgUUIDToStringPtr(p.IDCaixa),
+		}
+
+		switch {
+		case !p.IDMovimentacao.Valid:
+		case p.CaixaAberto:
+			// a entrada foi removida pelo trigger de estorno do caixa
+			estorno.Caixa = dto.EstornoCaixaRemovido
+		default:
+			if caixaAtivo == nil {
+				id, err := q.GetCaixaAtivo(ctx, in.TenantID)
+				if err != nil {
+					if errors.Is(err, pgx.ErrNoRows) {
+						return dto.PedidoCancelamentoResponse{}, ErrSemCaixaAberto
+					}
+					return dto.PedidoCancelamentoResponse{}, err
+				}
+				caixaAtivo = &id
+			}
+			mov, err := q.InsertMovimentacaoEstorno(ctx, pgstore.InsertMovimentacaoEstornoParams{
+				IDCaixa:          *caixaAtivo,
+				IDFormaPagamento: p.IDFormaPagamento,
+				Valor:            p.ValorMovimentacao,
+				Observacao: pgtype.Text{
+					String: fmt.Sprintf("Estorno - %s - pedido %s cancelado", p.FormaPagamento, pedido.CodigoPedido),
+					Valid:  true,
+				},
+				IDPagamento:   pgtype.UUID{Bytes: p.ID, Valid: true},
+				AutorizadoPor: autorizadoPor,
+			})
+			if err != nil {
+				return dto.PedidoCancelamentoResponse{}, err
+			}
+			movID, caixaID := mov.ID.String(), mov.IDCaixa.String()
+			estorno.Caixa = dto.EstornoCaixaLancado
+			estorno.IDCaixa = &caixaID
+			estorno.IDMovimentacaoEstorno = &movID
+		}
+
+		payload := dto.PagamentoEventPayload{
+			ID:                 p.ID.String(),
+			IDPedido:           in.IDPedido.String(),
+			IDContaReceber:     estorno.IDContaReceber,
+			CategoriaPagamento: pgTextToPtr(p.CategoriaPagamento),
+			FormaPagamento:     p.FormaPagamento,
+			ValorPago:          decimalutils.FromCentavos(valor),
+		}
+		if temTroco {
+			payload.Troco = types.NewNullDecimal(decimalutils.FromCentavos(troco).Big)
+		}
+		if err := criarEventoOutbox(ctx, q, in.TenantID, in.UserID,
+			dto.OutboxAggregatePagamento, p.ID.String(), dto.EventPagamentoDeleted, payload); err != nil {
+			return dto.PedidoCancelamentoResponse{}, err
+		}
+
+		estornados = append(estornados, estorno)
+	}
+
+	canceladas, err := q.CancelarContasReceberPedido(ctx, pgstore.CancelarContasReceberPedidoParams{
+		Motivo:   pgtype.Text{String: in.Motivo, Valid: true},
+		IDPedido: in.IDPedido,
+	})
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+	parcelas := make([]dto.ParcelaCanceladaResponse, len(canceladas))
+	for i, c := range canceladas {
+		devido, _ := decimalutils.NumericToCentavos(c.ValorDevido)
+		pagoParcela, _ := decimalutils.NumericToCentavos(c.ValorPago)
+		parcelas[i] = dto.ParcelaCanceladaResponse{
+			ID:          c.ID.String(),
+			Parcela:     c.Parcela,
+			Vencimento:  c.Vencimento.Time.Format("2006-01-02"),
+			ValorDevido: decimalutils.FromCentavos(devido),
+			ValorPago:   decimalutils.FromCentavos(pagoParcela),
+		}
+	}
+
+	motivo := in.Motivo
+	transicao, err := registrarTransicaoStatus(ctx, q, dto.AlterarStatusPedidoDTO{
+		TenantID: in.TenantID,
+		UserID:   in.UserID,
+		IDPedido: in.IDPedido,
+		IDStatus: dto.PedidoStatusCancelado,
+		Motivo:   &motivo,
+	}, pedido.IDStatus)
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	pagamentosJSON, err := json.Marshal(estornados)
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+	parcelasJSON, err := json.Marshal(parcelas)
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	registro, err := q.CreatePedidoCancelamento(ctx, pgstore.CreatePedidoCancelamentoParams{
+		TenantID:       in.TenantID,
+		IDPedido:       in.IDPedido,
+		StatusAnterior: pedido.IDStatus,
+		Motivo:         in.Motivo,
+		UserID:         pgtype.UUID{Bytes: in.UserID, Valid: in.UserID != uuid.Nil},
+		AutorizadoPor:  autorizadoPor,
+		ValorEstornado: decimalutils.CentavosToNumeric(totalEstorno),
+		Pagamentos:     pagamentosJSON,
+		Parcelas:       parcelasJSON,
+	})
+	if err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	cancelado := dto.PedidoCanceladoPayload{
+		ID:             in.IDPedido.String(),
+		CodigoPedido:   pedido.CodigoPedido,
+		StatusAnterior: pedido.IDStatus,
+		Motivo:         in.Motivo,
+		AutorizadoPor:  pgUUIDToStringPtr(autorizadoPor),
+		ValorEstornado: decimalutils.FromCentavos(totalEstorno),
+		QtdPagamentos:  len(estornados),
+		QtdParcelas:    len(parcelas),
+	}
+	if err := criarEventoOutbox(ctx, q, in.TenantID, in.UserID,
+		dto.OutboxAggregatePedido, in.IDPedido.String(), dto.EventPedidoCancelado, cancelado); err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.PedidoCancelamentoResponse{}, err
+	}
+
+	var userID *uuid.UUID
+	if in.UserID != uuid.Nil {
+		userID = &in.UserID
+	}
+	return dto.PedidoCancelamentoResponse{
+		ID:             registro.ID.String(),
+		IDPedido:       in.IDPedido.String(),
+		CodigoPedido:   pedido.CodigoPedido,
+		StatusAnterior: pedido.IDStatus,
+		Motivo:         registro.Motivo,
+		UserID:         userID,
+		AutorizadoPor:  in.AutorizadoPor,
+		ValorEstornado: decimalutils.FromCentavos(totalEstorno),
+		Pagamentos:     estornados,
+		Parcelas:       parcelas,
+		Transicao:      transicao,
+		CreatedAt:      registro.CreatedAt,
+	}, nil
+}
+
+func criarEventoOutbox(ctx context.Context, q *pgstore.Queries, tenantID, userID uuid.UUID,
+	aggregate, aggregateID, eventType string, payload any) error {
+
+	ev, err := dto.NewOutboxEventDTO(tenantID, userID, aggregate, aggregateID, eventType, payload)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateOutboxEvent(ctx, dto.CreateOutboxDTOToParams(&ev))
+	return err
+}
+
+func pgUUIDToStringPtr(v pgtype.UUID) *string {
+	if !v.Valid {
+		return nil
+	}
+	s := uuid.UUID(v.Bytes).String()
+	return &s
+}
+
+func pgTextToPtr(v pgtype.Text) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
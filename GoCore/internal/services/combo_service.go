@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrComboNaoEncontrado = errors.New("produto não é um combo")
+
+// ComboInvalidoError aponta slots com categoria, opção ou acréscimo que não
+// batem com o cardápio do tenant.
+type ComboInvalidoError struct {
+	Mensagem string
+}
+
+func (e *ComboInvalidoError) Error() string { return e.Mensagem }
+
+// ComboService mantém os slots dos produtos combo. O combo é um produto
+// comum com preço em produto_precos; os slots dizem o que o cliente escolhe
+// e quanto cada escolha acrescenta. A cotação do pedido (PrecificacaoService)
+// e os triggers de pedido_itens (migration 064) aplicam essas regras.
+type ComboService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewComboService(pool *pgxpool.Pool) ComboService {
+	return ComboService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (cs *ComboService) Get(ctx context.Context, tenantID, idProduto uuid.UUID) (dto.ComboResponse, error) {
+	produto, err := cs.queries.GetProdutoCombo(ctx, pgstore.GetProdutoComboParams{ID: idProduto, TenantID: tenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ComboResponse{}, ErrProdutoNaoEncontrado
+		}
+		return dto.ComboResponse{}, err
+	}
+
+	slots, err := cs.queries.ListComboSlots(ctx, idProduto)
+	if err != nil {
+		return dto.ComboResponse{}, err
+	}
+	if len(slots) == 0 {
+		return dto.ComboResponse{}, ErrComboNaoEncontrado
+	}
+	acrescimos, err := cs.queries.ListComboAcrescimos(ctx, idProduto)
+	if err != nil {
+		return dto.ComboResponse{}, err
+	}
+
+	porSlot := make(map[uuid.UUID][]dto.ComboSlotAcrescimoResponse, len(slots))
+	for _, a := range acrescimos {
+		porSlot[a.IDComboSlot] = append(porSlot[a.IDComboSlot], dto.ComboSlotAcrescimoResponse{
+			IDProduto:   a.IDProduto,
+			ProdutoNome: a.ProdutoNome,
+			Valor:       decimalutils.FromCentavos(centavos(a.Valor)),
+		})
+	}
+
+	out := dto.ComboResponse{
+		IDProduto:   produto.ID,
+		ProdutoNome: produto.Nome,
+		Slots:       make([]dto.ComboSlotResponse, len(slots)),
+	}
+	for i, s := range slots {
+		out.Slots[i] = dto.ComboSlotResponse{
+			ID:               s.ID,
+			Nome:             s.Nome,
+			IDCategoria:      s.IDCategoria,
+			CategoriaNome:    s.CategoriaNome,
+			IDCategoriaOpcao: uuidPtr(s.IDCategoriaOpcao),
+			OpcaoNome:        pgTextToPtr(s.OpcaoNome),
+			Quantidade:       s.Quantidade,
+			Ordem:            s.Ordem,
+			Acrescimos:       porSlot[s.ID],
+		}
+		if out.Slots[i].Acrescimos == nil {
+			out.Slots[i].Acrescimos = []dto.ComboSlotAcrescimoResponse{}
+		}
+	}
+	return out, nil
+}
+
+// Salvar substitui os slots do combo. Os slots anteriores são excluídos
+// logicamente para não quebrar os itens de pedidos já feitos.
+func (cs *ComboService) Salvar(ctx context.Context, in dto.ComboUpdateDTO) (dto.ComboResponse, error) {
+	produto, err := cs.queries.GetProdutoCombo(ctx, pgstore.GetProdutoComboParams{ID: in.IDProduto, TenantID: in.TenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ComboResponse{}, ErrProdutoNaoEncontrado
+		}
+		return dto.ComboResponse{}, err
+	}
+	if err := cs.validar(ctx, in, produto); err != nil {
+		return dto.ComboResponse{}, err
+	}
+
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return dto.ComboResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := cs.queries.WithTx(tx)
+
+	if _, err := q.DeleteComboSlots(ctx, in.IDProduto); err != nil {
+		return dto.ComboResponse{}, err
+	}
+	for i, s := range in.Slots {
+		quantidade := s.Quantidade
+		if quantidade == 0 {
+			quantidade = 1
+		}
+		idSlot, err := q.CreateComboSlot(ctx, pgstore.CreateComboSlotParams{
+			IDProduto:        in.IDProduto,
+			Nome:             s.Nome,
+			IDCategoria:      uuid.MustParse(s.IDCategoria),
+			IDCategoriaOpcao: stringToPgUUID(s.IDCategoriaOpcao),
+			Quantidade:       quantidade,
+			Ordem:            int32(i),
+		})
+		if err != nil {
+			return dto.ComboResponse{}, err
+		}
+		for _, a := range s.Acrescimos {
+			if err := q.CreateComboSlotAcrescimo(ctx, pgstore.CreateComboSlotAcrescimoParams{
+				IDComboSlot: idSlot,
+				IDProduto:   uuid.MustParse(a.IDProduto),
+				Valor:       decimalutils.CentavosToNumeric(decimalutils.ToCentavos(a.Valor)),
+			}); err != nil {
+				return dto.ComboResponse{}, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.ComboResponse{}, err
+	}
+	return cs.Get(ctx, in.TenantID, in.IDProduto)
+}
+
+// Remover exclui os slots; o produto volta a ser um produto comum.
+func (cs *ComboService) Remover(ctx context.Context, tenantID, idProduto uuid.UUID) error {
+	if _, err := cs.queries.GetProdutoCombo(ctx, pgstore.GetProdutoComboParams{ID: idProduto, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProdutoNaoEncontrado
+		}
+		return err
+	}
+
+	n, err := cs.queries.DeleteComboSlots(ctx, idProduto)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrComboNaoEncontrado
+	}
+	return nil
+}
+
+// validar confere, no cardápio do tenant, a categoria e a opção de cada
+// slot e se os produtos com acréscimo são da categoria do slot.
+func (cs *ComboService) validar(ctx context.Context, in dto.ComboUpdateDTO, produto pgstore.GetProdutoComboRow) error {
+	var categoriaIDs, produtoIDs []uuid.UUID
+	for _, s := range in.Slots {
+		categoriaIDs = append(categoriaIDs, uuid.MustParse(s.IDCategoria))
+		for _, a := range s.Acrescimos {
+			produtoIDs = append(produtoIDs, uuid.MustParse(a.IDProduto))
+		}
+	}
+
+	opcoesRows, err := cs.queries.ListCategoriasOpcoesCombo(ctx, pgstore.ListCategoriasOpcoesComboParams{
+		TenantID:     in.TenantID,
+		CategoriaIds: categoriaIDs,
+	})
+	if err != nil {
+		return err
+	}
+	opcoes := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, row := range opcoesRows {
+		if opcoes[row.IDCategoria] == nil {
+			opcoes[row.IDCategoria] = make(map[uuid.UUID]bool)
+		}
+		if row.IDCategoriaOpcao.Valid {
+			opcoes[row.IDCategoria][uuid.UUID(row.IDCategoriaOpcao.Bytes)] = true
+		}
+	}
+
+	categoriaProduto := make(map[uuid.UUID]uuid.UUID)
+	if len(produtoIDs) > 0 {
+		rows, err := cs.queries.ListProdutosCategoriaCombo(ctx, pgstore.ListProdutosCategoriaComboParams{
+			TenantID:   in.TenantID,
+			ProdutoIds: produtoIDs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			categoriaProduto[row.ID] = row.IDCategoria
+		}
+	}
+
+	for i, s := range in.Slots {
+		idCategoria := uuid.MustParse(s.IDCategoria)
+		opcoesCategoria, ok := opcoes[idCategoria]
+		switch {
+		case !ok:
+			return &ComboInvalidoError{fmt.Sprintf("slot %d: categoria %s não encontrada", i+1, s.IDCategoria)}
+		case idCategoria == produto.IDCategoria:
+			return &ComboInvalidoError{fmt.Sprintf("slot %d: a categoria do slot não pode ser a do próprio combo", i+1)}
+		case s.IDCategoriaOpcao != nil && !opcoesCategoria[uuid.MustParse(*s.IDCategoriaOpcao)]:
+			return &ComboInvalidoError{fmt.Sprintf("slot %d: opção %s não pertence à categoria do slot", i+1, *s.IDCategoriaOpcao)}
+		}
+
+		vistos := make(map[string]bool, len(s.Acrescimos))
+		for _, a := range s.Acrescimos {
+			if vistos[a.IDProduto] {
+				return &ComboInvalidoError{fmt.Sprintf("slot %d: produto %s com acréscimo repetido", i+1, a.IDProduto)}
+			}
+			vistos[a.IDProduto] = true
+			if cat, ok := categoriaProduto[uuid.MustParse(a.IDProduto)]; !ok || cat != idCategoria {
+				return &ComboInvalidoError{fmt.Sprintf("slot %d: produto %s não pertence à categoria do slot", i+1, a.IDProduto)}
+			}
+		}
+	}
+	return nil
+}
+
+func uuidPtr(u pgtype.UUID) *uuid.UUID {
+	if !u.Valid {
+		return nil
+	}
+	id := uuid.UUID(u.Bytes)
+	return &id
+}
+
+func stringToPgUUID(s *string) pgtype.UUID {
+	if s == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: uuid.MustParse(*s), Valid: true}
+}
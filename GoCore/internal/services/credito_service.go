@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrClienteNaoEncontrado      = errors.New("cliente não encontrado")
+	ErrContaReceberNaoEncontrada = errors.New("conta a receber não encontrada")
+)
+
+// CreditoInvalidoError aponta limite ou política fora das regras
+type CreditoInvalidoError struct {
+	Mensagem string
+}
+
+func (e *CreditoInvalidoError) Error() string { return e.Mensagem }
+
+// CreditoService cuida do fiado: limite por cliente, política de atraso do
+// tenant, extrato das parcelas em aberto e aging. A mesma política é
+// conferida pelo gatilho enforce_credito_cliente ao gravar a parcela; aqui
+// ela é avaliada antes para devolver o motivo detalhado e permitir a
+// autorização do gerente. Parcela vencida acumula multa e juros conforme a
+// política do tenant; o saldo atualizado sai de encargos_conta_receber.
+type CreditoService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewCreditoService(pool *pgxpool.Pool) CreditoService {
+	return CreditoService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (cs *CreditoService) GetConfig(ctx context.Context, tenantID uuid.UUID) (dto.CreditoConfigResponse, error) {
+	cfg, err := cs.queries.GetCreditoConfig(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CreditoConfigToResponse(nil), nil
+		}
+		return dto.CreditoConfigResponse{}, err
+	}
+	return dto.CreditoConfigToResponse(&cfg), nil
+}
+
+func (cs *CreditoService) SalvarConfig(ctx context.Context, in dto.CreditoConfigDTO) (dto.CreditoConfigResponse, error) {
+	if in.LimitePadrao != nil && decimalutils.ToCentavos(*in.LimitePadrao) < 0 {
+		return dto.CreditoConfigResponse{}, &CreditoInvalidoError{Mensagem: "limite_padrao não pode ser negativo"}
+	}
+	if in.MultaPercentual != nil {
+		if c := decimalutils.ToCentavos(*in.MultaPercentual); c < 0 || c > 100_00 {
+			return dto.CreditoConfigResponse{}, &CreditoInvalidoError{Mensagem: "multa_percentual deve estar entre 0 e 100"}
+		}
+	}
+	if in.JurosDiaPercentual != nil {
+		if c := decimalutils.ToCentavos(*in.JurosDiaPercentual); c < 0 || c > 10_00 {
+			return dto.CreditoConfigResponse{}, &CreditoInvalidoError{Mensagem: "juros_dia_percentual deve estar entre 0 e 10"}
+		}
+	}
+	cfg, err := cs.queries.UpsertCreditoConfig(ctx, in.ToUpsertParams())
+	if err != nil {
+		return dto.CreditoConfigResponse{}, err
+	}
+	return dto.CreditoConfigToResponse(&cfg), nil
+}
+
+// SalvarCliente grava o limite do cliente e devolve o extrato atualizado
+func (cs *CreditoService) SalvarCliente(ctx context.Context, tenantID, idCliente, userID uuid.UUID, in dto.ClienteCreditoDTO) (dto.ClienteCreditoResponse, error) {
+	limite := pgtype.Numeric{}
+	if in.Limite != nil {
+		centavos := decimalutils.ToCentavos(*in.Limite)
+		if centavos < 0 {
+			return dto.ClienteCreditoResponse{}, &CreditoInvalidoError{Mensagem: "limite não pode ser negativo"}
+		}
+		limite = decimalutils.CentavosToNumeric(centavos)
+	}
+
+	hoje, err := cs.hoje(ctx, tenantID)
+	if err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+	// Confere que o cliente é do tenant antes de gravar
+	if _, err := cs.situacao(ctx, cs.queries, tenantID, idCliente, hoje); err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+
+	if _, err := cs.queries.UpsertClienteCredito(ctx, pgstore.UpsertClienteCreditoParams{
+		IDCliente:  idCliente,
+		TenantID:   tenantID,
+		Limite:     limite,
+		Bloqueado:  in.Bloqueado,
+		Observacao: toPgTypeText(in.Observacao),
+		UpdatedBy:  pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil},
+	}); err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+	return cs.Extrato(ctx, tenantID, idCliente, &hoje)
+}
+
+// Saldo da parcela na data (padrão: hoje no fuso do tenant): principal em
+// aberto mais multa e juros pendentes, calculados por encargos_conta_receber
+// com os pagamentos feitos até a data
+func (cs *CreditoService) Saldo(ctx context.Context, tenantID, idConta uuid.UUID, data *time.Time) (dto.ContaReceberSaldoResponse, error) {
+	dia, err := cs.dia(ctx, tenantID, data)
+	if err != nil {
+		return dto.ContaReceberSaldoResponse{}, err
+	}
+	row, err := cs.queries.GetSaldoContaReceber(ctx, pgstore.GetSaldoContaReceberParams{
+		ID:       idConta,
+		TenantID: tenantID,
+		Data:     pgtype.Date{Time: dia, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ContaReceberSaldoResponse{}, ErrContaReceberNaoEncontrada
+		}
+		return dto.ContaReceberSaldoResponse{}, err
+	}
+	return dto.ContaReceberSaldoToResponse(row, dia), nil
+}
+
+// Extrato do fiado do cliente na data (padrão: hoje no fuso do tenant):
+// limite, saldo e parcelas em aberto com os pagamentos já abatidos
+func (cs *CreditoService) Extrato(ctx context.Context, tenantID, idCliente uuid.UUID, data *time.Time) (dto.ClienteCreditoResponse, error) {
+	dia, err := cs.dia(ctx, tenantID, data)
+	if err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+	sit, err := cs.situacao(ctx, cs.queries, tenantID, idCliente, dia)
+	if err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+
+	parcelas, err := cs.queries.ListParcelasAbertasCliente(ctx, pgstore.ListParcelasAbertasClienteParams{
+		IDCliente: idCliente,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+	pagamentos, err := cs.queries.ListPagamentosParcelasCliente(ctx, pgstore.ListPagamentosParcelasClienteParams{
+		IDCliente: idCliente,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return dto.ClienteCreditoResponse{}, err
+	}
+
+	saldo := centavos(sit.SaldoAberto)
+	out := dto.ClienteCreditoResponse{
+		IDCliente:       sit.ID,
+		Nome:            sit.NomeRazaoSocial,
+		LimiteProprio:   sit.LimiteCliente.Valid,
+		Bloqueado:       sit.Bloqueado,
+		Observacao:      pgTextToPtr(sit.Observacao),
+		SaldoAberto:     decimalutils.FromCentavos(saldo),
+		SaldoVencido:    decimalutils.FromCentavos(centavos(sit.SaldoVencido)),
+		MaiorAtrasoDias: sit.MaiorAtrasoDias,
+		Liberado:        true,
+		Data:            dia.Format("2006-01-02"),
+		Parcelas:        make([]dto.ParcelaCreditoResponse, len(parcelas)),
+	}
+	if sit.Limite.Valid {
+		limite := centavos(sit.Limite)
+		disponivel := max(limite-saldo, 0)
+		l, d := decimalutils.FromCentavos(limite), decimalutils.FromCentavos(disponivel)
+		out.Limite, out.Disponivel = &l, &d
+	}
+	if motivo, _ := avaliarCredito(sit, 0); motivo != "" {
+		out.Liberado, out.Motivo = false, motivo
+	}
+
+	indice := make(map[uuid.UUID]int, len(parcelas))
+	for i, p := range parcelas {
+		out.Parcelas[i] = dto.ParcelaCreditoToResponse(p, dia)
+		indice[p.ID] = i
+	}
+	for _, pg := range pagamentos {
+		if i, ok := indice[uuid.UUID(pg.IDContaReceber.Bytes)]; ok {
+			out.Parcelas[i].Pagamentos = append(out.Parcelas[i].Pagamentos, dto.PagamentoParcelaToResponse(pg))
+		}
+	}
+	return out, nil
+}
+
+// Aging das contas a receber na data: saldo em aberto por cliente nas
+// faixas a vencer, 0–30, 31–60, 61–90 e mais de 90 dias após o vencimento
+func (cs *CreditoService) Aging(ctx context.Context, tenantID uuid.UUID, data *time.Time) (dto.AgingResponse, error) {
+	dia, err := cs.dia(ctx, tenantID, data)
+	if err != nil {
+		return dto.AgingResponse{}, err
+	}
+	rows, err := cs.queries.ListAgingContasReceber(ctx, pgstore.ListAgingContasReceberParams{
+		Data:     pgtype.Date{Time: dia, Valid: true},
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return dto.AgingResponse{}, err
+	}
+
+	var aVencer, d30, d60, d90, d90Mais, total int64
+	out := dto.AgingResponse{
+		Data:     dia.Format("2006-01-02"),
+		Clientes: make([]dto.AgingClienteResponse, len(rows)),
+	}
+	for i, r := range rows {
+		out.Clientes[i] = dto.AgingClienteToResponse(r)
+		aVencer += centavos(r.AVencer)
+		d30 += centavos(r.Dias130)
+		d60 += centavos(r.Dias3160)
+		d90 += centavos(r.Dias6190)
+		d90Mais += centavos(r.Dias90Mais)
+		total += centavos(r.Total)
+	}
+	out.Totais = dto.AgingFaixasResponse{
+		AVencer:    decimalutils.FromCentavos(aVencer),
+		Dias1a30:   decimalutils.FromCentavos(d30),
+		Dias31a60:  decimalutils.FromCentavos(d60),
+		Dias61a90:  decimalutils.FromCentavos(d90),
+		Dias90Mais: decimalutils.FromCentavos(d90Mais),
+		Total:      decimalutils.FromCentavos(total),
+	}
+	return out, nil
+}
+
+// VerificarParcelas confere as parcelas novas contra a política de cada
+// cliente (somadas por cliente) e devolve os clientes recusados. Lista
+// vazia: todas podem ser gravadas sem gerente.
+func (cs *CreditoService) VerificarParcelas(ctx context.Context, tenantID uuid.UUID, parcelas []dto.ParcelaCreditoDTO) ([]dto.CreditoNegadoResponse, error) {
+	ids := make([]uuid.UUID, 0, len(parcelas))
+	visto := make(map[uuid.UUID]bool, len(parcelas))
+	for _, p := range parcelas {
+		if !visto[p.IDPedido] {
+			visto[p.IDPedido] = true
+			ids = append(ids, p.IDPedido)
+		}
+	}
+	pedidos, err := cs.queries.ListClientesDosPedidos(ctx, pgstore.ListClientesDosPedidosParams{
+		TenantID: tenantID,
+		Ids:      ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pedidos) != len(ids) {
+		return nil, ErrPedidoNaoEncontrado
+	}
+	clienteDoPedido := make(map[uuid.UUID]uuid.UUID, len(pedidos))
+	for _, p := range pedidos {
+		clienteDoPedido[p.ID] = p.IDCliente
+	}
+
+	// Soma por cliente, na ordem em que aparecem
+	var clientes []uuid.UUID
+	novo := make(map[uuid.UUID]int64)
+	pedidosDoCliente := make(map[uuid.UUID][]uuid.UUID)
+	for _, p := range parcelas {
+		c := clienteDoPedido[p.IDPedido]
+		if _, ok := novo[c]; !ok {
+			clientes = append(clientes, c)
+		}
+		novo[c] += decimalutils.ToCentavos(p.Valor)
+	}
+	for _, id := range ids {
+		c := clienteDoPedido[id]
+		pedidosDoCliente[c] = append(pedidosDoCliente[c], id)
+	}
+
+	hoje, err := cs.hoje(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	var negados []dto.CreditoNegadoResponse
+	for _, c := range clientes {
+		sit, err := cs.situacao(ctx, cs.queries, tenantID, c, hoje)
+		if err != nil {
+			return nil, err
+		}
+		motivo, mensagem := avaliarCredito(sit, novo[c])
+		if motivo == "" {
+			continue
+		}
+		n := dto.CreditoNegadoResponse{
+			IDCliente:       c,
+			Nome:            sit.NomeRazaoSocial,
+			Motivo:          motivo,
+			Mensagem:        mensagem,
+			SaldoAberto:     decimalutils.FromCentavos(centavos(sit.SaldoAberto)),
+			ValorNovo:       decimalutils.FromCentavos(novo[c]),
+			MaiorAtrasoDias: sit.MaiorAtrasoDias,
+			IDPedidos:       pedidosDoCliente[c],
+		}
+		if sit.Limite.Valid {
+			l := decimalutils.FromCentavos(centavos(sit.Limite))
+			n.Limite = &l
+		}
+		negados = append(negados, n)
+	}
+	return negados, nil
+}
+
+func (cs *CreditoService) situacao(ctx context.Context, q *pgstore.Queries, tenantID, idCliente uuid.UUID, dia time.Time) (pgstore.GetSituacaoCreditoClienteRow, error) {
+	sit, err := q.GetSituacaoCreditoCliente(ctx, pgstore.GetSituacaoCreditoClienteParams{
+		ID:       idCliente,
+		TenantID: tenantID,
+		Data:     pgtype.Date{Time: dia, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sit, ErrClienteNaoEncontrado
+		}
+		return sit, err
+	}
+	return sit, nil
+}
+
+// hoje no fuso do tenant, como data (meia-noite UTC, igual ao date do banco)
+func (cs *CreditoService) hoje(ctx context.Context, tenantID uuid.UUID) (time.Time, error) {
+	return cs.dia(ctx, tenantID, nil)
+}
+
+func (cs *CreditoService) dia(ctx context.Context, tenantID uuid.UUID, data *time.Time) (time.Time, error) {
+	ref := time.Time{}
+	if data != nil {
+		ref = *data
+	} else {
+		loc, err := fusoDoTenant(ctx, cs.queries, tenantID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		ref = time.Now().In(loc)
+	}
+	return time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// avaliarCredito aplica a política na mesma ordem do gatilho
+// enforce_credito_cliente: bloqueio, atraso e limite. Motivo vazio = liberado.
+func avaliarCredito(sit pgstore.GetSituacaoCreditoClienteRow, novo int64) (string, string) {
+	if sit.Bloqueado {
+		return dto.CreditoMotivoBloqueado, "crédito do cliente bloqueado"
+	}
+	if sit.BloquearAtraso && sit.MaiorAtrasoDias > sit.DiasTolerancia {
+		return dto.CreditoMotivoAtraso, fmt.Sprintf("cliente com parcela vencida há %d dia(s)", sit.MaiorAtrasoDias)
+	}
+	if sit.Limite.Valid {
+		limite, saldo := centavos(sit.Limite), centavos(sit.SaldoAberto)
+		if saldo+novo > limite {
+			return dto.CreditoMotivoLimite, fmt.Sprintf("limite de crédito excedido: saldo %s + novo %s > limite %s",
+				decimalutils.FromCentavos(saldo), decimalutils.FromCentavos(novo), decimalutils.FromCentavos(limite))
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Regras do cupom na cotação (cupons.tipo):
+//
+//	P → percentual sobre os itens elegíveis, limitado a desconto_maximo
+//	V → valor fixo, limitado aos itens elegíveis
+//	F → frete grátis: o desconto é a taxa de entrega
+//
+// Itens elegíveis são os das categorias/produtos do cupom ou, sem
+// restrição, todos. O valor mínimo vale para a soma de todos os itens.
+// Aqui o cupom só é conferido; o resgate que trava os limites é gravado
+// junto com o pedido.
+
+type cupomCotacao struct {
+	codigo     string
+	encontrado bool
+	row        pgstore.GetCupomCotacaoRow
+	categorias map[uuid.UUID]bool
+	produtos   map[uuid.UUID]bool
+	// resgates ativos do cliente; -1 quando não conferido
+	usosCliente int64
+}
+
+func (ps *PrecificacaoService) carregarCupom(ctx context.Context, in dto.PedidoCotacaoDTO) (*cupomCotacao, error) {
+	if in.Cupom == nil || strings.TrimSpace(*in.Cupom) == "" {
+		return nil, nil
+	}
+	cupom := &cupomCotacao{codigo: strings.TrimSpace(*in.Cupom), usosCliente: -1}
+
+	idPedido := pgtype.UUID{Bytes: in.IDPedido, Valid: in.IDPedido != uuid.Nil}
+	row, err := ps.queries.GetCupomCotacao(ctx, pgstore.GetCupomCotacaoParams{
+		IDPedido: idPedido,
+		TenantID: in.TenantID,
+		Codigo:   cupom.codigo,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return cupom, nil
+		}
+		return nil, err
+	}
+	cupom.encontrado = true
+	cupom.row = row
+
+	restricoes, err := ps.queries.ListCupomRestricoes(ctx, []uuid.UUID{row.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range restricoes {
+		if r.Tipo == "C" {
+			if cupom.categorias == nil {
+				cupom.categorias = make(map[uuid.UUID]bool)
+			}
+			cupom.categorias[r.ID] = true
+		} else {
+			if cupom.produtos == nil {
+				cupom.produtos = make(map[uuid.UUID]bool)
+			}
+			cupom.produtos[r.ID] = true
+		}
+	}
+
+	if row.LimitePorCliente.Valid && in.IDCliente != nil {
+		cupom.usosCliente, err = ps.queries.CountResgatesCliente(ctx, pgstore.CountResgatesClienteParams{
+			IDCupom:   row.ID,
+			IDCliente: uuid.MustParse(*in.IDCliente),
+			IDPedido:  idPedido,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cupom, nil
+}
+
+// elegivel diz se o item entra na base do desconto
+func (cc *cupomCotacao) elegivel(item *dto.CotacaoItemDTO) bool {
+	if cc.categorias == nil && cc.produtos == nil {
+		return true
+	}
+	if cc.categorias[uuid.MustParse(item.IDCategoria)] || cc.produtos[uuid.MustParse(item.IDProduto)] {
+		return true
+	}
+	if item.IDProduto2 != nil && cc.produtos[uuid.MustParse(*item.IDProduto2)] {
+		return true
+	}
+	for _, s := range item.Sabores {
+		if cc.produtos[uuid.MustParse(s.IDProduto)] {
+			return true
+		}
+	}
+	return false
+}
+
+// aplicarCupom confere o cupom e calcula o desconto. totais são os valores
+// de cada item (na ordem de in.Itens), subtotal a soma deles. Devolve nil
+// quando o cupom não pode ser usado; o motivo fica em c.problemas.
+func aplicarCupom(c *cotacao, cupom *cupomCotacao, in dto.PedidoCotacaoDTO, totais []int64, subtotal, taxa int64) *dto.CotacaoCupomResponse {
+	row := cupom.row
+	switch {
+	case !cupom.encontrado || !row.Ativo:
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomInvalido, "cupom %s inválido", cupom.codigo)
+		return nil
+	case !row.Vigente:
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomExpirado, "cupom %s fora do período de validade", row.Codigo)
+		return nil
+	case row.LimiteTotal.Valid && row.Usos >= row.LimiteTotal.Int32:
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomEsgotado, "cupom %s esgotado", row.Codigo)
+		return nil
+	case cupom.usosCliente >= 0 && cupom.usosCliente >= int64(row.LimitePorCliente.Int32):
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomLimiteCliente, "cliente já usou o cupom %s %d vez(es)", row.Codigo, row.LimitePorCliente.Int32)
+		return nil
+	}
+
+	if minimo := centavos(row.ValorMinimoPedido); subtotal < minimo {
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomValorMinimo, "cupom %s exige pedido mínimo de R$ %s", row.Codigo, decimalutils.FromCentavos(minimo).String())
+		return nil
+	}
+
+	var base int64
+	for i := range in.Itens {
+		if cupom.elegivel(&in.Itens[i]) {
+			base += totais[i]
+		}
+	}
+
+	var desconto int64
+	switch row.Tipo {
+	case dto.CupomPercentual:
+		// valor em centavos é o percentual × 100; meio centavo para cima
+		desconto = (base*centavos(row.Valor) + 5000) / 10000
+		if row.DescontoMaximo.Valid {
+			desconto = min(desconto, centavos(row.DescontoMaximo))
+		}
+	case dto.CupomValorFixo:
+		desconto = min(centavos(row.Valor), base)
+	case dto.CupomFreteGratis:
+		base, desconto = taxa, taxa
+	}
+	if desconto <= 0 {
+		c.problema(nil, nil, "cupom", dto.ProblemaCupomSemDesconto, "cupom %s não se aplica a este pedido", row.Codigo)
+		return nil
+	}
+
+	return &dto.CotacaoCupomResponse{
+		ID:        row.ID,
+		Codigo:    row.Codigo,
+		Descricao: pgTextToPtr(row.Descricao),
+		Tipo:      row.Tipo,
+		Base:      decimalutils.FromCentavos(base),
+		Desconto:  decimalutils.FromCentavos(desconto),
+	}
+}
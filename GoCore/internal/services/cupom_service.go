@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCupomNaoEncontrado   = errors.New("cupom não encontrado")
+	ErrCupomCodigoDuplicado = errors.New("já existe um cupom com este código")
+)
+
+// CupomInvalidoError aponta valores do cupom incoerentes com o tipo ou
+// restrições fora do cardápio do tenant.
+type CupomInvalidoError struct {
+	Mensagem string
+}
+
+func (e *CupomInvalidoError) Error() string { return e.Mensagem }
+
+// CupomService mantém os cupons de desconto do tenant. O cupom é conferido
+// e o desconto calculado na cotação do pedido (PrecificacaoService); o
+// resgate é gravado junto com o pedido e devolvido pelos triggers da
+// migration 065 quando o pedido é cancelado ou excluído.
+type CupomService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewCupomService(pool *pgxpool.Pool) CupomService {
+	return CupomService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (cs *CupomService) List(ctx context.Context, tenantID uuid.UUID) ([]dto.CupomResponse, error) {
+	cupons, err := cs.queries.ListCupons(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cupons) == 0 {
+		return []dto.CupomResponse{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(cupons))
+	for i, c := range cupons {
+		ids[i] = c.ID
+	}
+	restricoes, err := cs.queries.ListCupomRestricoes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.CupomResponse, len(cupons))
+	for i, c := range cupons {
+		out[i] = dto.CupomToResponse(c, restricoes)
+	}
+	return out, nil
+}
+
+func (cs *CupomService) Get(ctx context.Context, tenantID, id uuid.UUID) (dto.CupomResponse, error) {
+	cupom, err := cs.queries.GetCupom(ctx, pgstore.GetCupomParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CupomResponse{}, ErrCupomNaoEncontrado
+		}
+		return dto.CupomResponse{}, err
+	}
+	return cs.response(ctx, cupom)
+}
+
+func (cs *CupomService) Create(ctx context.Context, in dto.CupomDTO) (dto.CupomResponse, error) {
+	categorias, produtos, err := cs.validar(ctx, in)
+	if err != nil {
+		return dto.CupomResponse{}, err
+	}
+
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return dto.CupomResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := cs.queries.WithTx(tx)
+
+	cupom, err := q.CreateCupom(ctx, in.ToCreateParams())
+	if err != nil {
+		return dto.CupomResponse{}, cupomPgError(err)
+	}
+	if err := salvarRestricoes(ctx, q, cupom.ID, categorias, produtos); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	return cs.response(ctx, cupom)
+}
+
+// Update troca os dados e as restrições do cupom. Resgates já feitos não
+// são recalculados.
+func (cs *CupomService) Update(ctx context.Context, in dto.CupomDTO) (dto.CupomResponse, error) {
+	categorias, produtos, err := cs.validar(ctx, in)
+	if err != nil {
+		return dto.CupomResponse{}, err
+	}
+
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return dto.CupomResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := cs.queries.WithTx(tx)
+
+	cupom, err := q.UpdateCupom(ctx, in.ToUpdateParams())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CupomResponse{}, ErrCupomNaoEncontrado
+		}
+		return dto.CupomResponse{}, cupomPgError(err)
+	}
+	if err := q.DeleteCupomCategorias(ctx, cupom.ID); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	if err := q.DeleteCupomProdutos(ctx, cupom.ID); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	if err := salvarRestricoes(ctx, q, cupom.ID, categorias, produtos); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dto.CupomResponse{}, err
+	}
+	return cs.response(ctx, cupom)
+}
+
+func (cs *CupomService) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	n, err := cs.queries.DeleteCupom(ctx, pgstore.DeleteCupomParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCupomNaoEncontrado
+	}
+	return nil
+}
+
+func (cs *CupomService) ListResgates(ctx context.Context, tenantID, id uuid.UUID, limit, offset int32) ([]dto.CupomResgateResponse, error) {
+	if _, err := cs.queries.GetCupom(ctx, pgstore.GetCupomParams{ID: id, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCupomNaoEncontrado
+		}
+		return nil, err
+	}
+
+	rows, err := cs.queries.ListCupomResgates(ctx, pgstore.ListCupomResgatesParams{
+		IDCupom:  id,
+		TenantID: tenantID,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.CupomResgatesToResponses(rows), nil
+}
+
+func (cs *CupomService) response(ctx context.Context, cupom pgstore.Cupon) (dto.CupomResponse, error) {
+	restricoes, err := cs.queries.ListCupomRestricoes(ctx, []uuid.UUID{cupom.ID})
+	if err != nil {
+		return dto.CupomResponse{}, err
+	}
+	return dto.CupomToResponse(cupom, restricoes), nil
+}
+
+// validar confere os valores pelo tipo e se as categorias e produtos são do
+// tenant. Devolve as restrições sem repetição.
+func (cs *CupomService) validar(ctx context.Context, in dto.CupomDTO) ([]uuid.UUID, []uuid.UUID, error) {
+	valor := decimalutils.ToCentavos(in.Valor)
+	switch {
+	case valor < 0:
+		return nil, nil, &CupomInvalidoError{Mensagem: "valor não pode ser negativo"}
+	case in.Tipo == dto.CupomPercentual && (valor == 0 || valor > 10000):
+		return nil, nil, &CupomInvalidoError{Mensagem: "percentual deve ser maior que 0 e no máximo 100"}
+	case in.Tipo == dto.CupomValorFixo && valor == 0:
+		return nil, nil, &CupomInvalidoError{Mensagem: "valor do cupom deve ser maior que zero"}
+	case in.DescontoMaximo != nil && in.Tipo != dto.CupomPercentual:
+		return nil, nil, &CupomInvalidoError{Mensagem: "desconto_maximo só vale para cupom percentual"}
+	case in.DescontoMaximo != nil && decimalutils.ToCentavos(*in.DescontoMaximo) <= 0:
+		return nil, nil, &CupomInvalidoError{Mensagem: "desconto_maximo deve ser maior que zero"}
+	case in.ValorMinimoPedido != nil && decimalutils.ToCentavos(*in.ValorMinimoPedido) < 0:
+		return nil, nil, &CupomInvalidoError{Mensagem: "valor_minimo_pedido não pode ser negativo"}
+	case in.Fim != nil && in.Inicio != nil && !in.Fim.After(*in.Inicio):
+		return nil, nil, &CupomInvalidoError{Mensagem: "fim deve ser depois do início"}
+	case in.Tipo == dto.CupomFreteGratis && (len(in.Categorias) > 0 || len(in.Produtos) > 0):
+		return nil, nil, &CupomInvalidoError{Mensagem: "cupom de frete grátis não tem restrição de categoria ou produto"}
+	}
+
+	categorias := uuidsDistintos(in.Categorias)
+	if len(categorias) > 0 {
+		n, err := cs.queries.CountCategoriasTenant(ctx, pgstore.CountCategoriasTenantParams{
+			TenantID:     in.TenantID,
+			CategoriaIds: categorias,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if n != int64(len(categorias)) {
+			return nil, nil, &CupomInvalidoError{Mensagem: "categoria não encontrada"}
+		}
+	}
+
+	produtos := uuidsDistintos(in.Produtos)
+	if len(produtos) > 0 {
+		n, err := cs.queries.CountProdutosTenant(ctx, pgstore.CountProdutosTenantParams{
+			TenantID:   in.TenantID,
+			ProdutoIds: produtos,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if n != int64(len(produtos)) {
+			return nil, nil, &CupomInvalidoError{Mensagem: "produto não encontrado"}
+		}
+	}
+	return categorias, produtos, nil
+}
+
+func salvarRestricoes(ctx context.Context, q *pgstore.Queries, idCupom uuid.UUID, categorias, produtos []uuid.UUID) error {
+	if len(categorias) > 0 {
+		if err := q.InsertCupomCategorias(ctx, pgstore.InsertCupomCategoriasParams{IDCupom: idCupom, CategoriaIds: categorias}); err != nil {
+			return err
+		}
+	}
+	if len(produtos) > 0 {
+		if err := q.InsertCupomProdutos(ctx, pgstore.InsertCupomProdutosParams{IDCupom: idCupom, ProdutoIds: produtos}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uuidsDistintos(ids []string) []uuid.UUID {
+	vistos := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, s := range ids {
+		id := uuid.MustParse(s)
+		if !vistos[id] {
+			vistos[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// cupomPgError traduz o índice único de código e as constraints da
+// migration 065.
+func cupomPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505":
+			return ErrCupomCodigoDuplicado
+		case pgErr.ConstraintName == "chk_cupons_usos":
+			return &CupomInvalidoError{Mensagem: "limite_total menor que os usos do cupom"}
+		case pgErr.Code == "23514":
+			return &CupomInvalidoError{Mensagem: "valores do cupom inválidos"}
+		}
+	}
+	return err
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // imagens mínimas não trazem /usr/share/zoneinfo
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const timezonePadrao = "America/Sao_Paulo"
+
+var ErrTenantNaoEncontrado = errors.New("tenant não encontrado")
+
+// DisponibilidadeService decide o que pode ser pedido em um dado momento,
+// no horário local do tenant (tenants.timezone):
+//
+//   - categoria: ativo = 1, dentro da janela inicio/fim e com o
+//     disponivel_<dia> do dia marcado
+//   - produto: categoria disponível e status = 1
+//   - opção de preço: produto disponível, categoria_opcoes.status = 1 e
+//     produto_precos.disponivel = 1
+//
+// A janela inicio = fim vale o dia todo. Quando inicio > fim a janela vira a
+// meia-noite (ex.: 18:00–02:00) e o trecho da madrugada pertence ao dia
+// anterior, ou seja, sábado 01:00 usa disponivel_sexta.
+type DisponibilidadeService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewDisponibilidadeService(pool *pgxpool.Pool) DisponibilidadeService {
+	return DisponibilidadeService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// HorarioLocal devolve ref (ou agora, se nil) no fuso do tenant
+func (ds *DisponibilidadeService) HorarioLocal(ctx context.Context, tenantID uuid.UUID, ref *time.Time) (time.Time, error) {
+	loc, err := fusoDoTenant(ctx, ds.queries, tenantID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t := time.Now()
+	if ref != nil {
+		t = *ref
+	}
+	return t.In(loc), nil
+}
+
+// fusoDoTenant carrega tenants.timezone; fuso inválido cai no padrão
+func fusoDoTenant(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID) (*time.Location, error) {
+	tz, err := q.GetTenantTimezone(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTenantNaoEncontrado
+		}
+		return nil, err
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc, _ = time.LoadLocation(timezonePadrao)
+	}
+	return loc, nil
+}
+
+// Cardapio monta o cardápio do tenant com a disponibilidade de cada nível.
+// Sem incluirIndisponiveis, só o que pode ser pedido no momento é devolvido.
+func (ds *DisponibilidadeService) Cardapio(ctx context.Context, tenantID uuid.UUID, ref *time.Time, incluirIndisponiveis bool) (dto.CardapioDisponivelResponse, error) {
+	agora, err := ds.HorarioLocal(ctx, tenantID, ref)
+	if err != nil {
+		return dto.CardapioDisponivelResponse{}, err
+	}
+
+	categorias, err := ds.queries.ListCategoriasDisponibilidade(ctx, tenantID)
+	if err != nil {
+		return dto.CardapioDisponivelResponse{}, err
+	}
+	produtos, err := ds.queries.ListProdutosCardapio(ctx, tenantID)
+	if err != nil {
+		return dto.CardapioDisponivelResponse{}, err
+	}
+
+	// linhas vêm ordenadas por categoria/produto; agrupa mantendo a ordem
+	porCategoria := make(map[uuid.UUID][]*dto.CardapioProdutoResponse)
+	var atual *dto.CardapioProdutoResponse
+	var atualStatus int16
+	fechar := func() {
+		if atual == nil {
+			return
+		}
+		if atual.Disponivel && atualStatus != 1 {
+			atual.Disponivel, atual.Motivo = false, dto.MotivoProdutoInativo
+		}
+		atual = nil
+	}
+	for _, row := range produtos {
+		if atual == nil || atual.ID != row.IDProduto.String() {
+			fechar()
+			atual = &dto.CardapioProdutoResponse{
+				ID:     row.IDProduto.String(),
+				Nome:   row.ProdutoNome,
+				Motivo: dto.MotivoPrecoIndisponivel, // até achar uma opção disponível
+			}
+			atualStatus = row.ProdutoStatus
+			porCategoria[row.IDCategoria] = append(porCategoria[row.IDCategoria], atual)
+		}
+
+		opcao := dto.CardapioOpcaoResponse{
+			IDCategoriaOpcao: row.IDCategoriaOpcao.String(),
+			Nome:             row.OpcaoNome,
+			Disponivel:       true,
+		}
+		if v, ok := decimalutils.NumericToCentavos(row.PrecoBase); ok {
+			opcao.PrecoBase = decimalutils.FromCentavos(v)
+		} else {
+			opcao.PrecoBase = decimalutils.FromCentavos(0)
+			opcao.Disponivel, opcao.Motivo = false, dto.MotivoPrecoIndisponivel
+		}
+		if v, ok := decimalutils.NumericToCentavos(row.PrecoPromocional); ok {
+			promo := decimalutils.FromCentavos(v)
+			opcao.PrecoPromocional = &promo
+		}
+		switch {
+		case !opcao.Disponivel:
+		case row.OpcaoStatus != 1:
+			opcao.Disponivel, opcao.Motivo = false, dto.MotivoOpcaoInativa
+		case row.Disponivel != 1:
+			opcao.Disponivel, opcao.Motivo = false, dto.MotivoPrecoIndisponivel
+		}
+		if opcao.Disponivel {
+			atual.Disponivel, atual.Motivo = true, ""
+		}
+		atual.Opcoes = append(atual.Opcoes, opcao)
+	}
+	fechar()
+
+	out := dto.CardapioDisponivelResponse{
+		DataReferencia: agora,
+		Timezone:       agora.Location().String(),
+		Categorias:     []dto.CardapioCategoriaResponse{},
+	}
+	for _, c := range categorias {
+		disponivel, motivo := categoriaDisponivel(c, agora)
+		if !disponivel && !incluirIndisponiveis {
+			continue
+		}
+
+		cat := dto.CardapioCategoriaResponse{
+			ID:         c.ID.String(),
+			Nome:       c.Nome,
+			Inicio:     formatarHora(c.Inicio),
+			Fim:        formatarHora(c.Fim),
+			Disponivel: disponivel,
+			Motivo:     motivo,
+			Produtos:   []dto.CardapioProdutoResponse{},
+		}
+		for _, p := range porCategoria[c.ID] {
+			prod := *p
+			if !disponivel {
+				prod.Disponivel, prod.Motivo = false, motivo
+			}
+			if !incluirIndisponiveis {
+				if !prod.Disponivel {
+					continue
+				}
+				prod.Opcoes = filtrarOpcoesDisponiveis(prod.Opcoes)
+			}
+			if prod.Opcoes == nil {
+				prod.Opcoes = []dto.CardapioOpcaoResponse{}
+			}
+			cat.Produtos = append(cat.Produtos, prod)
+		}
+		out.Categorias = append(out.Categorias, cat)
+	}
+	return out, nil
+}
+
+// VerificarPedido confere a janela e o dia das categorias dos itens
+// (idCategorias na ordem dos itens) em ref, ou agora se nil; o pedido
+// agendado é conferido no horário marcado. Produto inativo e preço
+// indisponível são apontados pela precificação; categorias desconhecidas
+// também.
+func (ds *DisponibilidadeService) VerificarPedido(ctx context.Context, tenantID uuid.UUID, ref *time.Time, idCategorias []string) ([]dto.IndisponibilidadeItem, error) {
+	agora, err := ds.HorarioLocal(ctx, tenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.queries.ListCategoriasDisponibilidade(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	categorias := make(map[uuid.UUID]pgstore.ListCategoriasDisponibilidadeRow, len(rows))
+	for _, c := range rows {
+		categorias[c.ID] = c
+	}
+
+	var out []dto.IndisponibilidadeItem
+	for i, id := range idCategorias {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		c, ok := categorias[parsed]
+		if !ok {
+			continue
+		}
+		if disponivel, motivo := categoriaDisponivel(c, agora); !disponivel {
+			out = append(out, dto.IndisponibilidadeItem{
+				Item:        i,
+				IDCategoria: c.ID.String(),
+				Categoria:   c.Nome,
+				Motivo:      motivo,
+				Mensagem:    mensagemIndisponivel(i, c, motivo),
+			})
+		}
+	}
+	return out, nil
+}
+
+// categoriaDisponivel aplica ativo, janela e dia da semana no horário local t
+func categoriaDisponivel(c pgstore.ListCategoriasDisponibilidadeRow, t time.Time) (bool, string) {
+	if c.Ativo != 1 {
+		return false, dto.MotivoCategoriaInativa
+	}
+
+	dia := t.Weekday()
+	if c.Inicio.Valid && c.Fim.Valid && c.Inicio.Microseconds != c.Fim.Microseconds {
+		agora := microsDoDia(t)
+		inicio, fim := c.Inicio.Microseconds, c.Fim.Microseconds
+		switch {
+		case inicio < fim:
+			if agora < inicio || agora >= fim {
+				return false, dto.MotivoForaDoHorario
+			}
+		case agora >= inicio:
+			// trecho antes da meia-noite: dia corrente
+		case agora < fim:
+			dia = (dia + 6) % 7 // madrugada pertence ao dia anterior
+		default:
+			return false, dto.MotivoForaDoHorario
+		}
+	}
+
+	if diasDisponiveis(c)[dia] != 1 {
+		return false, dto.MotivoDiaIndisponivel
+	}
+	return true, ""
+}
+
+// indexado por time.Weekday (domingo = 0)
+func diasDisponiveis(c pgstore.ListCategoriasDisponibilidadeRow) [7]int16 {
+	return [7]int16{
+		c.DisponivelDomingo,
+		c.DisponivelSegunda,
+		c.DisponivelTerca,
+		c.DisponivelQuarta,
+		c.DisponivelQuinta,
+		c.DisponivelSexta,
+		c.DisponivelSabado,
+	}
+}
+
+func microsDoDia(t time.Time) int64 {
+	h, m, s := t.Clock()
+	return (int64(h)*3600+int64(m)*60+int64(s))*1e6 + int64(t.Nanosecond()/1000)
+}
+
+func formatarHora(t pgtype.Time) string {
+	if !t.Valid {
+		return "00:00"
+	}
+	minutos := t.Microseconds / 60e6
+	return fmt.Sprintf("%02d:%02d", minutos/60, minutos%60)
+}
+
+func mensagemIndisponivel(i int, c pgstore.ListCategoriasDisponibilidadeRow, motivo string) string {
+	switch motivo {
+	case dto.MotivoCategoriaInativa:
+		return fmt.Sprintf("item %d: categoria %s está inativa", i+1, c.Nome)
+	case dto.MotivoForaDoHorario:
+		return fmt.Sprintf("item %d: categoria %s disponível apenas das %s às %s",
+			i+1, c.Nome, formatarHora(c.Inicio), formatarHora(c.Fim))
+	default:
+		return fmt.Sprintf("item %d: categoria %s não está disponível hoje", i+1, c.Nome)
+	}
+}
+
+func filtrarOpcoesDisponiveis(opcoes []dto.CardapioOpcaoResponse) []dto.CardapioOpcaoResponse {
+	out := make([]dto.CardapioOpcaoResponse, 0, len(opcoes))
+	for _, o := range opcoes {
+		if o.Disponivel {
+			out = append(out, o)
+		}
+	}
+	return out
+}
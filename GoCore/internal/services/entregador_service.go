@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrEntregadorNaoEncontrado = errors.New("entregador não encontrado")
+	ErrUsuarioEntregadorEmUso  = errors.New("usuário já vinculado a outro entregador")
+	ErrUsuarioNaoEntregador    = errors.New("usuário não está vinculado a um entregador")
+)
+
+// EntregadorInvalidoError aponta dados do entregador incoerentes com o tenant
+type EntregadorInvalidoError struct {
+	Mensagem string
+}
+
+func (e *EntregadorInvalidoError) Error() string { return e.Mensagem }
+
+// EntregadorService mantém o cadastro de entregadores e o relatório de
+// entregas. As rotas ficam em RotaEntregaService.
+type EntregadorService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewEntregadorService(pool *pgxpool.Pool) EntregadorService {
+	return EntregadorService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (es *EntregadorService) List(ctx context.Context, tenantID uuid.UUID) ([]dto.EntregadorResponse, error) {
+	entregadores, err := es.queries.ListEntregadores(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.EntregadorResponse, len(entregadores))
+	for i, e := range entregadores {
+		out[i] = dto.EntregadorToResponse(e)
+	}
+	return out, nil
+}
+
+func (es *EntregadorService) Get(ctx context.Context, tenantID, id uuid.UUID) (dto.EntregadorResponse, error) {
+	e, err := es.queries.GetEntregador(ctx, pgstore.GetEntregadorParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.EntregadorResponse{}, ErrEntregadorNaoEncontrado
+		}
+		return dto.EntregadorResponse{}, err
+	}
+	return dto.EntregadorToResponse(e), nil
+}
+
+func (es *EntregadorService) Create(ctx context.Context, in dto.EntregadorDTO) (dto.EntregadorResponse, error) {
+	if err := es.validar(ctx, in); err != nil {
+		return dto.EntregadorResponse{}, err
+	}
+	e, err := es.queries.CreateEntregador(ctx, in.ToCreateParams())
+	if err != nil {
+		return dto.EntregadorResponse{}, entregadorPgError(err)
+	}
+	return dto.EntregadorToResponse(e), nil
+}
+
+// Update troca o cadastro. A taxa nova vale para pedidos atribuídos depois;
+// os que já estão em rota mantêm a taxa gravada.
+func (es *EntregadorService) Update(ctx context.Context, in dto.EntregadorDTO) (dto.EntregadorResponse, error) {
+	if err := es.validar(ctx, in); err != nil {
+		return dto.EntregadorResponse{}, err
+	}
+	e, err := es.queries.UpdateEntregador(ctx, in.ToUpdateParams())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.EntregadorResponse{}, ErrEntregadorNaoEncontrado
+		}
+		return dto.EntregadorResponse{}, entregadorPgError(err)
+	}
+	return dto.EntregadorToResponse(e), nil
+}
+
+func (es *EntregadorService) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	n, err := es.queries.DeleteEntregador(ctx, pgstore.DeleteEntregadorParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrEntregadorNaoEncontrado
+	}
+	return nil
+}
+
+// Relatorio lista, por entregador e dia da saída, as rotas, entregas e
+// valores. Sem período, vale o dia de hoje.
+func (es *EntregadorService) Relatorio(ctx context.Context, tenantID uuid.UUID, inicio, fim *time.Time, idEntregador *uuid.UUID) ([]dto.RelatorioEntregadorDiaResponse, error) {
+	hoje := time.Now()
+	if inicio == nil {
+		inicio = &hoje
+	}
+	if fim == nil {
+		fim = inicio
+	}
+	if fim.Before(*inicio) {
+		return nil, &EntregadorInvalidoError{Mensagem: "fim deve ser igual ou depois do início"}
+	}
+
+	params := pgstore.ListRelatorioEntregadoresParams{
+		TenantID: tenantID,
+		Inicio:   pgtype.Date{Time: *inicio, Valid: true},
+		Fim:      pgtype.Date{Time: *fim, Valid: true},
+	}
+	if idEntregador != nil {
+		params.IDEntregador = pgtype.UUID{Bytes: *idEntregador, Valid: true}
+	}
+	rows, err := es.queries.ListRelatorioEntregadores(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dto.RelatorioEntregadoresToResponse(rows), nil
+}
+
+func (es *EntregadorService) validar(ctx context.Context, in dto.EntregadorDTO) error {
+	if decimalutils.ToCentavos(in.TaxaPorEntrega) < 0 {
+		return &EntregadorInvalidoError{Mensagem: "taxa_por_entrega não pode ser negativa"}
+	}
+	if in.IDUsuario == nil {
+		return nil
+	}
+	idUsuario, err := uuid.Parse(*in.IDUsuario)
+	if err != nil {
+		return &EntregadorInvalidoError{Mensagem: "id_usuario inválido"}
+	}
+	n, err := es.queries.CountUsuarioTenant(ctx, pgstore.CountUsuarioTenantParams{ID: idUsuario, TenantID: in.TenantID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &EntregadorInvalidoError{Mensagem: "usuário não encontrado"}
+	}
+	return nil
+}
+
+// entregadorPgError traduz o índice único de usuário do entregador
+func entregadorPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrUsuarioEntregadorEmUso
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Por quanto tempo uma Idempotency-Key vale; depois disso é reaproveitada
+const IdempotencyValidade = 24 * time.Hour
+
+// IdempotencyService guarda em idempotency_keys a resposta de cada
+// (tenant, Idempotency-Key). É o store do middleware
+// httpmiddleware.Idempotency.
+type IdempotencyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewIdempotencyService(pool *pgxpool.Pool) IdempotencyService {
+	return IdempotencyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// Reservar grava a chave como em processamento. Se ela já existe (e não
+// venceu) devolve o registro existente e false; a PK serializa requisições
+// simultâneas com a mesma chave.
+func (is *IdempotencyService) Reservar(ctx context.Context, tenantID uuid.UUID, chave, metodo, rota, hash string) (dto.IdempotencyRegistro, bool, error) {
+	_, err := is.queries.ReservarIdempotencyKey(ctx, pgstore.ReservarIdempotencyKeyParams{
+		TenantID:         tenantID,
+		Chave:            chave,
+		Metodo:           metodo,
+		Rota:             rota,
+		HashRequisicao:   hash,
+		ValidadeSegundos: IdempotencyValidade.Seconds(),
+	})
+	if err == nil {
+		return dto.IdempotencyRegistro{}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return dto.IdempotencyRegistro{}, false, err
+	}
+
+	existente, err := is.queries.GetIdempotencyKey(ctx, pgstore.GetIdempotencyKeyParams{
+		TenantID: tenantID,
+		Chave:    chave,
+	})
+	if err != nil {
+		return dto.IdempotencyRegistro{}, false, err
+	}
+	return dto.IdempotencyKeyToRegistro(existente), false, nil
+}
+
+// Concluir guarda a resposta que será repetida nos reenvios
+func (is *IdempotencyService) Concluir(ctx context.Context, tenantID uuid.UUID, chave string, status int, contentType string, resposta []byte) error {
+	return is.queries.ConcluirIdempotencyKey(ctx, pgstore.ConcluirIdempotencyKeyParams{
+		TenantID:    tenantID,
+		Chave:       chave,
+		StatusCode:  pgtype.Int4{Int32: int32(status), Valid: true},
+		ContentType: pgtype.Text{String: contentType, Valid: contentType != ""},
+		Resposta:    resposta,
+	})
+}
+
+// Liberar apaga a chave para que o cliente possa tentar de novo
+// (erro interno ou panic no handler).
+func (is *IdempotencyService) Liberar(ctx context.Context, tenantID uuid.UUID, chave string) error {
+	return is.queries.DeleteIdempotencyKey(ctx, pgstore.DeleteIdempotencyKeyParams{
+		TenantID: tenantID,
+		Chave:    chave,
+	})
+}
+
+// RunLimpeza apaga periodicamente as chaves vencidas até ctx ser cancelado
+func (is *IdempotencyService) RunLimpeza(ctx context.Context, intervalo time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := is.queries.DeleteIdempotencyKeysExpiradas(ctx, IdempotencyValidade.Seconds())
+			if err != nil {
+				logger.Error("erro ao limpar idempotency keys", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				logger.Info("idempotency keys vencidas removidas", zap.Int64("total", n))
+			}
+		}
+	}
+}
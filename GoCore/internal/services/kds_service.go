@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrEstacaoNaoEncontrada     = errors.New("estação de produção não encontrada")
+	ErrCategoriaNaoEncontrada   = errors.New("categoria não encontrada")
+	ErrProdutoNaoEncontrado     = errors.New("produto não encontrado")
+	ErrItemPreparoNaoEncontrado = errors.New("item do pedido não encontrado")
+	ErrTransicaoPreparoInvalida = errors.New("transição de status de preparo inválida")
+)
+
+// Transições permitidas entre os estados de preparo. Voltar um passo é
+// permitido para corrigir toques errados na tela.
+var transicoesPreparo = map[string][]string{
+	dto.StatusPreparoQueued:    {dto.StatusPreparoPreparing, dto.StatusPreparoDone},
+	dto.StatusPreparoPreparing: {dto.StatusPreparoQueued, dto.StatusPreparoDone},
+	dto.StatusPreparoDone:      {dto.StatusPreparoPreparing, dto.StatusPreparoBumped},
+	dto.StatusPreparoBumped:    {dto.StatusPreparoDone},
+}
+
+// KdsService mantém as estações de produção e o estado de preparo dos itens.
+// Quando o último item de um pedido fica pronto, o pedido é marcado como
+// pronto (pedido_pronto/data_pedido_pronto) e o evento pedido.pronto é gravado.
+type KdsService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewKdsService(pool *pgxpool.Pool) KdsService {
+	return KdsService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+/* -------------------- Estações -------------------- */
+
+func (ks *KdsService) CreateEstacao(ctx context.Context, in dto.CreateEstacaoProducaoDTO) (dto.EstacaoProducaoResponse, error) {
+	estacao, err := ks.queries.CreateEstacaoProducao(ctx, dto.CreateEstacaoDTOToParams(&in))
+	if err != nil {
+		return dto.EstacaoProducaoResponse{}, err
+	}
+	return dto.EstacaoProducaoToResponse(estacao), nil
+}
+
+func (ks *KdsService) ListEstacoes(ctx context.Context, tenantID uuid.UUID) ([]dto.EstacaoProducaoResponse, error) {
+	rows, err := ks.queries.ListEstacoesProducao(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.EstacoesProducaoToResponses(rows), nil
+}
+
+func (ks *KdsService) GetEstacao(ctx context.Context, id, tenantID uuid.UUID) (dto.EstacaoProducaoResponse, error) {
+	estacao, err := ks.queries.GetEstacaoProducao(ctx, pgstore.GetEstacaoProducaoParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.EstacaoProducaoResponse{}, ErrEstacaoNaoEncontrada
+		}
+		return dto.EstacaoProducaoResponse{}, err
+	}
+	return dto.EstacaoProducaoToResponse(estacao), nil
+}
+
+func (ks *KdsService) UpdateEstacao(ctx context.Context, in dto.UpdateEstacaoProducaoDTO) (dto.EstacaoProducaoResponse, error) {
+	estacao, err := ks.queries.UpdateEstacaoProducao(ctx, dto.UpdateEstacaoDTOToParams(&in))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.EstacaoProducaoResponse{}, ErrEstacaoNaoEncontrada
+		}
+		return dto.EstacaoProducaoResponse{}, err
+	}
+	return dto.EstacaoProducaoToResponse(estacao), nil
+}
+
+// DeleteEstacao exclui a estação e remove o roteamento de categorias e
+// produtos que apontavam para ela. Itens já lançados mantêm a estação.
+func (ks *KdsService) DeleteEstacao(ctx context.Context, id, tenantID uuid.UUID) error {
+	tx, err := ks.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := ks.queries.WithTx(tx)
+
+	n, err := q.DeleteEstacaoProducao(ctx, pgstore.DeleteEstacaoProducaoParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrEstacaoNaoEncontrada
+	}
+
+	ref := pgtype.UUID{Bytes: id, Valid: true}
+	if err := q.UnsetEstacaoCategorias(ctx, ref); err != nil {
+		return err
+	}
+	if err := q.UnsetEstacaoProdutos(ctx, ref); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+/* -------------------- Roteamento -------------------- */
+
+func (ks *KdsService) SetCategoriaEstacao(ctx context.Context, categoriaID, tenantID uuid.UUID, in dto.SetEstacaoDTO) error {
+	ref, err := ks.estacaoRef(ctx, tenantID, in.IDEstacao)
+	if err != nil {
+		return err
+	}
+
+	n, err := ks.queries.SetCategoriaEstacao(ctx, pgstore.SetCategoriaEstacaoParams{
+		ID:        categoriaID,
+		IDEstacao: ref,
+		IDTenant:  tenantID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCategoriaNaoEncontrada
+	}
+	return nil
+}
+
+func (ks *KdsService) SetProdutoEstacao(ctx context.Context, produtoID, tenantID uuid.UUID, in dto.SetEstacaoDTO) error {
+	ref, err := ks.estacaoRef(ctx, tenantID, in.IDEstacao)
+	if err != nil {
+		return err
+	}
+
+	n, err := ks.queries.SetProdutoEstacao(ctx, pgstore.SetProdutoEstacaoParams{
+		ID:        produtoID,
+		IDEstacao: ref,
+		IDTenant:  tenantID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrProdutoNaoEncontrado
+	}
+	return nil
+}
+
+// estacaoRef valida que a estação pertence ao tenant; nil remove o roteamento.
+func (ks *KdsService) estacaoRef(ctx context.Context, tenantID uuid.UUID, id *uuid.UUID) (pgtype.UUID, error) {
+	if id == nil {
+		return pgtype.UUID{}, nil
+	}
+	if _, err := ks.GetEstacao(ctx, *id, tenantID); err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}, nil
+}
+
+/* -------------------- Fila -------------------- */
+
+// ListFila devolve os itens pendentes (e, se pedido, os prontos aguardando
+// despacho) de pedidos em aberto. estacaoID nil devolve todas as estações.
+func (ks *KdsService) ListFila(ctx context.Context, tenantID uuid.UUID, estacaoID *uuid.UUID, incluirProntos bool) ([]dto.FilaPreparoItemResponse, error) {
+	params := pgstore.ListFilaPreparoParams{
+		TenantID:       tenantID,
+		IncluirProntos: incluirProntos,
+	}
+	if estacaoID != nil {
+		if _, err := ks.GetEstacao(ctx, *estacaoID, tenantID); err != nil {
+			return nil, err
+		}
+		params.IDEstacao = pgtype.UUID{Bytes: *estacaoID, Valid: true}
+	}
+
+	rows, err := ks.queries.ListFilaPreparo(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FilaPreparoRowsToResponses(rows), nil
+}
+
+// UpdateItemStatus muda o estado de preparo de um item, ajustando os
+// timestamps. Se não restar item pendente no pedido, marca o pedido como
+// pronto e grava o evento pedido.pronto na mesma transação; se um item
+// pronto volta para a fila ou para o preparo, desmarca o pedido. O pedido
+// é travado antes do item, para que duas estações concluindo os últimos
+// itens ao mesmo tempo não deixem de ver uma à outra na contagem.
+func (ks *KdsService) UpdateItemStatus(ctx context.Context, tenantID, userID, itemID uuid.UUID, status string) (dto.PedidoItemPreparoResponse, error) {
+	tx, err := ks.pool.Begin(ctx)
+	if err != nil {
+		return dto.PedidoItemPreparoResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := ks.queries.WithTx(tx)
+
+	if _, err := q.LockPedidoDoItemPreparo(ctx, pgstore.LockPedidoDoItemPreparoParams{
+		ID:       itemID,
+		TenantID: tenantID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PedidoItemPreparoResponse{}, ErrItemPreparoNaoEncontrado
+		}
+		return dto.PedidoItemPreparoResponse{}, err
+	}
+
+	item, err := q.GetPedidoItemPreparoForUpdate(ctx, pgstore.GetPedidoItemPreparoForUpdateParams{
+		ID:       itemID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PedidoItemPreparoResponse{}, ErrItemPreparoNaoEncontrado
+		}
+		return dto.PedidoItemPreparoResponse{}, err
+	}
+	if !slices.Contains(transicoesPreparo[item.StatusPreparo], status) {
+		return dto.PedidoItemPreparoResponse{}, ErrTransicaoPreparoInvalida
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	params := pgstore.UpdatePedidoItemStatusPreparoParams{
+		ID:                itemID,
+		StatusPreparo:     status,
+		DataInicioPreparo: item.DataInicioPreparo,
+		DataFimPreparo:    item.DataFimPreparo,
+	}
+	switch status {
+	case dto.StatusPreparoQueued:
+		params.DataInicioPreparo = pgtype.Timestamptz{}
+		params.DataFimPreparo = pgtype.Timestamptz{}
+	case dto.StatusPreparoPreparing:
+		if !params.DataInicioPreparo.Valid {
+			params.DataInicioPreparo = now
+		}
+		params.DataFimPreparo = pgtype.Timestamptz{}
+	case dto.StatusPreparoDone:
+		// item marcado pronto direto da fila: início = fim
+		if !params.DataInicioPreparo.Valid {
+			params.DataInicioPreparo = now
+		}
+		if item.StatusPreparo != dto.StatusPreparoBumped {
+			params.DataFimPreparo = now
+		}
+	case dto.StatusPreparoBumped:
+		params.DataDespacho = now
+	}
+
+	updated, err := q.UpdatePedidoItemStatusPreparo(ctx, params)
+	if err != nil {
+		return dto.PedidoItemPreparoResponse{}, err
+	}
+	resp := dto.PedidoItemPreparoToResponse(updated)
+
+	if status == dto.StatusPreparoDone || status == dto.StatusPreparoBumped {
+		pendentes, err := q.CountPedidoItensPendentesPreparo(ctx, item.IDPedido)
+		if err != nil {
+			return dto.PedidoItemPreparoResponse{}, err
+		}
+		if pendentes == 0 {
+			marcado, err := ks.marcarPedidoPronto(ctx, q, tenantID, userID, item.IDPedido)
+			if err != nil {
+				return dto.PedidoItemPreparoResponse{}, err
+			}
+			resp.PedidoPronto = marcado
+		}
+	} else if item.StatusPreparo == dto.StatusPreparoDone || item.StatusPreparo == dto.StatusPreparoBumped {
+		if err := ks.desmarcarPedidoPronto(ctx, q, tenantID, userID, item.IDPedido); err != nil {
+			return dto.PedidoItemPreparoResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.PedidoItemPreparoResponse{}, err
+	}
+	return resp, nil
+}
+
+// marcarPedidoPronto devolve false se o pedido já estava pronto.
+func (ks *KdsService) marcarPedidoPronto(ctx context.Context, q *pgstore.Queries, tenantID, userID, pedidoID uuid.UUID) (bool, error) {
+	pedido, err := q.MarcarPedidoProntoAutomatico(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := registrarPedidoPronto(ctx, q, tenantID, userID, pgstore.DesmarcarPedidoProntoAutomaticoRow(pedido)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// desmarcarPedidoPronto não faz nada se o pedido não estava pronto; o
+// evento pedido.pronto sai com pedido_pronto=0, como no PUT manual.
+func (ks *KdsService) desmarcarPedidoPronto(ctx context.Context, q *pgstore.Queries, tenantID, userID, pedidoID uuid.UUID) error {
+	pedido, err := q.DesmarcarPedidoProntoAutomatico(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return registrarPedidoPronto(ctx, q, tenantID, userID, pedido)
+}
+
+func registrarPedidoPronto(ctx context.Context, q *pgstore.Queries, tenantID, userID uuid.UUID, pedido pgstore.DesmarcarPedidoProntoAutomaticoRow) error {
+	payload := dto.PedidoProntoPayload{
+		ID:           pedido.ID.String(),
+		CodigoPedido: pedido.CodigoPedido,
+		PedidoPronto: pedido.PedidoPronto,
+	}
+	if pedido.DataPedidoPronto.Valid {
+		payload.DataPedidoPronto = &pedido.DataPedidoPronto.Time
+	}
+
+	ev, err := dto.NewOutboxEventDTO(tenantID, userID,
+		dto.OutboxAggregatePedido, pedido.ID.String(), dto.EventPedidoPronto, payload)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateOutboxEvent(ctx, dto.CreateOutboxDTOToParams(&ev))
+	return err
+}
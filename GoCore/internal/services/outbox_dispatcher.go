@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// OutboxPublisher entrega um evento do outbox ao destino final (fila, webhook, log...).
+// Retornar erro faz o dispatcher reagendar o evento com backoff exponencial.
+type OutboxPublisher interface {
+	Publish(ctx context.Context, ev dto.OutboxEventResponse) error
+}
+
+// LogOutboxPublisher apenas registra o evento no log; útil como publisher padrão
+// enquanto nenhuma integração externa estiver configurada.
+type LogOutboxPublisher struct {
+	logger *zap.Logger
+}
+
+func NewLogOutboxPublisher(logger *zap.Logger) LogOutboxPublisher {
+	return LogOutboxPublisher{logger: logger.Named("outbox")}
+}
+
+func (p LogOutboxPublisher) Publish(_ context.Context, ev dto.OutboxEventResponse) error {
+	p.logger.Info("outbox event",
+		zap.Int64("id", ev.ID),
+		zap.String("tenant_id", ev.TenantID.String()),
+		zap.String("event_type", ev.EventType),
+		zap.String("aggregate_type", ev.AggregateType),
+		zap.String("aggregate_id", ev.AggregateID),
+	)
+	return nil
+}
+
+type OutboxDispatcherConfig struct {
+	BatchSize           int32         // eventos reservados por vez
+	PollInterval        time.Duration // intervalo entre varreduras
+	ClaimLease          time.Duration // por quanto tempo o lote fica reservado para publicação
+	MaxAttempts         int32         // após isso o evento vai para dead-letter
+	BaseBackoff         time.Duration // espera após a 1ª falha (dobra a cada tentativa)
+	MaxBackoff          time.Duration
+	PublishTimeout      time.Duration // timeout de cada Publish
+	Retention           time.Duration // idade mínima dos processados removidos
+	DeadLetterRetention time.Duration // idade mínima dos dead-letter removidos
+	CleanupInterval     time.Duration
+}
+
+func DefaultOutboxDispatcherConfig() OutboxDispatcherConfig {
+	return OutboxDispatcherConfig{
+		BatchSize:           100,
+		PollInterval:        2 * time.Second,
+		ClaimLease:          5 * time.Minute,
+		MaxAttempts:         10,
+		BaseBackoff:         5 * time.Second,
+		MaxBackoff:          30 * time.Minute,
+		PublishTimeout:      15 * time.Second,
+		Retention:           30 * 24 * time.Hour,
+		DeadLetterRetention: 90 * 24 * time.Hour,
+		CleanupInterval:     time.Hour,
+	}
+}
+
+// OutboxDispatcher consome a tabela outbox_event e entrega os eventos ao publisher.
+// Vários dispatchers (várias instâncias da API) podem rodar ao mesmo tempo:
+// o lote é reservado com FOR UPDATE SKIP LOCKED num UPDATE curto que adia
+// next_attempt_at por ClaimLease; a publicação acontece sem transação aberta.
+// A entrega é at-least-once: se o resultado não for gravado, o evento volta
+// quando a reserva expira.
+type OutboxDispatcher struct {
+	pool      *pgxpool.Pool
+	queries   *pgstore.Queries
+	publisher OutboxPublisher
+	logger    *zap.Logger
+	cfg       OutboxDispatcherConfig
+}
+
+func NewOutboxDispatcher(pool *pgxpool.Pool, publisher OutboxPublisher, logger *zap.Logger, cfg OutboxDispatcherConfig) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		pool:      pool,
+		queries:   pgstore.New(pool),
+		publisher: publisher,
+		logger:    logger.Named("outbox-dispatcher"),
+		cfg:       cfg,
+	}
+}
+
+// Run bloqueia até o contexto ser cancelado.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	poll := time.NewTicker(d.cfg.PollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(d.cfg.CleanupInterval)
+	defer cleanup.Stop()
+
+	d.logger.Info("dispatcher iniciado",
+		zap.Int32("batch_size", d.cfg.BatchSize),
+		zap.Duration("poll_interval", d.cfg.PollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("dispatcher finalizado")
+			return
+		case <-poll.C:
+			d.drain(ctx)
+		case <-cleanup.C:
+			if err := d.Cleanup(ctx); err != nil {
+				d.logger.Error("erro na limpeza do outbox", zap.Error(err))
+			}
+		}
+	}
+}
+
+// drain processa lotes enquanto houver backlog (lote cheio).
+func (d *OutboxDispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := d.DispatchBatch(ctx)
+		if err != nil {
+			d.logger.Error("erro ao despachar lote do outbox", zap.Error(err))
+			return
+		}
+		if n < int(d.cfg.BatchSize) {
+			return
+		}
+	}
+}
+
+// DispatchBatch reserva um lote, publica cada evento fora de transação e
+// grava o resultado de cada um num UPDATE próprio. Devolve quantos eventos
+// foram reservados.
+func (d *OutboxDispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	leasedUntil := time.Now().Add(d.cfg.ClaimLease)
+	rows, err := d.queries.ClaimOutboxEventsForDispatch(ctx, pgstore.ClaimOutboxEventsForDispatchParams{
+		Limit:         d.cfg.BatchSize,
+		NextAttemptAt: leasedUntil,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	events := dto.OutboxClaimedRowsToResponses(rows)
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	for i, ev := range events {
+		// sem tempo para publicar dentro da reserva: o resto volta quando
+		// ela expirar, sem contar tentativa
+		if ctx.Err() != nil || time.Now().Add(d.cfg.PublishTimeout).After(leasedUntil) {
+			d.logger.Warn("reserva do lote do outbox esgotada",
+				zap.Int("pendentes", len(events)-i))
+			break
+		}
+		if err := d.deliver(ctx, ev); err != nil {
+			d.logger.Error("erro ao gravar resultado do evento do outbox",
+				zap.Int64("id", ev.ID),
+				zap.Error(err))
+		}
+	}
+
+	return len(rows), nil
+}
+
+func (d *OutboxDispatcher) deliver(ctx context.Context, ev dto.OutboxEventResponse) error {
+	pubCtx, cancel := context.WithTimeout(ctx, d.cfg.PublishTimeout)
+	pubErr := d.publisher.Publish(pubCtx, ev)
+	cancel()
+
+	if pubErr == nil {
+		return d.queries.MarkOutboxEventProcessed(ctx, ev.ID)
+	}
+
+	attempts := ev.Attempts + 1
+	reason := dto.TextOrNull(pubErr.Error())
+
+	if attempts >= d.cfg.MaxAttempts {
+		d.logger.Warn("evento do outbox movido para dead-letter",
+			zap.Int64("id", ev.ID),
+			zap.String("event_type", ev.EventType),
+			zap.Int32("attempts", attempts),
+			zap.Error(pubErr))
+		return d.queries.MarkOutboxEventDeadLetter(ctx, pgstore.MarkOutboxEventDeadLetterParams{
+			ID:        ev.ID,
+			LastError: reason,
+		})
+	}
+
+	wait := d.backoff(attempts)
+	d.logger.Warn("falha ao publicar evento do outbox",
+		zap.Int64("id", ev.ID),
+		zap.String("event_type", ev.EventType),
+		zap.Int32("attempts", attempts),
+		zap.Duration("retry_in", wait),
+		zap.Error(pubErr))
+
+	return d.queries.ScheduleOutboxEventRetry(ctx, pgstore.ScheduleOutboxEventRetryParams{
+		ID:            ev.ID,
+		LastError:     reason,
+		NextAttemptAt: time.Now().Add(wait),
+	})
+}
+
+// backoff devolve BaseBackoff * 2^(attempts-1), limitado a MaxBackoff.
+func (d *OutboxDispatcher) backoff(attempts int32) time.Duration {
+	wait := d.cfg.BaseBackoff
+	for i := int32(1); i < attempts; i++ {
+		wait *= 2
+		if wait >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// Cleanup remove os eventos processados há mais de Retention e os
+// dead-letter estacionados há mais de DeadLetterRetention.
+func (d *OutboxDispatcher) Cleanup(ctx context.Context) error {
+	if err := d.queries.DeleteProcessedOutboxEventsOlderThan(ctx, time.Now().Add(-d.cfg.Retention)); err != nil {
+		return fmt.Errorf("delete processed outbox events: %w", err)
+	}
+	if err := d.queries.DeleteDeadLetterOutboxEventsOlderThan(ctx, time.Now().Add(-d.cfg.DeadLetterRetention)); err != nil {
+		return fmt.Errorf("delete dead-letter outbox events: %w", err)
+	}
+	return nil
+}
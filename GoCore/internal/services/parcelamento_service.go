@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ParcelamentoInvalidoError aponta plano fora das regras (valor, entrada,
+// vencimento)
+type ParcelamentoInvalidoError struct {
+	Mensagem string
+}
+
+func (e *ParcelamentoInvalidoError) Error() string { return e.Mensagem }
+
+// ParcelamentoService gera o crediário do pedido: divide o valor em
+// parcelas com o resto dos centavos na última, calcula os vencimentos e
+// grava a entrada (pagamento) e as parcelas em contas_receber. A soma é
+// conferida contra o restante do pedido, como no gatilho
+// enforce_parcela_nao_ultrapassa.
+type ParcelamentoService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewParcelamentoService(pool *pgxpool.Pool) ParcelamentoService {
+	return ParcelamentoService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// parcelaPlano é uma parcela calculada, ainda não gravada
+type parcelaPlano struct {
+	numero     int16
+	vencimento time.Time
+	centavos   int64
+}
+
+// Simular devolve o plano sem gravar nada
+func (ps *ParcelamentoService) Simular(ctx context.Context, in dto.ParcelamentoDTO) (dto.ParcelamentoResponse, error) {
+	pedido, err := ps.queries.GetPedidoParcelamento(ctx, pgstore.GetPedidoParcelamentoParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ParcelamentoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.ParcelamentoResponse{}, err
+	}
+	hoje, err := ps.hoje(ctx, ps.queries, in.TenantID)
+	if err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+
+	out, _, err := planoParcelamento(pedido, in, hoje)
+	return out, err
+}
+
+// Gerar recalcula o plano com o pedido travado e grava entrada e parcelas.
+// As parcelas levam autorizado_por quando o gerente liberou o crédito.
+func (ps *ParcelamentoService) Gerar(ctx context.Context, in dto.ParcelamentoDTO) (dto.ParcelamentoResponse, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := ps.queries.WithTx(tx)
+
+	if _, err := q.GetPedidoStatusForUpdate(ctx, pgstore.GetPedidoStatusForUpdateParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ParcelamentoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.ParcelamentoResponse{}, err
+	}
+	pedido, err := q.GetPedidoParcelamento(ctx, pgstore.GetPedidoParcelamentoParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	})
+	if err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+	hoje, err := ps.hoje(ctx, q, in.TenantID)
+	if err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+
+	out, plano, err := planoParcelamento(pedido, in, hoje)
+	if err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+
+	if out.Entrada != nil {
+		centavos := decimalutils.ToCentavos(*out.Entrada)
+		obs := fmt.Sprintf("Entrada do parcelamento em %dx", len(plano))
+		pagamento, err := q.InsertPagamentoEntrada(ctx, pgstore.InsertPagamentoEntradaParams{
+			IDPedido:       pedido.ID,
+			FormaPagamento: *in.FormaEntrada,
+			ValorPago:      decimalutils.CentavosToNumeric(centavos),
+			Observacao:     toPgTypeText(&obs),
+		})
+		if err != nil {
+			return dto.ParcelamentoResponse{}, err
+		}
+		if err := criarEventoOutbox(ctx, q, in.TenantID, in.UserID,
+			dto.OutboxAggregatePagamento, pagamento.ID.String(), dto.EventPagamentoRegistered,
+			dto.PagamentoEventPayload{
+				ID:             pagamento.ID.String(),
+				IDPedido:       pedido.ID.String(),
+				FormaPagamento: *in.FormaEntrada,
+				ValorPago:      decimalutils.FromCentavos(centavos),
+			}); err != nil {
+			return dto.ParcelamentoResponse{}, err
+		}
+		out.IDPagamentoEntrada = &pagamento.ID
+	}
+
+	autorizadoPor := pgtype.UUID{}
+	if in.AutorizadoPor != nil {
+		autorizadoPor = pgtype.UUID{Bytes: *in.AutorizadoPor, Valid: true}
+	}
+	for i, p := range plano {
+		row, err := q.InsertParcelaContaReceber(ctx, pgstore.InsertParcelaContaReceberParams{
+			IDPedido:      pedido.ID,
+			Parcela:       p.numero,
+			Vencimento:    pgtype.Date{Time: p.vencimento, Valid: true},
+			ValorDevido:   decimalutils.CentavosToNumeric(p.centavos),
+			AutorizadoPor: autorizadoPor,
+		})
+		if err != nil {
+			return dto.ParcelamentoResponse{}, err
+		}
+		out.Parcelas[i].ID = &row.ID
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.ParcelamentoResponse{}, err
+	}
+	out.Confirmado = true
+	return out, nil
+}
+
+func (ps *ParcelamentoService) hoje(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID) (time.Time, error) {
+	loc, err := fusoDoTenant(ctx, q, tenantID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	agora := time.Now().In(loc)
+	return time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// planoParcelamento confere entrada e valor contra o restante do pedido e
+// divide o valor: todas as parcelas com o valor truncado nos centavos, o
+// resto na última
+func planoParcelamento(pedido pgstore.GetPedidoParcelamentoRow, in dto.ParcelamentoDTO, hoje time.Time) (dto.ParcelamentoResponse, []parcelaPlano, error) {
+	if pedido.IDStatus == dto.PedidoStatusCancelado {
+		return dto.ParcelamentoResponse{}, nil, ErrPedidoJaCancelado
+	}
+	restante := centavos(pedido.ValorPedido) - centavos(pedido.ValorPago) - centavos(pedido.SaldoParcelas)
+	if restante <= 0 {
+		return dto.ParcelamentoResponse{}, nil, ErrPedidoQuitado
+	}
+	invalido := func(format string, args ...any) error {
+		return &ParcelamentoInvalidoError{Mensagem: fmt.Sprintf(format, args...)}
+	}
+
+	if in.Parcelas < 1 {
+		return dto.ParcelamentoResponse{}, nil, invalido("informe ao menos uma parcela")
+	}
+	if in.PrimeiroVencimento.Before(hoje) {
+		return dto.ParcelamentoResponse{}, nil, invalido("primeiro_vencimento não pode ser anterior a hoje (%s)", hoje.Format("2006-01-02"))
+	}
+	if in.Intervalo == dto.IntervaloDias && in.IntervaloDias < 1 {
+		return dto.ParcelamentoResponse{}, nil, invalido("informe intervalo_dias")
+	}
+	if int(pedido.ProximaParcela)+int(in.Parcelas)-1 > 32767 {
+		return dto.ParcelamentoResponse{}, nil, invalido("pedido já tem parcelas demais")
+	}
+
+	var entrada int64
+	if in.Entrada != nil {
+		entrada = decimalutils.ToCentavos(*in.Entrada)
+		if entrada <= 0 {
+			return dto.ParcelamentoResponse{}, nil, invalido("entrada deve ser maior que zero")
+		}
+		if entrada >= restante {
+			return dto.ParcelamentoResponse{}, nil, invalido("entrada quita o restante do pedido (%s): registre como pagamento",
+				decimalutils.FromCentavos(restante))
+		}
+	}
+	valor := restante - entrada
+	if in.Valor != nil {
+		valor = decimalutils.ToCentavos(*in.Valor)
+		if valor <= 0 {
+			return dto.ParcelamentoResponse{}, nil, invalido("valor deve ser maior que zero")
+		}
+		if entrada+valor > restante {
+			return dto.ParcelamentoResponse{}, nil, invalido("entrada + valor (%s) excede o restante do pedido (%s)",
+				decimalutils.FromCentavos(entrada+valor), decimalutils.FromCentavos(restante))
+		}
+	}
+	n := int64(in.Parcelas)
+	if valor < n {
+		return dto.ParcelamentoResponse{}, nil, invalido("valor %s não comporta %d parcelas", decimalutils.FromCentavos(valor), n)
+	}
+
+	base := valor / n
+	plano := make([]parcelaPlano, n)
+	out := dto.ParcelamentoResponse{
+		IDPedido:       pedido.ID,
+		CodigoPedido:   pedido.CodigoPedido,
+		Restante:       decimalutils.FromCentavos(restante),
+		ValorParcelado: decimalutils.FromCentavos(valor),
+		Parcelas:       make([]dto.ParcelaPlanoResponse, n),
+	}
+	if entrada > 0 {
+		e := decimalutils.FromCentavos(entrada)
+		out.Entrada, out.FormaEntrada = &e, in.FormaEntrada
+	}
+	for i := range plano {
+		p := parcelaPlano{
+			numero:     pedido.ProximaParcela + int16(i),
+			vencimento: vencimentoParcela(in.PrimeiroVencimento, in.Intervalo, in.IntervaloDias, i),
+			centavos:   base,
+		}
+		if i == len(plano)-1 {
+			p.centavos = valor - base*(n-1)
+		}
+		plano[i] = p
+		out.Parcelas[i] = dto.ParcelaPlanoResponse{
+			Parcela:    p.numero,
+			Vencimento: p.vencimento.Format("2006-01-02"),
+			Valor:      decimalutils.FromCentavos(p.centavos),
+		}
+	}
+	return out, plano, nil
+}
+
+// vencimentoParcela da i-ésima parcela (0 = primeira). No mensal, o dia é
+// mantido e cai no último dia dos meses mais curtos (31/01 → 28/02 → 31/03).
+func vencimentoParcela(primeiro time.Time, intervalo string, dias, i int) time.Time {
+	switch intervalo {
+	case dto.IntervaloSemanal:
+		return primeiro.AddDate(0, 0, 7*i)
+	case dto.IntervaloDias:
+		return primeiro.AddDate(0, 0, dias*i)
+	default:
+		mes := time.Date(primeiro.Year(), primeiro.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		ultimoDia := mes.AddDate(0, 1, -1).Day()
+		return mes.AddDate(0, 0, min(primeiro.Day(), ultimoDia)-1)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func diaTeste(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pedidoParcelamento(valor, pago, saldoParcelas int64) pgstore.GetPedidoParcelamentoRow {
+	return pgstore.GetPedidoParcelamentoRow{
+		CodigoPedido:   "100",
+		IDStatus:       dto.PedidoStatusConfirmado,
+		ValorPedido:    decimalutils.CentavosToNumeric(valor),
+		ValorPago:      decimalutils.CentavosToNumeric(pago),
+		SaldoParcelas:  decimalutils.CentavosToNumeric(saldoParcelas),
+		ProximaParcela: 1,
+	}
+}
+
+func decimalTeste(centavos int64) *types.Decimal {
+	d := decimalutils.FromCentavos(centavos)
+	return &d
+}
+
+func TestPlanoParcelamentoValores(t *testing.T) {
+	tests := []struct {
+		nome     string
+		pedido   pgstore.GetPedidoParcelamentoRow
+		parcelas int16
+		entrada  *types.Decimal
+		valor    *types.Decimal
+		want     []int64
+	}{
+		{"divisão exata", pedidoParcelamento(30000, 0, 0), 3, nil, nil, []int64{10000, 10000, 10000}},
+		{"resto na última", pedidoParcelamento(10000, 0, 0), 3, nil, nil, []int64{3333, 3333, 3334}},
+		{"resto de vários centavos", pedidoParcelamento(10006, 0, 0), 7, nil, nil, []int64{1429, 1429, 1429, 1429, 1429, 1429, 1432}},
+		{"desconta pago e parcelas abertas", pedidoParcelamento(10000, 2000, 3000), 2, nil, nil, []int64{2500, 2500}},
+		{"entrada sai do parcelado", pedidoParcelamento(10000, 0, 0), 3, decimalTeste(1000), nil, []int64{3000, 3000, 3000}},
+		{"valor informado", pedidoParcelamento(10000, 0, 0), 2, nil, decimalTeste(5001), []int64{2500, 2501}},
+		{"um centavo por parcela", pedidoParcelamento(3, 0, 0), 3, nil, nil, []int64{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			in := dto.ParcelamentoDTO{
+				Parcelas:           tt.parcelas,
+				PrimeiroVencimento: diaTeste("2026-02-10"),
+				Intervalo:          dto.IntervaloMensal,
+				Entrada:            tt.entrada,
+				Valor:              tt.valor,
+			}
+			_, plano, err := planoParcelamento(tt.pedido, in, diaTeste("2026-01-10"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plano) != len(tt.want) {
+				t.Fatalf("%d parcelas, want %d", len(plano), len(tt.want))
+			}
+			var soma, total int64
+			for i, p := range plano {
+				if p.centavos != tt.want[i] {
+					t.Errorf("parcela %d = %d, want %d", i+1, p.centavos, tt.want[i])
+				}
+				if p.numero != int16(i+1) {
+					t.Errorf("parcela %d com número %d", i+1, p.numero)
+				}
+				soma += p.centavos
+				total += tt.want[i]
+			}
+			if soma != total {
+				t.Errorf("soma das parcelas = %d, want %d", soma, total)
+			}
+		})
+	}
+}
+
+func TestPlanoParcelamentoInvalido(t *testing.T) {
+	hoje := diaTeste("2026-01-10")
+	base := dto.ParcelamentoDTO{Parcelas: 2, PrimeiroVencimento: diaTeste("2026-02-10"), Intervalo: dto.IntervaloMensal}
+	cancelado := pedidoParcelamento(10000, 0, 0)
+	cancelado.IDStatus = dto.PedidoStatusCancelado
+
+	tests := []struct {
+		nome    string
+		pedido  pgstore.GetPedidoParcelamentoRow
+		mudar   func(*dto.ParcelamentoDTO)
+		wantErr error
+	}{
+		{"pedido cancelado", cancelado, nil, ErrPedidoJaCancelado},
+		{"pedido quitado", pedidoParcelamento(10000, 10000, 0), nil, ErrPedidoQuitado},
+		{"já todo em parcelas", pedidoParcelamento(10000, 4000, 6000), nil, ErrPedidoQuitado},
+		{"sem parcelas", pedidoParcelamento(10000, 0, 0), func(d *dto.ParcelamentoDTO) { d.Parcelas = 0 }, nil},
+		{"vencimento no passado", pedidoParcelamento(10000, 0, 0), func(d *dto.ParcelamentoDTO) { d.PrimeiroVencimento = diaTeste("2026-01-09") }, nil},
+		{"intervalo em dias sem dias", pedidoParcelamento(10000, 0, 0), func(d *dto.ParcelamentoDTO) { d.Intervalo = dto.IntervaloDias }, nil},
+		{"entrada quita tudo", pedidoParcelamento(10000, 0, 0), func(d *dto.ParcelamentoDTO) { d.Entrada = decimalTeste(10000) }, nil},
+		{"entrada + valor excede", pedidoParcelamento(10000, 0, 0), func(d *dto.ParcelamentoDTO) { d.Entrada, d.Valor = decimalTeste(1000), decimalTeste(9001) }, nil},
+		{"menos de um centavo por parcela", pedidoParcelamento(1, 0, 0), nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			in := base
+			if tt.mudar != nil {
+				tt.mudar(&in)
+			}
+			_, _, err := planoParcelamento(tt.pedido, in, hoje)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			var invalido *ParcelamentoInvalidoError
+			if !errors.As(err, &invalido) {
+				t.Fatalf("err = %v, want ParcelamentoInvalidoError", err)
+			}
+		})
+	}
+}
+
+func TestVencimentoParcela(t *testing.T) {
+	tests := []struct {
+		nome      string
+		primeiro  string
+		intervalo string
+		dias      int
+		want      []string
+	}{
+		{"mensal no dia 10", "2026-01-10", dto.IntervaloMensal, 0, []string{"2026-01-10", "2026-02-10", "2026-03-10"}},
+		{"mensal no dia 31 volta ao 31", "2026-01-31", dto.IntervaloMensal, 0, []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30", "2026-05-31"}},
+		{"fevereiro bissexto", "2028-01-30", dto.IntervaloMensal, 0, []string{"2028-01-30", "2028-02-29", "2028-03-30"}},
+		{"virada do ano", "2026-11-30", dto.IntervaloMensal, 0, []string{"2026-11-30", "2026-12-30", "2027-01-30", "2027-02-28"}},
+		{"intervalo vazio é mensal", "2026-01-31", "", 0, []string{"2026-01-31", "2026-02-28"}},
+		{"semanal", "2026-02-25", dto.IntervaloSemanal, 0, []string{"2026-02-25", "2026-03-04", "2026-03-11"}},
+		{"a cada 15 dias", "2026-01-31", dto.IntervaloDias, 15, []string{"2026-01-31", "2026-02-15", "2026-03-02"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			for i, want := range tt.want {
+				got := vencimentoParcela(diaTeste(tt.primeiro), tt.intervalo, tt.dias, i).Format("2006-01-02")
+				if got != want {
+					t.Errorf("parcela %d: vencimento %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
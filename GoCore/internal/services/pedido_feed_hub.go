@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	pedidoFeedChannel       = "pedido_feed"
+	pedidoFeedBuffer        = 256 // eventos pendentes por cliente antes de desconectá-lo
+	pedidoFeedBackfillBatch = 500
+	pedidoFeedRetention     = 24 * time.Hour
+	pedidoFeedReconnectWait = 2 * time.Second
+)
+
+// ErrPedidoFeedLagged indica que o cliente ficou para trás (buffer cheio) ou
+// que a conexão LISTEN caiu; ele deve reconectar informando o Last-Event-ID.
+var ErrPedidoFeedLagged = errors.New("feed de pedidos interrompido; reconecte com Last-Event-ID")
+
+// PedidoFeedHub mantém uma conexão LISTEN por instância da API e distribui os
+// eventos do canal pedido_feed aos clientes conectados do mesmo tenant.
+// Como o NOTIFY é enviado pelo Postgres, funciona com várias instâncias.
+type PedidoFeedHub struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	logger  *zap.Logger
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[*PedidoFeedSubscription]struct{}
+}
+
+type PedidoFeedSubscription struct {
+	hub      *PedidoFeedHub
+	tenantID uuid.UUID
+	events   chan dto.PedidoFeedEvent
+	once     sync.Once
+}
+
+func NewPedidoFeedHub(pool *pgxpool.Pool, logger *zap.Logger) *PedidoFeedHub {
+	return &PedidoFeedHub{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		logger:  logger.Named("pedido-feed"),
+		subs:    make(map[uuid.UUID]map[*PedidoFeedSubscription]struct{}),
+	}
+}
+
+// Run escuta o canal até o contexto ser cancelado, reconectando em caso de
+// falha. A limpeza do log pedido_feed roda em paralelo.
+func (h *PedidoFeedHub) Run(ctx context.Context) {
+	go h.cleanupLoop(ctx)
+
+	for ctx.Err() == nil {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		h.logger.Error("conexão LISTEN perdida", zap.Error(err))
+
+		// eventos podem ter sido perdidos: força os clientes a retomar pelo log
+		h.closeAll()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(pedidoFeedReconnectWait):
+		}
+	}
+	h.closeAll()
+}
+
+func (h *PedidoFeedHub) listen(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN *")
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pedidoFeedChannel); err != nil {
+		return err
+	}
+	h.logger.Info("escutando canal", zap.String("channel", pedidoFeedChannel))
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ev dto.PedidoFeedEvent
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			h.logger.Warn("payload inválido no canal", zap.Error(err))
+			continue
+		}
+		h.broadcast(ev)
+	}
+}
+
+func (h *PedidoFeedHub) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.queries.DeletePedidoFeedOlderThan(ctx, time.Now().Add(-pedidoFeedRetention)); err != nil {
+				h.logger.Error("erro na limpeza do feed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (h *PedidoFeedHub) broadcast(ev dto.PedidoFeedEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[ev.TenantID] {
+		select {
+		case sub.events <- ev:
+		default:
+			// cliente lento: desconecta em vez de travar os demais
+			h.removeLocked(sub)
+		}
+	}
+}
+
+func (h *PedidoFeedHub) Subscribe(tenantID uuid.UUID) *PedidoFeedSubscription {
+	sub := &PedidoFeedSubscription{
+		hub:      h,
+		tenantID: tenantID,
+		events:   make(chan dto.PedidoFeedEvent, pedidoFeedBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[tenantID] == nil {
+		h.subs[tenantID] = make(map[*PedidoFeedSubscription]struct{})
+	}
+	h.subs[tenantID][sub] = struct{}{}
+	return sub
+}
+
+// Events é fechado quando o cliente é desconectado pelo hub.
+func (s *PedidoFeedSubscription) Events() <-chan dto.PedidoFeedEvent {
+	return s.events
+}
+
+func (s *PedidoFeedSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (h *PedidoFeedHub) removeLocked(sub *PedidoFeedSubscription) {
+	sub.once.Do(func() {
+		delete(h.subs[sub.tenantID], sub)
+		if len(h.subs[sub.tenantID]) == 0 {
+			delete(h.subs, sub.tenantID)
+		}
+		close(sub.events)
+	})
+}
+
+func (h *PedidoFeedHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// Stream envia ao cliente os eventos posteriores a lastEventID (lidos do log)
+// e depois os eventos ao vivo, até o contexto terminar ou send falhar.
+// lastEventID = 0 (primeira conexão) envia apenas os eventos ao vivo.
+// heartbeat é chamado periodicamente para manter a conexão aberta em proxies.
+//
+// O id do evento é atribuído no INSERT e não no COMMIT, então os eventos não
+// chegam necessariamente em ordem de id. Na retomada também é reenviada uma
+// janela de eventos de id menor que o cursor (ver ListPedidoFeedJanela); a
+// entrega é at-least-once e o cliente deduplica pelo id.
+func (h *PedidoFeedHub) Stream(ctx context.Context, tenantID uuid.UUID, lastEventID int64,
+	heartbeatEvery time.Duration, send func(dto.PedidoFeedEvent) error, heartbeat func() error) error {
+
+	// assina antes de ler o log para não perder eventos entre as duas etapas
+	sub := h.Subscribe(tenantID)
+	defer sub.Close()
+
+	// ids enviados no backfill, para não repeti-los quando chegarem ao vivo
+	enviados := make(map[int64]struct{})
+
+	if lastEventID > 0 {
+		rows, err := h.queries.ListPedidoFeedJanela(ctx, pgstore.ListPedidoFeedJanelaParams{
+			TenantID: tenantID,
+			ID:       lastEventID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, ev := range dto.PedidoFeedRowsToEvents(rows) {
+			if err := send(ev); err != nil {
+				return err
+			}
+			enviados[ev.ID] = struct{}{}
+		}
+	}
+
+	for lastEventID > 0 {
+		rows, err := h.queries.ListPedidoFeedSince(ctx, pgstore.ListPedidoFeedSinceParams{
+			TenantID: tenantID,
+			ID:       lastEventID,
+			Limit:    pedidoFeedBackfillBatch,
+		})
+		if err != nil {
+			return err
+		}
+		for _, ev := range dto.PedidoFeedRowsToEvents(rows) {
+			if err := send(ev); err != nil {
+				return err
+			}
+			enviados[ev.ID] = struct{}{}
+			lastEventID = ev.ID
+		}
+		if len(rows) < pedidoFeedBackfillBatch {
+			break
+		}
+	}
+
+	ticker := time.NewTicker(heartbeatEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return ErrPedidoFeedLagged
+			}
+			if _, ok := enviados[ev.ID]; ok {
+				delete(enviados, ev.ID) // já enviado no backfill
+				continue
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
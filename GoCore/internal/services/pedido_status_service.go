@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPedidoNaoEncontrado = errors.New("pedido não encontrado")
+	ErrStatusNaoEncontrado = errors.New("status não encontrado")
+	ErrMotivoObrigatorio   = errors.New("motivo é obrigatório para cancelar o pedido")
+	ErrLiberacaoSemCaixa   = errors.New("liberar pedido agendado exige um caixa aberto")
+)
+
+// Grafo de status do pedido. Concluído e Cancelado são finais.
+//
+//	Confirmado → Em preparação → Pronto → Saiu para entrega → Concluído
+//	Pronto → Concluído (retirada/balcão); qualquer não final → Cancelado
+//	Agendado → Confirmado (liberação para a cozinha)
+var transicoesStatus = map[int16][]int16{
+	dto.PedidoStatusAgendado:     {dto.PedidoStatusConfirmado, dto.PedidoStatusCancelado},
+	dto.PedidoStatusConfirmado:   {dto.PedidoStatusEmPreparacao, dto.PedidoStatusPronto, dto.PedidoStatusCancelado},
+	dto.PedidoStatusEmPreparacao: {dto.PedidoStatusPronto, dto.PedidoStatusCancelado},
+	dto.PedidoStatusPronto:       {dto.PedidoStatusSaiuEntrega, dto.PedidoStatusConcluido, dto.PedidoStatusCancelado},
+	dto.PedidoStatusSaiuEntrega:  {dto.PedidoStatusConcluido, dto.PedidoStatusCancelado},
+	dto.PedidoStatusConcluido:    {},
+	dto.PedidoStatusCancelado:    {},
+}
+
+var descricoesStatus = map[int16]string{
+	dto.PedidoStatusConfirmado:   "Confirmado",
+	dto.PedidoStatusEmPreparacao: "Em preparação",
+	dto.PedidoStatusPronto:       "Pronto",
+	dto.PedidoStatusSaiuEntrega:  "Saiu para entrega",
+	dto.PedidoStatusConcluido:    "Concluído",
+	dto.PedidoStatusCancelado:    "Cancelado",
+	dto.PedidoStatusAgendado:     "Agendado",
+}
+
+// TransicaoStatusInvalidaError informa de onde para onde o pedido tentou ir
+// e quais status são aceitos a partir do atual.
+type TransicaoStatusInvalidaError struct {
+	De         int16
+	Para       int16
+	Permitidos []int16
+}
+
+func (e *TransicaoStatusInvalidaError) Error() string {
+	if len(e.Permitidos) == 0 {
+		return fmt.Sprintf("pedido %s não pode mudar de status", descricoesStatus[e.De])
+	}
+	nomes := make([]string, len(e.Permitidos))
+	for i, s := range e.Permitidos {
+		nomes[i] = descricoesStatus[s]
+	}
+	return fmt.Sprintf("transição de status inválida: %s → %s (permitidos: %v)",
+		descricoesStatus[e.De], descricoesStatus[e.Para], nomes)
+}
+
+// ValidarTransicaoStatus confere se o pedido pode ir de um status a outro
+func ValidarTransicaoStatus(de, para int16) error {
+	if _, ok := descricoesStatus[para]; !ok {
+		return ErrStatusNaoEncontrado
+	}
+	permitidos := transicoesStatus[de]
+	if !slices.Contains(permitidos, para) {
+		return &TransicaoStatusInvalidaError{De: de, Para: para, Permitidos: permitidos}
+	}
+	return nil
+}
+
+// PedidoStatusService aplica o grafo de status e grava cada transição em
+// pedido_status_historico. O registro de criação é gravado por trigger.
+type PedidoStatusService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewPedidoStatusService(pool *pgxpool.Pool) PedidoStatusService {
+	return PedidoStatusService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// AlterarStatus devolve nil quando o pedido já está no status pedido.
+func (ps *PedidoStatusService) AlterarStatus(ctx context.Context, in dto.AlterarStatusPedidoDTO) (*dto.PedidoStatusHistoricoResponse, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := ps.queries.WithTx(tx)
+
+	pedido, err := q.GetPedidoStatusForUpdate(ctx, pgstore.GetPedidoStatusForUpdateParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPedidoNaoEncontrado
+		}
+		return nil, err
+	}
+	if pedido.IDStatus == in.IDStatus {
+		return nil, nil
+	}
+
+	resp, err := registrarTransicaoStatus(ctx, q, in, pedido.IDStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// registrarTransicaoStatus valida, atualiza o pedido, grava o histórico e o
+// evento pedido.status_changed na transação de q.
+func registrarTransicaoStatus(ctx context.Context, q *pgstore.Queries,
+	in dto.AlterarStatusPedidoDTO, statusAnterior int16) (dto.PedidoStatusHistoricoResponse, error) {
+
+	if err := ValidarTransicaoStatus(statusAnterior, in.IDStatus); err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+	if in.IDStatus == dto.PedidoStatusCancelado && (in.Motivo == nil || *in.Motivo == "") {
+		return dto.PedidoStatusHistoricoResponse{}, ErrMotivoObrigatorio
+	}
+	// A liberação do agendado gera o código definitivo, que é por caixa
+	if statusAnterior == dto.PedidoStatusAgendado && in.IDStatus != dto.PedidoStatusCancelado {
+		if _, err := q.GetCaixaAtivo(ctx, in.TenantID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return dto.PedidoStatusHistoricoResponse{}, ErrLiberacaoSemCaixa
+			}
+			return dto.PedidoStatusHistoricoResponse{}, err
+		}
+	}
+
+	codigoPedido, err := q.UpdatePedidoStatus(ctx, pgstore.UpdatePedidoStatusParams{
+		IDStatus: in.IDStatus,
+		ID:       in.IDPedido,
+	})
+	if err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+
+	params := pgstore.CreatePedidoStatusHistoricoParams{
+		TenantID:       in.TenantID,
+		IDPedido:       in.IDPedido,
+		StatusAnterior: pgtype.Int2{Int16: statusAnterior, Valid: true},
+		StatusNovo:     in.IDStatus,
+		UserID:         pgtype.UUID{Bytes: in.UserID, Valid: in.UserID != uuid.Nil},
+	}
+	if in.Motivo != nil {
+		params.Motivo = pgtype.Text{String: *in.Motivo, Valid: true}
+	}
+	historico, err := q.CreatePedidoStatusHistorico(ctx, params)
+	if err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+
+	payload := dto.PedidoStatusChangedPayload{
+		ID:             in.IDPedido.String(),
+		CodigoPedido:   codigoPedido,
+		StatusAnterior: statusAnterior,
+		StatusNovo:     in.IDStatus,
+	}
+	ev, err := dto.NewOutboxEventDTO(in.TenantID, in.UserID, dto.OutboxAggregatePedido,
+		in.IDPedido.String(), dto.EventPedidoStatusChanged, payload)
+	if err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+	if _, err := q.CreateOutboxEvent(ctx, dto.CreateOutboxDTOToParams(&ev)); err != nil {
+		return dto.PedidoStatusHistoricoResponse{}, err
+	}
+
+	anterior := descricoesStatus[statusAnterior]
+	return dto.PedidoStatusHistoricoToResponse(historico, &anterior, descricoesStatus[in.IDStatus]), nil
+}
+
+func (ps *PedidoStatusService) ListHistorico(ctx context.Context, tenantID, pedidoID uuid.UUID) (dto.PedidoHistoricoResponse, error) {
+	pedido, err := ps.queries.GetPedidoStatusAtual(ctx, pgstore.GetPedidoStatusAtualParams{
+		ID:       pedidoID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PedidoHistoricoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.PedidoHistoricoResponse{}, err
+	}
+
+	rows, err := ps.queries.ListPedidoStatusHistorico(ctx, pgstore.ListPedidoStatusHistoricoParams{
+		IDPedido: pedidoID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return dto.PedidoHistoricoResponse{}, err
+	}
+	return dto.PedidoHistoricoToResponse(pedido, rows), nil
+}
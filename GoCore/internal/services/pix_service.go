@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/pixutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+var (
+	ErrPixNaoConfigurado = errors.New("informe a chave PIX do estabelecimento em /pix/config")
+	ErrPedidoQuitado     = errors.New("pedido já está quitado")
+	ErrPixJaConfirmado   = errors.New("transferência PIX já confirmada neste pedido")
+)
+
+// PixInvalidoError aponta chave, valor ou txid fora das regras do BR Code
+type PixInvalidoError struct {
+	Mensagem string
+}
+
+func (e *PixInvalidoError) Error() string { return e.Mensagem }
+
+// PixService gera as cobranças PIX (BR Code estático) com os dados do
+// recebedor do tenant e registra os recebimentos conferidos pelo operador.
+// O txid da cobrança do pedido vem do codigo_pedido.
+type PixService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewPixService(pool *pgxpool.Pool) PixService {
+	return PixService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (ps *PixService) GetConfig(ctx context.Context, tenantID uuid.UUID) (dto.PixConfigResponse, error) {
+	row, err := ps.queries.GetTenantPix(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PixConfigResponse{}, ErrTenantNaoEncontrado
+		}
+		return dto.PixConfigResponse{}, err
+	}
+	return dto.PixConfigToResponse(row), nil
+}
+
+// SalvarConfig valida a chave e grava nome e cidade já no formato do BR Code
+func (ps *PixService) SalvarConfig(ctx context.Context, tenantID uuid.UUID, in dto.PixConfigDTO) (dto.PixConfigResponse, error) {
+	chave := strings.TrimSpace(in.Chave)
+	if strings.Contains(chave, "@") {
+		chave = strings.ToLower(chave)
+	}
+	if err := pixutils.ValidarChave(chave); err != nil {
+		return dto.PixConfigResponse{}, &PixInvalidoError{Mensagem: err.Error()}
+	}
+	nome := pixutils.NormalizarTexto(in.NomeRecebedor, pixutils.MaxNomeRecebedor)
+	cidade := pixutils.NormalizarTexto(in.Cidade, pixutils.MaxCidade)
+	if nome == "" || cidade == "" {
+		return dto.PixConfigResponse{}, &PixInvalidoError{Mensagem: "nome_recebedor e cidade precisam de letras ou números"}
+	}
+
+	params := pgstore.UpdateTenantPixParams{
+		ID:               tenantID,
+		PixChave:         toPgTypeText(&chave),
+		PixNomeRecebedor: toPgTypeText(&nome),
+		PixCidade:        toPgTypeText(&cidade),
+	}
+	n, err := ps.queries.UpdateTenantPix(ctx, params)
+	if err != nil {
+		return dto.PixConfigResponse{}, err
+	}
+	if n == 0 {
+		return dto.PixConfigResponse{}, ErrTenantNaoEncontrado
+	}
+	return dto.PixConfigToResponse(pgstore.GetTenantPixRow{
+		PixChave:         params.PixChave,
+		PixNomeRecebedor: params.PixNomeRecebedor,
+		PixCidade:        params.PixCidade,
+	}), nil
+}
+
+// CobrancaEstatica: QR fixo do balcão, sem valor e sem txid
+func (ps *PixService) CobrancaEstatica(ctx context.Context, tenantID uuid.UUID) (dto.PixCobrancaResponse, error) {
+	br, err := ps.recebedor(ctx, tenantID)
+	if err != nil {
+		return dto.PixCobrancaResponse{}, err
+	}
+	br.TxID = pixutils.TxIDSemIdentificador
+
+	payload, err := br.Payload()
+	if err != nil {
+		return dto.PixCobrancaResponse{}, &PixInvalidoError{Mensagem: err.Error()}
+	}
+	return dto.PixCobrancaResponse{TxID: br.TxID, Payload: payload}, nil
+}
+
+// CobrancaPedido gera o BR Code com valor do pedido. Sem valor, cobra o
+// saldo em aberto; com valor (pagamento dividido), até o saldo.
+func (ps *PixService) CobrancaPedido(ctx context.Context, tenantID, idPedido uuid.UUID, valor *types.Decimal) (dto.PixCobrancaResponse, error) {
+	br, err := ps.recebedor(ctx, tenantID)
+	if err != nil {
+		return dto.PixCobrancaResponse{}, err
+	}
+
+	pedido, err := ps.queries.GetPedidoCobrancaPix(ctx, pgstore.GetPedidoCobrancaPixParams{
+		ID:       idPedido,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PixCobrancaResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.PixCobrancaResponse{}, err
+	}
+	centavos, err := valorCobrancaPix(pedido, valor)
+	if err != nil {
+		return dto.PixCobrancaResponse{}, err
+	}
+
+	br.TxID = pixutils.TxIDDoPedido(pedido.CodigoPedido)
+	br.Descricao = "Pedido " + pedido.CodigoPedido
+	br.Valor = centavos
+
+	payload, err := br.Payload()
+	if err != nil {
+		return dto.PixCobrancaResponse{}, &PixInvalidoError{Mensagem: err.Error()}
+	}
+	v := decimalutils.FromCentavos(centavos)
+	return dto.PixCobrancaResponse{
+		IDPedido:     &pedido.ID,
+		CodigoPedido: pedido.CodigoPedido,
+		TxID:         br.TxID,
+		Valor:        &v,
+		Payload:      payload,
+	}, nil
+}
+
+// Confirmar grava o recebimento PIX como pagamento do pedido, com o txid
+// e o id fim a fim, se informado. O txid tem de ser o da cobrança do pedido.
+// É idempotente: a mesma transferência (e2e_id) ou, sem ela, o mesmo txid
+// e valor confirmados de novo devolvem o pagamento já gravado. Os gatilhos
+// de pedido_pagamentos recusam valor acima do saldo e lançam no caixa.
+func (ps *PixService) Confirmar(ctx context.Context, in dto.PixConfirmacaoDTO) (dto.PixConfirmacaoResponse, error) {
+	if err := pixutils.ValidarTxID(in.TxID); err != nil {
+		return dto.PixConfirmacaoResponse{}, &PixInvalidoError{Mensagem: err.Error()}
+	}
+
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := ps.queries.WithTx(tx)
+
+	// Trava o pedido: duas confirmações da mesma transferência não passam juntas
+	if _, err := q.GetPedidoStatusForUpdate(ctx, pgstore.GetPedidoStatusForUpdateParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.PixConfirmacaoResponse{}, ErrPedidoNaoEncontrado
+		}
+		return dto.PixConfirmacaoResponse{}, err
+	}
+	pedido, err := q.GetPedidoCobrancaPix(ctx, pgstore.GetPedidoCobrancaPixParams{
+		ID:       in.IDPedido,
+		TenantID: in.TenantID,
+	})
+	if err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+	if in.TxID != pixutils.TxIDDoPedido(pedido.CodigoPedido) {
+		return dto.PixConfirmacaoResponse{}, &PixInvalidoError{Mensagem: "txid não é da cobrança deste pedido"}
+	}
+
+	txid := pgtype.Text{String: in.TxID, Valid: true}
+	e2e := toPgTypeText(in.E2EID)
+	valorInformado := decimalutils.ToCentavos(in.Valor)
+	anterior, err := q.GetPagamentoPixConfirmado(ctx, pgstore.GetPagamentoPixConfirmadoParams{
+		IDPedido:  pedido.ID,
+		E2eID:     e2e,
+		Txid:      txid,
+		ValorPago: decimalutils.CentavosToNumeric(valorInformado),
+	})
+	switch {
+	case err == nil:
+		valorAnterior, _ := decimalutils.NumericToCentavos(anterior.ValorPago)
+		if anterior.PixTxid != txid || valorAnterior != valorInformado {
+			return dto.PixConfirmacaoResponse{}, ErrPixJaConfirmado
+		}
+		return dto.PixConfirmacaoResponse{
+			IDPagamento:  anterior.ID,
+			IDPedido:     pedido.ID,
+			TxID:         in.TxID,
+			Valor:        decimalutils.FromCentavos(valorAnterior),
+			Observacao:   anterior.Observacao.String,
+			CreatedAt:    anterior.CreatedAt,
+			JaConfirmado: true,
+		}, nil
+	case !errors.Is(err, pgx.ErrNoRows):
+		return dto.PixConfirmacaoResponse{}, err
+	}
+
+	centavos, err := valorCobrancaPix(pedido, &in.Valor)
+	if err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+
+	obs := "PIX txid " + in.TxID
+	if in.E2EID != nil {
+		obs += " E2E " + *in.E2EID
+	}
+
+	pagamento, err := q.InsertPagamentoPix(ctx, pgstore.InsertPagamentoPixParams{
+		IDPedido:      pedido.ID,
+		ValorPago:     decimalutils.CentavosToNumeric(centavos),
+		AutorizadoPor: pgtype.UUID{Bytes: in.UserID, Valid: in.UserID != uuid.Nil},
+		Observacao:    toPgTypeText(&obs),
+		PixTxid:       txid,
+		PixE2eID:      e2e,
+	})
+	if err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+
+	if err := criarEventoOutbox(ctx, q, in.TenantID, in.UserID,
+		dto.OutboxAggregatePagamento, pagamento.ID.String(), dto.EventPagamentoRegistered,
+		dto.PagamentoEventPayload{
+			ID:             pagamento.ID.String(),
+			IDPedido:       pedido.ID.String(),
+			FormaPagamento: "PIX",
+			ValorPago:      decimalutils.FromCentavos(centavos),
+		}); err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return dto.PixConfirmacaoResponse{}, err
+	}
+	return dto.PixConfirmacaoResponse{
+		IDPagamento: pagamento.ID,
+		IDPedido:    pedido.ID,
+		TxID:        in.TxID,
+		Valor:       decimalutils.FromCentavos(centavos),
+		Observacao:  obs,
+		CreatedAt:   pagamento.CreatedAt,
+	}, nil
+}
+
+// recebedor monta o BR Code base com a chave, o nome e a cidade do tenant
+func (ps *PixService) recebedor(ctx context.Context, tenantID uuid.UUID) (pixutils.BRCode, error) {
+	row, err := ps.queries.GetTenantPix(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pixutils.BRCode{}, ErrTenantNaoEncontrado
+		}
+		return pixutils.BRCode{}, err
+	}
+	if !row.PixChave.Valid || !row.PixNomeRecebedor.Valid || !row.PixCidade.Valid {
+		return pixutils.BRCode{}, ErrPixNaoConfigurado
+	}
+	return pixutils.BRCode{
+		Chave:         row.PixChave.String,
+		NomeRecebedor: row.PixNomeRecebedor.String,
+		Cidade:        row.PixCidade.String,
+	}, nil
+}
+
+// valorCobrancaPix confere o valor contra o saldo do pedido; sem valor,
+// devolve o saldo
+func valorCobrancaPix(pedido pgstore.GetPedidoCobrancaPixRow, valor *types.Decimal) (int64, error) {
+	if pedido.IDStatus == dto.PedidoStatusCancelado {
+		return 0, ErrPedidoJaCancelado
+	}
+	total, _ := decimalutils.NumericToCentavos(pedido.ValorPedido)
+	pago, _ := decimalutils.NumericToCentavos(pedido.ValorPago)
+	saldo := total - pago
+	if saldo <= 0 {
+		return 0, ErrPedidoQuitado
+	}
+	if valor == nil {
+		return saldo, nil
+	}
+
+	centavos := decimalutils.ToCentavos(*valor)
+	if centavos <= 0 {
+		return 0, &PixInvalidoError{Mensagem: "valor deve ser maior que zero"}
+	}
+	if centavos > saldo {
+		return 0, &PixInvalidoError{Mensagem: fmt.Sprintf("valor acima do saldo do pedido (%s)", decimalutils.FromCentavos(saldo))}
+	}
+	return centavos, nil
+}
@@ -0,0 +1,682 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// CotacaoInvalidaError lista os itens que não puderam ser precificados
+// (produto inexistente ou indisponível, opção sem preço, adicional de outra
+// categoria, grupo obrigatório sem escolha...). É devolvido como 422 com os
+// problemas por item.
+type CotacaoInvalidaError struct {
+	Problemas []dto.CotacaoProblema
+}
+
+func (e *CotacaoInvalidaError) Error() string {
+	msgs := make([]string, len(e.Problemas))
+	for i, p := range e.Problemas {
+		msgs[i] = p.Mensagem
+	}
+	return "cotação inválida: " + strings.Join(msgs, "; ")
+}
+
+// PrecificacaoService calcula os preços de um pedido a partir do cardápio:
+// produto_precos (promocional quando houver, respeitando disponivel),
+// categoria_adicional_opcoes.valor, a regra de sabores (meia pizza, frações)
+// da categoria e os acréscimos dos slots de combo.
+// Também valida as regras dos grupos de adicionais (ver adicionais_regras.go)
+// e calcula o desconto do cupom (ver cupom_regras.go) e a taxa da zona de
+// entrega (ver zonas_entrega_regras.go).
+// Os valores enviados pelo cliente servem apenas para apontar divergências.
+type PrecificacaoService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewPrecificacaoService(pool *pgxpool.Pool) PrecificacaoService {
+	return PrecificacaoService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type precoOpcao struct {
+	centavos    int64
+	promocional bool
+	disponivel  bool
+}
+
+type produtoCotacao struct {
+	nome        string
+	ativo       bool
+	idCategoria uuid.UUID
+	opcaoMeia   string
+	maxSabores  int
+	precos      map[uuid.UUID]precoOpcao // por id_categoria_opcao
+}
+
+// combosCotacao: slots dos combos do pedido e acréscimos em centavos por
+// slot e produto
+type combosCotacao struct {
+	slots      map[uuid.UUID][]pgstore.ListComboSlotsCotacaoRow // por id_produto do combo
+	acrescimos map[uuid.UUID]map[uuid.UUID]int64
+}
+
+// cotacao acumula problemas e divergências durante o cálculo
+type cotacao struct {
+	problemas    []dto.CotacaoProblema
+	divergencias []dto.PrecoDivergencia
+}
+
+func (c *cotacao) problema(item, adicional *int, campo, codigo, format string, args ...any) {
+	c.problemas = append(c.problemas, dto.CotacaoProblema{
+		Item:      item,
+		Adicional: adicional,
+		Campo:     campo,
+		Codigo:    codigo,
+		Mensagem:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *cotacao) comparar(item, adicional *int, campo string, informado *types.Decimal, calculado int64) {
+	if informado == nil || informado.Big == nil {
+		return
+	}
+	if decimalutils.ToCentavos(*informado) != calculado {
+		c.divergencias = append(c.divergencias, dto.PrecoDivergencia{
+			Campo:     campo,
+			Item:      item,
+			Adicional: adicional,
+			Informado: *informado,
+			Calculado: decimalutils.FromCentavos(calculado),
+		})
+	}
+}
+
+// Cotar devolve o detalhamento de preços do pedido. Erros de cardápio vêm
+// como *CotacaoInvalidaError; divergências não são erro.
+func (ps *PrecificacaoService) Cotar(ctx context.Context, in dto.PedidoCotacaoDTO) (dto.PedidoCotacaoResponse, error) {
+	var c cotacao
+
+	for i := range in.Itens {
+		in.Itens[i].NormalizarSabores()
+	}
+
+	produtos, err := ps.carregarProdutos(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+	adicionais, err := ps.carregarAdicionais(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+	grupos, err := ps.carregarGruposAdicionais(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+	combos, err := ps.carregarCombos(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+	cupom, err := ps.carregarCupom(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+	entrega, err := ps.carregarEntrega(ctx, in)
+	if err != nil {
+		return dto.PedidoCotacaoResponse{}, err
+	}
+
+	resp := dto.PedidoCotacaoResponse{
+		Itens:        make([]dto.CotacaoItemResponse, len(in.Itens)),
+		Divergencias: []dto.PrecoDivergencia{},
+	}
+
+	var subtotal int64
+	totais := make([]int64, len(in.Itens))
+	for i := range in.Itens {
+		item := &in.Itens[i]
+		idx := i
+		out, total := ps.cotarItem(&c, &idx, item, produtos, adicionais, combos)
+		validarGruposAdicionais(&c, &idx, item, adicionais, grupos)
+		resp.Itens[i] = out
+		totais[i] = total
+		subtotal += total
+	}
+
+	taxa := valorOuZero(in.TaxaEntrega)
+	// Com zona de entrega, a taxa informada só serve para apontar divergência
+	if entrega != nil {
+		resp.Entrega = resolverZonaEntrega(&c, entrega, subtotal)
+		if resp.Entrega != nil {
+			c.comparar(nil, nil, "taxa_entrega", in.TaxaEntrega, decimalutils.ToCentavos(resp.Entrega.Taxa))
+			taxa = decimalutils.ToCentavos(resp.Entrega.Taxa)
+		}
+	}
+	desconto := valorOuZero(in.Desconto)
+	acrescimo := valorOuZero(in.Acrescimo)
+	if taxa < 0 {
+		c.problema(nil, nil, "taxa_entrega", dto.ProblemaValorNegativo, "taxa de entrega não pode ser negativa")
+	}
+	if desconto < 0 {
+		c.problema(nil, nil, "desconto", dto.ProblemaValorNegativo, "desconto não pode ser negativo")
+	}
+	if acrescimo < 0 {
+		c.problema(nil, nil, "acrescimo", dto.ProblemaValorNegativo, "acréscimo não pode ser negativo")
+	}
+	// Com cupom, o desconto informado só serve para apontar divergência
+	if cupom != nil && len(c.problemas) == 0 {
+		resp.Cupom = aplicarCupom(&c, cupom, in, totais, subtotal, taxa)
+		if resp.Cupom != nil {
+			c.comparar(nil, nil, "desconto", in.Desconto, decimalutils.ToCentavos(resp.Cupom.Desconto))
+			desconto = decimalutils.ToCentavos(resp.Cupom.Desconto)
+		}
+	}
+	total := subtotal + taxa + acrescimo - desconto
+	if total < 0 {
+		c.problema(nil, nil, "desconto", dto.ProblemaDescontoExcedeTotal, "desconto maior que o valor do pedido")
+	}
+
+	if len(c.problemas) > 0 {
+		return dto.PedidoCotacaoResponse{}, &CotacaoInvalidaError{Problemas: c.problemas}
+	}
+
+	c.comparar(nil, nil, "valor_total", in.ValorTotal, subtotal)
+
+	resp.ValorTotal = decimalutils.FromCentavos(subtotal)
+	resp.TaxaEntrega = decimalutils.FromCentavos(taxa)
+	resp.Desconto = decimalutils.FromCentavos(desconto)
+	resp.Acrescimo = decimalutils.FromCentavos(acrescimo)
+	resp.TotalAPagar = decimalutils.FromCentavos(total)
+	resp.Divergencias = append(resp.Divergencias, c.divergencias...)
+	return resp, nil
+}
+
+func (ps *PrecificacaoService) cotarItem(c *cotacao, idx *int, item *dto.CotacaoItemDTO,
+	produtos map[uuid.UUID]*produtoCotacao, adicionais map[uuid.UUID]pgstore.ListAdicionalOpcoesCotacaoRow, combos combosCotacao) (dto.CotacaoItemResponse, int64) {
+
+	out := dto.CotacaoItemResponse{
+		Indice:     *idx,
+		IDProduto:  item.IDProduto,
+		Quantidade: item.Quantidade,
+		Adicionais: make([]dto.CotacaoAdicionalResponse, len(item.Adicionais)),
+	}
+
+	idCategoria := uuid.MustParse(item.IDCategoria)
+	if item.IDCategoriaOpcao == nil {
+		c.problema(idx, nil, "id_categoria_opcao", dto.ProblemaOpcaoObrigatoria, "item %d: opção da categoria (tamanho) é obrigatória", *idx+1)
+		return out, 0
+	}
+	out.IDCategoriaOpcao = *item.IDCategoriaOpcao
+	idOpcao := uuid.MustParse(*item.IDCategoriaOpcao)
+
+	preco1, ok := ps.precoProduto(c, idx, "id_produto", item.IDProduto, idCategoria, idOpcao, produtos)
+	if !ok {
+		return out, 0
+	}
+	p1 := produtos[uuid.MustParse(item.IDProduto)]
+	out.ProdutoNome = p1.nome
+	unitario := preco1.centavos
+	out.PrecoPromocional = preco1.promocional
+
+	if item.IDProduto2 != nil {
+		out.IDProduto2 = *item.IDProduto2
+		if p2, ok := produtos[uuid.MustParse(*item.IDProduto2)]; ok {
+			out.Produto2Nome = p2.nome
+		}
+	}
+	if len(item.Sabores) > 0 {
+		unitario, ok = ps.cotarSabores(c, idx, item, &out, p1, idCategoria, idOpcao, produtos)
+		if !ok {
+			return out, 0
+		}
+	}
+	c.comparar(idx, nil, "valor_unitario", item.ValorUnitario, unitario)
+
+	var adicionaisUnidade int64
+	for j := range item.Adicionais {
+		a := &item.Adicionais[j]
+		jdx := j
+		out.Adicionais[j] = dto.CotacaoAdicionalResponse{
+			IDAdicionalOpcao: a.IDAdicionalOpcao,
+			Quantidade:       a.Quantidade,
+		}
+
+		opcao, ok := adicionais[uuid.MustParse(a.IDAdicionalOpcao)]
+		switch {
+		case !ok:
+			c.problema(idx, &jdx, "id_adicional_opcao", dto.ProblemaNaoEncontrado, "item %d: adicional %s não encontrado", *idx+1, a.IDAdicionalOpcao)
+			continue
+		case opcao.IDCategoria != idCategoria:
+			c.problema(idx, &jdx, "id_adicional_opcao", dto.ProblemaCategoriaDivergente, "item %d: adicional %s não pertence à categoria do item", *idx+1, opcao.Nome)
+			continue
+		case opcao.Status != 1 || opcao.AdicionalStatus != 1:
+			c.problema(idx, &jdx, "id_adicional_opcao", dto.ProblemaIndisponivel, "item %d: adicional %s indisponível", *idx+1, opcao.Nome)
+			continue
+		}
+		valor, ok := decimalutils.NumericToCentavos(opcao.Valor)
+		if !ok {
+			c.problema(idx, &jdx, "id_adicional_opcao", dto.ProblemaSemPreco, "item %d: adicional %s sem preço", *idx+1, opcao.Nome)
+			continue
+		}
+
+		c.comparar(idx, &jdx, "adicionais.valor", a.Valor, valor)
+		out.Adicionais[j].Nome = opcao.Nome
+		out.Adicionais[j].ValorUnitario = decimalutils.FromCentavos(valor)
+		out.Adicionais[j].ValorTotal = decimalutils.FromCentavos(valor * int64(a.Quantidade))
+		adicionaisUnidade += valor * int64(a.Quantidade)
+	}
+
+	componentesUnidade, ok := ps.cotarComponentes(c, idx, item, &out, produtos, combos)
+	if !ok {
+		return out, 0
+	}
+
+	// Mesma fórmula de recalcular_total_pedido: os componentes são itens do
+	// pedido com quantidade = quantidade do componente × quantidade do combo
+	total := (unitario + adicionaisUnidade + componentesUnidade) * int64(item.Quantidade)
+	out.ValorUnitario = decimalutils.FromCentavos(unitario)
+	out.ValorAdicionais = decimalutils.FromCentavos(adicionaisUnidade)
+	out.ValorComponentes = decimalutils.FromCentavos(componentesUnidade)
+	out.ValorTotal = decimalutils.FromCentavos(total)
+	return out, total
+}
+
+// cotarSabores valida os sabores do item e aplica a regra da categoria
+// (valorSabores)
+func (ps *PrecificacaoService) cotarSabores(c *cotacao, idx *int, item *dto.CotacaoItemDTO, out *dto.CotacaoItemResponse,
+	p1 *produtoCotacao, idCategoria, idOpcao uuid.UUID, produtos map[uuid.UUID]*produtoCotacao) (int64, bool) {
+
+	if p1.opcaoMeia == "" {
+		c.problema(idx, nil, "sabores", dto.ProblemaMeiaInvalida, "item %d: categoria não permite mais de um sabor", *idx+1)
+		return 0, false
+	}
+	if len(item.Sabores) > p1.maxSabores {
+		c.problema(idx, nil, "sabores", dto.ProblemaSaboresExcedidos, "item %d: categoria permite no máximo %d sabores", *idx+1, p1.maxSabores)
+		return 0, false
+	}
+
+	out.Sabores = make([]dto.CotacaoSaborResponse, len(item.Sabores))
+	vistos := make(map[string]bool, len(item.Sabores))
+	cotados := make([]saborCotado, 0, len(item.Sabores))
+	ok := true
+	for j, sabor := range item.Sabores {
+		if vistos[sabor.IDProduto] {
+			c.problema(idx, nil, "sabores", dto.ProblemaMeiaInvalida, "item %d: sabor %s repetido", *idx+1, sabor.IDProduto)
+			ok = false
+			continue
+		}
+		vistos[sabor.IDProduto] = true
+
+		preco, precoOK := ps.precoProduto(c, idx, "sabores", sabor.IDProduto, idCategoria, idOpcao, produtos)
+		if !precoOK {
+			ok = false
+			continue
+		}
+		out.Sabores[j] = dto.CotacaoSaborResponse{
+			IDProduto:     sabor.IDProduto,
+			ProdutoNome:   produtos[uuid.MustParse(sabor.IDProduto)].nome,
+			Partes:        sabor.Partes,
+			ValorUnitario: decimalutils.FromCentavos(preco.centavos),
+		}
+		out.PrecoPromocional = out.PrecoPromocional || preco.promocional
+		cotados = append(cotados, saborCotado{centavos: preco.centavos, partes: int64(sabor.Partes)})
+	}
+	if !ok {
+		return 0, false
+	}
+
+	regra, valor := valorSabores(p1.opcaoMeia, cotados)
+	out.RegraMeia = regra
+	return valor, true
+}
+
+// saborCotado: preço do sabor na opção do item e a fração que ele ocupa
+type saborCotado struct {
+	centavos int64
+	partes   int64
+}
+
+// valorSabores é a regra de calcular_valor_sabores (migration 063): 'M' →
+// média, 'P' → média ponderada pela fração, qualquer outro valor → maior
+// valor. Arredonda como ROUND(numeric, 2): meio centavo para cima. Devolve
+// a regra aplicada e o valor unitário.
+func valorSabores(opcaoMeia string, sabores []saborCotado) (string, int64) {
+	var soma, somaPonderada, partes, maior int64
+	for _, s := range sabores {
+		soma += s.centavos
+		somaPonderada += s.centavos * s.partes
+		partes += s.partes
+		maior = max(maior, s.centavos)
+	}
+
+	n := int64(len(sabores))
+	switch opcaoMeia {
+	case "M":
+		return "M", (2*soma + n) / (2 * n)
+	case "P":
+		return "P", (2*somaPonderada + partes) / (2 * partes)
+	default:
+		return "V", maior
+	}
+}
+
+// cotarComponentes valida as escolhas do combo contra os slots (categoria,
+// opção fixa, quantidade por slot) e devolve a soma dos acréscimos por
+// unidade do combo. O preço do pacote é o do próprio produto combo.
+func (ps *PrecificacaoService) cotarComponentes(c *cotacao, idx *int, item *dto.CotacaoItemDTO, out *dto.CotacaoItemResponse,
+	produtos map[uuid.UUID]*produtoCotacao, combos combosCotacao) (int64, bool) {
+
+	slots := combos.slots[uuid.MustParse(item.IDProduto)]
+	if len(slots) == 0 {
+		if len(item.Componentes) > 0 {
+			c.problema(idx, nil, "componentes", dto.ProblemaNaoCombo, "item %d: produto %s não é um combo", *idx+1, out.ProdutoNome)
+			return 0, false
+		}
+		return 0, true
+	}
+
+	porSlot := make(map[uuid.UUID]pgstore.ListComboSlotsCotacaoRow, len(slots))
+	for _, s := range slots {
+		porSlot[s.ID] = s
+	}
+
+	out.Componentes = make([]dto.CotacaoComponenteResponse, len(item.Componentes))
+	escolhidos := make(map[uuid.UUID]int, len(slots))
+	var total int64
+	ok := true
+	for k := range item.Componentes {
+		comp := &item.Componentes[k]
+		slot, achou := porSlot[uuid.MustParse(comp.IDComboSlot)]
+		if !achou {
+			c.problema(idx, nil, "componentes", dto.ProblemaComboSlot, "item %d: componente %d em slot que não pertence ao combo", *idx+1, k+1)
+			ok = false
+			continue
+		}
+		escolhidos[slot.ID] += comp.Quantidade
+
+		idCategoria := uuid.MustParse(comp.IDCategoria)
+		if idCategoria != slot.IDCategoria {
+			c.problema(idx, nil, "componentes", dto.ProblemaCategoriaDivergente, "item %d: componente %d fora da categoria de %s", *idx+1, k+1, slot.Nome)
+			ok = false
+			continue
+		}
+
+		var idOpcao uuid.UUID
+		switch {
+		case comp.IDCategoriaOpcao != nil:
+			idOpcao = uuid.MustParse(*comp.IDCategoriaOpcao)
+			if slot.IDCategoriaOpcao.Valid && idOpcao != uuid.UUID(slot.IDCategoriaOpcao.Bytes) {
+				c.problema(idx, nil, "componentes", dto.ProblemaComboSlot, "item %d: componente %d com opção diferente da exigida em %s", *idx+1, k+1, slot.Nome)
+				ok = false
+				continue
+			}
+		case slot.IDCategoriaOpcao.Valid:
+			idOpcao = uuid.UUID(slot.IDCategoriaOpcao.Bytes)
+		default:
+			c.problema(idx, nil, "componentes", dto.ProblemaOpcaoObrigatoria, "item %d: componente %d sem opção da categoria (tamanho)", *idx+1, k+1)
+			ok = false
+			continue
+		}
+
+		if _, precoOK := ps.precoProduto(c, idx, "componentes", comp.IDProduto, idCategoria, idOpcao, produtos); !precoOK {
+			ok = false
+			continue
+		}
+		if len(combos.slots[uuid.MustParse(comp.IDProduto)]) > 0 {
+			c.problema(idx, nil, "componentes", dto.ProblemaComboSlot, "item %d: componente %d é outro combo", *idx+1, k+1)
+			ok = false
+			continue
+		}
+		p := produtos[uuid.MustParse(comp.IDProduto)]
+
+		resp := dto.CotacaoComponenteResponse{
+			IDComboSlot:      comp.IDComboSlot,
+			SlotNome:         slot.Nome,
+			IDProduto:        comp.IDProduto,
+			ProdutoNome:      p.nome,
+			IDCategoriaOpcao: idOpcao.String(),
+			Quantidade:       comp.Quantidade,
+		}
+		if len(comp.Sabores) > 0 {
+			if !ps.validarSaboresComponente(c, idx, k, comp, &resp, p, idCategoria, idOpcao, produtos) {
+				ok = false
+				continue
+			}
+		}
+
+		acrescimo := combos.acrescimos[slot.ID][uuid.MustParse(comp.IDProduto)]
+		c.comparar(idx, nil, "componentes.valor_unitario", comp.ValorUnitario, acrescimo)
+		resp.ValorUnitario = decimalutils.FromCentavos(acrescimo)
+		resp.ValorTotal = decimalutils.FromCentavos(acrescimo * int64(comp.Quantidade))
+		out.Componentes[k] = resp
+		total += acrescimo * int64(comp.Quantidade)
+	}
+
+	for _, s := range slots {
+		if escolhidos[s.ID] != int(s.Quantidade) {
+			c.problema(idx, nil, "componentes", dto.ProblemaComboIncompleto, "item %d: escolha %d produto(s) em %s", *idx+1, s.Quantidade, s.Nome)
+			ok = false
+		}
+	}
+	return total, ok
+}
+
+// validarSaboresComponente: componente com mais de um sabor (pizza do
+// combo). Os sabores seguem as regras da categoria, mas o componente só
+// cobra o acréscimo do slot pelo primeiro sabor.
+func (ps *PrecificacaoService) validarSaboresComponente(c *cotacao, idx *int, k int, comp *dto.CotacaoComponenteDTO, resp *dto.CotacaoComponenteResponse,
+	p1 *produtoCotacao, idCategoria, idOpcao uuid.UUID, produtos map[uuid.UUID]*produtoCotacao) bool {
+
+	if p1.opcaoMeia == "" {
+		c.problema(idx, nil, "componentes", dto.ProblemaMeiaInvalida, "item %d: componente %d: categoria não permite mais de um sabor", *idx+1, k+1)
+		return false
+	}
+	if len(comp.Sabores) > p1.maxSabores {
+		c.problema(idx, nil, "componentes", dto.ProblemaSaboresExcedidos, "item %d: componente %d: categoria permite no máximo %d sabores", *idx+1, k+1, p1.maxSabores)
+		return false
+	}
+
+	resp.Sabores = make([]dto.CotacaoSaborResponse, len(comp.Sabores))
+	vistos := make(map[string]bool, len(comp.Sabores))
+	for j, sabor := range comp.Sabores {
+		if vistos[sabor.IDProduto] {
+			c.problema(idx, nil, "componentes", dto.ProblemaMeiaInvalida, "item %d: componente %d: sabor %s repetido", *idx+1, k+1, sabor.IDProduto)
+			return false
+		}
+		vistos[sabor.IDProduto] = true
+
+		preco, ok := ps.precoProduto(c, idx, "componentes", sabor.IDProduto, idCategoria, idOpcao, produtos)
+		if !ok {
+			return false
+		}
+		resp.Sabores[j] = dto.CotacaoSaborResponse{
+			IDProduto:     sabor.IDProduto,
+			ProdutoNome:   produtos[uuid.MustParse(sabor.IDProduto)].nome,
+			Partes:        sabor.Partes,
+			ValorUnitario: decimalutils.FromCentavos(preco.centavos),
+		}
+	}
+	return true
+}
+
+func (ps *PrecificacaoService) precoProduto(c *cotacao, idx *int, campo, idProduto string, idCategoria, idOpcao uuid.UUID,
+	produtos map[uuid.UUID]*produtoCotacao) (precoOpcao, bool) {
+
+	p, ok := produtos[uuid.MustParse(idProduto)]
+	switch {
+	case !ok:
+		c.problema(idx, nil, campo, dto.ProblemaNaoEncontrado, "item %d: produto %s não encontrado", *idx+1, idProduto)
+		return precoOpcao{}, false
+	case p.idCategoria != idCategoria:
+		c.problema(idx, nil, campo, dto.ProblemaCategoriaDivergente, "item %d: produto %s não pertence à categoria do item", *idx+1, p.nome)
+		return precoOpcao{}, false
+	case !p.ativo:
+		c.problema(idx, nil, campo, dto.ProblemaIndisponivel, "item %d: produto %s inativo", *idx+1, p.nome)
+		return precoOpcao{}, false
+	}
+
+	preco, ok := p.precos[idOpcao]
+	switch {
+	case !ok:
+		c.problema(idx, nil, campo, dto.ProblemaSemPreco, "item %d: produto %s sem preço para a opção informada", *idx+1, p.nome)
+		return precoOpcao{}, false
+	case !preco.disponivel:
+		c.problema(idx, nil, campo, dto.ProblemaIndisponivel, "item %d: produto %s indisponível nesta opção", *idx+1, p.nome)
+		return precoOpcao{}, false
+	}
+	return preco, true
+}
+
+func (ps *PrecificacaoService) carregarProdutos(ctx context.Context, in dto.PedidoCotacaoDTO) (map[uuid.UUID]*produtoCotacao, error) {
+	var ids []uuid.UUID
+	for _, item := range in.Itens {
+		ids = append(ids, uuid.MustParse(item.IDProduto))
+		if item.IDProduto2 != nil {
+			ids = append(ids, uuid.MustParse(*item.IDProduto2))
+		}
+		for _, sabor := range item.Sabores {
+			ids = append(ids, uuid.MustParse(sabor.IDProduto))
+		}
+		for _, comp := range item.Componentes {
+			ids = append(ids, uuid.MustParse(comp.IDProduto))
+			for _, sabor := range comp.Sabores {
+				ids = append(ids, uuid.MustParse(sabor.IDProduto))
+			}
+		}
+	}
+
+	rows, err := ps.queries.ListPrecosProdutosCotacao(ctx, pgstore.ListPrecosProdutosCotacaoParams{
+		TenantID:   in.TenantID,
+		ProdutoIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	produtos := make(map[uuid.UUID]*produtoCotacao)
+	for _, row := range rows {
+		p, ok := produtos[row.IDProduto]
+		if !ok {
+			p = &produtoCotacao{
+				nome:        row.ProdutoNome,
+				ativo:       row.ProdutoStatus == 1,
+				idCategoria: row.IDCategoria,
+				opcaoMeia:   strings.TrimSpace(row.OpcaoMeia.String),
+				maxSabores:  int(row.MaxSabores),
+				precos:      make(map[uuid.UUID]precoOpcao),
+			}
+			produtos[row.IDProduto] = p
+		}
+
+		preco := precoOpcao{disponivel: row.Disponivel == 1}
+		if v, ok := decimalutils.NumericToCentavos(row.PrecoPromocional); ok {
+			preco.centavos, preco.promocional = v, true
+		} else if v, ok := decimalutils.NumericToCentavos(row.PrecoBase); ok {
+			preco.centavos = v
+		} else {
+			preco.disponivel = false
+		}
+		p.precos[row.IDCategoriaOpcao] = preco
+	}
+	return produtos, nil
+}
+
+func (ps *PrecificacaoService) carregarAdicionais(ctx context.Context, in dto.PedidoCotacaoDTO) (map[uuid.UUID]pgstore.ListAdicionalOpcoesCotacaoRow, error) {
+	var ids []uuid.UUID
+	for _, item := range in.Itens {
+		for _, a := range item.Adicionais {
+			ids = append(ids, uuid.MustParse(a.IDAdicionalOpcao))
+		}
+	}
+	if len(ids) == 0 {
+		return map[uuid.UUID]pgstore.ListAdicionalOpcoesCotacaoRow{}, nil
+	}
+
+	rows, err := ps.queries.ListAdicionalOpcoesCotacao(ctx, pgstore.ListAdicionalOpcoesCotacaoParams{
+		TenantID: in.TenantID,
+		OpcaoIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adicionais := make(map[uuid.UUID]pgstore.ListAdicionalOpcoesCotacaoRow, len(rows))
+	for _, row := range rows {
+		adicionais[row.ID] = row
+	}
+	return adicionais, nil
+}
+
+// carregarCombos lê os slots dos produtos do pedido (itens e componentes,
+// para recusar combo dentro de combo) e os acréscimos desses slots.
+func (ps *PrecificacaoService) carregarCombos(ctx context.Context, in dto.PedidoCotacaoDTO) (combosCotacao, error) {
+	combos := combosCotacao{
+		slots:      make(map[uuid.UUID][]pgstore.ListComboSlotsCotacaoRow),
+		acrescimos: make(map[uuid.UUID]map[uuid.UUID]int64),
+	}
+
+	var ids []uuid.UUID
+	for _, item := range in.Itens {
+		ids = append(ids, uuid.MustParse(item.IDProduto))
+		for _, comp := range item.Componentes {
+			ids = append(ids, uuid.MustParse(comp.IDProduto))
+		}
+	}
+
+	slots, err := ps.queries.ListComboSlotsCotacao(ctx, ids)
+	if err != nil {
+		return combos, err
+	}
+	if len(slots) == 0 {
+		return combos, nil
+	}
+
+	slotIDs := make([]uuid.UUID, len(slots))
+	for i, s := range slots {
+		combos.slots[s.IDProduto] = append(combos.slots[s.IDProduto], s)
+		slotIDs[i] = s.ID
+	}
+
+	acrescimos, err := ps.queries.ListComboAcrescimosCotacao(ctx, slotIDs)
+	if err != nil {
+		return combos, err
+	}
+	for _, a := range acrescimos {
+		if combos.acrescimos[a.IDComboSlot] == nil {
+			combos.acrescimos[a.IDComboSlot] = make(map[uuid.UUID]int64)
+		}
+		valor, _ := decimalutils.NumericToCentavos(a.Valor)
+		combos.acrescimos[a.IDComboSlot][a.IDProduto] = valor
+	}
+	return combos, nil
+}
+
+func (ps *PrecificacaoService) carregarGruposAdicionais(ctx context.Context, in dto.PedidoCotacaoDTO) ([]pgstore.ListGruposAdicionaisCotacaoRow, error) {
+	ids := make([]uuid.UUID, 0, len(in.Itens))
+	for _, item := range in.Itens {
+		ids = append(ids, uuid.MustParse(item.IDCategoria))
+	}
+
+	return ps.queries.ListGruposAdicionaisCotacao(ctx, pgstore.ListGruposAdicionaisCotacaoParams{
+		TenantID:     in.TenantID,
+		CategoriaIds: ids,
+	})
+}
+
+func valorOuZero(d *types.Decimal) int64 {
+	if d == nil {
+		return 0
+	}
+	return decimalutils.ToCentavos(*d)
+}
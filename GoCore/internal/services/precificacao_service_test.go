@@ -0,0 +1,34 @@
+package services
+
+import "testing"
+
+func TestValorSabores(t *testing.T) {
+	tests := []struct {
+		nome      string
+		opcaoMeia string
+		sabores   []saborCotado
+		regra     string
+		want      int64
+	}{
+		{"média de dois", "M", []saborCotado{{4000, 1}, {5000, 1}}, "M", 4500},
+		{"média arredonda meio centavo para cima", "M", []saborCotado{{4000, 1}, {4001, 1}}, "M", 4001},
+		{"média de três com dízima", "M", []saborCotado{{3000, 1}, {3000, 1}, {3001, 1}}, "M", 3000},
+		{"média de quatro", "M", []saborCotado{{3000, 1}, {3500, 1}, {4000, 1}, {4500, 1}}, "M", 3750},
+		{"média ignora as frações", "M", []saborCotado{{3000, 3}, {6000, 1}}, "M", 4500},
+		{"ponderada 3/4 + 1/4", "P", []saborCotado{{4000, 3}, {8000, 1}}, "P", 5000},
+		{"ponderada arredonda meio centavo para cima", "P", []saborCotado{{1001, 1}, {1000, 1}}, "P", 1001},
+		{"ponderada de três", "P", []saborCotado{{3000, 2}, {4500, 1}, {6000, 1}}, "P", 4125},
+		{"ponderada com frações iguais é a média", "P", []saborCotado{{3000, 1}, {4000, 1}, {5000, 1}}, "P", 4000},
+		{"maior valor", "V", []saborCotado{{4000, 1}, {5500, 1}, {5000, 1}}, "V", 5500},
+		{"regra desconhecida cobra o maior", "X", []saborCotado{{4000, 1}, {3000, 1}}, "V", 4000},
+		{"um sabor só", "M", []saborCotado{{4290, 1}}, "M", 4290},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			regra, got := valorSabores(tt.opcaoMeia, tt.sabores)
+			if regra != tt.regra || got != tt.want {
+				t.Fatalf("valorSabores(%q) = %q, %d; want %q, %d", tt.opcaoMeia, regra, got, tt.regra, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/report"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrRelatorioCaixaNaoEncontrado = errors.New("caixa não encontrado")
+
+// RelatorioService monta os dados do comprovante do pedido e do fechamento
+// de caixa para o pacote report, que gera o PDF (antes era gerado pelo
+// serviço .NET/FastReport) e a impressão ESC/POS.
+type RelatorioService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewRelatorioService(pool *pgxpool.Pool) RelatorioService {
+	return RelatorioService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// PedidoPDF gera o comprovante do pedido no layout informado
+func (rs *RelatorioService) PedidoPDF(ctx context.Context, tenantID, idPedido uuid.UUID, layout report.Layout) ([]byte, error) {
+	pedido, err := rs.CarregarPedido(ctx, tenantID, idPedido)
+	if err != nil {
+		return nil, err
+	}
+	return report.RenderPedido(pedido, layout)
+}
+
+// CarregarPedido lê o pedido com itens, adicionais, pagamentos, cliente e
+// estabelecimento. A data vem no fuso do tenant.
+func (rs *RelatorioService) CarregarPedido(ctx context.Context, tenantID, idPedido uuid.UUID) (report.Pedido, error) {
+	p, err := rs.queries.GetRelatorioPedido(ctx, pgstore.GetRelatorioPedidoParams{
+		ID:       idPedido,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return report.Pedido{}, ErrPedidoNaoEncontrado
+		}
+		return report.Pedido{}, err
+	}
+
+	itens, err := rs.queries.ListRelatorioItens(ctx, idPedido)
+	if err != nil {
+		return report.Pedido{}, err
+	}
+	sabores, err := rs.queries.ListRelatorioSabores(ctx, idPedido)
+	if err != nil {
+		return report.Pedido{}, err
+	}
+	adicionais, err := rs.queries.ListRelatorioAdicionais(ctx, idPedido)
+	if err != nil {
+		return report.Pedido{}, err
+	}
+	pagamentos, err := rs.queries.ListRelatorioPagamentos(ctx, idPedido)
+	if err != nil {
+		return report.Pedido{}, err
+	}
+
+	loc, err := time.LoadLocation(p.TenantTimezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(timezonePadrao)
+	}
+
+	out := report.Pedido{
+		Estabelecimento: report.Estabelecimento{
+			Nome:     p.TenantNome,
+			Endereco: p.TenantEndereco.String,
+			Bairro:   p.TenantBairro.String,
+			Cidade:   p.TenantCidade.String,
+			Telefone: p.TenantTelefone.String,
+		},
+		Cliente: report.Cliente{
+			Nome:        p.ClienteNome,
+			Telefone:    p.ClienteTelefone.String,
+			Celular:     p.ClienteCelular.String,
+			Cep:         p.ClienteCep.String,
+			Logradouro:  p.ClienteLogradouro.String,
+			Numero:      p.ClienteNumero.String,
+			Complemento: p.ClienteComplemento.String,
+			Bairro:      p.ClienteBairro.String,
+			Cidade:      p.ClienteCidade.String,
+			Uf:          p.ClienteUf.String,
+		},
+		Codigo:          p.CodigoPedido,
+		Data:            p.DataPedido.In(loc),
+		TipoEntrega:     p.TipoEntrega,
+		Status:          p.StatusDescricao,
+		Observacao:      p.Observacao.String,
+		Subtotal:        centavos(p.ValorTotal),
+		TaxaEntrega:     centavos(p.TaxaEntrega),
+		NomeTaxaEntrega: p.NomeTaxaEntrega.String,
+		Desconto:        centavos(p.Desconto),
+		Acrescimo:       centavos(p.Acrescimo),
+		TrocoPara:       centavos(p.TrocoPara),
+		ValorPago:       centavos(p.ValorPago),
+	}
+	// mesma conta dos triggers de pagamento
+	out.Total = out.Subtotal + out.TaxaEntrega + out.Acrescimo - out.Desconto
+
+	porItem := make(map[uuid.UUID][]report.Adicional, len(itens))
+	for _, a := range adicionais {
+		porItem[a.IDPedidoItem] = append(porItem[a.IDPedidoItem], report.Adicional{
+			Nome:       a.Nome,
+			Quantidade: a.Quantidade,
+			Valor:      centavos(a.Valor),
+		})
+	}
+
+	saboresPorItem := make(map[uuid.UUID][]report.Sabor, len(itens))
+	for _, sb := range sabores {
+		saboresPorItem[sb.IDPedidoItem] = append(saboresPorItem[sb.IDPedidoItem], report.Sabor{
+			Nome:   sb.Nome,
+			Partes: int(sb.Partes),
+		})
+	}
+
+	out.Itens = make([]report.Item, len(itens))
+	for i, it := range itens {
+		out.Itens[i] = report.Item{
+			Quantidade:    it.Quantidade,
+			Categoria:     it.CategoriaNome,
+			Produto:       it.ProdutoNome,
+			Produto2:      it.Produto2Nome.String,
+			Sabores:       saboresPorItem[it.ID],
+			Opcao:         it.OpcaoNome.String,
+			ValorUnitario: centavos(it.ValorUnitario),
+			Observacao:    it.Observacao.String,
+			Adicionais:    porItem[it.ID],
+			Componente:    it.Componente,
+		}
+	}
+
+	out.Pagamentos = make([]report.Pagamento, len(pagamentos))
+	for i, pg := range pagamentos {
+		out.Pagamentos[i] = report.Pagamento{
+			Forma:     pg.FormaPagamento,
+			Categoria: pg.CategoriaPagamento.String,
+			Valor:     centavos(pg.ValorPago),
+			Troco:     centavos(pg.Troco),
+			Data:      pg.CreatedAt.In(loc),
+		}
+		out.Troco += out.Pagamentos[i].Troco
+	}
+	// sem pagamento registrado o troco é o previsto pelo "troco para"
+	if out.Troco == 0 && out.TrocoPara > out.Total {
+		out.Troco = out.TrocoPara - out.Total
+	}
+
+	return out, nil
+}
+
+// CarregarCaixa lê o caixa com os valores esperados e informados por forma
+// de pagamento e os totais de sangria, suprimento e estorno.
+func (rs *RelatorioService) CarregarCaixa(ctx context.Context, tenantID, idCaixa uuid.UUID) (report.Caixa, error) {
+	c, err := rs.queries.GetRelatorioCaixa(ctx, pgstore.GetRelatorioCaixaParams{
+		ID:       idCaixa,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return report.Caixa{}, ErrRelatorioCaixaNaoEncontrado
+		}
+		return report.Caixa{}, err
+	}
+
+	formas, err := rs.queries.ListRelatorioCaixaFormas(ctx, idCaixa)
+	if err != nil {
+		return report.Caixa{}, err
+	}
+	movimentacoes, err := rs.queries.ListRelatorioCaixaMovimentacoes(ctx, idCaixa)
+	if err != nil {
+		return report.Caixa{}, err
+	}
+
+	loc, err := time.LoadLocation(c.TenantTimezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(timezonePadrao)
+	}
+
+	out := report.Caixa{
+		Estabelecimento: c.TenantNome,
+		Numero:          c.SeqID,
+		Operador:        c.OperadorNome,
+		Abertura:        c.DataAbertura.In(loc),
+		Fechado:         c.Status == pgstore.StatusCaixaF,
+		Observacao:      c.ObservacaoFechamento.String,
+		ValorAbertura:   centavos(c.ValorAbertura),
+		Formas:          make([]report.CaixaForma, len(formas)),
+	}
+	if c.DataFechamento.Valid {
+		out.Fechamento = c.DataFechamento.Time.In(loc)
+	}
+
+	for i, f := range formas {
+		out.Formas[i] = report.CaixaForma{
+			Nome:      f.NomeForma,
+			Esperado:  centavos(f.ValorEsperado),
+			Informado: centavos(f.ValorInformado),
+			Informou:  f.ValorInformado.Valid,
+		}
+	}
+
+	for _, m := range movimentacoes {
+		switch m.Tipo {
+		case "U":
+			out.Suprimentos, out.QtdSuprimentos = centavos(m.Total), m.Quantidade
+		case "S":
+			out.Sangrias, out.QtdSangrias = centavos(m.Total), m.Quantidade
+		case "E":
+			out.Estornos, out.QtdEstornos = centavos(m.Total), m.Quantidade
+		}
+	}
+
+	return out, nil
+}
+
+func centavos(n pgtype.Numeric) int64 {
+	c, _ := decimalutils.NumericToCentavos(n)
+	return c
+}
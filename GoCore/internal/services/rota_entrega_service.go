@@ -0,0 +1,760 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/geoutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRotaEntregaNaoEncontrada = errors.New("rota de entrega não encontrada")
+	ErrPedidoForaDaRota         = errors.New("pedido não está nesta rota")
+	ErrRotaSemPedidos           = errors.New("rota não tem pedidos")
+	ErrRotaSemCaixaAberto       = errors.New("acerto da rota exige um caixa aberto")
+	ErrOrigemNaoConfigurada     = errors.New("informe as coordenadas da loja em /zonas-entrega/origem")
+)
+
+// RotaEntregaInvalidaError aponta pedidos que não podem entrar na rota
+type RotaEntregaInvalidaError struct {
+	Mensagem string
+}
+
+func (e *RotaEntregaInvalidaError) Error() string { return e.Mensagem }
+
+// RotaEntregaStatusError informa uma ação que o status atual da rota (ou do
+// pedido na rota) não permite.
+type RotaEntregaStatusError struct {
+	Mensagem string
+}
+
+func (e *RotaEntregaStatusError) Error() string { return e.Mensagem }
+
+// RotaEntregaService controla as saídas do entregador:
+//
+//	Aberta → (saída) Em rota → (retorno) Retornou → acerto
+//	Aberta → Cancelada
+//
+// Na saída os pedidos vão para "Saiu para entrega" e cada entrega
+// confirmada conclui o pedido. No acerto o dinheiro recebido vira pagamento
+// do pedido e as taxas do entregador saem do caixa aberto como sangria.
+type RotaEntregaService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewRotaEntregaService(pool *pgxpool.Pool) RotaEntregaService {
+	return RotaEntregaService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// EntregadorDoUsuario devolve o entregador vinculado ao usuário do app
+func (rs *RotaEntregaService) EntregadorDoUsuario(ctx context.Context, userID uuid.UUID) (pgstore.Entregadore, error) {
+	e, err := rs.queries.GetEntregadorPorUsuario(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Entregadore{}, ErrUsuarioNaoEntregador
+		}
+		return pgstore.Entregadore{}, err
+	}
+	return e, nil
+}
+
+func (rs *RotaEntregaService) List(ctx context.Context, tenantID uuid.UUID, status *string, idEntregador *uuid.UUID, limit, offset int32) ([]dto.RotaEntregaResponse, error) {
+	params := pgstore.ListRotasEntregaParams{
+		TenantID: tenantID,
+		Status:   toPgTypeText(status),
+		Lim:      limit,
+		Off:      offset,
+	}
+	if idEntregador != nil {
+		params.IDEntregador = pgtype.UUID{Bytes: *idEntregador, Valid: true}
+	}
+	rows, err := rs.queries.ListRotasEntrega(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	rotas := make([]pgstore.GetRotaEntregaRow, len(rows))
+	for i, r := range rows {
+		rotas[i] = pgstore.GetRotaEntregaRow(r)
+	}
+	return rs.responses(ctx, rotas)
+}
+
+// ListEntregador lista as rotas abertas e em andamento do entregador
+func (rs *RotaEntregaService) ListEntregador(ctx context.Context, idEntregador uuid.UUID) ([]dto.RotaEntregaResponse, error) {
+	rows, err := rs.queries.ListRotasEntregaEntregador(ctx, idEntregador)
+	if err != nil {
+		return nil, err
+	}
+	rotas := make([]pgstore.GetRotaEntregaRow, len(rows))
+	for i, r := range rows {
+		rotas[i] = pgstore.GetRotaEntregaRow(r)
+	}
+	return rs.responses(ctx, rotas)
+}
+
+func (rs *RotaEntregaService) Get(ctx context.Context, ref dto.RotaEntregaRef) (dto.RotaEntregaResponse, error) {
+	return rs.get(ctx, rs.queries, ref)
+}
+
+// Create monta a rota do entregador com os pedidos informados
+func (rs *RotaEntregaService) Create(ctx context.Context, in dto.RotaEntregaCreateDTO) (dto.RotaEntregaResponse, error) {
+	idEntregador, err := uuid.Parse(in.IDEntregador)
+	if err != nil {
+		return dto.RotaEntregaResponse{}, ErrEntregadorNaoEncontrado
+	}
+
+	tx, err := rs.pool.Begin(ctx)
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := rs.queries.WithTx(tx)
+
+	entregador, err := q.GetEntregador(ctx, pgstore.GetEntregadorParams{ID: idEntregador, TenantID: in.TenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.RotaEntregaResponse{}, ErrEntregadorNaoEncontrado
+		}
+		return dto.RotaEntregaResponse{}, err
+	}
+	if !entregador.Ativo {
+		return dto.RotaEntregaResponse{}, &RotaEntregaInvalidaError{Mensagem: "entregador inativo"}
+	}
+
+	id, err := q.CreateRotaEntrega(ctx, pgstore.CreateRotaEntregaParams{
+		TenantID:     in.TenantID,
+		IDEntregador: idEntregador,
+		Observacao:   toPgTypeText(in.Observacao),
+		CriadoPor:    pgtype.UUID{Bytes: in.UserID, Valid: in.UserID != uuid.Nil},
+	})
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	if err := incluirPedidosRota(ctx, q, in.TenantID, id, entregador.TaxaPorEntrega, in.Pedidos); err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+
+	out, err := rs.get(ctx, q, dto.RotaEntregaRef{TenantID: in.TenantID, ID: id})
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	return out, nil
+}
+
+// AdicionarPedidos inclui pedidos em uma rota que ainda não saiu
+func (rs *RotaEntregaService) AdicionarPedidos(ctx context.Context, ref dto.RotaEntregaRef, pedidos []string) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta {
+			return &RotaEntregaStatusError{Mensagem: "só é possível incluir pedidos antes da saída"}
+		}
+		entregador, err := q.GetEntregador(ctx, pgstore.GetEntregadorParams{ID: rota.IDEntregador, TenantID: ref.TenantID})
+		if err != nil {
+			return err
+		}
+		return incluirPedidosRota(ctx, q, ref.TenantID, rota.ID, entregador.TaxaPorEntrega, pedidos)
+	})
+}
+
+// RemoverPedido tira um pedido de uma rota que ainda não saiu
+func (rs *RotaEntregaService) RemoverPedido(ctx context.Context, ref dto.RotaEntregaRef, idPedido uuid.UUID) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta {
+			return &RotaEntregaStatusError{Mensagem: "só é possível remover pedidos antes da saída"}
+		}
+		n, err := q.DeleteRotaEntregaPedido(ctx, pgstore.DeleteRotaEntregaPedidoParams{IDRota: rota.ID, IDPedido: idPedido})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrPedidoForaDaRota
+		}
+		return nil
+	})
+}
+
+// Saida registra a saída do entregador, passa os pedidos para "Saiu para
+// entrega" e calcula o dinheiro a cobrar de cada cliente.
+func (rs *RotaEntregaService) Saida(ctx context.Context, ref dto.RotaEntregaRef) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta {
+			return &RotaEntregaStatusError{Mensagem: "rota já saiu ou foi cancelada"}
+		}
+		pedidos, err := q.ListPedidosRotaForUpdate(ctx, rota.ID)
+		if err != nil {
+			return err
+		}
+		if len(pedidos) == 0 {
+			return ErrRotaSemPedidos
+		}
+		for _, p := range pedidos {
+			if p.IDStatus == dto.PedidoStatusSaiuEntrega {
+				continue
+			}
+			if _, err := registrarTransicaoStatus(ctx, q, dto.AlterarStatusPedidoDTO{
+				TenantID: ref.TenantID,
+				UserID:   ref.UserID,
+				IDPedido: p.IDPedido,
+				IDStatus: dto.PedidoStatusSaiuEntrega,
+			}, p.IDStatus); err != nil {
+				return fmt.Errorf("pedido %s: %w", p.CodigoPedido, err)
+			}
+		}
+		if err := q.CalcularValorReceberRota(ctx, rota.ID); err != nil {
+			return err
+		}
+		return q.SairRotaEntrega(ctx, rota.ID)
+	})
+}
+
+// Entregue confirma a entrega do pedido. O dinheiro recebido só vira
+// pagamento no acerto da rota; até lá o pedido com valor recebido continua
+// em "Saiu para entrega" e é concluído no acerto. Sem dinheiro a registrar,
+// é concluído aqui.
+func (rs *RotaEntregaService) Entregue(ctx context.Context, ref dto.RotaEntregaRef, idPedido uuid.UUID, in dto.EntregaRealizadaDTO) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		parada, err := rs.travarParada(ctx, q, rota, idPedido)
+		if err != nil {
+			return err
+		}
+
+		receber, _ := decimalutils.NumericToCentavos(parada.ValorReceber)
+		recebido := receber
+		if in.ValorRecebido != nil {
+			recebido = decimalutils.ToCentavos(*in.ValorRecebido)
+		}
+		if recebido < 0 || recebido > receber {
+			return &RotaEntregaInvalidaError{Mensagem: fmt.Sprintf(
+				"valor_recebido deve estar entre 0 e %s", decimalutils.FromCentavos(receber).String())}
+		}
+
+		if recebido == 0 && parada.IDStatus != dto.PedidoStatusConcluido {
+			if _, err := registrarTransicaoStatus(ctx, q, dto.AlterarStatusPedidoDTO{
+				TenantID: ref.TenantID,
+				UserID:   ref.UserID,
+				IDPedido: idPedido,
+				IDStatus: dto.PedidoStatusConcluido,
+			}, parada.IDStatus); err != nil {
+				return err
+			}
+		}
+		return q.MarcarEntregaRota(ctx, pgstore.MarcarEntregaRotaParams{
+			Status:        dto.EntregaRealizada,
+			ValorRecebido: decimalutils.CentavosToNumeric(recebido),
+			Observacao:    toPgTypeText(in.Observacao),
+			ID:            parada.ID,
+		})
+	})
+}
+
+// NaoEntregue registra a entrega frustrada. O pedido continua em "Saiu para
+// entrega" e pode ir em outra rota ou ser cancelado.
+func (rs *RotaEntregaService) NaoEntregue(ctx context.Context, ref dto.RotaEntregaRef, idPedido uuid.UUID, in dto.EntregaNaoRealizadaDTO) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		parada, err := rs.travarParada(ctx, q, rota, idPedido)
+		if err != nil {
+			return err
+		}
+		motivo := in.Motivo
+		return q.MarcarEntregaRota(ctx, pgstore.MarcarEntregaRotaParams{
+			Status:        dto.EntregaNaoRealizada,
+			ValorRecebido: decimalutils.CentavosToNumeric(0),
+			Observacao:    toPgTypeText(&motivo),
+			ID:            parada.ID,
+		})
+	})
+}
+
+// Retorno fecha a rota; os pedidos ainda pendentes ficam como não entregues
+func (rs *RotaEntregaService) Retorno(ctx context.Context, ref dto.RotaEntregaRef) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaEmRota {
+			return &RotaEntregaStatusError{Mensagem: "rota não está em andamento"}
+		}
+		if err := q.MarcarPendentesNaoEntregues(ctx, rota.ID); err != nil {
+			return err
+		}
+		return q.RetornarRotaEntrega(ctx, rota.ID)
+	})
+}
+
+// Cancelar desfaz uma rota que ainda não saiu, liberando os pedidos
+func (rs *RotaEntregaService) Cancelar(ctx context.Context, ref dto.RotaEntregaRef) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta {
+			return &RotaEntregaStatusError{Mensagem: "só é possível cancelar a rota antes da saída"}
+		}
+		if err := q.DeleteRotaEntregaPedidos(ctx, rota.ID); err != nil {
+			return err
+		}
+		return q.CancelarRotaEntrega(ctx, rota.ID)
+	})
+}
+
+// Acerto fecha as contas da rota com o caixa aberto: cada valor recebido
+// vira pagamento em dinheiro do pedido (entra no caixa pelo trigger de
+// pedido_pagamentos), limitado ao saldo do pedido agora, e o pedido é
+// concluído; o que passar do saldo fica como excedente da parada. A soma
+// das taxas do entregador sai como sangria. valor_entregue é obrigatório
+// quando houve dinheiro recebido e, se diferir do recebido menos as taxas,
+// exige observação.
+func (rs *RotaEntregaService) Acerto(ctx context.Context, ref dto.RotaEntregaRef, in dto.RotaEntregaAcertoDTO) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaRetornou {
+			return &RotaEntregaStatusError{Mensagem: "acerto só depois do retorno da rota"}
+		}
+		if rota.AcertadoEm.Valid {
+			return &RotaEntregaStatusError{Mensagem: "rota já acertada"}
+		}
+		if in.ValorEntregue != nil && decimalutils.ToCentavos(*in.ValorEntregue) < 0 {
+			return &RotaEntregaInvalidaError{Mensagem: "valor_entregue não pode ser negativo"}
+		}
+
+		totais, err := q.GetTotaisRotaEntrega(ctx, rota.ID)
+		if err != nil {
+			return err
+		}
+		if err := conferirValorEntregue(totais, in); err != nil {
+			return err
+		}
+
+		idCaixa, err := q.GetCaixaAtivo(ctx, ref.TenantID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRotaSemCaixaAberto
+			}
+			return err
+		}
+		autorizadoPor := pgtype.UUID{Bytes: ref.UserID, Valid: ref.UserID != uuid.Nil}
+
+		recebimentos, err := q.ListRecebimentosRota(ctx, rota.ID)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recebimentos {
+			if err := rs.registrarRecebimento(ctx, q, ref, rota, rec, autorizadoPor); err != nil {
+				return fmt.Errorf("pedido %s: %w", rec.CodigoPedido, err)
+			}
+		}
+
+		var idMovimentacao pgtype.UUID
+		if taxas, _ := decimalutils.NumericToCentavos(totais.ValorTaxas); taxas > 0 {
+			mov, err := q.SangriaCaixa(ctx, pgstore.SangriaCaixaParams{
+				IDCaixa:       idCaixa,
+				Valor:         totais.ValorTaxas,
+				Observacao:    pgtype.Text{String: fmt.Sprintf("Taxas de entrega - rota %d", rota.SeqID), Valid: true},
+				AutorizadoPor: autorizadoPor,
+			})
+			if err != nil {
+				return err
+			}
+			idMovimentacao = pgtype.UUID{Bytes: mov.ID, Valid: true}
+		}
+
+		params := pgstore.AcertarRotaEntregaParams{
+			ID:                  rota.ID,
+			ValorTaxas:          totais.ValorTaxas,
+			ValorRecebido:       totais.ValorRecebido,
+			IDCaixa:             pgtype.UUID{Bytes: idCaixa, Valid: true},
+			IDMovimentacaoTaxas: idMovimentacao,
+			AcertadoPor:         autorizadoPor,
+			ObservacaoAcerto:    toPgTypeText(in.Observacao),
+		}
+		if in.ValorEntregue != nil {
+			params.ValorEntregue = decimalutils.CentavosToNumeric(decimalutils.ToCentavos(*in.ValorEntregue))
+		}
+		return q.AcertarRotaEntrega(ctx, params)
+	})
+}
+
+// registrarRecebimento grava o pagamento do dinheiro recebido na entrega até
+// o saldo do pedido (outro pagamento pode ter entrado depois da saída) e
+// conclui o pedido. Pedido cancelado não recebe pagamento: tudo é excedente.
+func (rs *RotaEntregaService) registrarRecebimento(ctx context.Context, q *pgstore.Queries, ref dto.RotaEntregaRef,
+	rota pgstore.GetRotaEntregaForUpdateRow, rec pgstore.ListRecebimentosRotaRow, autorizadoPor pgtype.UUID) error {
+
+	recebido, _ := decimalutils.NumericToCentavos(rec.ValorRecebido)
+	saldo, _ := decimalutils.NumericToCentavos(rec.Saldo)
+	if rec.IDStatus == dto.PedidoStatusCancelado {
+		saldo = 0
+	}
+	valor := min(recebido, max(saldo, 0))
+
+	var idPagamento pgtype.UUID
+	if valor > 0 {
+		obs := fmt.Sprintf("Recebido na entrega - rota %d", rota.SeqID)
+		id, err := q.InsertPagamentoEntrega(ctx, pgstore.InsertPagamentoEntregaParams{
+			IDPedido:      rec.IDPedido,
+			ValorPago:     decimalutils.CentavosToNumeric(valor),
+			AutorizadoPor: autorizadoPor,
+			Observacao:    pgtype.Text{String: obs, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		idPagamento = pgtype.UUID{Bytes: id, Valid: true}
+
+		if err := criarEventoOutbox(ctx, q, ref.TenantID, ref.UserID,
+			dto.OutboxAggregatePagamento, id.String(), dto.EventPagamentoRegistered,
+			dto.PagamentoEventPayload{
+				ID:             id.String(),
+				IDPedido:       rec.IDPedido.String(),
+				FormaPagamento: "Dinheiro",
+				ValorPago:      decimalutils.FromCentavos(valor),
+			}); err != nil {
+			return err
+		}
+	}
+	if err := q.SetPagamentoRotaEntregaPedido(ctx, pgstore.SetPagamentoRotaEntregaPedidoParams{
+		ID:             rec.ID,
+		IDPagamento:    idPagamento,
+		ValorExcedente: decimalutils.CentavosToNumeric(recebido - valor),
+	}); err != nil {
+		return err
+	}
+
+	if rec.IDStatus != dto.PedidoStatusSaiuEntrega {
+		return nil
+	}
+	_, err := registrarTransicaoStatus(ctx, q, dto.AlterarStatusPedidoDTO{
+		TenantID: ref.TenantID,
+		UserID:   ref.UserID,
+		IDPedido: rec.IDPedido,
+		IDStatus: dto.PedidoStatusConcluido,
+	}, rec.IDStatus)
+	return err
+}
+
+// conferirValorEntregue compara o dinheiro devolvido pelo entregador com o
+// recebido dos clientes menos as taxas que ele retém
+func conferirValorEntregue(totais pgstore.GetTotaisRotaEntregaRow, in dto.RotaEntregaAcertoDTO) error {
+	recebido, _ := decimalutils.NumericToCentavos(totais.ValorRecebido)
+	taxas, _ := decimalutils.NumericToCentavos(totais.ValorTaxas)
+	if in.ValorEntregue == nil {
+		if recebido > 0 {
+			return &RotaEntregaInvalidaError{Mensagem: "informe valor_entregue para conferir o dinheiro recebido"}
+		}
+		return nil
+	}
+
+	esperado := recebido - taxas
+	if decimalutils.ToCentavos(*in.ValorEntregue) != esperado && (in.Observacao == nil || strings.TrimSpace(*in.Observacao) == "") {
+		return &RotaEntregaInvalidaError{Mensagem: fmt.Sprintf(
+			"valor_entregue difere do esperado (%s); informe a observação do acerto",
+			decimalutils.FromCentavos(esperado).String())}
+	}
+	return nil
+}
+
+// Planejar sugere rotas para os pedidos Delivery fora de rota. Nada é
+// gravado: a tela de despacho cria cada rota aceita (ou editada) com
+// POST /rotas-entrega, na ordem sugerida.
+func (rs *RotaEntregaService) Planejar(ctx context.Context, tenantID uuid.UUID, in dto.PlanejamentoEntregaDTO) (dto.PlanejamentoEntregaResponse, error) {
+	origem, err := origemDaLoja(ctx, rs.queries, tenantID)
+	if err != nil {
+		return dto.PlanejamentoEntregaResponse{}, err
+	}
+
+	params := pgstore.ListPedidosParaPlanejamentoParams{
+		TenantID:  tenantID,
+		StatusIds: []int16{dto.PedidoStatusPronto},
+	}
+	if len(in.Pedidos) > 0 {
+		params.PedidoIds = uuidsDistintos(in.Pedidos)
+		params.StatusIds = []int16{dto.PedidoStatusConfirmado, dto.PedidoStatusEmPreparacao, dto.PedidoStatusPronto}
+	}
+	rows, err := rs.queries.ListPedidosParaPlanejamento(ctx, params)
+	if err != nil {
+		return dto.PlanejamentoEntregaResponse{}, err
+	}
+	if params.PedidoIds != nil && len(rows) != len(params.PedidoIds) {
+		return dto.PlanejamentoEntregaResponse{}, &RotaEntregaInvalidaError{
+			Mensagem: "pedido não encontrado, já em rota, concluído ou não é Delivery"}
+	}
+
+	out := dto.PlanejamentoEntregaResponse{
+		Origem:         origem,
+		Rotas:          []dto.RotaPlanejadaResponse{},
+		SemCoordenadas: []dto.PedidoSemCoordenadasResponse{},
+	}
+	var paradas []paradaRota
+	for _, r := range rows {
+		ponto := pontoDeNumeric(r.Lat, r.Lng)
+		if ponto == nil {
+			out.SemCoordenadas = append(out.SemCoordenadas, dto.PedidoSemCoordenadasResponse{
+				IDPedido:     r.ID,
+				CodigoPedido: r.CodigoPedido,
+				ClienteNome:  r.ClienteNome,
+			})
+			continue
+		}
+		paradas = append(paradas, paradaRota{
+			idPedido:    r.ID,
+			codigo:      r.CodigoPedido,
+			clienteNome: r.ClienteNome,
+			bairro:      r.Bairro,
+			ponto:       *ponto,
+			prazo:       prazoDoPedido(r.DataPedido, r.PrazoMax, r.AgendadoPara),
+		})
+	}
+
+	p := novosParametrosRota(origem, in.ParametrosRotaDTO)
+	out.SaidaEm = p.saida
+	maxPorRota, raioKm := maxPedidosPorRotaPadrao, raioAgrupamentoPadraoKm
+	if in.MaxPedidosPorRota != nil {
+		maxPorRota = int(*in.MaxPedidosPorRota)
+	}
+	if in.RaioAgrupamentoKm != nil {
+		raioKm = *in.RaioAgrupamentoKm
+	}
+	for _, grupo := range p.agrupar(paradas, maxPorRota, raioKm) {
+		seq, aval := p.sequenciar(grupo)
+		out.Rotas = append(out.Rotas, p.rotaPlanejadaResponse(seq, aval))
+	}
+	return out, nil
+}
+
+// Otimizar reordena as paradas de uma rota que ainda não saiu. Pedidos sem
+// coordenadas vão para o fim, na ordem em que estavam.
+func (rs *RotaEntregaService) Otimizar(ctx context.Context, ref dto.RotaEntregaRef, in dto.ParametrosRotaDTO) (dto.RotaOtimizadaResponse, error) {
+	var plano dto.RotaPlanejadaResponse
+	rota, err := rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta {
+			return &RotaEntregaStatusError{Mensagem: "só é possível otimizar a rota antes da saída"}
+		}
+		origem, err := origemDaLoja(ctx, q, ref.TenantID)
+		if err != nil {
+			return err
+		}
+		pedidos, err := q.ListRotaEntregaPedidos(ctx, []uuid.UUID{rota.ID})
+		if err != nil {
+			return err
+		}
+		if len(pedidos) == 0 {
+			return ErrRotaSemPedidos
+		}
+
+		var paradas []paradaRota
+		var semCoordenadas []uuid.UUID
+		for _, r := range pedidos {
+			ponto := pontoDeNumeric(r.Lat, r.Lng)
+			if ponto == nil {
+				semCoordenadas = append(semCoordenadas, r.IDPedido)
+				continue
+			}
+			paradas = append(paradas, paradaRota{
+				idPedido:    r.IDPedido,
+				codigo:      r.CodigoPedido,
+				clienteNome: r.ClienteNome,
+				bairro:      r.Bairro,
+				ponto:       *ponto,
+				prazo:       prazoDoPedido(r.DataPedido, r.PrazoMax, r.AgendadoPara),
+			})
+		}
+
+		p := novosParametrosRota(origem, in)
+		seq, aval := p.sequenciar(paradas)
+		plano = p.rotaPlanejadaResponse(seq, aval)
+
+		_, err = q.UpdateOrdemRotaEntregaPedidos(ctx, pgstore.UpdateOrdemRotaEntregaPedidosParams{
+			PedidoIds: append(slices.Clone(plano.Pedidos), semCoordenadas...),
+			IDRota:    rota.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return dto.RotaOtimizadaResponse{}, err
+	}
+	return dto.RotaOtimizadaResponse{Rota: rota, Plano: plano}, nil
+}
+
+// Reordenar grava a ordem editada na tela de despacho ou no app. A lista
+// precisa ter todos os pedidos da rota.
+func (rs *RotaEntregaService) Reordenar(ctx context.Context, ref dto.RotaEntregaRef, pedidos []string) (dto.RotaEntregaResponse, error) {
+	return rs.emTx(ctx, ref, func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error {
+		if rota.Status != dto.RotaAberta && rota.Status != dto.RotaEmRota {
+			return &RotaEntregaStatusError{Mensagem: "rota já retornou ou foi cancelada"}
+		}
+		atuais, err := q.ListRotaEntregaPedidos(ctx, []uuid.UUID{rota.ID})
+		if err != nil {
+			return err
+		}
+		ids := uuidsDistintos(pedidos)
+		if len(ids) != len(pedidos) || len(ids) != len(atuais) {
+			return &RotaEntregaInvalidaError{Mensagem: "informe todos os pedidos da rota, sem repetir"}
+		}
+		n, err := q.UpdateOrdemRotaEntregaPedidos(ctx, pgstore.UpdateOrdemRotaEntregaPedidosParams{
+			PedidoIds: ids,
+			IDRota:    rota.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if int(n) != len(atuais) {
+			return ErrPedidoForaDaRota
+		}
+		return nil
+	})
+}
+
+// emTx trava a rota, aplica a ação e devolve a rota atualizada
+func (rs *RotaEntregaService) emTx(ctx context.Context, ref dto.RotaEntregaRef,
+	acao func(q *pgstore.Queries, rota pgstore.GetRotaEntregaForUpdateRow) error) (dto.RotaEntregaResponse, error) {
+
+	tx, err := rs.pool.Begin(ctx)
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := rs.queries.WithTx(tx)
+
+	rota, err := q.GetRotaEntregaForUpdate(ctx, pgstore.GetRotaEntregaForUpdateParams{ID: ref.ID, TenantID: ref.TenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.RotaEntregaResponse{}, ErrRotaEntregaNaoEncontrada
+		}
+		return dto.RotaEntregaResponse{}, err
+	}
+	if ref.IDEntregador != uuid.Nil && rota.IDEntregador != ref.IDEntregador {
+		return dto.RotaEntregaResponse{}, ErrRotaEntregaNaoEncontrada
+	}
+
+	if err := acao(q, rota); err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+
+	out, err := rs.get(ctx, q, ref)
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	return out, nil
+}
+
+// travarParada trava o pedido da rota em andamento que ainda está pendente
+func (rs *RotaEntregaService) travarParada(ctx context.Context, q *pgstore.Queries,
+	rota pgstore.GetRotaEntregaForUpdateRow, idPedido uuid.UUID) (pgstore.GetRotaEntregaPedidoForUpdateRow, error) {
+
+	if rota.Status != dto.RotaEmRota {
+		return pgstore.GetRotaEntregaPedidoForUpdateRow{}, &RotaEntregaStatusError{Mensagem: "rota não está em andamento"}
+	}
+	parada, err := q.GetRotaEntregaPedidoForUpdate(ctx, pgstore.GetRotaEntregaPedidoForUpdateParams{
+		IDRota:   rota.ID,
+		IDPedido: idPedido,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.GetRotaEntregaPedidoForUpdateRow{}, ErrPedidoForaDaRota
+		}
+		return pgstore.GetRotaEntregaPedidoForUpdateRow{}, err
+	}
+	if parada.Status != dto.EntregaPendente {
+		return pgstore.GetRotaEntregaPedidoForUpdateRow{}, &RotaEntregaStatusError{Mensagem: "entrega do pedido já registrada"}
+	}
+	return parada, nil
+}
+
+func (rs *RotaEntregaService) get(ctx context.Context, q *pgstore.Queries, ref dto.RotaEntregaRef) (dto.RotaEntregaResponse, error) {
+	rota, err := q.GetRotaEntrega(ctx, pgstore.GetRotaEntregaParams{ID: ref.ID, TenantID: ref.TenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.RotaEntregaResponse{}, ErrRotaEntregaNaoEncontrada
+		}
+		return dto.RotaEntregaResponse{}, err
+	}
+	if ref.IDEntregador != uuid.Nil && rota.IDEntregador != ref.IDEntregador {
+		return dto.RotaEntregaResponse{}, ErrRotaEntregaNaoEncontrada
+	}
+	pedidos, err := q.ListRotaEntregaPedidos(ctx, []uuid.UUID{rota.ID})
+	if err != nil {
+		return dto.RotaEntregaResponse{}, err
+	}
+	return dto.RotaEntregaToResponse(rota, pedidos), nil
+}
+
+func (rs *RotaEntregaService) responses(ctx context.Context, rotas []pgstore.GetRotaEntregaRow) ([]dto.RotaEntregaResponse, error) {
+	out := make([]dto.RotaEntregaResponse, len(rotas))
+	if len(rotas) == 0 {
+		return out, nil
+	}
+	ids := make([]uuid.UUID, len(rotas))
+	for i, r := range rotas {
+		ids[i] = r.ID
+	}
+	pedidos, err := rs.queries.ListRotaEntregaPedidos(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range rotas {
+		out[i] = dto.RotaEntregaToResponse(r, pedidos)
+	}
+	return out, nil
+}
+
+func origemDaLoja(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID) (geoutils.Ponto, error) {
+	row, err := q.GetTenantOrigem(ctx, tenantID)
+	if err != nil {
+		return geoutils.Ponto{}, err
+	}
+	origem := pontoDeNumeric(row.Lat, row.Lng)
+	if origem == nil {
+		return geoutils.Ponto{}, ErrOrigemNaoConfigurada
+	}
+	return *origem, nil
+}
+
+// incluirPedidosRota confere e grava os pedidos na rota com a taxa atual do
+// entregador. Só entram pedidos Delivery ainda não concluídos e fora de
+// outra rota.
+func incluirPedidosRota(ctx context.Context, q *pgstore.Queries, tenantID, idRota uuid.UUID,
+	taxa pgtype.Numeric, pedidos []string) error {
+
+	ids := uuidsDistintos(pedidos)
+	rows, err := q.ListPedidosParaRota(ctx, pgstore.ListPedidosParaRotaParams{TenantID: tenantID, PedidoIds: ids})
+	if err != nil {
+		return err
+	}
+	if len(rows) != len(ids) {
+		return &RotaEntregaInvalidaError{Mensagem: "pedido não encontrado"}
+	}
+	for _, p := range rows {
+		switch {
+		case p.TipoEntrega != "Delivery":
+			return &RotaEntregaInvalidaError{Mensagem: fmt.Sprintf("pedido %s não é Delivery", p.CodigoPedido)}
+		case p.IDStatus == dto.PedidoStatusConcluido, p.IDStatus == dto.PedidoStatusCancelado:
+			return &RotaEntregaInvalidaError{Mensagem: fmt.Sprintf("pedido %s já está %s", p.CodigoPedido, descricoesStatus[p.IDStatus])}
+		case p.EmRota:
+			return &RotaEntregaInvalidaError{Mensagem: fmt.Sprintf("pedido %s já está em uma rota", p.CodigoPedido)}
+		}
+	}
+
+	ordem, err := q.MaxOrdemRotaEntrega(ctx, idRota)
+	if err != nil {
+		return err
+	}
+	return q.InsertRotaEntregaPedidos(ctx, pgstore.InsertRotaEntregaPedidosParams{
+		IDRota:       idRota,
+		OrdemInicial: ordem,
+		ValorTaxa:    taxa,
+		PedidoIds:    ids,
+	})
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/geoutils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Roteirização das entregas: agrupa pedidos em rotas e ordena as paradas
+// com vizinho mais próximo + 2-opt sobre a distância de haversine. O prazo
+// de cada pedido (data_pedido + prazo_max) pesa antes da distância: uma
+// ordem só é melhor se não atrasar mais pedidos.
+
+const (
+	velocidadeMediaPadraoKmh = 25.0
+	minutosPorParadaPadrao   = 3
+	maxPedidosPorRotaPadrao  = 4
+	raioAgrupamentoPadraoKm  = 3.0
+)
+
+type paradaRota struct {
+	idPedido    uuid.UUID
+	codigo      string
+	clienteNome string
+	bairro      pgtype.Text
+	ponto       geoutils.Ponto
+	prazo       *time.Time
+}
+
+type parametrosRota struct {
+	origem        geoutils.Ponto
+	saida         time.Time
+	kmPorMinuto   float64
+	minutosParada float64
+}
+
+type avaliacaoRota struct {
+	distanciaKm float64
+	atrasos     int
+	atrasoMin   float64
+	trechos     []float64
+	chegadas    []time.Time
+}
+
+func novosParametrosRota(origem geoutils.Ponto, in dto.ParametrosRotaDTO) parametrosRota {
+	p := parametrosRota{
+		origem:        origem,
+		saida:         time.Now(),
+		kmPorMinuto:   velocidadeMediaPadraoKmh / 60,
+		minutosParada: minutosPorParadaPadrao,
+	}
+	if in.SaidaEm != nil {
+		p.saida = *in.SaidaEm
+	}
+	if in.VelocidadeMediaKmh != nil {
+		p.kmPorMinuto = *in.VelocidadeMediaKmh / 60
+	}
+	if in.MinutosPorParada != nil {
+		p.minutosParada = float64(*in.MinutosPorParada)
+	}
+	return p
+}
+
+// prazoDoPedido: o horário marcado, no pedido agendado; senão data do
+// pedido + prazo_max em minutos
+func prazoDoPedido(dataPedido time.Time, prazoMax pgtype.Int4, agendadoPara pgtype.Timestamptz) *time.Time {
+	if agendadoPara.Valid {
+		return &agendadoPara.Time
+	}
+	if !prazoMax.Valid {
+		return nil
+	}
+	prazo := dataPedido.Add(time.Duration(prazoMax.Int32) * time.Minute)
+	return &prazo
+}
+
+// avaliar percorre a rota aberta (sem a volta à loja) a partir da saída
+func (p parametrosRota) avaliar(seq []paradaRota) avaliacaoRota {
+	a := avaliacaoRota{
+		trechos:  make([]float64, len(seq)),
+		chegadas: make([]time.Time, len(seq)),
+	}
+	atual, minutos := p.origem, 0.0
+	for i, s := range seq {
+		if i > 0 {
+			minutos += p.minutosParada
+		}
+		d := geoutils.DistanciaKm(atual, s.ponto)
+		a.distanciaKm += d
+		minutos += d / p.kmPorMinuto
+		a.trechos[i] = d
+		a.chegadas[i] = p.saida.Add(time.Duration(minutos * float64(time.Minute)))
+		if s.prazo != nil && a.chegadas[i].After(*s.prazo) {
+			a.atrasos++
+			a.atrasoMin += a.chegadas[i].Sub(*s.prazo).Minutes()
+		}
+		atual = s.ponto
+	}
+	return a
+}
+
+// melhorQue: menos pedidos atrasados, depois menos minutos de atraso,
+// depois menor distância
+func (a avaliacaoRota) melhorQue(b avaliacaoRota) bool {
+	const eps = 1e-9
+	if a.atrasos != b.atrasos {
+		return a.atrasos < b.atrasos
+	}
+	if math.Abs(a.atrasoMin-b.atrasoMin) > eps {
+		return a.atrasoMin < b.atrasoMin
+	}
+	return a.distanciaKm < b.distanciaKm-eps
+}
+
+// sequenciar parte do vizinho mais próximo e do prazo mais curto primeiro,
+// melhora as duas com 2-opt e fica com a melhor
+func (p parametrosRota) sequenciar(paradas []paradaRota) ([]paradaRota, avaliacaoRota) {
+	porPrazo := slices.Clone(paradas)
+	slices.SortStableFunc(porPrazo, compararPrazo)
+
+	melhor, aval := p.doisOpt(p.vizinhoMaisProximo(paradas))
+	if seq, a := p.doisOpt(porPrazo); a.melhorQue(aval) {
+		melhor, aval = seq, a
+	}
+	return melhor, aval
+}
+
+func (p parametrosRota) vizinhoMaisProximo(paradas []paradaRota) []paradaRota {
+	restantes := slices.Clone(paradas)
+	seq := make([]paradaRota, 0, len(paradas))
+	atual := p.origem
+	for len(restantes) > 0 {
+		idx, menor := 0, math.Inf(1)
+		for i, r := range restantes {
+			if d := geoutils.DistanciaKm(atual, r.ponto); d < menor {
+				idx, menor = i, d
+			}
+		}
+		seq = append(seq, restantes[idx])
+		atual = restantes[idx].ponto
+		restantes = slices.Delete(restantes, idx, idx+1)
+	}
+	return seq
+}
+
+// doisOpt inverte trechos da rota enquanto alguma inversão melhorar
+func (p parametrosRota) doisOpt(seq []paradaRota) ([]paradaRota, avaliacaoRota) {
+	seq = slices.Clone(seq)
+	aval := p.avaliar(seq)
+	for melhorou := true; melhorou; {
+		melhorou = false
+		for i := 0; i < len(seq)-1; i++ {
+			for k := i + 1; k < len(seq); k++ {
+				slices.Reverse(seq[i : k+1])
+				if a := p.avaliar(seq); a.melhorQue(aval) {
+					aval, melhorou = a, true
+				} else {
+					slices.Reverse(seq[i : k+1])
+				}
+			}
+		}
+	}
+	return seq, aval
+}
+
+// agrupar monta as rotas a partir do pedido de prazo mais curto, somando
+// os pedidos mais próximos enquanto couberem e não atrasarem os demais
+func (p parametrosRota) agrupar(paradas []paradaRota, maxPorRota int, raioKm float64) [][]paradaRota {
+	pendentes := slices.Clone(paradas)
+	slices.SortStableFunc(pendentes, compararPrazo)
+
+	var grupos [][]paradaRota
+	for len(pendentes) > 0 {
+		rota := []paradaRota{pendentes[0]}
+		pendentes = pendentes[1:]
+		_, aval := p.sequenciar(rota)
+
+		for len(rota) < maxPorRota {
+			candidatos := p.candidatos(rota, pendentes, raioKm)
+			aceito := -1
+			for _, idx := range candidatos {
+				c := pendentes[idx]
+				// Pedido que atrasa mesmo indo sozinho não conta contra a rota
+				tolerancia := p.avaliar([]paradaRota{c}).atrasos
+				if _, a := p.sequenciar(append(slices.Clone(rota), c)); a.atrasos <= aval.atrasos+tolerancia {
+					aceito, aval = idx, a
+					break
+				}
+			}
+			if aceito < 0 {
+				break
+			}
+			rota = append(rota, pendentes[aceito])
+			pendentes = slices.Delete(pendentes, aceito, aceito+1)
+		}
+		grupos = append(grupos, rota)
+	}
+	return grupos
+}
+
+// candidatos: índices dos pendentes a até raioKm de alguma parada da rota,
+// do mais próximo ao mais distante
+func (p parametrosRota) candidatos(rota, pendentes []paradaRota, raioKm float64) []int {
+	distancias := make(map[int]float64)
+	var idxs []int
+	for i, c := range pendentes {
+		menor := math.Inf(1)
+		for _, r := range rota {
+			menor = math.Min(menor, geoutils.DistanciaKm(r.ponto, c.ponto))
+		}
+		if menor <= raioKm {
+			distancias[i] = menor
+			idxs = append(idxs, i)
+		}
+	}
+	slices.SortStableFunc(idxs, func(a, b int) int {
+		switch {
+		case distancias[a] < distancias[b]:
+			return -1
+		case distancias[a] > distancias[b]:
+			return 1
+		}
+		return 0
+	})
+	return idxs
+}
+
+// compararPrazo ordena pelo prazo, os sem prazo por último
+func compararPrazo(a, b paradaRota) int {
+	switch {
+	case a.prazo == nil && b.prazo == nil:
+		return 0
+	case a.prazo == nil:
+		return 1
+	case b.prazo == nil:
+		return -1
+	}
+	return a.prazo.Compare(*b.prazo)
+}
+
+func (p parametrosRota) rotaPlanejadaResponse(seq []paradaRota, aval avaliacaoRota) dto.RotaPlanejadaResponse {
+	out := dto.RotaPlanejadaResponse{
+		Pedidos:     make([]uuid.UUID, len(seq)),
+		Paradas:     make([]dto.ParadaPlanejadaResponse, len(seq)),
+		DistanciaKm: arredondarKm(aval.distanciaKm),
+		Atrasados:   aval.atrasos,
+	}
+	for i, s := range seq {
+		parada := dto.ParadaPlanejadaResponse{
+			Ordem:           int32(i + 1),
+			IDPedido:        s.idPedido,
+			CodigoPedido:    s.codigo,
+			ClienteNome:     s.clienteNome,
+			Bairro:          pgTextToPtr(s.bairro),
+			Lat:             s.ponto.Lat,
+			Lng:             s.ponto.Lng,
+			DistanciaKm:     arredondarKm(aval.trechos[i]),
+			ChegadaPrevista: aval.chegadas[i],
+			Prazo:           s.prazo,
+		}
+		if s.prazo != nil {
+			folga := int32(math.Floor(s.prazo.Sub(aval.chegadas[i]).Minutes()))
+			parada.FolgaMin = &folga
+			parada.Atrasado = aval.chegadas[i].After(*s.prazo)
+		}
+		out.Pedidos[i] = s.idPedido
+		out.Paradas[i] = parada
+	}
+	if n := len(seq); n > 0 {
+		out.RetornoKm = arredondarKm(geoutils.DistanciaKm(seq[n-1].ponto, p.origem))
+		out.DuracaoMin = int32(math.Ceil(aval.chegadas[n-1].Sub(p.saida).Minutes()))
+	}
+	return out
+}
+
+func arredondarKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
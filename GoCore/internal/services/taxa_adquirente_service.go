@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	formaCartaoCredito = 3 // formas_pagamento.id de CARTAO_CREDITO
+
+	diasCalendarioPadrao = 30
+	diasCalendarioMaximo = 366
+)
+
+var ErrTaxaAdquirenteNaoEncontrada = errors.New("taxa da adquirente não encontrada")
+
+// TaxaAdquirenteInvalidaError aponta taxa ou período fora das regras
+type TaxaAdquirenteInvalidaError struct {
+	Mensagem string
+}
+
+func (e *TaxaAdquirenteInvalidaError) Error() string { return e.Mensagem }
+
+// TaxaAdquirenteService configura o MDR e o prazo de repasse de cada forma
+// de pagamento do tenant e monta o calendário de recebíveis. A taxa, o
+// líquido e a data do depósito de cada pagamento são calculados pelo
+// gatilho calcular_repasse_pagamento com a configuração vigente no INSERT.
+type TaxaAdquirenteService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewTaxaAdquirenteService(pool *pgxpool.Pool) TaxaAdquirenteService {
+	return TaxaAdquirenteService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (ts *TaxaAdquirenteService) Listar(ctx context.Context, tenantID uuid.UUID) ([]dto.TaxaAdquirenteResponse, error) {
+	rows, err := ts.queries.ListTaxasAdquirente(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.TaxaAdquirenteResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.TaxaAdquirenteToResponse(r))
+	}
+	return out, nil
+}
+
+// Salvar grava a taxa da forma (e parcelas); a combinação já existente é
+// atualizada. Vale só para os pagamentos gravados depois.
+func (ts *TaxaAdquirenteService) Salvar(ctx context.Context, tenantID uuid.UUID, in dto.TaxaAdquirenteDTO) (dto.TaxaAdquirenteResponse, error) {
+	forma, err := ts.queries.GetFormaPagamento(ctx, in.IDFormaPagamento)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.TaxaAdquirenteResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "forma de pagamento inválida"}
+		}
+		return dto.TaxaAdquirenteResponse{}, err
+	}
+	if forma.Tipo == "D" {
+		return dto.TaxaAdquirenteResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "dinheiro não passa pela adquirente"}
+	}
+	if in.Parcelas > 1 && forma.ID != formaCartaoCredito {
+		return dto.TaxaAdquirenteResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "parcelas só no cartão de crédito"}
+	}
+	if c := decimalutils.ToCentavos(*in.TaxaPercentual); c < 0 || c > 100_00 {
+		return dto.TaxaAdquirenteResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "taxa_percentual deve estar entre 0 e 100"}
+	}
+	if in.TaxaFixa != nil && decimalutils.ToCentavos(*in.TaxaFixa) < 0 {
+		return dto.TaxaAdquirenteResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "taxa_fixa não pode ser negativa"}
+	}
+
+	taxa, err := ts.queries.UpsertTaxaAdquirente(ctx, in.ToUpsertParams(tenantID))
+	if err != nil {
+		return dto.TaxaAdquirenteResponse{}, err
+	}
+	return dto.TaxaAdquirenteSalvaToResponse(taxa, forma), nil
+}
+
+// Remover apaga a taxa; a forma volta a repassar o valor cheio no dia
+func (ts *TaxaAdquirenteService) Remover(ctx context.Context, tenantID, id uuid.UUID) error {
+	n, err := ts.queries.DeleteTaxaAdquirente(ctx, pgstore.DeleteTaxaAdquirenteParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTaxaAdquirenteNaoEncontrada
+	}
+	return nil
+}
+
+// Calendario lista os depósitos previstos da adquirente por dia, de inicio
+// (padrão: hoje no fuso do tenant) a fim (padrão: inicio + 30 dias)
+func (ts *TaxaAdquirenteService) Calendario(ctx context.Context, tenantID uuid.UUID, inicio, fim *time.Time) (dto.CalendarioRecebiveisResponse, error) {
+	var de time.Time
+	if inicio != nil {
+		de = *inicio
+	} else {
+		loc, err := fusoDoTenant(ctx, ts.queries, tenantID)
+		if err != nil {
+			return dto.CalendarioRecebiveisResponse{}, err
+		}
+		de = time.Now().In(loc)
+	}
+	de = time.Date(de.Year(), de.Month(), de.Day(), 0, 0, 0, 0, time.UTC)
+
+	ate := de.AddDate(0, 0, diasCalendarioPadrao)
+	if fim != nil {
+		ate = time.Date(fim.Year(), fim.Month(), fim.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if ate.Before(de) {
+		return dto.CalendarioRecebiveisResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "fim anterior ao início"}
+	}
+	if ate.Sub(de) > diasCalendarioMaximo*24*time.Hour {
+		return dto.CalendarioRecebiveisResponse{}, &TaxaAdquirenteInvalidaError{Mensagem: "período máximo de 366 dias"}
+	}
+
+	rows, err := ts.queries.ListCalendarioRecebiveis(ctx, pgstore.ListCalendarioRecebiveisParams{
+		TenantID: tenantID,
+		Inicio:   pgtype.Date{Time: de, Valid: true},
+		Fim:      pgtype.Date{Time: ate, Valid: true},
+	})
+	if err != nil {
+		return dto.CalendarioRecebiveisResponse{}, err
+	}
+	return dto.CalendarioRecebiveisToResponse(rows, de, ate), nil
+}
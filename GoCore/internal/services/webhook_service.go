@@ -0,0 +1,445 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+	"gobid/internal/webhookutils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrWebhookNaoEncontrado        = errors.New("webhook não encontrado")
+	ErrWebhookEntregaNaoEncontrada = errors.New("entrega de webhook não encontrada")
+	ErrWebhookURLInvalida          = errors.New("url do webhook deve ser http(s) absoluta")
+	ErrWebhookDestinoBloqueado     = errors.New("url do webhook aponta para endereço interno")
+	ErrWebhookEventoDesconhecido   = errors.New("event_type desconhecido")
+)
+
+const (
+	webhookTimeout         = 10 * time.Second
+	webhookDialTimeout     = 5 * time.Second
+	webhookMaxResponseBody = 2048 // bytes gravados no log de entrega
+)
+
+// Faixas que não são loopback/privadas para o pacote net, mas também não
+// são internet pública.
+var webhookRedesBloqueadas = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),     // "esta rede"
+	mustCIDR("100.64.0.0/10"), // CGNAT
+	mustCIDR("192.0.0.0/24"),  // atribuições IETF
+	mustCIDR("198.18.0.0/15"), // benchmark
+	mustCIDR("64:ff9b::/96"),  // NAT64 (embute IPv4)
+}
+
+// WebhookService mantém as assinaturas de webhook do tenant e entrega os
+// eventos do outbox a elas. Implementa OutboxPublisher.
+type WebhookService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	client  *http.Client
+}
+
+func NewWebhookService(pool *pgxpool.Pool) WebhookService {
+	return WebhookService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		client:  newWebhookHTTPClient(),
+	}
+}
+
+// newWebhookHTTPClient confere o IP de cada conexão no momento do dial, de
+// modo que nem DNS trocado depois do cadastro (rebinding) nem proxy levam a
+// entrega para a rede interna.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookDialTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !webhookIPPermitido(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrWebhookDestinoBloqueado, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		// redirecionamento é tratado como falha: a assinatura vale para a URL cadastrada
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+/* -------------------- Assinaturas -------------------- */
+
+func (ws *WebhookService) CreateSubscription(ctx context.Context, in dto.CreateWebhookSubscriptionDTO) (dto.WebhookSubscriptionResponse, error) {
+	if err := validateWebhook(ctx, in.URL, in.EventTypes); err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	secret, err := webhookutils.NewSecret()
+	if err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	sub, err := ws.queries.CreateWebhookSubscription(ctx, dto.CreateWebhookDTOToParams(&in, secret))
+	if err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	resp := dto.WebhookSubscriptionToResponse(sub)
+	resp.Secret = sub.Secret
+	return resp, nil
+}
+
+func (ws *WebhookService) ListSubscriptions(ctx context.Context, tenantID uuid.UUID) ([]dto.WebhookSubscriptionResponse, error) {
+	rows, err := ws.queries.ListWebhookSubscriptions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.WebhookSubscriptionsToResponses(rows), nil
+}
+
+func (ws *WebhookService) GetSubscription(ctx context.Context, id, tenantID uuid.UUID) (dto.WebhookSubscriptionResponse, error) {
+	sub, err := ws.getSubscription(ctx, id, tenantID)
+	if err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+	return dto.WebhookSubscriptionToResponse(sub), nil
+}
+
+func (ws *WebhookService) UpdateSubscription(ctx context.Context, in dto.UpdateWebhookSubscriptionDTO) (dto.WebhookSubscriptionResponse, error) {
+	if err := validateWebhook(ctx, in.URL, in.EventTypes); err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	sub, err := ws.queries.UpdateWebhookSubscription(ctx, dto.UpdateWebhookDTOToParams(&in))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.WebhookSubscriptionResponse{}, ErrWebhookNaoEncontrado
+		}
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+	return dto.WebhookSubscriptionToResponse(sub), nil
+}
+
+func (ws *WebhookService) RotateSecret(ctx context.Context, id, tenantID uuid.UUID) (dto.WebhookSubscriptionResponse, error) {
+	secret, err := webhookutils.NewSecret()
+	if err != nil {
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	sub, err := ws.queries.RotateWebhookSubscriptionSecret(ctx, pgstore.RotateWebhookSubscriptionSecretParams{
+		ID:       id,
+		TenantID: tenantID,
+		Secret:   secret,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.WebhookSubscriptionResponse{}, ErrWebhookNaoEncontrado
+		}
+		return dto.WebhookSubscriptionResponse{}, err
+	}
+
+	resp := dto.WebhookSubscriptionToResponse(sub)
+	resp.Secret = sub.Secret
+	return resp, nil
+}
+
+func (ws *WebhookService) DeleteSubscription(ctx context.Context, id, tenantID uuid.UUID) error {
+	n, err := ws.queries.DeleteWebhookSubscription(ctx, pgstore.DeleteWebhookSubscriptionParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrWebhookNaoEncontrado
+	}
+	return nil
+}
+
+/* -------------------- Entregas -------------------- */
+
+func (ws *WebhookService) ListDeliveries(ctx context.Context, subscriptionID, tenantID uuid.UUID, limit, offset int32) ([]dto.WebhookDeliveryResponse, error) {
+	if _, err := ws.getSubscription(ctx, subscriptionID, tenantID); err != nil {
+		return nil, err
+	}
+
+	rows, err := ws.queries.ListWebhookDeliveries(ctx, pgstore.ListWebhookDeliveriesParams{
+		SubscriptionID: subscriptionID,
+		TenantID:       tenantID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.WebhookDeliveriesToResponses(rows), nil
+}
+
+// Redeliver reenvia o corpo gravado numa entrega anterior, com nova assinatura
+// e timestamp, e registra o resultado como entrega manual.
+func (ws *WebhookService) Redeliver(ctx context.Context, subscriptionID uuid.UUID, deliveryID int64, tenantID uuid.UUID) (dto.WebhookDeliveryResponse, error) {
+	prev, err := ws.queries.GetWebhookDelivery(ctx, pgstore.GetWebhookDeliveryParams{
+		ID:       deliveryID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.WebhookDeliveryResponse{}, ErrWebhookEntregaNaoEncontrada
+		}
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	if prev.SubscriptionID != subscriptionID {
+		return dto.WebhookDeliveryResponse{}, ErrWebhookEntregaNaoEncontrada
+	}
+
+	sub, err := ws.getSubscription(ctx, subscriptionID, tenantID)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+
+	delivery, err := ws.deliver(ctx, sub, prev.EventID, prev.EventType, prev.Payload, true)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	return dto.WebhookDeliveryToResponse(delivery), nil
+}
+
+// Publish entrega o evento a todas as assinaturas interessadas que ainda não
+// o receberam, em paralelo, para que uma assinatura lenta não consuma o
+// timeout das demais. Se alguma falhar, devolve erro para o dispatcher
+// reagendar; na próxima tentativa apenas as que falharam recebem o evento
+// de novo.
+func (ws *WebhookService) Publish(ctx context.Context, ev dto.OutboxEventResponse) error {
+	subs, err := ws.queries.ListWebhookSubscriptionsForEvent(ctx, pgstore.ListWebhookSubscriptionsForEventParams{
+		TenantID:  ev.TenantID,
+		EventType: ev.EventType,
+		EventID:   ev.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(dto.WebhookEventBodyFromOutbox(ev))
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		logErr error
+	)
+	for _, sub := range subs {
+		wg.Add(1)
+		go func(sub pgstore.WebhookSubscription) {
+			defer wg.Done()
+			delivery, err := ws.deliver(ctx, sub, ev.ID, ev.EventType, body, false)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logErr = err
+				return
+			}
+			if !delivery.Success {
+				failed++
+			}
+		}(sub)
+	}
+	wg.Wait()
+
+	if logErr != nil {
+		return logErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d de %d webhooks falharam", failed, len(subs))
+	}
+	return nil
+}
+
+// deliver faz o POST assinado e grava o resultado em webhook_deliveries.
+// Só devolve erro se não conseguir gravar o log; falha HTTP vai no registro.
+func (ws *WebhookService) deliver(ctx context.Context, sub pgstore.WebhookSubscription,
+	eventID int64, eventType string, body []byte, manual bool) (pgstore.WebhookDelivery, error) {
+
+	params := pgstore.CreateWebhookDeliveryParams{
+		TenantID:       sub.TenantID,
+		SubscriptionID: sub.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        body,
+		Url:            sub.Url,
+		Manual:         manual,
+	}
+
+	start := time.Now()
+	statusCode, respBody, err := ws.post(ctx, sub, eventID, eventType, body)
+	params.DurationMs = int32(time.Since(start).Milliseconds())
+
+	if statusCode != 0 {
+		params.StatusCode = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+		params.ResponseBody = dto.TextOrNull(respBody)
+	}
+	params.Success, params.Error = resultadoEntrega(statusCode, err)
+
+	// o log é gravado mesmo que o contexto da entrega tenha expirado
+	return ws.queries.CreateWebhookDelivery(context.WithoutCancel(ctx), params)
+}
+
+// resultadoEntrega: sucesso só com 2xx; senão, o motivo da falha
+func resultadoEntrega(statusCode int, err error) (bool, pgtype.Text) {
+	switch {
+	case err != nil:
+		return false, dto.TextOrNull(err.Error())
+	case statusCode < 200 || statusCode > 299:
+		return false, dto.TextOrNull(fmt.Sprintf("status HTTP %d", statusCode))
+	default:
+		return true, pgtype.Text{}
+	}
+}
+
+func (ws *WebhookService) post(ctx context.Context, sub pgstore.WebhookSubscription,
+	eventID int64, eventType string, body []byte) (int, string, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	id := strconv.FormatInt(eventID, 10)
+	ts := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gobid-webhooks/1")
+	req.Header.Set(webhookutils.HeaderID, id)
+	req.Header.Set(webhookutils.HeaderEvent, eventType)
+	req.Header.Set(webhookutils.HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(webhookutils.HeaderSignature, webhookutils.Sign(sub.Secret, id, ts, body))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	// drena o restante para reaproveitar a conexão
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, string(respBody), nil
+}
+
+func (ws *WebhookService) getSubscription(ctx context.Context, id, tenantID uuid.UUID) (pgstore.WebhookSubscription, error) {
+	sub, err := ws.queries.GetWebhookSubscription(ctx, pgstore.GetWebhookSubscriptionParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.WebhookSubscription{}, ErrWebhookNaoEncontrado
+		}
+		return pgstore.WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+func validateWebhook(ctx context.Context, rawURL string, eventTypes []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrWebhookURLInvalida
+	}
+	if err := validateWebhookHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+	for _, et := range eventTypes {
+		if !dto.IsKnownOutboxEventType(et) {
+			return fmt.Errorf("%w: %s", ErrWebhookEventoDesconhecido, et)
+		}
+	}
+	return nil
+}
+
+// validateWebhookHost resolve o host e recusa o cadastro se algum endereço
+// for interno. A conferência vale de novo a cada conexão (ver
+// newWebhookHTTPClient), pois o DNS pode mudar depois.
+func validateWebhookHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !webhookIPPermitido(ip) {
+			return ErrWebhookDestinoBloqueado
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %s não resolve", ErrWebhookURLInvalida, host)
+	}
+	for _, a := range addrs {
+		if !webhookIPPermitido(a.IP) {
+			return ErrWebhookDestinoBloqueado
+		}
+	}
+	return nil
+}
+
+// webhookIPPermitido recusa loopback, redes privadas, link-local (inclui o
+// metadata 169.254.169.254 das clouds), multicast e faixas reservadas.
+func webhookIPPermitido(ip net.IP) bool {
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return false
+	}
+	for _, rede := range webhookRedesBloqueadas {
+		if rede.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, rede, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return rede
+}
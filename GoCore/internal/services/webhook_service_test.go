@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gobid/internal/store/pgstore"
+	"gobid/internal/webhookutils"
+
+	"github.com/google/uuid"
+)
+
+// recebido guarda o que o servidor de teste viu em cada requisição
+type recebido struct {
+	id, evento, timestamp, assinatura string
+	body                              []byte
+}
+
+// servidorWebhook responde com os status da lista, em ordem (o último se
+// repete), e registra as requisições
+func servidorWebhook(t *testing.T, status ...int) (*httptest.Server, func() []recebido) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		todas []recebido
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		todas = append(todas, recebido{
+			id:         r.Header.Get(webhookutils.HeaderID),
+			evento:     r.Header.Get(webhookutils.HeaderEvent),
+			timestamp:  r.Header.Get(webhookutils.HeaderTimestamp),
+			assinatura: r.Header.Get(webhookutils.HeaderSignature),
+			body:       body,
+		})
+		n := len(todas)
+		mu.Unlock()
+
+		code := status[len(status)-1]
+		if n <= len(status) {
+			code = status[n-1]
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []recebido {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recebido(nil), todas...)
+	}
+}
+
+// webhookServiceDeTeste usa o client de produção (timeout e política de
+// redirect), mas com o transport do httptest, que aceita loopback
+func webhookServiceDeTeste(srv *httptest.Server) *WebhookService {
+	client := newWebhookHTTPClient()
+	client.Transport = srv.Client().Transport
+	return &WebhookService{client: client}
+}
+
+func assinaturaDeTeste(url string) pgstore.WebhookSubscription {
+	return pgstore.WebhookSubscription{
+		ID:       uuid.New(),
+		TenantID: uuid.New(),
+		Url:      url,
+		Secret:   "whsec_teste",
+	}
+}
+
+func TestWebhookPostAssinado(t *testing.T) {
+	srv, recebidos := servidorWebhook(t, http.StatusOK)
+	ws := webhookServiceDeTeste(srv)
+	sub := assinaturaDeTeste(srv.URL)
+	body := []byte(`{"event_type":"pedido.created"}`)
+
+	status, resp, err := ws.post(context.Background(), sub, 42, "pedido.created", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, motivo := resultadoEntrega(status, err); !ok || motivo.Valid {
+		t.Fatalf("resultadoEntrega(%d) = %v, %q; want sucesso", status, ok, motivo.String)
+	}
+	if resp != "ok" {
+		t.Fatalf("corpo da resposta = %q", resp)
+	}
+
+	r := recebidos()
+	if len(r) != 1 {
+		t.Fatalf("%d requisições, want 1", len(r))
+	}
+	if r[0].id != "42" || r[0].evento != "pedido.created" {
+		t.Fatalf("cabeçalhos = id %q evento %q", r[0].id, r[0].evento)
+	}
+	err = webhookutils.Verify(sub.Secret, r[0].id, r[0].timestamp, r[0].assinatura, r[0].body,
+		webhookutils.DefaultTolerance, time.Now())
+	if err != nil {
+		t.Fatalf("receptor não validou a assinatura: %v", err)
+	}
+}
+
+func TestWebhookPostNovaTentativa(t *testing.T) {
+	srv, recebidos := servidorWebhook(t, http.StatusInternalServerError, http.StatusNoContent)
+	ws := webhookServiceDeTeste(srv)
+	sub := assinaturaDeTeste(srv.URL)
+	body := []byte(`{"id":7}`)
+
+	status, _, err := ws.post(context.Background(), sub, 7, "pedido.updated", body)
+	if ok, motivo := resultadoEntrega(status, err); ok || motivo.String != "status HTTP 500" {
+		t.Fatalf("1ª tentativa: resultadoEntrega = %v, %q; want falha 500", ok, motivo.String)
+	}
+
+	status, _, err = ws.post(context.Background(), sub, 7, "pedido.updated", body)
+	if ok, _ := resultadoEntrega(status, err); !ok {
+		t.Fatalf("2ª tentativa: status %d, err %v; want sucesso", status, err)
+	}
+
+	r := recebidos()
+	if len(r) != 2 {
+		t.Fatalf("%d requisições, want 2", len(r))
+	}
+	// o id do evento se repete para o receptor deduplicar; a assinatura
+	// de cada tentativa é válida por si
+	if r[0].id != r[1].id {
+		t.Fatalf("ids diferentes entre tentativas: %q, %q", r[0].id, r[1].id)
+	}
+	for i, req := range r {
+		err := webhookutils.Verify(sub.Secret, req.id, req.timestamp, req.assinatura, req.body,
+			webhookutils.DefaultTolerance, time.Now())
+		if err != nil {
+			t.Fatalf("tentativa %d: assinatura inválida: %v", i+1, err)
+		}
+	}
+}
+
+func TestWebhookPostFalhas(t *testing.T) {
+	t.Run("redirect não é seguido", func(t *testing.T) {
+		destino, recebidos := servidorWebhook(t, http.StatusOK)
+		srv := httptest.NewServer(http.RedirectHandler(destino.URL, http.StatusFound))
+		t.Cleanup(srv.Close)
+
+		ws := webhookServiceDeTeste(srv)
+		status, _, err := ws.post(context.Background(), assinaturaDeTeste(srv.URL), 1, "pedido.created", []byte(`{}`))
+		if ok, _ := resultadoEntrega(status, err); ok || status != http.StatusFound {
+			t.Fatalf("status %d, err %v; want falha com 302", status, err)
+		}
+		if n := len(recebidos()); n != 0 {
+			t.Fatalf("destino do redirect recebeu %d requisições", n)
+		}
+	})
+
+	t.Run("conexão recusada", func(t *testing.T) {
+		srv, _ := servidorWebhook(t, http.StatusOK)
+		url := srv.URL
+		ws := webhookServiceDeTeste(srv)
+		srv.Close()
+
+		status, _, err := ws.post(context.Background(), assinaturaDeTeste(url), 1, "pedido.created", []byte(`{}`))
+		if ok, motivo := resultadoEntrega(status, err); ok || !motivo.Valid || status != 0 {
+			t.Fatalf("status %d, err %v; want falha sem status", status, err)
+		}
+	})
+
+	t.Run("timeout do contexto", func(t *testing.T) {
+		pare := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-pare
+		}))
+		t.Cleanup(srv.Close)
+		t.Cleanup(func() { close(pare) }) // libera o handler antes do Close
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		ws := webhookServiceDeTeste(srv)
+		_, _, err := ws.post(ctx, assinaturaDeTeste(srv.URL), 1, "pedido.created", []byte(`{}`))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("destino interno bloqueado no dial", func(t *testing.T) {
+		srv, recebidos := servidorWebhook(t, http.StatusOK)
+		ws := &WebhookService{client: newWebhookHTTPClient()}
+
+		_, _, err := ws.post(context.Background(), assinaturaDeTeste(srv.URL), 1, "pedido.created", []byte(`{}`))
+		if !errors.Is(err, ErrWebhookDestinoBloqueado) {
+			t.Fatalf("err = %v, want ErrWebhookDestinoBloqueado", err)
+		}
+		if n := len(recebidos()); n != 0 {
+			t.Fatalf("servidor interno recebeu %d requisições", n)
+		}
+	})
+}
+
+func TestWebhookIPPermitido(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.0.10", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := webhookIPPermitido(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("webhookIPPermitido(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"https://8.8.8.8/hook", nil},
+		{"ftp://8.8.8.8/hook", ErrWebhookURLInvalida},
+		{"/relativa", ErrWebhookURLInvalida},
+		{"http://127.0.0.1:8080/hook", ErrWebhookDestinoBloqueado},
+		{"http://[::1]/hook", ErrWebhookDestinoBloqueado},
+		{"http://169.254.169.254/latest/meta-data", ErrWebhookDestinoBloqueado},
+		{"http://localhost/hook", ErrWebhookDestinoBloqueado},
+	}
+	for _, tt := range tests {
+		err := validateWebhook(context.Background(), tt.url, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("validateWebhook(%s) = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
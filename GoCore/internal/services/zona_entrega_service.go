@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/geoutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrZonaEntregaNaoEncontrada = errors.New("zona de entrega não encontrada")
+	ErrSemZonasEntrega          = errors.New("tenant não tem zonas de entrega ativas")
+	ErrForaDaAreaEntrega        = errors.New("endereço fora da área de entrega")
+	ErrEnderecoIncompleto       = errors.New("informe cep, bairro ou coordenadas do endereço")
+)
+
+// ZonaEntregaInvalidaError aponta uma zona sem a definição exigida pelo tipo
+type ZonaEntregaInvalidaError struct {
+	Mensagem string
+}
+
+func (e *ZonaEntregaInvalidaError) Error() string { return e.Mensagem }
+
+// ZonaEntregaService mantém as zonas de entrega e as coordenadas da loja.
+// A zona do pedido é resolvida na cotação (PrecificacaoService), que troca a
+// taxa de entrega informada pela da zona; ver zonas_entrega_regras.go.
+type ZonaEntregaService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewZonaEntregaService(pool *pgxpool.Pool) ZonaEntregaService {
+	return ZonaEntregaService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+func (zs *ZonaEntregaService) List(ctx context.Context, tenantID uuid.UUID) ([]dto.ZonaEntregaResponse, error) {
+	zonas, err := zs.queries.ListZonasEntrega(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.ZonaEntregaResponse, len(zonas))
+	for i, z := range zonas {
+		if out[i], err = zonaEntregaResponse(z); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (zs *ZonaEntregaService) Get(ctx context.Context, tenantID, id uuid.UUID) (dto.ZonaEntregaResponse, error) {
+	zona, err := zs.queries.GetZonaEntrega(ctx, pgstore.GetZonaEntregaParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ZonaEntregaResponse{}, ErrZonaEntregaNaoEncontrada
+		}
+		return dto.ZonaEntregaResponse{}, err
+	}
+	return zonaEntregaResponse(zona)
+}
+
+func (zs *ZonaEntregaService) Create(ctx context.Context, in dto.ZonaEntregaDTO) (dto.ZonaEntregaResponse, error) {
+	params, err := zs.validar(ctx, in)
+	if err != nil {
+		return dto.ZonaEntregaResponse{}, err
+	}
+	zona, err := zs.queries.CreateZonaEntrega(ctx, params)
+	if err != nil {
+		return dto.ZonaEntregaResponse{}, err
+	}
+	return zonaEntregaResponse(zona)
+}
+
+// Update troca a definição da zona. Pedidos já feitos mantêm a taxa gravada.
+func (zs *ZonaEntregaService) Update(ctx context.Context, in dto.ZonaEntregaDTO) (dto.ZonaEntregaResponse, error) {
+	params, err := zs.validar(ctx, in)
+	if err != nil {
+		return dto.ZonaEntregaResponse{}, err
+	}
+	zona, err := zs.queries.UpdateZonaEntrega(ctx, pgstore.UpdateZonaEntregaParams{
+		ID:                in.ID,
+		TenantID:          params.TenantID,
+		Nome:              params.Nome,
+		Tipo:              params.Tipo,
+		Bairros:           params.Bairros,
+		CepInicio:         params.CepInicio,
+		CepFim:            params.CepFim,
+		RaioKm:            params.RaioKm,
+		Poligono:          params.Poligono,
+		Taxa:              params.Taxa,
+		ValorMinimoPedido: params.ValorMinimoPedido,
+		PrazoMin:          params.PrazoMin,
+		PrazoMax:          params.PrazoMax,
+		Ordem:             params.Ordem,
+		Ativo:             params.Ativo,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ZonaEntregaResponse{}, ErrZonaEntregaNaoEncontrada
+		}
+		return dto.ZonaEntregaResponse{}, err
+	}
+	return zonaEntregaResponse(zona)
+}
+
+func (zs *ZonaEntregaService) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	n, err := zs.queries.DeleteZonaEntrega(ctx, pgstore.DeleteZonaEntregaParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrZonaEntregaNaoEncontrada
+	}
+	return nil
+}
+
+func (zs *ZonaEntregaService) GetOrigem(ctx context.Context, tenantID uuid.UUID) (dto.TenantOrigemResponse, error) {
+	row, err := zs.queries.GetTenantOrigem(ctx, tenantID)
+	if err != nil {
+		return dto.TenantOrigemResponse{}, err
+	}
+	var out dto.TenantOrigemResponse
+	if p := pontoDeNumeric(row.Lat, row.Lng); p != nil {
+		out.Lat, out.Lng = &p.Lat, &p.Lng
+	}
+	return out, nil
+}
+
+// PutOrigem grava as coordenadas da loja usadas pelas zonas por raio
+func (zs *ZonaEntregaService) PutOrigem(ctx context.Context, tenantID uuid.UUID, in dto.TenantOrigemDTO) (dto.TenantOrigemResponse, error) {
+	p := geoutils.Ponto{Lat: in.Lat, Lng: in.Lng}
+	if !p.Valido() {
+		return dto.TenantOrigemResponse{}, &ZonaEntregaInvalidaError{Mensagem: "coordenadas da loja inválidas"}
+	}
+	if _, err := zs.queries.UpdateTenantOrigem(ctx, pgstore.UpdateTenantOrigemParams{
+		ID:  tenantID,
+		Lat: coordenadaToNumeric(p.Lat),
+		Lng: coordenadaToNumeric(p.Lng),
+	}); err != nil {
+		return dto.TenantOrigemResponse{}, err
+	}
+	return dto.TenantOrigemResponse{Lat: &p.Lat, Lng: &p.Lng}, nil
+}
+
+// Resolver devolve a zona que atende o endereço, como na cotação do pedido
+func (zs *ZonaEntregaService) Resolver(ctx context.Context, tenantID uuid.UUID, in dto.EnderecoEntregaDTO) (dto.EntregaResolvidaResponse, error) {
+	zonas, err := carregarZonasEntrega(ctx, zs.queries, tenantID)
+	if err != nil {
+		return dto.EntregaResolvidaResponse{}, err
+	}
+	if zonas == nil {
+		return dto.EntregaResolvidaResponse{}, ErrSemZonasEntrega
+	}
+
+	endereco, err := enderecoDoPedido(ctx, zs.queries, tenantID, in)
+	if err != nil {
+		return dto.EntregaResolvidaResponse{}, err
+	}
+	if endereco.vazio() {
+		return dto.EntregaResolvidaResponse{}, ErrEnderecoIncompleto
+	}
+
+	zona, distancia := zonas.resolver(endereco)
+	if zona == nil {
+		return dto.EntregaResolvidaResponse{}, ErrForaDaAreaEntrega
+	}
+	return dto.ZonaEntregaToResolvida(*zona, distancia), nil
+}
+
+// validar confere a definição exigida pelo tipo e devolve os parâmetros já
+// normalizados: bairros sem acento, prefixo de CEP convertido em faixa e
+// polígono em [[lat, lng], ...]
+func (zs *ZonaEntregaService) validar(ctx context.Context, in dto.ZonaEntregaDTO) (pgstore.CreateZonaEntregaParams, error) {
+	params := pgstore.CreateZonaEntregaParams{
+		TenantID:          in.TenantID,
+		Nome:              strings.TrimSpace(in.Nome),
+		Tipo:              in.Tipo,
+		Taxa:              decimalutils.CentavosToNumeric(decimalutils.ToCentavos(in.Taxa)),
+		ValorMinimoPedido: decimalutils.CentavosToNumeric(valorOuZero(in.ValorMinimoPedido)),
+		Ordem:             in.Ordem,
+		Ativo:             in.Ativo == nil || *in.Ativo,
+	}
+	if in.PrazoMin != nil {
+		params.PrazoMin = pgtype.Int4{Int32: *in.PrazoMin, Valid: true}
+	}
+	if in.PrazoMax != nil {
+		params.PrazoMax = pgtype.Int4{Int32: *in.PrazoMax, Valid: true}
+	}
+
+	switch {
+	case decimalutils.ToCentavos(in.Taxa) < 0:
+		return params, &ZonaEntregaInvalidaError{Mensagem: "taxa não pode ser negativa"}
+	case valorOuZero(in.ValorMinimoPedido) < 0:
+		return params, &ZonaEntregaInvalidaError{Mensagem: "valor_minimo_pedido não pode ser negativo"}
+	case in.PrazoMin != nil && in.PrazoMax != nil && *in.PrazoMin > *in.PrazoMax:
+		return params, &ZonaEntregaInvalidaError{Mensagem: "prazo_min não pode ser maior que prazo_max"}
+	}
+
+	switch in.Tipo {
+	case dto.ZonaPorBairro:
+		vistos := make(map[string]bool, len(in.Bairros))
+		for _, b := range in.Bairros {
+			if n := normalizarBairro(b); n != "" && !vistos[n] {
+				vistos[n] = true
+				params.Bairros = append(params.Bairros, n)
+			}
+		}
+		if len(params.Bairros) == 0 {
+			return params, &ZonaEntregaInvalidaError{Mensagem: "zona por bairro exige a lista de bairros"}
+		}
+
+	case dto.ZonaPorCep:
+		inicio, fim, err := faixaCep(in)
+		if err != nil {
+			return params, err
+		}
+		params.CepInicio = pgtype.Text{String: inicio, Valid: true}
+		params.CepFim = pgtype.Text{String: fim, Valid: true}
+
+	case dto.ZonaPorRaio:
+		if in.RaioKm == nil || decimalutils.ToCentavos(*in.RaioKm) <= 0 {
+			return params, &ZonaEntregaInvalidaError{Mensagem: "zona por raio exige raio_km maior que zero"}
+		}
+		origem, err := zs.queries.GetTenantOrigem(ctx, in.TenantID)
+		if err != nil {
+			return params, err
+		}
+		if pontoDeNumeric(origem.Lat, origem.Lng) == nil {
+			return params, &ZonaEntregaInvalidaError{Mensagem: "cadastre as coordenadas da loja antes de criar zona por raio"}
+		}
+		params.RaioKm = decimalutils.CentavosToNumeric(decimalutils.ToCentavos(*in.RaioKm))
+
+	case dto.ZonaPorPoligono:
+		if len(in.Poligono) < 3 {
+			return params, &ZonaEntregaInvalidaError{Mensagem: "polígono exige pelo menos 3 vértices"}
+		}
+		for _, p := range in.Poligono {
+			if !p.Valido() {
+				return params, &ZonaEntregaInvalidaError{Mensagem: "polígono com coordenada inválida"}
+			}
+		}
+		raw, err := codificarPoligono(in.Poligono)
+		if err != nil {
+			return params, err
+		}
+		params.Poligono = raw
+	}
+	return params, nil
+}
+
+// faixaCep aceita prefixo ("01310" → 01310000..01310999) ou início e fim
+func faixaCep(in dto.ZonaEntregaDTO) (string, string, error) {
+	if in.CepPrefixo != nil {
+		if in.CepInicio != nil || in.CepFim != nil {
+			return "", "", &ZonaEntregaInvalidaError{Mensagem: "informe cep_prefixo ou cep_inicio/cep_fim, não ambos"}
+		}
+		p := *in.CepPrefixo
+		return p + strings.Repeat("0", 8-len(p)), p + strings.Repeat("9", 8-len(p)), nil
+	}
+	if in.CepInicio == nil || in.CepFim == nil {
+		return "", "", &ZonaEntregaInvalidaError{Mensagem: "zona por CEP exige cep_prefixo ou cep_inicio e cep_fim"}
+	}
+	if *in.CepInicio > *in.CepFim {
+		return "", "", &ZonaEntregaInvalidaError{Mensagem: "cep_inicio maior que cep_fim"}
+	}
+	return *in.CepInicio, *in.CepFim, nil
+}
+
+func zonaEntregaResponse(z pgstore.ZonasEntrega) (dto.ZonaEntregaResponse, error) {
+	poligono, err := decodificarPoligono(z.Poligono)
+	if err != nil {
+		return dto.ZonaEntregaResponse{}, err
+	}
+	return dto.ZonaEntregaToResponse(z, poligono), nil
+}
+
+// coordenadaToNumeric grava com as 6 casas de numeric(9,6)
+func coordenadaToNumeric(v float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(strconv.FormatFloat(v, 'f', 6, 64))
+	return n
+}
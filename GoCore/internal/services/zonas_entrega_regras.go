@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode"
+
+	"gobid/internal/decimalutils"
+	"gobid/internal/dto"
+	"gobid/internal/geoutils"
+	"gobid/internal/store/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Regras da zona de entrega (zonas_entrega.tipo):
+//
+//	B → bairro do endereço na lista, sem diferenciar acento e caixa
+//	C → CEP entre cep_inicio e cep_fim
+//	R → distância em linha reta da loja até lat/lng do pedido ≤ raio_km
+//	P → lat/lng do pedido dentro do polígono
+//
+// As zonas ativas são testadas pela ordem; vale a primeira que atende o
+// endereço. Tenant sem zonas ativas não usa o motor e mantém a taxa
+// informada no pedido.
+
+// enderecoEntrega: o que o motor usa do endereço, já normalizado
+type enderecoEntrega struct {
+	cep    string
+	bairro string
+	ponto  *geoutils.Ponto
+}
+
+func (e enderecoEntrega) vazio() bool {
+	return e.cep == "" && e.bairro == "" && e.ponto == nil
+}
+
+type zonasEntrega struct {
+	zonas    []pgstore.ZonasEntrega
+	poligono map[uuid.UUID][]geoutils.Ponto
+	// coordenadas da loja; nil sem cadastro
+	origem *geoutils.Ponto
+}
+
+func carregarZonasEntrega(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID) (*zonasEntrega, error) {
+	zonas, err := q.ListZonasEntregaAtivas(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(zonas) == 0 {
+		return nil, nil
+	}
+
+	z := &zonasEntrega{zonas: zonas, poligono: make(map[uuid.UUID][]geoutils.Ponto)}
+	for _, zona := range zonas {
+		if zona.Tipo != dto.ZonaPorPoligono {
+			continue
+		}
+		pontos, err := decodificarPoligono(zona.Poligono)
+		if err != nil {
+			return nil, err
+		}
+		z.poligono[zona.ID] = pontos
+	}
+
+	origem, err := q.GetTenantOrigem(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	z.origem = pontoDeNumeric(origem.Lat, origem.Lng)
+	return z, nil
+}
+
+// entregaCotacao: zonas do tenant e endereço do pedido em cotação
+type entregaCotacao struct {
+	zonas    *zonasEntrega
+	endereco enderecoEntrega
+}
+
+// carregarEntrega só vale para Delivery em tenant com zonas ativas; nos
+// demais casos devolve nil e a taxa informada é mantida
+func (ps *PrecificacaoService) carregarEntrega(ctx context.Context, in dto.PedidoCotacaoDTO) (*entregaCotacao, error) {
+	if in.TipoEntrega != "Delivery" {
+		return nil, nil
+	}
+	zonas, err := carregarZonasEntrega(ctx, ps.queries, in.TenantID)
+	if err != nil || zonas == nil {
+		return nil, err
+	}
+	endereco, err := enderecoDoPedido(ctx, ps.queries, in.TenantID, dto.EnderecoEntregaDTO{
+		IDCliente: in.IDCliente,
+		Cep:       in.Cep,
+		Bairro:    in.Bairro,
+		Lat:       in.Lat,
+		Lng:       in.Lng,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entregaCotacao{zonas: zonas, endereco: endereco}, nil
+}
+
+// resolverZonaEntrega aponta endereço fora de todas as zonas e pedido
+// abaixo do mínimo da zona
+func resolverZonaEntrega(c *cotacao, e *entregaCotacao, subtotal int64) *dto.EntregaResolvidaResponse {
+	if e.endereco.vazio() {
+		c.problema(nil, nil, "entrega", dto.ProblemaEnderecoIncompleto, "endereço de entrega sem cep, bairro ou coordenadas")
+		return nil
+	}
+	zona, distancia := e.zonas.resolver(e.endereco)
+	if zona == nil {
+		c.problema(nil, nil, "entrega", dto.ProblemaForaDaArea, "endereço fora da área de entrega")
+		return nil
+	}
+	out := dto.ZonaEntregaToResolvida(*zona, distancia)
+	if minimo := decimalutils.ToCentavos(out.ValorMinimoPedido); subtotal < minimo {
+		c.problema(nil, nil, "entrega", dto.ProblemaEntregaValorMinimo, "pedido mínimo para %s é %s", zona.Nome, out.ValorMinimoPedido.String())
+		return nil
+	}
+	return &out
+}
+
+// resolver devolve a primeira zona que atende o endereço e, quando há
+// coordenadas dos dois lados, a distância da loja
+func (z *zonasEntrega) resolver(e enderecoEntrega) (*pgstore.ZonasEntrega, *float64) {
+	var distancia *float64
+	if z.origem != nil && e.ponto != nil {
+		d := geoutils.DistanciaKm(*z.origem, *e.ponto)
+		distancia = &d
+	}
+
+	for i := range z.zonas {
+		zona := &z.zonas[i]
+		atende := false
+		switch zona.Tipo {
+		case dto.ZonaPorBairro:
+			atende = e.bairro != "" && contemBairro(zona.Bairros, e.bairro)
+		case dto.ZonaPorCep:
+			atende = e.cep != "" && zona.CepInicio.Valid && zona.CepFim.Valid &&
+				e.cep >= zona.CepInicio.String && e.cep <= zona.CepFim.String
+		case dto.ZonaPorRaio:
+			if distancia != nil && zona.RaioKm.Valid {
+				raio, _ := zona.RaioKm.Float64Value()
+				atende = *distancia <= raio.Float64
+			}
+		case dto.ZonaPorPoligono:
+			atende = e.ponto != nil && geoutils.DentroDoPoligono(*e.ponto, z.poligono[zona.ID])
+		}
+		if atende {
+			return zona, distancia
+		}
+	}
+	return nil, distancia
+}
+
+// enderecoDoPedido monta o endereço a partir do informado; cep e bairro em
+// branco vêm do cadastro do cliente
+func enderecoDoPedido(ctx context.Context, q *pgstore.Queries, tenantID uuid.UUID, in dto.EnderecoEntregaDTO) (enderecoEntrega, error) {
+	var e enderecoEntrega
+	if in.Cep != nil {
+		e.cep = normalizarCep(*in.Cep)
+	}
+	if in.Bairro != nil {
+		e.bairro = normalizarBairro(*in.Bairro)
+	}
+	if in.Lat != nil && in.Lng != nil {
+		p := geoutils.Ponto{Lat: *in.Lat, Lng: *in.Lng}
+		if p.Valido() {
+			e.ponto = &p
+		}
+	}
+
+	if (e.cep == "" || e.bairro == "") && in.IDCliente != nil {
+		idCliente, err := uuid.Parse(*in.IDCliente)
+		if err != nil {
+			return e, nil
+		}
+		cliente, err := q.GetClienteEnderecoEntrega(ctx, pgstore.GetClienteEnderecoEntregaParams{
+			ID:       idCliente,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return e, nil
+			}
+			return e, err
+		}
+		if e.cep == "" && cliente.Cep.Valid {
+			e.cep = normalizarCep(cliente.Cep.String)
+		}
+		if e.bairro == "" && cliente.Bairro.Valid {
+			e.bairro = normalizarBairro(cliente.Bairro.String)
+		}
+	}
+	return e, nil
+}
+
+// normalizarCep mantém só os dígitos; CEP incompleto é descartado
+func normalizarCep(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() != 8 {
+		return ""
+	}
+	return b.String()
+}
+
+// normalizarBairro: minúsculas, sem acento e com espaços simples
+func normalizarBairro(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	sem, _, err := transform.String(t, s)
+	if err != nil {
+		sem = s
+	}
+	return strings.Join(strings.Fields(strings.ToLower(sem)), " ")
+}
+
+func contemBairro(bairros []string, bairro string) bool {
+	for _, b := range bairros {
+		if b == bairro {
+			return true
+		}
+	}
+	return false
+}
+
+// decodificarPoligono lê o jsonb [[lat, lng], ...]
+func decodificarPoligono(raw []byte) ([]geoutils.Ponto, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var pares [][2]float64
+	if err := json.Unmarshal(raw, &pares); err != nil {
+		return nil, err
+	}
+	pontos := make([]geoutils.Ponto, len(pares))
+	for i, p := range pares {
+		pontos[i] = geoutils.Ponto{Lat: p[0], Lng: p[1]}
+	}
+	return pontos, nil
+}
+
+func codificarPoligono(pontos []geoutils.Ponto) ([]byte, error) {
+	if len(pontos) == 0 {
+		return nil, nil
+	}
+	pares := make([][2]float64, len(pontos))
+	for i, p := range pontos {
+		pares[i] = [2]float64{p.Lat, p.Lng}
+	}
+	return json.Marshal(pares)
+}
+
+func pontoDeNumeric(lat, lng pgtype.Numeric) *geoutils.Ponto {
+	if !lat.Valid || !lng.Valid {
+		return nil
+	}
+	la, err := lat.Float64Value()
+	if err != nil {
+		return nil
+	}
+	lo, err := lng.Float64Value()
+	if err != nil {
+		return nil
+	}
+	p := geoutils.Ponto{Lat: la.Float64, Lng: lo.Float64}
+	if !p.Valido() {
+		return nil
+	}
+	return &p
+}
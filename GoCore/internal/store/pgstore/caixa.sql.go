@@ -12,22 +12,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const fecharCaixa = `-- name: FecharCaixa :exec
+const fecharCaixa = `-- name: FecharCaixa :one
 UPDATE caixas
 SET status = 'F',
     data_fechamento = now(),
     observacao_fechamento = $2
 WHERE id = $1
+  AND tenant_id = $3
+  AND status = 'A'
+RETURNING id, seq_id, tenant_id, id_operador, data_abertura, data_fechamento, valor_abertura, observacao_abertura, observacao_fechamento, status, created_at, updated_at, deleted_at
 `
 
 type FecharCaixaParams struct {
 	ID                   uuid.UUID   `json:"id"`
 	ObservacaoFechamento pgtype.Text `json:"observacao_fechamento"`
+	TenantID             uuid.UUID   `json:"tenant_id"`
 }
 
-func (q *Queries) FecharCaixa(ctx context.Context, arg FecharCaixaParams) error {
-	_, err := q.db.Exec(ctx, fecharCaixa, arg.ID, arg.ObservacaoFechamento)
-	return err
+func (q *Queries) FecharCaixa(ctx context.Context, arg FecharCaixaParams) (Caixa, error) {
+	row := q.db.QueryRow(ctx, fecharCaixa, arg.ID, arg.ObservacaoFechamento, arg.TenantID)
+	var i Caixa
+	err := row.Scan(
+		&i.ID,
+		&i.SeqID,
+		&i.TenantID,
+		&i.IDOperador,
+		&i.DataAbertura,
+		&i.DataFechamento,
+		&i.ValorAbertura,
+		&i.ObservacaoAbertura,
+		&i.ObservacaoFechamento,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getCaixaAbertosPorTenant = `-- name: GetCaixaAbertosPorTenant :many
@@ -0,0 +1,36 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – controle de entrega do outbox (retry / dead-letter)
+   ========================================================= */
+ALTER TABLE public.outbox_event
+  ADD COLUMN next_attempt_at timestamptz NOT NULL DEFAULT now(),  -- backoff exponencial
+  ADD COLUMN processed_at    timestamptz,
+  ADD COLUMN dead_letter     boolean     NOT NULL DEFAULT false;  -- evento "venenoso"
+
+-- O dispatcher busca por próximos elegíveis, ignorando DLQ
+DROP INDEX IF EXISTS idx_outbox_unprocessed;
+
+CREATE INDEX idx_outbox_pending
+        ON public.outbox_event (next_attempt_at, created_at)
+     WHERE processed = false AND dead_letter = false;
+
+CREATE INDEX idx_outbox_dead_letter
+        ON public.outbox_event (tenant_id, created_at)
+     WHERE dead_letter = true;
+
+COMMENT ON COLUMN public.outbox_event.next_attempt_at IS 'Próxima tentativa de entrega (backoff exponencial)';
+COMMENT ON COLUMN public.outbox_event.dead_letter IS 'true = excedeu o máximo de tentativas e não será mais entregue';
+---- create above / drop below ----
+DROP INDEX IF EXISTS idx_outbox_dead_letter;
+DROP INDEX IF EXISTS idx_outbox_pending;
+
+ALTER TABLE public.outbox_event
+  DROP COLUMN IF EXISTS dead_letter,
+  DROP COLUMN IF EXISTS processed_at,
+  DROP COLUMN IF EXISTS next_attempt_at;
+
+CREATE INDEX idx_outbox_unprocessed
+        ON public.outbox_event (created_at)
+     WHERE processed = false;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,58 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – assinaturas de webhook por tenant + log de entregas
+   ========================================================= */
+CREATE TABLE public.webhook_subscriptions
+(
+    id          uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   uuid        NOT NULL REFERENCES public.tenants (id),
+    url         text        NOT NULL,
+    secret      text        NOT NULL,                 -- chave do HMAC-SHA256
+    event_types text[]      NOT NULL DEFAULT '{}',    -- vazio = todos os eventos
+    descricao   text,
+    ativo       boolean     NOT NULL DEFAULT true,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    deleted_at  timestamptz
+);
+
+CREATE INDEX idx_webhook_subscriptions_tenant
+        ON public.webhook_subscriptions (tenant_id)
+     WHERE deleted_at IS NULL AND ativo = true;
+
+COMMENT ON COLUMN public.webhook_subscriptions.event_types IS 'Filtro de event_type (ex.: pedido.created). Vazio = recebe todos';
+
+CREATE TABLE public.webhook_deliveries
+(
+    id               bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    tenant_id        uuid        NOT NULL REFERENCES public.tenants (id),
+    subscription_id  uuid        NOT NULL REFERENCES public.webhook_subscriptions (id),
+    event_id         bigint      NOT NULL,            -- outbox_event.id (sem FK: o outbox é limpo periodicamente)
+    event_type       text        NOT NULL,
+    payload          jsonb       NOT NULL,            -- corpo enviado (permite reentrega manual)
+    url              text        NOT NULL,
+    status_code      int,
+    response_body    text,                            -- truncado
+    error            text,
+    success          boolean     NOT NULL DEFAULT false,
+    duration_ms      int         NOT NULL DEFAULT 0,
+    manual           boolean     NOT NULL DEFAULT false,  -- true = reentrega solicitada pelo usuário
+    created_at       timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription
+        ON public.webhook_deliveries (subscription_id, created_at DESC);
+
+-- O publisher consulta se a assinatura já recebeu o evento com sucesso
+CREATE INDEX idx_webhook_deliveries_event
+        ON public.webhook_deliveries (event_id, subscription_id)
+     WHERE success = true;
+---- create above / drop below ----
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription;
+DROP TABLE IF EXISTS public.webhook_deliveries;
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_tenant;
+DROP TABLE IF EXISTS public.webhook_subscriptions;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
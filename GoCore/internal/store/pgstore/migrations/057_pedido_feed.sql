@@ -0,0 +1,97 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – feed de pedidos em tempo real (LISTEN/NOTIFY)
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Log curto dos eventos, usado para retomar a partir do
+--    Last-Event-ID quando o cliente reconecta
+------------------------------------------------------------
+CREATE TABLE public.pedido_feed
+(
+    id          bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    tenant_id   uuid        NOT NULL,
+    pedido_id   uuid        NOT NULL,
+    event_type  text        NOT NULL,   -- pedido.created | pedido.updated | ...
+    payload     jsonb       NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pedido_feed_tenant_id
+        ON public.pedido_feed (tenant_id, id);
+
+CREATE INDEX idx_pedido_feed_created_at
+        ON public.pedido_feed (created_at);
+
+------------------------------------------------------------
+-- 2) Trigger: grava no feed e notifica o canal pedido_feed.
+--    O NOTIFY só é entregue no COMMIT da transação.
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.notify_pedido_feed()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_event   TEXT;
+    v_id      BIGINT;
+    v_payload JSONB;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        v_event := 'pedido.created';
+    ELSIF NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN
+        v_event := 'pedido.deleted';
+    ELSIF NEW.id_status IS DISTINCT FROM OLD.id_status THEN
+        v_event := 'pedido.status_changed';
+    ELSIF NEW.pedido_pronto IS DISTINCT FROM OLD.pedido_pronto THEN
+        v_event := 'pedido.pronto';
+    ELSE
+        -- ignora updates que só tocaram updated_at
+        IF (to_jsonb(NEW) - 'updated_at') = (to_jsonb(OLD) - 'updated_at') THEN
+            RETURN NULL;
+        END IF;
+        v_event := 'pedido.updated';
+    END IF;
+
+    v_payload := jsonb_build_object(
+        'id',                 NEW.id,
+        'codigo_pedido',      NEW.codigo_pedido,
+        'id_cliente',         NEW.id_cliente,
+        'data_pedido',        NEW.data_pedido,
+        'tipo_entrega',       NEW.tipo_entrega,
+        'id_status',          NEW.id_status,
+        'status_anterior',    CASE WHEN TG_OP = 'UPDATE' THEN OLD.id_status END,
+        'pedido_pronto',      NEW.pedido_pronto,
+        'data_pedido_pronto', NEW.data_pedido_pronto,
+        'valor_total',        NEW.valor_total,
+        'deleted',            NEW.deleted_at IS NOT NULL
+    );
+
+    INSERT INTO public.pedido_feed (tenant_id, pedido_id, event_type, payload)
+         VALUES (NEW.tenant_id, NEW.id, v_event, v_payload)
+      RETURNING id INTO v_id;
+
+    PERFORM pg_notify('pedido_feed', json_build_object(
+        'id',         v_id,
+        'tenant_id',  NEW.tenant_id,
+        'pedido_id',  NEW.id,
+        'event_type', v_event,
+        'payload',    v_payload,
+        'created_at', now()
+    )::text);
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_pedidos_notify_feed
+    AFTER INSERT OR UPDATE ON public.pedidos
+    FOR EACH ROW EXECUTE FUNCTION public.notify_pedido_feed();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pedidos_notify_feed ON public.pedidos;
+DROP FUNCTION IF EXISTS public.notify_pedido_feed();
+
+DROP INDEX IF EXISTS idx_pedido_feed_created_at;
+DROP INDEX IF EXISTS idx_pedido_feed_tenant_id;
+DROP TABLE IF EXISTS public.pedido_feed;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
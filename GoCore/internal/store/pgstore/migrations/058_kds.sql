@@ -0,0 +1,106 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – KDS: estações de produção e estado de preparo por item
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Estações de produção (pizzaria, bar, sobremesas...)
+------------------------------------------------------------
+CREATE TABLE public.estacoes_producao
+(
+    id          uuid         PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   uuid         NOT NULL REFERENCES public.tenants (id),
+    nome        varchar(100) NOT NULL,
+    ordem       integer      NOT NULL DEFAULT 0,
+    ativo       boolean      NOT NULL DEFAULT true,
+    created_at  timestamptz  NOT NULL DEFAULT now(),
+    updated_at  timestamptz  NOT NULL DEFAULT now(),
+    deleted_at  timestamptz
+);
+
+CREATE INDEX idx_estacoes_producao_tenant
+        ON public.estacoes_producao (tenant_id)
+     WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_estacoes_producao_update_updated_at
+    BEFORE UPDATE ON public.estacoes_producao
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 2) Roteamento: produto sobrepõe a categoria
+------------------------------------------------------------
+ALTER TABLE public.categorias
+  ADD COLUMN id_estacao uuid REFERENCES public.estacoes_producao (id);
+
+ALTER TABLE public.produtos
+  ADD COLUMN id_estacao uuid REFERENCES public.estacoes_producao (id);
+
+COMMENT ON COLUMN public.categorias.id_estacao IS 'Estação de produção padrão dos produtos da categoria';
+COMMENT ON COLUMN public.produtos.id_estacao IS 'Estação de produção do produto (sobrepõe a da categoria)';
+
+------------------------------------------------------------
+-- 3) Estado de preparo por item
+--    queued → preparing → done → bumped (retirado da tela)
+------------------------------------------------------------
+ALTER TABLE public.pedido_itens
+  ADD COLUMN id_estacao          uuid REFERENCES public.estacoes_producao (id),
+  ADD COLUMN status_preparo      varchar(12) NOT NULL DEFAULT 'queued',
+  ADD COLUMN data_inicio_preparo timestamptz,
+  ADD COLUMN data_fim_preparo    timestamptz,
+  ADD COLUMN data_despacho       timestamptz,
+  ADD CONSTRAINT chk_pedido_itens_status_preparo
+      CHECK (status_preparo IN ('queued', 'preparing', 'done', 'bumped'));
+
+-- Itens já existentes não devem aparecer nas filas
+UPDATE public.pedido_itens
+   SET status_preparo = 'bumped'
+ WHERE status_preparo = 'queued';
+
+CREATE INDEX idx_pedido_itens_fila_preparo
+        ON public.pedido_itens (id_estacao, status_preparo)
+     WHERE deleted_at IS NULL AND status_preparo IN ('queued', 'preparing', 'done');
+
+------------------------------------------------------------
+-- 4) Define a estação do item na inserção
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.set_estacao_pedido_item()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.id_estacao IS NULL THEN
+        SELECT COALESCE(p.id_estacao, c.id_estacao)
+          INTO NEW.id_estacao
+          FROM public.produtos p
+          JOIN public.categorias c ON c.id = p.id_categoria
+         WHERE p.id = NEW.id_produto;
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pedido_itens_set_estacao
+    BEFORE INSERT ON public.pedido_itens
+    FOR EACH ROW EXECUTE FUNCTION public.set_estacao_pedido_item();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pedido_itens_set_estacao ON public.pedido_itens;
+DROP FUNCTION IF EXISTS public.set_estacao_pedido_item();
+
+DROP INDEX IF EXISTS idx_pedido_itens_fila_preparo;
+
+ALTER TABLE public.pedido_itens
+  DROP CONSTRAINT IF EXISTS chk_pedido_itens_status_preparo,
+  DROP COLUMN IF EXISTS data_despacho,
+  DROP COLUMN IF EXISTS data_fim_preparo,
+  DROP COLUMN IF EXISTS data_inicio_preparo,
+  DROP COLUMN IF EXISTS status_preparo,
+  DROP COLUMN IF EXISTS id_estacao;
+
+ALTER TABLE public.produtos   DROP COLUMN IF EXISTS id_estacao;
+ALTER TABLE public.categorias DROP COLUMN IF EXISTS id_estacao;
+
+DROP TRIGGER IF EXISTS trg_estacoes_producao_update_updated_at ON public.estacoes_producao;
+DROP INDEX IF EXISTS idx_estacoes_producao_tenant;
+DROP TABLE IF EXISTS public.estacoes_producao;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
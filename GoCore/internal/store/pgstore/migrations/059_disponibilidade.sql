@@ -0,0 +1,21 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Disponibilidade do cardápio por horário do tenant
+   ========================================================= */
+
+-- categorias.inicio/fim e disponivel_* são avaliados no fuso do tenant
+ALTER TABLE public.tenants
+  ADD COLUMN timezone varchar(64) NOT NULL DEFAULT 'America/Sao_Paulo';
+
+COMMENT ON COLUMN public.tenants.timezone IS 'Fuso horário IANA usado para avaliar a disponibilidade do cardápio';
+
+-- Pedidos criados fora da disponibilidade por liberação do gerente
+ALTER TABLE public.pedidos
+  ADD COLUMN disponibilidade_liberada_por uuid REFERENCES public.users (id);
+
+COMMENT ON COLUMN public.pedidos.disponibilidade_liberada_por IS 'Usuário (gerente) que liberou itens fora da janela de disponibilidade';
+---- create above / drop below ----
+ALTER TABLE public.pedidos DROP COLUMN IF EXISTS disponibilidade_liberada_por;
+ALTER TABLE public.tenants DROP COLUMN IF EXISTS timezone;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,50 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Histórico de status do pedido
+   ========================================================= */
+
+CREATE TABLE public.pedido_status_historico
+(
+    id              uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       uuid        NOT NULL REFERENCES public.tenants (id),
+    id_pedido       uuid        NOT NULL REFERENCES public.pedidos (id) ON DELETE CASCADE,
+    status_anterior smallint    REFERENCES public.pedido_status (id),
+    status_novo     smallint    NOT NULL REFERENCES public.pedido_status (id),
+    user_id         uuid        REFERENCES public.users (id),
+    motivo          text,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE  public.pedido_status_historico IS 'Transições de status do pedido, para auditoria e tempos de preparo/entrega';
+COMMENT ON COLUMN public.pedido_status_historico.status_anterior IS 'Status antes da transição (nulo no registro de criação)';
+COMMENT ON COLUMN public.pedido_status_historico.user_id IS 'Usuário que fez a transição (nulo quando automática)';
+
+CREATE INDEX idx_pedido_status_historico_pedido
+        ON public.pedido_status_historico (id_pedido, created_at);
+
+-- Registro inicial de cada pedido; as transições são gravadas pela aplicação
+CREATE OR REPLACE FUNCTION public.registrar_status_inicial_pedido()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    INSERT INTO public.pedido_status_historico (tenant_id, id_pedido, status_novo, created_at)
+    VALUES (NEW.tenant_id, NEW.id, NEW.id_status, NEW.created_at);
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pedidos_status_inicial
+    AFTER INSERT ON public.pedidos
+    FOR EACH ROW EXECUTE FUNCTION public.registrar_status_inicial_pedido();
+
+-- Pedidos existentes: só o status atual, na data de criação
+INSERT INTO public.pedido_status_historico (tenant_id, id_pedido, status_novo, created_at)
+SELECT tenant_id, id, id_status, created_at
+FROM   public.pedidos;
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pedidos_status_inicial ON public.pedidos;
+DROP FUNCTION IF EXISTS public.registrar_status_inicial_pedido();
+DROP TABLE IF EXISTS public.pedido_status_historico;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
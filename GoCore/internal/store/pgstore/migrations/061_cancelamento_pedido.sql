@@ -0,0 +1,593 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Cancelamento de pedido com estorno de pagamentos,
+        estorno no caixa e cancelamento de parcelas
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Parcelas canceladas deixam de compor o saldo do pedido
+------------------------------------------------------------
+ALTER TABLE public.contas_receber
+  ADD COLUMN cancelado_em        timestamptz,
+  ADD COLUMN motivo_cancelamento text;
+
+COMMENT ON COLUMN public.contas_receber.cancelado_em IS 'Data do cancelamento da parcela (cancelamento do pedido)';
+
+CREATE OR REPLACE FUNCTION public.recalcular_pagamentos(p_pedido_id uuid)
+RETURNS void
+LANGUAGE plpgsql AS $$
+DECLARE
+    v_total             numeric(10,2);
+    v_valor_pago        numeric(10,2);
+    v_saldo_parcelas    numeric(10,2);
+BEGIN
+    SELECT valor_total
+         + COALESCE(taxa_entrega,0)
+         + COALESCE(acrescimo,0)
+         - COALESCE(desconto,0)
+      INTO v_total
+      FROM public.pedidos
+     WHERE id = p_pedido_id;
+
+    SELECT COALESCE(SUM(valor_pago - troco),0)
+      INTO v_valor_pago
+      FROM public.pedido_pagamentos
+     WHERE id_pedido = p_pedido_id
+       AND deleted_at IS NULL;
+
+    SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+      INTO v_saldo_parcelas
+      FROM public.contas_receber
+     WHERE id_pedido = p_pedido_id
+       AND cancelado_em IS NULL;
+
+    UPDATE public.pedidos
+       SET valor_pago = v_valor_pago,
+           quitado    = (v_valor_pago >= v_total),
+           finalizado = (v_valor_pago >= v_total) OR (v_saldo_parcelas > 0),
+           updated_at = now()
+     WHERE id = p_pedido_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_pagamento_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago_anteriores  numeric(10,2);
+  v_saldo_parcelas   numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* pagamentos que ABATEM parcela são ignorados aqui */
+  IF NEW.id_conta_receber IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  /* estorno (soft delete) não soma ao pago */
+  IF NEW.deleted_at IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago_anteriores
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_parcelas
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL;
+
+  v_restante := v_total - v_pago_anteriores - v_saldo_parcelas;
+
+  IF (NEW.valor_pago - NEW.troco) > v_restante THEN
+     RAISE EXCEPTION
+       'Pagamento %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_pago - NEW.troco, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_parcela_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago             numeric(10,2);
+  v_saldo_outros     numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* Se UPDATE e valor_devido não mudou -> ignora */
+  IF TG_OP = 'UPDATE'
+     AND NEW.valor_devido IS NOT DISTINCT FROM OLD.valor_devido THEN
+        RETURN NEW;
+  END IF;
+
+  IF NEW.cancelado_em IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL;
+
+  /* saldo das demais parcelas */
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_outros
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  v_restante := v_total - v_pago - v_saldo_outros;
+
+  IF NEW.valor_devido > v_restante THEN
+     RAISE EXCEPTION
+       'Parcela %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_devido, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+------------------------------------------------------------
+-- 2) Pedido travado (quitado/finalizado) ainda pode ser
+--    cancelado: o cancelamento estorna tudo na mesma transação
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.enforce_pedido_nao_editavel()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_pedido_id uuid;
+    v_locked    boolean;
+    v_changed_other boolean := false;
+BEGIN
+    IF TG_TABLE_NAME = 'pedidos' THEN
+        v_pedido_id := COALESCE(NEW.id, OLD.id);
+    ELSIF TG_TABLE_NAME = 'pedido_itens' THEN
+        v_pedido_id := COALESCE(NEW.id_pedido, OLD.id_pedido);
+    ELSIF TG_TABLE_NAME = 'pedido_item_adicionais' THEN
+        SELECT id_pedido
+          INTO v_pedido_id
+          FROM public.pedido_itens
+         WHERE id = COALESCE(NEW.id_pedido_item, OLD.id_pedido_item);
+    END IF;
+
+    SELECT (finalizado OR quitado)
+      INTO v_locked
+      FROM public.pedidos
+     WHERE id = v_pedido_id;
+
+    IF v_locked THEN
+       IF TG_TABLE_NAME = 'pedidos' AND TG_OP = 'UPDATE' THEN
+          v_changed_other :=
+                (NEW.valor_total      IS DISTINCT FROM  OLD.valor_total)
+             OR (NEW.taxa_entrega     IS DISTINCT FROM  OLD.taxa_entrega)
+             OR (NEW.desconto         IS DISTINCT FROM  OLD.desconto)
+             OR (NEW.acrescimo        IS DISTINCT FROM  OLD.acrescimo)
+             OR (NEW.observacao       IS DISTINCT FROM  OLD.observacao)
+             OR (NEW.tipo_entrega     IS DISTINCT FROM  OLD.tipo_entrega)
+             OR (NEW.prazo            IS DISTINCT FROM  OLD.prazo)
+             OR (NEW.prazo_min        IS DISTINCT FROM  OLD.prazo_min)
+             OR (NEW.prazo_max        IS DISTINCT FROM  OLD.prazo_max)
+             OR (NEW.id_status        IS DISTINCT FROM  OLD.id_status
+                 AND NEW.id_status <> 6)                                -- 6 = Cancelado
+             OR (NEW.deleted_at       IS DISTINCT FROM  OLD.deleted_at);
+
+          IF v_changed_other THEN
+             RAISE EXCEPTION
+               'Pedido % já está finalizado/quitado: alterações não permitidas',
+               v_pedido_id USING ERRCODE = 'P0001';
+          END IF;
+
+       ELSIF TG_TABLE_NAME = 'pedidos' AND TG_OP = 'DELETE' THEN
+          RAISE EXCEPTION
+            'Pedido % já está finalizado/quitado: exclusão não permitida',
+            v_pedido_id USING ERRCODE = 'P0001';
+
+       ELSIF TG_TABLE_NAME <> 'pedidos' THEN
+          RAISE EXCEPTION
+            'Pedido % já está finalizado/quitado: alterações em itens não permitidas',
+            v_pedido_id USING ERRCODE = 'P0001';
+       END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+------------------------------------------------------------
+-- 3) Caixa: movimento de estorno (E) e caixa fechado intocável
+------------------------------------------------------------
+ALTER TABLE public.caixa_movimentacoes
+  DROP CONSTRAINT caixa_movimentacoes_tipo_check,
+  DROP CONSTRAINT caixa_mov_pagto_forma_chk;
+
+ALTER TABLE public.caixa_movimentacoes
+  ADD CONSTRAINT caixa_movimentacoes_tipo_check
+      CHECK (tipo IN ('S','U','P','E')),             -- E=Estorno de pagamento
+  ADD CONSTRAINT caixa_mov_valor_estorno_chk
+      CHECK (tipo <> 'E' OR valor > 0),
+  ADD CONSTRAINT caixa_mov_pagto_forma_chk CHECK (
+      (tipo IN ('P','E') AND id_forma_pagamento IS NOT NULL) OR
+      (tipo IN ('S','U') AND id_forma_pagamento IS NULL)
+  );
+
+COMMENT ON COLUMN public.caixa_movimentacoes.tipo IS 'S=Sangria (saída), U=Suprimento (entrada), P=Pagamento (entrada), E=Estorno de pagamento (saída)';
+
+-- Estorno abate da forma de pagamento original
+CREATE OR REPLACE FUNCTION public.calcular_valor_esperado_forma(p_caixa_id uuid, p_forma_pagamento_id smallint)
+RETURNS numeric(10,2)
+LANGUAGE plpgsql
+STABLE
+AS $$
+DECLARE
+    v_total_pagamentos numeric(10,2);
+    v_total_sang_sup   numeric(10,2);
+    v_valor_abertura   numeric(10,2);
+BEGIN
+    v_total_pagamentos := 0;
+    v_total_sang_sup   := 0;
+    v_valor_abertura   := 0;
+
+    IF p_forma_pagamento_id = 1 THEN  -- DINHEIRO
+        SELECT valor_abertura INTO v_valor_abertura
+          FROM public.caixas
+         WHERE id = p_caixa_id;
+
+        SELECT COALESCE(SUM(CASE WHEN tipo='S' THEN -valor WHEN tipo='U' THEN valor END),0)
+          INTO v_total_sang_sup
+          FROM public.caixa_movimentacoes
+         WHERE id_caixa = p_caixa_id
+           AND tipo IN ('S','U')
+           AND deleted_at IS NULL;
+    END IF;
+
+    SELECT COALESCE(SUM(CASE WHEN tipo='P' THEN valor ELSE -valor END),0)
+      INTO v_total_pagamentos
+      FROM public.caixa_movimentacoes
+     WHERE id_caixa = p_caixa_id
+       AND tipo IN ('P','E')
+       AND id_forma_pagamento = p_forma_pagamento_id
+       AND deleted_at IS NULL;
+
+    RETURN v_valor_abertura + v_total_sang_sup + v_total_pagamentos;
+END;
+$$;
+
+-- Pagamento alterado/removido só some do caixa se o caixa ainda estiver
+-- aberto; de caixa fechado o estorno é lançado no caixa atual (tipo E)
+CREATE OR REPLACE FUNCTION public.estornar_pagamento_caixa()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM public.caixa_movimentacoes cm
+     USING public.caixas c
+     WHERE cm.id_pagamento = OLD.id
+       AND cm.tipo = 'P'
+       AND cm.deleted_at IS NULL
+       AND c.id = cm.id_caixa
+       AND c.status = 'A';
+    RETURN OLD;
+END;
+$$;
+
+------------------------------------------------------------
+-- 4) Registro de auditoria do cancelamento
+------------------------------------------------------------
+CREATE TABLE public.pedido_cancelamentos
+(
+    id               uuid          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id        uuid          NOT NULL REFERENCES public.tenants (id),
+    id_pedido        uuid          NOT NULL UNIQUE REFERENCES public.pedidos (id) ON DELETE CASCADE,
+    status_anterior  smallint      NOT NULL REFERENCES public.pedido_status (id),
+    motivo           text          NOT NULL,
+    user_id          uuid          REFERENCES public.users (id),
+    autorizado_por   uuid          REFERENCES public.users (id),
+    valor_estornado  numeric(10,2) NOT NULL DEFAULT 0,
+    pagamentos       jsonb         NOT NULL DEFAULT '[]',
+    parcelas         jsonb         NOT NULL DEFAULT '[]',
+    created_at       timestamptz   NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE  public.pedido_cancelamentos IS 'Auditoria dos cancelamentos de pedido';
+COMMENT ON COLUMN public.pedido_cancelamentos.autorizado_por IS 'Gerente que autorizou o cancelamento de pedido com pagamento';
+COMMENT ON COLUMN public.pedido_cancelamentos.pagamentos IS 'Pagamentos estornados e como cada um saiu do caixa';
+COMMENT ON COLUMN public.pedido_cancelamentos.parcelas IS 'Parcelas de contas a receber canceladas';
+
+CREATE INDEX idx_pedido_cancelamentos_tenant
+        ON public.pedido_cancelamentos (tenant_id, created_at);
+---- create above / drop below ----
+DROP TABLE IF EXISTS public.pedido_cancelamentos;
+
+CREATE OR REPLACE FUNCTION public.estornar_pagamento_caixa()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM public.caixa_movimentacoes
+     WHERE id_pagamento = OLD.id
+       AND tipo='P'
+       AND deleted_at IS NULL;
+    RETURN OLD;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.calcular_valor_esperado_forma(p_caixa_id uuid, p_forma_pagamento_id smallint)
+RETURNS numeric(10,2)
+LANGUAGE plpgsql
+STABLE
+AS $$
+DECLARE
+    v_total_pagamentos numeric(10,2);
+    v_total_sang_sup   numeric(10,2);
+    v_valor_abertura   numeric(10,2);
+BEGIN
+    v_total_pagamentos := 0;
+    v_total_sang_sup   := 0;
+    v_valor_abertura   := 0;
+
+    IF p_forma_pagamento_id = 1 THEN  -- DINHEIRO
+        SELECT valor_abertura INTO v_valor_abertura
+          FROM public.caixas
+         WHERE id = p_caixa_id;
+
+        SELECT COALESCE(SUM(CASE WHEN tipo='S' THEN -valor WHEN tipo='U' THEN valor END),0)
+          INTO v_total_sang_sup
+          FROM public.caixa_movimentacoes
+         WHERE id_caixa = p_caixa_id
+           AND tipo IN ('S','U')
+           AND deleted_at IS NULL;
+    END IF;
+
+    SELECT COALESCE(SUM(valor),0)
+      INTO v_total_pagamentos
+      FROM public.caixa_movimentacoes
+     WHERE id_caixa = p_caixa_id
+       AND tipo = 'P'
+       AND id_forma_pagamento = p_forma_pagamento_id
+       AND deleted_at IS NULL;
+
+    RETURN v_valor_abertura + v_total_sang_sup + v_total_pagamentos;
+END;
+$$;
+
+DELETE FROM public.caixa_movimentacoes WHERE tipo = 'E';
+
+ALTER TABLE public.caixa_movimentacoes
+  DROP CONSTRAINT caixa_mov_pagto_forma_chk,
+  DROP CONSTRAINT caixa_mov_valor_estorno_chk,
+  DROP CONSTRAINT caixa_movimentacoes_tipo_check;
+
+ALTER TABLE public.caixa_movimentacoes
+  ADD CONSTRAINT caixa_movimentacoes_tipo_check CHECK (tipo IN ('S','U','P')),
+  ADD CONSTRAINT caixa_mov_pagto_forma_chk CHECK (
+      (tipo='P' AND id_forma_pagamento IS NOT NULL) OR
+      (tipo IN ('S','U') AND id_forma_pagamento IS NULL)
+  );
+
+CREATE OR REPLACE FUNCTION public.enforce_pedido_nao_editavel()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_pedido_id uuid;
+    v_locked    boolean;
+    v_changed_other boolean := false;
+BEGIN
+    IF TG_TABLE_NAME = 'pedidos' THEN
+        v_pedido_id := COALESCE(NEW.id, OLD.id);
+    ELSIF TG_TABLE_NAME = 'pedido_itens' THEN
+        v_pedido_id := COALESCE(NEW.id_pedido, OLD.id_pedido);
+    ELSIF TG_TABLE_NAME = 'pedido_item_adicionais' THEN
+        SELECT id_pedido
+          INTO v_pedido_id
+          FROM public.pedido_itens
+         WHERE id = COALESCE(NEW.id_pedido_item, OLD.id_pedido_item);
+    END IF;
+
+    SELECT (finalizado OR quitado)
+      INTO v_locked
+      FROM public.pedidos
+     WHERE id = v_pedido_id;
+
+    IF v_locked THEN
+       IF TG_TABLE_NAME = 'pedidos' AND TG_OP = 'UPDATE' THEN
+          v_changed_other :=
+                (NEW.valor_total      IS DISTINCT FROM  OLD.valor_total)
+             OR (NEW.taxa_entrega     IS DISTINCT FROM  OLD.taxa_entrega)
+             OR (NEW.desconto         IS DISTINCT FROM  OLD.desconto)
+             OR (NEW.acrescimo        IS DISTINCT FROM  OLD.acrescimo)
+             OR (NEW.observacao       IS DISTINCT FROM  OLD.observacao)
+             OR (NEW.tipo_entrega     IS DISTINCT FROM  OLD.tipo_entrega)
+             OR (NEW.prazo            IS DISTINCT FROM  OLD.prazo)
+             OR (NEW.prazo_min        IS DISTINCT FROM  OLD.prazo_min)
+             OR (NEW.prazo_max        IS DISTINCT FROM  OLD.prazo_max)
+             OR (NEW.id_status        IS DISTINCT FROM  OLD.id_status)
+             OR (NEW.deleted_at       IS DISTINCT FROM  OLD.deleted_at);
+
+          IF v_changed_other THEN
+             RAISE EXCEPTION
+               'Pedido % já está finalizado/quitado: alterações não permitidas',
+               v_pedido_id USING ERRCODE = 'P0001';
+          END IF;
+
+       ELSIF TG_TABLE_NAME = 'pedidos' AND TG_OP = 'DELETE' THEN
+          RAISE EXCEPTION
+            'Pedido % já está finalizado/quitado: exclusão não permitida',
+            v_pedido_id USING ERRCODE = 'P0001';
+
+       ELSIF TG_TABLE_NAME <> 'pedidos' THEN
+          RAISE EXCEPTION
+            'Pedido % já está finalizado/quitado: alterações em itens não permitidas',
+            v_pedido_id USING ERRCODE = 'P0001';
+       END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_parcela_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago             numeric(10,2);
+  v_saldo_outros     numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  IF TG_OP = 'UPDATE'
+     AND NEW.valor_devido IS NOT DISTINCT FROM OLD.valor_devido THEN
+        RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL;
+
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_outros
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  v_restante := v_total - v_pago - v_saldo_outros;
+
+  IF NEW.valor_devido > v_restante THEN
+     RAISE EXCEPTION
+       'Parcela %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_devido, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_pagamento_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago_anteriores  numeric(10,2);
+  v_saldo_parcelas   numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  IF NEW.id_conta_receber IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago_anteriores
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_parcelas
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido;
+
+  v_restante := v_total - v_pago_anteriores - v_saldo_parcelas;
+
+  IF (NEW.valor_pago - NEW.troco) > v_restante THEN
+     RAISE EXCEPTION
+       'Pagamento %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_pago - NEW.troco, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.recalcular_pagamentos(p_pedido_id uuid)
+RETURNS void
+LANGUAGE plpgsql AS $$
+DECLARE
+    v_total             numeric(10,2);
+    v_valor_pago        numeric(10,2);
+    v_saldo_parcelas    numeric(10,2);
+BEGIN
+    SELECT valor_total
+         + COALESCE(taxa_entrega,0)
+         + COALESCE(acrescimo,0)
+         - COALESCE(desconto,0)
+      INTO v_total
+      FROM public.pedidos
+     WHERE id = p_pedido_id;
+
+    SELECT COALESCE(SUM(valor_pago - troco),0)
+      INTO v_valor_pago
+      FROM public.pedido_pagamentos
+     WHERE id_pedido = p_pedido_id
+       AND deleted_at IS NULL;
+
+    SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+      INTO v_saldo_parcelas
+      FROM public.contas_receber
+     WHERE id_pedido = p_pedido_id;
+
+    UPDATE public.pedidos
+       SET valor_pago = v_valor_pago,
+           quitado    = (v_valor_pago >= v_total),
+           finalizado = (v_valor_pago >= v_total) OR (v_saldo_parcelas > 0),
+           updated_at = now()
+     WHERE id = p_pedido_id;
+END;
+$$;
+
+ALTER TABLE public.contas_receber
+  DROP COLUMN IF EXISTS motivo_cancelamento,
+  DROP COLUMN IF EXISTS cancelado_em;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Chaves de idempotência (header Idempotency-Key)
+   ========================================================= */
+
+CREATE TABLE public.idempotency_keys
+(
+    tenant_id       uuid        NOT NULL REFERENCES public.tenants (id) ON DELETE CASCADE,
+    chave           text        NOT NULL,
+    metodo          text        NOT NULL,
+    rota            text        NOT NULL,
+    hash_requisicao text        NOT NULL,
+    status_code     integer,
+    content_type    text,
+    resposta        bytea,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    concluido_em    timestamptz,
+    PRIMARY KEY (tenant_id, chave)
+);
+
+COMMENT ON TABLE  public.idempotency_keys IS 'Respostas guardadas por Idempotency-Key para repetir em reenvios do cliente';
+COMMENT ON COLUMN public.idempotency_keys.hash_requisicao IS 'SHA-256 de método, rota e corpo; outro payload com a mesma chave é rejeitado';
+COMMENT ON COLUMN public.idempotency_keys.status_code IS 'Nulo enquanto a primeira requisição está em processamento';
+
+CREATE INDEX idx_idempotency_keys_created_at
+        ON public.idempotency_keys (created_at);
+---- create above / drop below ----
+DROP TABLE IF EXISTS public.idempotency_keys;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,318 @@
+-- Write your migrate up statements here
+/* =========================================================
+   MIGRATION UP  –  Pizza com N sabores (frações)
+   =========================================================
+   pedido_itens.id_produto_2 só comporta duas metades. Os sabores passam a
+   ficar em pedido_item_sabores, cada um com a sua fração (partes / soma
+   das partes do item). Itens meio-a-meio antigos continuam funcionando:
+   são copiados para a tabela nova como dois sabores de 1/2 e o trigger
+   de meia-pizza continua valendo para quem só grava id_produto_2.
+   ========================================================= */
+
+-- 1) ── Configuração da categoria
+ALTER TABLE public.categorias
+    ADD COLUMN max_sabores smallint NOT NULL DEFAULT 2,
+    ADD CONSTRAINT chk_categorias_max_sabores CHECK (max_sabores BETWEEN 1 AND 8);
+
+COMMENT ON COLUMN public.categorias.opcao_meia IS 'Opção para mais de um sabor (M=Valor médio, V=Maior valor, P=Proporcional à fração, vazio=Não permitido)';
+COMMENT ON COLUMN public.categorias.max_sabores IS
+    'Quantidade máxima de sabores por item (só vale quando opcao_meia permite)';
+
+-- 2) ── Sabores do item
+CREATE SEQUENCE public.pedido_item_sabores_seq_id_seq;
+
+CREATE TABLE public.pedido_item_sabores (
+    id              uuid          DEFAULT gen_random_uuid() NOT NULL,
+    seq_id          bigint        NOT NULL DEFAULT nextval('public.pedido_item_sabores_seq_id_seq'),
+    id_pedido_item  uuid          NOT NULL,
+    id_produto      uuid          NOT NULL,
+    partes          smallint      NOT NULL DEFAULT 1,  -- fração = partes / soma das partes do item
+    valor           numeric(10,2),                     -- preço do sabor na opção do item, gravado na inclusão
+    ordem           smallint      NOT NULL DEFAULT 0,
+    created_at      timestamptz   DEFAULT now() NOT NULL,
+    CONSTRAINT pedido_item_sabores_pkey     PRIMARY KEY(id),
+    CONSTRAINT fk_pis_pedido_item           FOREIGN KEY(id_pedido_item) REFERENCES public.pedido_itens(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pis_produto               FOREIGN KEY(id_produto)     REFERENCES public.produtos(id),
+    CONSTRAINT uq_pis_item_produto          UNIQUE (id_pedido_item, id_produto),
+    CONSTRAINT chk_pis_partes               CHECK (partes > 0)
+);
+
+CREATE INDEX idx_pis_pedido_item ON public.pedido_item_sabores(id_pedido_item);
+
+-- 3) ── Itens meio-a-meio existentes viram dois sabores de 1/2.
+--       Feito antes dos triggers para não recalcular o valor já cobrado.
+INSERT INTO public.pedido_item_sabores (id_pedido_item, id_produto, partes, valor, ordem)
+SELECT pi.id, s.id_produto, 1,
+       public.preco_para_produto_opcao(s.id_produto, pi.id_categoria_opcao),
+       s.ordem
+  FROM public.pedido_itens pi
+ CROSS JOIN LATERAL (VALUES (pi.id_produto, 0), (pi.id_produto_2, 1)) AS s(id_produto, ordem)
+ WHERE pi.id_produto_2 IS NOT NULL
+   AND pi.id_produto_2 <> pi.id_produto;
+
+-- 4) ── Valor do item a partir dos sabores, pela regra da categoria
+CREATE OR REPLACE FUNCTION public.calcular_valor_sabores(p_item uuid)
+RETURNS numeric(10,2) LANGUAGE plpgsql STABLE AS
+$$
+DECLARE
+    v_regra char(1);
+    v_valor numeric(10,2);
+BEGIN
+    SELECT COALESCE(NULLIF(c.opcao_meia, ''), 'V')
+      INTO v_regra
+      FROM public.pedido_itens pi
+      JOIN public.categorias c ON c.id = pi.id_categoria
+     WHERE pi.id = p_item;
+
+    /*  Políticas:
+        'M' → média simples dos sabores
+        'P' → média ponderada pela fração de cada sabor
+        qualquer outro valor = maior valor  */
+    SELECT CASE v_regra
+               WHEN 'M' THEN ROUND(AVG(s.valor), 2)
+               WHEN 'P' THEN ROUND(SUM(s.valor * s.partes) / SUM(s.partes), 2)
+               ELSE MAX(s.valor)
+           END
+      INTO v_valor
+      FROM public.pedido_item_sabores s
+     WHERE s.id_pedido_item = p_item;
+
+    RETURN v_valor;
+END;
+$$;
+
+-- 5) ── Valida o sabor e grava o preço dele na opção do item
+CREATE OR REPLACE FUNCTION public.chk_pedido_item_sabor()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_categoria   uuid;
+    v_opcao       uuid;
+    v_opcao_meia  char(1);
+    v_max         smallint;
+    v_cat_produto uuid;
+    v_qtd         integer;
+BEGIN
+    SELECT pi.id_categoria, pi.id_categoria_opcao, c.opcao_meia, c.max_sabores
+      INTO v_categoria, v_opcao, v_opcao_meia, v_max
+      FROM public.pedido_itens pi
+      JOIN public.categorias c ON c.id = pi.id_categoria
+     WHERE pi.id = NEW.id_pedido_item;
+
+    SELECT id_categoria INTO v_cat_produto
+      FROM public.produtos
+     WHERE id = NEW.id_produto;
+
+    IF v_cat_produto IS DISTINCT FROM v_categoria THEN
+        RAISE EXCEPTION 'Os sabores devem ser da mesma categoria do item' USING ERRCODE='P0001';
+    END IF;
+
+    SELECT COUNT(*) + 1 INTO v_qtd
+      FROM public.pedido_item_sabores
+     WHERE id_pedido_item = NEW.id_pedido_item
+       AND id <> NEW.id;
+
+    IF v_qtd > 1 AND COALESCE(v_opcao_meia, '') = '' THEN
+        RAISE EXCEPTION 'Categoria não permite mais de um sabor' USING ERRCODE='P0001';
+    END IF;
+
+    IF v_qtd > v_max THEN
+        RAISE EXCEPTION 'Categoria permite no máximo % sabores', v_max USING ERRCODE='P0001';
+    END IF;
+
+    NEW.valor := public.preco_para_produto_opcao(NEW.id_produto, v_opcao);
+
+    IF NEW.valor IS NULL THEN
+        RAISE EXCEPTION 'Preço não encontrado para o sabor' USING ERRCODE='P0001';
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pis_validar
+BEFORE INSERT OR UPDATE OF id_produto, id_pedido_item
+ON public.pedido_item_sabores
+FOR EACH ROW
+EXECUTE FUNCTION public.chk_pedido_item_sabor();
+
+-- 6) ── Recalcula o valor do item quando os sabores mudam
+--       (o UPDATE em pedido_itens dispara o recálculo do total do pedido)
+CREATE OR REPLACE FUNCTION public.trg_pis_recalc_item()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_item uuid := COALESCE(NEW.id_pedido_item, OLD.id_pedido_item);
+BEGIN
+    UPDATE public.pedido_itens
+       SET valor_unitario = COALESCE(public.calcular_valor_sabores(v_item), valor_unitario)
+     WHERE id = v_item
+       AND deleted_at IS NULL;
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_pis_after_iu_d
+AFTER INSERT OR UPDATE OR DELETE
+ON public.pedido_item_sabores
+FOR EACH ROW
+EXECUTE FUNCTION public.trg_pis_recalc_item();
+
+-- 7) ── Meia-pizza: item com sabores usa o valor calculado pelos sabores
+CREATE OR REPLACE FUNCTION public.chk_calcular_meia_pizza()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_opcao_meia char(1);
+    v_preco1     numeric(10,2);
+    v_preco2     numeric(10,2);
+    v_cat_prod2  uuid;
+BEGIN
+    /* ── Item com sabores em pedido_item_sabores ── */
+    IF TG_OP = 'UPDATE' AND EXISTS (
+           SELECT 1 FROM public.pedido_item_sabores WHERE id_pedido_item = NEW.id) THEN
+        NEW.valor_unitario := COALESCE(public.calcular_valor_sabores(NEW.id), NEW.valor_unitario);
+        RETURN NEW;
+    END IF;
+
+    /* ── Descobre a política da categoria ── */
+    SELECT c.opcao_meia
+      INTO v_opcao_meia
+      FROM public.categorias c
+     WHERE c.id = NEW.id_categoria;
+
+    /* ── Validações quando há segunda metade ── */
+    IF NEW.id_produto_2 IS NOT NULL THEN
+        -- Categoria precisa permitir; trata NULL como “não permitido”
+        IF COALESCE(v_opcao_meia, '') = '' THEN
+            RAISE EXCEPTION 'Categoria não permite meia pizza' USING ERRCODE='P0001';
+        END IF;
+
+        -- Ambas as metades devem pertencer à mesma categoria
+        SELECT id_categoria INTO v_cat_prod2
+          FROM public.produtos
+         WHERE id = NEW.id_produto_2;
+
+        IF v_cat_prod2 IS DISTINCT FROM NEW.id_categoria THEN
+            RAISE EXCEPTION 'As duas metades devem ser da mesma categoria' USING ERRCODE='P0001';
+        END IF;
+
+        -- Não pode repetir a mesma metade
+        IF NEW.id_produto_2 = NEW.id_produto THEN
+            RAISE EXCEPTION 'As duas metades não podem ser o mesmo produto' USING ERRCODE='P0001';
+        END IF;
+    END IF;
+
+    /* ── Cálculo de preços ── */
+    v_preco1 := public.preco_para_produto_opcao(
+                    NEW.id_produto, NEW.id_categoria_opcao);
+
+    IF v_preco1 IS NULL THEN
+        RAISE EXCEPTION 'Preço não encontrado para a primeira metade' USING ERRCODE='P0001';
+    END IF;
+
+    IF NEW.id_produto_2 IS NOT NULL THEN
+        v_preco2 := public.preco_para_produto_opcao(
+                        NEW.id_produto_2, NEW.id_categoria_opcao);
+
+        IF v_preco2 IS NULL THEN
+            RAISE EXCEPTION 'Preço não encontrado para a segunda metade' USING ERRCODE='P0001';
+        END IF;
+
+        /*  Políticas com duas metades iguais:
+            'M' e 'P' → média; qualquer outro valor (inclusive NULL) = maior valor  */
+        IF COALESCE(v_opcao_meia, 'V') IN ('M', 'P') THEN
+            NEW.valor_unitario := ROUND((v_preco1 + v_preco2)/2, 2);
+        ELSE
+            NEW.valor_unitario := GREATEST(v_preco1, v_preco2);
+        END IF;
+    ELSE
+        NEW.valor_unitario := v_preco1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+---- create above / drop below ----
+/* =========================================================
+   MIGRATION DOWN  –  Volta para id_produto / id_produto_2
+   ========================================================= */
+
+-- Restaura a função de meia-pizza da migration 040
+CREATE OR REPLACE FUNCTION public.chk_calcular_meia_pizza()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_opcao_meia char(1);
+    v_preco1     numeric(10,2);
+    v_preco2     numeric(10,2);
+    v_cat_prod2  uuid;
+BEGIN
+    SELECT c.opcao_meia
+      INTO v_opcao_meia
+      FROM public.categorias c
+     WHERE c.id = NEW.id_categoria;
+
+    IF NEW.id_produto_2 IS NOT NULL THEN
+        IF COALESCE(v_opcao_meia, '') = '' THEN
+            RAISE EXCEPTION 'Categoria não permite meia pizza' USING ERRCODE='P0001';
+        END IF;
+
+        SELECT id_categoria INTO v_cat_prod2
+          FROM public.produtos
+         WHERE id = NEW.id_produto_2;
+
+        IF v_cat_prod2 IS DISTINCT FROM NEW.id_categoria THEN
+            RAISE EXCEPTION 'As duas metades devem ser da mesma categoria' USING ERRCODE='P0001';
+        END IF;
+
+        IF NEW.id_produto_2 = NEW.id_produto THEN
+            RAISE EXCEPTION 'As duas metades não podem ser o mesmo produto' USING ERRCODE='P0001';
+        END IF;
+    END IF;
+
+    v_preco1 := public.preco_para_produto_opcao(
+                    NEW.id_produto, NEW.id_categoria_opcao);
+
+    IF v_preco1 IS NULL THEN
+        RAISE EXCEPTION 'Preço não encontrado para a primeira metade' USING ERRCODE='P0001';
+    END IF;
+
+    IF NEW.id_produto_2 IS NOT NULL THEN
+        v_preco2 := public.preco_para_produto_opcao(
+                        NEW.id_produto_2, NEW.id_categoria_opcao);
+
+        IF v_preco2 IS NULL THEN
+            RAISE EXCEPTION 'Preço não encontrado para a segunda metade' USING ERRCODE='P0001';
+        END IF;
+
+        IF COALESCE(v_opcao_meia, 'V') = 'M' THEN
+            NEW.valor_unitario := ROUND((v_preco1 + v_preco2)/2, 2);
+        ELSE
+            NEW.valor_unitario := GREATEST(v_preco1, v_preco2);
+        END IF;
+    ELSE
+        NEW.valor_unitario := v_preco1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_pis_after_iu_d ON public.pedido_item_sabores;
+DROP TRIGGER IF EXISTS trg_pis_validar ON public.pedido_item_sabores;
+DROP FUNCTION IF EXISTS public.trg_pis_recalc_item();
+DROP FUNCTION IF EXISTS public.chk_pedido_item_sabor();
+DROP FUNCTION IF EXISTS public.calcular_valor_sabores(uuid);
+
+DROP TABLE IF EXISTS public.pedido_item_sabores;
+DROP SEQUENCE IF EXISTS public.pedido_item_sabores_seq_id_seq;
+
+UPDATE public.categorias SET opcao_meia = 'M' WHERE opcao_meia = 'P';
+COMMENT ON COLUMN public.categorias.opcao_meia IS 'Opção para meio a meio (M=Valor médio, V=Maior valor, vazio=Não permitido)';
+ALTER TABLE public.categorias
+    DROP CONSTRAINT IF EXISTS chk_categorias_max_sabores,
+    DROP COLUMN IF EXISTS max_sabores;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,185 @@
+-- Write your migrate up statements here
+/* =========================================================
+   MIGRATION UP  –  Combos / kits
+   =========================================================
+   O combo é um produto comum: o preço do pacote fica em produto_precos.
+   combo_slots define as escolhas do cliente ("Pizza", "Bebida"), cada uma
+   restrita a uma categoria e, opcionalmente, a uma opção (tamanho). No
+   pedido, cada componente escolhido vira um item filho do item do combo
+   (id_item_combo), que vai para a estação de produção do produto e entra
+   nas estatísticas de venda, mas só cobra o acréscimo do slot.
+   ========================================================= */
+
+-- 1) ── Slots do combo
+CREATE TABLE public.combo_slots (
+    id                  uuid          DEFAULT gen_random_uuid() NOT NULL,
+    id_produto          uuid          NOT NULL,   -- o produto combo
+    nome                varchar(100)  NOT NULL,
+    id_categoria        uuid          NOT NULL,   -- categoria dos produtos permitidos
+    id_categoria_opcao  uuid,                     -- opção exigida (ex.: Grande, 2L); NULL = qualquer
+    quantidade          integer       NOT NULL DEFAULT 1,
+    ordem               integer       NOT NULL DEFAULT 0,
+    created_at          timestamptz   DEFAULT now() NOT NULL,
+    updated_at          timestamptz   DEFAULT now() NOT NULL,
+    deleted_at          timestamptz,
+    CONSTRAINT combo_slots_pkey          PRIMARY KEY(id),
+    CONSTRAINT fk_cs_produto             FOREIGN KEY(id_produto)         REFERENCES public.produtos(id),
+    CONSTRAINT fk_cs_categoria           FOREIGN KEY(id_categoria)       REFERENCES public.categorias(id),
+    CONSTRAINT fk_cs_categoria_opcao     FOREIGN KEY(id_categoria_opcao) REFERENCES public.categoria_opcoes(id),
+    CONSTRAINT chk_cs_quantidade         CHECK (quantidade > 0)
+);
+
+CREATE INDEX idx_combo_slots_produto
+        ON public.combo_slots (id_produto)
+     WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_combo_slots_update_updated_at
+    BEFORE UPDATE ON public.combo_slots
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- 2) ── Acréscimo (ou desconto, se negativo) por produto escolhido no slot
+CREATE TABLE public.combo_slot_acrescimos (
+    id              uuid          DEFAULT gen_random_uuid() NOT NULL,
+    id_combo_slot   uuid          NOT NULL,
+    id_produto      uuid          NOT NULL,
+    valor           numeric(10,2) NOT NULL,
+    CONSTRAINT combo_slot_acrescimos_pkey   PRIMARY KEY(id),
+    CONSTRAINT fk_csa_combo_slot            FOREIGN KEY(id_combo_slot) REFERENCES public.combo_slots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_csa_produto               FOREIGN KEY(id_produto)    REFERENCES public.produtos(id),
+    CONSTRAINT uq_csa_slot_produto          UNIQUE (id_combo_slot, id_produto)
+);
+
+-- 3) ── Componentes no pedido
+ALTER TABLE public.pedido_itens
+    ADD COLUMN id_item_combo uuid REFERENCES public.pedido_itens (id),
+    ADD COLUMN id_combo_slot uuid REFERENCES public.combo_slots (id);
+
+COMMENT ON COLUMN public.pedido_itens.id_item_combo IS 'Item do combo ao qual este componente pertence';
+COMMENT ON COLUMN public.pedido_itens.id_combo_slot IS 'Slot do combo atendido por este componente';
+
+CREATE INDEX idx_pedido_itens_item_combo
+        ON public.pedido_itens (id_item_combo)
+     WHERE id_item_combo IS NOT NULL;
+
+-- 4) ── Valida o componente e cobra só o acréscimo do slot.
+--       Roda depois de trg_pi_meia_pizza (ordem alfabética dos triggers)
+--       e sobrepõe o valor_unitario calculado por ele.
+CREATE OR REPLACE FUNCTION public.chk_item_combo()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_slot       public.combo_slots%ROWTYPE;
+    v_pai        public.pedido_itens%ROWTYPE;
+    v_cat_prod   uuid;
+BEGIN
+    IF NEW.id_item_combo IS NULL THEN
+        IF NEW.id_combo_slot IS NOT NULL THEN
+            RAISE EXCEPTION 'Slot de combo informado em item que não é componente' USING ERRCODE='P0001';
+        END IF;
+        RETURN NEW;
+    END IF;
+
+    -- Componente excluído não é revalidado, só tem o preço reaplicado: a
+    -- exclusão lógica do sqlboiler regrava todas as colunas e dispara o
+    -- trg_pi_meia_pizza, que recalcula o valor_unitario.
+    IF NEW.deleted_at IS NULL THEN
+        SELECT * INTO v_pai FROM public.pedido_itens WHERE id = NEW.id_item_combo;
+        SELECT * INTO v_slot FROM public.combo_slots WHERE id = NEW.id_combo_slot;
+
+        IF v_slot.id IS NULL THEN
+            RAISE EXCEPTION 'Componente de combo sem slot' USING ERRCODE='P0001';
+        END IF;
+
+        IF v_pai.id_pedido IS DISTINCT FROM NEW.id_pedido THEN
+            RAISE EXCEPTION 'Componente e combo devem ser do mesmo pedido' USING ERRCODE='P0001';
+        END IF;
+
+        IF v_pai.id_item_combo IS NOT NULL THEN
+            RAISE EXCEPTION 'Combo dentro de combo não é permitido' USING ERRCODE='P0001';
+        END IF;
+
+        IF v_slot.id_produto IS DISTINCT FROM v_pai.id_produto THEN
+            RAISE EXCEPTION 'Slot não pertence ao combo do item' USING ERRCODE='P0001';
+        END IF;
+
+        SELECT id_categoria INTO v_cat_prod
+          FROM public.produtos
+         WHERE id = NEW.id_produto;
+
+        IF v_cat_prod IS DISTINCT FROM v_slot.id_categoria
+           OR NEW.id_categoria IS DISTINCT FROM v_slot.id_categoria THEN
+            RAISE EXCEPTION 'Produto fora da categoria do slot %', v_slot.nome USING ERRCODE='P0001';
+        END IF;
+
+        IF v_slot.id_categoria_opcao IS NOT NULL
+           AND NEW.id_categoria_opcao IS DISTINCT FROM v_slot.id_categoria_opcao THEN
+            RAISE EXCEPTION 'Opção diferente da exigida pelo slot %', v_slot.nome USING ERRCODE='P0001';
+        END IF;
+    END IF;
+
+    NEW.valor_unitario := COALESCE((
+        SELECT a.valor
+          FROM public.combo_slot_acrescimos a
+         WHERE a.id_combo_slot = NEW.id_combo_slot
+           AND a.id_produto    = NEW.id_produto), 0);
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pi_preco_combo
+BEFORE INSERT OR UPDATE OF id_produto, id_produto_2, id_categoria_opcao, id_item_combo, id_combo_slot
+ON public.pedido_itens
+FOR EACH ROW
+EXECUTE FUNCTION public.chk_item_combo();
+
+-- 5) ── Sabores de um componente não alteram o preço dele
+CREATE OR REPLACE FUNCTION public.trg_pis_recalc_item()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_item uuid := COALESCE(NEW.id_pedido_item, OLD.id_pedido_item);
+BEGIN
+    UPDATE public.pedido_itens
+       SET valor_unitario = COALESCE(public.calcular_valor_sabores(v_item), valor_unitario)
+     WHERE id = v_item
+       AND id_item_combo IS NULL
+       AND deleted_at IS NULL;
+
+    RETURN NULL;
+END;
+$$;
+---- create above / drop below ----
+/* =========================================================
+   MIGRATION DOWN  –  Remove combos
+   ========================================================= */
+
+CREATE OR REPLACE FUNCTION public.trg_pis_recalc_item()
+RETURNS trigger LANGUAGE plpgsql AS
+$$
+DECLARE
+    v_item uuid := COALESCE(NEW.id_pedido_item, OLD.id_pedido_item);
+BEGIN
+    UPDATE public.pedido_itens
+       SET valor_unitario = COALESCE(public.calcular_valor_sabores(v_item), valor_unitario)
+     WHERE id = v_item
+       AND deleted_at IS NULL;
+
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_pi_preco_combo ON public.pedido_itens;
+DROP FUNCTION IF EXISTS public.chk_item_combo();
+
+DROP INDEX IF EXISTS idx_pedido_itens_item_combo;
+ALTER TABLE public.pedido_itens
+    DROP COLUMN IF EXISTS id_combo_slot,
+    DROP COLUMN IF EXISTS id_item_combo;
+
+DROP TABLE IF EXISTS public.combo_slot_acrescimos;
+DROP TRIGGER IF EXISTS trg_combo_slots_update_updated_at ON public.combo_slots;
+DROP INDEX IF EXISTS idx_combo_slots_produto;
+DROP TABLE IF EXISTS public.combo_slots;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,148 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Cupons de desconto por tenant
+   =========================================================
+   O desconto do cupom é calculado na cotação do pedido e gravado em
+   pedidos.desconto; pedidos.cupom guarda o código. O resgate fica em
+   cupom_resgates, gravado na mesma transação do pedido, e cupons.usos é o
+   contador usado para travar o limite global.
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Cupons
+------------------------------------------------------------
+CREATE TABLE public.cupons
+(
+    id                    uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    tenant_id             uuid          NOT NULL REFERENCES public.tenants (id),
+    codigo                varchar(40)   NOT NULL,
+    descricao             varchar(200),
+    tipo                  char(1)       NOT NULL,
+    valor                 numeric(10,2) NOT NULL DEFAULT 0,
+    desconto_maximo       numeric(10,2),
+    valor_minimo_pedido   numeric(10,2) NOT NULL DEFAULT 0,
+    inicio                timestamptz   NOT NULL DEFAULT now(),
+    fim                   timestamptz,
+    limite_total          integer,
+    limite_por_cliente    integer,
+    usos                  integer       NOT NULL DEFAULT 0,
+    ativo                 boolean       NOT NULL DEFAULT true,
+    created_at            timestamptz   NOT NULL DEFAULT now(),
+    updated_at            timestamptz   NOT NULL DEFAULT now(),
+    deleted_at            timestamptz,
+    CONSTRAINT chk_cupons_tipo        CHECK (tipo IN ('P', 'V', 'F')),
+    CONSTRAINT chk_cupons_valor       CHECK (valor >= 0 AND (tipo <> 'P' OR valor <= 100)),
+    CONSTRAINT chk_cupons_periodo     CHECK (fim IS NULL OR fim > inicio),
+    CONSTRAINT chk_cupons_limites     CHECK ((limite_total IS NULL OR limite_total > 0)
+                                         AND (limite_por_cliente IS NULL OR limite_por_cliente > 0)),
+    CONSTRAINT chk_cupons_usos        CHECK (usos >= 0 AND (limite_total IS NULL OR usos <= limite_total))
+);
+
+COMMENT ON COLUMN public.cupons.codigo IS 'Código digitado pelo cliente; único por tenant, sem diferenciar maiúsculas';
+COMMENT ON COLUMN public.cupons.tipo IS 'P=Percentual, V=Valor fixo, F=Frete grátis';
+COMMENT ON COLUMN public.cupons.valor IS 'Percentual (0-100) ou valor em reais; ignorado no frete grátis';
+COMMENT ON COLUMN public.cupons.desconto_maximo IS 'Teto do desconto percentual';
+COMMENT ON COLUMN public.cupons.valor_minimo_pedido IS 'Soma mínima dos itens (sem taxa de entrega) para aceitar o cupom';
+COMMENT ON COLUMN public.cupons.usos IS 'Resgates ativos; mantido por cupom_resgates';
+
+CREATE UNIQUE INDEX uq_cupons_tenant_codigo
+        ON public.cupons (tenant_id, upper(codigo))
+     WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_cupons_update_updated_at
+    BEFORE UPDATE ON public.cupons
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 2) Restrições: sem linhas, o cupom vale para o pedido todo;
+--    com linhas, o desconto incide só nos itens dessas
+--    categorias/produtos
+------------------------------------------------------------
+CREATE TABLE public.cupom_categorias
+(
+    id_cupom     uuid NOT NULL REFERENCES public.cupons (id) ON DELETE CASCADE,
+    id_categoria uuid NOT NULL REFERENCES public.categorias (id),
+    PRIMARY KEY (id_cupom, id_categoria)
+);
+
+CREATE TABLE public.cupom_produtos
+(
+    id_cupom   uuid NOT NULL REFERENCES public.cupons (id) ON DELETE CASCADE,
+    id_produto uuid NOT NULL REFERENCES public.produtos (id),
+    PRIMARY KEY (id_cupom, id_produto)
+);
+
+------------------------------------------------------------
+-- 3) Resgates
+------------------------------------------------------------
+CREATE TABLE public.cupom_resgates
+(
+    id             uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    id_cupom       uuid          NOT NULL REFERENCES public.cupons (id),
+    id_pedido      uuid          NOT NULL REFERENCES public.pedidos (id),
+    id_cliente     uuid          NOT NULL REFERENCES public.clientes (id),
+    valor_desconto numeric(10,2) NOT NULL,
+    created_at     timestamptz   NOT NULL DEFAULT now(),
+    cancelado_em   timestamptz
+);
+
+COMMENT ON COLUMN public.cupom_resgates.cancelado_em IS 'Pedido cancelado, excluído ou editado com outro cupom; o uso volta para o cupom';
+
+CREATE UNIQUE INDEX uq_cupom_resgates_pedido
+        ON public.cupom_resgates (id_pedido)
+     WHERE cancelado_em IS NULL;
+
+CREATE INDEX idx_cupom_resgates_cupom_cliente
+        ON public.cupom_resgates (id_cupom, id_cliente)
+     WHERE cancelado_em IS NULL;
+
+------------------------------------------------------------
+-- 4) Devolve o uso do cupom do pedido
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.liberar_cupom_pedido(p_pedido_id uuid)
+RETURNS void
+LANGUAGE plpgsql AS $$
+BEGIN
+    WITH r AS (
+        UPDATE public.cupom_resgates
+           SET cancelado_em = now()
+         WHERE id_pedido = p_pedido_id
+           AND cancelado_em IS NULL
+     RETURNING id_cupom
+    )
+    UPDATE public.cupons c
+       SET usos = c.usos - 1
+      FROM r
+     WHERE c.id = r.id_cupom;
+END;
+$$;
+
+-- Pedido cancelado (status 6) ou excluído libera o cupom
+CREATE OR REPLACE FUNCTION public.trg_pedidos_liberar_cupom()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF (NEW.id_status = 6 AND OLD.id_status IS DISTINCT FROM 6)
+       OR (NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL) THEN
+        PERFORM public.liberar_cupom_pedido(NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_pedidos_liberar_cupom
+AFTER UPDATE OF id_status, deleted_at ON public.pedidos
+FOR EACH ROW
+EXECUTE FUNCTION public.trg_pedidos_liberar_cupom();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pedidos_liberar_cupom ON public.pedidos;
+DROP FUNCTION IF EXISTS public.trg_pedidos_liberar_cupom();
+DROP FUNCTION IF EXISTS public.liberar_cupom_pedido(uuid);
+
+DROP TABLE IF EXISTS public.cupom_resgates;
+DROP TABLE IF EXISTS public.cupom_produtos;
+DROP TABLE IF EXISTS public.cupom_categorias;
+DROP TRIGGER IF EXISTS trg_cupons_update_updated_at ON public.cupons;
+DROP TABLE IF EXISTS public.cupons;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
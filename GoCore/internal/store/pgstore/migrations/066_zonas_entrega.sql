@@ -0,0 +1,86 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Zonas de entrega e taxa por região
+   =========================================================
+   A taxa de entrega do pedido Delivery passa a vir da zona que contém o
+   endereço: lista de bairros, faixa de CEP, raio em km a partir da loja
+   (tenants.lat/lng) ou polígono. Tenant sem zonas continua com a taxa
+   informada no pedido (taxa_entrega_padrao).
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Coordenadas da loja
+------------------------------------------------------------
+ALTER TABLE public.tenants
+    ADD COLUMN lat numeric(9,6),
+    ADD COLUMN lng numeric(9,6);
+
+COMMENT ON COLUMN public.tenants.lat IS 'Latitude da loja, origem das zonas por raio';
+COMMENT ON COLUMN public.tenants.lng IS 'Longitude da loja, origem das zonas por raio';
+
+------------------------------------------------------------
+-- 2) Zonas
+------------------------------------------------------------
+CREATE TABLE public.zonas_entrega
+(
+    id                  uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    tenant_id           uuid          NOT NULL REFERENCES public.tenants (id),
+    nome                varchar(100)  NOT NULL,
+    tipo                char(1)       NOT NULL,
+    bairros             text[],
+    cep_inicio          char(8),
+    cep_fim             char(8),
+    raio_km             numeric(6,2),
+    poligono            jsonb,
+    taxa                numeric(10,2) NOT NULL DEFAULT 0,
+    valor_minimo_pedido numeric(10,2) NOT NULL DEFAULT 0,
+    prazo_min           integer,
+    prazo_max           integer,
+    ordem               integer       NOT NULL DEFAULT 0,
+    ativo               boolean       NOT NULL DEFAULT true,
+    created_at          timestamptz   NOT NULL DEFAULT now(),
+    updated_at          timestamptz   NOT NULL DEFAULT now(),
+    deleted_at          timestamptz,
+    CONSTRAINT chk_zonas_entrega_tipo      CHECK (tipo IN ('B', 'C', 'R', 'P')),
+    CONSTRAINT chk_zonas_entrega_definicao CHECK (
+           (tipo = 'B' AND cardinality(bairros) > 0)
+        OR (tipo = 'C' AND cep_inicio ~ '^[0-9]{8}$' AND cep_fim ~ '^[0-9]{8}$' AND cep_inicio <= cep_fim)
+        OR (tipo = 'R' AND raio_km > 0)
+        OR (tipo = 'P' AND jsonb_typeof(poligono) = 'array' AND jsonb_array_length(poligono) >= 3)
+    ),
+    CONSTRAINT chk_zonas_entrega_valores   CHECK (taxa >= 0 AND valor_minimo_pedido >= 0),
+    CONSTRAINT chk_zonas_entrega_prazo     CHECK (prazo_min IS NULL OR prazo_max IS NULL OR prazo_min <= prazo_max)
+);
+
+COMMENT ON COLUMN public.zonas_entrega.tipo IS 'B=Bairros, C=Faixa de CEP, R=Raio da loja, P=Polígono';
+COMMENT ON COLUMN public.zonas_entrega.bairros IS 'Tipo B: nomes em minúsculas e sem acento';
+COMMENT ON COLUMN public.zonas_entrega.poligono IS 'Tipo P: [[lat, lng], ...] na ordem dos vértices';
+COMMENT ON COLUMN public.zonas_entrega.ordem IS 'Endereço em mais de uma zona fica com a de menor ordem';
+COMMENT ON COLUMN public.zonas_entrega.prazo_min IS 'Prazo estimado em minutos, sugerido para pedidos.prazo_min';
+
+CREATE INDEX idx_zonas_entrega_tenant
+        ON public.zonas_entrega (tenant_id, ordem)
+     WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_zonas_entrega_update_updated_at
+    BEFORE UPDATE ON public.zonas_entrega
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 3) Zona aplicada ao pedido
+------------------------------------------------------------
+ALTER TABLE public.pedidos
+    ADD COLUMN id_zona_entrega uuid REFERENCES public.zonas_entrega (id);
+
+COMMENT ON COLUMN public.pedidos.id_zona_entrega IS 'Zona que definiu taxa_entrega/nome_taxa_entrega';
+---- create above / drop below ----
+ALTER TABLE public.pedidos DROP COLUMN IF EXISTS id_zona_entrega;
+
+DROP TRIGGER IF EXISTS trg_zonas_entrega_update_updated_at ON public.zonas_entrega;
+DROP TABLE IF EXISTS public.zonas_entrega;
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS lat,
+    DROP COLUMN IF EXISTS lng;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
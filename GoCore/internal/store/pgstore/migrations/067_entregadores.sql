@@ -0,0 +1,143 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Entregadores e rotas de entrega
+   =========================================================
+   O pedido Delivery passa a ter quem levou e quando voltou: o entregador
+   recebe uma rota com um ou mais pedidos, sai (pedidos → "Saiu para
+   entrega"), marca cada entrega e retorna. No acerto, o dinheiro recebido
+   dos clientes vira pagamento do pedido (e entra no caixa pelo trigger de
+   pedido_pagamentos) e as taxas do entregador saem do caixa como sangria.
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Entregadores
+------------------------------------------------------------
+CREATE TABLE public.entregadores
+(
+    id               uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    tenant_id        uuid          NOT NULL REFERENCES public.tenants (id),
+    id_usuario       uuid          REFERENCES public.users (id) ON DELETE SET NULL,
+    nome             varchar(100)  NOT NULL,
+    telefone         varchar(20),
+    veiculo          varchar(50),
+    placa            varchar(10),
+    taxa_por_entrega numeric(10,2) NOT NULL DEFAULT 0,
+    ativo            boolean       NOT NULL DEFAULT true,
+    created_at       timestamptz   NOT NULL DEFAULT now(),
+    updated_at       timestamptz   NOT NULL DEFAULT now(),
+    deleted_at       timestamptz,
+    CONSTRAINT chk_entregadores_taxa CHECK (taxa_por_entrega >= 0)
+);
+
+COMMENT ON COLUMN public.entregadores.id_usuario IS 'Usuário do app do entregador (login em /api/v1/mobile)';
+COMMENT ON COLUMN public.entregadores.taxa_por_entrega IS 'Valor pago ao entregador por pedido entregue';
+
+CREATE INDEX idx_entregadores_tenant
+        ON public.entregadores (tenant_id)
+     WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX uidx_entregadores_usuario
+        ON public.entregadores (id_usuario)
+     WHERE deleted_at IS NULL AND id_usuario IS NOT NULL;
+
+CREATE TRIGGER trg_entregadores_update_updated_at
+    BEFORE UPDATE ON public.entregadores
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 2) Rotas (saídas do entregador)
+------------------------------------------------------------
+CREATE TABLE public.rotas_entrega
+(
+    id                    uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    seq_id                bigint        GENERATED BY DEFAULT AS IDENTITY,
+    tenant_id             uuid          NOT NULL REFERENCES public.tenants (id),
+    id_entregador         uuid          NOT NULL REFERENCES public.entregadores (id),
+    status                char(1)       NOT NULL DEFAULT 'A',
+    saida_em              timestamptz,
+    retorno_em            timestamptz,
+    observacao            text,
+    criado_por            uuid          REFERENCES public.users (id) ON DELETE SET NULL,
+    -- acerto
+    valor_taxas           numeric(10,2) NOT NULL DEFAULT 0,
+    valor_recebido        numeric(10,2) NOT NULL DEFAULT 0,
+    valor_entregue        numeric(10,2),
+    id_caixa              uuid          REFERENCES public.caixas (id),
+    id_movimentacao_taxas uuid          REFERENCES public.caixa_movimentacoes (id) ON DELETE SET NULL,
+    acertado_em           timestamptz,
+    acertado_por          uuid          REFERENCES public.users (id) ON DELETE SET NULL,
+    observacao_acerto     text,
+    created_at            timestamptz   NOT NULL DEFAULT now(),
+    updated_at            timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT chk_rotas_entrega_status CHECK (
+           (status = 'A' AND saida_em IS NULL)
+        OR (status = 'S' AND saida_em IS NOT NULL AND retorno_em IS NULL)
+        OR (status = 'R' AND saida_em IS NOT NULL AND retorno_em IS NOT NULL)
+        OR (status = 'C' AND saida_em IS NULL)
+    ),
+    CONSTRAINT chk_rotas_entrega_acerto CHECK (acertado_em IS NULL OR status = 'R')
+);
+
+COMMENT ON COLUMN public.rotas_entrega.status IS 'A=Aberta (montando), S=Em rota, R=Retornou, C=Cancelada';
+COMMENT ON COLUMN public.rotas_entrega.valor_taxas IS 'Taxas devidas ao entregador, pagas como sangria no acerto';
+COMMENT ON COLUMN public.rotas_entrega.valor_recebido IS 'Dinheiro recebido dos clientes, registrado como pagamento no acerto';
+COMMENT ON COLUMN public.rotas_entrega.valor_entregue IS 'Dinheiro que o entregador entregou no acerto (conferência)';
+
+CREATE UNIQUE INDEX uidx_rotas_entrega_seq ON public.rotas_entrega (seq_id);
+
+CREATE INDEX idx_rotas_entrega_tenant
+        ON public.rotas_entrega (tenant_id, created_at DESC);
+
+CREATE INDEX idx_rotas_entrega_entregador
+        ON public.rotas_entrega (id_entregador, status);
+
+CREATE TRIGGER trg_rotas_entrega_update_updated_at
+    BEFORE UPDATE ON public.rotas_entrega
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 3) Pedidos da rota
+------------------------------------------------------------
+CREATE TABLE public.rota_entrega_pedidos
+(
+    id             uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    id_rota        uuid          NOT NULL REFERENCES public.rotas_entrega (id) ON DELETE CASCADE,
+    id_pedido      uuid          NOT NULL REFERENCES public.pedidos (id),
+    ordem          integer       NOT NULL DEFAULT 0,
+    status         char(1)       NOT NULL DEFAULT 'P',
+    valor_taxa     numeric(10,2) NOT NULL DEFAULT 0,
+    valor_receber  numeric(10,2) NOT NULL DEFAULT 0,
+    valor_recebido numeric(10,2) NOT NULL DEFAULT 0,
+    entregue_em    timestamptz,
+    observacao     text,
+    id_pagamento   uuid          REFERENCES public.pedido_pagamentos (id) ON DELETE SET NULL,
+    created_at     timestamptz   NOT NULL DEFAULT now(),
+    updated_at     timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT uq_rota_entrega_pedido UNIQUE (id_rota, id_pedido),
+    CONSTRAINT chk_rota_entrega_pedidos_status CHECK (status IN ('P', 'E', 'N')),
+    CONSTRAINT chk_rota_entrega_pedidos_valores CHECK (valor_taxa >= 0 AND valor_receber >= 0 AND valor_recebido >= 0)
+);
+
+COMMENT ON COLUMN public.rota_entrega_pedidos.status IS 'P=Pendente, E=Entregue, N=Não entregue';
+COMMENT ON COLUMN public.rota_entrega_pedidos.valor_taxa IS 'Taxa do entregador no momento da atribuição';
+COMMENT ON COLUMN public.rota_entrega_pedidos.valor_receber IS 'Saldo em dinheiro a cobrar do cliente, calculado na saída';
+
+-- Um pedido só pode estar em uma rota; o não entregue pode ir em outra
+CREATE UNIQUE INDEX uidx_rota_entrega_pedidos_pedido
+        ON public.rota_entrega_pedidos (id_pedido)
+     WHERE status <> 'N';
+
+CREATE TRIGGER trg_rota_entrega_pedidos_update_updated_at
+    BEFORE UPDATE ON public.rota_entrega_pedidos
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_rota_entrega_pedidos_update_updated_at ON public.rota_entrega_pedidos;
+DROP TABLE IF EXISTS public.rota_entrega_pedidos;
+
+DROP TRIGGER IF EXISTS trg_rotas_entrega_update_updated_at ON public.rotas_entrega;
+DROP TABLE IF EXISTS public.rotas_entrega;
+
+DROP TRIGGER IF EXISTS trg_entregadores_update_updated_at ON public.entregadores;
+DROP TABLE IF EXISTS public.entregadores;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – PIX (BR Code)
+   =========================================================
+   Dados do recebedor usados para montar o QR Code PIX dos pedidos. O
+   pagamento é confirmado pelo operador e gravado em pedido_pagamentos
+   com forma_pagamento = 'PIX' e o txid na observação.
+   ========================================================= */
+ALTER TABLE public.tenants
+    ADD COLUMN pix_chave          varchar(77),
+    ADD COLUMN pix_nome_recebedor varchar(25),
+    ADD COLUMN pix_cidade         varchar(15);
+
+COMMENT ON COLUMN public.tenants.pix_chave IS 'Chave PIX do recebedor (CPF, CNPJ, e-mail, celular ou aleatória)';
+COMMENT ON COLUMN public.tenants.pix_nome_recebedor IS 'Nome no BR Code, sem acentos, até 25 caracteres';
+COMMENT ON COLUMN public.tenants.pix_cidade IS 'Cidade no BR Code, sem acentos, até 15 caracteres';
+
+-- Pagamentos PIX do pedido, para localizar pelo txid
+CREATE INDEX idx_pagamentos_pix
+        ON public.pedido_pagamentos (id_pedido)
+     WHERE forma_pagamento = 'PIX' AND deleted_at IS NULL;
+---- create above / drop below ----
+DROP INDEX IF EXISTS public.idx_pagamentos_pix;
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS pix_chave,
+    DROP COLUMN IF EXISTS pix_nome_recebedor,
+    DROP COLUMN IF EXISTS pix_cidade;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,175 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Crédito de clientes (fiado)
+   =========================================================
+   Limite de crédito por cliente (ou o padrão do tenant) e política de
+   atraso. O saldo do cliente é a soma das parcelas em aberto de
+   contas_receber de todos os seus pedidos. Parcela nova que estoura o
+   limite, de cliente bloqueado ou com parcela vencida além da
+   tolerância só entra com autorizado_por (gerente).
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Política de crédito por tenant
+------------------------------------------------------------
+CREATE TABLE public.credito_config
+(
+    tenant_id              uuid          NOT NULL PRIMARY KEY REFERENCES public.tenants (id),
+    limite_padrao          numeric(10,2),
+    bloquear_atraso        boolean       NOT NULL DEFAULT true,
+    dias_tolerancia_atraso integer       NOT NULL DEFAULT 0,
+    updated_at             timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT chk_credito_config_limite     CHECK (limite_padrao IS NULL OR limite_padrao >= 0),
+    CONSTRAINT chk_credito_config_tolerancia CHECK (dias_tolerancia_atraso >= 0)
+);
+
+COMMENT ON COLUMN public.credito_config.limite_padrao IS 'Limite dos clientes sem limite próprio; NULL = sem limite';
+COMMENT ON COLUMN public.credito_config.bloquear_atraso IS 'Recusa parcela nova de cliente com parcela vencida além da tolerância';
+COMMENT ON COLUMN public.credito_config.dias_tolerancia_atraso IS 'Dias de atraso aceitos antes do bloqueio';
+
+CREATE TRIGGER trg_credito_config_update_updated_at
+    BEFORE UPDATE ON public.credito_config
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 2) Limite do cliente
+------------------------------------------------------------
+CREATE TABLE public.clientes_credito
+(
+    id_cliente uuid          NOT NULL PRIMARY KEY REFERENCES public.clientes (id) ON DELETE CASCADE,
+    tenant_id  uuid          NOT NULL REFERENCES public.tenants (id),
+    limite     numeric(10,2),
+    bloqueado  boolean       NOT NULL DEFAULT false,
+    observacao text,
+    updated_by uuid          REFERENCES public.users (id) ON DELETE SET NULL,
+    updated_at timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT chk_clientes_credito_limite CHECK (limite IS NULL OR limite >= 0)
+);
+
+COMMENT ON COLUMN public.clientes_credito.limite IS 'Limite de crédito do cliente; NULL = limite padrão do tenant';
+COMMENT ON COLUMN public.clientes_credito.bloqueado IS 'Crédito suspenso: nenhuma parcela nova sem gerente';
+
+CREATE INDEX idx_clientes_credito_tenant
+        ON public.clientes_credito (tenant_id);
+
+CREATE TRIGGER trg_clientes_credito_update_updated_at
+    BEFORE UPDATE ON public.clientes_credito
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 3) Gerente que liberou a parcela fora da política
+------------------------------------------------------------
+ALTER TABLE public.contas_receber
+    ADD COLUMN autorizado_por uuid REFERENCES public.users (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN public.contas_receber.autorizado_por IS 'Gerente que autorizou a parcela acima do limite ou com cliente em atraso';
+
+-- Parcelas em aberto, para o saldo do cliente e o aging
+CREATE INDEX idx_cr_em_aberto
+        ON public.contas_receber (id_pedido, vencimento)
+     WHERE cancelado_em IS NULL AND quitado IS NOT TRUE;
+
+------------------------------------------------------------
+-- 4) Situação de crédito do cliente numa data
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.situacao_credito_cliente(p_id_cliente uuid, p_data date)
+RETURNS TABLE (limite            numeric(10,2),
+               bloqueado         boolean,
+               bloquear_atraso   boolean,
+               dias_tolerancia   integer,
+               saldo_aberto      numeric(10,2),
+               saldo_vencido     numeric(10,2),
+               maior_atraso_dias integer)
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT COALESCE(cc.limite, cfg.limite_padrao),
+           COALESCE(cc.bloqueado, false),
+           COALESCE(cfg.bloquear_atraso, true),
+           COALESCE(cfg.dias_tolerancia_atraso, 0),
+           COALESCE(a.saldo_aberto, 0)::numeric(10,2),
+           COALESCE(a.saldo_vencido, 0)::numeric(10,2),
+           COALESCE(a.maior_atraso_dias, 0)
+      FROM public.clientes c
+      LEFT JOIN public.clientes_credito cc ON cc.id_cliente = c.id
+      LEFT JOIN public.credito_config cfg  ON cfg.tenant_id = c.tenant_id
+      LEFT JOIN LATERAL (
+            SELECT SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0))                                     AS saldo_aberto,
+                   SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0)) FILTER (WHERE cr.vencimento < p_data) AS saldo_vencido,
+                   MAX(p_data - cr.vencimento) FILTER (WHERE cr.vencimento < p_data)                     AS maior_atraso_dias
+              FROM public.contas_receber cr
+              JOIN public.pedidos p ON p.id = cr.id_pedido
+             WHERE p.id_cliente = c.id
+               AND p.id_status <> 6                                   -- 6 = Cancelado
+               AND p.deleted_at IS NULL
+               AND cr.cancelado_em IS NULL
+               AND COALESCE(cr.valor_pago, 0) < cr.valor_devido
+      ) a ON true
+     WHERE c.id = p_id_cliente;
+$$;
+
+------------------------------------------------------------
+-- 5) Parcela nova conferida contra a política, sob lock do cliente
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.enforce_credito_cliente()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_cliente uuid;
+    v_hoje    date;
+    v_sit     record;
+BEGIN
+    IF NEW.autorizado_por IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT p.id_cliente, (now() AT TIME ZONE COALESCE(t.timezone, 'America/Sao_Paulo'))::date
+      INTO v_cliente, v_hoje
+      FROM public.pedidos p
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE p.id = NEW.id_pedido;
+
+    IF v_cliente IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtextextended('credito:' || v_cliente::text, 0));
+
+    SELECT * INTO v_sit FROM public.situacao_credito_cliente(v_cliente, v_hoje);
+
+    IF v_sit.bloqueado THEN
+        RAISE EXCEPTION 'Crédito do cliente bloqueado' USING ERRCODE = 'P0001';
+    END IF;
+
+    IF v_sit.bloquear_atraso AND v_sit.maior_atraso_dias > v_sit.dias_tolerancia THEN
+        RAISE EXCEPTION 'Cliente com parcela vencida há % dia(s)',
+              v_sit.maior_atraso_dias USING ERRCODE = 'P0001';
+    END IF;
+
+    IF v_sit.limite IS NOT NULL AND v_sit.saldo_aberto + NEW.valor_devido > v_sit.limite THEN
+        RAISE EXCEPTION 'Limite de crédito excedido: saldo % + parcela % > limite %',
+              v_sit.saldo_aberto, NEW.valor_devido, v_sit.limite USING ERRCODE = 'P0001';
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_cr_credito_cliente
+    BEFORE INSERT ON public.contas_receber
+    FOR EACH ROW EXECUTE FUNCTION public.enforce_credito_cliente();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_cr_credito_cliente ON public.contas_receber;
+DROP FUNCTION IF EXISTS public.enforce_credito_cliente();
+DROP FUNCTION IF EXISTS public.situacao_credito_cliente(uuid, date);
+
+DROP INDEX IF EXISTS public.idx_cr_em_aberto;
+
+ALTER TABLE public.contas_receber
+    DROP COLUMN IF EXISTS autorizado_por;
+
+DROP TABLE IF EXISTS public.clientes_credito;
+DROP TABLE IF EXISTS public.credito_config;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
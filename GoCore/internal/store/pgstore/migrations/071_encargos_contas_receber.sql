@@ -0,0 +1,529 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Multa e juros sobre parcelas vencidas (contas_receber)
+   =========================================================
+   Multa fixa (% do principal em aberto no vencimento) e juros
+   diários pró-rata sobre o principal em aberto a cada dia, contados
+   do vencimento. Dentro da carência não há encargos; passada a
+   carência, contam desde o vencimento.
+
+   Cada pagamento da parcela paga primeiro os encargos pendentes na
+   data do pagamento (pedido_pagamentos.valor_encargos) e o resto
+   abate o principal. contas_receber.valor_pago e pedidos.valor_pago
+   seguem só com o principal; os encargos pagos ficam em
+   contas_receber.encargos_pagos. O caixa recebe o valor cheio.
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Política de encargos por tenant
+------------------------------------------------------------
+ALTER TABLE public.credito_config
+    ADD COLUMN multa_percentual       numeric(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN juros_dia_percentual   numeric(7,4) NOT NULL DEFAULT 0,
+    ADD COLUMN dias_carencia_encargos integer      NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_credito_config_multa    CHECK (multa_percentual BETWEEN 0 AND 100),
+    ADD CONSTRAINT chk_credito_config_juros    CHECK (juros_dia_percentual BETWEEN 0 AND 10),
+    ADD CONSTRAINT chk_credito_config_carencia CHECK (dias_carencia_encargos >= 0);
+
+COMMENT ON COLUMN public.credito_config.multa_percentual IS 'Multa por atraso, % do principal em aberto no vencimento';
+COMMENT ON COLUMN public.credito_config.juros_dia_percentual IS 'Juros de mora ao dia, % pró-rata sobre o principal em aberto';
+COMMENT ON COLUMN public.credito_config.dias_carencia_encargos IS 'Dias após o vencimento sem multa nem juros';
+
+------------------------------------------------------------
+-- 2) Encargos pagos, separados do principal
+------------------------------------------------------------
+ALTER TABLE public.pedido_pagamentos
+    ADD COLUMN valor_encargos numeric(10,2) NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_pp_valor_encargos
+        CHECK (valor_encargos >= 0 AND valor_encargos <= valor_pago - troco);
+
+COMMENT ON COLUMN public.pedido_pagamentos.valor_encargos IS 'Parte do pagamento da parcela que quitou multa e juros (não abate o principal)';
+
+ALTER TABLE public.contas_receber
+    ADD COLUMN encargos_pagos numeric(10,2) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN public.contas_receber.encargos_pagos IS 'Multa e juros já pagos; valor_pago guarda só o principal';
+
+------------------------------------------------------------
+-- 3) Encargos da parcela numa data
+------------------------------------------------------------
+-- Considera só os pagamentos feitos até p_data (no fuso do tenant).
+-- Juros: Σ principal em aberto × dias entre os pagamentos após o
+-- vencimento × taxa diária, arredondado no fim.
+CREATE OR REPLACE FUNCTION public.encargos_conta_receber(p_conta_id uuid, p_data date)
+RETURNS TABLE (dias_atraso      integer,
+               em_carencia      boolean,
+               principal_aberto numeric(10,2),
+               multa            numeric(10,2),
+               juros            numeric(10,2),
+               encargos_pagos   numeric(10,2))
+LANGUAGE plpgsql
+STABLE
+AS $$
+DECLARE
+    v_venc        date;
+    v_devido      numeric(10,2);
+    v_cancelada   boolean;
+    v_tz          text;
+    v_multa_pct   numeric;
+    v_juros_pct   numeric;
+    v_carencia    integer;
+    v_aberto      numeric(10,2);
+    v_aberto_venc numeric(10,2);
+    v_desde       date;
+    v_base        numeric := 0;
+    r             record;
+BEGIN
+    SELECT cr.vencimento, cr.valor_devido, cr.cancelado_em IS NOT NULL,
+           COALESCE(t.timezone, 'America/Sao_Paulo'),
+           COALESCE(cfg.multa_percentual, 0),
+           COALESCE(cfg.juros_dia_percentual, 0),
+           COALESCE(cfg.dias_carencia_encargos, 0)
+      INTO v_venc, v_devido, v_cancelada, v_tz, v_multa_pct, v_juros_pct, v_carencia
+      FROM public.contas_receber cr
+      JOIN public.pedidos p ON p.id = cr.id_pedido
+      JOIN public.tenants t ON t.id = p.tenant_id
+      LEFT JOIN public.credito_config cfg ON cfg.tenant_id = p.tenant_id
+     WHERE cr.id = p_conta_id;
+
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    v_aberto := v_devido;
+    v_desde := v_venc;
+    encargos_pagos := 0;
+
+    FOR r IN
+        SELECT (pp.created_at AT TIME ZONE v_tz)::date                    AS dia,
+               SUM(pp.valor_pago - pp.troco - pp.valor_encargos)           AS principal,
+               SUM(pp.valor_encargos)                                      AS encargos
+          FROM public.pedido_pagamentos pp
+         WHERE pp.id_conta_receber = p_conta_id
+           AND pp.deleted_at IS NULL
+           AND (pp.created_at AT TIME ZONE v_tz)::date <= p_data
+         GROUP BY 1
+         ORDER BY 1
+    LOOP
+        IF r.dia > v_venc THEN
+            IF v_aberto_venc IS NULL THEN
+                v_aberto_venc := v_aberto;
+            END IF;
+            v_base := v_base + GREATEST(v_aberto, 0) * (r.dia - v_desde);
+            v_desde := r.dia;
+        END IF;
+        v_aberto := v_aberto - r.principal;
+        encargos_pagos := encargos_pagos + r.encargos;
+    END LOOP;
+
+    IF p_data > v_venc THEN
+        v_base := v_base + GREATEST(v_aberto, 0) * (p_data - v_desde);
+    END IF;
+
+    dias_atraso      := GREATEST(p_data - v_venc, 0);
+    em_carencia      := dias_atraso > 0 AND dias_atraso <= v_carencia;
+    principal_aberto := GREATEST(v_aberto, 0);
+    multa            := 0;
+    juros            := 0;
+
+    IF NOT v_cancelada AND dias_atraso > v_carencia THEN
+        multa := round(GREATEST(COALESCE(v_aberto_venc, v_aberto), 0) * v_multa_pct / 100, 2);
+        juros := round(v_base * v_juros_pct / 100, 2);
+    END IF;
+
+    RETURN NEXT;
+END;
+$$;
+
+------------------------------------------------------------
+-- 4) Pagamento da parcela quita primeiro os encargos pendentes
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.alocar_encargos_pagamento()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_hoje     date;
+    v_pendente numeric(10,2);
+BEGIN
+    NEW.valor_encargos := 0;
+
+    IF NEW.id_conta_receber IS NULL OR NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+
+    -- pagamentos concorrentes da mesma parcela não repartem o mesmo encargo
+    PERFORM 1 FROM public.contas_receber WHERE id = NEW.id_conta_receber FOR UPDATE;
+
+    SELECT (NEW.created_at AT TIME ZONE COALESCE(t.timezone, 'America/Sao_Paulo'))::date
+      INTO v_hoje
+      FROM public.pedidos p
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE p.id = NEW.id_pedido;
+
+    SELECT GREATEST(e.multa + e.juros - e.encargos_pagos, 0)
+      INTO v_pendente
+      FROM public.encargos_conta_receber(NEW.id_conta_receber, v_hoje) e;
+
+    NEW.valor_encargos := LEAST(COALESCE(v_pendente, 0), GREATEST(NEW.valor_pago - NEW.troco, 0));
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pp_alocar_encargos
+    BEFORE INSERT ON public.pedido_pagamentos
+    FOR EACH ROW EXECUTE FUNCTION public.alocar_encargos_pagamento();
+
+------------------------------------------------------------
+-- 5) Principal e encargos da parcela
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.recalcular_conta_receber(p_conta_id uuid)
+RETURNS void LANGUAGE plpgsql AS $$
+DECLARE
+  v_pago     numeric(10,2);
+  v_encargos numeric(10,2);
+BEGIN
+  SELECT COALESCE(SUM(valor_pago - troco - valor_encargos),0),
+         COALESCE(SUM(valor_encargos),0)
+    INTO v_pago, v_encargos
+    FROM public.pedido_pagamentos
+   WHERE id_conta_receber = p_conta_id
+     AND deleted_at IS NULL;
+
+  UPDATE public.contas_receber
+     SET valor_pago     = v_pago,
+         encargos_pagos = v_encargos,
+         updated_at     = now()
+   WHERE id = p_conta_id;
+END;
+$$;
+
+------------------------------------------------------------
+-- 6) Pago do pedido e restante: só o principal
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.recalcular_pagamentos(p_pedido_id uuid)
+RETURNS void
+LANGUAGE plpgsql AS $$
+DECLARE
+    v_total             numeric(10,2);
+    v_valor_pago        numeric(10,2);
+    v_saldo_parcelas    numeric(10,2);
+BEGIN
+    SELECT valor_total
+         + COALESCE(taxa_entrega,0)
+         + COALESCE(acrescimo,0)
+         - COALESCE(desconto,0)
+      INTO v_total
+      FROM public.pedidos
+     WHERE id = p_pedido_id;
+
+    SELECT COALESCE(SUM(valor_pago - troco - valor_encargos),0)
+      INTO v_valor_pago
+      FROM public.pedido_pagamentos
+     WHERE id_pedido = p_pedido_id
+       AND deleted_at IS NULL;
+
+    SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+      INTO v_saldo_parcelas
+      FROM public.contas_receber
+     WHERE id_pedido = p_pedido_id
+       AND cancelado_em IS NULL;
+
+    UPDATE public.pedidos
+       SET valor_pago = v_valor_pago,
+           quitado    = (v_valor_pago >= v_total),
+           finalizado = (v_valor_pago >= v_total) OR (v_saldo_parcelas > 0),
+           updated_at = now()
+     WHERE id = p_pedido_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_pagamento_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago_anteriores  numeric(10,2);
+  v_saldo_parcelas   numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* pagamentos que ABATEM parcela são ignorados aqui */
+  IF NEW.id_conta_receber IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  /* estorno (soft delete) não soma ao pago */
+  IF NEW.deleted_at IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco - valor_encargos),0)
+    INTO v_pago_anteriores
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_parcelas
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL;
+
+  v_restante := v_total - v_pago_anteriores - v_saldo_parcelas;
+
+  IF (NEW.valor_pago - NEW.troco) > v_restante THEN
+     RAISE EXCEPTION
+       'Pagamento %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_pago - NEW.troco, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_parcela_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago             numeric(10,2);
+  v_saldo_outros     numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* Se UPDATE e valor_devido não mudou -> ignora */
+  IF TG_OP = 'UPDATE'
+     AND NEW.valor_devido IS NOT DISTINCT FROM OLD.valor_devido THEN
+        RETURN NEW;
+  END IF;
+
+  IF NEW.cancelado_em IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco - valor_encargos),0)
+    INTO v_pago
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL;
+
+  /* saldo das demais parcelas */
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_outros
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  v_restante := v_total - v_pago - v_saldo_outros;
+
+  IF NEW.valor_devido > v_restante THEN
+     RAISE EXCEPTION
+       'Parcela %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_devido, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pp_alocar_encargos ON public.pedido_pagamentos;
+DROP FUNCTION IF EXISTS public.alocar_encargos_pagamento();
+DROP FUNCTION IF EXISTS public.encargos_conta_receber(uuid, date);
+
+CREATE OR REPLACE FUNCTION public.recalcular_conta_receber(p_conta_id uuid)
+RETURNS void LANGUAGE plpgsql AS $$
+DECLARE
+  v_pago numeric(10,2);
+BEGIN
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago
+    FROM public.pedido_pagamentos
+   WHERE id_conta_receber = p_conta_id
+     AND deleted_at IS NULL;
+
+  UPDATE public.contas_receber
+     SET valor_pago = v_pago,
+         updated_at = now()
+   WHERE id = p_conta_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.recalcular_pagamentos(p_pedido_id uuid)
+RETURNS void
+LANGUAGE plpgsql AS $$
+DECLARE
+    v_total             numeric(10,2);
+    v_valor_pago        numeric(10,2);
+    v_saldo_parcelas    numeric(10,2);
+BEGIN
+    SELECT valor_total
+         + COALESCE(taxa_entrega,0)
+         + COALESCE(acrescimo,0)
+         - COALESCE(desconto,0)
+      INTO v_total
+      FROM public.pedidos
+     WHERE id = p_pedido_id;
+
+    SELECT COALESCE(SUM(valor_pago - troco),0)
+      INTO v_valor_pago
+      FROM public.pedido_pagamentos
+     WHERE id_pedido = p_pedido_id
+       AND deleted_at IS NULL;
+
+    SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+      INTO v_saldo_parcelas
+      FROM public.contas_receber
+     WHERE id_pedido = p_pedido_id
+       AND cancelado_em IS NULL;
+
+    UPDATE public.pedidos
+       SET valor_pago = v_valor_pago,
+           quitado    = (v_valor_pago >= v_total),
+           finalizado = (v_valor_pago >= v_total) OR (v_saldo_parcelas > 0),
+           updated_at = now()
+     WHERE id = p_pedido_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_pagamento_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago_anteriores  numeric(10,2);
+  v_saldo_parcelas   numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* pagamentos que ABATEM parcela são ignorados aqui */
+  IF NEW.id_conta_receber IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  /* estorno (soft delete) não soma ao pago */
+  IF NEW.deleted_at IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago_anteriores
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_parcelas
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL;
+
+  v_restante := v_total - v_pago_anteriores - v_saldo_parcelas;
+
+  IF (NEW.valor_pago - NEW.troco) > v_restante THEN
+     RAISE EXCEPTION
+       'Pagamento %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_pago - NEW.troco, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.enforce_parcela_nao_ultrapassa()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  v_total            numeric(10,2);
+  v_pago             numeric(10,2);
+  v_saldo_outros     numeric(10,2);
+  v_restante         numeric(10,2);
+BEGIN
+  /* Se UPDATE e valor_devido não mudou -> ignora */
+  IF TG_OP = 'UPDATE'
+     AND NEW.valor_devido IS NOT DISTINCT FROM OLD.valor_devido THEN
+        RETURN NEW;
+  END IF;
+
+  IF NEW.cancelado_em IS NOT NULL THEN
+     RETURN NEW;
+  END IF;
+
+  SELECT valor_total
+       + COALESCE(taxa_entrega,0)
+       + COALESCE(acrescimo,0)
+       - COALESCE(desconto,0)
+    INTO v_total
+    FROM public.pedidos
+   WHERE id = NEW.id_pedido;
+
+  SELECT COALESCE(SUM(valor_pago - troco),0)
+    INTO v_pago
+    FROM public.pedido_pagamentos
+   WHERE id_pedido = NEW.id_pedido
+     AND deleted_at IS NULL;
+
+  /* saldo das demais parcelas */
+  SELECT COALESCE(SUM(valor_devido - valor_pago),0)
+    INTO v_saldo_outros
+    FROM public.contas_receber
+   WHERE id_pedido = NEW.id_pedido
+     AND cancelado_em IS NULL
+     AND (TG_OP = 'INSERT' OR id <> OLD.id);
+
+  v_restante := v_total - v_pago - v_saldo_outros;
+
+  IF NEW.valor_devido > v_restante THEN
+     RAISE EXCEPTION
+       'Parcela %.2f excede o restante do pedido (%.2f)',
+       NEW.valor_devido, v_restante
+       USING ERRCODE = 'P0001';
+  END IF;
+
+  RETURN NEW;
+END;
+$$;
+
+ALTER TABLE public.contas_receber
+    DROP COLUMN IF EXISTS encargos_pagos;
+
+ALTER TABLE public.pedido_pagamentos
+    DROP CONSTRAINT IF EXISTS chk_pp_valor_encargos,
+    DROP COLUMN IF EXISTS valor_encargos;
+
+ALTER TABLE public.credito_config
+    DROP CONSTRAINT IF EXISTS chk_credito_config_multa,
+    DROP CONSTRAINT IF EXISTS chk_credito_config_juros,
+    DROP CONSTRAINT IF EXISTS chk_credito_config_carencia,
+    DROP COLUMN IF EXISTS multa_percentual,
+    DROP COLUMN IF EXISTS juros_dia_percentual,
+    DROP COLUMN IF EXISTS dias_carencia_encargos;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,136 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Taxas da adquirente (MDR) e previsão de repasse
+   =========================================================
+   Débito, crédito e vales chegam descontada a taxa da adquirente e
+   dias depois. Cada tenant configura, por forma de pagamento (e por
+   número de parcelas no crédito), o percentual, a tarifa fixa e o
+   prazo de repasse. Ao gravar o pagamento, o gatilho calcula a taxa,
+   o valor líquido que será depositado e a data prevista do depósito.
+
+   Pagamentos anteriores a esta migration ficam com as colunas nulas:
+   as consultas tratam como líquido = bruto, no dia do pagamento.
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Taxa e prazo por forma de pagamento e parcelas
+------------------------------------------------------------
+CREATE TABLE public.taxas_adquirente
+(
+    id                 uuid          NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    tenant_id          uuid          NOT NULL REFERENCES public.tenants (id),
+    id_forma_pagamento smallint      NOT NULL REFERENCES public.formas_pagamento (id),
+    parcelas           smallint      NOT NULL DEFAULT 1,
+    taxa_percentual    numeric(5,2)  NOT NULL DEFAULT 0,
+    taxa_fixa          numeric(10,2) NOT NULL DEFAULT 0,
+    dias_repasse       integer       NOT NULL DEFAULT 0,
+    created_at         timestamptz   NOT NULL DEFAULT now(),
+    updated_at         timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT uq_taxas_adquirente             UNIQUE (tenant_id, id_forma_pagamento, parcelas),
+    CONSTRAINT chk_taxas_adquirente_parcelas   CHECK (parcelas BETWEEN 1 AND 24),
+    CONSTRAINT chk_taxas_adquirente_percentual CHECK (taxa_percentual BETWEEN 0 AND 100),
+    CONSTRAINT chk_taxas_adquirente_fixa       CHECK (taxa_fixa >= 0),
+    CONSTRAINT chk_taxas_adquirente_dias       CHECK (dias_repasse BETWEEN 0 AND 365)
+);
+
+COMMENT ON COLUMN public.taxas_adquirente.parcelas IS 'Parcelas no cartão de crédito; 1 = à vista (e demais formas)';
+COMMENT ON COLUMN public.taxas_adquirente.taxa_percentual IS 'MDR: % descontado pela adquirente sobre o valor do pagamento';
+COMMENT ON COLUMN public.taxas_adquirente.taxa_fixa IS 'Tarifa fixa por transação';
+COMMENT ON COLUMN public.taxas_adquirente.dias_repasse IS 'Dias corridos entre o pagamento e o depósito (D+n)';
+
+CREATE TRIGGER trg_taxas_adquirente_update_updated_at
+    BEFORE UPDATE ON public.taxas_adquirente
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+------------------------------------------------------------
+-- 2) Taxa, líquido e data do repasse em cada pagamento
+------------------------------------------------------------
+ALTER TABLE public.pedido_pagamentos
+    ADD COLUMN parcelas_cartao smallint      NOT NULL DEFAULT 1,
+    ADD COLUMN taxa_adquirente numeric(10,2),
+    ADD COLUMN valor_repasse   numeric(10,2),
+    ADD COLUMN data_repasse    date,
+    ADD CONSTRAINT chk_pp_parcelas_cartao CHECK (parcelas_cartao BETWEEN 1 AND 24);
+
+COMMENT ON COLUMN public.pedido_pagamentos.parcelas_cartao IS 'Parcelas no cartão de crédito (1 = à vista)';
+COMMENT ON COLUMN public.pedido_pagamentos.taxa_adquirente IS 'Taxa da adquirente (MDR + tarifa fixa); NULL nos pagamentos antigos';
+COMMENT ON COLUMN public.pedido_pagamentos.valor_repasse IS 'Valor líquido a ser depositado: valor_pago - troco - taxa_adquirente';
+COMMENT ON COLUMN public.pedido_pagamentos.data_repasse IS 'Data prevista do depósito no fuso do tenant';
+
+CREATE INDEX idx_pp_data_repasse
+        ON public.pedido_pagamentos (data_repasse)
+     WHERE deleted_at IS NULL;
+
+------------------------------------------------------------
+-- 3) Cálculo no INSERT
+------------------------------------------------------------
+-- Procura a taxa da forma com o mesmo número de parcelas; sem ela, a
+-- taxa à vista (parcelas = 1). Forma sem taxa configurada (dinheiro,
+-- PIX direto...) repassa o valor cheio no dia do pagamento.
+CREATE OR REPLACE FUNCTION public.calcular_repasse_pagamento()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_tenant uuid;
+    v_tz     text;
+    v_forma  smallint;
+    v_base   numeric(10,2);
+    v_taxa   record;
+BEGIN
+    SELECT p.tenant_id, COALESCE(t.timezone, 'America/Sao_Paulo')
+      INTO v_tenant, v_tz
+      FROM public.pedidos p
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE p.id = NEW.id_pedido;
+
+    v_forma := public.get_forma_pagamento_id(NEW.forma_pagamento);
+
+    IF NEW.parcelas_cartao > 1 AND v_forma IS DISTINCT FROM 3 THEN   -- 3 = CARTAO_CREDITO
+        RAISE EXCEPTION 'Parcelamento (%x) só no cartão de crédito', NEW.parcelas_cartao
+              USING ERRCODE = 'P0001';
+    END IF;
+
+    v_base := NEW.valor_pago - COALESCE(NEW.troco, 0);
+
+    SELECT ta.taxa_percentual, ta.taxa_fixa, ta.dias_repasse
+      INTO v_taxa
+      FROM public.taxas_adquirente ta
+     WHERE ta.tenant_id = v_tenant
+       AND ta.id_forma_pagamento = v_forma
+       AND ta.parcelas IN (NEW.parcelas_cartao, 1)
+     ORDER BY ta.parcelas DESC
+     LIMIT 1;
+
+    IF FOUND THEN
+        NEW.taxa_adquirente := LEAST(round(v_base * v_taxa.taxa_percentual / 100, 2) + v_taxa.taxa_fixa, v_base);
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date + v_taxa.dias_repasse;
+    ELSE
+        NEW.taxa_adquirente := 0;
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date;
+    END IF;
+    NEW.valor_repasse := v_base - NEW.taxa_adquirente;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_pp_repasse_adquirente
+    BEFORE INSERT ON public.pedido_pagamentos
+    FOR EACH ROW EXECUTE FUNCTION public.calcular_repasse_pagamento();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS trg_pp_repasse_adquirente ON public.pedido_pagamentos;
+DROP FUNCTION IF EXISTS public.calcular_repasse_pagamento();
+
+DROP INDEX IF EXISTS public.idx_pp_data_repasse;
+
+ALTER TABLE public.pedido_pagamentos
+    DROP CONSTRAINT IF EXISTS chk_pp_parcelas_cartao,
+    DROP COLUMN IF EXISTS parcelas_cartao,
+    DROP COLUMN IF EXISTS taxa_adquirente,
+    DROP COLUMN IF EXISTS valor_repasse,
+    DROP COLUMN IF EXISTS data_repasse;
+
+DROP TABLE IF EXISTS public.taxas_adquirente;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – pedido_feed.created_at no momento do INSERT
+   =========================================================
+   now() é o início da transação. A retomada pelo Last-Event-ID volta
+   uma janela de created_at a partir do cursor para reenviar eventos de
+   id menor confirmados depois; para isso created_at precisa andar junto
+   com o id, que é atribuído no INSERT.
+   ========================================================= */
+ALTER TABLE public.pedido_feed
+    ALTER COLUMN created_at SET DEFAULT clock_timestamp();
+---- create above / drop below ----
+ALTER TABLE public.pedido_feed
+    ALTER COLUMN created_at SET DEFAULT now();
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – excedente do dinheiro recebido na entrega
+   =========================================================
+   O pagamento da entrega é gravado no acerto pelo saldo do pedido
+   naquele momento; se o cliente pagou outra parte depois da saída, o
+   que o entregador recebeu a mais não vira pagamento e fica registrado
+   na parada, para devolução.
+   ========================================================= */
+ALTER TABLE public.rota_entrega_pedidos
+    ADD COLUMN valor_excedente numeric(10,2) NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_rota_entrega_pedidos_excedente CHECK (valor_excedente >= 0);
+
+COMMENT ON COLUMN public.rota_entrega_pedidos.valor_excedente IS 'Recebido além do saldo do pedido no acerto; não vira pagamento';
+---- create above / drop below ----
+ALTER TABLE public.rota_entrega_pedidos
+    DROP CONSTRAINT IF EXISTS chk_rota_entrega_pedidos_excedente,
+    DROP COLUMN IF EXISTS valor_excedente;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
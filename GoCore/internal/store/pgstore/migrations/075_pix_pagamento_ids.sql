@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – txid e id fim a fim do pagamento PIX
+   =========================================================
+   A confirmação PIX é idempotente: a mesma transferência (e2e_id) ou,
+   sem e2e_id, o mesmo txid e valor confirmados de novo no pedido
+   devolvem o pagamento já gravado. Os ids saem da observação para
+   colunas próprias.
+   ========================================================= */
+ALTER TABLE public.pedido_pagamentos
+    ADD COLUMN pix_txid   varchar(25),
+    ADD COLUMN pix_e2e_id varchar(32);
+
+COMMENT ON COLUMN public.pedido_pagamentos.pix_txid IS 'txid da cobrança PIX (campo 62-05 do BR Code)';
+COMMENT ON COLUMN public.pedido_pagamentos.pix_e2e_id IS 'Id fim a fim da transferência PIX no Banco Central';
+
+-- Confirmações anteriores: "PIX txid <txid>[ E2E <e2e>]"
+UPDATE public.pedido_pagamentos
+   SET pix_txid   = substring(observacao FROM '^PIX txid ([A-Za-z0-9]{1,25})'),
+       pix_e2e_id = substring(observacao FROM ' E2E ([A-Za-z0-9]{32})')
+ WHERE forma_pagamento = 'PIX'
+   AND observacao LIKE 'PIX txid %';
+
+CREATE UNIQUE INDEX uidx_pagamentos_pix_e2e
+        ON public.pedido_pagamentos (id_pedido, pix_e2e_id)
+     WHERE pix_e2e_id IS NOT NULL AND deleted_at IS NULL;
+---- create above / drop below ----
+DROP INDEX IF EXISTS public.uidx_pagamentos_pix_e2e;
+
+ALTER TABLE public.pedido_pagamentos
+    DROP COLUMN IF EXISTS pix_txid,
+    DROP COLUMN IF EXISTS pix_e2e_id;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
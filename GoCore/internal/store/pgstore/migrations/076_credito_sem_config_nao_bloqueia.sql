@@ -0,0 +1,87 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – crédito: sem configuração, atraso não bloqueia
+   =========================================================
+   Tenant que nunca salvou credito_config não escolheu bloquear clientes
+   com parcela vencida; a política padrão passa a só registrar o atraso.
+   Quem quiser o bloqueio liga bloquear_atraso na configuração.
+   ========================================================= */
+ALTER TABLE public.credito_config
+    ALTER COLUMN bloquear_atraso SET DEFAULT false;
+
+CREATE OR REPLACE FUNCTION public.situacao_credito_cliente(p_id_cliente uuid, p_data date)
+RETURNS TABLE (limite            numeric(10,2),
+               bloqueado         boolean,
+               bloquear_atraso   boolean,
+               dias_tolerancia   integer,
+               saldo_aberto      numeric(10,2),
+               saldo_vencido     numeric(10,2),
+               maior_atraso_dias integer)
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT COALESCE(cc.limite, cfg.limite_padrao),
+           COALESCE(cc.bloqueado, false),
+           COALESCE(cfg.bloquear_atraso, false),
+           COALESCE(cfg.dias_tolerancia_atraso, 0),
+           COALESCE(a.saldo_aberto, 0)::numeric(10,2),
+           COALESCE(a.saldo_vencido, 0)::numeric(10,2),
+           COALESCE(a.maior_atraso_dias, 0)
+      FROM public.clientes c
+      LEFT JOIN public.clientes_credito cc ON cc.id_cliente = c.id
+      LEFT JOIN public.credito_config cfg  ON cfg.tenant_id = c.tenant_id
+      LEFT JOIN LATERAL (
+            SELECT SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0))                                     AS saldo_aberto,
+                   SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0)) FILTER (WHERE cr.vencimento < p_data) AS saldo_vencido,
+                   MAX(p_data - cr.vencimento) FILTER (WHERE cr.vencimento < p_data)                     AS maior_atraso_dias
+              FROM public.contas_receber cr
+              JOIN public.pedidos p ON p.id = cr.id_pedido
+             WHERE p.id_cliente = c.id
+               AND p.id_status <> 6                                   -- 6 = Cancelado
+               AND p.deleted_at IS NULL
+               AND cr.cancelado_em IS NULL
+               AND COALESCE(cr.valor_pago, 0) < cr.valor_devido
+      ) a ON true
+     WHERE c.id = p_id_cliente;
+$$;
+---- create above / drop below ----
+ALTER TABLE public.credito_config
+    ALTER COLUMN bloquear_atraso SET DEFAULT true;
+
+CREATE OR REPLACE FUNCTION public.situacao_credito_cliente(p_id_cliente uuid, p_data date)
+RETURNS TABLE (limite            numeric(10,2),
+               bloqueado         boolean,
+               bloquear_atraso   boolean,
+               dias_tolerancia   integer,
+               saldo_aberto      numeric(10,2),
+               saldo_vencido     numeric(10,2),
+               maior_atraso_dias integer)
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT COALESCE(cc.limite, cfg.limite_padrao),
+           COALESCE(cc.bloqueado, false),
+           COALESCE(cfg.bloquear_atraso, true),
+           COALESCE(cfg.dias_tolerancia_atraso, 0),
+           COALESCE(a.saldo_aberto, 0)::numeric(10,2),
+           COALESCE(a.saldo_vencido, 0)::numeric(10,2),
+           COALESCE(a.maior_atraso_dias, 0)
+      FROM public.clientes c
+      LEFT JOIN public.clientes_credito cc ON cc.id_cliente = c.id
+      LEFT JOIN public.credito_config cfg  ON cfg.tenant_id = c.tenant_id
+      LEFT JOIN LATERAL (
+            SELECT SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0))                                     AS saldo_aberto,
+                   SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0)) FILTER (WHERE cr.vencimento < p_data) AS saldo_vencido,
+                   MAX(p_data - cr.vencimento) FILTER (WHERE cr.vencimento < p_data)                     AS maior_atraso_dias
+              FROM public.contas_receber cr
+              JOIN public.pedidos p ON p.id = cr.id_pedido
+             WHERE p.id_cliente = c.id
+               AND p.id_status <> 6                                   -- 6 = Cancelado
+               AND p.deleted_at IS NULL
+               AND cr.cancelado_em IS NULL
+               AND COALESCE(cr.valor_pago, 0) < cr.valor_devido
+      ) a ON true
+     WHERE c.id = p_id_cliente;
+$$;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,157 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Realoca os encargos quando um pagamento da parcela sai
+   =========================================================
+   alocar_encargos_pagamento (071) só roda no INSERT. Estornado um
+   pagamento (soft delete) ou restaurado, os pagamentos seguintes da
+   parcela ficavam com o valor_encargos calculado sobre um histórico
+   que não existe mais. Agora trg_pp_recalc_cr refaz a alocação de
+   todos os pagamentos vivos da parcela, em ordem, antes de recalcular
+   principal e encargos pagos.
+   ========================================================= */
+
+------------------------------------------------------------
+-- 1) Realocação dos encargos da parcela
+------------------------------------------------------------
+-- Percorre os pagamentos vivos por (created_at, id). Os de dias
+-- anteriores já estão realocados; os do mesmo dia a partir do atual
+-- ainda têm o valor antigo, que é devolvido ao pendente. Só grava o
+-- que mudou.
+CREATE OR REPLACE FUNCTION public.realocar_encargos_conta_receber(p_conta_id uuid)
+RETURNS void
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_tz       text;
+    v_pendente numeric(10,2);
+    v_encargos numeric(10,2);
+    r          record;
+BEGIN
+    PERFORM 1 FROM public.contas_receber WHERE id = p_conta_id FOR UPDATE;
+
+    SELECT COALESCE(t.timezone, 'America/Sao_Paulo')
+      INTO v_tz
+      FROM public.contas_receber cr
+      JOIN public.pedidos p ON p.id = cr.id_pedido
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE cr.id = p_conta_id;
+
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    -- a realocação só troca valor_encargos; o pagamento continua no caixa
+    PERFORM set_config('app.realocando_encargos', 'on', true);
+
+    FOR r IN
+        SELECT pp.id, pp.created_at, pp.valor_pago - pp.troco AS liquido, pp.valor_encargos,
+               (pp.created_at AT TIME ZONE v_tz)::date AS dia
+          FROM public.pedido_pagamentos pp
+         WHERE pp.id_conta_receber = p_conta_id
+           AND pp.deleted_at IS NULL
+         ORDER BY pp.created_at, pp.id
+    LOOP
+        SELECT GREATEST(e.multa + e.juros - e.encargos_pagos + (
+                   SELECT COALESCE(SUM(o.valor_encargos), 0)
+                     FROM public.pedido_pagamentos o
+                    WHERE o.id_conta_receber = p_conta_id
+                      AND o.deleted_at IS NULL
+                      AND (o.created_at AT TIME ZONE v_tz)::date = r.dia
+                      AND (o.created_at, o.id) >= (r.created_at, r.id)), 0)
+          INTO v_pendente
+          FROM public.encargos_conta_receber(p_conta_id, r.dia) e;
+
+        v_encargos := LEAST(COALESCE(v_pendente, 0), GREATEST(r.liquido, 0));
+
+        IF v_encargos <> r.valor_encargos THEN
+            UPDATE public.pedido_pagamentos
+               SET valor_encargos = v_encargos
+             WHERE id = r.id;
+        END IF;
+    END LOOP;
+
+    PERFORM set_config('app.realocando_encargos', '', true);
+END;
+$$;
+
+------------------------------------------------------------
+-- 2) Estorno ou restauração de pagamento realoca a parcela
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.trg_pp_recalc_cr()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+     IF OLD.id_conta_receber IS NOT NULL THEN
+        PERFORM public.recalcular_conta_receber(OLD.id_conta_receber);
+     END IF;
+  ELSE
+     IF NEW.id_conta_receber IS NOT NULL THEN
+        IF TG_OP = 'UPDATE' AND OLD.deleted_at IS DISTINCT FROM NEW.deleted_at THEN
+           PERFORM public.realocar_encargos_conta_receber(NEW.id_conta_receber);
+        END IF;
+        PERFORM public.recalcular_conta_receber(NEW.id_conta_receber);
+     END IF;
+  END IF;
+  RETURN NEW;
+END;
+$$;
+
+------------------------------------------------------------
+-- 3) Realocação de encargos não tira o pagamento do caixa
+------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.estornar_pagamento_caixa()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND current_setting('app.realocando_encargos', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+
+    DELETE FROM public.caixa_movimentacoes cm
+     USING public.caixas c
+     WHERE cm.id_pagamento = OLD.id
+       AND cm.tipo = 'P'
+       AND cm.deleted_at IS NULL
+       AND c.id = cm.id_caixa
+       AND c.status = 'A';
+    RETURN OLD;
+END;
+$$;
+
+---- create above / drop below ----
+CREATE OR REPLACE FUNCTION public.estornar_pagamento_caixa()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM public.caixa_movimentacoes cm
+     USING public.caixas c
+     WHERE cm.id_pagamento = OLD.id
+       AND cm.tipo = 'P'
+       AND cm.deleted_at IS NULL
+       AND c.id = cm.id_caixa
+       AND c.status = 'A';
+    RETURN OLD;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION public.trg_pp_recalc_cr()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+     IF OLD.id_conta_receber IS NOT NULL THEN
+        PERFORM public.recalcular_conta_receber(OLD.id_conta_receber);
+     END IF;
+  ELSE
+     IF NEW.id_conta_receber IS NOT NULL THEN
+        PERFORM public.recalcular_conta_receber(NEW.id_conta_receber);
+     END IF;
+  END IF;
+  RETURN NEW;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS public.realocar_encargos_conta_receber(uuid);
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,126 @@
+-- Write your migrate up statements here
+/* =========================================================
+   UP – Crédito parcelado sem taxa configurada
+   =========================================================
+   calcular_repasse_pagamento (072) caía na taxa à vista quando não
+   havia taxa para o número de parcelas do crédito, o que subestima o
+   MDR do parcelado. Agora cada número de parcelas precisa da sua taxa:
+   se a forma tem alguma taxa configurada e falta a do parcelamento
+   usado, o pagamento é recusado. Forma sem nenhuma taxa continua
+   repassando o valor cheio no dia.
+
+   O repasse do parcelado é gravado numa data só: considera-se a
+   antecipação integral pela adquirente, em D+dias_repasse da taxa
+   daquele parcelamento.
+   ========================================================= */
+
+CREATE OR REPLACE FUNCTION public.calcular_repasse_pagamento()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_tenant uuid;
+    v_tz     text;
+    v_forma  smallint;
+    v_base   numeric(10,2);
+    v_taxa   record;
+BEGIN
+    SELECT p.tenant_id, COALESCE(t.timezone, 'America/Sao_Paulo')
+      INTO v_tenant, v_tz
+      FROM public.pedidos p
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE p.id = NEW.id_pedido;
+
+    v_forma := public.get_forma_pagamento_id(NEW.forma_pagamento);
+
+    IF NEW.parcelas_cartao > 1 AND v_forma IS DISTINCT FROM 3 THEN   -- 3 = CARTAO_CREDITO
+        RAISE EXCEPTION 'Parcelamento (%x) só no cartão de crédito', NEW.parcelas_cartao
+              USING ERRCODE = 'P0001';
+    END IF;
+
+    v_base := NEW.valor_pago - COALESCE(NEW.troco, 0);
+
+    SELECT ta.taxa_percentual, ta.taxa_fixa, ta.dias_repasse
+      INTO v_taxa
+      FROM public.taxas_adquirente ta
+     WHERE ta.tenant_id = v_tenant
+       AND ta.id_forma_pagamento = v_forma
+       AND ta.parcelas = NEW.parcelas_cartao;
+
+    IF FOUND THEN
+        NEW.taxa_adquirente := LEAST(round(v_base * v_taxa.taxa_percentual / 100, 2) + v_taxa.taxa_fixa, v_base);
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date + v_taxa.dias_repasse;
+    ELSE
+        -- parcelado sem a própria taxa não usa a do à vista
+        IF NEW.parcelas_cartao > 1 AND EXISTS (
+               SELECT 1
+                 FROM public.taxas_adquirente ta
+                WHERE ta.tenant_id = v_tenant
+                  AND ta.id_forma_pagamento = v_forma) THEN
+            RAISE EXCEPTION 'Taxa do crédito em %x não configurada', NEW.parcelas_cartao
+                  USING ERRCODE = 'P0001';
+        END IF;
+        NEW.taxa_adquirente := 0;
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date;
+    END IF;
+    NEW.valor_repasse := v_base - NEW.taxa_adquirente;
+
+    RETURN NEW;
+END;
+$$;
+
+COMMENT ON COLUMN public.pedido_pagamentos.data_repasse IS 'Data prevista do depósito no fuso do tenant; no crédito parcelado, antecipação integral';
+COMMENT ON COLUMN public.taxas_adquirente.parcelas IS 'Parcelas no cartão de crédito; 1 = à vista (e demais formas). Cada parcelamento aceito precisa da sua taxa';
+---- create above / drop below ----
+COMMENT ON COLUMN public.pedido_pagamentos.data_repasse IS 'Data prevista do depósito no fuso do tenant';
+COMMENT ON COLUMN public.taxas_adquirente.parcelas IS 'Parcelas no cartão de crédito; 1 = à vista (e demais formas)';
+
+CREATE OR REPLACE FUNCTION public.calcular_repasse_pagamento()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_tenant uuid;
+    v_tz     text;
+    v_forma  smallint;
+    v_base   numeric(10,2);
+    v_taxa   record;
+BEGIN
+    SELECT p.tenant_id, COALESCE(t.timezone, 'America/Sao_Paulo')
+      INTO v_tenant, v_tz
+      FROM public.pedidos p
+      JOIN public.tenants t ON t.id = p.tenant_id
+     WHERE p.id = NEW.id_pedido;
+
+    v_forma := public.get_forma_pagamento_id(NEW.forma_pagamento);
+
+    IF NEW.parcelas_cartao > 1 AND v_forma IS DISTINCT FROM 3 THEN   -- 3 = CARTAO_CREDITO
+        RAISE EXCEPTION 'Parcelamento (%x) só no cartão de crédito', NEW.parcelas_cartao
+              USING ERRCODE = 'P0001';
+    END IF;
+
+    v_base := NEW.valor_pago - COALESCE(NEW.troco, 0);
+
+    SELECT ta.taxa_percentual, ta.taxa_fixa, ta.dias_repasse
+      INTO v_taxa
+      FROM public.taxas_adquirente ta
+     WHERE ta.tenant_id = v_tenant
+       AND ta.id_forma_pagamento = v_forma
+       AND ta.parcelas IN (NEW.parcelas_cartao, 1)
+     ORDER BY ta.parcelas DESC
+     LIMIT 1;
+
+    IF FOUND THEN
+        NEW.taxa_adquirente := LEAST(round(v_base * v_taxa.taxa_percentual / 100, 2) + v_taxa.taxa_fixa, v_base);
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date + v_taxa.dias_repasse;
+    ELSE
+        NEW.taxa_adquirente := 0;
+        NEW.data_repasse    := (NEW.created_at AT TIME ZONE v_tz)::date;
+    END IF;
+    NEW.valor_repasse := v_base - NEW.taxa_adquirente;
+
+    RETURN NEW;
+END;
+$$;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -56,6 +56,32 @@ func (ns NullStatusCaixa) Value() (driver.Value, error) {
 }
 
 // Sessões de caixa - abertura & fechamento (status_caixa ENUM)
+type AgendamentoConfig struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Ativo    bool      `json:"ativo"`
+	// Tamanho da faixa de horário; divide o dia (meia-noite no fuso do tenant)
+	IntervaloMinutos int32 `json:"intervalo_minutos"`
+	// Máximo de pedidos por faixa; NULL = sem limite
+	CapacidadePorFaixa pgtype.Int4 `json:"capacidade_por_faixa"`
+	// Minutos antes de agendado_para em que o pedido vai para a cozinha
+	AntecedenciaLiberacaoMin int32 `json:"antecedencia_liberacao_min"`
+	// Antecedência mínima, em minutos, para agendar
+	AntecedenciaMinimaMin int32     `json:"antecedencia_minima_min"`
+	DiasMaximos           int32     `json:"dias_maximos"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type AgendamentoFaixa struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	// 0=domingo … 6=sábado
+	DiaSemana  int16       `json:"dia_semana"`
+	HoraInicio pgtype.Time `json:"hora_inicio"`
+	HoraFim    pgtype.Time `json:"hora_fim"`
+	// Pedidos por faixa nesse horário; 0 = não aceita agendamento
+	Capacidade int32 `json:"capacidade"`
+}
+
 type Caixa struct {
 	ID                   uuid.UUID          `json:"id"`
 	SeqID                int64              `json:"seq_id"`
@@ -91,7 +117,7 @@ type CaixaMovimentaco struct {
 	ID      uuid.UUID `json:"id"`
 	SeqID   int64     `json:"seq_id"`
 	IDCaixa uuid.UUID `json:"id_caixa"`
-	// S=Sangria (saída), U=Suprimento (entrada), P=Pagamento (entrada)
+	// S=Sangria (saída), U=Suprimento (entrada), P=Pagamento (entrada), E=Estorno de pagamento (saída)
 	Tipo             string             `json:"tipo"`
 	IDFormaPagamento pgtype.Int2        `json:"id_forma_pagamento"`
 	Valor            pgtype.Numeric     `json:"valor"`
@@ -169,6 +195,10 @@ type Categoria struct {
 	// Data e hora de exclusão lógica (soft delete)
 	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
 	TipoVisualizacao pgtype.Int4        `json:"tipo_visualizacao"`
+	// Estação de produção padrão dos produtos da categoria
+	IDEstacao pgtype.UUID `json:"id_estacao"`
+	// Quantidade máxima de sabores por item (só vale quando opcao_meia permite)
+	MaxSabores int16 `json:"max_sabores"`
 }
 
 // Tipos de adicionais disponíveis em cada categoria do cardápio
@@ -314,6 +344,41 @@ type Cliente struct {
 	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type ClientesCredito struct {
+	IDCliente uuid.UUID `json:"id_cliente"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	// Limite de crédito do cliente; NULL = limite padrão do tenant
+	Limite pgtype.Numeric `json:"limite"`
+	// Crédito suspenso: nenhuma parcela nova sem gerente
+	Bloqueado  bool        `json:"bloqueado"`
+	Observacao pgtype.Text `json:"observacao"`
+	UpdatedBy  pgtype.UUID `json:"updated_by"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type ComboSlot struct {
+	ID uuid.UUID `json:"id"`
+	// o produto combo
+	IDProduto uuid.UUID `json:"id_produto"`
+	Nome      string    `json:"nome"`
+	// categoria dos produtos permitidos
+	IDCategoria uuid.UUID `json:"id_categoria"`
+	// opção exigida (ex.: Grande, 2L); NULL = qualquer
+	IDCategoriaOpcao pgtype.UUID        `json:"id_categoria_opcao"`
+	Quantidade       int32              `json:"quantidade"`
+	Ordem            int32              `json:"ordem"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type ComboSlotAcrescimo struct {
+	ID          uuid.UUID      `json:"id"`
+	IDComboSlot uuid.UUID      `json:"id_combo_slot"`
+	IDProduto   uuid.UUID      `json:"id_produto"`
+	Valor       pgtype.Numeric `json:"valor"`
+}
+
 type ContasReceber struct {
 	ID          uuid.UUID      `json:"id"`
 	IDPedido    uuid.UUID      `json:"id_pedido"`
@@ -324,6 +389,30 @@ type ContasReceber struct {
 	Quitado     pgtype.Bool    `json:"quitado"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	// Data do cancelamento da parcela (cancelamento do pedido)
+	CanceladoEm        pgtype.Timestamptz `json:"cancelado_em"`
+	MotivoCancelamento pgtype.Text        `json:"motivo_cancelamento"`
+	// Gerente que autorizou a parcela acima do limite ou com cliente em atraso
+	AutorizadoPor pgtype.UUID `json:"autorizado_por"`
+	// Multa e juros já pagos; valor_pago guarda só o principal
+	EncargosPagos pgtype.Numeric `json:"encargos_pagos"`
+}
+
+type CreditoConfig struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	// Limite dos clientes sem limite próprio; NULL = sem limite
+	LimitePadrao pgtype.Numeric `json:"limite_padrao"`
+	// Recusa parcela nova de cliente com parcela vencida além da tolerância
+	BloquearAtraso bool `json:"bloquear_atraso"`
+	// Dias de atraso aceitos antes do bloqueio
+	DiasToleranciaAtraso int32     `json:"dias_tolerancia_atraso"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	// Multa por atraso, % do principal em aberto no vencimento
+	MultaPercentual pgtype.Numeric `json:"multa_percentual"`
+	// Juros de mora ao dia, % pró-rata sobre o principal em aberto
+	JurosDiaPercentual pgtype.Numeric `json:"juros_dia_percentual"`
+	// Dias após o vencimento sem multa nem juros
+	DiasCarenciaEncargos int32 `json:"dias_carencia_encargos"`
 }
 
 type Culinaria struct {
@@ -332,6 +421,81 @@ type Culinaria struct {
 	MeioMeio    int16  `json:"meio_meio"`
 }
 
+type Cupon struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	// Código digitado pelo cliente; único por tenant, sem diferenciar maiúsculas
+	Codigo    string      `json:"codigo"`
+	Descricao pgtype.Text `json:"descricao"`
+	// P=Percentual, V=Valor fixo, F=Frete grátis
+	Tipo string `json:"tipo"`
+	// Percentual (0-100) ou valor em reais; ignorado no frete grátis
+	Valor pgtype.Numeric `json:"valor"`
+	// Teto do desconto percentual
+	DescontoMaximo pgtype.Numeric `json:"desconto_maximo"`
+	// Soma mínima dos itens (sem taxa de entrega) para aceitar o cupom
+	ValorMinimoPedido pgtype.Numeric     `json:"valor_minimo_pedido"`
+	Inicio            time.Time          `json:"inicio"`
+	Fim               pgtype.Timestamptz `json:"fim"`
+	LimiteTotal       pgtype.Int4        `json:"limite_total"`
+	LimitePorCliente  pgtype.Int4        `json:"limite_por_cliente"`
+	// Resgates ativos; mantido por cupom_resgates
+	Usos      int32              `json:"usos"`
+	Ativo     bool               `json:"ativo"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type CupomCategoria struct {
+	IDCupom     uuid.UUID `json:"id_cupom"`
+	IDCategoria uuid.UUID `json:"id_categoria"`
+}
+
+type CupomProduto struct {
+	IDCupom   uuid.UUID `json:"id_cupom"`
+	IDProduto uuid.UUID `json:"id_produto"`
+}
+
+type CupomResgate struct {
+	ID            uuid.UUID      `json:"id"`
+	IDCupom       uuid.UUID      `json:"id_cupom"`
+	IDPedido      uuid.UUID      `json:"id_pedido"`
+	IDCliente     uuid.UUID      `json:"id_cliente"`
+	ValorDesconto pgtype.Numeric `json:"valor_desconto"`
+	CreatedAt     time.Time      `json:"created_at"`
+	// Pedido cancelado, excluído ou editado com outro cupom; o uso volta para o cupom
+	CanceladoEm pgtype.Timestamptz `json:"cancelado_em"`
+}
+
+type Entregadore struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	// Usuário do app do entregador (login em /api/v1/mobile)
+	IDUsuario pgtype.UUID `json:"id_usuario"`
+	Nome      string      `json:"nome"`
+	Telefone  pgtype.Text `json:"telefone"`
+	Veiculo   pgtype.Text `json:"veiculo"`
+	Placa     pgtype.Text `json:"placa"`
+	// Valor pago ao entregador por pedido entregue
+	TaxaPorEntrega pgtype.Numeric     `json:"taxa_por_entrega"`
+	Ativo          bool               `json:"ativo"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type EstacoesProducao struct {
+	ID        uuid.UUID          `json:"id"`
+	TenantID  uuid.UUID          `json:"tenant_id"`
+	Nome      string             `json:"nome"`
+	Ordem     int32              `json:"ordem"`
+	Ativo     bool               `json:"ativo"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
 type FormasPagamento struct {
 	ID     int16       `json:"id"`
 	Codigo string      `json:"codigo"`
@@ -341,6 +505,22 @@ type FormasPagamento struct {
 	Ordem  pgtype.Int2 `json:"ordem"`
 }
 
+// Respostas guardadas por Idempotency-Key para repetir em reenvios do cliente
+type IdempotencyKey struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Chave    string    `json:"chave"`
+	Metodo   string    `json:"metodo"`
+	Rota     string    `json:"rota"`
+	// SHA-256 de método, rota e corpo; outro payload com a mesma chave é rejeitado
+	HashRequisicao string `json:"hash_requisicao"`
+	// Nulo enquanto a primeira requisição está em processamento
+	StatusCode  pgtype.Int4        `json:"status_code"`
+	ContentType pgtype.Text        `json:"content_type"`
+	Resposta    []byte             `json:"resposta"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ConcluidoEm pgtype.Timestamptz `json:"concluido_em"`
+}
+
 type OperadoresCaixa struct {
 	ID        uuid.UUID          `json:"id"`
 	SeqID     int64              `json:"seq_id"`
@@ -368,17 +548,20 @@ type OperadoresCaixaView struct {
 }
 
 type OutboxEvent struct {
-	ID            int64       `json:"id"`
-	AggregateType string      `json:"aggregate_type"`
-	AggregateID   string      `json:"aggregate_id"`
-	EventType     string      `json:"event_type"`
-	Payload       []byte      `json:"payload"`
-	CreatedAt     time.Time   `json:"created_at"`
-	Processed     bool        `json:"processed"`
-	Attempts      int32       `json:"attempts"`
-	LastError     pgtype.Text `json:"last_error"`
-	TenantID      uuid.UUID   `json:"tenant_id"`
-	UserID        uuid.UUID   `json:"user_id"`
+	ID            int64              `json:"id"`
+	AggregateType string             `json:"aggregate_type"`
+	AggregateID   string             `json:"aggregate_id"`
+	EventType     string             `json:"event_type"`
+	Payload       []byte             `json:"payload"`
+	CreatedAt     time.Time          `json:"created_at"`
+	Processed     bool               `json:"processed"`
+	Attempts      int32              `json:"attempts"`
+	LastError     pgtype.Text        `json:"last_error"`
+	TenantID      uuid.UUID          `json:"tenant_id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	ProcessedAt   pgtype.Timestamptz `json:"processed_at"`
+	DeadLetter    bool               `json:"dead_letter"`
 }
 
 type Pedido struct {
@@ -414,6 +597,43 @@ type Pedido struct {
 	Desconto           pgtype.Numeric     `json:"desconto"`
 	Acrescimo          pgtype.Numeric     `json:"acrescimo"`
 	Finalizado         bool               `json:"finalizado"`
+	// Usuário (gerente) que liberou itens fora da janela de disponibilidade
+	DisponibilidadeLiberadaPor pgtype.UUID `json:"disponibilidade_liberada_por"`
+	// Zona que definiu taxa_entrega/nome_taxa_entrega
+	IDZonaEntrega pgtype.UUID `json:"id_zona_entrega"`
+	// Horário marcado para a entrega/retirada do pedido agendado
+	AgendadoPara pgtype.Timestamptz `json:"agendado_para"`
+	// Quando o pedido agendado entrou na fila da cozinha
+	LiberadoEm pgtype.Timestamptz `json:"liberado_em"`
+	// Usuário que agendou; a liberação automática é registrada em seu nome
+	AgendadoPor pgtype.UUID `json:"agendado_por"`
+}
+
+// Auditoria dos cancelamentos de pedido
+type PedidoCancelamento struct {
+	ID             uuid.UUID   `json:"id"`
+	TenantID       uuid.UUID   `json:"tenant_id"`
+	IDPedido       uuid.UUID   `json:"id_pedido"`
+	StatusAnterior int16       `json:"status_anterior"`
+	Motivo         string      `json:"motivo"`
+	UserID         pgtype.UUID `json:"user_id"`
+	// Gerente que autorizou o cancelamento de pedido com pagamento
+	AutorizadoPor  pgtype.UUID    `json:"autorizado_por"`
+	ValorEstornado pgtype.Numeric `json:"valor_estornado"`
+	// Pagamentos estornados e como cada um saiu do caixa
+	Pagamentos []byte `json:"pagamentos"`
+	// Parcelas de contas a receber canceladas
+	Parcelas  []byte    `json:"parcelas"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PedidoFeed struct {
+	ID        int64     `json:"id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	PedidoID  uuid.UUID `json:"pedido_id"`
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type PedidoItemAdicionai struct {
@@ -440,20 +660,36 @@ type PedidoItemAdicionaisView struct {
 	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type PedidoItemSabore struct {
+	ID           uuid.UUID          `json:"id"`
+	SeqID        int64              `json:"seq_id"`
+	IDPedidoItem uuid.UUID          `json:"id_pedido_item"`
+	IDProduto    uuid.UUID          `json:"id_produto"`
+	Partes       int16              `json:"partes"`
+	Valor        pgtype.Numeric     `json:"valor"`
+	Ordem        int16              `json:"ordem"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type PedidoIten struct {
-	ID               uuid.UUID          `json:"id"`
-	SeqID            int64              `json:"seq_id"`
-	IDPedido         uuid.UUID          `json:"id_pedido"`
-	IDProduto        uuid.UUID          `json:"id_produto"`
-	IDProduto2       pgtype.UUID        `json:"id_produto_2"`
-	IDCategoria      uuid.UUID          `json:"id_categoria"`
-	IDCategoriaOpcao pgtype.UUID        `json:"id_categoria_opcao"`
-	Observacao       pgtype.Text        `json:"observacao"`
-	ValorUnitario    pgtype.Numeric     `json:"valor_unitario"`
-	Quantidade       int32              `json:"quantidade"`
-	CreatedAt        time.Time          `json:"created_at"`
-	UpdatedAt        time.Time          `json:"updated_at"`
-	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	ID                uuid.UUID          `json:"id"`
+	SeqID             int64              `json:"seq_id"`
+	IDPedido          uuid.UUID          `json:"id_pedido"`
+	IDProduto         uuid.UUID          `json:"id_produto"`
+	IDProduto2        pgtype.UUID        `json:"id_produto_2"`
+	IDCategoria       uuid.UUID          `json:"id_categoria"`
+	IDCategoriaOpcao  pgtype.UUID        `json:"id_categoria_opcao"`
+	Observacao        pgtype.Text        `json:"observacao"`
+	ValorUnitario     pgtype.Numeric     `json:"valor_unitario"`
+	Quantidade        int32              `json:"quantidade"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	IDEstacao         pgtype.UUID        `json:"id_estacao"`
+	StatusPreparo     string             `json:"status_preparo"`
+	DataInicioPreparo pgtype.Timestamptz `json:"data_inicio_preparo"`
+	DataFimPreparo    pgtype.Timestamptz `json:"data_fim_preparo"`
+	DataDespacho      pgtype.Timestamptz `json:"data_despacho"`
 }
 
 type PedidoItensView struct {
@@ -486,6 +722,20 @@ type PedidoPagamento struct {
 	CreatedAt          time.Time          `json:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at"`
 	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	// Parte do pagamento da parcela que quitou multa e juros (não abate o principal)
+	ValorEncargos pgtype.Numeric `json:"valor_encargos"`
+	// Parcelas no cartão de crédito (1 = à vista)
+	ParcelasCartao int16 `json:"parcelas_cartao"`
+	// Taxa da adquirente (MDR + tarifa fixa); NULL nos pagamentos antigos
+	TaxaAdquirente pgtype.Numeric `json:"taxa_adquirente"`
+	// Valor líquido a ser depositado: valor_pago - troco - taxa_adquirente
+	ValorRepasse pgtype.Numeric `json:"valor_repasse"`
+	// Data prevista do depósito no fuso do tenant; no crédito parcelado, antecipação integral
+	DataRepasse pgtype.Date `json:"data_repasse"`
+	// txid da cobrança PIX (campo 62-05 do BR Code)
+	PixTxid pgtype.Text `json:"pix_txid"`
+	// Id fim a fim da transferência PIX no Banco Central
+	PixE2eID pgtype.Text `json:"pix_e2e_id"`
 }
 
 type PedidoSeqCaixa struct {
@@ -504,6 +754,20 @@ type PedidoStatus struct {
 	Descricao string `json:"descricao"`
 }
 
+// Transições de status do pedido, para auditoria e tempos de preparo/entrega
+type PedidoStatusHistorico struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	IDPedido uuid.UUID `json:"id_pedido"`
+	// Status antes da transição (nulo no registro de criação)
+	StatusAnterior pgtype.Int2 `json:"status_anterior"`
+	StatusNovo     int16       `json:"status_novo"`
+	// Usuário que fez a transição (nulo quando automática)
+	UserID    pgtype.UUID `json:"user_id"`
+	Motivo    pgtype.Text `json:"motivo"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
 type PedidosView struct {
 	ID                 uuid.UUID          `json:"id"`
 	SeqID              int64              `json:"seq_id"`
@@ -576,6 +840,8 @@ type Produto struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 	// Timestamp da exclusão lógica do produto (soft delete).
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	// Estação de produção do produto (sobrepõe a da categoria)
+	IDEstacao pgtype.UUID `json:"id_estacao"`
 }
 
 // Armazena as variações de preço para cada produto, baseadas nas opções de categoria.
@@ -604,12 +870,75 @@ type ProdutoPreco struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type RotaEntregaPedido struct {
+	ID       uuid.UUID `json:"id"`
+	IDRota   uuid.UUID `json:"id_rota"`
+	IDPedido uuid.UUID `json:"id_pedido"`
+	Ordem    int32     `json:"ordem"`
+	// P=Pendente, E=Entregue, N=Não entregue
+	Status string `json:"status"`
+	// Taxa do entregador no momento da atribuição
+	ValorTaxa pgtype.Numeric `json:"valor_taxa"`
+	// Saldo em dinheiro a cobrar do cliente, calculado na saída
+	ValorReceber  pgtype.Numeric     `json:"valor_receber"`
+	ValorRecebido pgtype.Numeric     `json:"valor_recebido"`
+	EntregueEm    pgtype.Timestamptz `json:"entregue_em"`
+	Observacao    pgtype.Text        `json:"observacao"`
+	IDPagamento   pgtype.UUID        `json:"id_pagamento"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	// Recebido além do saldo do pedido no acerto; não vira pagamento
+	ValorExcedente pgtype.Numeric `json:"valor_excedente"`
+}
+
+type RotasEntrega struct {
+	ID           uuid.UUID `json:"id"`
+	SeqID        int64     `json:"seq_id"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	IDEntregador uuid.UUID `json:"id_entregador"`
+	// A=Aberta (montando), S=Em rota, R=Retornou, C=Cancelada
+	Status     string             `json:"status"`
+	SaidaEm    pgtype.Timestamptz `json:"saida_em"`
+	RetornoEm  pgtype.Timestamptz `json:"retorno_em"`
+	Observacao pgtype.Text        `json:"observacao"`
+	CriadoPor  pgtype.UUID        `json:"criado_por"`
+	// Taxas devidas ao entregador, pagas como sangria no acerto
+	ValorTaxas pgtype.Numeric `json:"valor_taxas"`
+	// Dinheiro recebido dos clientes, registrado como pagamento no acerto
+	ValorRecebido pgtype.Numeric `json:"valor_recebido"`
+	// Dinheiro que o entregador entregou no acerto (conferência)
+	ValorEntregue       pgtype.Numeric     `json:"valor_entregue"`
+	IDCaixa             pgtype.UUID        `json:"id_caixa"`
+	IDMovimentacaoTaxas pgtype.UUID        `json:"id_movimentacao_taxas"`
+	AcertadoEm          pgtype.Timestamptz `json:"acertado_em"`
+	AcertadoPor         pgtype.UUID        `json:"acertado_por"`
+	ObservacaoAcerto    pgtype.Text        `json:"observacao_acerto"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+}
+
 type Session struct {
 	Token  string    `json:"token"`
 	Data   []byte    `json:"data"`
 	Expiry time.Time `json:"expiry"`
 }
 
+type TaxasAdquirente struct {
+	ID               uuid.UUID `json:"id"`
+	TenantID         uuid.UUID `json:"tenant_id"`
+	IDFormaPagamento int16     `json:"id_forma_pagamento"`
+	// Parcelas no cartão de crédito; 1 = à vista (e demais formas). Cada parcelamento aceito precisa da sua taxa
+	Parcelas int16 `json:"parcelas"`
+	// MDR: % descontado pela adquirente sobre o valor do pagamento
+	TaxaPercentual pgtype.Numeric `json:"taxa_percentual"`
+	// Tarifa fixa por transação
+	TaxaFixa pgtype.Numeric `json:"taxa_fixa"`
+	// Dias corridos entre o pagamento e o depósito (D+n)
+	DiasRepasse int32     `json:"dias_repasse"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 type Tenant struct {
 	ID                uuid.UUID      `json:"id"`
 	Name              string         `json:"name"`
@@ -624,6 +953,18 @@ type Tenant struct {
 	Cidade            pgtype.Text    `json:"cidade"`
 	SeqID             int64          `json:"seq_id"`
 	TaxaEntregaPadrao pgtype.Numeric `json:"taxa_entrega_padrao"`
+	// Fuso horário IANA usado para avaliar a disponibilidade do cardápio
+	Timezone string `json:"timezone"`
+	// Latitude da loja, origem das zonas por raio
+	Lat pgtype.Numeric `json:"lat"`
+	// Longitude da loja, origem das zonas por raio
+	Lng pgtype.Numeric `json:"lng"`
+	// Chave PIX do recebedor (CPF, CNPJ, e-mail, celular ou aleatória)
+	PixChave pgtype.Text `json:"pix_chave"`
+	// Nome no BR Code, sem acentos, até 25 caracteres
+	PixNomeRecebedor pgtype.Text `json:"pix_nome_recebedor"`
+	// Cidade no BR Code, sem acentos, até 15 caracteres
+	PixCidade pgtype.Text `json:"pix_cidade"`
 }
 
 type User struct {
@@ -642,3 +983,60 @@ type User struct {
 	PermissionAdicional pgtype.Int4 `json:"permission_adicional"`
 	PermissionCliente   pgtype.Int4 `json:"permission_cliente"`
 }
+
+type WebhookDelivery struct {
+	ID             int64       `json:"id"`
+	TenantID       uuid.UUID   `json:"tenant_id"`
+	SubscriptionID uuid.UUID   `json:"subscription_id"`
+	EventID        int64       `json:"event_id"`
+	EventType      string      `json:"event_type"`
+	Payload        []byte      `json:"payload"`
+	Url            string      `json:"url"`
+	StatusCode     pgtype.Int4 `json:"status_code"`
+	ResponseBody   pgtype.Text `json:"response_body"`
+	Error          pgtype.Text `json:"error"`
+	Success        bool        `json:"success"`
+	DurationMs     int32       `json:"duration_ms"`
+	Manual         bool        `json:"manual"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Url      string    `json:"url"`
+	Secret   string    `json:"secret"`
+	// Filtro de event_type (ex.: pedido.created). Vazio = recebe todos
+	EventTypes []string           `json:"event_types"`
+	Descricao  pgtype.Text        `json:"descricao"`
+	Ativo      bool               `json:"ativo"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type ZonasEntrega struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Nome     string    `json:"nome"`
+	// B=Bairros, C=Faixa de CEP, R=Raio da loja, P=Polígono
+	Tipo string `json:"tipo"`
+	// Tipo B: nomes em minúsculas e sem acento
+	Bairros   []string       `json:"bairros"`
+	CepInicio pgtype.Text    `json:"cep_inicio"`
+	CepFim    pgtype.Text    `json:"cep_fim"`
+	RaioKm    pgtype.Numeric `json:"raio_km"`
+	// Tipo P: [[lat, lng], ...] na ordem dos vértices
+	Poligono          []byte         `json:"poligono"`
+	Taxa              pgtype.Numeric `json:"taxa"`
+	ValorMinimoPedido pgtype.Numeric `json:"valor_minimo_pedido"`
+	// Prazo estimado em minutos, sugerido para pedidos.prazo_min
+	PrazoMin pgtype.Int4 `json:"prazo_min"`
+	PrazoMax pgtype.Int4 `json:"prazo_max"`
+	// Endereço em mais de uma zona fica com a de menor ordem
+	Ordem     int32              `json:"ordem"`
+	Ativo     bool               `json:"ativo"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
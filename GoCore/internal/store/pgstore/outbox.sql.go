@@ -13,6 +13,78 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimOutboxEventsForDispatch = `-- name: ClaimOutboxEventsForDispatch :many
+/*
+Reserva o lote do dispatcher: as linhas ficam travadas (SKIP LOCKED)
+só durante este UPDATE, que adia next_attempt_at para o fim da
+reserva. A publicação acontece fora da transação; se o dispatcher
+cair no meio, o evento volta a ser elegível quando a reserva expira.
+A ordem do RETURNING não é garantida: o dispatcher reordena.     */
+WITH lote AS (
+    SELECT id
+    FROM   outbox_event
+    WHERE  processed = false
+      AND  dead_letter = false
+      AND  next_attempt_at <= now()
+    ORDER  BY created_at
+    LIMIT  $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox_event oe
+SET    next_attempt_at = $2
+FROM   lote
+WHERE  oe.id = lote.id
+RETURNING oe.id, oe.tenant_id, oe.user_id, oe.aggregate_type, oe.aggregate_id, oe.event_type, oe.payload,
+          oe.created_at, oe.attempts
+`
+
+type ClaimOutboxEventsForDispatchParams struct {
+	Limit         int32     `json:"limit"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+type ClaimOutboxEventsForDispatchRow struct {
+	ID            int64     `json:"id"`
+	TenantID      uuid.UUID `json:"tenant_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	AggregateType string    `json:"aggregate_type"`
+	AggregateID   string    `json:"aggregate_id"`
+	EventType     string    `json:"event_type"`
+	Payload       []byte    `json:"payload"`
+	CreatedAt     time.Time `json:"created_at"`
+	Attempts      int32     `json:"attempts"`
+}
+
+func (q *Queries) ClaimOutboxEventsForDispatch(ctx context.Context, arg ClaimOutboxEventsForDispatchParams) ([]ClaimOutboxEventsForDispatchRow, error) {
+	rows, err := q.db.Query(ctx, claimOutboxEventsForDispatch, arg.Limit, arg.NextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimOutboxEventsForDispatchRow
+	for rows.Next() {
+		var i ClaimOutboxEventsForDispatchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.UserID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createOutboxEvent = `-- name: CreateOutboxEvent :one
 
 INSERT INTO outbox_event (
@@ -80,16 +152,30 @@ func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventPa
 	return i, err
 }
 
+const deleteDeadLetterOutboxEventsOlderThan = `-- name: DeleteDeadLetterOutboxEventsOlderThan :exec
+/* Limpeza da dead-letter: processed_at guarda quando o evento foi
+   estacionado; nos antigos, a última tentativa agendada.          */
+DELETE FROM outbox_event
+WHERE dead_letter = true
+  AND COALESCE(processed_at, next_attempt_at) < $1::timestamptz
+`
+
+func (q *Queries) DeleteDeadLetterOutboxEventsOlderThan(ctx context.Context, antes time.Time) error {
+	_, err := q.db.Exec(ctx, deleteDeadLetterOutboxEventsOlderThan, antes)
+	return err
+}
+
 const deleteProcessedOutboxEventsOlderThan = `-- name: DeleteProcessedOutboxEventsOlderThan :exec
-/* Limpeza: remova eventos já processados e antigos.
-   Ex.: call passando NOW() - interval '30 days'.        */
+/* Limpeza: remova eventos processados há mais tempo que a retenção.
+   Ex.: call passando NOW() - interval '30 days'.
+   Eventos processados antes de processed_at existir usam created_at. */
 DELETE FROM outbox_event
 WHERE processed = true
-  AND created_at < $1
+  AND COALESCE(processed_at, created_at) < $1::timestamptz
 `
 
-func (q *Queries) DeleteProcessedOutboxEventsOlderThan(ctx context.Context, createdAt time.Time) error {
-	_, err := q.db.Exec(ctx, deleteProcessedOutboxEventsOlderThan, createdAt)
+func (q *Queries) DeleteProcessedOutboxEventsOlderThan(ctx context.Context, antes time.Time) error {
+	_, err := q.db.Exec(ctx, deleteProcessedOutboxEventsOlderThan, antes)
 	return err
 }
 
@@ -189,6 +275,27 @@ func (q *Queries) ListUnprocessedOutboxEventsForProcessing(ctx context.Context,
 	return items, nil
 }
 
+const markOutboxEventDeadLetter = `-- name: MarkOutboxEventDeadLetter :exec
+/* Excedeu o número máximo de tentativas: estaciona o evento.
+   processed_at marca quando, para a limpeza da dead-letter.   */
+UPDATE outbox_event
+SET    attempts     = attempts + 1,
+       last_error   = $2,
+       dead_letter  = true,
+       processed_at = now()
+WHERE  id = $1
+`
+
+type MarkOutboxEventDeadLetterParams struct {
+	ID        int64       `json:"id"`
+	LastError pgtype.Text `json:"last_error"`
+}
+
+func (q *Queries) MarkOutboxEventDeadLetter(ctx context.Context, arg MarkOutboxEventDeadLetterParams) error {
+	_, err := q.db.Exec(ctx, markOutboxEventDeadLetter, arg.ID, arg.LastError)
+	return err
+}
+
 const markOutboxEventError = `-- name: MarkOutboxEventError :exec
 /*
 Grava o motivo da falha e incrementa o número de tentativas.
@@ -212,9 +319,10 @@ func (q *Queries) MarkOutboxEventError(ctx context.Context, arg MarkOutboxEventE
 
 const markOutboxEventProcessed = `-- name: MarkOutboxEventProcessed :exec
 UPDATE outbox_event
-SET    processed = true,
-       attempts  = attempts + 1,
-       last_error = NULL
+SET    processed    = true,
+       processed_at = now(),
+       attempts     = attempts + 1,
+       last_error   = NULL
 WHERE  id = $1
 `
 
@@ -222,3 +330,22 @@ func (q *Queries) MarkOutboxEventProcessed(ctx context.Context, id int64) error
 	_, err := q.db.Exec(ctx, markOutboxEventProcessed, id)
 	return err
 }
+
+const scheduleOutboxEventRetry = `-- name: ScheduleOutboxEventRetry :exec
+UPDATE outbox_event
+SET    attempts        = attempts + 1,
+       last_error      = $2,
+       next_attempt_at = $3
+WHERE  id = $1
+`
+
+type ScheduleOutboxEventRetryParams struct {
+	ID            int64       `json:"id"`
+	LastError     pgtype.Text `json:"last_error"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+}
+
+func (q *Queries) ScheduleOutboxEventRetry(ctx context.Context, arg ScheduleOutboxEventRetryParams) error {
+	_, err := q.db.Exec(ctx, scheduleOutboxEventRetry, arg.ID, arg.LastError, arg.NextAttemptAt)
+	return err
+}
@@ -0,0 +1,118 @@
+-- SQLC Queries para pedidos agendados
+-- ***********************************
+
+-- name: GetAgendamentoConfig :one
+SELECT tenant_id, ativo, intervalo_minutos, capacidade_por_faixa, antecedencia_liberacao_min,
+       antecedencia_minima_min, dias_maximos, updated_at
+FROM   agendamento_config
+WHERE  tenant_id = $1;
+
+-- name: UpsertAgendamentoConfig :one
+INSERT INTO agendamento_config (
+    tenant_id,
+    ativo,
+    intervalo_minutos,
+    capacidade_por_faixa,
+    antecedencia_liberacao_min,
+    antecedencia_minima_min,
+    dias_maximos
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (tenant_id) DO UPDATE
+SET    ativo                      = EXCLUDED.ativo,
+       intervalo_minutos          = EXCLUDED.intervalo_minutos,
+       capacidade_por_faixa       = EXCLUDED.capacidade_por_faixa,
+       antecedencia_liberacao_min = EXCLUDED.antecedencia_liberacao_min,
+       antecedencia_minima_min    = EXCLUDED.antecedencia_minima_min,
+       dias_maximos               = EXCLUDED.dias_maximos
+RETURNING tenant_id, ativo, intervalo_minutos, capacidade_por_faixa, antecedencia_liberacao_min,
+          antecedencia_minima_min, dias_maximos, updated_at;
+
+-- name: ListAgendamentoFaixas :many
+SELECT id, tenant_id, dia_semana, hora_inicio, hora_fim, capacidade
+FROM   agendamento_faixas
+WHERE  tenant_id = $1
+ORDER  BY dia_semana, hora_inicio;
+
+-- name: DeleteAgendamentoFaixas :exec
+DELETE FROM agendamento_faixas
+WHERE  tenant_id = $1;
+
+-- name: CreateAgendamentoFaixa :exec
+INSERT INTO agendamento_faixas (
+    tenant_id, dia_semana, hora_inicio, hora_fim, capacidade
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetFaixaAgendamento :one
+/* Faixa do horário e quantos pedidos já ocupam; id_pedido fica de fora no reagendamento. */
+SELECT f.inicio::timestamptz AS inicio,
+       f.fim::timestamptz    AS fim,
+       f.capacidade,
+       (SELECT count(*)
+          FROM pedidos p
+         WHERE p.tenant_id      = sqlc.arg(tenant_id)
+           AND p.agendado_para >= f.inicio
+           AND p.agendado_para <  f.fim
+           AND p.id_status     <> 6
+           AND p.deleted_at IS NULL
+           AND (sqlc.narg(id_pedido)::uuid IS NULL OR p.id <> sqlc.narg(id_pedido)::uuid))::int AS ocupados
+FROM   faixa_agendamento(sqlc.arg(tenant_id), sqlc.arg(horario)::timestamptz) f;
+
+-- name: ListHorariosAgendamento :many
+/* Faixas entre inicio e fim (um dia, no fuso do tenant) com a ocupação de cada uma. */
+SELECT f.inicio::timestamptz AS inicio,
+       f.fim::timestamptz    AS fim,
+       f.capacidade,
+       (SELECT count(*)
+          FROM pedidos p
+         WHERE p.tenant_id      = sqlc.arg(tenant_id)
+           AND p.agendado_para >= f.inicio
+           AND p.agendado_para <  f.fim
+           AND p.id_status     <> 6
+           AND p.deleted_at IS NULL)::int AS ocupados
+FROM   generate_series(sqlc.arg(inicio)::timestamptz,
+                       sqlc.arg(fim)::timestamptz - interval '1 minute',
+                       make_interval(mins => sqlc.arg(intervalo_minutos)::int)) AS g(horario)
+CROSS  JOIN LATERAL faixa_agendamento(sqlc.arg(tenant_id), g.horario) f
+ORDER  BY 1;
+
+-- name: ListPedidosAgendados :many
+SELECT p.id,
+       p.codigo_pedido,
+       p.id_status,
+       p.tipo_entrega,
+       p.agendado_para,
+       p.liberado_em,
+       (p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                      - COALESCE(p.desconto, 0))::numeric(10,2) AS valor_pedido,
+       c.nome_razao_social AS cliente_nome
+FROM   pedidos p
+JOIN   clientes c ON c.id = p.id_cliente
+WHERE  p.tenant_id      = sqlc.arg(tenant_id)
+  AND  p.agendado_para >= sqlc.arg(inicio)::timestamptz
+  AND  p.agendado_para <  sqlc.arg(fim)::timestamptz
+  AND  p.id_status     <> 6
+  AND  p.deleted_at IS NULL
+ORDER  BY p.agendado_para, p.seq_id;
+
+-- name: ListPedidosAgendadosParaLiberar :many
+/* Agendados com a liberação vencida, só de tenants com caixa aberto. SKIP LOCKED divide o lote entre instâncias.
+   Sem agendado_por não há em nome de quem liberar: fica para a liberação manual e não ocupa o lote. */
+SELECT p.id,
+       p.tenant_id,
+       p.codigo_pedido,
+       p.agendado_para,
+       p.agendado_por
+FROM   pedidos p
+LEFT   JOIN agendamento_config c ON c.tenant_id = p.tenant_id
+WHERE  p.id_status = 7
+  AND  p.deleted_at IS NULL
+  AND  p.agendado_por IS NOT NULL
+  AND  p.agendado_para - make_interval(mins => COALESCE(c.antecedencia_liberacao_min, 40)) <= now()
+  AND  get_caixa_ativo(p.tenant_id) IS NOT NULL
+ORDER  BY p.agendado_para
+LIMIT  sqlc.arg(limite)
+FOR UPDATE OF p SKIP LOCKED;
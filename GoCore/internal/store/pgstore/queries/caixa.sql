@@ -39,9 +39,12 @@ INSERT INTO caixa_fechamento_formas (id_caixa, id_forma_pagamento, valor_informa
 VALUES
   ($1, $2, $3);
 
--- name: FecharCaixa :exec
+-- name: FecharCaixa :one
 UPDATE caixas
 SET status = 'F',
     data_fechamento = now(),
     observacao_fechamento = $2
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1
+  AND tenant_id = $3
+  AND status = 'A'
+RETURNING *;
\ No newline at end of file
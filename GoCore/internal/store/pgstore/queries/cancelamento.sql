@@ -0,0 +1,71 @@
+-- SQLC Queries para o cancelamento de pedidos
+-- ******************************************
+
+-- name: GetPedidoCancelamentoForUpdate :one
+SELECT id, codigo_pedido, id_status, valor_pago
+FROM   pedidos
+WHERE  id = sqlc.arg(id)
+  AND  tenant_id = sqlc.arg(tenant_id)
+  AND  deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListPagamentosCancelamento :many
+SELECT pp.id,
+       pp.id_conta_receber,
+       pp.categoria_pagamento,
+       pp.forma_pagamento,
+       pp.valor_pago,
+       pp.troco,
+       cm.id                             AS id_movimentacao,
+       cm.id_caixa,
+       cm.id_forma_pagamento,
+       cm.valor                          AS valor_movimentacao,
+       COALESCE(c.status = 'A', false)::boolean AS caixa_aberto
+FROM   pedido_pagamentos pp
+LEFT   JOIN caixa_movimentacoes cm ON cm.id_pagamento = pp.id
+                                  AND cm.tipo = 'P'
+                                  AND cm.deleted_at IS NULL
+LEFT   JOIN caixas c               ON c.id = cm.id_caixa
+WHERE  pp.id_pedido = sqlc.arg(id_pedido)
+  AND  pp.deleted_at IS NULL
+ORDER  BY pp.created_at, pp.id
+FOR UPDATE OF pp;
+
+-- name: SoftDeletePagamento :exec
+UPDATE pedido_pagamentos
+SET    deleted_at = now()
+WHERE  id = sqlc.arg(id)
+  AND  deleted_at IS NULL;
+
+-- name: GetCaixaAtivo :one
+SELECT id
+FROM   caixas
+WHERE  tenant_id = sqlc.arg(tenant_id)
+  AND  status = 'A'
+  AND  deleted_at IS NULL
+LIMIT  1;
+
+-- name: InsertMovimentacaoEstorno :one
+INSERT INTO caixa_movimentacoes
+(id_caixa, tipo, id_forma_pagamento, valor, observacao, id_pagamento, autorizado_por)
+VALUES
+($1, 'E', $2, $3, $4, $5, $6)
+RETURNING id, seq_id, id_caixa, tipo, id_forma_pagamento, valor, observacao, id_pagamento, autorizado_por, created_at, updated_at, deleted_at;
+
+-- name: CancelarContasReceberPedido :many
+UPDATE contas_receber
+SET    cancelado_em        = now(),
+       motivo_cancelamento = sqlc.arg(motivo)
+WHERE  id_pedido = sqlc.arg(id_pedido)
+  AND  cancelado_em IS NULL
+RETURNING id, parcela, vencimento, valor_devido, valor_pago;
+
+-- name: CreatePedidoCancelamento :one
+INSERT INTO pedido_cancelamentos (
+    tenant_id, id_pedido, status_anterior, motivo, user_id, autorizado_por,
+    valor_estornado, pagamentos, parcelas
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, tenant_id, id_pedido, status_anterior, motivo, user_id, autorizado_por,
+          valor_estornado, pagamentos, parcelas, created_at;
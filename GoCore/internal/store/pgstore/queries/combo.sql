@@ -0,0 +1,95 @@
+-- SQLC Queries para combos / kits
+-- *******************************
+
+-- name: GetProdutoCombo :one
+/* Produto do tenant que será (ou já é) combo. */
+SELECT p.id,
+       p.nome,
+       p.id_categoria
+FROM   produtos p
+JOIN   categorias c ON c.id = p.id_categoria
+WHERE  p.id = sqlc.arg(id)
+  AND  c.id_tenant = sqlc.arg(tenant_id)
+  AND  p.deleted_at IS NULL;
+
+-- name: ListComboSlots :many
+SELECT cs.id,
+       cs.id_produto,
+       cs.nome,
+       cs.id_categoria,
+       c.nome   AS categoria_nome,
+       cs.id_categoria_opcao,
+       co.nome  AS opcao_nome,
+       cs.quantidade,
+       cs.ordem
+FROM   combo_slots cs
+JOIN   categorias c             ON c.id  = cs.id_categoria
+LEFT   JOIN categoria_opcoes co ON co.id = cs.id_categoria_opcao
+WHERE  cs.id_produto = $1
+  AND  cs.deleted_at IS NULL
+ORDER  BY cs.ordem, cs.nome;
+
+-- name: ListComboAcrescimos :many
+SELECT a.id_combo_slot,
+       a.id_produto,
+       p.nome AS produto_nome,
+       a.valor
+FROM   combo_slot_acrescimos a
+JOIN   combo_slots cs ON cs.id = a.id_combo_slot
+JOIN   produtos p     ON p.id  = a.id_produto
+WHERE  cs.id_produto = $1
+  AND  cs.deleted_at IS NULL
+ORDER  BY p.nome;
+
+-- name: DeleteComboSlots :execrows
+/*
+Soft delete: pedido_itens.id_combo_slot continua apontando para os slots
+antigos dos pedidos já feitos. */
+UPDATE combo_slots
+SET    deleted_at = now()
+WHERE  id_produto = $1
+  AND  deleted_at IS NULL;
+
+-- name: CreateComboSlot :one
+INSERT INTO combo_slots (
+    id_produto,
+    nome,
+    id_categoria,
+    id_categoria_opcao,
+    quantidade,
+    ordem
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id;
+
+-- name: CreateComboSlotAcrescimo :exec
+INSERT INTO combo_slot_acrescimos (
+    id_combo_slot,
+    id_produto,
+    valor
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: ListCategoriasOpcoesCombo :many
+/*
+Categorias do tenant com suas opções, para validar os slots.
+Categoria sem opção volta uma linha com id_categoria_opcao nulo. */
+SELECT c.id               AS id_categoria,
+       co.id              AS id_categoria_opcao
+FROM   categorias c
+LEFT   JOIN categoria_opcoes co ON co.id_categoria = c.id
+                               AND co.deleted_at IS NULL
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  c.id = ANY(sqlc.arg(categoria_ids)::uuid[])
+  AND  c.deleted_at IS NULL;
+
+-- name: ListProdutosCategoriaCombo :many
+SELECT p.id,
+       p.id_categoria
+FROM   produtos p
+JOIN   categorias c ON c.id = p.id_categoria
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  p.id = ANY(sqlc.arg(produto_ids)::uuid[])
+  AND  p.deleted_at IS NULL;
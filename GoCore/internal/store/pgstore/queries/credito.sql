@@ -0,0 +1,169 @@
+-- SQLC Queries para crédito de clientes (fiado)
+-- *********************************************
+
+-- name: GetCreditoConfig :one
+SELECT tenant_id, limite_padrao, bloquear_atraso, dias_tolerancia_atraso, updated_at,
+       multa_percentual, juros_dia_percentual, dias_carencia_encargos
+FROM   credito_config
+WHERE  tenant_id = $1;
+
+-- name: UpsertCreditoConfig :one
+INSERT INTO credito_config (
+    tenant_id,
+    limite_padrao,
+    bloquear_atraso,
+    dias_tolerancia_atraso,
+    multa_percentual,
+    juros_dia_percentual,
+    dias_carencia_encargos
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (tenant_id) DO UPDATE
+SET    limite_padrao          = EXCLUDED.limite_padrao,
+       bloquear_atraso        = EXCLUDED.bloquear_atraso,
+       dias_tolerancia_atraso = EXCLUDED.dias_tolerancia_atraso,
+       multa_percentual       = EXCLUDED.multa_percentual,
+       juros_dia_percentual   = EXCLUDED.juros_dia_percentual,
+       dias_carencia_encargos = EXCLUDED.dias_carencia_encargos
+RETURNING tenant_id, limite_padrao, bloquear_atraso, dias_tolerancia_atraso, updated_at,
+          multa_percentual, juros_dia_percentual, dias_carencia_encargos;
+
+-- name: UpsertClienteCredito :one
+INSERT INTO clientes_credito (
+    id_cliente,
+    tenant_id,
+    limite,
+    bloqueado,
+    observacao,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (id_cliente) DO UPDATE
+SET    limite     = EXCLUDED.limite,
+       bloqueado  = EXCLUDED.bloqueado,
+       observacao = EXCLUDED.observacao,
+       updated_by = EXCLUDED.updated_by
+RETURNING id_cliente, tenant_id, limite, bloqueado, observacao, updated_by, updated_at;
+
+-- name: GetSituacaoCreditoCliente :one
+/* limite_cliente é o limite próprio; limite já resolve o padrão do tenant. */
+SELECT c.id,
+       c.nome_razao_social,
+       cc.limite                    AS limite_cliente,
+       cc.observacao,
+       s.limite::numeric(10,2)      AS limite,
+       s.bloqueado::boolean         AS bloqueado,
+       s.bloquear_atraso::boolean   AS bloquear_atraso,
+       s.dias_tolerancia::integer   AS dias_tolerancia,
+       s.saldo_aberto::numeric(10,2)  AS saldo_aberto,
+       s.saldo_vencido::numeric(10,2) AS saldo_vencido,
+       s.maior_atraso_dias::integer AS maior_atraso_dias
+FROM   clientes c
+LEFT   JOIN clientes_credito cc ON cc.id_cliente = c.id
+CROSS  JOIN LATERAL situacao_credito_cliente(c.id, sqlc.arg(data)::date) s
+WHERE  c.id = $1
+  AND  c.tenant_id = $2
+  AND  c.deleted_at IS NULL;
+
+-- name: ListParcelasAbertasCliente :many
+SELECT cr.id,
+       cr.id_pedido,
+       p.codigo_pedido,
+       cr.parcela,
+       cr.vencimento,
+       cr.valor_devido,
+       COALESCE(cr.valor_pago, 0)::numeric(10,2) AS valor_pago,
+       cr.autorizado_por,
+       cr.created_at
+FROM   contas_receber cr
+JOIN   pedidos p ON p.id = cr.id_pedido
+WHERE  p.id_cliente = $1
+  AND  p.tenant_id = $2
+  AND  p.id_status <> 6
+  AND  p.deleted_at IS NULL
+  AND  cr.cancelado_em IS NULL
+  AND  COALESCE(cr.valor_pago, 0) < cr.valor_devido
+ORDER  BY cr.vencimento, p.codigo_pedido, cr.parcela;
+
+-- name: ListPagamentosParcelasCliente :many
+/* Pagamentos já abatidos das parcelas em aberto do cliente. */
+SELECT pp.id,
+       pp.id_conta_receber,
+       pp.forma_pagamento,
+       (pp.valor_pago - pp.troco)::numeric(10,2) AS valor,
+       pp.valor_encargos,
+       pp.created_at
+FROM   pedido_pagamentos pp
+JOIN   contas_receber cr ON cr.id = pp.id_conta_receber
+JOIN   pedidos p ON p.id = cr.id_pedido
+WHERE  p.id_cliente = $1
+  AND  p.tenant_id = $2
+  AND  p.id_status <> 6
+  AND  p.deleted_at IS NULL
+  AND  pp.deleted_at IS NULL
+  AND  cr.cancelado_em IS NULL
+  AND  COALESCE(cr.valor_pago, 0) < cr.valor_devido
+ORDER  BY pp.created_at;
+
+-- name: GetSaldoContaReceber :one
+/* Principal e encargos (multa e juros) da parcela na data; ver encargos_conta_receber. */
+SELECT cr.id,
+       cr.id_pedido,
+       p.codigo_pedido,
+       p.id_cliente,
+       cr.parcela,
+       cr.vencimento,
+       cr.valor_devido,
+       cr.cancelado_em,
+       e.dias_atraso::integer               AS dias_atraso,
+       e.em_carencia::boolean               AS em_carencia,
+       e.principal_aberto::numeric(10,2)    AS principal_aberto,
+       e.multa::numeric(10,2)               AS multa,
+       e.juros::numeric(10,2)               AS juros,
+       e.encargos_pagos::numeric(10,2)      AS encargos_pagos
+FROM   contas_receber cr
+JOIN   pedidos p ON p.id = cr.id_pedido
+CROSS  JOIN LATERAL encargos_conta_receber(cr.id, sqlc.arg(data)::date) e
+WHERE  cr.id = $1
+  AND  p.tenant_id = $2
+  AND  p.deleted_at IS NULL;
+
+-- name: ListClientesDosPedidos :many
+SELECT p.id,
+       p.id_cliente
+FROM   pedidos p
+WHERE  p.tenant_id = $1
+  AND  p.id = ANY(sqlc.arg(ids)::uuid[])
+  AND  p.deleted_at IS NULL;
+
+-- name: ListAgingContasReceber :many
+/* Saldo em aberto por cliente e faixa de dias após o vencimento na data. A parcela
+   que vence na data ainda está a vencer; o atraso conta a partir do dia seguinte. */
+WITH abertas AS (
+    SELECT p.id_cliente,
+           cr.valor_devido - COALESCE(cr.valor_pago, 0) AS saldo,
+           sqlc.arg(data)::date - cr.vencimento         AS dias
+    FROM   contas_receber cr
+    JOIN   pedidos p ON p.id = cr.id_pedido
+    WHERE  p.tenant_id = sqlc.arg(tenant_id)
+      AND  p.id_status <> 6
+      AND  p.deleted_at IS NULL
+      AND  cr.cancelado_em IS NULL
+      AND  COALESCE(cr.valor_pago, 0) < cr.valor_devido
+)
+SELECT a.id_cliente,
+       c.nome_razao_social,
+       count(*)::integer                                                                    AS parcelas,
+       COALESCE(SUM(a.saldo) FILTER (WHERE a.dias <= 0), 0)::numeric(10,2)                  AS a_vencer,
+       COALESCE(SUM(a.saldo) FILTER (WHERE a.dias BETWEEN 1 AND 30), 0)::numeric(10,2)      AS dias_1_30,
+       COALESCE(SUM(a.saldo) FILTER (WHERE a.dias BETWEEN 31 AND 60), 0)::numeric(10,2)     AS dias_31_60,
+       COALESCE(SUM(a.saldo) FILTER (WHERE a.dias BETWEEN 61 AND 90), 0)::numeric(10,2)     AS dias_61_90,
+       COALESCE(SUM(a.saldo) FILTER (WHERE a.dias > 90), 0)::numeric(10,2)                  AS dias_90_mais,
+       SUM(a.saldo)::numeric(10,2)                                                          AS total,
+       GREATEST(MAX(a.dias), 0)::integer                                                    AS maior_atraso_dias
+FROM   abertas a
+JOIN   clientes c ON c.id = a.id_cliente
+GROUP  BY a.id_cliente, c.nome_razao_social
+ORDER  BY total DESC, c.nome_razao_social;
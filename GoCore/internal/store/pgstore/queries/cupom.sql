@@ -0,0 +1,172 @@
+-- SQLC Queries para cupons de desconto
+-- ***********************************
+
+-- name: CreateCupom :one
+INSERT INTO cupons (
+    tenant_id,
+    codigo,
+    descricao,
+    tipo,
+    valor,
+    desconto_maximo,
+    valor_minimo_pedido,
+    inicio,
+    fim,
+    limite_total,
+    limite_por_cliente,
+    ativo
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING id, tenant_id, codigo, descricao, tipo, valor, desconto_maximo, valor_minimo_pedido,
+          inicio, fim, limite_total, limite_por_cliente, usos, ativo, created_at, updated_at, deleted_at;
+
+-- name: GetCupom :one
+SELECT id, tenant_id, codigo, descricao, tipo, valor, desconto_maximo, valor_minimo_pedido,
+       inicio, fim, limite_total, limite_por_cliente, usos, ativo, created_at, updated_at, deleted_at
+FROM   cupons
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: ListCupons :many
+SELECT id, tenant_id, codigo, descricao, tipo, valor, desconto_maximo, valor_minimo_pedido,
+       inicio, fim, limite_total, limite_por_cliente, usos, ativo, created_at, updated_at, deleted_at
+FROM   cupons
+WHERE  tenant_id = $1
+  AND  deleted_at IS NULL
+ORDER  BY ativo DESC, inicio DESC, codigo;
+
+-- name: UpdateCupom :one
+/* usos não é alterado aqui: é mantido pelos resgates. */
+UPDATE cupons
+SET    codigo              = $3,
+       descricao           = $4,
+       tipo                = $5,
+       valor               = $6,
+       desconto_maximo     = $7,
+       valor_minimo_pedido = $8,
+       inicio              = $9,
+       fim                 = $10,
+       limite_total        = $11,
+       limite_por_cliente  = $12,
+       ativo               = $13
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, codigo, descricao, tipo, valor, desconto_maximo, valor_minimo_pedido,
+          inicio, fim, limite_total, limite_por_cliente, usos, ativo, created_at, updated_at, deleted_at;
+
+-- name: DeleteCupom :execrows
+/* Soft delete: os resgates continuam apontando para o cupom. */
+UPDATE cupons
+SET    deleted_at = now()
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: DeleteCupomCategorias :exec
+DELETE FROM cupom_categorias
+WHERE  id_cupom = $1;
+
+-- name: DeleteCupomProdutos :exec
+DELETE FROM cupom_produtos
+WHERE  id_cupom = $1;
+
+-- name: InsertCupomCategorias :exec
+INSERT INTO cupom_categorias (id_cupom, id_categoria)
+SELECT sqlc.arg(id_cupom), unnest(sqlc.arg(categoria_ids)::uuid[]);
+
+-- name: InsertCupomProdutos :exec
+INSERT INTO cupom_produtos (id_cupom, id_produto)
+SELECT sqlc.arg(id_cupom), unnest(sqlc.arg(produto_ids)::uuid[]);
+
+-- name: ListCupomRestricoes :many
+/*
+Categorias (tipo C) e produtos (tipo P) a que os cupons se restringem.
+Cupom sem linhas vale para o pedido todo. */
+SELECT cc.id_cupom,
+       'C'::text    AS tipo,
+       cc.id_categoria AS id,
+       c.nome
+FROM   cupom_categorias cc
+JOIN   categorias c ON c.id = cc.id_categoria
+WHERE  cc.id_cupom = ANY(sqlc.arg(cupom_ids)::uuid[])
+UNION ALL
+SELECT cp.id_cupom,
+       'P'::text    AS tipo,
+       cp.id_produto AS id,
+       p.nome
+FROM   cupom_produtos cp
+JOIN   produtos p ON p.id = cp.id_produto
+WHERE  cp.id_cupom = ANY(sqlc.arg(cupom_ids)::uuid[])
+ORDER  BY 1, 2, 4;
+
+-- name: CountCategoriasTenant :one
+SELECT COUNT(*)
+FROM   categorias
+WHERE  id_tenant = sqlc.arg(tenant_id)
+  AND  id = ANY(sqlc.arg(categoria_ids)::uuid[])
+  AND  deleted_at IS NULL;
+
+-- name: CountProdutosTenant :one
+SELECT COUNT(*)
+FROM   produtos p
+JOIN   categorias c ON c.id = p.id_categoria
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  p.id = ANY(sqlc.arg(produto_ids)::uuid[])
+  AND  p.deleted_at IS NULL;
+
+-- name: ListCupomResgates :many
+SELECT r.id,
+       r.id_pedido,
+       p.codigo_pedido,
+       r.id_cliente,
+       cl.nome AS cliente_nome,
+       r.valor_desconto,
+       r.created_at,
+       r.cancelado_em
+FROM   cupom_resgates r
+JOIN   cupons c    ON c.id  = r.id_cupom
+JOIN   pedidos p   ON p.id  = r.id_pedido
+JOIN   clientes cl ON cl.id = r.id_cliente
+WHERE  r.id_cupom = $1
+  AND  c.tenant_id = $2
+ORDER  BY r.created_at DESC
+LIMIT  $3 OFFSET $4;
+
+-- name: GetCupomCotacao :one
+/*
+Cupom pelo código para a cotação. Na edição de um pedido o resgate do
+próprio pedido não conta no limite global (usos). */
+SELECT c.id,
+       c.codigo,
+       c.descricao,
+       c.tipo,
+       c.valor,
+       c.desconto_maximo,
+       c.valor_minimo_pedido,
+       c.limite_total,
+       c.limite_por_cliente,
+       c.usos - (
+           SELECT COUNT(*)
+           FROM   cupom_resgates r
+           WHERE  r.id_cupom = c.id
+             AND  r.id_pedido = sqlc.narg(id_pedido)
+             AND  r.cancelado_em IS NULL
+       )::int AS usos,
+       c.ativo,
+       (c.inicio <= now() AND (c.fim IS NULL OR c.fim > now())) AS vigente
+FROM   cupons c
+WHERE  c.tenant_id = sqlc.arg(tenant_id)
+  AND  upper(c.codigo) = upper(sqlc.arg(codigo))
+  AND  c.deleted_at IS NULL;
+
+-- name: CountResgatesCliente :one
+/* Resgates ativos do cliente, sem contar o do pedido em edição. */
+SELECT COUNT(*)
+FROM   cupom_resgates
+WHERE  id_cupom = sqlc.arg(id_cupom)
+  AND  id_cliente = sqlc.arg(id_cliente)
+  AND  cancelado_em IS NULL
+  AND  id_pedido IS DISTINCT FROM sqlc.narg(id_pedido);
@@ -6,8 +6,20 @@ SELECT
     SUM(COALESCE(acrescimo, 0))::numeric(12,2) as total_acrescimo,
     SUM(COALESCE(taxa_entrega, 0))::numeric(12,2) as total_taxa_entrega,
     SUM(COALESCE(valor_total, 0))::numeric(12,2) AS total_valor_total,
-    SUM(COALESCE(valor_pago, 0))::numeric(12,2)                                               AS total_pago
+    SUM(COALESCE(valor_pago, 0))::numeric(12,2)                                               AS total_pago,
+    SUM(COALESCE(t.taxa_adquirente, 0))::numeric(12,2)                                        AS total_taxa_adquirente,
+    SUM(COALESCE(t.valor_liquido, 0))::numeric(12,2)                                          AS total_liquido
 FROM  public.pedidos
+LEFT  JOIN LATERAL (
+    /* taxa da adquirente (MDR) e o que os pagamentos do pedido depositam,
+       encargos incluídos; pagamento antigo, sem taxa, repassa o valor cheio */
+    SELECT SUM(pp.taxa_adquirente) AS taxa_adquirente,
+           SUM(COALESCE(pp.valor_repasse,
+                        pp.valor_pago - COALESCE(pp.troco, 0))) AS valor_liquido
+    FROM   public.pedido_pagamentos pp
+    WHERE  pp.id_pedido = pedidos.id
+      AND  pp.deleted_at IS NULL
+) t ON true
 WHERE deleted_at IS NULL
   AND (data_pedido AT TIME ZONE 'America/Sao_Paulo')::date
         >= CURRENT_DATE - INTERVAL '89 days'
@@ -36,12 +48,17 @@ ORDER BY p.data_pedido DESC;
 /*
   Pagamentos líquidos (valor_pago – troco) dos últimos 3 meses,
   agrupados por DIA e CATEGORIA de pagamento.
+  • taxa_adquirente / valor_repasse: MDR descontado e o que a adquirente
+    deposita (pagamento antigo, sem taxa calculada, repassa o valor cheio).
   • O tenant é passado no parâmetro $1.
 */
 SELECT
     (pp.created_at AT TIME ZONE 'America/Sao_Paulo')::date          AS dia,
     pp.categoria_pagamento,
-    SUM(pp.valor_pago - COALESCE(pp.troco, 0))::numeric(12,2)       AS valor_liquido
+    SUM(pp.valor_pago - COALESCE(pp.troco, 0))::numeric(12,2)       AS valor_liquido,
+    SUM(COALESCE(pp.taxa_adquirente, 0))::numeric(12,2)             AS taxa_adquirente,
+    SUM(COALESCE(pp.valor_repasse,
+                 pp.valor_pago - COALESCE(pp.troco, 0)))::numeric(12,2) AS valor_repasse
 FROM   public.pedido_pagamentos pp
 JOIN   public.pedidos          p  ON p.id = pp.id_pedido            -- garante o tenant
 WHERE  pp.deleted_at IS NULL
@@ -0,0 +1,51 @@
+-- SQLC Queries para a disponibilidade do cardápio
+-- ***********************************************
+
+-- name: GetTenantTimezone :one
+SELECT timezone
+FROM   tenants
+WHERE  id = $1;
+
+-- name: ListCategoriasDisponibilidade :many
+/*
+Janela (inicio/fim) e dias da semana de todas as categorias do tenant.
+Categorias inativas voltam na lista para que o motivo seja informado. */
+SELECT id,
+       nome,
+       ordem,
+       ativo,
+       inicio::time AS inicio,
+       fim::time    AS fim,
+       disponivel_domingo,
+       disponivel_segunda,
+       disponivel_terca,
+       disponivel_quarta,
+       disponivel_quinta,
+       disponivel_sexta,
+       disponivel_sabado
+FROM   categorias
+WHERE  id_tenant = sqlc.arg(tenant_id)
+  AND  deleted_at IS NULL
+ORDER  BY ordem NULLS LAST, nome;
+
+-- name: ListProdutosCardapio :many
+SELECT p.id            AS id_produto,
+       p.nome          AS produto_nome,
+       p.status        AS produto_status,
+       p.id_categoria,
+       pp.id_categoria_opcao,
+       co.nome         AS opcao_nome,
+       co.status       AS opcao_status,
+       pp.preco_base,
+       pp.preco_promocional,
+       pp.disponivel
+FROM   produtos p
+JOIN   categorias c       ON c.id = p.id_categoria
+JOIN   produto_precos pp  ON pp.id_produto = p.id
+                         AND pp.deleted_at IS NULL
+JOIN   categoria_opcoes co ON co.id = pp.id_categoria_opcao
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  c.deleted_at IS NULL
+  AND  p.deleted_at IS NULL
+  AND  co.deleted_at IS NULL
+ORDER  BY p.id_categoria, p.ordem NULLS LAST, p.nome, co.nome;
@@ -0,0 +1,93 @@
+-- SQLC Queries para entregadores
+-- ******************************
+
+-- name: CreateEntregador :one
+INSERT INTO entregadores (
+    tenant_id,
+    id_usuario,
+    nome,
+    telefone,
+    veiculo,
+    placa,
+    taxa_por_entrega,
+    ativo
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, tenant_id, id_usuario, nome, telefone, veiculo, placa, taxa_por_entrega, ativo,
+          created_at, updated_at, deleted_at;
+
+-- name: GetEntregador :one
+SELECT id, tenant_id, id_usuario, nome, telefone, veiculo, placa, taxa_por_entrega, ativo,
+       created_at, updated_at, deleted_at
+FROM   entregadores
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: GetEntregadorPorUsuario :one
+SELECT id, tenant_id, id_usuario, nome, telefone, veiculo, placa, taxa_por_entrega, ativo,
+       created_at, updated_at, deleted_at
+FROM   entregadores
+WHERE  id_usuario = $1
+  AND  deleted_at IS NULL;
+
+-- name: ListEntregadores :many
+SELECT id, tenant_id, id_usuario, nome, telefone, veiculo, placa, taxa_por_entrega, ativo,
+       created_at, updated_at, deleted_at
+FROM   entregadores
+WHERE  tenant_id = $1
+  AND  deleted_at IS NULL
+ORDER  BY nome;
+
+-- name: UpdateEntregador :one
+UPDATE entregadores
+SET    id_usuario       = $3,
+       nome             = $4,
+       telefone         = $5,
+       veiculo          = $6,
+       placa            = $7,
+       taxa_por_entrega = $8,
+       ativo            = $9
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, id_usuario, nome, telefone, veiculo, placa, taxa_por_entrega, ativo,
+          created_at, updated_at, deleted_at;
+
+-- name: DeleteEntregador :execrows
+UPDATE entregadores
+SET    deleted_at = now(),
+       id_usuario = NULL
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: CountUsuarioTenant :one
+SELECT COUNT(*)
+FROM   users
+WHERE  id = $1
+  AND  tenant_id = $2;
+
+-- name: ListRelatorioEntregadores :many
+/* Entregas por entregador e dia da saída, no fuso do tenant. */
+SELECT e.id                                                     AS id_entregador,
+       e.nome,
+       (r.saida_em AT TIME ZONE t.timezone)::date               AS dia,
+       COUNT(DISTINCT r.id)                                     AS rotas,
+       COUNT(*) FILTER (WHERE rp.status = 'E')                  AS entregues,
+       COUNT(*) FILTER (WHERE rp.status = 'N')                  AS nao_entregues,
+       COALESCE(SUM(rp.valor_taxa) FILTER (WHERE rp.status = 'E'), 0)::numeric(10,2) AS valor_taxas,
+       COALESCE(SUM(rp.valor_recebido), 0)::numeric(10,2)       AS valor_recebido,
+       COALESCE(AVG(EXTRACT(EPOCH FROM rp.entregue_em - r.saida_em) / 60)
+                FILTER (WHERE rp.status = 'E'), 0)::float8      AS minutos_medio
+FROM   rotas_entrega r
+JOIN   tenants t               ON t.id = r.tenant_id
+JOIN   entregadores e          ON e.id = r.id_entregador
+JOIN   rota_entrega_pedidos rp ON rp.id_rota = r.id
+WHERE  r.tenant_id = sqlc.arg(tenant_id)
+  AND  r.status IN ('S', 'R')
+  AND  (r.saida_em AT TIME ZONE t.timezone)::date BETWEEN sqlc.arg(inicio)::date AND sqlc.arg(fim)::date
+  AND  (sqlc.narg(id_entregador)::uuid IS NULL OR r.id_entregador = sqlc.narg(id_entregador)::uuid)
+GROUP  BY e.id, e.nome, dia
+ORDER  BY dia, e.nome;
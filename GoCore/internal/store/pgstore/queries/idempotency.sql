@@ -0,0 +1,45 @@
+-- SQLC Queries para as chaves de idempotência
+-- *******************************************
+
+-- name: ReservarIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    tenant_id, chave, metodo, rota, hash_requisicao
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (tenant_id, chave) DO UPDATE
+SET    metodo          = EXCLUDED.metodo,
+       rota            = EXCLUDED.rota,
+       hash_requisicao = EXCLUDED.hash_requisicao,
+       status_code     = NULL,
+       content_type    = NULL,
+       resposta        = NULL,
+       created_at      = now(),
+       concluido_em    = NULL
+WHERE  idempotency_keys.created_at < now() - make_interval(secs => sqlc.arg(validade_segundos)::double precision)
+RETURNING created_at;
+
+-- name: GetIdempotencyKey :one
+SELECT tenant_id, chave, metodo, rota, hash_requisicao, status_code,
+       content_type, resposta, created_at, concluido_em
+FROM   idempotency_keys
+WHERE  tenant_id = $1
+  AND  chave = $2;
+
+-- name: ConcluirIdempotencyKey :exec
+UPDATE idempotency_keys
+SET    status_code  = $3,
+       content_type = $4,
+       resposta     = $5,
+       concluido_em = now()
+WHERE  tenant_id = $1
+  AND  chave = $2;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE  tenant_id = $1
+  AND  chave = $2;
+
+-- name: DeleteIdempotencyKeysExpiradas :execrows
+DELETE FROM idempotency_keys
+WHERE  created_at < now() - make_interval(secs => sqlc.arg(validade_segundos)::double precision);
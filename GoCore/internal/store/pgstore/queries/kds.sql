@@ -0,0 +1,179 @@
+-- SQLC Queries para o KDS (estações de produção)
+-- **********************************************
+
+-- name: CreateEstacaoProducao :one
+INSERT INTO estacoes_producao (
+    tenant_id,
+    nome,
+    ordem,
+    ativo
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, tenant_id, nome, ordem, ativo, created_at, updated_at, deleted_at;
+
+-- name: GetEstacaoProducao :one
+SELECT id, tenant_id, nome, ordem, ativo, created_at, updated_at, deleted_at
+FROM   estacoes_producao
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: ListEstacoesProducao :many
+SELECT id, tenant_id, nome, ordem, ativo, created_at, updated_at, deleted_at
+FROM   estacoes_producao
+WHERE  tenant_id = $1
+  AND  deleted_at IS NULL
+ORDER  BY ordem, nome;
+
+-- name: UpdateEstacaoProducao :one
+UPDATE estacoes_producao
+SET    nome  = $3,
+       ordem = $4,
+       ativo = $5
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, nome, ordem, ativo, created_at, updated_at, deleted_at;
+
+-- name: DeleteEstacaoProducao :execrows
+UPDATE estacoes_producao
+SET    deleted_at = now(),
+       ativo      = false
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: UnsetEstacaoCategorias :exec
+UPDATE categorias
+SET    id_estacao = NULL
+WHERE  id_estacao = $1;
+
+-- name: UnsetEstacaoProdutos :exec
+UPDATE produtos
+SET    id_estacao = NULL
+WHERE  id_estacao = $1;
+
+-- name: SetCategoriaEstacao :execrows
+UPDATE categorias
+SET    id_estacao = $2
+WHERE  id = $1
+  AND  id_tenant = $3
+  AND  deleted_at IS NULL;
+
+-- name: SetProdutoEstacao :execrows
+UPDATE produtos p
+SET    id_estacao = $2
+FROM   categorias c
+WHERE  p.id = $1
+  AND  c.id = p.id_categoria
+  AND  c.id_tenant = $3
+  AND  p.deleted_at IS NULL;
+
+-- name: ListFilaPreparo :many
+/*
+Itens a preparar (e, opcionalmente, prontos aguardando despacho) de
+pedidos em aberto. Sem id_estacao devolve a fila de todas as estações. */
+SELECT pi.id,
+       pi.id_pedido,
+       p.codigo_pedido,
+       p.tipo_entrega,
+       p.data_pedido,
+       pi.id_estacao,
+       pi.id_produto,
+       pr.nome  AS produto_nome,
+       pr2.nome AS produto_2_nome,
+       co.nome  AS opcao_nome,
+       pi.quantidade,
+       pi.observacao,
+       pi.status_preparo,
+       pi.data_inicio_preparo,
+       pi.data_fim_preparo,
+       COALESCE((
+           SELECT jsonb_agg(jsonb_build_object('nome', cao.nome, 'quantidade', pia.quantidade) ORDER BY cao.nome)
+           FROM   pedido_item_adicionais pia
+           JOIN   categoria_adicional_opcoes cao ON cao.id = pia.id_adicional_opcao
+           WHERE  pia.id_pedido_item = pi.id
+             AND  pia.deleted_at IS NULL
+       ), '[]'::jsonb)::jsonb AS adicionais,
+       COALESCE((
+           SELECT jsonb_agg(jsonb_build_object('nome', prs.nome, 'partes', pis.partes) ORDER BY pis.ordem)
+           FROM   pedido_item_sabores pis
+           JOIN   produtos prs ON prs.id = pis.id_produto
+           WHERE  pis.id_pedido_item = pi.id
+       ), '[]'::jsonb)::jsonb AS sabores
+FROM   pedido_itens pi
+JOIN   pedidos p         ON p.id = pi.id_pedido
+JOIN   produtos pr       ON pr.id = pi.id_produto
+LEFT   JOIN produtos pr2 ON pr2.id = pi.id_produto_2
+LEFT   JOIN categoria_opcoes co ON co.id = pi.id_categoria_opcao
+WHERE  p.tenant_id = sqlc.arg(tenant_id)
+  AND  p.deleted_at IS NULL
+  AND  pi.deleted_at IS NULL
+  AND  p.id_status NOT IN (5, 6, 7)  -- concluído / cancelado / agendado
+  AND  (sqlc.narg(id_estacao)::uuid IS NULL OR pi.id_estacao = sqlc.narg(id_estacao)::uuid)
+  AND  (pi.status_preparo IN ('queued', 'preparing')
+        OR (sqlc.arg(incluir_prontos)::boolean AND pi.status_preparo = 'done'))
+ORDER  BY COALESCE(p.liberado_em, p.data_pedido), p.codigo_pedido, pi.seq_id;
+
+-- name: LockPedidoDoItemPreparo :one
+/* Trava o pedido antes do item: estações finalizando os últimos itens ao
+   mesmo tempo se serializam, e a contagem de pendentes de cada uma já vê
+   o que a outra gravou. */
+SELECT p.id
+FROM   pedidos p
+JOIN   pedido_itens pi ON pi.id_pedido = p.id
+WHERE  pi.id = $1
+  AND  p.tenant_id = $2
+  AND  pi.deleted_at IS NULL
+  AND  p.deleted_at IS NULL
+FOR UPDATE OF p;
+
+-- name: GetPedidoItemPreparoForUpdate :one
+SELECT pi.id, pi.id_pedido, pi.id_estacao, pi.status_preparo,
+       pi.data_inicio_preparo, pi.data_fim_preparo, pi.data_despacho
+FROM   pedido_itens pi
+JOIN   pedidos p ON p.id = pi.id_pedido
+WHERE  pi.id = $1
+  AND  p.tenant_id = $2
+  AND  pi.deleted_at IS NULL
+  AND  p.deleted_at IS NULL
+FOR UPDATE OF pi;
+
+-- name: UpdatePedidoItemStatusPreparo :one
+UPDATE pedido_itens
+SET    status_preparo      = $2,
+       data_inicio_preparo = $3,
+       data_fim_preparo    = $4,
+       data_despacho       = $5
+WHERE  id = $1
+RETURNING id, id_pedido, id_estacao, status_preparo,
+          data_inicio_preparo, data_fim_preparo, data_despacho;
+
+-- name: CountPedidoItensPendentesPreparo :one
+SELECT COUNT(*)
+FROM   pedido_itens
+WHERE  id_pedido = $1
+  AND  deleted_at IS NULL
+  AND  status_preparo IN ('queued', 'preparing');
+
+-- name: DesmarcarPedidoProntoAutomatico :one
+/* Item voltou para a fila ou para o preparo: o pedido deixa de estar
+   pronto. Sem linha = não estava pronto. */
+UPDATE pedidos
+SET    pedido_pronto      = 0,
+       data_pedido_pronto = NULL
+WHERE  id = $1
+  AND  pedido_pronto = 1
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, codigo_pedido, pedido_pronto, data_pedido_pronto;
+
+-- name: MarcarPedidoProntoAutomatico :one
+/* Só altera se ainda não estiver pronto; sem linha = já estava pronto. */
+UPDATE pedidos
+SET    pedido_pronto      = 1,
+       data_pedido_pronto = now()
+WHERE  id = $1
+  AND  pedido_pronto = 0
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, codigo_pedido, pedido_pronto, data_pedido_pronto;
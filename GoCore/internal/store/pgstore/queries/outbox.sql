@@ -40,9 +40,10 @@ FROM   to_send;
 
 -- name: MarkOutboxEventProcessed :exec
 UPDATE outbox_event
-SET    processed = true,
-       attempts  = attempts + 1,
-       last_error = NULL
+SET    processed    = true,
+       processed_at = now(),
+       attempts     = attempts + 1,
+       last_error   = NULL
 WHERE  id = $1;
 
 -- name: MarkOutboxEventError :exec
@@ -56,8 +57,57 @@ SET    attempts   = attempts + 1,
 WHERE  id = $1;
 
 -- name: DeleteProcessedOutboxEventsOlderThan :exec
-/* Limpeza: remova eventos já processados e antigos.
-   Ex.: call passando NOW() - interval '30 days'.        */
+/* Limpeza: remova eventos processados há mais tempo que a retenção.
+   Ex.: call passando NOW() - interval '30 days'.
+   Eventos processados antes de processed_at existir usam created_at. */
 DELETE FROM outbox_event
 WHERE processed = true
-  AND created_at < $1;
+  AND COALESCE(processed_at, created_at) < sqlc.arg(antes)::timestamptz;
+
+-- name: DeleteDeadLetterOutboxEventsOlderThan :exec
+/* Limpeza da dead-letter: processed_at guarda quando o evento foi
+   estacionado; nos antigos, a última tentativa agendada.          */
+DELETE FROM outbox_event
+WHERE dead_letter = true
+  AND COALESCE(processed_at, next_attempt_at) < sqlc.arg(antes)::timestamptz;
+
+-- name: ClaimOutboxEventsForDispatch :many
+/*
+Reserva o lote do dispatcher: as linhas ficam travadas (SKIP LOCKED)
+só durante este UPDATE, que adia next_attempt_at para o fim da
+reserva. A publicação acontece fora da transação; se o dispatcher
+cair no meio, o evento volta a ser elegível quando a reserva expira.
+A ordem do RETURNING não é garantida: o dispatcher reordena.     */
+WITH lote AS (
+    SELECT id
+    FROM   outbox_event
+    WHERE  processed = false
+      AND  dead_letter = false
+      AND  next_attempt_at <= now()
+    ORDER  BY created_at
+    LIMIT  $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox_event oe
+SET    next_attempt_at = $2
+FROM   lote
+WHERE  oe.id = lote.id
+RETURNING oe.id, oe.tenant_id, oe.user_id, oe.aggregate_type, oe.aggregate_id, oe.event_type, oe.payload,
+          oe.created_at, oe.attempts;
+
+-- name: ScheduleOutboxEventRetry :exec
+UPDATE outbox_event
+SET    attempts        = attempts + 1,
+       last_error      = $2,
+       next_attempt_at = $3
+WHERE  id = $1;
+
+-- name: MarkOutboxEventDeadLetter :exec
+/* Excedeu o número máximo de tentativas: estaciona o evento.
+   processed_at marca quando, para a limpeza da dead-letter.   */
+UPDATE outbox_event
+SET    attempts     = attempts + 1,
+       last_error   = $2,
+       dead_letter  = true,
+       processed_at = now()
+WHERE  id = $1;
@@ -0,0 +1,35 @@
+-- SQLC Queries para parcelamento (crediário) do pedido
+-- ****************************************************
+
+-- name: GetPedidoParcelamento :one
+/* restante segue enforce_parcela_nao_ultrapassa: total - pagamentos (sem encargos) - saldo das parcelas ativas. */
+SELECT p.id,
+       p.codigo_pedido,
+       p.id_status,
+       (p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                      - COALESCE(p.desconto, 0))::numeric(10,2) AS valor_pedido,
+       (SELECT COALESCE(SUM(pp.valor_pago - pp.troco - pp.valor_encargos), 0)
+          FROM pedido_pagamentos pp
+         WHERE pp.id_pedido = p.id
+           AND pp.deleted_at IS NULL)::numeric(10,2) AS valor_pago,
+       (SELECT COALESCE(SUM(cr.valor_devido - COALESCE(cr.valor_pago, 0)), 0)
+          FROM contas_receber cr
+         WHERE cr.id_pedido = p.id
+           AND cr.cancelado_em IS NULL)::numeric(10,2) AS saldo_parcelas,
+       (SELECT COALESCE(MAX(cr.parcela), 0) + 1
+          FROM contas_receber cr
+         WHERE cr.id_pedido = p.id)::smallint AS proxima_parcela
+FROM   pedidos p
+WHERE  p.id = $1
+  AND  p.tenant_id = $2
+  AND  p.deleted_at IS NULL;
+
+-- name: InsertParcelaContaReceber :one
+INSERT INTO contas_receber (id_pedido, parcela, vencimento, valor_devido, autorizado_por)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at;
+
+-- name: InsertPagamentoEntrada :one
+INSERT INTO pedido_pagamentos (id_pedido, forma_pagamento, valor_pago, troco, observacao)
+VALUES ($1, $2, $3, 0, $4)
+RETURNING id, created_at;
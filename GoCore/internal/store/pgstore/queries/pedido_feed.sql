@@ -0,0 +1,28 @@
+-- SQLC Queries para o feed de pedidos
+-- ***********************************
+
+-- name: ListPedidoFeedSince :many
+/* Eventos posteriores ao Last-Event-ID do cliente, em ordem. */
+SELECT id, tenant_id, pedido_id, event_type, payload, created_at
+FROM   pedido_feed
+WHERE  tenant_id = $1
+  AND  id > $2
+ORDER  BY id
+LIMIT  $3;
+
+-- name: DeletePedidoFeedOlderThan :exec
+DELETE FROM pedido_feed
+WHERE created_at < $1;
+
+-- name: ListPedidoFeedJanela :many
+/* Eventos com id abaixo do cursor gravados até 1 minuto antes dele. O id
+   é atribuído no INSERT, não no COMMIT: um evento de id menor pode ter
+   ficado visível depois do evento do cursor e não ter chegado ao cliente.
+   Os já recebidos são reenviados; o cliente deduplica pelo id. */
+SELECT f.id, f.tenant_id, f.pedido_id, f.event_type, f.payload, f.created_at
+FROM   pedido_feed f
+JOIN   pedido_feed c ON c.id = $2
+WHERE  f.tenant_id = $1
+  AND  f.id < c.id
+  AND  f.created_at >= c.created_at - interval '1 minute'
+ORDER  BY f.id;
@@ -0,0 +1,50 @@
+-- SQLC Queries para o status e o histórico de status dos pedidos
+-- **************************************************************
+
+-- name: GetPedidoStatusAtual :one
+SELECT id, codigo_pedido, id_status
+FROM   pedidos
+WHERE  id = sqlc.arg(id)
+  AND  tenant_id = sqlc.arg(tenant_id)
+  AND  deleted_at IS NULL;
+
+-- name: GetPedidoStatusForUpdate :one
+SELECT id, codigo_pedido, id_status
+FROM   pedidos
+WHERE  id = sqlc.arg(id)
+  AND  tenant_id = sqlc.arg(tenant_id)
+  AND  deleted_at IS NULL
+FOR UPDATE;
+
+-- name: UpdatePedidoStatus :one
+/* O código muda quando um pedido agendado é liberado (trigger). */
+UPDATE pedidos
+SET    id_status = sqlc.arg(id_status)
+WHERE  id = sqlc.arg(id)
+RETURNING codigo_pedido;
+
+-- name: CreatePedidoStatusHistorico :one
+INSERT INTO pedido_status_historico (
+    tenant_id, id_pedido, status_anterior, status_novo, user_id, motivo
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, tenant_id, id_pedido, status_anterior, status_novo, user_id, motivo, created_at;
+
+-- name: ListPedidoStatusHistorico :many
+SELECT h.id,
+       h.status_anterior,
+       sa.descricao AS status_anterior_descricao,
+       h.status_novo,
+       sn.descricao AS status_novo_descricao,
+       h.user_id,
+       u.user_name,
+       h.motivo,
+       h.created_at
+FROM   pedido_status_historico h
+JOIN   pedido_status sn      ON sn.id = h.status_novo
+LEFT   JOIN pedido_status sa ON sa.id = h.status_anterior
+LEFT   JOIN users u          ON u.id  = h.user_id
+WHERE  h.id_pedido = sqlc.arg(id_pedido)
+  AND  h.tenant_id = sqlc.arg(tenant_id)
+ORDER  BY h.created_at, h.id;
@@ -0,0 +1,52 @@
+-- SQLC Queries para cobranças PIX
+-- *******************************
+
+-- name: GetTenantPix :one
+SELECT pix_chave,
+       pix_nome_recebedor,
+       pix_cidade
+FROM   tenants
+WHERE  id = $1;
+
+-- name: UpdateTenantPix :execrows
+UPDATE tenants
+SET    pix_chave          = $2,
+       pix_nome_recebedor = $3,
+       pix_cidade         = $4
+WHERE  id = $1;
+
+-- name: GetPedidoCobrancaPix :one
+SELECT p.id,
+       p.codigo_pedido,
+       p.id_status,
+       (p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                      - COALESCE(p.desconto, 0))::numeric(10,2) AS valor_pedido,
+       p.valor_pago
+FROM   pedidos p
+WHERE  p.id = $1
+  AND  p.tenant_id = $2
+  AND  p.deleted_at IS NULL;
+
+-- name: GetPagamentoPixConfirmado :one
+/* Confirmação repetida: pela transferência (e2e_id) ou, sem ela, pelo txid e valor. */
+SELECT id,
+       pix_txid,
+       valor_pago,
+       observacao,
+       created_at
+FROM   pedido_pagamentos
+WHERE  id_pedido = sqlc.arg(id_pedido)
+  AND  forma_pagamento = 'PIX'
+  AND  deleted_at IS NULL
+  AND  CASE WHEN sqlc.narg(e2e_id)::text IS NULL
+            THEN pix_txid = sqlc.arg(txid) AND pix_e2e_id IS NULL AND valor_pago = sqlc.arg(valor_pago)
+            ELSE pix_e2e_id = sqlc.narg(e2e_id)
+       END
+ORDER  BY created_at
+LIMIT  1;
+
+-- name: InsertPagamentoPix :one
+INSERT INTO pedido_pagamentos (id_pedido, forma_pagamento, valor_pago, troco, autorizado_por, observacao,
+                               pix_txid, pix_e2e_id)
+VALUES ($1, 'PIX', $2, 0, $3, $4, $5, $6)
+RETURNING id, created_at;
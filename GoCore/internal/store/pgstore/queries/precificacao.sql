@@ -0,0 +1,77 @@
+-- SQLC Queries para a precificação de pedidos (cotação)
+-- ****************************************************
+
+-- name: ListPrecosProdutosCotacao :many
+/*
+Preços de todas as opções dos produtos informados, restritos ao tenant.
+Produtos inativos voltam na lista para que a cotação aponte o motivo. */
+SELECT p.id            AS id_produto,
+       p.nome          AS produto_nome,
+       p.status        AS produto_status,
+       p.id_categoria,
+       c.opcao_meia,
+       c.max_sabores,
+       pp.id_categoria_opcao,
+       pp.preco_base,
+       pp.preco_promocional,
+       pp.disponivel
+FROM   produtos p
+JOIN   categorias c      ON c.id = p.id_categoria
+JOIN   produto_precos pp ON pp.id_produto = p.id
+                        AND pp.deleted_at IS NULL
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  p.id = ANY(sqlc.arg(produto_ids)::uuid[])
+  AND  p.deleted_at IS NULL;
+
+-- name: ListAdicionalOpcoesCotacao :many
+SELECT cao.id,
+       cao.nome,
+       cao.valor,
+       cao.status,
+       ca.id     AS id_categoria_adicional,
+       ca.id_categoria,
+       ca.status AS adicional_status
+FROM   categoria_adicional_opcoes cao
+JOIN   categoria_adicionais ca ON ca.id = cao.id_categoria_adicional
+JOIN   categorias c            ON c.id = ca.id_categoria
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  cao.id = ANY(sqlc.arg(opcao_ids)::uuid[])
+  AND  cao.deleted_at IS NULL
+  AND  ca.deleted_at IS NULL;
+
+-- name: ListGruposAdicionaisCotacao :many
+/* Grupos de adicionais ativos das categorias do pedido, com as regras de seleção. */
+SELECT ca.id,
+       ca.id_categoria,
+       ca.nome,
+       ca.selecao,
+       ca.minimo,
+       ca.limite,
+       ca.is_main
+FROM   categoria_adicionais ca
+JOIN   categorias c ON c.id = ca.id_categoria
+WHERE  c.id_tenant = sqlc.arg(tenant_id)
+  AND  ca.id_categoria = ANY(sqlc.arg(categoria_ids)::uuid[])
+  AND  ca.status = 1
+  AND  ca.deleted_at IS NULL
+ORDER  BY ca.seq_id;
+
+-- name: ListComboSlotsCotacao :many
+/* Slots dos produtos do pedido que são combos. */
+SELECT cs.id,
+       cs.id_produto,
+       cs.nome,
+       cs.id_categoria,
+       cs.id_categoria_opcao,
+       cs.quantidade
+FROM   combo_slots cs
+WHERE  cs.id_produto = ANY(sqlc.arg(produto_ids)::uuid[])
+  AND  cs.deleted_at IS NULL
+ORDER  BY cs.ordem;
+
+-- name: ListComboAcrescimosCotacao :many
+SELECT id_combo_slot,
+       id_produto,
+       valor
+FROM   combo_slot_acrescimos
+WHERE  id_combo_slot = ANY(sqlc.arg(slot_ids)::uuid[]);
@@ -0,0 +1,134 @@
+-- SQLC Queries para o comprovante (relatório) do pedido
+-- *****************************************************
+
+-- name: GetRelatorioPedido :one
+SELECT p.id,
+       p.codigo_pedido,
+       p.data_pedido,
+       p.tipo_entrega,
+       p.observacao,
+       p.valor_total,
+       p.taxa_entrega,
+       p.nome_taxa_entrega,
+       p.desconto,
+       p.acrescimo,
+       p.troco_para,
+       p.valor_pago,
+       p.id_status,
+       ps.descricao          AS status_descricao,
+       t.name                AS tenant_nome,
+       t.endereco            AS tenant_endereco,
+       t.bairro              AS tenant_bairro,
+       t.cidade              AS tenant_cidade,
+       t.telefone            AS tenant_telefone,
+       t.timezone            AS tenant_timezone,
+       c.nome_razao_social   AS cliente_nome,
+       c.telefone            AS cliente_telefone,
+       c.celular             AS cliente_celular,
+       c.cep                 AS cliente_cep,
+       c.logradouro          AS cliente_logradouro,
+       c.numero              AS cliente_numero,
+       c.complemento         AS cliente_complemento,
+       c.bairro              AS cliente_bairro,
+       c.cidade              AS cliente_cidade,
+       c.uf                  AS cliente_uf
+FROM   pedidos p
+JOIN   tenants t        ON t.id  = p.tenant_id
+JOIN   clientes c       ON c.id  = p.id_cliente
+JOIN   pedido_status ps ON ps.id = p.id_status
+WHERE  p.id = sqlc.arg(id)
+  AND  p.tenant_id = sqlc.arg(tenant_id)
+  AND  p.deleted_at IS NULL;
+
+-- name: ListRelatorioItens :many
+SELECT pi.id,
+       pi.quantidade,
+       pi.valor_unitario,
+       pi.observacao,
+       cat.nome  AS categoria_nome,
+       pr.nome   AS produto_nome,
+       pr2.nome  AS produto2_nome,
+       co.nome   AS opcao_nome,
+       pi.id_item_combo IS NOT NULL AS componente
+FROM   pedido_itens pi
+JOIN   categorias cat          ON cat.id = pi.id_categoria
+JOIN   produtos pr             ON pr.id  = pi.id_produto
+LEFT   JOIN produtos pr2       ON pr2.id = pi.id_produto_2
+LEFT   JOIN categoria_opcoes co ON co.id = pi.id_categoria_opcao
+WHERE  pi.id_pedido = sqlc.arg(id_pedido)
+  AND  pi.deleted_at IS NULL
+ORDER  BY pi.seq_id;
+
+-- name: ListRelatorioSabores :many
+SELECT pis.id_pedido_item,
+       pr.nome,
+       pis.partes
+FROM   pedido_item_sabores pis
+JOIN   pedido_itens pi ON pi.id = pis.id_pedido_item
+JOIN   produtos pr     ON pr.id = pis.id_produto
+WHERE  pi.id_pedido = sqlc.arg(id_pedido)
+  AND  pi.deleted_at IS NULL
+ORDER  BY pis.id_pedido_item, pis.ordem;
+
+-- name: ListRelatorioAdicionais :many
+SELECT pia.id_pedido_item,
+       cao.nome,
+       pia.quantidade,
+       pia.valor
+FROM   pedido_item_adicionais pia
+JOIN   pedido_itens pi                ON pi.id  = pia.id_pedido_item
+JOIN   categoria_adicional_opcoes cao ON cao.id = pia.id_adicional_opcao
+WHERE  pi.id_pedido = sqlc.arg(id_pedido)
+  AND  pi.deleted_at IS NULL
+  AND  pia.deleted_at IS NULL
+ORDER  BY pia.seq_id;
+
+-- name: ListRelatorioPagamentos :many
+SELECT forma_pagamento,
+       categoria_pagamento,
+       valor_pago,
+       troco,
+       created_at
+FROM   pedido_pagamentos
+WHERE  id_pedido = sqlc.arg(id_pedido)
+  AND  deleted_at IS NULL
+ORDER  BY created_at, id;
+
+-- name: GetRelatorioCaixa :one
+SELECT c.id,
+       c.seq_id,
+       c.data_abertura,
+       c.data_fechamento,
+       c.valor_abertura,
+       c.observacao_fechamento,
+       c.status,
+       o.nome       AS operador_nome,
+       t.name       AS tenant_nome,
+       t.timezone   AS tenant_timezone
+FROM   caixas c
+JOIN   tenants t           ON t.id = c.tenant_id
+JOIN   operadores_caixa o  ON o.id = c.id_operador
+WHERE  c.id = sqlc.arg(id)
+  AND  c.tenant_id = sqlc.arg(tenant_id)
+  AND  c.deleted_at IS NULL;
+
+-- name: ListRelatorioCaixaFormas :many
+SELECT f.id_forma_pagamento,
+       f.nome_forma::text                                      AS nome_forma,
+       COALESCE(cff.valor_esperado, f.valor_esperado)::numeric AS valor_esperado,
+       cff.valor_informado
+FROM   calcular_valores_esperados_caixa(sqlc.arg(id_caixa)) f
+LEFT   JOIN caixa_fechamento_formas cff
+       ON  cff.id_caixa = sqlc.arg(id_caixa)
+       AND cff.id_forma_pagamento = f.id_forma_pagamento;
+
+-- name: ListRelatorioCaixaMovimentacoes :many
+SELECT tipo::text          AS tipo,
+       COUNT(*)::int       AS quantidade,
+       SUM(valor)::numeric AS total
+FROM   caixa_movimentacoes
+WHERE  id_caixa = sqlc.arg(id_caixa)
+  AND  tipo IN ('S', 'U', 'E')
+  AND  deleted_at IS NULL
+GROUP  BY tipo
+ORDER  BY tipo;
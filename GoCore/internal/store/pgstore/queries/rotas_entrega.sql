@@ -0,0 +1,280 @@
+-- SQLC Queries para rotas de entrega
+-- **********************************
+
+-- name: CreateRotaEntrega :one
+INSERT INTO rotas_entrega (tenant_id, id_entregador, observacao, criado_por)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
+-- name: GetRotaEntrega :one
+SELECT r.id, r.seq_id, r.tenant_id, r.id_entregador, e.nome AS entregador_nome, r.status, r.saida_em, r.retorno_em,
+       r.observacao, r.valor_taxas, r.valor_recebido, r.valor_entregue, r.id_caixa, r.acertado_em, r.acertado_por,
+       r.observacao_acerto, r.created_at, r.updated_at
+FROM   rotas_entrega r
+JOIN   entregadores e ON e.id = r.id_entregador
+WHERE  r.id = $1
+  AND  r.tenant_id = $2;
+
+-- name: ListRotasEntrega :many
+SELECT r.id, r.seq_id, r.tenant_id, r.id_entregador, e.nome AS entregador_nome, r.status, r.saida_em, r.retorno_em,
+       r.observacao, r.valor_taxas, r.valor_recebido, r.valor_entregue, r.id_caixa, r.acertado_em, r.acertado_por,
+       r.observacao_acerto, r.created_at, r.updated_at
+FROM   rotas_entrega r
+JOIN   entregadores e ON e.id = r.id_entregador
+WHERE  r.tenant_id = sqlc.arg(tenant_id)
+  AND  (sqlc.narg(status)::char IS NULL OR r.status = sqlc.narg(status)::char)
+  AND  (sqlc.narg(id_entregador)::uuid IS NULL OR r.id_entregador = sqlc.narg(id_entregador)::uuid)
+ORDER  BY r.created_at DESC
+LIMIT  sqlc.arg(lim)
+OFFSET sqlc.arg(off);
+
+-- name: ListRotasEntregaEntregador :many
+/* Rotas do app do entregador: em montagem ou em andamento. */
+SELECT r.id, r.seq_id, r.tenant_id, r.id_entregador, e.nome AS entregador_nome, r.status, r.saida_em, r.retorno_em,
+       r.observacao, r.valor_taxas, r.valor_recebido, r.valor_entregue, r.id_caixa, r.acertado_em, r.acertado_por,
+       r.observacao_acerto, r.created_at, r.updated_at
+FROM   rotas_entrega r
+JOIN   entregadores e ON e.id = r.id_entregador
+WHERE  r.id_entregador = $1
+  AND  r.status IN ('A', 'S')
+ORDER  BY r.created_at;
+
+-- name: GetRotaEntregaForUpdate :one
+SELECT id, seq_id, id_entregador, status, acertado_em
+FROM   rotas_entrega
+WHERE  id = $1
+  AND  tenant_id = $2
+FOR UPDATE;
+
+-- name: SairRotaEntrega :exec
+UPDATE rotas_entrega
+SET    status   = 'S',
+       saida_em = now()
+WHERE  id = $1;
+
+-- name: RetornarRotaEntrega :exec
+UPDATE rotas_entrega
+SET    status     = 'R',
+       retorno_em = now()
+WHERE  id = $1;
+
+-- name: CancelarRotaEntrega :exec
+UPDATE rotas_entrega
+SET    status = 'C'
+WHERE  id = $1;
+
+-- name: AcertarRotaEntrega :exec
+UPDATE rotas_entrega
+SET    valor_taxas           = $2,
+       valor_recebido        = $3,
+       valor_entregue        = $4,
+       id_caixa              = $5,
+       id_movimentacao_taxas = $6,
+       acertado_por          = $7,
+       observacao_acerto     = $8,
+       acertado_em           = now()
+WHERE  id = $1;
+
+-- name: ListRotaEntregaPedidos :many
+SELECT rp.id,
+       rp.id_rota,
+       rp.id_pedido,
+       rp.ordem,
+       rp.status,
+       rp.valor_taxa,
+       rp.valor_receber,
+       rp.valor_recebido,
+       rp.entregue_em,
+       rp.observacao,
+       rp.id_pagamento,
+       rp.valor_excedente,
+       p.codigo_pedido,
+       p.id_status,
+       (p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                      - COALESCE(p.desconto, 0))::numeric(10,2) AS valor_pedido,
+       p.valor_pago,
+       p.forma_pagamento,
+       p.troco_para,
+       p.lat,
+       p.lng,
+       p.prazo_max,
+       p.data_pedido,
+       p.agendado_para,
+       p.observacao                                   AS observacao_pedido,
+       c.nome_razao_social                            AS cliente_nome,
+       COALESCE(c.celular, c.telefone)::text          AS cliente_telefone,
+       c.logradouro,
+       c.numero,
+       c.complemento,
+       c.bairro,
+       c.cidade,
+       c.cep
+FROM   rota_entrega_pedidos rp
+JOIN   pedidos p  ON p.id = rp.id_pedido
+JOIN   clientes c ON c.id = p.id_cliente
+WHERE  rp.id_rota = ANY(sqlc.arg(rota_ids)::uuid[])
+ORDER  BY rp.id_rota, rp.ordem, rp.created_at;
+
+-- name: ListPedidosParaRota :many
+/* Trava os pedidos para que dois operadores não os coloquem em rotas diferentes. */
+SELECT p.id,
+       p.codigo_pedido,
+       p.id_status,
+       p.tipo_entrega,
+       EXISTS (SELECT 1
+                 FROM rota_entrega_pedidos x
+                WHERE x.id_pedido = p.id
+                  AND x.status <> 'N')::boolean AS em_rota
+FROM   pedidos p
+WHERE  p.tenant_id = sqlc.arg(tenant_id)
+  AND  p.id = ANY(sqlc.arg(pedido_ids)::uuid[])
+  AND  p.deleted_at IS NULL
+FOR UPDATE OF p;
+
+-- name: MaxOrdemRotaEntrega :one
+SELECT COALESCE(MAX(ordem), 0)::int
+FROM   rota_entrega_pedidos
+WHERE  id_rota = $1;
+
+-- name: InsertRotaEntregaPedidos :exec
+INSERT INTO rota_entrega_pedidos (id_rota, id_pedido, ordem, valor_taxa)
+SELECT sqlc.arg(id_rota), u.id, sqlc.arg(ordem_inicial)::int + u.n::int, sqlc.arg(valor_taxa)
+FROM   unnest(sqlc.arg(pedido_ids)::uuid[]) WITH ORDINALITY AS u(id, n);
+
+-- name: DeleteRotaEntregaPedido :execrows
+DELETE FROM rota_entrega_pedidos
+WHERE  id_rota = $1
+  AND  id_pedido = $2;
+
+-- name: DeleteRotaEntregaPedidos :exec
+DELETE FROM rota_entrega_pedidos
+WHERE  id_rota = $1;
+
+-- name: ListPedidosRotaForUpdate :many
+SELECT rp.id_pedido,
+       p.codigo_pedido,
+       p.id_status
+FROM   rota_entrega_pedidos rp
+JOIN   pedidos p ON p.id = rp.id_pedido
+WHERE  rp.id_rota = $1
+  AND  rp.status = 'P'
+ORDER  BY rp.ordem, rp.created_at
+FOR UPDATE OF p;
+
+-- name: CalcularValorReceberRota :exec
+/* Na saída: saldo a cobrar em dinheiro dos pedidos a pagar na entrega,
+   descontadas as parcelas em aberto (como enforce_pagamento_nao_ultrapassa). */
+UPDATE rota_entrega_pedidos rp
+SET    valor_receber = CASE
+           WHEN get_forma_pagamento_id(p.forma_pagamento) = 1 THEN
+               GREATEST(p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                        - COALESCE(p.desconto, 0) - p.valor_pago
+                        - COALESCE((SELECT SUM(cr.valor_devido - cr.valor_pago)
+                                      FROM contas_receber cr
+                                     WHERE cr.id_pedido = p.id
+                                       AND cr.cancelado_em IS NULL), 0), 0)
+           ELSE 0
+       END
+FROM   pedidos p
+WHERE  p.id = rp.id_pedido
+  AND  rp.id_rota = $1
+  AND  rp.status = 'P';
+
+-- name: GetRotaEntregaPedidoForUpdate :one
+SELECT rp.id, rp.status, rp.valor_receber, p.codigo_pedido, p.id_status
+FROM   rota_entrega_pedidos rp
+JOIN   pedidos p ON p.id = rp.id_pedido
+WHERE  rp.id_rota = $1
+  AND  rp.id_pedido = $2
+FOR UPDATE;
+
+-- name: MarcarEntregaRota :exec
+UPDATE rota_entrega_pedidos
+SET    status         = sqlc.arg(status),
+       valor_recebido = sqlc.arg(valor_recebido),
+       observacao     = sqlc.narg(observacao),
+       entregue_em    = CASE WHEN sqlc.arg(status) = 'E' THEN now() END
+WHERE  id = sqlc.arg(id);
+
+-- name: MarcarPendentesNaoEntregues :exec
+UPDATE rota_entrega_pedidos
+SET    status     = 'N',
+       observacao = COALESCE(observacao, 'Não entregue até o retorno')
+WHERE  id_rota = $1
+  AND  status = 'P';
+
+-- name: ListRecebimentosRota :many
+/* No acerto: dinheiro recebido em cada entrega e o saldo do pedido agora,
+   calculado como em enforce_pagamento_nao_ultrapassa. */
+SELECT rp.id,
+       rp.id_pedido,
+       rp.valor_recebido,
+       p.codigo_pedido,
+       p.id_status,
+       (p.valor_total + COALESCE(p.taxa_entrega, 0) + COALESCE(p.acrescimo, 0)
+                      - COALESCE(p.desconto, 0)
+                      - COALESCE((SELECT SUM(pp.valor_pago - pp.troco - pp.valor_encargos)
+                                    FROM pedido_pagamentos pp
+                                   WHERE pp.id_pedido = p.id
+                                     AND pp.deleted_at IS NULL), 0)
+                      - COALESCE((SELECT SUM(cr.valor_devido - cr.valor_pago)
+                                    FROM contas_receber cr
+                                   WHERE cr.id_pedido = p.id
+                                     AND cr.cancelado_em IS NULL), 0))::numeric(10,2) AS saldo
+FROM   rota_entrega_pedidos rp
+JOIN   pedidos p ON p.id = rp.id_pedido
+WHERE  rp.id_rota = $1
+  AND  rp.status = 'E'
+  AND  rp.valor_recebido > 0
+  AND  rp.id_pagamento IS NULL
+ORDER  BY rp.ordem
+FOR UPDATE OF p;
+
+-- name: InsertPagamentoEntrega :one
+INSERT INTO pedido_pagamentos (id_pedido, forma_pagamento, valor_pago, troco, autorizado_por, observacao)
+VALUES ($1, 'Dinheiro', $2, 0, $3, $4)
+RETURNING id;
+
+-- name: SetPagamentoRotaEntregaPedido :exec
+UPDATE rota_entrega_pedidos
+SET    id_pagamento    = $2,
+       valor_excedente = $3
+WHERE  id = $1;
+
+-- name: GetTotaisRotaEntrega :one
+SELECT COALESCE(SUM(valor_taxa) FILTER (WHERE status = 'E'), 0)::numeric(10,2) AS valor_taxas,
+       COALESCE(SUM(valor_recebido) FILTER (WHERE status = 'E'), 0)::numeric(10,2) AS valor_recebido
+FROM   rota_entrega_pedidos
+WHERE  id_rota = $1;
+
+-- name: ListPedidosParaPlanejamento :many
+/* Pedidos Delivery fora de rota para o planejador, com coordenadas e prazo. */
+SELECT p.id,
+       p.codigo_pedido,
+       p.id_status,
+       p.lat,
+       p.lng,
+       p.prazo_max,
+       p.data_pedido,
+       p.agendado_para,
+       c.nome_razao_social AS cliente_nome,
+       c.bairro
+FROM   pedidos p
+JOIN   clientes c ON c.id = p.id_cliente
+WHERE  p.tenant_id = sqlc.arg(tenant_id)
+  AND  p.tipo_entrega = 'Delivery'
+  AND  p.deleted_at IS NULL
+  AND  p.id_status = ANY(sqlc.arg(status_ids)::smallint[])
+  AND  (sqlc.narg(pedido_ids)::uuid[] IS NULL OR p.id = ANY(sqlc.narg(pedido_ids)::uuid[]))
+  AND  NOT EXISTS (SELECT 1
+                     FROM rota_entrega_pedidos x
+                    WHERE x.id_pedido = p.id
+                      AND x.status <> 'N')
+ORDER  BY p.data_pedido;
+
+-- name: UpdateOrdemRotaEntregaPedidos :execrows
+UPDATE rota_entrega_pedidos rp
+SET    ordem = u.n::int
+FROM   unnest(sqlc.arg(pedido_ids)::uuid[]) WITH ORDINALITY AS u(id, n)
+WHERE  rp.id_rota = sqlc.arg(id_rota)
+  AND  rp.id_pedido = u.id;
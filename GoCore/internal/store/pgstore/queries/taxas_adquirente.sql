@@ -0,0 +1,79 @@
+-- SQLC Queries para taxas da adquirente e calendário de recebíveis
+-- ****************************************************************
+
+-- name: ListTaxasAdquirente :many
+SELECT ta.id,
+       ta.id_forma_pagamento,
+       fp.codigo,
+       fp.nome,
+       ta.parcelas,
+       ta.taxa_percentual,
+       ta.taxa_fixa,
+       ta.dias_repasse,
+       ta.updated_at
+FROM   taxas_adquirente ta
+JOIN   formas_pagamento fp ON fp.id = ta.id_forma_pagamento
+WHERE  ta.tenant_id = $1
+ORDER  BY fp.ordem, ta.parcelas;
+
+-- name: GetFormaPagamento :one
+SELECT id, codigo, nome, tipo
+FROM   formas_pagamento
+WHERE  id = $1
+  AND  ativo = 1;
+
+-- name: UpsertTaxaAdquirente :one
+INSERT INTO taxas_adquirente (
+    tenant_id,
+    id_forma_pagamento,
+    parcelas,
+    taxa_percentual,
+    taxa_fixa,
+    dias_repasse
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (tenant_id, id_forma_pagamento, parcelas) DO UPDATE
+SET    taxa_percentual = EXCLUDED.taxa_percentual,
+       taxa_fixa       = EXCLUDED.taxa_fixa,
+       dias_repasse    = EXCLUDED.dias_repasse
+RETURNING id, tenant_id, id_forma_pagamento, parcelas, taxa_percentual, taxa_fixa, dias_repasse, created_at, updated_at;
+
+-- name: DeleteTaxaAdquirente :execrows
+DELETE FROM taxas_adquirente
+WHERE  id = $1
+  AND  tenant_id = $2;
+
+-- name: ListCalendarioRecebiveis :many
+/* Depósitos previstos por dia e forma. Dinheiro (tipo D) fica no caixa;
+   pagamento sem repasse calculado (anterior às taxas) conta o valor cheio
+   no dia do pagamento. O prazo máximo (365 dias) limita a busca. */
+WITH recebiveis AS (
+    SELECT COALESCE(pp.data_repasse,
+                    (pp.created_at AT TIME ZONE COALESCE(t.timezone, 'America/Sao_Paulo'))::date) AS data_repasse,
+           pp.forma_pagamento,
+           pp.parcelas_cartao,
+           pp.valor_pago - COALESCE(pp.troco, 0)                              AS valor_bruto,
+           COALESCE(pp.taxa_adquirente, 0)                                    AS taxa,
+           COALESCE(pp.valor_repasse, pp.valor_pago - COALESCE(pp.troco, 0))  AS valor_liquido
+    FROM   pedido_pagamentos pp
+    JOIN   pedidos p ON p.id = pp.id_pedido
+    JOIN   tenants t ON t.id = p.tenant_id
+    LEFT   JOIN formas_pagamento fp ON fp.id = get_forma_pagamento_id(pp.forma_pagamento)
+    WHERE  p.tenant_id = sqlc.arg(tenant_id)
+      AND  p.deleted_at IS NULL
+      AND  pp.deleted_at IS NULL
+      AND  fp.tipo IS DISTINCT FROM 'D'
+      AND  pp.created_at >= sqlc.arg(inicio)::date - 367
+      AND  pp.created_at <  sqlc.arg(fim)::date + 2
+)
+SELECT r.data_repasse::date                  AS data_repasse,
+       r.forma_pagamento,
+       count(*)::integer                     AS pagamentos,
+       SUM(r.valor_bruto)::numeric(12,2)     AS valor_bruto,
+       SUM(r.taxa)::numeric(12,2)            AS taxa,
+       SUM(r.valor_liquido)::numeric(12,2)   AS valor_liquido
+FROM   recebiveis r
+WHERE  r.data_repasse BETWEEN sqlc.arg(inicio)::date AND sqlc.arg(fim)::date
+GROUP  BY r.data_repasse, r.forma_pagamento
+ORDER  BY r.data_repasse, r.forma_pagamento;
@@ -0,0 +1,119 @@
+-- SQLC Queries para Webhooks
+-- **************************
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    tenant_id,
+    url,
+    secret,
+    event_types,
+    descricao,
+    ativo
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, tenant_id, url, secret, event_types, descricao, ativo,
+          created_at, updated_at, deleted_at;
+
+-- name: GetWebhookSubscription :one
+SELECT id, tenant_id, url, secret, event_types, descricao, ativo,
+       created_at, updated_at, deleted_at
+FROM   webhook_subscriptions
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: ListWebhookSubscriptions :many
+SELECT id, tenant_id, url, secret, event_types, descricao, ativo,
+       created_at, updated_at, deleted_at
+FROM   webhook_subscriptions
+WHERE  tenant_id = $1
+  AND  deleted_at IS NULL
+ORDER  BY created_at;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET    url         = $3,
+       event_types = $4,
+       descricao   = $5,
+       ativo       = $6,
+       updated_at  = now()
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, url, secret, event_types, descricao, ativo,
+          created_at, updated_at, deleted_at;
+
+-- name: RotateWebhookSubscriptionSecret :one
+UPDATE webhook_subscriptions
+SET    secret     = $3,
+       updated_at = now()
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, url, secret, event_types, descricao, ativo,
+          created_at, updated_at, deleted_at;
+
+-- name: DeleteWebhookSubscription :execrows
+UPDATE webhook_subscriptions
+SET    deleted_at = now(),
+       ativo      = false
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+/*
+Assinaturas ativas do tenant interessadas no evento e que ainda
+não o receberam com sucesso (evita reenvio quando o outbox
+reagenda o evento por falha em outra assinatura).          */
+SELECT s.id, s.tenant_id, s.url, s.secret, s.event_types, s.descricao, s.ativo,
+       s.created_at, s.updated_at, s.deleted_at
+FROM   webhook_subscriptions s
+WHERE  s.tenant_id = sqlc.arg(tenant_id)
+  AND  s.ativo = true
+  AND  s.deleted_at IS NULL
+  AND  (cardinality(s.event_types) = 0 OR sqlc.arg(event_type)::text = ANY (s.event_types))
+  AND  NOT EXISTS (
+         SELECT 1
+         FROM   webhook_deliveries d
+         WHERE  d.subscription_id = s.id
+           AND  d.event_id = sqlc.arg(event_id)::bigint
+           AND  d.success = true
+       );
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+    tenant_id,
+    subscription_id,
+    event_id,
+    event_type,
+    payload,
+    url,
+    status_code,
+    response_body,
+    error,
+    success,
+    duration_ms,
+    manual
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING id, tenant_id, subscription_id, event_id, event_type, payload,
+          url, status_code, response_body, error, success, duration_ms, manual, created_at;
+
+-- name: GetWebhookDelivery :one
+SELECT id, tenant_id, subscription_id, event_id, event_type, payload,
+       url, status_code, response_body, error, success, duration_ms, manual, created_at
+FROM   webhook_deliveries
+WHERE  id = $1
+  AND  tenant_id = $2;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, tenant_id, subscription_id, event_id, event_type, payload,
+       url, status_code, response_body, error, success, duration_ms, manual, created_at
+FROM   webhook_deliveries
+WHERE  subscription_id = $1
+  AND  tenant_id = $2
+ORDER  BY id DESC
+LIMIT  $3 OFFSET $4;
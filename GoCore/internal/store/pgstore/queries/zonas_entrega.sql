@@ -0,0 +1,99 @@
+-- SQLC Queries para zonas de entrega
+-- **********************************
+
+-- name: CreateZonaEntrega :one
+INSERT INTO zonas_entrega (
+    tenant_id,
+    nome,
+    tipo,
+    bairros,
+    cep_inicio,
+    cep_fim,
+    raio_km,
+    poligono,
+    taxa,
+    valor_minimo_pedido,
+    prazo_min,
+    prazo_max,
+    ordem,
+    ativo
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING id, tenant_id, nome, tipo, bairros, cep_inicio, cep_fim, raio_km, poligono, taxa, valor_minimo_pedido,
+          prazo_min, prazo_max, ordem, ativo, created_at, updated_at, deleted_at;
+
+-- name: GetZonaEntrega :one
+SELECT id, tenant_id, nome, tipo, bairros, cep_inicio, cep_fim, raio_km, poligono, taxa, valor_minimo_pedido,
+       prazo_min, prazo_max, ordem, ativo, created_at, updated_at, deleted_at
+FROM   zonas_entrega
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: ListZonasEntrega :many
+SELECT id, tenant_id, nome, tipo, bairros, cep_inicio, cep_fim, raio_km, poligono, taxa, valor_minimo_pedido,
+       prazo_min, prazo_max, ordem, ativo, created_at, updated_at, deleted_at
+FROM   zonas_entrega
+WHERE  tenant_id = $1
+  AND  deleted_at IS NULL
+ORDER  BY ordem, nome;
+
+-- name: ListZonasEntregaAtivas :many
+/* Na ordem de prioridade: o endereço fica com a primeira zona que o contém. */
+SELECT id, tenant_id, nome, tipo, bairros, cep_inicio, cep_fim, raio_km, poligono, taxa, valor_minimo_pedido,
+       prazo_min, prazo_max, ordem, ativo, created_at, updated_at, deleted_at
+FROM   zonas_entrega
+WHERE  tenant_id = $1
+  AND  ativo
+  AND  deleted_at IS NULL
+ORDER  BY ordem, created_at;
+
+-- name: UpdateZonaEntrega :one
+UPDATE zonas_entrega
+SET    nome                = $3,
+       tipo                = $4,
+       bairros             = $5,
+       cep_inicio          = $6,
+       cep_fim             = $7,
+       raio_km             = $8,
+       poligono            = $9,
+       taxa                = $10,
+       valor_minimo_pedido = $11,
+       prazo_min           = $12,
+       prazo_max           = $13,
+       ordem               = $14,
+       ativo               = $15
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL
+RETURNING id, tenant_id, nome, tipo, bairros, cep_inicio, cep_fim, raio_km, poligono, taxa, valor_minimo_pedido,
+          prazo_min, prazo_max, ordem, ativo, created_at, updated_at, deleted_at;
+
+-- name: DeleteZonaEntrega :execrows
+/* Soft delete: pedidos.id_zona_entrega continua apontando para a zona. */
+UPDATE zonas_entrega
+SET    deleted_at = now()
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
+
+-- name: GetTenantOrigem :one
+SELECT lat,
+       lng
+FROM   tenants
+WHERE  id = $1;
+
+-- name: UpdateTenantOrigem :execrows
+UPDATE tenants
+SET    lat = $2,
+       lng = $3
+WHERE  id = $1;
+
+-- name: GetClienteEnderecoEntrega :one
+SELECT cep,
+       bairro
+FROM   clientes
+WHERE  id = $1
+  AND  tenant_id = $2
+  AND  deleted_at IS NULL;
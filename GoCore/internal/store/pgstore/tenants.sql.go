@@ -244,7 +244,7 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (Product, er
 }
 
 const getTenant = `-- name: GetTenant :one
-SELECT id, name, plan, status, created_at, id_cliente_padrao, photo, telefone, endereco, bairro, cidade, seq_id, taxa_entrega_padrao
+SELECT id, name, plan, status, created_at, id_cliente_padrao, photo, telefone, endereco, bairro, cidade, seq_id, taxa_entrega_padrao, timezone, lat, lng, pix_chave, pix_nome_recebedor, pix_cidade
 FROM tenants
 WHERE id = $1
 `
@@ -266,6 +266,12 @@ func (q *Queries) GetTenant(ctx context.Context, id uuid.UUID) (Tenant, error) {
 		&i.Cidade,
 		&i.SeqID,
 		&i.TaxaEntregaPadrao,
+		&i.Timezone,
+		&i.Lat,
+		&i.Lng,
+		&i.PixChave,
+		&i.PixNomeRecebedor,
+		&i.PixCidade,
 	)
 	return i, err
 }
@@ -385,7 +391,7 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 }
 
 const listTenants = `-- name: ListTenants :many
-SELECT id, name, plan, status, created_at, id_cliente_padrao, photo, telefone, endereco, bairro, cidade, seq_id, taxa_entrega_padrao
+SELECT id, name, plan, status, created_at, id_cliente_padrao, photo, telefone, endereco, bairro, cidade, seq_id, taxa_entrega_padrao, timezone, lat, lng, pix_chave, pix_nome_recebedor, pix_cidade
 FROM tenants
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -419,6 +425,12 @@ func (q *Queries) ListTenants(ctx context.Context, arg ListTenantsParams) ([]Ten
 			&i.Cidade,
 			&i.SeqID,
 			&i.TaxaEntregaPadrao,
+			&i.Timezone,
+			&i.Lat,
+			&i.Lng,
+			&i.PixChave,
+			&i.PixNomeRecebedor,
+			&i.PixCidade,
 		); err != nil {
 			return nil, err
 		}
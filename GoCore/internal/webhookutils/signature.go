@@ -0,0 +1,97 @@
+package webhookutils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos enviados em cada entrega de webhook.
+const (
+	HeaderID        = "X-Webhook-Id"        // id do evento; igual em todas as tentativas
+	HeaderEvent     = "X-Webhook-Event"     // event_type (ex.: pedido.created)
+	HeaderTimestamp = "X-Webhook-Timestamp" // unix em segundos
+	HeaderSignature = "X-Webhook-Signature" // v1=<hex(HMAC-SHA256)>
+)
+
+const signatureVersion = "v1"
+
+// Janela padrão aceita por Verify entre o envio e o recebimento.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrSignatureMissing  = errors.New("assinatura ausente")
+	ErrSignatureMismatch = errors.New("assinatura inválida")
+	ErrTimestampInvalid  = errors.New("timestamp inválido")
+	ErrTimestampExpired  = errors.New("timestamp fora da janela de tolerância")
+)
+
+// NewSecret gera um segredo aleatório de 256 bits para uma assinatura.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign devolve o valor do cabeçalho X-Webhook-Signature.
+// A mensagem assinada é "<id>.<timestamp>.<body>", de modo que nem o corpo
+// nem o timestamp podem ser trocados sem invalidar a assinatura.
+func Sign(secret, id string, timestamp int64, body []byte) string {
+	return signatureVersion + "=" + hex.EncodeToString(mac(secret, id, timestamp, body))
+}
+
+// Verify valida assinatura e timestamp de uma entrega recebida.
+// Para proteção completa contra replay o receptor também deve descartar
+// ids (X-Webhook-Id) já processados dentro da janela de tolerância.
+func Verify(secret, id, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	if signature == "" {
+		return ErrSignatureMissing
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrTimestampInvalid
+	}
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > tolerance {
+		return ErrTimestampExpired
+	}
+
+	expected := mac(secret, id, ts, body)
+
+	// aceita várias assinaturas separadas por vírgula (rotação de segredo)
+	for _, part := range strings.Split(signature, ",") {
+		version, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		got, err := hex.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+func mac(secret, id string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(id))
+	h.Write([]byte("."))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package webhookutils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVetorConhecido(t *testing.T) {
+	// HMAC-SHA256("segredo", "42.1700000000.{}")
+	const want = "v1=0e542e6c6879619de94a6927a1632199fdfa5852dae9db956ef3da87c562736e"
+	if got := Sign("segredo", "42", 1700000000, []byte("{}")); got != want {
+		t.Fatalf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const (
+		secret = "whsec_teste"
+		id     = "123"
+	)
+	body := []byte(`{"event_type":"pedido.created"}`)
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign(secret, id, now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		id        string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   error
+	}{
+		{"válida", secret, id, ts, sig, body, now, nil},
+		{"dentro da tolerância", secret, id, ts, sig, body, now.Add(4 * time.Minute), nil},
+		{"rotação: segunda assinatura válida", secret, id, ts, "v1=00ff, " + sig, body, now, nil},
+		{"sem assinatura", secret, id, ts, "", body, now, ErrSignatureMissing},
+		{"timestamp inválido", secret, id, "abc", sig, body, now, ErrTimestampInvalid},
+		{"timestamp expirado", secret, id, ts, sig, body, now.Add(6 * time.Minute), ErrTimestampExpired},
+		{"timestamp no futuro", secret, id, ts, sig, body, now.Add(-6 * time.Minute), ErrTimestampExpired},
+		{"segredo errado", "outro", id, ts, sig, body, now, ErrSignatureMismatch},
+		{"id trocado", secret, "124", ts, sig, body, now, ErrSignatureMismatch},
+		{"corpo alterado", secret, id, ts, sig, []byte(`{}`), now, ErrSignatureMismatch},
+		{"versão desconhecida", secret, id, ts, "v2=" + strings.TrimPrefix(sig, "v1="), body, now, ErrSignatureMismatch},
+		{"hex inválido", secret, id, ts, "v1=zz", body, now, ErrSignatureMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.id, tt.timestamp, tt.signature, tt.body, DefaultTolerance, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Fatalf("NewSecret() = %q, formato inesperado", a)
+	}
+	if a == b {
+		t.Fatal("NewSecret() repetiu o segredo")
+	}
+}